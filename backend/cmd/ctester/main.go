@@ -14,10 +14,12 @@ import (
 	"pentagi/pkg/providers/bedrock"
 	"pentagi/pkg/providers/custom"
 	"pentagi/pkg/providers/gemini"
+	"pentagi/pkg/providers/mock"
 	"pentagi/pkg/providers/ollama"
 	"pentagi/pkg/providers/openai"
 	"pentagi/pkg/providers/pconfig"
 	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/providers/replay"
 	"pentagi/pkg/providers/tester"
 	"pentagi/pkg/providers/tester/testdata"
 
@@ -26,7 +28,7 @@ import (
 
 func main() {
 	envFile := flag.String("env", ".env", "Path to environment file")
-	providerType := flag.String("type", "custom", "Provider type [custom, openai, anthropic, gemini, bedrock, ollama]")
+	providerType := flag.String("type", "custom", "Provider type [custom, openai, anthropic, gemini, bedrock, ollama, replay, mock]")
 	configPath := flag.String("config", "", "Path to provider config file")
 	testsPath := flag.String("tests", "", "Path to custom tests YAML file")
 	reportPath := flag.String("report", "", "Path to write report file")
@@ -34,6 +36,8 @@ func main() {
 	testGroups := flag.String("groups", "all", "Comma-separated test groups to run")
 	workers := flag.Int("workers", 4, "Number of workers to use")
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
+	cassettesPath := flag.String("cassettes", "", "Path to replay cassettes directory (overrides REPLAY_CASSETTES_DIR)")
+	record := flag.Bool("record", false, "Record provider calls into replay cassettes directory")
 	flag.Parse()
 
 	if err := godotenv.Load(*envFile); err != nil {
@@ -50,11 +54,27 @@ func main() {
 		cfg.OllamaServerConfig = *configPath
 	}
 
+	if *cassettesPath != "" {
+		cfg.ReplayCassettesDir = *cassettesPath
+	}
+	if *record {
+		cfg.ReplayRecordEnabled = true
+	}
+
 	prv, err := createProvider(*providerType, cfg)
 	if err != nil {
 		log.Fatalf("Error creating provider: %v", err)
 	}
 
+	// replay provider records its fallback calls by itself
+	if cfg.ReplayRecordEnabled && prv.Type() != provider.ProviderReplay {
+		store, err := replay.NewStore(cfg.ReplayCassettesDir)
+		if err != nil {
+			log.Fatalf("Error creating replay cassettes store: %v", err)
+		}
+		prv = replay.NewRecorder(prv, store)
+	}
+
 	fmt.Printf("Testing %s Provider\n", *providerType)
 	fmt.Println("=================================================")
 
@@ -169,6 +189,40 @@ func createProvider(providerType string, cfg *config.Config) (provider.Provider,
 		}
 		return ollama.New(cfg, providerConfig)
 
+	case "replay":
+		store, err := replay.NewStore(cfg.ReplayCassettesDir)
+		if err != nil {
+			return nil, fmt.Errorf("error creating replay cassettes store: %w", err)
+		}
+		var fallback provider.Provider
+		if fallbackType := cfg.ReplayFallbackProvider; fallbackType != "" && fallbackType != "replay" {
+			if fallback, err = createProvider(fallbackType, cfg); err != nil {
+				return nil, fmt.Errorf("error creating replay fallback provider: %w", err)
+			}
+			if cfg.ReplayRecordEnabled {
+				fallback = replay.NewRecorder(fallback, store)
+			}
+		}
+		providerConfig, err := replay.DefaultProviderConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error creating replay provider config: %w", err)
+		}
+		return replay.New(store, replay.MissPolicy(cfg.ReplayMissPolicy), fallback, providerConfig)
+
+	case "mock":
+		if cfg.MockScriptPath == "" {
+			return nil, fmt.Errorf("Mock script path is not set")
+		}
+		script, err := mock.LoadScript(cfg.MockScriptPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading mock script: %w", err)
+		}
+		providerConfig, err := mock.DefaultProviderConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error creating mock provider config: %w", err)
+		}
+		return mock.New(script, providerConfig)
+
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
 	}
@@ -28,11 +28,13 @@ import (
 
 func main() {
 	envFile := flag.String("env", ".env", "Path to environment file")
-	providerName := flag.String("provider", "custom", "Provider name (openai, anthropic, gemini, bedrock, ollama, custom)")
+	providerName := flag.String("provider", "custom", "Provider name (openai, anthropic, gemini, bedrock, ollama, custom, replay, mock)")
 	flowID := flag.Int64("flow", 0, "Flow ID for testing functions that require it (0 means using mocks)")
 	userID := flag.Int64("user", 0, "User ID for testing functions that require it (1 is default admin user)")
 	taskID := flag.Int64("task", 0, "Task ID for testing functions with default unset")
 	subtaskID := flag.Int64("subtask", 0, "Subtask ID for testing functions with default unset")
+	cassettesPath := flag.String("cassettes", "", "Path to replay cassettes directory (overrides REPLAY_CASSETTES_DIR)")
+	record := flag.Bool("record", false, "Record all provider calls into replay cassettes directory")
 	flag.Parse()
 
 	if *taskID == 0 {
@@ -52,6 +54,13 @@ func main() {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
+	if *cassettesPath != "" {
+		cfg.ReplayCassettesDir = *cassettesPath
+	}
+	if *record {
+		cfg.ReplayRecordEnabled = true
+	}
+
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -91,6 +100,10 @@ func main() {
 	terminal.PrintInfo("Starting ftester with the following parameters:")
 	terminal.PrintKeyValue("Environment file", *envFile)
 	terminal.PrintKeyValue("Provider", *providerName)
+	if cfg.ReplayCassettesDir != "" {
+		terminal.PrintKeyValue("Replay cassettes", cfg.ReplayCassettesDir)
+		terminal.PrintKeyValueFormat("Replay recording", "%t", cfg.ReplayRecordEnabled)
+	}
 	if *flowID != 0 {
 		terminal.PrintKeyValue("Flow ID", fmt.Sprintf("%d", *flowID))
 	} else {
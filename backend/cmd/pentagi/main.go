@@ -6,6 +6,7 @@ import (
 	"errors"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"pentagi/migrations"
+	"pentagi/pkg/audit"
 	"pentagi/pkg/config"
 	"pentagi/pkg/controller"
 	"pentagi/pkg/database"
@@ -20,7 +22,9 @@ import (
 	"pentagi/pkg/graph/subscriptions"
 	obs "pentagi/pkg/observability"
 	"pentagi/pkg/providers"
+	"pentagi/pkg/scheduler"
 	router "pentagi/pkg/server"
+	"pentagi/pkg/webhooks"
 
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
@@ -94,6 +98,12 @@ func main() {
 
 	log.Println("Migrations ran successfully")
 
+	// the state metrics query the tables which are created by the migrations
+	stateDumper := database.NewStateDumper(queries)
+	if err := obs.Observer.StartStateMetricCollect(stateDumper, attribute.String("component", "server")); err != nil {
+		logrus.WithError(err).Error("failed to start state metrics collection")
+	}
+
 	client, err := docker.NewDockerClient(ctx, queries, cfg)
 	if err != nil {
 		log.Fatalf("failed to initialize Docker client: %v", err)
@@ -103,14 +113,33 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to initialize providers: %v", err)
 	}
-	subscriptions := subscriptions.NewSubscriptionsController()
-	controller := controller.NewFlowController(queries, cfg, client, providers, subscriptions)
+	webhooks, err := webhooks.NewDispatcher(cfg, queries)
+	if err != nil {
+		log.Fatalf("failed to initialize webhooks: %v", err)
+	}
+	webhooks.Start()
+
+	auditLogger, err := audit.NewLogger(cfg, queries)
+	if err != nil {
+		log.Fatalf("failed to initialize audit log: %v", err)
+	}
+
+	subscriptions := subscriptions.NewSubscriptionsController(queries.GetTeamUserIDs)
+	controller := controller.NewFlowController(queries, cfg, client, providers, subscriptions, webhooks)
 
 	if err := controller.LoadFlows(ctx); err != nil {
 		log.Fatalf("failed to load flows: %v", err)
 	}
 
-	r := router.NewRouter(queries, orm, cfg, providers, controller, subscriptions)
+	scheduler := scheduler.NewScheduler(queries, controller, providers)
+	scheduler.Start()
+
+	var metricsHandler http.Handler
+	if otelclient != nil {
+		metricsHandler = otelclient.MetricsHandler()
+	}
+
+	r := router.NewRouter(queries, orm, cfg, providers, controller, subscriptions, webhooks, auditLogger, metricsHandler)
 
 	// Run the server in a separate goroutine
 	go func() {
@@ -129,5 +158,11 @@ func main() {
 	<-sigChan
 	log.Println("Shutting down...")
 
+	scheduler.Stop()
+	webhooks.Stop()
+	if err := auditLogger.Close(); err != nil {
+		log.Printf("failed to close audit log: %v", err)
+	}
+
 	log.Println("Shutdown complete")
 }
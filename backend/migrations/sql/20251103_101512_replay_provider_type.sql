@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Add replay to the provider_type enum
+CREATE TYPE PROVIDER_TYPE_NEW AS ENUM (
+  'openai',
+  'anthropic',
+  'gemini',
+  'bedrock',
+  'ollama',
+  'custom',
+  'replay'
+);
+
+-- Update the tables to use the new enum type
+ALTER TABLE providers
+    ALTER COLUMN type TYPE PROVIDER_TYPE_NEW USING type::text::PROVIDER_TYPE_NEW;
+ALTER TABLE flows
+    ALTER COLUMN model_provider_type TYPE PROVIDER_TYPE_NEW USING model_provider_type::text::PROVIDER_TYPE_NEW;
+ALTER TABLE assistants
+    ALTER COLUMN model_provider_type TYPE PROVIDER_TYPE_NEW USING model_provider_type::text::PROVIDER_TYPE_NEW;
+
+-- Drop the old type and rename the new one
+DROP TYPE PROVIDER_TYPE;
+ALTER TYPE PROVIDER_TYPE_NEW RENAME TO PROVIDER_TYPE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Revert the changes by removing replay from the enum
+DELETE FROM providers WHERE type = 'replay';
+UPDATE flows SET model_provider_type = 'custom' WHERE model_provider_type = 'replay';
+UPDATE assistants SET model_provider_type = 'custom' WHERE model_provider_type = 'replay';
+
+CREATE TYPE PROVIDER_TYPE_NEW AS ENUM (
+  'openai',
+  'anthropic',
+  'gemini',
+  'bedrock',
+  'ollama',
+  'custom'
+);
+
+-- Update the tables to use the new enum type
+ALTER TABLE providers
+    ALTER COLUMN type TYPE PROVIDER_TYPE_NEW USING type::text::PROVIDER_TYPE_NEW;
+ALTER TABLE flows
+    ALTER COLUMN model_provider_type TYPE PROVIDER_TYPE_NEW USING model_provider_type::text::PROVIDER_TYPE_NEW;
+ALTER TABLE assistants
+    ALTER COLUMN model_provider_type TYPE PROVIDER_TYPE_NEW USING model_provider_type::text::PROVIDER_TYPE_NEW;
+
+-- Drop the old type and rename the new one
+DROP TYPE PROVIDER_TYPE;
+ALTER TYPE PROVIDER_TYPE_NEW RENAME TO PROVIDER_TYPE;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE msgchains ADD COLUMN usage_cache_read BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE msgchains ADD COLUMN usage_cache_write BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE msgchains DROP COLUMN usage_cache_write;
+ALTER TABLE msgchains DROP COLUMN usage_cache_read;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'playbooks.admin'),
+  (1, 'playbooks.view'),
+  (1, 'playbooks.edit'),
+  (2, 'playbooks.view'),
+  (2, 'playbooks.edit');
+
+CREATE TABLE playbooks (
+  id               BIGINT        PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id          BIGINT        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name             TEXT          NOT NULL,
+  description      TEXT          NOT NULL DEFAULT '',
+  content          TEXT          NOT NULL,
+  created_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  updated_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  deleted_at       TIMESTAMPTZ   NULL
+);
+
+CREATE INDEX playbooks_user_id_idx ON playbooks(user_id);
+CREATE INDEX playbooks_name_user_id_idx ON playbooks(name, user_id);
+CREATE UNIQUE INDEX playbooks_name_user_id_unique ON playbooks(name, user_id) WHERE deleted_at IS NULL;
+
+CREATE OR REPLACE TRIGGER update_playbooks_modified
+  BEFORE UPDATE ON playbooks
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Rendered playbook is stored per flow to keep it consistent after the playbook was changed
+CREATE TABLE flow_playbooks (
+  flow_id          BIGINT        PRIMARY KEY REFERENCES flows(id) ON DELETE CASCADE,
+  playbook_id      BIGINT        NULL REFERENCES playbooks(id) ON DELETE SET NULL,
+  plan             JSON          NOT NULL,
+  next_task        INTEGER       NOT NULL DEFAULT 0,
+  created_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  updated_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX flow_playbooks_playbook_id_idx ON flow_playbooks(playbook_id);
+
+CREATE OR REPLACE TRIGGER update_flow_playbooks_modified
+  BEFORE UPDATE ON flow_playbooks
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE flow_playbooks;
+DROP TABLE playbooks;
+
+DELETE FROM privileges WHERE name IN (
+  'playbooks.admin',
+  'playbooks.view',
+  'playbooks.edit'
+);
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'schedules.admin'),
+  (1, 'schedules.create'),
+  (1, 'schedules.delete'),
+  (1, 'schedules.edit'),
+  (1, 'schedules.view'),
+  (2, 'schedules.create'),
+  (2, 'schedules.delete'),
+  (2, 'schedules.edit'),
+  (2, 'schedules.view');
+
+CREATE TYPE SCHEDULE_MISSED_POLICY AS ENUM ('skip','run_once');
+CREATE TYPE SCHEDULE_RUN_STATUS AS ENUM ('started','skipped','failed');
+
+CREATE TABLE schedules (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT                   NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name                 TEXT                     NOT NULL,
+  cron                 TEXT                     NOT NULL,
+  timezone             TEXT                     NOT NULL DEFAULT 'UTC',
+  input                TEXT                     NOT NULL DEFAULT '',
+  playbook_id          BIGINT                   NULL REFERENCES playbooks(id) ON DELETE SET NULL,
+  variables            JSON                     NOT NULL DEFAULT '{}',
+  scope                TEXT                     NOT NULL DEFAULT '',
+  model_provider_name  TEXT                     NOT NULL,
+  model_provider_type  PROVIDER_TYPE            NOT NULL,
+  plan_review          BOOLEAN                  NOT NULL DEFAULT FALSE,
+  missed_policy        SCHEDULE_MISSED_POLICY   NOT NULL DEFAULT 'skip',
+  enabled              BOOLEAN                  NOT NULL DEFAULT TRUE,
+  next_run_at          TIMESTAMPTZ              NULL,
+  last_run_at          TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  deleted_at           TIMESTAMPTZ              NULL
+);
+
+CREATE INDEX schedules_user_id_idx ON schedules(user_id);
+CREATE INDEX schedules_playbook_id_idx ON schedules(playbook_id);
+CREATE INDEX schedules_next_run_at_idx ON schedules(next_run_at) WHERE enabled AND deleted_at IS NULL;
+
+CREATE OR REPLACE TRIGGER update_schedules_modified
+  BEFORE UPDATE ON schedules
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- History of the schedule fires, the flow is kept as a reference while it exists
+CREATE TABLE schedule_runs (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  schedule_id          BIGINT                   NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+  flow_id              BIGINT                   NULL REFERENCES flows(id) ON DELETE SET NULL,
+  status               SCHEDULE_RUN_STATUS      NOT NULL,
+  reason               TEXT                     NOT NULL DEFAULT '',
+  scheduled_at         TIMESTAMPTZ              NOT NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX schedule_runs_schedule_id_idx ON schedule_runs(schedule_id);
+CREATE INDEX schedule_runs_flow_id_idx ON schedule_runs(flow_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE schedule_runs;
+DROP TABLE schedules;
+DROP TYPE SCHEDULE_RUN_STATUS;
+DROP TYPE SCHEDULE_MISSED_POLICY;
+
+DELETE FROM privileges WHERE name IN (
+  'schedules.admin',
+  'schedules.create',
+  'schedules.delete',
+  'schedules.edit',
+  'schedules.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'campaigns.admin'),
+  (1, 'campaigns.create'),
+  (1, 'campaigns.delete'),
+  (1, 'campaigns.edit'),
+  (1, 'campaigns.view'),
+  (2, 'campaigns.create'),
+  (2, 'campaigns.delete'),
+  (2, 'campaigns.edit'),
+  (2, 'campaigns.view');
+
+CREATE TYPE CAMPAIGN_STATUS AS ENUM ('running','stopped','finished');
+CREATE TYPE CAMPAIGN_TARGET_STATUS AS ENUM ('pending','started','failed','cancelled');
+
+CREATE TABLE campaigns (
+  id                   BIGINT            PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT            NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name                 TEXT              NOT NULL,
+  status               CAMPAIGN_STATUS   NOT NULL DEFAULT 'running',
+  input                TEXT              NOT NULL DEFAULT '',
+  playbook_id          BIGINT            NULL REFERENCES playbooks(id) ON DELETE SET NULL,
+  variables            JSON              NOT NULL DEFAULT '{}',
+  scope                TEXT              NOT NULL DEFAULT '',
+  model_provider_name  TEXT              NOT NULL,
+  model_provider_type  PROVIDER_TYPE     NOT NULL,
+  plan_review          BOOLEAN           NOT NULL DEFAULT FALSE,
+  concurrency          INTEGER           NOT NULL DEFAULT 1 CHECK (concurrency > 0),
+  created_at           TIMESTAMPTZ       DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ       DEFAULT CURRENT_TIMESTAMP,
+  deleted_at           TIMESTAMPTZ       NULL
+);
+
+CREATE INDEX campaigns_user_id_idx ON campaigns(user_id);
+CREATE INDEX campaigns_status_idx ON campaigns(status) WHERE deleted_at IS NULL;
+
+CREATE OR REPLACE TRIGGER update_campaigns_modified
+  BEFORE UPDATE ON campaigns
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Every target gets its own flow, pending targets are started within the campaign concurrency limit
+CREATE TABLE campaign_targets (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  campaign_id          BIGINT                   NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+  target               TEXT                     NOT NULL,
+  status               CAMPAIGN_TARGET_STATUS   NOT NULL DEFAULT 'pending',
+  flow_id              BIGINT                   NULL REFERENCES flows(id) ON DELETE SET NULL,
+  reason               TEXT                     NOT NULL DEFAULT '',
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX campaign_targets_campaign_id_idx ON campaign_targets(campaign_id);
+CREATE INDEX campaign_targets_flow_id_idx ON campaign_targets(flow_id);
+
+CREATE OR REPLACE TRIGGER update_campaign_targets_modified
+  BEFORE UPDATE ON campaign_targets
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE campaign_targets;
+DROP TABLE campaigns;
+DROP TYPE CAMPAIGN_TARGET_STATUS;
+DROP TYPE CAMPAIGN_STATUS;
+
+DELETE FROM privileges WHERE name IN (
+  'campaigns.admin',
+  'campaigns.create',
+  'campaigns.delete',
+  'campaigns.edit',
+  'campaigns.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Add queued to the flow_status enum, flows wait in this status until admitted by the flow queue
+CREATE TYPE FLOW_STATUS_NEW AS ENUM (
+  'queued',
+  'created',
+  'running',
+  'waiting',
+  'finished',
+  'failed'
+);
+
+ALTER TABLE flows ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE flows
+    ALTER COLUMN status TYPE FLOW_STATUS_NEW USING status::text::FLOW_STATUS_NEW;
+ALTER TABLE flows ALTER COLUMN status SET DEFAULT 'created';
+
+DROP TYPE FLOW_STATUS;
+ALTER TYPE FLOW_STATUS_NEW RENAME TO FLOW_STATUS;
+
+CREATE TYPE FLOW_PRIORITY AS ENUM ('low','normal','high');
+
+-- Launch parameters of the queued flow, the record is removed when the flow worker is started
+CREATE TABLE flow_queue (
+  flow_id       BIGINT          PRIMARY KEY REFERENCES flows(id) ON DELETE CASCADE,
+  priority      FLOW_PRIORITY   NOT NULL DEFAULT 'normal',
+  input         TEXT            NOT NULL,
+  functions     JSON            NOT NULL DEFAULT '{}',
+  playbook_id   BIGINT          NULL REFERENCES playbooks(id) ON DELETE SET NULL,
+  playbook      JSON            NOT NULL DEFAULT 'null',
+  created_at    TIMESTAMPTZ     DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX flow_queue_priority_created_at_idx ON flow_queue(priority DESC, created_at ASC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE flow_queue;
+DROP TYPE FLOW_PRIORITY;
+
+UPDATE flows SET status = 'failed' WHERE status = 'queued';
+
+CREATE TYPE FLOW_STATUS_NEW AS ENUM (
+  'created',
+  'running',
+  'waiting',
+  'finished',
+  'failed'
+);
+
+ALTER TABLE flows ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE flows
+    ALTER COLUMN status TYPE FLOW_STATUS_NEW USING status::text::FLOW_STATUS_NEW;
+ALTER TABLE flows ALTER COLUMN status SET DEFAULT 'created';
+
+DROP TYPE FLOW_STATUS;
+ALTER TYPE FLOW_STATUS_NEW RENAME TO FLOW_STATUS;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Watchdog limits of the flow which override the global limits from the config, zero means the global limit
+CREATE TABLE flow_limits (
+  flow_id              BIGINT        PRIMARY KEY REFERENCES flows(id) ON DELETE CASCADE,
+  flow_time_limit      INTEGER       NOT NULL DEFAULT 0,
+  task_time_limit      INTEGER       NOT NULL DEFAULT 0,
+  subtask_time_limit   INTEGER       NOT NULL DEFAULT 0,
+  max_iterations       INTEGER       NOT NULL DEFAULT 0,
+  max_tool_calls       INTEGER       NOT NULL DEFAULT 0,
+  max_delegation_depth INTEGER       NOT NULL DEFAULT 0,
+  created_at           TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE TRIGGER update_flow_limits_modified
+  BEFORE UPDATE ON flow_limits
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE flow_limits;
+-- +goose StatementEnd
@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'webhooks.admin'),
+  (1, 'webhooks.create'),
+  (1, 'webhooks.delete'),
+  (1, 'webhooks.edit'),
+  (1, 'webhooks.view'),
+  (2, 'webhooks.create'),
+  (2, 'webhooks.delete'),
+  (2, 'webhooks.edit'),
+  (2, 'webhooks.view');
+
+CREATE TYPE WEBHOOK_DELIVERY_STATUS AS ENUM ('pending','delivered','failed');
+
+-- Webhook without the user is system-wide and receives the events of all flows,
+-- empty list of the events means all events
+CREATE TABLE webhooks (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT                   NULL REFERENCES users(id) ON DELETE CASCADE,
+  name                 TEXT                     NOT NULL,
+  url                  TEXT                     NOT NULL,
+  secret               TEXT                     NOT NULL,
+  events               TEXT[]                   NOT NULL DEFAULT '{}',
+  enabled              BOOLEAN                  NOT NULL DEFAULT TRUE,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX webhooks_user_id_idx ON webhooks(user_id);
+
+CREATE OR REPLACE TRIGGER update_webhooks_modified
+  BEFORE UPDATE ON webhooks
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Delivery log of the webhook events, pending deliveries are retried until next_attempt_at
+CREATE TABLE webhook_deliveries (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  webhook_id           BIGINT                   NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  flow_id              BIGINT                   NULL REFERENCES flows(id) ON DELETE SET NULL,
+  event                TEXT                     NOT NULL,
+  payload              JSON                     NOT NULL,
+  status               WEBHOOK_DELIVERY_STATUS  NOT NULL DEFAULT 'pending',
+  attempts             INTEGER                  NOT NULL DEFAULT 0,
+  response_code        INTEGER                  NOT NULL DEFAULT 0,
+  error                TEXT                     NOT NULL DEFAULT '',
+  next_attempt_at      TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries(webhook_id);
+CREATE INDEX webhook_deliveries_flow_id_idx ON webhook_deliveries(flow_id);
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE OR REPLACE TRIGGER update_webhook_deliveries_modified
+  BEFORE UPDATE ON webhook_deliveries
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+DROP TYPE WEBHOOK_DELIVERY_STATUS;
+
+DELETE FROM privileges WHERE name IN (
+  'webhooks.admin',
+  'webhooks.create',
+  'webhooks.delete',
+  'webhooks.edit',
+  'webhooks.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'api_keys.admin'),
+  (1, 'api_keys.create'),
+  (1, 'api_keys.delete'),
+  (1, 'api_keys.view'),
+  (2, 'api_keys.create'),
+  (2, 'api_keys.delete'),
+  (2, 'api_keys.view');
+
+-- Personal API key of the user, only the hash of the key is stored and the prefix is kept
+-- to recognize the key in the list, privileges are the subset of the user role privileges
+CREATE TABLE api_keys (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT                   NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name                 TEXT                     NOT NULL,
+  key_prefix           TEXT                     NOT NULL,
+  key_hash             TEXT                     NOT NULL,
+  privileges           TEXT[]                   NOT NULL DEFAULT '{}',
+  expires_at           TIMESTAMPTZ              NOT NULL,
+  last_used_at         TIMESTAMPTZ              NULL,
+  revoked_at           TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX api_keys_key_hash_idx ON api_keys(key_hash);
+CREATE INDEX api_keys_user_id_idx ON api_keys(user_id);
+
+CREATE OR REPLACE TRIGGER update_api_keys_modified
+  BEFORE UPDATE ON api_keys
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+
+DELETE FROM privileges WHERE name IN (
+  'api_keys.admin',
+  'api_keys.create',
+  'api_keys.delete',
+  'api_keys.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN type DROP DEFAULT;
+
+CREATE TYPE USER_TYPE_NEW AS ENUM ('local','oauth','ldap');
+
+ALTER TABLE users
+    ALTER COLUMN type TYPE USER_TYPE_NEW USING type::text::USER_TYPE_NEW;
+
+DROP TYPE USER_TYPE;
+
+ALTER TYPE USER_TYPE_NEW RENAME TO USER_TYPE;
+
+ALTER TABLE users
+    ALTER COLUMN type SET NOT NULL,
+    ALTER COLUMN type SET DEFAULT 'local';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ALTER COLUMN type DROP DEFAULT;
+
+CREATE TYPE USER_TYPE_NEW AS ENUM ('local','oauth');
+
+-- LDAP users are kept as external users without the password
+ALTER TABLE users
+    ALTER COLUMN type TYPE USER_TYPE_NEW USING (
+      CASE WHEN type = 'ldap' THEN 'oauth' ELSE type::text END
+    )::USER_TYPE_NEW;
+
+DROP TYPE USER_TYPE;
+
+ALTER TYPE USER_TYPE_NEW RENAME TO USER_TYPE;
+
+ALTER TABLE users
+    ALTER COLUMN type SET NOT NULL,
+    ALTER COLUMN type SET DEFAULT 'local';
+-- +goose StatementEnd
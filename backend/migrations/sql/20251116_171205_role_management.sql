@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'roles.create'),
+  (1, 'roles.edit')
+  ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM privileges WHERE name IN (
+  'roles.create',
+  'roles.edit'
+);
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'teams.admin'),
+  (1, 'teams.view'),
+  (1, 'teams.create'),
+  (1, 'teams.edit'),
+  (1, 'teams.delete'),
+  (2, 'teams.view'),
+  (2, 'teams.create'),
+  (2, 'teams.edit'),
+  (2, 'teams.delete')
+  ON CONFLICT DO NOTHING;
+
+CREATE TYPE TEAM_ROLE AS ENUM ('viewer', 'operator', 'admin');
+
+CREATE TABLE teams (
+  id               BIGINT        PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  name             TEXT          NOT NULL,
+  description      TEXT          NOT NULL DEFAULT '',
+  created_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  updated_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX teams_name_unique ON teams(LOWER(name));
+
+CREATE OR REPLACE TRIGGER update_teams_modified
+  BEFORE UPDATE ON teams
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+CREATE TABLE team_members (
+  team_id          BIGINT        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+  user_id          BIGINT        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role             TEAM_ROLE     NOT NULL DEFAULT 'viewer',
+  created_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  updated_at       TIMESTAMPTZ   DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX team_members_user_id_idx ON team_members(user_id);
+
+CREATE OR REPLACE TRIGGER update_team_members_modified
+  BEFORE UPDATE ON team_members
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Team owned resources stay owned by the user who created them, the team gives access to its members
+ALTER TABLE flows ADD COLUMN team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE providers ADD COLUMN team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE prompts ADD COLUMN team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE playbooks ADD COLUMN team_id BIGINT NULL REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX flows_team_id_idx ON flows(team_id);
+CREATE INDEX providers_team_id_idx ON providers(team_id);
+CREATE INDEX prompts_team_id_idx ON prompts(team_id);
+CREATE INDEX playbooks_team_id_idx ON playbooks(team_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE playbooks DROP COLUMN team_id;
+ALTER TABLE prompts DROP COLUMN team_id;
+ALTER TABLE providers DROP COLUMN team_id;
+ALTER TABLE flows DROP COLUMN team_id;
+
+DROP TABLE team_members;
+DROP TABLE teams;
+DROP TYPE TEAM_ROLE;
+
+DELETE FROM privileges WHERE name IN (
+  'teams.admin',
+  'teams.view',
+  'teams.create',
+  'teams.edit',
+  'teams.delete'
+);
+-- +goose StatementEnd
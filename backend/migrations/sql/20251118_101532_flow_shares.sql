@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'flow_shares.admin'),
+  (1, 'flow_shares.create'),
+  (1, 'flow_shares.delete'),
+  (1, 'flow_shares.view'),
+  (2, 'flow_shares.create'),
+  (2, 'flow_shares.delete'),
+  (2, 'flow_shares.view')
+  ON CONFLICT DO NOTHING;
+
+-- Read-only link to the flow for people without account, only the hash of the signed token is stored
+-- and the prefix is kept to recognize the link in the list
+CREATE TABLE flow_shares (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  flow_id              BIGINT                   NOT NULL REFERENCES flows(id) ON DELETE CASCADE,
+  user_id              BIGINT                   NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_prefix         TEXT                     NOT NULL,
+  token_hash           TEXT                     NOT NULL,
+  with_terminal_logs   BOOLEAN                  NOT NULL DEFAULT FALSE,
+  expires_at           TIMESTAMPTZ              NOT NULL,
+  revoked_at           TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX flow_shares_token_hash_idx ON flow_shares(token_hash);
+CREATE INDEX flow_shares_flow_id_idx ON flow_shares(flow_id);
+
+CREATE OR REPLACE TRIGGER update_flow_shares_modified
+  BEFORE UPDATE ON flow_shares
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Each request made with the share link, so the owner can see who looked at the flow
+CREATE TABLE flow_share_accesses (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  share_id             BIGINT                   NOT NULL REFERENCES flow_shares(id) ON DELETE CASCADE,
+  resource             TEXT                     NOT NULL,
+  remote_addr          TEXT                     NOT NULL DEFAULT '',
+  user_agent           TEXT                     NOT NULL DEFAULT '',
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX flow_share_accesses_share_id_idx ON flow_share_accesses(share_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE flow_share_accesses;
+DROP TABLE flow_shares;
+
+DELETE FROM privileges WHERE name IN (
+  'flow_shares.admin',
+  'flow_shares.create',
+  'flow_shares.delete',
+  'flow_shares.view'
+);
+-- +goose StatementEnd
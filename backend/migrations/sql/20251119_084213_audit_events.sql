@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'audit_events.view')
+  ON CONFLICT DO NOTHING;
+
+-- Append-only log of the security-relevant actions, the actor and the target aren't referenced
+-- by the foreign keys and the actor mail is copied, so the events outlive the deleted records
+CREATE TABLE audit_events (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT                   NULL,
+  user_mail            TEXT                     NOT NULL DEFAULT '',
+  action               TEXT                     NOT NULL,
+  target_type          TEXT                     NOT NULL,
+  target_id            BIGINT                   NULL,
+  target_name          TEXT                     NOT NULL DEFAULT '',
+  old_values           JSON                     NOT NULL DEFAULT '{}',
+  new_values           JSON                     NOT NULL DEFAULT '{}',
+  remote_addr          TEXT                     NOT NULL DEFAULT '',
+  user_agent           TEXT                     NOT NULL DEFAULT '',
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events(created_at);
+CREATE INDEX audit_events_user_id_idx ON audit_events(user_id);
+CREATE INDEX audit_events_action_idx ON audit_events(action);
+CREATE INDEX audit_events_target_idx ON audit_events(target_type, target_id);
+
+CREATE OR REPLACE FUNCTION reject_audit_events_change()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reject_audit_events_modify
+  BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE PROCEDURE reject_audit_events_change();
+
+CREATE TRIGGER reject_audit_events_truncate
+  BEFORE TRUNCATE ON audit_events
+  FOR EACH STATEMENT EXECUTE PROCEDURE reject_audit_events_change();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_events;
+DROP FUNCTION reject_audit_events_change;
+
+DELETE FROM privileges WHERE name IN (
+  'audit_events.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The log pages are read by the flow in the id order, so the composite indexes serve both
+-- the flow filter and the keyset cursor and replace the single column flow indexes
+DROP INDEX IF EXISTS msglogs_flow_id_idx;
+CREATE INDEX msglogs_flow_id_id_idx ON msglogs(flow_id, id);
+
+DROP INDEX IF EXISTS agentlogs_flow_id_idx;
+CREATE INDEX agentlogs_flow_id_id_idx ON agentlogs(flow_id, id);
+
+DROP INDEX IF EXISTS searchlogs_flow_id_idx;
+CREATE INDEX searchlogs_flow_id_id_idx ON searchlogs(flow_id, id);
+
+DROP INDEX IF EXISTS vecstorelogs_flow_id_idx;
+CREATE INDEX vecstorelogs_flow_id_id_idx ON vecstorelogs(flow_id, id);
+
+DROP INDEX IF EXISTS termlogs_container_id_idx;
+CREATE INDEX termlogs_container_id_id_idx ON termlogs(container_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS msglogs_flow_id_id_idx;
+CREATE INDEX msglogs_flow_id_idx ON msglogs(flow_id);
+
+DROP INDEX IF EXISTS agentlogs_flow_id_id_idx;
+CREATE INDEX agentlogs_flow_id_idx ON agentlogs(flow_id);
+
+DROP INDEX IF EXISTS searchlogs_flow_id_id_idx;
+CREATE INDEX searchlogs_flow_id_idx ON searchlogs(flow_id);
+
+DROP INDEX IF EXISTS vecstorelogs_flow_id_id_idx;
+CREATE INDEX vecstorelogs_flow_id_idx ON vecstorelogs(flow_id);
+
+DROP INDEX IF EXISTS termlogs_container_id_id_idx;
+CREATE INDEX termlogs_container_id_idx ON termlogs(container_id);
+-- +goose StatementEnd
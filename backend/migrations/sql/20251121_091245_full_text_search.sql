@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The document is cut to keep the tsvector under its size limit on the huge terminal outputs,
+-- the simple configuration keeps hosts, paths and exploit names as is without stemming
+CREATE OR REPLACE FUNCTION search_document(doc TEXT)
+RETURNS TSVECTOR AS $$
+  SELECT to_tsvector('simple'::REGCONFIG, left(doc, 200000));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- The headline is built over the same document prefix which is indexed by search_document,
+-- so the huge terminal outputs don't make every search query parse the whole text
+CREATE OR REPLACE FUNCTION search_headline(doc TEXT, query TSQUERY, options TEXT)
+RETURNS TEXT AS $$
+  SELECT ts_headline('simple'::REGCONFIG, left(doc, 200000), query, options);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX flows_title_search_idx ON flows
+  USING GIN (search_document(title));
+CREATE INDEX tasks_search_idx ON tasks
+  USING GIN (search_document(title || E'\n' || input || E'\n' || result));
+CREATE INDEX subtasks_search_idx ON subtasks
+  USING GIN (search_document(title || E'\n' || description || E'\n' || result));
+CREATE INDEX msglogs_search_idx ON msglogs
+  USING GIN (search_document(message || E'\n' || result));
+CREATE INDEX termlogs_search_idx ON termlogs
+  USING GIN (search_document(text));
+CREATE INDEX searchlogs_search_idx ON searchlogs
+  USING GIN (search_document(query || E'\n' || result));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS flows_title_search_idx;
+DROP INDEX IF EXISTS tasks_search_idx;
+DROP INDEX IF EXISTS subtasks_search_idx;
+DROP INDEX IF EXISTS msglogs_search_idx;
+DROP INDEX IF EXISTS termlogs_search_idx;
+DROP INDEX IF EXISTS searchlogs_search_idx;
+
+DROP FUNCTION IF EXISTS search_headline;
+DROP FUNCTION IF EXISTS search_document;
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO privileges (role_id, name) VALUES
+  (1, 'mfa.admin'),
+  (1, 'sessions.admin'),
+  (1, 'sessions.delete'),
+  (1, 'sessions.view'),
+  (2, 'sessions.delete'),
+  (2, 'sessions.view')
+  ON CONFLICT DO NOTHING;
+
+-- Failed login attempts by the account or the client address key, the key is locked
+-- for the exponentially growing time after the allowed number of failures
+CREATE TABLE login_throttles (
+  key                  TEXT                     PRIMARY KEY,
+  failures             INTEGER                  NOT NULL DEFAULT 0,
+  last_failure_at      TIMESTAMPTZ              NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_until         TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE TRIGGER update_login_throttles_modified
+  BEFORE UPDATE ON login_throttles
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- TOTP second factor of the local user, it's enforced on login after the confirmation only,
+-- the recovery codes are stored as hashes and removed after use
+CREATE TABLE user_mfa (
+  user_id              BIGINT                   PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret               TEXT                     NOT NULL,
+  recovery_codes       TEXT[]                   NOT NULL DEFAULT '{}',
+  last_used_step       BIGINT                   NOT NULL DEFAULT 0,
+  confirmed_at         TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE TRIGGER update_user_mfa_modified
+  BEFORE UPDATE ON user_mfa
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+
+-- Login session of the cookie, the cookie keeps the random session id and only its hash is stored,
+-- so the session can be listed and revoked before the cookie expires
+CREATE TABLE user_sessions (
+  id                   BIGINT                   PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  user_id              BIGINT                   NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  sid_hash             TEXT                     NOT NULL,
+  method               TEXT                     NOT NULL,
+  remote_addr          TEXT                     NOT NULL DEFAULT '',
+  user_agent           TEXT                     NOT NULL DEFAULT '',
+  expires_at           TIMESTAMPTZ              NOT NULL,
+  last_seen_at         TIMESTAMPTZ              NULL,
+  revoked_at           TIMESTAMPTZ              NULL,
+  created_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP,
+  updated_at           TIMESTAMPTZ              DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX user_sessions_sid_hash_idx ON user_sessions(sid_hash);
+CREATE INDEX user_sessions_user_id_idx ON user_sessions(user_id);
+
+CREATE OR REPLACE TRIGGER update_user_sessions_modified
+  BEFORE UPDATE ON user_sessions
+  FOR EACH ROW EXECUTE PROCEDURE update_modified_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE user_sessions;
+DROP TABLE user_mfa;
+DROP TABLE login_throttles;
+
+DELETE FROM privileges WHERE name IN (
+  'mfa.admin',
+  'mfa.required',
+  'sessions.admin',
+  'sessions.delete',
+  'sessions.view'
+);
+-- +goose StatementEnd
@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Action string
+
+const (
+	ActionLogin          Action = "auth.login"
+	ActionLoginFailed    Action = "auth.login_failed"
+	ActionLoginLocked    Action = "auth.login_locked"
+	ActionLogout         Action = "auth.logout"
+	ActionPasswordChange Action = "user.password_change"
+
+	ActionMFAEnable        Action = "user.mfa_enable"
+	ActionMFADisable       Action = "user.mfa_disable"
+	ActionMFARecoveryCodes Action = "user.mfa_recovery_codes"
+
+	ActionSessionRevoke Action = "session.revoke"
+
+	ActionUserCreate Action = "user.create"
+	ActionUserUpdate Action = "user.update"
+	ActionUserDelete Action = "user.delete"
+
+	ActionRoleCreate Action = "role.create"
+	ActionRoleUpdate Action = "role.update"
+
+	ActionAPIKeyCreate Action = "api_key.create"
+	ActionAPIKeyRevoke Action = "api_key.revoke"
+
+	ActionFlowCreate Action = "flow.create"
+	ActionFlowDelete Action = "flow.delete"
+	ActionFlowShare  Action = "flow.share"
+
+	ActionFlowShareCreate Action = "flow_share.create"
+	ActionFlowShareRevoke Action = "flow_share.revoke"
+
+	ActionProviderCreate Action = "provider.create"
+	ActionProviderUpdate Action = "provider.update"
+	ActionProviderDelete Action = "provider.delete"
+	ActionProviderShare  Action = "provider.share"
+
+	ActionPromptCreate Action = "prompt.create"
+	ActionPromptUpdate Action = "prompt.update"
+	ActionPromptDelete Action = "prompt.delete"
+	ActionPromptShare  Action = "prompt.share"
+
+	ActionWebhookCreate Action = "webhook.create"
+	ActionWebhookUpdate Action = "webhook.update"
+	ActionWebhookDelete Action = "webhook.delete"
+
+	ActionTeamCreate       Action = "team.create"
+	ActionTeamUpdate       Action = "team.update"
+	ActionTeamDelete       Action = "team.delete"
+	ActionTeamMemberSet    Action = "team.member_set"
+	ActionTeamMemberRemove Action = "team.member_remove"
+)
+
+const (
+	TargetUser      = "user"
+	TargetRole      = "role"
+	TargetSession   = "session"
+	TargetAPIKey    = "api_key"
+	TargetFlow      = "flow"
+	TargetFlowShare = "flow_share"
+	TargetProvider  = "provider"
+	TargetPrompt    = "prompt"
+	TargetWebhook   = "webhook"
+	TargetTeam      = "team"
+)
+
+// Event is the action on the target, the before and after values of the config objects
+// are reduced to the changed fields with the secrets masked
+type Event struct {
+	Action     Action
+	TargetType string
+	TargetID   int64
+	TargetName string
+	Before     any
+	After      any
+}
+
+// Record is the stored event which is exported to SIEM
+type Record struct {
+	ID         int64          `json:"id"`
+	Timestamp  time.Time      `json:"timestamp"`
+	UserID     int64          `json:"userId,omitempty"`
+	UserMail   string         `json:"userMail,omitempty"`
+	Action     Action         `json:"action"`
+	TargetType string         `json:"targetType"`
+	TargetID   int64          `json:"targetId,omitempty"`
+	TargetName string         `json:"targetName,omitempty"`
+	OldValues  map[string]any `json:"oldValues,omitempty"`
+	NewValues  map[string]any `json:"newValues,omitempty"`
+	RemoteAddr string         `json:"remoteAddr,omitempty"`
+	UserAgent  string         `json:"userAgent,omitempty"`
+}
+
+// Actor is the user and the client which made the request, the zero user means unauthenticated request
+type Actor struct {
+	UserID     int64
+	RemoteAddr string
+	UserAgent  string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// Recorder stores the event with the actor from the context, the failures are logged only
+// so the audited action isn't rolled back after it's already done
+type Recorder interface {
+	Record(ctx context.Context, event Event)
+}
+
+type Logger interface {
+	Recorder
+	Close() error
+}
+
+type exporter interface {
+	Export(record Record) error
+	Close() error
+}
+
+type recorder struct {
+	db       database.Querier
+	exporter exporter
+	logger   *logrus.Entry
+}
+
+// NewLogger returns the recorder which stores the events to the audit_events table
+// and exports them to syslog or JSON lines file if it's configured
+func NewLogger(cfg *config.Config, db database.Querier) (Logger, error) {
+	var (
+		err error
+		exp exporter
+	)
+
+	switch cfg.AuditExport {
+	case "":
+	case "jsonl":
+		exp, err = newJSONLinesExporter(cfg.AuditJSONLPath)
+	case "syslog":
+		exp, err = newSyslogExporter(cfg.AuditSyslogNetwork, cfg.AuditSyslogAddr, cfg.AuditSyslogTag)
+	default:
+		err = fmt.Errorf("unknown audit export type '%s'", cfg.AuditExport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit exporter: %w", err)
+	}
+
+	return &recorder{
+		db:       db,
+		exporter: exp,
+		logger:   logrus.WithField("component", "audit"),
+	}, nil
+}
+
+func (r *recorder) Record(ctx context.Context, event Event) {
+	actor := ActorFromContext(ctx)
+	oldValues, newValues := Diff(event.Before, event.After)
+
+	logger := r.logger.WithFields(logrus.Fields{
+		"user_id":     actor.UserID,
+		"action":      event.Action,
+		"target_type": event.TargetType,
+		"target_id":   event.TargetID,
+	})
+
+	var userMail string
+	if actor.UserID != 0 {
+		if user, err := r.db.GetUser(ctx, actor.UserID); err == nil {
+			userMail = user.Mail
+		}
+	}
+
+	created, err := r.db.CreateAuditEvent(ctx, database.CreateAuditEventParams{
+		UserID:     nullID(actor.UserID),
+		UserMail:   userMail,
+		Action:     string(event.Action),
+		TargetType: event.TargetType,
+		TargetID:   nullID(event.TargetID),
+		TargetName: event.TargetName,
+		OldValues:  marshalValues(oldValues),
+		NewValues:  marshalValues(newValues),
+		RemoteAddr: actor.RemoteAddr,
+		UserAgent:  actor.UserAgent,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to store audit event")
+		return
+	}
+
+	if r.exporter == nil {
+		return
+	}
+
+	err = r.exporter.Export(Record{
+		ID:         created.ID,
+		Timestamp:  created.CreatedAt.Time.UTC(),
+		UserID:     actor.UserID,
+		UserMail:   userMail,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		TargetName: event.TargetName,
+		OldValues:  oldValues,
+		NewValues:  newValues,
+		RemoteAddr: actor.RemoteAddr,
+		UserAgent:  actor.UserAgent,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to export audit event")
+	}
+}
+
+func (r *recorder) Close() error {
+	if r.exporter == nil {
+		return nil
+	}
+
+	return r.exporter.Close()
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
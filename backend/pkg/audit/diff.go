@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+)
+
+// MaskedValue replaces the values of the secret fields in the diff
+const MaskedValue = "[MASKED]"
+
+var secretFieldPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|hash|authorization|cookie|api[-_]?key|access[-_]?key|private[-_]?key)`)
+
+// Diff returns the fields of the objects which differ, the object is converted to the fields by its JSON form,
+// the nil before or after means the created or deleted object, so all its fields are returned
+func Diff(before, after any) (map[string]any, map[string]any) {
+	oldFields, newFields := toFields(before), toFields(after)
+
+	oldValues, newValues := make(map[string]any), make(map[string]any)
+	for key, value := range oldFields {
+		if newValue, ok := newFields[key]; !ok || !reflect.DeepEqual(value, newValue) {
+			oldValues[key] = maskValue(key, value)
+		}
+	}
+	for key, value := range newFields {
+		if oldValue, ok := oldFields[key]; !ok || !reflect.DeepEqual(value, oldValue) {
+			newValues[key] = maskValue(key, value)
+		}
+	}
+
+	return oldValues, newValues
+}
+
+// toFields returns the top-level fields of the object, the scalar values are stored as "value" field
+func toFields(value any) map[string]any {
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil()) {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		var scalar any
+		if err := json.Unmarshal(data, &scalar); err != nil {
+			return nil
+		}
+		return map[string]any{"value": scalar}
+	}
+
+	return fields
+}
+
+// maskValue hides the secrets in the field and in the nested objects of the field
+func maskValue(key string, value any) any {
+	if secretFieldPattern.MatchString(key) {
+		if value == nil || value == "" {
+			return value
+		}
+		return MaskedValue
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for k, nested := range v {
+			masked[k] = maskValue(k, nested)
+		}
+		return masked
+	case []any:
+		masked := make([]any, 0, len(v))
+		for _, nested := range v {
+			masked = append(masked, maskValue("", nested))
+		}
+		return masked
+	default:
+		return value
+	}
+}
+
+func marshalValues(values map[string]any) json.RawMessage {
+	data, err := json.Marshal(values)
+	if err != nil || values == nil {
+		return json.RawMessage("{}")
+	}
+
+	return data
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Secret  string            `json:"secret"`
+	Headers map[string]string `json:"headers"`
+}
+
+func TestDiffChangedFields(t *testing.T) {
+	before := testConfig{Name: "hook", URL: "https://a.example", Secret: "old-secret"}
+	after := testConfig{Name: "hook", URL: "https://b.example", Secret: "new-secret"}
+
+	oldValues, newValues := Diff(before, after)
+	assert.Equal(t, map[string]any{"url": "https://a.example", "secret": MaskedValue}, oldValues)
+	assert.Equal(t, map[string]any{"url": "https://b.example", "secret": MaskedValue}, newValues)
+}
+
+func TestDiffCreatedAndDeleted(t *testing.T) {
+	value := &testConfig{Name: "hook", Headers: map[string]string{"X-Api-Key": "key", "X-Team": "red"}}
+
+	oldValues, newValues := Diff(nil, value)
+	assert.Empty(t, oldValues)
+	assert.Equal(t, "hook", newValues["name"])
+	assert.Equal(t, "", newValues["secret"])
+	assert.Equal(t, map[string]any{"X-Api-Key": MaskedValue, "X-Team": "red"}, newValues["headers"])
+
+	var missing *testConfig
+	oldValues, newValues = Diff(value, missing)
+	assert.Equal(t, "hook", oldValues["name"])
+	assert.Empty(t, newValues)
+}
+
+func TestDiffScalarValues(t *testing.T) {
+	oldValues, newValues := Diff(nil, map[string]string{"method": "local"})
+	assert.Empty(t, oldValues)
+	assert.Equal(t, map[string]any{"method": "local"}, newValues)
+
+	oldValues, newValues = Diff([]string{"flows.view"}, []string{"flows.view", "flows.edit"})
+	assert.Equal(t, map[string]any{"value": []any{"flows.view"}}, oldValues)
+	assert.Equal(t, map[string]any{"value": []any{"flows.view", "flows.edit"}}, newValues)
+}
+
+func TestMarshalValues(t *testing.T) {
+	assert.JSONEq(t, `{}`, string(marshalValues(nil)))
+	assert.JSONEq(t, `{"name":"hook"}`, string(marshalValues(map[string]any{"name": "hook"})))
+}
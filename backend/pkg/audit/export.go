@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// jsonLinesExporter appends the records to the file, the file is opened once and must be rotated by copytruncate
+type jsonLinesExporter struct {
+	mx   *sync.Mutex
+	file *os.File
+}
+
+func newJSONLinesExporter(path string) (exporter, error) {
+	if path == "" {
+		return nil, errors.New("audit JSON lines path is required")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit JSON lines file: %w", err)
+	}
+
+	return &jsonLinesExporter{
+		mx:   &sync.Mutex{},
+		file: file,
+	}, nil
+}
+
+func (e *jsonLinesExporter) Export(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	_, err = e.file.Write(append(data, '\n'))
+	return err
+}
+
+func (e *jsonLinesExporter) Close() error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	return e.file.Close()
+}
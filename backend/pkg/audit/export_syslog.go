@@ -0,0 +1,37 @@
+//go:build !windows
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+)
+
+// syslogExporter sends the records as JSON messages with the auth facility,
+// the empty network and address mean the local syslog daemon
+type syslogExporter struct {
+	writer *syslog.Writer
+}
+
+func newSyslogExporter(network, addr, tag string) (exporter, error) {
+	writer, err := syslog.Dial(network, addr, syslog.LOG_NOTICE|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+
+	return &syslogExporter{writer: writer}, nil
+}
+
+func (e *syslogExporter) Export(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return e.writer.Notice(string(data))
+}
+
+func (e *syslogExporter) Close() error {
+	return e.writer.Close()
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package audit
+
+import "errors"
+
+func newSyslogExporter(network, addr, tag string) (exporter, error) {
+	return nil, errors.New("syslog audit export is not supported on windows")
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLinesExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	exp, err := newJSONLinesExporter(path)
+	require.NoError(t, err)
+
+	timestamp := time.Date(2025, 11, 19, 8, 42, 13, 0, time.UTC)
+	for id := int64(1); id <= 2; id++ {
+		require.NoError(t, exp.Export(Record{
+			ID:         id,
+			Timestamp:  timestamp,
+			UserID:     7,
+			Action:     ActionFlowDelete,
+			TargetType: TargetFlow,
+			TargetID:   42,
+			OldValues:  map[string]any{"title": "scan"},
+			RemoteAddr: "10.0.0.1",
+		}))
+	}
+	require.NoError(t, exp.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(2), records[1].ID)
+	assert.Equal(t, ActionFlowDelete, records[0].Action)
+	assert.Equal(t, map[string]any{"title": "scan"}, records[0].OldValues)
+	assert.True(t, timestamp.Equal(records[0].Timestamp))
+}
+
+func TestJSONLinesExporterPathRequired(t *testing.T) {
+	_, err := newJSONLinesExporter("")
+	assert.Error(t, err)
+}
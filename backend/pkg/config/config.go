@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/google/uuid"
@@ -19,6 +20,22 @@ type Config struct {
 	DataDir     string `env:"DATA_DIR" envDefault:"./data"`
 	AskUser     bool   `env:"ASK_USER" envDefault:"false"`
 
+	// Subtasks without unfinished dependencies are executed concurrently up to this limit
+	SubtasksParallelLimit int `env:"SUBTASKS_PARALLEL_LIMIT" envDefault:"1"`
+
+	// Flow queue admits new flows while active flows are below these limits, 0 means no limit
+	FlowQueueGlobalLimit   int `env:"FLOW_QUEUE_GLOBAL_LIMIT" envDefault:"0"`
+	FlowQueueUserLimit     int `env:"FLOW_QUEUE_USER_LIMIT" envDefault:"0"`
+	FlowQueueProviderLimit int `env:"FLOW_QUEUE_PROVIDER_LIMIT" envDefault:"0"`
+
+	// Watchdog limits of the flow execution, time limits are in seconds, 0 means no limit
+	FlowTimeLimit           int `env:"FLOW_TIME_LIMIT" envDefault:"0"`
+	TaskTimeLimit           int `env:"TASK_TIME_LIMIT" envDefault:"0"`
+	SubtaskTimeLimit        int `env:"SUBTASK_TIME_LIMIT" envDefault:"0"`
+	AgentMaxIterations      int `env:"AGENT_MAX_ITERATIONS" envDefault:"0"`
+	AgentMaxToolCalls       int `env:"AGENT_MAX_TOOL_CALLS" envDefault:"0"`
+	AgentMaxDelegationDepth int `env:"AGENT_MAX_DELEGATION_DEPTH" envDefault:"0"`
+
 	// For communication with PentAGI Cloud API
 	InstallationID string `env:"INSTALLATION_ID"`
 	LicenseKey     string `env:"LICENSE_KEY"`
@@ -104,6 +121,15 @@ type Config struct {
 	BedrockSessionToken string `env:"BEDROCK_SESSION_TOKEN"`
 	BedrockServerURL    string `env:"BEDROCK_SERVER_URL"`
 
+	// Record/replay LLM provider
+	ReplayCassettesDir     string `env:"REPLAY_CASSETTES_DIR"`
+	ReplayRecordEnabled    bool   `env:"REPLAY_RECORD_ENABLED" envDefault:"false"`
+	ReplayMissPolicy       string `env:"REPLAY_MISS_POLICY" envDefault:"fail"`
+	ReplayFallbackProvider string `env:"REPLAY_FALLBACK_PROVIDER"`
+
+	// Scripted mock LLM provider
+	MockScriptPath string `env:"MOCK_SCRIPT_PATH"`
+
 	// DuckDuckGo search engine
 	DuckDuckGoEnabled bool `env:"DUCKDUCKGO_ENABLED" envDefault:"true"`
 
@@ -120,9 +146,48 @@ type Config struct {
 	OAuthGithubClientID     string `env:"OAUTH_GITHUB_CLIENT_ID"`
 	OAuthGithubClientSecret string `env:"OAUTH_GITHUB_CLIENT_SECRET"`
 
+	// Generic OpenID Connect provider
+	OIDCProviderName    string   `env:"OIDC_PROVIDER_NAME" envDefault:"oidc"`
+	OIDCIssuerURL       string   `env:"OIDC_ISSUER_URL"`
+	OIDCClientID        string   `env:"OIDC_CLIENT_ID"`
+	OIDCClientSecret    string   `env:"OIDC_CLIENT_SECRET"`
+	OIDCScopes          []string `env:"OIDC_SCOPES" envDefault:"openid,email,profile"`
+	OIDCGroupsClaim     string   `env:"OIDC_GROUPS_CLAIM" envDefault:"groups"`
+	OIDCRoleMapping     string   `env:"OIDC_ROLE_MAPPING" envDefault:"*:User"`
+	OIDCUsernameAsEmail bool     `env:"OIDC_USERNAME_AS_EMAIL" envDefault:"false"`
+
+	// LDAP bind authentication for the login form
+	LDAPURL            string `env:"LDAP_URL"`
+	LDAPStartTLS       bool   `env:"LDAP_START_TLS" envDefault:"false"`
+	LDAPSkipVerify     bool   `env:"LDAP_SKIP_VERIFY" envDefault:"false"`
+	LDAPBindDN         string `env:"LDAP_BIND_DN"`
+	LDAPBindPassword   string `env:"LDAP_BIND_PASSWORD"`
+	LDAPBaseDN         string `env:"LDAP_BASE_DN"`
+	LDAPUserFilter     string `env:"LDAP_USER_FILTER" envDefault:"(|(uid={login})(mail={login}))"`
+	LDAPMailAttribute  string `env:"LDAP_MAIL_ATTRIBUTE" envDefault:"mail"`
+	LDAPNameAttribute  string `env:"LDAP_NAME_ATTRIBUTE" envDefault:"cn"`
+	LDAPGroupAttribute string `env:"LDAP_GROUP_ATTRIBUTE" envDefault:"memberOf"`
+	LDAPRoleMapping    string `env:"LDAP_ROLE_MAPPING" envDefault:"*:User"`
+
+	// Brute-force protection of the login form, the lockout is doubled on each failure after the limit
+	// in seconds, the zero attempts number disables the limit
+	LoginMaxAttempts   int `env:"LOGIN_MAX_ATTEMPTS" envDefault:"5"`
+	LoginIPMaxAttempts int `env:"LOGIN_IP_MAX_ATTEMPTS" envDefault:"20"`
+	LoginLockoutBase   int `env:"LOGIN_LOCKOUT_BASE" envDefault:"30"`
+	LoginLockoutMax    int `env:"LOGIN_LOCKOUT_MAX" envDefault:"900"`
+	LoginFailureWindow int `env:"LOGIN_FAILURE_WINDOW" envDefault:"3600"`
+
 	// Public URL for auth callback
 	PublicURL string `env:"PUBLIC_URL" envDefault:""`
 
+	// Audit log export for SIEM, the events are always stored in the audit_events table,
+	// the export type is either empty, "jsonl" or "syslog"
+	AuditExport        string `env:"AUDIT_EXPORT" envDefault:""`
+	AuditJSONLPath     string `env:"AUDIT_JSONL_PATH"`
+	AuditSyslogNetwork string `env:"AUDIT_SYSLOG_NETWORK"`
+	AuditSyslogAddr    string `env:"AUDIT_SYSLOG_ADDR"`
+	AuditSyslogTag     string `env:"AUDIT_SYSLOG_TAG" envDefault:"pentagi"`
+
 	// Traversaal search engine
 	TraversaalAPIKey string `env:"TRAVERSAAL_API_KEY"`
 
@@ -160,6 +225,10 @@ type Config struct {
 	// Telemetry (observability OpenTelemetry collector)
 	TelemetryEndpoint string `env:"OTEL_HOST"`
 
+	// Prometheus metrics endpoint, it exports the same meters which are sent to the OpenTelemetry collector
+	MetricsEnabled bool   `env:"METRICS_ENABLED" envDefault:"false"`
+	MetricsToken   string `env:"METRICS_TOKEN"`
+
 	// Langfuse
 	LangfuseBaseURL   string `env:"LANGFUSE_BASE_URL"`
 	LangfuseProjectID string `env:"LANGFUSE_PROJECT_ID"`
@@ -196,6 +265,29 @@ func NewConfig() (*Config, error) {
 	return &config, nil
 }
 
+// secretEnvPattern matches the names of the variables which contain credentials
+var secretEnvPattern = regexp.MustCompile(`_(KEY|KEY_ID|SECRET|SECRET_KEY|SECRET_ACCESS_KEY|PASSWORD|TOKEN|SALT)$`)
+
+// SecretValues returns the non-empty values of the credentials from the config,
+// they are redacted from the flow data which is shown to people without account
+func (c *Config) SecretValues() []string {
+	var values []string
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := field.Tag.Get("env")
+		if field.Type.Kind() != reflect.String || !secretEnvPattern.MatchString(name) {
+			continue
+		}
+		if value := v.Field(i).String(); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
 func ensureInstallationID(config *Config) {
 	// validate current installation ID from environment
 	if config.InstallationID != "" && uuid.Validate(config.InstallationID) == nil {
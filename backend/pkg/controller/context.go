@@ -23,6 +23,15 @@ type FlowContext struct {
 	FlowID    int64
 	FlowTitle string
 
+	// PlanReview holds the generated subtasks until the user approves the task plan
+	PlanReview bool
+
+	// ParallelLimit is the max number of ready subtasks of the task which are executed concurrently
+	ParallelLimit int
+
+	// Limits are the global watchdog limits which are overridden by the flow limits on each task run
+	Limits providers.Limits
+
 	Executor  tools.FlowToolsExecutor
 	Provider  providers.FlowProvider
 	Publisher subscriptions.FlowPublisher
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,11 +16,13 @@ import (
 	"pentagi/pkg/graph/subscriptions"
 	obs "pentagi/pkg/observability"
 	"pentagi/pkg/observability/langfuse"
+	"pentagi/pkg/playbook"
 	"pentagi/pkg/providers"
 	"pentagi/pkg/providers/pconfig"
 	"pentagi/pkg/providers/provider"
 	"pentagi/pkg/templates"
 	"pentagi/pkg/tools"
+	"pentagi/pkg/webhooks"
 
 	"github.com/sirupsen/logrus"
 )
@@ -39,6 +42,11 @@ type FlowWorker interface {
 	ListAssistants(ctx context.Context) []AssistantWorker
 	ListTasks(ctx context.Context) []TaskWorker
 	PutInput(ctx context.Context, input string) error
+	PatchTaskPlan(ctx context.Context, taskID int64, patch tools.SubtaskPatch) error
+	ApproveTaskPlan(ctx context.Context, taskID int64) error
+	RetrySubtask(ctx context.Context, taskID, subtaskID int64, instructions string) error
+	SkipSubtask(ctx context.Context, taskID int64) error
+	InsertSubtask(ctx context.Context, taskID int64, title, description string) error
 	Finish(ctx context.Context) error
 	Stop(ctx context.Context) error
 }
@@ -55,16 +63,32 @@ type flowWorker struct {
 	taskWG  *sync.WaitGroup
 	input   chan flowInput
 	flowCtx *FlowContext
+	hooks   webhooks.Notifier
 	logger  *logrus.Entry
+
+	// started is the start of the flow worker which the flow time limit is counted from
+	started time.Time
+
+	// playbook is set for flows created from a playbook, next is an index of the next playbook task
+	playbook     *playbook.Playbook
+	playbookNext int
 }
 
 type newFlowWorkerCtx struct {
-	userID    int64
-	input     string
-	dryRun    bool
-	prvname   provider.ProviderName
-	prvtype   provider.ProviderType
-	functions *tools.Functions
+	userID     int64
+	input      string
+	dryRun     bool
+	prvname    provider.ProviderName
+	prvtype    provider.ProviderType
+	functions  *tools.Functions
+	planReview bool
+
+	playbook   *playbook.Playbook
+	playbookID *int64
+
+	// flow is the queued flow record which is admitted by the flow queue,
+	// the new flow record is created if it's nil
+	flow *database.Flow
 
 	flowWorkerCtx
 }
@@ -75,6 +99,7 @@ type flowWorkerCtx struct {
 	docker docker.DockerClient
 	provs  providers.ProviderController
 	subs   subscriptions.SubscriptionsController
+	hooks  webhooks.Notifier
 
 	flowProviderControllers
 }
@@ -103,6 +128,10 @@ const flowInputTimeout = 1 * time.Second
 type flowInput struct {
 	input string
 	done  chan error
+
+	// control changes the state of the existing task (e.g. approves the plan or retries a subtask)
+	// instead of passing the input, the returned task is run after that in the flow worker
+	control func(ctx context.Context) (TaskWorker, error)
 }
 
 func NewFlowWorker(
@@ -112,19 +141,18 @@ func NewFlowWorker(
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.NewFlowWorker")
 	defer span.End()
 
-	flow, err := fwc.db.CreateFlow(ctx, database.CreateFlowParams{
-		Title:             "untitled",
-		Status:            database.FlowStatusCreated,
-		Model:             "unknown",
-		ModelProviderName: fwc.prvname.String(),
-		ModelProviderType: database.ProviderType(fwc.prvtype),
-		Language:          "English",
-		Functions:         []byte("{}"),
-		UserID:            fwc.userID,
-	})
-	if err != nil {
-		logrus.WithError(err).Error("failed to create flow in DB")
-		return nil, fmt.Errorf("failed to create flow in DB: %w", err)
+	var (
+		flow database.Flow
+		err  error
+	)
+	if fwc.flow != nil {
+		flow = *fwc.flow
+	} else {
+		flow, err = fwc.db.CreateFlow(ctx, newFlowParams(fwc.userID, fwc.prvname, fwc.prvtype, fwc.planReview, database.FlowStatusCreated))
+		if err != nil {
+			logrus.WithError(err).Error("failed to create flow in DB")
+			return nil, fmt.Errorf("failed to create flow in DB: %w", err)
+		}
 	}
 
 	logger := logrus.WithContext(ctx).WithFields(logrus.Fields{
@@ -168,8 +196,12 @@ func NewFlowWorker(
 	if err != nil {
 		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to create flow tools executor", err)
 	}
+	var image string
+	if fwc.playbook != nil {
+		image = fwc.playbook.Image
+	}
 	flowProvider, err := fwc.provs.NewFlowProvider(
-		ctx, fwc.prvname, prompter, executor, flow.ID, fwc.userID, fwc.cfg.AskUser, fwc.input,
+		ctx, fwc.prvname, prompter, executor, flow.ID, fwc.userID, fwc.cfg.AskUser, image, fwc.input,
 	)
 	if err != nil {
 		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to get flow provider", err)
@@ -192,6 +224,22 @@ func NewFlowWorker(
 		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to update flow in DB", err)
 	}
 
+	if fwc.playbook != nil {
+		planBlob, err := json.Marshal(fwc.playbook)
+		if err != nil {
+			return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to marshal playbook", err)
+		}
+
+		_, err = fwc.db.CreateFlowPlaybook(ctx, database.CreateFlowPlaybookParams{
+			FlowID:     flow.ID,
+			PlaybookID: database.Int64ToNullInt64(fwc.playbookID),
+			Plan:       planBlob,
+		})
+		if err != nil {
+			return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to create flow playbook in DB", err)
+		}
+	}
+
 	pub := fwc.subs.NewFlowPublisher(fwc.userID, flow.ID)
 	workers, err := newFlowProviderWorkers(ctx, flow.ID, &fwc.flowProviderControllers, pub)
 	if err != nil {
@@ -212,16 +260,19 @@ func NewFlowWorker(
 	executor.SetGraphitiClient(fwc.provs.GraphitiClient())
 
 	flowCtx := &FlowContext{
-		DB:         fwc.db,
-		UserID:     fwc.userID,
-		FlowID:     flow.ID,
-		FlowTitle:  flowProvider.Title(),
-		Executor:   executor,
-		Provider:   flowProvider,
-		Publisher:  pub,
-		MsgLog:     workers.mlw,
-		TermLog:    workers.tlw,
-		Screenshot: workers.sw,
+		DB:            fwc.db,
+		UserID:        fwc.userID,
+		FlowID:        flow.ID,
+		FlowTitle:     flowProvider.Title(),
+		PlanReview:    flow.PlanReview,
+		ParallelLimit: fwc.cfg.SubtasksParallelLimit,
+		Limits:        providers.NewLimits(fwc.cfg),
+		Executor:      executor,
+		Provider:      flowProvider,
+		Publisher:     pub,
+		MsgLog:        workers.mlw,
+		TermLog:       workers.tlw,
+		Screenshot:    workers.sw,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	ctx, _ = obs.Observer.NewObservation(ctx, langfuse.WithObservationTraceID(observation.TraceID()))
@@ -237,12 +288,15 @@ func NewFlowWorker(
 		taskWG:  &sync.WaitGroup{},
 		input:   make(chan flowInput),
 		flowCtx: flowCtx,
+		hooks:   fwc.hooks,
+		started: time.Now(),
 		logger: logrus.WithFields(logrus.Fields{
 			"flow_id":   flow.ID,
 			"user_id":   fwc.userID,
 			"trace_id":  observation.TraceID(),
 			"component": "worker",
 		}),
+		playbook: fwc.playbook,
 	}
 
 	if err := executor.Prepare(ctx); err != nil {
@@ -254,12 +308,18 @@ func NewFlowWorker(
 		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to get flow containers", err)
 	}
 
-	fw.flowCtx.Publisher.FlowCreated(ctx, flow, containers)
+	// the queued flow is already published when it was put to the queue
+	if fwc.flow != nil {
+		fw.flowCtx.Publisher.FlowUpdated(ctx, flow, containers)
+	} else {
+		fw.flowCtx.Publisher.FlowCreated(ctx, flow, containers)
+	}
 
 	fw.wg.Add(1)
 	go fw.worker()
 
-	if !fwc.dryRun {
+	// playbook tasks are created by the worker itself
+	if !fwc.dryRun && fwc.playbook == nil {
 		if err := fw.PutInput(ctx, fwc.input); err != nil {
 			return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to run flow worker", err)
 		}
@@ -270,6 +330,26 @@ func NewFlowWorker(
 	return fw, nil
 }
 
+func newFlowParams(
+	userID int64,
+	prvname provider.ProviderName,
+	prvtype provider.ProviderType,
+	planReview bool,
+	status database.FlowStatus,
+) database.CreateFlowParams {
+	return database.CreateFlowParams{
+		Title:             "untitled",
+		Status:            status,
+		Model:             "unknown",
+		ModelProviderName: prvname.String(),
+		ModelProviderType: database.ProviderType(prvtype),
+		Language:          "English",
+		Functions:         []byte("{}"),
+		UserID:            userID,
+		PlanReview:        planReview,
+	}
+}
+
 func LoadFlowWorker(ctx context.Context, flow database.Flow, fwc flowWorkerCtx) (FlowWorker, error) {
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.LoadFlowWorker")
 	defer span.End()
@@ -362,16 +442,19 @@ func LoadFlowWorker(ctx context.Context, flow database.Flow, fwc flowWorkerCtx)
 	executor.SetGraphitiClient(fwc.provs.GraphitiClient())
 
 	flowCtx := &FlowContext{
-		DB:         fwc.db,
-		UserID:     flow.UserID,
-		FlowID:     flow.ID,
-		FlowTitle:  flowProvider.Title(),
-		Executor:   executor,
-		Provider:   flowProvider,
-		Publisher:  pub,
-		MsgLog:     workers.mlw,
-		TermLog:    workers.tlw,
-		Screenshot: workers.sw,
+		DB:            fwc.db,
+		UserID:        flow.UserID,
+		FlowID:        flow.ID,
+		FlowTitle:     flowProvider.Title(),
+		PlanReview:    flow.PlanReview,
+		ParallelLimit: fwc.cfg.SubtasksParallelLimit,
+		Limits:        providers.NewLimits(fwc.cfg),
+		Executor:      executor,
+		Provider:      flowProvider,
+		Publisher:     pub,
+		MsgLog:        workers.mlw,
+		TermLog:       workers.tlw,
+		Screenshot:    workers.sw,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	ctx, _ = obs.Observer.NewObservation(ctx, langfuse.WithObservationTraceID(observation.TraceID()))
@@ -387,6 +470,8 @@ func LoadFlowWorker(ctx context.Context, flow database.Flow, fwc flowWorkerCtx)
 		taskWG:  &sync.WaitGroup{},
 		input:   make(chan flowInput),
 		flowCtx: flowCtx,
+		hooks:   fwc.hooks,
+		started: time.Now(),
 		logger: logrus.WithFields(logrus.Fields{
 			"flow_id":   flow.ID,
 			"user_id":   flow.UserID,
@@ -395,6 +480,15 @@ func LoadFlowWorker(ctx context.Context, flow database.Flow, fwc flowWorkerCtx)
 		}),
 	}
 
+	if fpb, err := fwc.db.GetFlowPlaybook(ctx, flow.ID); err == nil {
+		if fw.playbook, err = playbook.Load(fpb.Plan); err != nil {
+			return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to load flow playbook", err)
+		}
+		fw.playbookNext = int(fpb.NextTask)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to get flow playbook", err)
+	}
+
 	if err := executor.Prepare(ctx); err != nil {
 		return nil, wrapErrorEndSpan(ctx, flowSpan, "failed to prepare flow resources", err)
 	}
@@ -564,7 +658,123 @@ func (fw *flowWorker) PutInput(ctx context.Context, input string) error {
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.PutInput")
 	defer span.End()
 
-	flin := flowInput{input: input, done: make(chan error, 1)}
+	return fw.sendInput(ctx, flowInput{input: input, done: make(chan error, 1)})
+}
+
+// PatchTaskPlan edits the subtasks of the task which is waiting for the plan review,
+// operations have the same semantics as the refiner subtask patch
+func (fw *flowWorker) PatchTaskPlan(ctx context.Context, taskID int64, patch tools.SubtaskPatch) error {
+	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.PatchTaskPlan")
+	defer span.End()
+
+	task, err := fw.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return task.PatchPlan(ctx, patch)
+}
+
+// ApproveTaskPlan starts execution of the reviewed task plan in the flow worker
+func (fw *flowWorker) ApproveTaskPlan(ctx context.Context, taskID int64) error {
+	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.ApproveTaskPlan")
+	defer span.End()
+
+	task, err := fw.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if !task.IsPlanReview() {
+		return fmt.Errorf("task %d plan is not waiting for review", taskID)
+	}
+
+	return fw.sendInput(ctx, flowInput{
+		input: "approve task plan",
+		done:  make(chan error, 1),
+		control: func(ctx context.Context) (TaskWorker, error) {
+			return task, task.ApprovePlan(ctx)
+		},
+	})
+}
+
+// RetrySubtask performs the completed subtask again with optional user instructions,
+// the task must be stopped or completed before this call
+func (fw *flowWorker) RetrySubtask(ctx context.Context, taskID, subtaskID int64, instructions string) error {
+	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.RetrySubtask")
+	defer span.End()
+
+	task, err := fw.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if !task.IsCompleted() && !task.IsWaiting() {
+		return fmt.Errorf("task %d is running, stop it before retrying subtask", taskID)
+	}
+
+	return fw.sendInput(ctx, flowInput{
+		input: "retry subtask",
+		done:  make(chan error, 1),
+		control: func(ctx context.Context) (TaskWorker, error) {
+			return task, task.RetrySubtask(ctx, subtaskID, instructions)
+		},
+	})
+}
+
+// SkipSubtask stops the running task if it's needed and continues it from the next subtask
+func (fw *flowWorker) SkipSubtask(ctx context.Context, taskID int64) error {
+	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.SkipSubtask")
+	defer span.End()
+
+	task, err := fw.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if task.IsCompleted() {
+		return fmt.Errorf("task %d has already completed", taskID)
+	}
+
+	if !task.IsWaiting() {
+		if err := fw.Stop(ctx); err != nil {
+			return fmt.Errorf("failed to stop task %d: %w", taskID, err)
+		}
+	}
+
+	return fw.sendInput(ctx, flowInput{
+		input: "skip subtask",
+		done:  make(chan error, 1),
+		control: func(ctx context.Context) (TaskWorker, error) {
+			return task, task.SkipSubtask(ctx)
+		},
+	})
+}
+
+// InsertSubtask adds the new subtask after the current one, the running task picks it up next
+func (fw *flowWorker) InsertSubtask(ctx context.Context, taskID int64, title, description string) error {
+	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.flowWorker.InsertSubtask")
+	defer span.End()
+
+	task, err := fw.getTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	return task.InsertSubtask(ctx, title, description)
+}
+
+func (fw *flowWorker) getTask(ctx context.Context, taskID int64) (TaskWorker, error) {
+	for _, task := range fw.tc.ListTasks(ctx) {
+		if task.GetTaskID() == taskID {
+			return task, nil
+		}
+	}
+
+	return nil, fmt.Errorf("task %d not found in flow %d", taskID, fw.flowCtx.FlowID)
+}
+
+func (fw *flowWorker) sendInput(ctx context.Context, flin flowInput) error {
 	select {
 	case <-fw.ctx.Done():
 		close(flin.done)
@@ -705,6 +915,11 @@ func (fw *flowWorker) worker() {
 		}
 	}
 
+	// perform remaining playbook tasks after creating or loading
+	if fw.continuePlaybook(getLogger) {
+		return
+	}
+
 	// process user input in regular job
 	for flin := range fw.input {
 		if task, err := fw.processInput(flin); err != nil {
@@ -720,10 +935,85 @@ func (fw *flowWorker) worker() {
 		} else {
 			getLogger(flin.input, task).Info("user input processed")
 		}
+
+		if fw.continuePlaybook(getLogger) {
+			return
+		}
+	}
+}
+
+// continuePlaybook performs the remaining playbook tasks and returns true if the flow is stopped
+func (fw *flowWorker) continuePlaybook(getLogger func(input string, task TaskWorker) *logrus.Entry) bool {
+	if err := fw.runPlaybook(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			getLogger("playbook", nil).Info("flow are going to be stopped by user")
+			return true
+		}
+
+		getLogger("playbook", nil).WithError(err).Error("failed to perform playbook task")
+
+		// anyway there need to set flow status to Waiting new user input even an error happened
+		_ = fw.SetStatus(fw.ctx, database.FlowStatusWaiting)
+	}
+
+	return false
+}
+
+// runPlaybook creates and runs the playbook tasks one by one while the previous task is finished
+// successfully, a failed or waiting task passes control to the user until the next input
+func (fw *flowWorker) runPlaybook() error {
+	if fw.playbook == nil {
+		return nil
+	}
+
+	for {
+		pbTask := fw.playbook.GetTask(fw.playbookNext)
+		if pbTask == nil {
+			return nil
+		}
+
+		if tasks := fw.tc.ListTasks(fw.ctx); len(tasks) != 0 {
+			last := tasks[len(tasks)-1]
+			if !last.IsCompleted() {
+				return nil
+			}
+			if status, err := last.GetStatus(fw.ctx); err != nil {
+				return fmt.Errorf("failed to get task %d status: %w", last.GetTaskID(), err)
+			} else if status != database.TaskStatusFinished {
+				return nil
+			}
+		}
+
+		// anyway there need to set flow status to Running to disable user input
+		_ = fw.SetStatus(fw.ctx, database.FlowStatusRunning)
+
+		// move to the next task before creation to avoid infinite retries of the broken task
+		fw.playbookNext++
+		_, err := fw.flowCtx.DB.UpdateFlowPlaybookNextTask(fw.ctx, database.UpdateFlowPlaybookNextTaskParams{
+			NextTask: int32(fw.playbookNext),
+			FlowID:   fw.flowCtx.FlowID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update flow %d playbook: %w", fw.flowCtx.FlowID, err)
+		}
+
+		task, err := fw.tc.CreateTask(fw.ctx, pbTask.Input, pbTask.Subtasks, fw)
+		if err != nil {
+			return fmt.Errorf("failed to create playbook task for flow %d: %w", fw.flowCtx.FlowID, err)
+		}
+
+		spanName := fmt.Sprintf("perform playbook task %d: %s", task.GetTaskID(), task.GetTitle())
+		if err := fw.runTask(spanName, pbTask.Input, task); err != nil {
+			return err
+		}
 	}
 }
 
 func (fw *flowWorker) processInput(flin flowInput) (TaskWorker, error) {
+	if flin.control != nil {
+		return fw.processControl(flin)
+	}
+
 	for _, task := range fw.tc.ListTasks(fw.ctx) {
 		if !task.IsCompleted() && task.IsWaiting() {
 			if err := task.PutInput(fw.ctx, flin.input); err != nil {
@@ -740,7 +1030,7 @@ func (fw *flowWorker) processInput(flin flowInput) (TaskWorker, error) {
 	// anyway there need to set flow status to Running to disable user input
 	_ = fw.SetStatus(fw.ctx, database.FlowStatusRunning)
 
-	if task, err := fw.tc.CreateTask(fw.ctx, flin.input, fw); err != nil {
+	if task, err := fw.tc.CreateTask(fw.ctx, flin.input, nil, fw); err != nil {
 		err = fmt.Errorf("failed to create task for flow %d: %w", fw.flowCtx.FlowID, err)
 		flin.done <- err
 		return nil, err
@@ -751,6 +1041,44 @@ func (fw *flowWorker) processInput(flin flowInput) (TaskWorker, error) {
 	}
 }
 
+func (fw *flowWorker) processControl(flin flowInput) (TaskWorker, error) {
+	task, err := flin.control(fw.ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to %s: %w", flin.input, err)
+		flin.done <- err
+		return task, err
+	}
+
+	flin.done <- nil
+	spanName := fmt.Sprintf("%s and run task %d: %s", flin.input, task.GetTaskID(), task.GetTitle())
+	return task, fw.runTask(spanName, flin.input, task)
+}
+
+// getLimits returns the global watchdog limits with the values which are set for the flow,
+// the flow limits are read on each task run, so the changes are applied to the next run
+func (fw *flowWorker) getLimits(ctx context.Context) providers.Limits {
+	limits := fw.flowCtx.Limits
+
+	fl, err := fw.flowCtx.DB.GetFlowLimits(ctx, fw.flowCtx.FlowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return limits
+	} else if err != nil {
+		fw.logger.WithError(err).Warn("failed to get flow limits, global limits are used")
+		return limits
+	}
+
+	return limits.Override(fl)
+}
+
+// limitReached notifies the webhooks about the subtask which is finished by the watchdog
+func (fw *flowWorker) limitReached(ctx context.Context, taskID, subtaskID int64, reason string) {
+	fw.hooks.Notify(ctx, fw.flowCtx.UserID, fw.flowCtx.FlowID, webhooks.EventBudgetExceeded, webhooks.BudgetExceeded{
+		TaskID:    taskID,
+		SubtaskID: subtaskID,
+		Reason:    reason,
+	})
+}
+
 func (fw *flowWorker) runTask(spanName, input string, task TaskWorker) error {
 	_, observation := obs.Observer.NewObservation(fw.ctx)
 	span := observation.Span(
@@ -764,6 +1092,8 @@ func (fw *flowWorker) runTask(spanName, input string, task TaskWorker) error {
 	fw.taskMX.Lock()
 	fw.taskST()
 	ctx, taskST := context.WithCancel(fw.ctx)
+	ctx = providers.PutFlowWatchdog(ctx, fw.getLimits(ctx), fw.started)
+	ctx = providers.PutLimitHandler(ctx, fw.limitReached)
 	fw.taskST = taskST
 	fw.taskMX.Unlock()
 
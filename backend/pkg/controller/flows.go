@@ -11,9 +11,11 @@ import (
 	"pentagi/pkg/database"
 	"pentagi/pkg/docker"
 	"pentagi/pkg/graph/subscriptions"
+	"pentagi/pkg/playbook"
 	"pentagi/pkg/providers"
 	"pentagi/pkg/providers/provider"
 	"pentagi/pkg/tools"
+	"pentagi/pkg/webhooks"
 
 	"github.com/sirupsen/logrus"
 )
@@ -31,6 +33,28 @@ type FlowController interface {
 		prvname provider.ProviderName,
 		prvtype provider.ProviderType,
 		functions *tools.Functions,
+		planReview bool,
+		priority database.FlowPriority,
+		limits *database.FlowLimit,
+	) (database.Flow, error)
+	CreateFlowFromPlaybook(
+		ctx context.Context,
+		userID int64,
+		playbookID *int64,
+		pb *playbook.Playbook,
+		prvname provider.ProviderName,
+		prvtype provider.ProviderType,
+		planReview bool,
+		priority database.FlowPriority,
+		limits *database.FlowLimit,
+	) (database.Flow, error)
+	ForkFlow(
+		ctx context.Context,
+		userID int64,
+		flowID int64,
+		subtaskID int64,
+		prvname provider.ProviderName,
+		prvtype provider.ProviderType,
 	) (FlowWorker, error)
 	CreateAssistant(
 		ctx context.Context,
@@ -54,9 +78,11 @@ type flowController struct {
 	mx     *sync.Mutex
 	cfg    *config.Config
 	flows  map[int64]FlowWorker
+	queue  chan struct{}
 	docker docker.DockerClient
 	provs  providers.ProviderController
 	subs   subscriptions.SubscriptionsController
+	hooks  webhooks.Notifier
 	alc    AgentLogController
 	mlc    MsgLogController
 	aslc   AssistantLogController
@@ -72,15 +98,18 @@ func NewFlowController(
 	docker docker.DockerClient,
 	provs providers.ProviderController,
 	subs subscriptions.SubscriptionsController,
+	hooks webhooks.Notifier,
 ) FlowController {
 	return &flowController{
 		db:     db,
 		mx:     &sync.Mutex{},
 		cfg:    cfg,
 		flows:  make(map[int64]FlowWorker),
+		queue:  make(chan struct{}, 1),
 		docker: docker,
 		provs:  provs,
-		subs:   subs,
+		subs:   webhooks.WrapSubscriptions(subs, hooks),
+		hooks:  hooks,
 		alc:    NewAgentLogController(db),
 		mlc:    NewMsgLogController(db),
 		aslc:   NewAssistantLogController(db),
@@ -91,7 +120,17 @@ func NewFlowController(
 	}
 }
 
+// LoadFlows loads workers of running and waiting flows and starts the flow queue, flows which were
+// admitted but not started before the restart are returned to the queue
 func (fc *flowController) LoadFlows(ctx context.Context) error {
+	requeued, err := fc.db.RequeueFlows(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to requeue flows: %w", err)
+	}
+	for _, flow := range requeued {
+		logrus.WithContext(ctx).WithField("flow_id", flow.ID).Info("flow is returned to the queue")
+	}
+
 	flows, err := fc.db.GetFlows(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load flows: %w", err)
@@ -104,6 +143,7 @@ func (fc *flowController) LoadFlows(ctx context.Context) error {
 			docker: fc.docker,
 			provs:  fc.provs,
 			subs:   fc.subs,
+			hooks:  fc.hooks,
 			flowProviderControllers: flowProviderControllers{
 				mlc:  fc.mlc,
 				aslc: fc.aslc,
@@ -126,9 +166,12 @@ func (fc *flowController) LoadFlows(ctx context.Context) error {
 		fc.flows[flow.ID] = fw
 	}
 
+	go fc.runQueue(context.Background())
+
 	return nil
 }
 
+// CreateFlow puts the new flow to the queue, the flow worker is started when the flow is admitted
 func (fc *flowController) CreateFlow(
 	ctx context.Context,
 	userID int64,
@@ -136,22 +179,76 @@ func (fc *flowController) CreateFlow(
 	prvname provider.ProviderName,
 	prvtype provider.ProviderType,
 	functions *tools.Functions,
+	planReview bool,
+	priority database.FlowPriority,
+	limits *database.FlowLimit,
+) (database.Flow, error) {
+	return fc.enqueueFlow(ctx, newFlowWorkerCtx{
+		userID:     userID,
+		input:      input,
+		prvname:    prvname,
+		prvtype:    prvtype,
+		functions:  functions,
+		planReview: planReview,
+	}, priority, limits)
+}
+
+// CreateFlowFromPlaybook queues the flow from the rendered playbook: the playbook tasks are performed
+// one by one with predefined subtasks, the tool allow-list and the preferred image
+func (fc *flowController) CreateFlowFromPlaybook(
+	ctx context.Context,
+	userID int64,
+	playbookID *int64,
+	pb *playbook.Playbook,
+	prvname provider.ProviderName,
+	prvtype provider.ProviderType,
+	planReview bool,
+	priority database.FlowPriority,
+	limits *database.FlowLimit,
+) (database.Flow, error) {
+	task := pb.GetTask(0)
+	if task == nil {
+		return database.Flow{}, fmt.Errorf("playbook '%s' has no tasks", pb.Name)
+	}
+
+	return fc.enqueueFlow(ctx, newFlowWorkerCtx{
+		userID:     userID,
+		input:      task.Input,
+		prvname:    prvname,
+		prvtype:    prvtype,
+		functions:  pb.Functions(),
+		planReview: planReview,
+		playbook:   pb,
+		playbookID: playbookID,
+	}, priority, limits)
+}
+
+// ForkFlow creates the new waiting flow from the source flow state right after the completed subtask,
+// the forked flow is continued by the user input and can use another provider
+func (fc *flowController) ForkFlow(
+	ctx context.Context,
+	userID int64,
+	flowID int64,
+	subtaskID int64,
+	prvname provider.ProviderName,
+	prvtype provider.ProviderType,
 ) (FlowWorker, error) {
 	fc.mx.Lock()
 	defer fc.mx.Unlock()
 
-	fw, err := NewFlowWorker(ctx, newFlowWorkerCtx{
+	fw, err := ForkFlowWorker(ctx, forkFlowWorkerCtx{
 		userID:    userID,
-		input:     input,
+		flowID:    flowID,
+		subtaskID: subtaskID,
 		prvname:   prvname,
 		prvtype:   prvtype,
-		functions: functions,
 		flowWorkerCtx: flowWorkerCtx{
 			db:     fc.db,
 			cfg:    fc.cfg,
 			docker: fc.docker,
 			provs:  fc.provs,
 			subs:   fc.subs,
+			hooks:  fc.hooks,
 			flowProviderControllers: flowProviderControllers{
 				mlc:  fc.mlc,
 				aslc: fc.aslc,
@@ -164,7 +261,7 @@ func (fc *flowController) CreateFlow(
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create flow worker: %w", err)
+		return nil, fmt.Errorf("failed to fork flow worker: %w", err)
 	}
 
 	fc.flows[fw.GetFlowID()] = fw
@@ -197,6 +294,7 @@ func (fc *flowController) CreateAssistant(
 		docker: fc.docker,
 		provs:  fc.provs,
 		subs:   fc.subs,
+		hooks:  fc.hooks,
 		flowProviderControllers: flowProviderControllers{
 			mlc:  fc.mlc,
 			aslc: fc.aslc,
@@ -335,7 +433,7 @@ func (fc *flowController) StopFlow(ctx context.Context, flowID int64) error {
 
 	flow, ok := fc.flows[flowID]
 	if !ok {
-		return ErrFlowNotFound
+		return fc.cancelQueuedFlow(ctx, flowID)
 	}
 
 	err := flow.Stop(ctx)
@@ -352,7 +450,7 @@ func (fc *flowController) FinishFlow(ctx context.Context, flowID int64) error {
 
 	flow, ok := fc.flows[flowID]
 	if !ok {
-		return ErrFlowNotFound
+		return fc.cancelQueuedFlow(ctx, flowID)
 	}
 
 	err := flow.Finish(ctx)
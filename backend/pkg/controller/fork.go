@@ -0,0 +1,399 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/docker"
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/tools"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/sirupsen/logrus"
+)
+
+const forkedTaskResult = "The task was forked after the subtask \"%s\", the rest of its subtasks were not performed"
+
+type forkFlowWorkerCtx struct {
+	userID    int64
+	flowID    int64
+	subtaskID int64
+	prvname   provider.ProviderName
+	prvtype   provider.ProviderType
+
+	flowWorkerCtx
+}
+
+// forkIDsMap keeps pairs of the source and the copied record IDs in the form of parallel arrays
+// which are unnested by the copy queries to remap task_id and subtask_id references
+type forkIDsMap struct {
+	src []int64
+	dst []int64
+}
+
+func (m *forkIDsMap) add(src, dst int64) {
+	m.src = append(m.src, src)
+	m.dst = append(m.dst, dst)
+}
+
+func (m *forkIDsMap) get(src int64) (int64, bool) {
+	if idx := slices.Index(m.src, src); idx != -1 {
+		return m.dst[idx], true
+	}
+	return 0, false
+}
+
+// ForkFlowWorker creates a new flow from the state of the source flow right after the completed subtask:
+// tasks, subtasks, message chains and logs are copied up to that point, the primary container is
+// committed to the image and its work folder is copied into the new flow container
+func ForkFlowWorker(ctx context.Context, fwc forkFlowWorkerCtx) (FlowWorker, error) {
+	logger := logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"src_flow_id":   fwc.flowID,
+		"subtask_id":    fwc.subtaskID,
+		"user_id":       fwc.userID,
+		"provider_name": fwc.prvname.String(),
+		"provider_type": fwc.prvtype.String(),
+	})
+
+	src, err := fwc.db.GetFlow(ctx, fwc.flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow %d: %w", fwc.flowID, err)
+	}
+
+	point, err := fwc.db.GetFlowSubtask(ctx, database.GetFlowSubtaskParams{
+		ID:     fwc.subtaskID,
+		FlowID: fwc.flowID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtask %d: %w", fwc.subtaskID, err)
+	}
+
+	switch point.Status {
+	case database.SubtaskStatusFinished, database.SubtaskStatusFailed:
+	default:
+		return nil, fmt.Errorf("subtask %d has status %s: only completed subtasks can be a fork point",
+			point.ID, point.Status)
+	}
+
+	tasks, err := fwc.db.GetFlowTasks(ctx, fwc.flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow %d tasks: %w", fwc.flowID, err)
+	}
+
+	prv, err := fwc.provs.GetProvider(ctx, fwc.prvname, fwc.userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	flow, err := fwc.db.CreateFlow(ctx, database.CreateFlowParams{
+		Title:             fmt.Sprintf("%s (fork)", src.Title),
+		Status:            database.FlowStatusWaiting,
+		Model:             prv.Model(pconfig.OptionsTypePrimaryAgent),
+		ModelProviderName: fwc.prvname.String(),
+		ModelProviderType: database.ProviderType(fwc.prvtype),
+		Language:          src.Language,
+		Functions:         src.Functions,
+		UserID:            fwc.userID,
+		PlanReview:        src.PlanReview,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flow in DB: %w", err)
+	}
+
+	logger = logger.WithField("flow_id", flow.ID)
+	logger.Info("forked flow created in DB")
+
+	fw, err := forkFlowState(ctx, fwc, src, flow, point, tasks)
+	if err != nil {
+		logger.WithError(err).Error("failed to fork flow")
+		if _, err := fwc.db.UpdateFlowStatus(ctx, database.UpdateFlowStatusParams{
+			Status: database.FlowStatusFailed,
+			ID:     flow.ID,
+		}); err != nil {
+			logger.WithError(err).Error("failed to set forked flow status to failed")
+		}
+		return nil, err
+	}
+
+	return fw, nil
+}
+
+func forkFlowState(
+	ctx context.Context,
+	fwc forkFlowWorkerCtx,
+	src, flow database.Flow,
+	point database.Subtask,
+	tasks []database.Task,
+) (FlowWorker, error) {
+	until, err := forkFlowRecords(ctx, fwc.db, src, flow, point, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := forkFlowContainer(ctx, fwc, src.ID, flow.ID, until); err != nil {
+		return nil, err
+	}
+
+	// the fork keeps the watchdog limits of the source flow
+	if limits, err := fwc.db.GetFlowLimits(ctx, src.ID); err == nil {
+		_, err = fwc.db.UpsertFlowLimits(ctx, database.UpsertFlowLimitsParams{
+			FlowID:             flow.ID,
+			FlowTimeLimit:      limits.FlowTimeLimit,
+			TaskTimeLimit:      limits.TaskTimeLimit,
+			SubtaskTimeLimit:   limits.SubtaskTimeLimit,
+			MaxIterations:      limits.MaxIterations,
+			MaxToolCalls:       limits.MaxToolCalls,
+			MaxDelegationDepth: limits.MaxDelegationDepth,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy flow %d limits: %w", src.ID, err)
+		}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get flow %d limits: %w", src.ID, err)
+	}
+
+	fw, err := LoadFlowWorker(ctx, flow, fwc.flowWorkerCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load forked flow worker: %w", err)
+	}
+
+	containers, err := fwc.db.GetFlowContainers(ctx, flow.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flow containers: %w", err)
+	}
+
+	fw.GetContext().Publisher.FlowCreated(ctx, flow, containers)
+
+	return fw, nil
+}
+
+// forkFlowRecords copies tasks and subtasks of the source flow which were completed up to the fork point
+// into the new flow together with their message chains and logs, it returns the fork point time
+func forkFlowRecords(
+	ctx context.Context,
+	db database.Querier,
+	src, flow database.Flow,
+	point database.Subtask,
+	tasks []database.Task,
+) (time.Time, error) {
+	var taskIDs, subtaskIDs forkIDsMap
+	for _, task := range tasks {
+		if task.ID > point.TaskID {
+			continue
+		}
+
+		subtasks, err := db.GetFlowTaskSubtasks(ctx, database.GetFlowTaskSubtasksParams{
+			TaskID: task.ID,
+			FlowID: src.ID,
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to get task %d subtasks: %w", task.ID, err)
+		}
+
+		// only completed subtasks are the part of the flow history,
+		// the fork task keeps the subtasks which were completed up to the fork point
+		total := len(subtasks)
+		subtasks = slices.DeleteFunc(subtasks, func(st database.Subtask) bool {
+			switch st.Status {
+			case database.SubtaskStatusFinished, database.SubtaskStatusFailed:
+				return task.ID == point.TaskID && st.ID > point.ID
+			default:
+				return true
+			}
+		})
+
+		status, result := task.Status, task.Result
+		if task.ID == point.TaskID && (status != database.TaskStatusFinished || len(subtasks) != total) {
+			status, result = database.TaskStatusFinished, fmt.Sprintf(forkedTaskResult, point.Title)
+		}
+
+		copied, err := db.CopyTask(ctx, database.CopyTaskParams{
+			Status:    status,
+			Title:     task.Title,
+			Input:     task.Input,
+			Result:    result,
+			FlowID:    flow.ID,
+			CreatedAt: task.CreatedAt,
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to copy task %d: %w", task.ID, err)
+		}
+		taskIDs.add(task.ID, copied.ID)
+
+		for _, subtask := range subtasks {
+			dependsOn := make([]int64, 0, len(subtask.DependsOn))
+			for _, id := range subtask.DependsOn {
+				if dst, ok := subtaskIDs.get(id); ok {
+					dependsOn = append(dependsOn, dst)
+				}
+			}
+
+			copied, err := db.CopySubtask(ctx, database.CopySubtaskParams{
+				Status:      subtask.Status,
+				Title:       subtask.Title,
+				Description: subtask.Description,
+				Result:      subtask.Result,
+				Context:     subtask.Context,
+				DependsOn:   dependsOn,
+				TaskID:      taskIDs.dst[len(taskIDs.dst)-1],
+				CreatedAt:   subtask.CreatedAt,
+			})
+			if err != nil {
+				return time.Time{}, fmt.Errorf("failed to copy subtask %d: %w", subtask.ID, err)
+			}
+			subtaskIDs.add(subtask.ID, copied.ID)
+		}
+	}
+
+	until := time.Now()
+	if point.UpdatedAt.Valid {
+		until = point.UpdatedAt.Time
+	}
+
+	if err := forkFlowLogs(ctx, db, src.ID, flow.ID, &taskIDs, &subtaskIDs, until); err != nil {
+		return time.Time{}, err
+	}
+
+	return until, nil
+}
+
+func forkFlowLogs(
+	ctx context.Context,
+	db database.Querier,
+	srcFlowID, dstFlowID int64,
+	taskIDs, subtaskIDs *forkIDsMap,
+	until time.Time,
+) error {
+	copyLogs := []struct {
+		name string
+		copy func() error
+	}{
+		{"message chains", func() error {
+			return db.CopyFlowMsgChains(ctx, database.CopyFlowMsgChainsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+		{"message logs", func() error {
+			return db.CopyFlowMsgLogs(ctx, database.CopyFlowMsgLogsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+		{"agent logs", func() error {
+			return db.CopyFlowAgentLogs(ctx, database.CopyFlowAgentLogsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+		{"search logs", func() error {
+			return db.CopyFlowSearchLogs(ctx, database.CopyFlowSearchLogsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+		{"vector store logs", func() error {
+			return db.CopyFlowVectorStoreLogs(ctx, database.CopyFlowVectorStoreLogsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+		{"tool calls", func() error {
+			return db.CopyFlowToolcalls(ctx, database.CopyFlowToolcallsParams{
+				DstFlowID: dstFlowID, SrcFlowID: srcFlowID, Until: until,
+				SrcTaskIds: taskIDs.src, DstTaskIds: taskIDs.dst,
+				SrcSubtaskIds: subtaskIDs.src, DstSubtaskIds: subtaskIDs.dst,
+			})
+		}},
+	}
+
+	for _, cl := range copyLogs {
+		if err := cl.copy(); err != nil {
+			return fmt.Errorf("failed to copy flow %s: %w", cl.name, err)
+		}
+	}
+
+	return nil
+}
+
+// forkFlowContainer spawns the primary container of the forked flow from the committed image
+// of the source container and copies the work folder; if the source container is gone already
+// the new container is started from the source image with the empty work folder
+func forkFlowContainer(ctx context.Context, fwc forkFlowWorkerCtx, srcFlowID, dstFlowID int64, until time.Time) error {
+	logger := logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"src_flow_id": srcFlowID,
+		"flow_id":     dstFlowID,
+	})
+
+	src, err := fwc.db.GetFlowPrimaryContainer(ctx, srcFlowID)
+	if err != nil {
+		return fmt.Errorf("failed to get flow %d primary container: %w", srcFlowID, err)
+	}
+
+	image, cloned := src.Image, false
+	switch src.Status {
+	case database.ContainerStatusRunning, database.ContainerStatusStopped:
+		forkImage := fmt.Sprintf("pentagi-fork:flow-%d", dstFlowID)
+		if err := fwc.docker.CommitContainer(ctx, src.LocalID.String, forkImage); err != nil {
+			logger.WithError(err).Warn("failed to commit source container, using the source image")
+		} else {
+			image, cloned = forkImage, true
+		}
+	}
+
+	dst, err := tools.SpawnPrimaryContainer(ctx, fwc.docker, fwc.cfg, dstFlowID, image)
+	if err != nil {
+		return fmt.Errorf("failed to spawn forked flow container: %w", err)
+	}
+
+	if cloned {
+		if err := copyWorkFolder(ctx, fwc.docker, src.LocalID.String, dst.LocalID.String); err != nil {
+			logger.WithError(err).Warn("failed to copy work folder to the forked flow container")
+		}
+	}
+
+	// the image name is used in the agents prompts so it keeps the source one
+	if _, err := fwc.db.UpdateContainerImage(ctx, database.UpdateContainerImageParams{
+		Image: src.Image,
+		ID:    dst.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to update forked flow container image: %w", err)
+	}
+
+	if err := fwc.db.CopyContainerTermLogs(ctx, database.CopyContainerTermLogsParams{
+		DstContainerID: dst.ID,
+		SrcContainerID: src.ID,
+		Until:          until,
+	}); err != nil {
+		return fmt.Errorf("failed to copy flow terminal logs: %w", err)
+	}
+
+	return nil
+}
+
+func copyWorkFolder(ctx context.Context, dockerClient docker.DockerClient, srcID, dstID string) error {
+	archive, _, err := dockerClient.CopyFromContainer(ctx, srcID, docker.WorkFolderPathInContainer)
+	if err != nil {
+		return fmt.Errorf("failed to copy work folder from container: %w", err)
+	}
+	defer archive.Close()
+
+	// the archive root is the work folder itself so it's extracted to the parent directory
+	err = dockerClient.CopyToContainer(ctx, dstID, "/", archive, container.CopyToContainerOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to copy work folder to container: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,403 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/providers"
+	"pentagi/pkg/providers/mock"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	forkSrcFlowID  = 1
+	forkSrcOwnerID = 5
+	forkCallerID   = 7
+)
+
+var forkBaseTime = time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func forkTime(offset time.Duration) sql.NullTime {
+	return sql.NullTime{Time: forkBaseTime.Add(offset), Valid: true}
+}
+
+func forkNullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// forkQuerier keeps flows, tasks, subtasks and message chains in memory and emulates the copy queries,
+// the other flow logs are copied by the same queries shape so they are not emulated
+type forkQuerier struct {
+	database.Querier
+	flows     []database.Flow
+	tasks     []database.Task
+	subtasks  []database.Subtask
+	msgchains []database.Msgchain
+	lastID    int64
+}
+
+// newForkQuerier returns the source flow with two tasks done and the third one not started,
+// the fork point is the subtask 22 of the second task which has more subtasks after it
+func newForkQuerier() *forkQuerier {
+	return &forkQuerier{
+		flows: []database.Flow{
+			{ID: forkSrcFlowID, Title: "scan", Status: database.FlowStatusWaiting, UserID: forkSrcOwnerID, Language: "English"},
+		},
+		tasks: []database.Task{
+			{ID: 10, Status: database.TaskStatusFinished, Title: "recon", Result: "done", FlowID: forkSrcFlowID, CreatedAt: forkTime(0)},
+			{ID: 20, Status: database.TaskStatusFailed, Title: "exploit", Result: "failed", FlowID: forkSrcFlowID, CreatedAt: forkTime(time.Hour)},
+			{ID: 30, Status: database.TaskStatusCreated, Title: "report", FlowID: forkSrcFlowID, CreatedAt: forkTime(5 * time.Hour)},
+		},
+		subtasks: []database.Subtask{
+			{ID: 11, Status: database.SubtaskStatusFinished, Title: "ports", TaskID: 10, UpdatedAt: forkTime(10 * time.Minute)},
+			{ID: 12, Status: database.SubtaskStatusFinished, Title: "services", TaskID: 10, DependsOn: []int64{11}, UpdatedAt: forkTime(20 * time.Minute)},
+			{ID: 21, Status: database.SubtaskStatusFinished, Title: "payload", TaskID: 20, UpdatedAt: forkTime(2 * time.Hour)},
+			{ID: 22, Status: database.SubtaskStatusFinished, Title: "deliver", TaskID: 20, DependsOn: []int64{21}, UpdatedAt: forkTime(3 * time.Hour)},
+			{ID: 23, Status: database.SubtaskStatusFailed, Title: "escalate", TaskID: 20, DependsOn: []int64{22}, UpdatedAt: forkTime(4 * time.Hour)},
+			{ID: 24, Status: database.SubtaskStatusCreated, Title: "persist", TaskID: 20},
+			{ID: 31, Status: database.SubtaskStatusCreated, Title: "write", TaskID: 30},
+		},
+		msgchains: []database.Msgchain{
+			{ID: 1, Type: database.MsgchainTypePrimaryAgent, FlowID: forkSrcFlowID, CreatedAt: forkTime(0)},
+			{ID: 2, Type: database.MsgchainTypePentester, FlowID: forkSrcFlowID, TaskID: forkNullID(10), SubtaskID: forkNullID(11), CreatedAt: forkTime(5 * time.Minute)},
+			{ID: 3, Type: database.MsgchainTypeCoder, FlowID: forkSrcFlowID, TaskID: forkNullID(20), SubtaskID: forkNullID(22), CreatedAt: forkTime(150 * time.Minute)},
+			{ID: 4, Type: database.MsgchainTypePentester, FlowID: forkSrcFlowID, TaskID: forkNullID(20), SubtaskID: forkNullID(23), CreatedAt: forkTime(200 * time.Minute)},
+			{ID: 5, Type: database.MsgchainTypeAssistant, FlowID: forkSrcFlowID, CreatedAt: forkTime(time.Minute)},
+			{ID: 6, Type: database.MsgchainTypePrimaryAgent, FlowID: 2, CreatedAt: forkTime(0)},
+		},
+		lastID: 100,
+	}
+}
+
+func (q *forkQuerier) nextID() int64 {
+	q.lastID++
+	return q.lastID
+}
+
+func (q *forkQuerier) GetFlow(_ context.Context, id int64) (database.Flow, error) {
+	for _, flow := range q.flows {
+		if flow.ID == id {
+			return flow, nil
+		}
+	}
+	return database.Flow{}, sql.ErrNoRows
+}
+
+func (q *forkQuerier) GetFlowSubtask(_ context.Context, arg database.GetFlowSubtaskParams) (database.Subtask, error) {
+	for _, subtask := range q.subtasks {
+		if subtask.ID != arg.ID {
+			continue
+		}
+		if idx := slices.IndexFunc(q.tasks, func(task database.Task) bool {
+			return task.ID == subtask.TaskID && task.FlowID == arg.FlowID
+		}); idx != -1 {
+			return subtask, nil
+		}
+	}
+	return database.Subtask{}, sql.ErrNoRows
+}
+
+func (q *forkQuerier) GetFlowTasks(_ context.Context, flowID int64) ([]database.Task, error) {
+	var tasks []database.Task
+	for _, task := range q.tasks {
+		if task.FlowID == flowID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (q *forkQuerier) GetFlowTaskSubtasks(
+	_ context.Context,
+	arg database.GetFlowTaskSubtasksParams,
+) ([]database.Subtask, error) {
+	var subtasks []database.Subtask
+	for _, subtask := range q.subtasks {
+		if subtask.TaskID == arg.TaskID {
+			subtasks = append(subtasks, subtask)
+		}
+	}
+	return subtasks, nil
+}
+
+func (q *forkQuerier) CreateFlow(_ context.Context, arg database.CreateFlowParams) (database.Flow, error) {
+	flow := database.Flow{
+		ID:                q.nextID(),
+		Title:             arg.Title,
+		Status:            arg.Status,
+		Model:             arg.Model,
+		ModelProviderName: arg.ModelProviderName,
+		ModelProviderType: arg.ModelProviderType,
+		Language:          arg.Language,
+		Functions:         arg.Functions,
+		UserID:            arg.UserID,
+		PlanReview:        arg.PlanReview,
+	}
+	q.flows = append(q.flows, flow)
+	return flow, nil
+}
+
+func (q *forkQuerier) UpdateFlowStatus(_ context.Context, arg database.UpdateFlowStatusParams) (database.Flow, error) {
+	for idx := range q.flows {
+		if q.flows[idx].ID == arg.ID {
+			q.flows[idx].Status = arg.Status
+			return q.flows[idx], nil
+		}
+	}
+	return database.Flow{}, sql.ErrNoRows
+}
+
+func (q *forkQuerier) CopyTask(_ context.Context, arg database.CopyTaskParams) (database.Task, error) {
+	task := database.Task{
+		ID:        q.nextID(),
+		Status:    arg.Status,
+		Title:     arg.Title,
+		Input:     arg.Input,
+		Result:    arg.Result,
+		FlowID:    arg.FlowID,
+		CreatedAt: arg.CreatedAt,
+	}
+	q.tasks = append(q.tasks, task)
+	return task, nil
+}
+
+func (q *forkQuerier) CopySubtask(_ context.Context, arg database.CopySubtaskParams) (database.Subtask, error) {
+	subtask := database.Subtask{
+		ID:          q.nextID(),
+		Status:      arg.Status,
+		Title:       arg.Title,
+		Description: arg.Description,
+		Result:      arg.Result,
+		Context:     arg.Context,
+		DependsOn:   arg.DependsOn,
+		TaskID:      arg.TaskID,
+		CreatedAt:   arg.CreatedAt,
+	}
+	q.subtasks = append(q.subtasks, subtask)
+	return subtask, nil
+}
+
+// CopyFlowMsgChains remaps references like the unnested IDs arrays join of the query does
+func (q *forkQuerier) CopyFlowMsgChains(_ context.Context, arg database.CopyFlowMsgChainsParams) error {
+	remap := func(id sql.NullInt64, src, dst []int64) (sql.NullInt64, bool) {
+		if !id.Valid {
+			return id, true
+		}
+		if idx := slices.Index(src, id.Int64); idx != -1 {
+			return forkNullID(dst[idx]), true
+		}
+		return id, false
+	}
+
+	for _, chain := range slices.Clone(q.msgchains) {
+		if chain.FlowID != arg.SrcFlowID || chain.Type == database.MsgchainTypeAssistant ||
+			chain.CreatedAt.Time.After(arg.Until) {
+			continue
+		}
+
+		taskID, ok := remap(chain.TaskID, arg.SrcTaskIds, arg.DstTaskIds)
+		if !ok {
+			continue
+		}
+		subtaskID, ok := remap(chain.SubtaskID, arg.SrcSubtaskIds, arg.DstSubtaskIds)
+		if !ok {
+			continue
+		}
+
+		chain.ID, chain.FlowID = q.nextID(), arg.DstFlowID
+		chain.TaskID, chain.SubtaskID = taskID, subtaskID
+		q.msgchains = append(q.msgchains, chain)
+	}
+
+	return nil
+}
+
+func (q *forkQuerier) CopyFlowMsgLogs(context.Context, database.CopyFlowMsgLogsParams) error {
+	return nil
+}
+
+func (q *forkQuerier) CopyFlowAgentLogs(context.Context, database.CopyFlowAgentLogsParams) error {
+	return nil
+}
+
+func (q *forkQuerier) CopyFlowSearchLogs(context.Context, database.CopyFlowSearchLogsParams) error {
+	return nil
+}
+
+func (q *forkQuerier) CopyFlowVectorStoreLogs(context.Context, database.CopyFlowVectorStoreLogsParams) error {
+	return nil
+}
+
+func (q *forkQuerier) CopyFlowToolcalls(context.Context, database.CopyFlowToolcallsParams) error {
+	return nil
+}
+
+func (q *forkQuerier) GetFlowPrimaryContainer(_ context.Context, flowID int64) (database.Container, error) {
+	return database.Container{}, sql.ErrNoRows
+}
+
+type forkSnapshot struct {
+	tasks     []database.Task
+	subtasks  []database.Subtask
+	msgchains []database.Msgchain
+}
+
+// snapshot returns the records of the flow, subtasks are taken by the flow tasks
+func (q *forkQuerier) snapshot(flowID int64) forkSnapshot {
+	var s forkSnapshot
+	for _, task := range q.tasks {
+		if task.FlowID != flowID {
+			continue
+		}
+		s.tasks = append(s.tasks, task)
+		for _, subtask := range q.subtasks {
+			if subtask.TaskID == task.ID {
+				s.subtasks = append(s.subtasks, subtask)
+			}
+		}
+	}
+	for _, chain := range q.msgchains {
+		if chain.FlowID == flowID {
+			s.msgchains = append(s.msgchains, chain)
+		}
+	}
+	return s
+}
+
+type forkProviders struct {
+	providers.ProviderController
+	prv provider.Provider
+}
+
+func (p *forkProviders) GetProvider(context.Context, provider.ProviderName, int64) (provider.Provider, error) {
+	return p.prv, nil
+}
+
+func newForkFlowWorkerCtx(t *testing.T, db database.Querier, subtaskID int64) forkFlowWorkerCtx {
+	t.Helper()
+
+	cfg, err := mock.DefaultProviderConfig()
+	require.NoError(t, err)
+	prv, err := mock.New(&mock.Script{}, cfg)
+	require.NoError(t, err)
+
+	return forkFlowWorkerCtx{
+		userID:    forkCallerID,
+		flowID:    forkSrcFlowID,
+		subtaskID: subtaskID,
+		prvname:   provider.ProviderName("mock"),
+		prvtype:   provider.ProviderMock,
+		flowWorkerCtx: flowWorkerCtx{
+			db:    db,
+			provs: &forkProviders{prv: prv},
+		},
+	}
+}
+
+func TestForkFlowRecords(t *testing.T) {
+	db := newForkQuerier()
+	before := db.snapshot(forkSrcFlowID)
+
+	src := db.flows[0]
+	point, err := db.GetFlowSubtask(context.Background(), database.GetFlowSubtaskParams{ID: 22, FlowID: src.ID})
+	require.NoError(t, err)
+	fork, err := db.CreateFlow(context.Background(), database.CreateFlowParams{Title: "scan (fork)", UserID: forkCallerID})
+	require.NoError(t, err)
+
+	until, err := forkFlowRecords(context.Background(), db, src, fork, point, db.tasks)
+	require.NoError(t, err)
+	assert.Equal(t, point.UpdatedAt.Time, until)
+
+	// the source flow records are left untouched
+	assert.Equal(t, before, db.snapshot(forkSrcFlowID))
+
+	// IDs are taken from the sequence in the copy order: task 10, subtasks 11, 12, task 20, subtasks 21, 22
+	copied := db.snapshot(fork.ID)
+	assert.Equal(t, []database.Task{
+		{ID: 102, Status: database.TaskStatusFinished, Title: "recon", Result: "done", FlowID: fork.ID, CreatedAt: forkTime(0)},
+		{ID: 105, Status: database.TaskStatusFinished, Title: "exploit",
+			Result: fmt.Sprintf(forkedTaskResult, "deliver"), FlowID: fork.ID, CreatedAt: forkTime(time.Hour)},
+	}, copied.tasks)
+	assert.Equal(t, []database.Subtask{
+		{ID: 103, Status: database.SubtaskStatusFinished, Title: "ports", TaskID: 102, DependsOn: []int64{}},
+		{ID: 104, Status: database.SubtaskStatusFinished, Title: "services", TaskID: 102, DependsOn: []int64{103}},
+		{ID: 106, Status: database.SubtaskStatusFinished, Title: "payload", TaskID: 105, DependsOn: []int64{}},
+		{ID: 107, Status: database.SubtaskStatusFinished, Title: "deliver", TaskID: 105, DependsOn: []int64{106}},
+	}, copied.subtasks)
+
+	// messages after the fork point, of the skipped subtasks and of the assistants are not copied
+	require.Len(t, copied.msgchains, 3)
+	for idx, want := range []struct {
+		srcID, taskID, subtaskID int64
+	}{
+		{srcID: 1},
+		{srcID: 2, taskID: 102, subtaskID: 103},
+		{srcID: 3, taskID: 105, subtaskID: 107},
+	} {
+		chain := copied.msgchains[idx]
+		assert.Greater(t, chain.ID, int64(107))
+		assert.Equal(t, before.msgchains[want.srcID-1].Type, chain.Type)
+		assert.Equal(t, forkNullID(want.taskID), chain.TaskID)
+		assert.Equal(t, forkNullID(want.subtaskID), chain.SubtaskID)
+	}
+}
+
+func TestForkFlowRecordsFinishedTask(t *testing.T) {
+	db := newForkQuerier()
+	src := db.flows[0]
+
+	// the fork point is the last subtask of the finished task, so the task keeps its result
+	point := db.subtasks[1]
+	fork := database.Flow{ID: 2}
+	_, err := forkFlowRecords(context.Background(), db, src, fork, point, db.tasks)
+	require.NoError(t, err)
+
+	copied := db.snapshot(fork.ID)
+	require.Len(t, copied.tasks, 1)
+	assert.Equal(t, database.TaskStatusFinished, copied.tasks[0].Status)
+	assert.Equal(t, "done", copied.tasks[0].Result)
+	assert.Len(t, copied.subtasks, 2)
+}
+
+func TestForkFlowWorkerOwner(t *testing.T) {
+	db := newForkQuerier()
+	before := db.snapshot(forkSrcFlowID)
+
+	// the primary container of the source flow is gone, so the fork stops after the records copy
+	_, err := ForkFlowWorker(context.Background(), newForkFlowWorkerCtx(t, db, 22))
+	require.ErrorContains(t, err, "primary container")
+
+	require.Len(t, db.flows, 2)
+	fork := db.flows[1]
+	assert.NotEqual(t, int64(forkSrcFlowID), fork.ID)
+	assert.Equal(t, int64(forkCallerID), fork.UserID)
+	assert.Equal(t, "scan (fork)", fork.Title)
+	assert.Equal(t, "English", fork.Language)
+	assert.Equal(t, database.FlowStatusFailed, fork.Status)
+	assert.Len(t, db.snapshot(fork.ID).tasks, 2)
+
+	assert.Equal(t, database.FlowStatusWaiting, db.flows[0].Status)
+	assert.Equal(t, int64(forkSrcOwnerID), db.flows[0].UserID)
+	assert.Equal(t, before, db.snapshot(forkSrcFlowID))
+}
+
+func TestForkFlowWorkerNotCompletedPoint(t *testing.T) {
+	for _, subtaskID := range []int64{24, 31, 99} {
+		t.Run(fmt.Sprint(subtaskID), func(t *testing.T) {
+			db := newForkQuerier()
+
+			_, err := ForkFlowWorker(context.Background(), newForkFlowWorkerCtx(t, db, subtaskID))
+			require.Error(t, err)
+			assert.Len(t, db.flows, 1)
+			assert.Len(t, db.tasks, 3)
+		})
+	}
+}
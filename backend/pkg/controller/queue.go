@@ -0,0 +1,322 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/playbook"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/tools"
+
+	"github.com/sirupsen/logrus"
+)
+
+// flows are admitted on the enqueue signal and periodically to pick up the slots
+// which are released when active flows become waiting, finished or failed
+const flowQueueInterval = 5 * time.Second
+
+// flowQueueLimits holds max numbers of active (created or running) flows, zero means no limit
+type flowQueueLimits struct {
+	global   int
+	user     int
+	provider int
+}
+
+// flowQueueProvider identifies the provider which the limit is applied to, the default providers
+// are shared by all users and the user defined providers are resolved by the name of the user,
+// so the flows of different users with the same provider name don't share the slots
+type flowQueueProvider struct {
+	userID int64
+	name   provider.ProviderName
+}
+
+func newFlowQueueProvider(userID int64, prvname string) flowQueueProvider {
+	if name := provider.ProviderName(prvname); name.IsDefault() {
+		return flowQueueProvider{name: name}
+	}
+
+	return flowQueueProvider{userID: userID, name: provider.ProviderName(prvname)}
+}
+
+// flowQueueSlots counts active flows which occupy the queue limits
+type flowQueueSlots struct {
+	total     int
+	users     map[int64]int
+	providers map[flowQueueProvider]int
+}
+
+func newFlowQueueSlots(active []database.Flow) *flowQueueSlots {
+	slots := &flowQueueSlots{
+		users:     make(map[int64]int),
+		providers: make(map[flowQueueProvider]int),
+	}
+	for _, flow := range active {
+		slots.take(flow.UserID, newFlowQueueProvider(flow.UserID, flow.ModelProviderName))
+	}
+
+	return slots
+}
+
+func (s *flowQueueSlots) take(userID int64, prv flowQueueProvider) {
+	s.total++
+	s.users[userID]++
+	s.providers[prv]++
+}
+
+// admit takes the slot for the flow if it fits into all limits
+func (l flowQueueLimits) admit(slots *flowQueueSlots, userID int64, prvname string) bool {
+	prv := newFlowQueueProvider(userID, prvname)
+
+	switch {
+	case l.global > 0 && slots.total >= l.global:
+		return false
+	case l.user > 0 && slots.users[userID] >= l.user:
+		return false
+	case l.provider > 0 && slots.providers[prv] >= l.provider:
+		return false
+	}
+
+	slots.take(userID, prv)
+
+	return true
+}
+
+// enqueueFlow creates the flow record in the queued status with the parameters to start the flow worker later,
+// queued flows are admitted by priority and creation time within the configured concurrency limits,
+// the flow watchdog limits are stored before the flow is queued, so the first task is run with them
+func (fc *flowController) enqueueFlow(
+	ctx context.Context,
+	fwc newFlowWorkerCtx,
+	priority database.FlowPriority,
+	limits *database.FlowLimit,
+) (database.Flow, error) {
+	functions, err := json.Marshal(fwc.functions)
+	if err != nil {
+		return database.Flow{}, fmt.Errorf("failed to marshal functions: %w", err)
+	}
+
+	pb, err := json.Marshal(fwc.playbook)
+	if err != nil {
+		return database.Flow{}, fmt.Errorf("failed to marshal playbook: %w", err)
+	}
+
+	flow, err := fc.db.CreateFlow(ctx, newFlowParams(fwc.userID, fwc.prvname, fwc.prvtype, fwc.planReview, database.FlowStatusQueued))
+	if err != nil {
+		return database.Flow{}, fmt.Errorf("failed to create flow in DB: %w", err)
+	}
+
+	if limits != nil {
+		_, err = fc.db.UpsertFlowLimits(ctx, database.UpsertFlowLimitsParams{
+			FlowID:             flow.ID,
+			FlowTimeLimit:      limits.FlowTimeLimit,
+			TaskTimeLimit:      limits.TaskTimeLimit,
+			SubtaskTimeLimit:   limits.SubtaskTimeLimit,
+			MaxIterations:      limits.MaxIterations,
+			MaxToolCalls:       limits.MaxToolCalls,
+			MaxDelegationDepth: limits.MaxDelegationDepth,
+		})
+		if err != nil {
+			fc.failQueuedFlow(ctx, flow.ID, err)
+			return database.Flow{}, fmt.Errorf("failed to set flow %d limits: %w", flow.ID, err)
+		}
+	}
+
+	_, err = fc.db.CreateFlowQueueItem(ctx, database.CreateFlowQueueItemParams{
+		FlowID:     flow.ID,
+		Priority:   priority,
+		Input:      fwc.input,
+		Functions:  functions,
+		PlaybookID: database.Int64ToNullInt64(fwc.playbookID),
+		Playbook:   pb,
+	})
+	if err != nil {
+		fc.failQueuedFlow(ctx, flow.ID, err)
+		return database.Flow{}, fmt.Errorf("failed to put flow %d to the queue: %w", flow.ID, err)
+	}
+
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"flow_id":  flow.ID,
+		"user_id":  fwc.userID,
+		"priority": priority,
+	}).Info("flow is queued")
+
+	fc.subs.NewFlowPublisher(flow.UserID, flow.ID).FlowCreated(ctx, flow, nil)
+	fc.notifyQueue()
+
+	return flow, nil
+}
+
+func (fc *flowController) notifyQueue() {
+	select {
+	case fc.queue <- struct{}{}:
+	default:
+	}
+}
+
+// runQueue admits queued flows from the single goroutine, so the limits are checked consistently
+func (fc *flowController) runQueue(ctx context.Context) {
+	ticker := time.NewTicker(flowQueueInterval)
+	defer ticker.Stop()
+
+	for {
+		fc.admitFlows(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-fc.queue:
+		}
+	}
+}
+
+func (fc *flowController) admitFlows(ctx context.Context) {
+	logger := logrus.WithContext(ctx).WithField("component", "flow_queue")
+
+	items, err := fc.db.GetFlowQueue(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get flow queue")
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	active, err := fc.db.GetActiveFlows(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get active flows")
+		return
+	}
+
+	limits := flowQueueLimits{
+		global:   fc.cfg.FlowQueueGlobalLimit,
+		user:     fc.cfg.FlowQueueUserLimit,
+		provider: fc.cfg.FlowQueueProviderLimit,
+	}
+	slots := newFlowQueueSlots(active)
+
+	// the blocked flow doesn't hold flows of other users and providers which fit into the limits
+	for _, item := range items {
+		if !limits.admit(slots, item.UserID, item.ModelProviderName) {
+			continue
+		}
+
+		flow, err := fc.db.AdmitQueuedFlow(ctx, item.FlowID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue // the flow was cancelled in the meantime
+		} else if err != nil {
+			logger.WithError(err).WithField("flow_id", item.FlowID).Error("failed to admit queued flow")
+			continue
+		}
+
+		logger.WithFields(logrus.Fields{
+			"flow_id":  flow.ID,
+			"user_id":  flow.UserID,
+			"priority": item.Priority,
+		}).Info("queued flow is admitted")
+
+		go fc.startQueuedFlow(flow, item)
+	}
+}
+
+// startQueuedFlow starts the flow worker of the admitted flow, the queue item is kept until the worker
+// is started, so the flow is queued again by LoadFlows if the backend is restarted in the middle
+func (fc *flowController) startQueuedFlow(flow database.Flow, item database.GetFlowQueueRow) {
+	ctx := context.Background()
+
+	functions := &tools.Functions{}
+	if err := json.Unmarshal(item.Functions, functions); err != nil {
+		fc.failQueuedFlow(ctx, flow.ID, fmt.Errorf("failed to unmarshal functions: %w", err))
+		return
+	}
+
+	var pb *playbook.Playbook
+	if err := json.Unmarshal(item.Playbook, &pb); err != nil {
+		fc.failQueuedFlow(ctx, flow.ID, fmt.Errorf("failed to unmarshal playbook: %w", err))
+		return
+	}
+
+	fw, err := NewFlowWorker(ctx, newFlowWorkerCtx{
+		userID:     flow.UserID,
+		input:      item.Input,
+		prvname:    provider.ProviderName(flow.ModelProviderName),
+		prvtype:    provider.ProviderType(flow.ModelProviderType),
+		functions:  functions,
+		planReview: flow.PlanReview,
+		playbook:   pb,
+		playbookID: database.NullInt64ToInt64(item.PlaybookID),
+		flow:       &flow,
+		flowWorkerCtx: flowWorkerCtx{
+			db:     fc.db,
+			cfg:    fc.cfg,
+			docker: fc.docker,
+			provs:  fc.provs,
+			subs:   fc.subs,
+			hooks:  fc.hooks,
+			flowProviderControllers: flowProviderControllers{
+				mlc:  fc.mlc,
+				aslc: fc.aslc,
+				alc:  fc.alc,
+				slc:  fc.slc,
+				tlc:  fc.tlc,
+				vslc: fc.vslc,
+				sc:   fc.sc,
+			},
+		},
+	})
+	if err != nil {
+		fc.failQueuedFlow(ctx, flow.ID, fmt.Errorf("failed to create flow worker: %w", err))
+		return
+	}
+
+	fc.mx.Lock()
+	fc.flows[fw.GetFlowID()] = fw
+	fc.mx.Unlock()
+
+	if err := fc.db.DeleteFlowQueueItem(ctx, flow.ID); err != nil {
+		logrus.WithError(err).WithField("flow_id", flow.ID).Error("failed to delete flow queue item")
+	}
+}
+
+func (fc *flowController) failQueuedFlow(ctx context.Context, flowID int64, reason error) {
+	logger := logrus.WithContext(ctx).WithField("flow_id", flowID)
+	logger.WithError(reason).Error("failed to start queued flow")
+
+	if err := fc.db.DeleteFlowQueueItem(ctx, flowID); err != nil {
+		logger.WithError(err).Error("failed to delete flow queue item")
+	}
+
+	flow, err := fc.db.UpdateFlowStatus(ctx, database.UpdateFlowStatusParams{
+		Status: database.FlowStatusFailed,
+		ID:     flowID,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to set flow status to failed")
+		return
+	}
+
+	fc.subs.NewFlowPublisher(flow.UserID, flow.ID).FlowUpdated(ctx, flow, nil)
+}
+
+// cancelQueuedFlow removes the flow from the queue before it's admitted and finishes it
+func (fc *flowController) cancelQueuedFlow(ctx context.Context, flowID int64) error {
+	flow, err := fc.db.CancelQueuedFlow(ctx, flowID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFlowNotFound
+	} else if err != nil {
+		return fmt.Errorf("failed to cancel queued flow %d: %w", flowID, err)
+	}
+
+	if err := fc.db.DeleteFlowQueueItem(ctx, flowID); err != nil {
+		return fmt.Errorf("failed to delete flow %d queue item: %w", flowID, err)
+	}
+
+	fc.subs.NewFlowPublisher(flow.UserID, flow.ID).FlowUpdated(ctx, flow, nil)
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/database"
+)
+
+func TestFlowQueueLimitsAdmit(t *testing.T) {
+	active := []database.Flow{
+		{UserID: 1, ModelProviderName: "openai", ModelProviderType: database.ProviderTypeOpenai},
+		{UserID: 2, ModelProviderName: "team-claude", ModelProviderType: database.ProviderTypeAnthropic},
+	}
+
+	tests := []struct {
+		name    string
+		limits  flowQueueLimits
+		userID  int64
+		prvname string
+		want    bool
+	}{
+		{"no limits", flowQueueLimits{}, 1, "openai", true},
+		{"global limit reached", flowQueueLimits{global: 2}, 3, "gemini", false},
+		{"global limit free", flowQueueLimits{global: 3}, 3, "gemini", true},
+		{"user limit reached", flowQueueLimits{user: 1}, 1, "gemini", false},
+		{"user limit other user", flowQueueLimits{user: 1}, 3, "gemini", true},
+		{"default provider limit reached", flowQueueLimits{provider: 1}, 3, "openai", false},
+		{"default provider limit other provider", flowQueueLimits{provider: 1}, 3, "gemini", true},
+		{"user provider limit reached", flowQueueLimits{provider: 1}, 2, "team-claude", false},
+		{"user provider limit other provider of same type", flowQueueLimits{provider: 1}, 2, "own-claude", true},
+		{"user provider limit same name of other user", flowQueueLimits{provider: 1}, 3, "team-claude", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := newFlowQueueSlots(active)
+			if got := tt.limits.admit(slots, tt.userID, tt.prvname); got != tt.want {
+				t.Errorf("Expected admit %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFlowQueueLimitsTakeSlot(t *testing.T) {
+	limits := flowQueueLimits{user: 2}
+	slots := newFlowQueueSlots(nil)
+
+	admitted := 0
+	for range 3 {
+		if limits.admit(slots, 1, "openai") {
+			admitted++
+		}
+	}
+
+	if admitted != 2 {
+		t.Errorf("Expected 2 admitted flows, got %d", admitted)
+	}
+	if prv := newFlowQueueProvider(1, "openai"); slots.total != 2 || slots.providers[prv] != 2 {
+		t.Errorf("Unexpected slots: %+v", slots)
+	}
+}
+
+// restartQuerier keeps flows and the flow queue like they are stored in the DB before the restart,
+// the queue goroutine reads it concurrently with the test
+type restartQuerier struct {
+	database.Querier
+	mx       sync.Mutex
+	flows    []database.Flow
+	queue    map[int64]database.FlowPriority
+	admitted []int64
+}
+
+func (q *restartQuerier) RequeueFlows(_ context.Context) ([]database.Flow, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	var requeued []database.Flow
+	for idx, flow := range q.flows {
+		if _, ok := q.queue[flow.ID]; ok && flow.Status == database.FlowStatusCreated {
+			q.flows[idx].Status = database.FlowStatusQueued
+			requeued = append(requeued, q.flows[idx])
+		}
+	}
+	return requeued, nil
+}
+
+func (q *restartQuerier) GetFlows(_ context.Context) ([]database.Flow, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	return slices.Clone(q.flows), nil
+}
+
+func (q *restartQuerier) GetFlowPrimaryContainer(_ context.Context, _ int64) (database.Container, error) {
+	return database.Container{}, sql.ErrNoRows
+}
+
+func (q *restartQuerier) GetFlowQueue(_ context.Context) ([]database.GetFlowQueueRow, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	var items []database.GetFlowQueueRow
+	for _, flow := range q.flows {
+		if priority, ok := q.queue[flow.ID]; ok && flow.Status == database.FlowStatusQueued {
+			items = append(items, database.GetFlowQueueRow{
+				FlowID:            flow.ID,
+				Priority:          priority,
+				UserID:            flow.UserID,
+				ModelProviderName: flow.ModelProviderName,
+			})
+		}
+	}
+	rank := map[database.FlowPriority]int{
+		database.FlowPriorityHigh:   0,
+		database.FlowPriorityNormal: 1,
+		database.FlowPriorityLow:    2,
+	}
+	slices.SortStableFunc(items, func(a, b database.GetFlowQueueRow) int {
+		return rank[a.Priority] - rank[b.Priority]
+	})
+	return items, nil
+}
+
+func (q *restartQuerier) GetActiveFlows(_ context.Context) ([]database.Flow, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	return slices.DeleteFunc(slices.Clone(q.flows), func(flow database.Flow) bool {
+		return flow.Status != database.FlowStatusCreated && flow.Status != database.FlowStatusRunning
+	}), nil
+}
+
+func (q *restartQuerier) AdmitQueuedFlow(_ context.Context, flowID int64) (database.Flow, error) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	// the admission is only recorded, so the flow worker isn't started in the test
+	q.admitted = append(q.admitted, flowID)
+	return database.Flow{}, sql.ErrNoRows
+}
+
+func (q *restartQuerier) setStatus(flowID int64, status database.FlowStatus) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	for idx := range q.flows {
+		if q.flows[idx].ID == flowID {
+			q.flows[idx].Status = status
+		}
+	}
+}
+
+func (q *restartQuerier) status(flowID int64) database.FlowStatus {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	for _, flow := range q.flows {
+		if flow.ID == flowID {
+			return flow.Status
+		}
+	}
+	return ""
+}
+
+func (q *restartQuerier) admittedFlows() []int64 {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+	return slices.Clone(q.admitted)
+}
+
+func TestLoadFlowsRequeuesAfterRestart(t *testing.T) {
+	db := &restartQuerier{
+		flows: []database.Flow{
+			// admitted before the restart, but the worker wasn't started
+			{ID: 1, UserID: 1, Status: database.FlowStatusCreated, ModelProviderName: "openai"},
+			{ID: 2, UserID: 1, Status: database.FlowStatusQueued, ModelProviderName: "openai"},
+			// occupies the only slot, its worker can't be loaded without the container in the test
+			{ID: 3, UserID: 2, Status: database.FlowStatusRunning, ModelProviderName: "openai"},
+		},
+		queue: map[int64]database.FlowPriority{
+			1: database.FlowPriorityHigh,
+			2: database.FlowPriorityNormal,
+		},
+	}
+	fc := &flowController{
+		db:    db,
+		mx:    &sync.Mutex{},
+		cfg:   &config.Config{FlowQueueGlobalLimit: 1},
+		flows: make(map[int64]FlowWorker),
+		queue: make(chan struct{}, 1),
+	}
+
+	if err := fc.LoadFlows(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, id := range []int64{1, 2} {
+		if status := db.status(id); status != database.FlowStatusQueued {
+			t.Errorf("Expected flow %d to be queued, got %s", id, status)
+		}
+	}
+	fc.mx.Lock()
+	workers := len(fc.flows)
+	fc.mx.Unlock()
+	if workers != 0 {
+		t.Errorf("Expected no workers for queued flows, got %d", workers)
+	}
+
+	// the queue is resumed, the requeued flow waits for the slot like the others
+	time.Sleep(50 * time.Millisecond)
+	if admitted := db.admittedFlows(); len(admitted) != 0 {
+		t.Fatalf("Expected no admission while the limit is reached, got %v", admitted)
+	}
+
+	db.setStatus(3, database.FlowStatusFinished)
+	fc.notifyQueue()
+
+	deadline := time.Now().Add(time.Second)
+	for len(db.admittedFlows()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if admitted := db.admittedFlows(); !slices.Equal(admitted, []int64{1}) {
+		t.Errorf("Expected the requeued flow to be admitted into the free slot, got %v", admitted)
+	}
+}
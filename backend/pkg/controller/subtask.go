@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"pentagi/pkg/database"
@@ -30,10 +31,19 @@ type SubtaskWorker interface {
 	GetResult(ctx context.Context) (string, error)
 	SetResult(ctx context.Context, result string) error
 	PutInput(ctx context.Context, input string) error
+	Retry(ctx context.Context, instructions string) error
+	Skip(ctx context.Context) error
 	Run(ctx context.Context) error
 	Finish(ctx context.Context) error
 }
 
+const (
+	subtaskRetryMessage = "The user asked to perform the current subtask again. " +
+		"Take into account the previous attempt and its result, then complete the subtask and report the new result."
+	subtaskRetryInstructions = "Additional instructions from the user:"
+	subtaskSkippedResult     = "The subtask was skipped by the user."
+)
+
 type subtaskWorker struct {
 	mx         *sync.RWMutex
 	subtaskCtx *SubtaskContext
@@ -53,9 +63,20 @@ func NewSubtaskWorker(
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.NewSubtaskWorker")
 	defer span.End()
 
-	msgChainID, err := taskCtx.Provider.PrepareAgentChain(ctx, taskCtx.TaskID, id)
+	msgChains, err := taskCtx.DB.GetSubtaskPrimaryMsgChains(ctx, database.Int64ToNullInt64(&id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare primary agent chain for subtask %d: %w", id, err)
+		return nil, fmt.Errorf("failed to get subtask primary msg chains for subtask %d: %w", id, err)
+	}
+
+	var msgChainID int64
+	if len(msgChains) != 0 {
+		// retried subtask continues its own msg chain which already contains the user instructions
+		msgChainID = msgChains[0].ID
+	} else {
+		msgChainID, err = taskCtx.Provider.PrepareAgentChain(ctx, taskCtx.TaskID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare primary agent chain for subtask %d: %w", id, err)
+		}
 	}
 
 	return &subtaskWorker{
@@ -271,6 +292,78 @@ func (stw *subtaskWorker) PutInput(ctx context.Context, input string) error {
 	return nil
 }
 
+// Retry restores the msg chain of the completed subtask and puts it back to the plan,
+// the next Run call continues the same chain with the retry request and user instructions
+func (stw *subtaskWorker) Retry(ctx context.Context, instructions string) error {
+	if !stw.IsCompleted() {
+		return fmt.Errorf("subtask %d has not completed yet", stw.subtaskCtx.SubtaskID)
+	}
+
+	input := subtaskRetryMessage
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		input = fmt.Sprintf("%s\n\n%s\n%s", input, subtaskRetryInstructions, instructions)
+	}
+
+	msgChainID := stw.subtaskCtx.MsgChainID
+	if err := stw.subtaskCtx.Provider.EnsureChainConsistency(ctx, msgChainID); err != nil {
+		return fmt.Errorf("failed to ensure chain consistency for subtask %d: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	if err := stw.subtaskCtx.Provider.PutInputToAgentChain(ctx, msgChainID, input); err != nil {
+		return fmt.Errorf("failed to put retry input for subtask %d: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	_, err := stw.subtaskCtx.MsgLog.PutSubtaskMsg(
+		ctx,
+		database.MsglogTypeInput,
+		stw.subtaskCtx.TaskID,
+		stw.subtaskCtx.SubtaskID,
+		"", // thinking is empty because this is input
+		input,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put retry input for subtask %d: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	// status Created returns the subtask to the planned queue before the rest of planned subtasks
+	_, err = stw.subtaskCtx.DB.UpdateSubtaskStatus(ctx, database.UpdateSubtaskStatusParams{
+		Status: database.SubtaskStatusCreated,
+		ID:     stw.subtaskCtx.SubtaskID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set subtask %d status to created: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	stw.mx.Lock()
+	defer stw.mx.Unlock()
+
+	stw.completed = false
+	stw.waiting = false
+
+	return nil
+}
+
+// Skip completes the current subtask without execution, the task continues with the next one
+func (stw *subtaskWorker) Skip(ctx context.Context) error {
+	if stw.IsCompleted() {
+		return fmt.Errorf("subtask has already completed")
+	}
+
+	if err := stw.subtaskCtx.Provider.EnsureChainConsistency(ctx, stw.subtaskCtx.MsgChainID); err != nil {
+		return fmt.Errorf("failed to ensure chain consistency for subtask %d: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	if err := stw.SetResult(ctx, subtaskSkippedResult); err != nil {
+		return err
+	}
+
+	if err := stw.SetStatus(ctx, database.SubtaskStatusFinished); err != nil {
+		return fmt.Errorf("failed to set subtask %d status to finished: %w", stw.subtaskCtx.SubtaskID, err)
+	}
+
+	return nil
+}
+
 func (stw *subtaskWorker) Run(ctx context.Context) error {
 	if stw.IsCompleted() {
 		return fmt.Errorf("subtask has already completed")
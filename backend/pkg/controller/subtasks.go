@@ -4,12 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 
 	"pentagi/pkg/database"
+	"pentagi/pkg/providers"
+	"pentagi/pkg/tools"
+
+	"github.com/sirupsen/logrus"
 )
 
+// ErrSubtasksBlocked is returned by PopSubtasks when planned subtasks are left
+// but all of them depend on unfinished ones (e.g. a subtask which waits for the user)
+var ErrSubtasksBlocked = errors.New("planned subtasks are blocked by unfinished dependencies")
+
 type NewSubtaskInfo struct {
 	Title       string
 	Description string
@@ -18,8 +27,12 @@ type NewSubtaskInfo struct {
 type SubtaskController interface {
 	LoadSubtasks(ctx context.Context, taskID int64, updater TaskUpdater) error
 	GenerateSubtasks(ctx context.Context) error
+	SeedSubtasks(ctx context.Context, plan []tools.SubtaskInfo) error
 	RefineSubtasks(ctx context.Context) error
-	PopSubtask(ctx context.Context, updater TaskUpdater) (SubtaskWorker, error)
+	PatchSubtasks(ctx context.Context, patch tools.SubtaskPatch) error
+	InsertSubtask(ctx context.Context, info tools.SubtaskInfo) error
+	SkipSubtask(ctx context.Context) error
+	PopSubtasks(ctx context.Context, updater TaskUpdater, limit int) ([]SubtaskWorker, error)
 	ListSubtasks(ctx context.Context) []SubtaskWorker
 	GetSubtask(ctx context.Context, subtaskID int64) (SubtaskWorker, error)
 }
@@ -28,6 +41,11 @@ type subtaskController struct {
 	mx       *sync.Mutex
 	taskCtx  *TaskContext
 	subtasks map[int64]SubtaskWorker
+
+	// planMX serializes replacing of the planned subtasks by the refiner and the user,
+	// userEdit is set when the user changed the plan and the next refinement is skipped
+	planMX   *sync.Mutex
+	userEdit bool
 }
 
 func NewSubtaskController(taskCtx *TaskContext) SubtaskController {
@@ -35,6 +53,7 @@ func NewSubtaskController(taskCtx *TaskContext) SubtaskController {
 		mx:       &sync.Mutex{},
 		taskCtx:  taskCtx,
 		subtasks: make(map[int64]SubtaskWorker),
+		planMX:   &sync.Mutex{},
 	}
 }
 
@@ -77,26 +96,78 @@ func (stc *subtaskController) GenerateSubtasks(ctx context.Context) error {
 		return fmt.Errorf("no subtasks generated for task %d", stc.taskCtx.TaskID)
 	}
 
+	return stc.createSubtasks(ctx, positionalPlan(plan), nil)
+}
+
+// SeedSubtasks stores the predefined plan (e.g. from a playbook) instead of calling the generator,
+// the refiner is still able to patch these subtasks after each completed one
+func (stc *subtaskController) SeedSubtasks(ctx context.Context, plan []tools.SubtaskInfo) error {
+	if len(plan) == 0 {
+		return fmt.Errorf("no subtasks to seed for task %d", stc.taskCtx.TaskID)
+	}
+
+	return stc.createSubtasks(ctx, positionalPlan(plan), nil)
+}
+
+// positionalPlan sets 1-based positions as keys of the new plan, dependencies of the generated
+// and predefined subtasks refer to positions of previous subtasks in the list
+func positionalPlan(plan []tools.SubtaskInfo) []tools.SubtaskInfoPatch {
+	result := make([]tools.SubtaskInfoPatch, 0, len(plan))
+	for idx, info := range plan {
+		result = append(result, tools.SubtaskInfoPatch{
+			ID:          int64(idx + 1),
+			SubtaskInfo: info,
+		})
+	}
+
+	return result
+}
+
+// createSubtasks stores the plan in the execution order, dependencies are resolved from the plan keys
+// to IDs of created subtasks, references to subtasks out of the plan are kept only for existing ones,
+// others (forward, unknown or self references) are dropped so the dependency graph stays acyclic
+func (stc *subtaskController) createSubtasks(
+	ctx context.Context,
+	plan []tools.SubtaskInfoPatch,
+	existing map[int64]struct{},
+) error {
+	created := make(map[int64]int64, len(plan))
+
 	// TODO: change it to insert subtasks in transaction
 	for _, info := range plan {
-		_, err := stc.taskCtx.DB.CreateSubtask(ctx, database.CreateSubtaskParams{
+		dependsOn := make([]int64, 0, len(info.DependsOn))
+		for _, key := range info.DependsOn {
+			if id, ok := created[key]; ok {
+				dependsOn = append(dependsOn, id)
+			} else if _, ok := existing[key]; ok {
+				dependsOn = append(dependsOn, key)
+			}
+		}
+		slices.Sort(dependsOn)
+
+		subtask, err := stc.taskCtx.DB.CreateSubtask(ctx, database.CreateSubtaskParams{
 			Status:      database.SubtaskStatusCreated,
 			TaskID:      stc.taskCtx.TaskID,
 			Title:       info.Title,
 			Description: info.Description,
+			DependsOn:   slices.Compact(dependsOn),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create subtask for task %d: %w", stc.taskCtx.TaskID, err)
 		}
+
+		if info.ID != 0 {
+			created[info.ID] = subtask.ID
+		}
 	}
 
 	return nil
 }
 
 func (stc *subtaskController) RefineSubtasks(ctx context.Context) error {
-	subtasks, err := stc.taskCtx.DB.GetTaskSubtasks(ctx, stc.taskCtx.TaskID)
-	if err != nil {
-		return fmt.Errorf("failed to get task %d subtasks: %w", stc.taskCtx.TaskID, err)
+	// the plan changed by the user is performed as is until the next completed subtask
+	if stc.takeUserEdit() {
+		return nil
 	}
 
 	plan, err := stc.taskCtx.Provider.RefineSubtasks(ctx, stc.taskCtx.TaskID)
@@ -108,10 +179,170 @@ func (stc *subtaskController) RefineSubtasks(ctx context.Context) error {
 		return nil // no subtasks refined
 	}
 
+	stc.planMX.Lock()
+	defer stc.planMX.Unlock()
+
+	// the user changed the plan while the refiner was working, user changes take precedence
+	if stc.userEdit {
+		stc.userEdit = false
+		return nil
+	}
+
+	subtasks, err := stc.getPlannedSubtasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	return stc.replacePlannedSubtasks(ctx, subtasks, plan)
+}
+
+// PatchSubtasks applies the user edits to the planned subtasks before they are executed
+func (stc *subtaskController) PatchSubtasks(ctx context.Context, patch tools.SubtaskPatch) error {
+	stc.planMX.Lock()
+	defer stc.planMX.Unlock()
+
+	subtasks, err := stc.getPlannedSubtasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger := logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"flow_id": stc.taskCtx.FlowID,
+		"task_id": stc.taskCtx.TaskID,
+	})
+	plan, err := providers.PatchSubtasks(subtasks, patch, logger)
+	if err != nil {
+		return fmt.Errorf("failed to patch subtasks for task %d: %w", stc.taskCtx.TaskID, err)
+	}
+
+	return stc.replacePlannedSubtasks(ctx, subtasks, plan)
+}
+
+// InsertSubtask puts the new subtask right after the current one, before the rest of planned subtasks
+func (stc *subtaskController) InsertSubtask(ctx context.Context, info tools.SubtaskInfo) error {
+	stc.planMX.Lock()
+	defer stc.planMX.Unlock()
+
+	subtasks, err := stc.getPlannedSubtasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(subtasks) >= providers.TasksNumberLimit {
+		return fmt.Errorf("too many planned subtasks for task %d: %d of %d allowed",
+			stc.taskCtx.TaskID, len(subtasks)+1, providers.TasksNumberLimit)
+	}
+
+	plan := make([]tools.SubtaskInfoPatch, 0, len(subtasks)+1)
+	plan = append(plan, tools.SubtaskInfoPatch{SubtaskInfo: info})
+	for _, subtask := range subtasks {
+		plan = append(plan, tools.SubtaskInfoPatch{
+			ID: subtask.ID,
+			SubtaskInfo: tools.SubtaskInfo{
+				Title:       subtask.Title,
+				Description: subtask.Description,
+				DependsOn:   subtask.DependsOn,
+			},
+		})
+	}
+
+	if err := stc.replacePlannedSubtasks(ctx, subtasks, plan); err != nil {
+		return err
+	}
+
+	stc.userEdit = true
+
+	return nil
+}
+
+// SkipSubtask completes the current subtask (waiting or the next planned one) without execution
+func (stc *subtaskController) SkipSubtask(ctx context.Context) error {
+	planned, err := stc.taskCtx.DB.GetTaskPlannedSubtasks(ctx, stc.taskCtx.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %d planned subtasks: %w", stc.taskCtx.TaskID, err)
+	}
+
+	if len(planned) == 0 {
+		return fmt.Errorf("task %d has no subtask to skip", stc.taskCtx.TaskID)
+	}
+
+	current := planned[0]
+	if st, err := stc.GetSubtask(ctx, current.ID); err == nil {
+		return st.Skip(ctx)
+	}
+
+	// the subtask was not started yet, so there is no worker and msg chain for it
+	_, err = stc.taskCtx.DB.UpdateSubtaskResult(ctx, database.UpdateSubtaskResultParams{
+		Result: subtaskSkippedResult,
+		ID:     current.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set subtask %d result: %w", current.ID, err)
+	}
+
+	_, err = stc.taskCtx.DB.UpdateSubtaskStatus(ctx, database.UpdateSubtaskStatusParams{
+		Status: database.SubtaskStatusFinished,
+		ID:     current.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set subtask %d status: %w", current.ID, err)
+	}
+
+	return nil
+}
+
+func (stc *subtaskController) takeUserEdit() bool {
+	stc.planMX.Lock()
+	defer stc.planMX.Unlock()
+
+	userEdit := stc.userEdit
+	stc.userEdit = false
+
+	return userEdit
+}
+
+// getPlannedSubtasks returns subtasks which were not started yet in the execution order,
+// retried subtasks keep their workers and msg chains so they are not a part of the plan
+func (stc *subtaskController) getPlannedSubtasks(ctx context.Context) ([]database.Subtask, error) {
+	subtasks, err := stc.taskCtx.DB.GetTaskPlannedSubtasks(ctx, stc.taskCtx.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %d planned subtasks: %w", stc.taskCtx.TaskID, err)
+	}
+
+	stc.mx.Lock()
+	defer stc.mx.Unlock()
+
+	planned := make([]database.Subtask, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		if _, ok := stc.subtasks[subtask.ID]; ok || subtask.Status != database.SubtaskStatusCreated {
+			continue
+		}
+		planned = append(planned, subtask)
+	}
+
+	return planned, nil
+}
+
+func (stc *subtaskController) replacePlannedSubtasks(
+	ctx context.Context,
+	subtasks []database.Subtask,
+	plan []tools.SubtaskInfoPatch,
+) error {
 	subtaskIDs := make([]int64, 0, len(subtasks))
 	for _, subtask := range subtasks {
-		if subtask.Status == database.SubtaskStatusCreated {
-			subtaskIDs = append(subtaskIDs, subtask.ID)
+		subtaskIDs = append(subtaskIDs, subtask.ID)
+	}
+
+	// unfinished subtasks out of the plan (waiting or retried ones) still can be dependencies
+	unfinished, err := stc.taskCtx.DB.GetTaskPlannedSubtasks(ctx, stc.taskCtx.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %d planned subtasks: %w", stc.taskCtx.TaskID, err)
+	}
+
+	existing := make(map[int64]struct{}, len(unfinished))
+	for _, subtask := range unfinished {
+		if !slices.Contains(subtaskIDs, subtask.ID) {
+			existing[subtask.ID] = struct{}{}
 		}
 	}
 
@@ -121,22 +352,13 @@ func (stc *subtaskController) RefineSubtasks(ctx context.Context) error {
 	}
 
 	// TODO: change it to insert subtasks in transaction and union it with delete ones
-	for _, info := range plan {
-		_, err := stc.taskCtx.DB.CreateSubtask(ctx, database.CreateSubtaskParams{
-			Status:      database.SubtaskStatusCreated,
-			TaskID:      stc.taskCtx.TaskID,
-			Title:       info.Title,
-			Description: info.Description,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create subtask for task %d: %w", stc.taskCtx.TaskID, err)
-		}
-	}
-
-	return nil
+	return stc.createSubtasks(ctx, plan, existing)
 }
 
-func (stc *subtaskController) PopSubtask(ctx context.Context, updater TaskUpdater) (SubtaskWorker, error) {
+// PopSubtasks returns up to limit planned subtasks which don't wait for the user input
+// and have no unfinished dependencies, it returns nil if there is nothing to run
+// and ErrSubtasksBlocked if runnable subtasks are left but none of them is ready
+func (stc *subtaskController) PopSubtasks(ctx context.Context, updater TaskUpdater, limit int) ([]SubtaskWorker, error) {
 	stc.mx.Lock()
 	defer stc.mx.Unlock()
 
@@ -145,23 +367,74 @@ func (stc *subtaskController) PopSubtask(ctx context.Context, updater TaskUpdate
 		return nil, fmt.Errorf("failed to get task planned subtasks: %w", err)
 	}
 
-	if len(subtasks) == 0 {
+	runnable := make([]database.Subtask, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		if st, ok := stc.subtasks[subtask.ID]; ok && st.IsWaiting() {
+			continue
+		}
+		runnable = append(runnable, subtask)
+	}
+
+	ready := selectReadySubtasks(subtasks, runnable, limit)
+	if len(ready) == 0 && len(runnable) != 0 {
+		return nil, ErrSubtasksBlocked
+	}
+
+	workers := make([]SubtaskWorker, 0, max(limit, 1))
+	for _, stdb := range ready {
+		if st, ok := stc.subtasks[stdb.ID]; ok {
+			workers = append(workers, st)
+			continue
+		}
+
+		st, err := NewSubtaskWorker(ctx, stc.taskCtx, stdb.ID, stdb.Title, stdb.Description, updater)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create subtask worker: %w", err)
+		}
+
+		stc.subtasks[stdb.ID] = st
+		workers = append(workers, st)
+	}
+
+	if len(workers) == 0 {
 		return nil, nil
 	}
 
-	stdb := subtasks[0]
-	if st, ok := stc.subtasks[stdb.ID]; ok {
-		return st, nil
+	return workers, nil
+}
+
+// selectReadySubtasks returns up to limit runnable subtasks in the plan order which dependencies
+// are not among the unfinished ones, a subtask is never started before its dependencies are done
+func selectReadySubtasks(unfinished, runnable []database.Subtask, limit int) []database.Subtask {
+	if len(runnable) == 0 {
+		return nil
 	}
 
-	st, err := NewSubtaskWorker(ctx, stc.taskCtx, stdb.ID, stdb.Title, stdb.Description, updater)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create subtask worker: %w", err)
+	pending := make(map[int64]struct{}, len(unfinished))
+	for _, subtask := range unfinished {
+		pending[subtask.ID] = struct{}{}
 	}
 
-	stc.subtasks[stdb.ID] = st
+	ready := make([]database.Subtask, 0, max(limit, 1))
+	for _, subtask := range runnable {
+		if len(ready) >= max(limit, 1) {
+			break
+		}
+
+		isReady := true
+		for _, id := range subtask.DependsOn {
+			if _, ok := pending[id]; ok {
+				isReady = false
+				break
+			}
+		}
+
+		if isReady {
+			ready = append(ready, subtask)
+		}
+	}
 
-	return st, nil
+	return ready
 }
 
 func (stc *subtaskController) ListSubtasks(ctx context.Context) []SubtaskWorker {
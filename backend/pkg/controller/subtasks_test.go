@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/providers"
+	"pentagi/pkg/tools"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTaskID = 100
+
+// planQuerier keeps subtasks of the test task in memory, other queries are not used by the plan controls
+type planQuerier struct {
+	database.Querier
+	subtasks []database.Subtask
+	lastID   int64
+}
+
+func newPlanQuerier(subtasks ...database.Subtask) *planQuerier {
+	q := &planQuerier{subtasks: subtasks}
+	for idx := range subtasks {
+		subtasks[idx].TaskID = testTaskID
+		q.lastID = max(q.lastID, subtasks[idx].ID)
+	}
+	return q
+}
+
+func (q *planQuerier) GetTaskPlannedSubtasks(_ context.Context, taskID int64) ([]database.Subtask, error) {
+	var planned []database.Subtask
+	for _, subtask := range q.subtasks {
+		if subtask.TaskID != taskID {
+			continue
+		}
+		if subtask.Status == database.SubtaskStatusCreated || subtask.Status == database.SubtaskStatusWaiting {
+			planned = append(planned, subtask)
+		}
+	}
+	return planned, nil
+}
+
+func (q *planQuerier) CreateSubtask(_ context.Context, arg database.CreateSubtaskParams) (database.Subtask, error) {
+	// IDs are not reused like in the DB sequence
+	q.lastID++
+	subtask := database.Subtask{
+		ID:          q.lastID,
+		Status:      arg.Status,
+		Title:       arg.Title,
+		Description: arg.Description,
+		TaskID:      arg.TaskID,
+		DependsOn:   arg.DependsOn,
+	}
+	q.subtasks = append(q.subtasks, subtask)
+
+	return subtask, nil
+}
+
+func (q *planQuerier) DeleteSubtasks(_ context.Context, ids []int64) error {
+	q.subtasks = slices.DeleteFunc(q.subtasks, func(subtask database.Subtask) bool {
+		return slices.Contains(ids, subtask.ID)
+	})
+	return nil
+}
+
+func (q *planQuerier) UpdateSubtaskResult(
+	_ context.Context,
+	arg database.UpdateSubtaskResultParams,
+) (database.Subtask, error) {
+	subtask, err := q.get(arg.ID)
+	if err != nil {
+		return database.Subtask{}, err
+	}
+	subtask.Result = arg.Result
+	return *subtask, nil
+}
+
+func (q *planQuerier) UpdateSubtaskStatus(
+	_ context.Context,
+	arg database.UpdateSubtaskStatusParams,
+) (database.Subtask, error) {
+	subtask, err := q.get(arg.ID)
+	if err != nil {
+		return database.Subtask{}, err
+	}
+	subtask.Status = arg.Status
+	return *subtask, nil
+}
+
+func (q *planQuerier) get(id int64) (*database.Subtask, error) {
+	for idx := range q.subtasks {
+		if q.subtasks[idx].ID == id {
+			return &q.subtasks[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("subtask %d not found", id)
+}
+
+// stubSubtaskWorker is a started subtask, only the state and the skip call are used by the plan controls
+type stubSubtaskWorker struct {
+	SubtaskWorker
+	id        int64
+	completed bool
+	waiting   bool
+	skipped   bool
+}
+
+func (st *stubSubtaskWorker) GetSubtaskID() int64 { return st.id }
+func (st *stubSubtaskWorker) IsCompleted() bool   { return st.completed }
+func (st *stubSubtaskWorker) IsWaiting() bool     { return st.waiting }
+
+func (st *stubSubtaskWorker) Skip(ctx context.Context) error {
+	st.skipped = true
+	return nil
+}
+
+func newTestSubtaskController(db database.Querier, workers ...SubtaskWorker) *subtaskController {
+	// provider is not set, so any unexpected refinement call fails the test
+	stc := NewSubtaskController(&TaskContext{
+		TaskID:      testTaskID,
+		FlowContext: FlowContext{DB: db},
+	}).(*subtaskController)
+
+	for _, st := range workers {
+		stc.subtasks[st.GetSubtaskID()] = st
+	}
+
+	return stc
+}
+
+func plannedIDs(t *testing.T, db *planQuerier) []int64 {
+	planned, err := db.GetTaskPlannedSubtasks(context.Background(), testTaskID)
+	require.NoError(t, err)
+
+	return subtaskIDs(planned)
+}
+
+func TestInsertSubtask(t *testing.T) {
+	ctx := context.Background()
+	db := newPlanQuerier(
+		database.Subtask{ID: 1, Status: database.SubtaskStatusFinished, Title: "Recon"},
+		database.Subtask{ID: 2, Status: database.SubtaskStatusCreated, Title: "Scan"},
+		database.Subtask{ID: 3, Status: database.SubtaskStatusCreated, Title: "Enumerate"},
+		database.Subtask{ID: 4, Status: database.SubtaskStatusCreated, Title: "Exploit", DependsOn: []int64{3}},
+		database.Subtask{ID: 5, Status: database.SubtaskStatusCreated, Title: "Report", DependsOn: []int64{2}},
+	)
+	// subtask 2 is the current one, it's started and out of the plan which is replaced
+	stc := newTestSubtaskController(db, &stubSubtaskWorker{id: 2})
+
+	err := stc.InsertSubtask(ctx, tools.SubtaskInfo{Title: "Check creds", Description: "Try default creds"})
+	require.NoError(t, err)
+
+	// the new subtask goes right after the current one and the rest of the plan is renumbered
+	assert.Equal(t, []int64{2, 6, 7, 8, 9}, plannedIDs(t, db))
+
+	inserted, err := db.get(6)
+	require.NoError(t, err)
+	assert.Equal(t, "Check creds", inserted.Title)
+	assert.Empty(t, inserted.DependsOn)
+
+	for id, expected := range map[int64]struct {
+		title     string
+		dependsOn []int64
+	}{
+		7: {"Enumerate", []int64{}},
+		8: {"Exploit", []int64{7}},
+		9: {"Report", []int64{2}},
+	} {
+		subtask, err := db.get(id)
+		require.NoError(t, err)
+		assert.Equal(t, expected.title, subtask.Title)
+		assert.Equal(t, expected.dependsOn, subtask.DependsOn, "dependencies of subtask %d", id)
+	}
+
+	// the user plan is performed as is, so the next refinement doesn't call the provider
+	assert.NoError(t, stc.RefineSubtasks(ctx))
+	assert.False(t, stc.userEdit)
+}
+
+func TestInsertSubtaskLimit(t *testing.T) {
+	subtasks := make([]database.Subtask, 0, providers.TasksNumberLimit)
+	for idx := range providers.TasksNumberLimit {
+		subtasks = append(subtasks, database.Subtask{ID: int64(idx + 1), Status: database.SubtaskStatusCreated})
+	}
+	db := newPlanQuerier(subtasks...)
+	stc := newTestSubtaskController(db)
+
+	err := stc.InsertSubtask(context.Background(), tools.SubtaskInfo{Title: "one more", Description: "one more"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many planned subtasks")
+	assert.Len(t, plannedIDs(t, db), providers.TasksNumberLimit)
+	assert.False(t, stc.userEdit)
+}
+
+func TestSkipSubtaskNotStarted(t *testing.T) {
+	ctx := context.Background()
+	db := newPlanQuerier(
+		database.Subtask{ID: 1, Status: database.SubtaskStatusCreated},
+		database.Subtask{ID: 2, Status: database.SubtaskStatusCreated, DependsOn: []int64{1}},
+		database.Subtask{ID: 3, Status: database.SubtaskStatusCreated},
+	)
+	stc := newTestSubtaskController(db)
+
+	planned, err := db.GetTaskPlannedSubtasks(ctx, testTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, subtaskIDs(selectReadySubtasks(planned, planned, 3)))
+
+	require.NoError(t, stc.SkipSubtask(ctx))
+
+	skipped, err := db.get(1)
+	require.NoError(t, err)
+	assert.Equal(t, database.SubtaskStatusFinished, skipped.Status)
+	assert.Equal(t, subtaskSkippedResult, skipped.Result)
+
+	// the dependent subtask becomes ready once the skipped one is finished
+	planned, err = db.GetTaskPlannedSubtasks(ctx, testTaskID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, subtaskIDs(selectReadySubtasks(planned, planned, 3)))
+}
+
+func TestSkipSubtaskStarted(t *testing.T) {
+	db := newPlanQuerier(
+		database.Subtask{ID: 1, Status: database.SubtaskStatusWaiting},
+		database.Subtask{ID: 2, Status: database.SubtaskStatusCreated},
+	)
+	worker := &stubSubtaskWorker{id: 1, waiting: true}
+	stc := newTestSubtaskController(db, worker)
+
+	require.NoError(t, stc.SkipSubtask(context.Background()))
+	assert.True(t, worker.skipped, "started subtask is skipped by its worker to close its msg chain")
+	assert.Equal(t, []int64{1, 2}, plannedIDs(t, db))
+}
+
+func TestSelectReadySubtasksBlocked(t *testing.T) {
+	waiting := database.Subtask{ID: 1, Status: database.SubtaskStatusWaiting}
+	dependent := database.Subtask{ID: 2, Status: database.SubtaskStatusCreated, DependsOn: []int64{1}}
+
+	// the only runnable subtask depends on the waiting one, so it's not started before the user input
+	ready := selectReadySubtasks([]database.Subtask{waiting, dependent}, []database.Subtask{dependent}, 3)
+	assert.Empty(t, ready)
+}
+
+func TestPopSubtasksBlocked(t *testing.T) {
+	db := newPlanQuerier(
+		database.Subtask{ID: 1, Status: database.SubtaskStatusWaiting},
+		database.Subtask{ID: 2, Status: database.SubtaskStatusCreated, DependsOn: []int64{1}},
+	)
+	stc := newTestSubtaskController(db, &stubSubtaskWorker{id: 1, waiting: true})
+
+	workers, err := stc.PopSubtasks(context.Background(), nil, 3)
+	assert.ErrorIs(t, err, ErrSubtasksBlocked)
+	assert.Empty(t, workers)
+}
+
+func TestSkipSubtaskNothingPlanned(t *testing.T) {
+	db := newPlanQuerier(database.Subtask{ID: 1, Status: database.SubtaskStatusFinished})
+	stc := newTestSubtaskController(db)
+
+	err := stc.SkipSubtask(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no subtask to skip")
+}
+
+func TestSubtaskWorkerRetryNotCompleted(t *testing.T) {
+	for _, state := range []struct {
+		name    string
+		waiting bool
+	}{
+		{"running", false},
+		{"waiting", true},
+	} {
+		t.Run(state.name, func(t *testing.T) {
+			stw := &subtaskWorker{
+				mx:         &sync.RWMutex{},
+				subtaskCtx: &SubtaskContext{SubtaskID: 7},
+				waiting:    state.waiting,
+			}
+
+			err := stw.Retry(context.Background(), "try harder")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "has not completed yet")
+		})
+	}
+}
+
+func TestSubtaskWorkerSkipCompleted(t *testing.T) {
+	stw := &subtaskWorker{
+		mx:         &sync.RWMutex{},
+		subtaskCtx: &SubtaskContext{SubtaskID: 7},
+		completed:  true,
+	}
+
+	err := stw.Skip(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already completed")
+}
+
+func TestTaskWorkerSubtaskControls(t *testing.T) {
+	newTaskWorker := func(completed, waiting, planReview bool, workers ...SubtaskWorker) *taskWorker {
+		db := newPlanQuerier(database.Subtask{ID: 1, Status: database.SubtaskStatusFinished})
+		stc := newTestSubtaskController(db, workers...)
+		return &taskWorker{
+			mx:         &sync.RWMutex{},
+			stc:        stc,
+			taskCtx:    stc.taskCtx,
+			completed:  completed,
+			waiting:    waiting,
+			planReview: planReview,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		worker *taskWorker
+		call   func(ctx context.Context, tw *taskWorker) error
+		errMsg string
+	}{
+		{
+			name:   "retry in running task",
+			worker: newTaskWorker(false, false, false, &stubSubtaskWorker{id: 1, completed: true}),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.RetrySubtask(ctx, 1, "") },
+			errMsg: "is running",
+		},
+		{
+			name:   "retry in plan review",
+			worker: newTaskWorker(false, true, true, &stubSubtaskWorker{id: 1, completed: true}),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.RetrySubtask(ctx, 1, "") },
+			errMsg: "waiting for approval",
+		},
+		{
+			name: "retry while other subtask waits for input",
+			worker: newTaskWorker(false, true, false,
+				&stubSubtaskWorker{id: 1, completed: true},
+				&stubSubtaskWorker{id: 2, waiting: true},
+			),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.RetrySubtask(ctx, 1, "") },
+			errMsg: "subtask 2 is waiting for input",
+		},
+		{
+			name:   "retry not started subtask",
+			worker: newTaskWorker(true, false, false),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.RetrySubtask(ctx, 1, "") },
+			errMsg: "is not started or not found",
+		},
+		{
+			name:   "skip in completed task",
+			worker: newTaskWorker(true, false, false),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.SkipSubtask(ctx) },
+			errMsg: "has already completed",
+		},
+		{
+			name:   "skip in running task",
+			worker: newTaskWorker(false, false, false),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.SkipSubtask(ctx) },
+			errMsg: "is running",
+		},
+		{
+			name:   "insert in completed task",
+			worker: newTaskWorker(true, false, false),
+			call: func(ctx context.Context, tw *taskWorker) error {
+				return tw.InsertSubtask(ctx, "title", "description")
+			},
+			errMsg: "has already completed",
+		},
+		{
+			name:   "insert without description",
+			worker: newTaskWorker(false, false, false),
+			call:   func(ctx context.Context, tw *taskWorker) error { return tw.InsertSubtask(ctx, "title", " ") },
+			errMsg: "title and description are required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(context.Background(), tt.worker)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func subtaskIDs(subtasks []database.Subtask) []int64 {
+	ids := make([]int64, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		ids = append(ids, subtask.ID)
+	}
+	return ids
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"pentagi/pkg/database"
@@ -23,11 +24,17 @@ type TaskWorker interface {
 	GetTitle() string
 	IsCompleted() bool
 	IsWaiting() bool
+	IsPlanReview() bool
 	GetStatus(ctx context.Context) (database.TaskStatus, error)
 	SetStatus(ctx context.Context, status database.TaskStatus) error
 	GetResult(ctx context.Context) (string, error)
 	SetResult(ctx context.Context, result string) error
 	PutInput(ctx context.Context, input string) error
+	PatchPlan(ctx context.Context, patch tools.SubtaskPatch) error
+	ApprovePlan(ctx context.Context) error
+	RetrySubtask(ctx context.Context, subtaskID int64, instructions string) error
+	SkipSubtask(ctx context.Context) error
+	InsertSubtask(ctx context.Context, title, description string) error
 	Run(ctx context.Context) error
 	Finish(ctx context.Context) error
 }
@@ -39,12 +46,15 @@ type taskWorker struct {
 	updater   FlowUpdater
 	completed bool
 	waiting   bool
+	// planReview is set while the planned subtasks are waiting for the user approval
+	planReview bool
 }
 
 func NewTaskWorker(
 	ctx context.Context,
 	flowCtx *FlowContext,
 	input string,
+	plan []tools.SubtaskInfo,
 	updater FlowUpdater,
 ) (TaskWorker, error) {
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "controller.NewTaskWorker")
@@ -88,7 +98,12 @@ func NewTaskWorker(
 		return nil, fmt.Errorf("failed to put input for task %d: %w", taskCtx.TaskID, err)
 	}
 
-	err = stc.GenerateSubtasks(ctx)
+	// predefined plan skips the generator, e.g. for the playbook tasks
+	if len(plan) != 0 {
+		err = stc.SeedSubtasks(ctx, plan)
+	} else {
+		err = stc.GenerateSubtasks(ctx)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate subtasks: %w", err)
 	}
@@ -101,12 +116,13 @@ func NewTaskWorker(
 	flowCtx.Publisher.TaskUpdated(ctx, task, subtasks)
 
 	return &taskWorker{
-		mx:        &sync.RWMutex{},
-		stc:       stc,
-		taskCtx:   taskCtx,
-		updater:   updater,
-		completed: false,
-		waiting:   false,
+		mx:         &sync.RWMutex{},
+		stc:        stc,
+		taskCtx:    taskCtx,
+		updater:    updater,
+		completed:  false,
+		waiting:    false,
+		planReview: flowCtx.PlanReview,
 	}, nil
 }
 
@@ -152,6 +168,11 @@ func LoadTaskWorker(
 		return nil, fmt.Errorf("failed to load subtasks for task %d: %w", task.ID, err)
 	}
 
+	// the waiting task without started subtasks is still under the plan review
+	if waiting && flowCtx.PlanReview && len(tw.stc.ListSubtasks(ctx)) == 0 {
+		tw.planReview = true
+	}
+
 	return tw, nil
 }
 
@@ -185,6 +206,13 @@ func (tw *taskWorker) IsWaiting() bool {
 	return tw.waiting
 }
 
+func (tw *taskWorker) IsPlanReview() bool {
+	tw.mx.RLock()
+	defer tw.mx.RUnlock()
+
+	return tw.planReview
+}
+
 func (tw *taskWorker) GetStatus(ctx context.Context) (database.TaskStatus, error) {
 	task, err := tw.taskCtx.DB.GetTask(ctx, tw.taskCtx.TaskID)
 	if err != nil {
@@ -265,6 +293,10 @@ func (tw *taskWorker) PutInput(ctx context.Context, input string) error {
 		return fmt.Errorf("task is not waiting")
 	}
 
+	if tw.IsPlanReview() {
+		return fmt.Errorf("task plan is waiting for approval")
+	}
+
 	for _, st := range tw.stc.ListSubtasks(ctx) {
 		if !st.IsCompleted() && st.IsWaiting() {
 			if err := st.PutInput(ctx, input); err != nil {
@@ -278,28 +310,174 @@ func (tw *taskWorker) PutInput(ctx context.Context, input string) error {
 	return nil
 }
 
+// PatchPlan edits the planned subtasks while the task plan is under the user review
+func (tw *taskWorker) PatchPlan(ctx context.Context, patch tools.SubtaskPatch) error {
+	tw.mx.Lock()
+	defer tw.mx.Unlock()
+
+	if !tw.planReview {
+		return fmt.Errorf("task %d plan is not waiting for review", tw.taskCtx.TaskID)
+	}
+
+	if err := tw.stc.PatchSubtasks(ctx, patch); err != nil {
+		return err
+	}
+
+	return tw.publishUpdate(ctx)
+}
+
+// ApprovePlan releases the reviewed plan, the subtasks are executed by the next Run call
+func (tw *taskWorker) ApprovePlan(ctx context.Context) error {
+	tw.mx.Lock()
+	if !tw.planReview {
+		tw.mx.Unlock()
+		return fmt.Errorf("task %d plan is not waiting for review", tw.taskCtx.TaskID)
+	}
+	tw.planReview = false
+	tw.mx.Unlock()
+
+	return tw.SetStatus(ctx, database.TaskStatusRunning)
+}
+
+// RetrySubtask puts the completed subtask back to the plan to perform it again in the same msg chain,
+// the completed task is reopened; the caller is responsible to run the task after this call
+func (tw *taskWorker) RetrySubtask(ctx context.Context, subtaskID int64, instructions string) error {
+	if !tw.IsCompleted() && !tw.IsWaiting() {
+		return fmt.Errorf("task %d is running, stop it before retrying subtask", tw.taskCtx.TaskID)
+	}
+
+	if tw.IsPlanReview() {
+		return fmt.Errorf("task %d plan is waiting for approval", tw.taskCtx.TaskID)
+	}
+
+	for _, st := range tw.stc.ListSubtasks(ctx) {
+		if !st.IsCompleted() && st.IsWaiting() {
+			return fmt.Errorf("subtask %d is waiting for input, skip it before retrying another one", st.GetSubtaskID())
+		}
+	}
+
+	st, err := tw.stc.GetSubtask(ctx, subtaskID)
+	if err != nil {
+		return fmt.Errorf("subtask %d is not started or not found in task %d: %w", subtaskID, tw.taskCtx.TaskID, err)
+	}
+
+	if err := st.Retry(ctx, instructions); err != nil {
+		return err
+	}
+
+	return tw.SetStatus(ctx, database.TaskStatusRunning)
+}
+
+// SkipSubtask completes the current subtask without result, the caller is responsible to stop
+// the running task before and to run it after this call
+func (tw *taskWorker) SkipSubtask(ctx context.Context) error {
+	if tw.IsCompleted() {
+		return fmt.Errorf("task %d has already completed", tw.taskCtx.TaskID)
+	}
+
+	if !tw.IsWaiting() {
+		return fmt.Errorf("task %d is running, stop it before skipping subtask", tw.taskCtx.TaskID)
+	}
+
+	if tw.IsPlanReview() {
+		return fmt.Errorf("task %d plan is waiting for approval", tw.taskCtx.TaskID)
+	}
+
+	if err := tw.stc.SkipSubtask(ctx); err != nil {
+		return fmt.Errorf("failed to skip subtask of task %d: %w", tw.taskCtx.TaskID, err)
+	}
+
+	return tw.SetStatus(ctx, database.TaskStatusRunning)
+}
+
+// InsertSubtask adds the new subtask right after the current one, it's allowed for the running task
+// and the next refinement is skipped to perform the user plan as is
+func (tw *taskWorker) InsertSubtask(ctx context.Context, title, description string) error {
+	if tw.IsCompleted() {
+		return fmt.Errorf("task %d has already completed", tw.taskCtx.TaskID)
+	}
+
+	if strings.TrimSpace(title) == "" || strings.TrimSpace(description) == "" {
+		return fmt.Errorf("subtask title and description are required")
+	}
+
+	err := tw.stc.InsertSubtask(ctx, tools.SubtaskInfo{
+		Title:       title,
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert subtask to task %d: %w", tw.taskCtx.TaskID, err)
+	}
+
+	return tw.publishUpdate(ctx)
+}
+
+func (tw *taskWorker) publishUpdate(ctx context.Context) error {
+	task, err := tw.taskCtx.DB.GetTask(ctx, tw.taskCtx.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %d: %w", tw.taskCtx.TaskID, err)
+	}
+
+	subtasks, err := tw.taskCtx.DB.GetTaskSubtasks(ctx, tw.taskCtx.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task %d subtasks: %w", tw.taskCtx.TaskID, err)
+	}
+
+	tw.taskCtx.Publisher.TaskUpdated(ctx, task, subtasks)
+
+	return nil
+}
+
 func (tw *taskWorker) Run(ctx context.Context) error {
 	ctx = tools.PutAgentContext(ctx, database.MsgchainTypePrimaryAgent)
+	ctx = providers.PutTaskWatchdog(ctx)
+
+	// generated plan is shown to the user and waits for approval before execution
+	if tw.IsPlanReview() {
+		return tw.SetStatus(ctx, database.TaskStatusWaiting)
+	}
 
 	for len(tw.stc.ListSubtasks(ctx)) < providers.TasksNumberLimit+3 {
-		st, err := tw.stc.PopSubtask(ctx, tw)
-		if err != nil {
+		// remaining subtasks are not started after the time limit, the task reports collected results
+		if providers.IsDeadlineExceeded(ctx) {
+			break
+		}
+
+		sts, err := tw.stc.PopSubtasks(ctx, tw, tw.taskCtx.ParallelLimit)
+		if errors.Is(err, ErrSubtasksBlocked) {
+			// the task is resumed by the user input to the subtask which the rest of the plan depends on
+			return tw.SetStatus(ctx, database.TaskStatusWaiting)
+		} else if err != nil {
 			return err
 		}
 
 		// empty queue for subtasks means that task is done
-		if st == nil {
+		if len(sts) == 0 {
+			// other subtasks are done but some of them still wait for the user input
+			if tw.hasWaitingSubtasks(ctx) {
+				return tw.SetStatus(ctx, database.TaskStatusWaiting)
+			}
 			break
 		}
 
-		if err := st.Run(ctx); err != nil {
+		if err := tw.runSubtasks(ctx, sts); err != nil {
 			return err
 		}
 
 		// pass through if task is waiting from back status propagation
 		if tw.IsWaiting() {
 			return nil
-		} // otherwise subtask is done
+		} // otherwise subtasks are done
+
+		// concurrent subtask could set running status after another one started waiting
+		if tw.hasWaitingSubtasks(ctx) {
+			return tw.SetStatus(ctx, database.TaskStatusWaiting)
+		}
+
+		// the plan isn't refined if no more subtasks are started
+		if providers.IsDeadlineExceeded(ctx) {
+			break
+		}
 
 		if err := tw.stc.RefineSubtasks(ctx); err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -347,6 +525,43 @@ func (tw *taskWorker) Run(ctx context.Context) error {
 	return nil
 }
 
+// runSubtasks executes the batch of ready subtasks, concurrent subtasks use separate
+// working directories in the container and results are merged before the refinement
+func (tw *taskWorker) runSubtasks(ctx context.Context, sts []SubtaskWorker) error {
+	if len(sts) == 1 {
+		return sts[0].Run(ctx)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(sts))
+	)
+
+	for idx, st := range sts {
+		wg.Add(1)
+		go func(idx int, st SubtaskWorker) {
+			defer wg.Done()
+
+			stCtx := tools.PutWorkDir(ctx, tools.SubtaskWorkDir(st.GetSubtaskID()))
+			errs[idx] = st.Run(stCtx)
+		}(idx, st)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (tw *taskWorker) hasWaitingSubtasks(ctx context.Context) bool {
+	for _, st := range tw.stc.ListSubtasks(ctx) {
+		if !st.IsCompleted() && st.IsWaiting() {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (tw *taskWorker) Finish(ctx context.Context) error {
 	if tw.IsCompleted() {
 		return fmt.Errorf("task has already completed")
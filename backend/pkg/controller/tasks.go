@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+
+	"pentagi/pkg/tools"
 )
 
 type TaskController interface {
-	CreateTask(ctx context.Context, input string, updater FlowUpdater) (TaskWorker, error)
+	CreateTask(ctx context.Context, input string, plan []tools.SubtaskInfo, updater FlowUpdater) (TaskWorker, error)
 	LoadTasks(ctx context.Context, flowID int64, updater FlowUpdater) error
 	ListTasks(ctx context.Context) []TaskWorker
 	GetTask(ctx context.Context, taskID int64) (TaskWorker, error)
@@ -66,12 +68,13 @@ func (tc *taskController) LoadTasks(
 func (tc *taskController) CreateTask(
 	ctx context.Context,
 	input string,
+	plan []tools.SubtaskInfo,
 	updater FlowUpdater,
 ) (TaskWorker, error) {
 	tc.mx.Lock()
 	defer tc.mx.Unlock()
 
-	tw, err := NewTaskWorker(ctx, tc.flowCtx, input, updater)
+	tw, err := NewTaskWorker(ctx, tc.flowCtx, input, plan, updater)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task worker: %w", err)
 	}
@@ -0,0 +1,135 @@
+package csum
+
+import (
+	"context"
+	"fmt"
+
+	"pentagi/pkg/cast"
+	"pentagi/pkg/tools"
+
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+// Default configuration constants for the token budget of the summarization algorithm
+const (
+	// budgetTriggerPercent defines the share of available tokens after which the chain is summarized (80%)
+	budgetTriggerPercent = 80
+
+	// budgetLastSectionPercent defines the share of available tokens for the last section (50%)
+	budgetLastSectionPercent = 50
+
+	// budgetBodyPairPercent defines the share of available tokens for a single body pair (15%)
+	budgetBodyPairPercent = 15
+
+	// budgetQAPairPercent defines the share of available tokens for QA pair sections (75%)
+	budgetQAPairPercent = 75
+
+	// minAvailablePercent defines the minimum share of the context window available for the chain
+	// when the completion reserve is too large for the model (50%)
+	minAvailablePercent = 50
+
+	// emergencyBudgetPercent defines the share of the chain size which is kept after the provider
+	// rejected the chain because of the context length (60%)
+	emergencyBudgetPercent = 60
+
+	// defaultBytesPerToken is used for the emergency budget when the model tokenizer is unknown
+	defaultBytesPerToken = 3.0
+)
+
+// ContextBudget describes the context window of the active model in tokens,
+// the reserve covers the model completion and tool definitions which aren't part of the chain
+type ContextBudget struct {
+	ContextWindow int
+	ReserveTokens int
+	BytesPerToken float64
+}
+
+// IsEnabled returns true if the model context window is known
+func (b ContextBudget) IsEnabled() bool {
+	return b.ContextWindow > 0 && b.BytesPerToken > 0
+}
+
+// AvailableTokens returns the number of tokens which the chain may use
+func (b ContextBudget) AvailableTokens() int {
+	return max(b.ContextWindow-b.ReserveTokens, b.ContextWindow*minAvailablePercent/100)
+}
+
+// EstimateTokens converts the size of the chain in bytes to the estimated number of tokens
+func (b ContextBudget) EstimateTokens(size int) int {
+	if b.BytesPerToken <= 0 {
+		return 0
+	}
+	return int(float64(size) / b.BytesPerToken)
+}
+
+func (b ContextBudget) availableBytes(percent int) int {
+	return int(float64(b.AvailableTokens()*percent/100) * b.BytesPerToken)
+}
+
+// NewEmergencyBudget returns a budget which is used after the provider rejected the chain
+// because of the context length, so the current chain size is a known upper bound of the context window
+func NewEmergencyBudget(budget ContextBudget, chain []llms.MessageContent) (ContextBudget, error) {
+	ast, err := cast.NewChainAST(chain, true)
+	if err != nil {
+		return budget, fmt.Errorf("failed to create ChainAST: %w", err)
+	}
+
+	if budget.BytesPerToken <= 0 {
+		budget.BytesPerToken = defaultBytesPerToken
+	}
+
+	chainTokens := budget.EstimateTokens(ast.Size())
+	if budget.IsEnabled() {
+		chainTokens = min(chainTokens, budget.AvailableTokens())
+	}
+
+	return ContextBudget{
+		ContextWindow: max(chainTokens*emergencyBudgetPercent/100, 1),
+		BytesPerToken: budget.BytesPerToken,
+	}, nil
+}
+
+// withBudget derives the size limits of the summarization strategies from the context budget
+func (config SummarizerConfig) withBudget(budget ContextBudget) SummarizerConfig {
+	config.LastSecBytes = budget.availableBytes(budgetLastSectionPercent)
+	config.MaxBPBytes = budget.availableBytes(budgetBodyPairPercent)
+	config.MaxQABytes = budget.availableBytes(budgetQAPairPercent)
+
+	return config
+}
+
+// fitContextBudget is the last resort step which is used when the chain still exceeds the budget
+// after the configured strategies: it collapses all previous sections and rotates the last one
+func fitContextBudget(
+	ctx context.Context,
+	ast *cast.ChainAST,
+	handler tools.SummarizeHandler,
+	budget ContextBudget,
+) error {
+	if budget.EstimateTokens(ast.Size()) <= budget.AvailableTokens() {
+		return nil
+	}
+
+	if err := summarizeSections(ctx, ast, handler, keepMinLastQASections); err != nil {
+		return fmt.Errorf("failed to summarize sections: %w", err)
+	}
+
+	lastSectionBytes := budget.availableBytes(budgetLastSectionPercent)
+	bodyPairBytes := budget.availableBytes(budgetBodyPairPercent)
+	err := summarizeLastSection(ctx, ast, handler, len(ast.Sections)-1,
+		lastSectionBytes, bodyPairBytes, lastSectionReservePercentage)
+	if err != nil {
+		return fmt.Errorf("failed to summarize last section: %w", err)
+	}
+
+	if budget.EstimateTokens(ast.Size()) <= budget.AvailableTokens() {
+		return nil
+	}
+
+	maxBytes := budget.availableBytes(budgetQAPairPercent)
+	if err := summarizeQAPairs(ctx, ast, handler, len(ast.Sections), maxBytes, true); err != nil {
+		return fmt.Errorf("failed to summarize QA pairs: %w", err)
+	}
+
+	return nil
+}
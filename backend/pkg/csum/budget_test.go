@@ -0,0 +1,108 @@
+package csum
+
+import (
+	"context"
+	"testing"
+
+	"pentagi/pkg/cast"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBudgetTestChain(t *testing.T, sections, pairs, pairSize int) *cast.ChainAST {
+	t.Helper()
+
+	ast := &cast.ChainAST{}
+	addBodyPairsToLastSection(pairs, pairSize)(t, ast)
+	for i := 1; i < sections; i++ {
+		addNewSection("Question", pairs, pairSize)(t, ast)
+	}
+
+	return ast
+}
+
+func TestContextBudget(t *testing.T) {
+	budget := ContextBudget{ContextWindow: 8000, ReserveTokens: 2000, BytesPerToken: 4}
+	assert.True(t, budget.IsEnabled())
+	assert.Equal(t, 6000, budget.AvailableTokens())
+	assert.Equal(t, 250, budget.EstimateTokens(1000))
+
+	// the reserve larger than the window keeps a half of the window available
+	budget.ReserveTokens = 7000
+	assert.Equal(t, 4000, budget.AvailableTokens())
+
+	assert.False(t, ContextBudget{}.IsEnabled())
+	assert.Equal(t, 0, ContextBudget{}.EstimateTokens(1000))
+
+	config := SummarizerConfig{LastSecBytes: 1, MaxBPBytes: 1, MaxQABytes: 1}.withBudget(
+		ContextBudget{ContextWindow: 1_000_000, ReserveTokens: 0, BytesPerToken: 4},
+	)
+	assert.Equal(t, 2_000_000, config.LastSecBytes)
+	assert.Equal(t, 600_000, config.MaxBPBytes)
+	assert.Equal(t, 3_000_000, config.MaxQABytes)
+}
+
+func TestSummarizeChainWithBudget(t *testing.T) {
+	ctx := context.Background()
+	config := SummarizerConfig{PreserveLast: true, UseQA: true, KeepQASections: 1}
+
+	t.Run("large window skips summarization", func(t *testing.T) {
+		ast := newBudgetTestChain(t, 5, 4, 2*1024)
+		mock := newMockSummarizer("summary", nil, nil)
+
+		budget := ContextBudget{ContextWindow: 1_000_000, ReserveTokens: 8000, BytesPerToken: 3.8}
+		chain, err := NewSummarizer(config).WithContextBudget(budget).SummarizeChain(ctx, mock.SummarizerHandler(), ast.Messages())
+		require.NoError(t, err)
+		assert.False(t, mock.called, "Summarizer must not be called for chains far from the context window")
+		assert.Equal(t, len(ast.Messages()), len(chain))
+	})
+
+	t.Run("small window fits the chain", func(t *testing.T) {
+		ast := newBudgetTestChain(t, 5, 4, 2*1024)
+		mock := newMockSummarizer("summary", nil, nil)
+
+		budget := ContextBudget{ContextWindow: 8192, ReserveTokens: 2048, BytesPerToken: 3.6}
+		chain, err := NewSummarizer(config).WithContextBudget(budget).SummarizeChain(ctx, mock.SummarizerHandler(), ast.Messages())
+		require.NoError(t, err)
+		assert.True(t, mock.called)
+
+		result, err := cast.NewChainAST(chain, false)
+		require.NoError(t, err)
+		verifyASTConsistency(t, result)
+		assert.LessOrEqual(t, budget.EstimateTokens(result.Size()), budget.AvailableTokens())
+	})
+
+	t.Run("disabled budget keeps byte limits", func(t *testing.T) {
+		s := NewSummarizer(config).WithContextBudget(ContextBudget{}).(*summarizer)
+		assert.Equal(t, maxLastSectionByteSize, s.config.LastSecBytes)
+		assert.False(t, s.budget.IsEnabled())
+	})
+}
+
+func TestNewEmergencyBudget(t *testing.T) {
+	ast := newBudgetTestChain(t, 3, 4, 3*1024)
+	chain := ast.Messages()
+
+	// unknown model uses the default tokenizer estimation
+	budget, err := NewEmergencyBudget(ContextBudget{}, chain)
+	require.NoError(t, err)
+	assert.True(t, budget.IsEnabled())
+	assert.Equal(t, defaultBytesPerToken, budget.BytesPerToken)
+	assert.Equal(t, int(float64(ast.Size())/defaultBytesPerToken)*emergencyBudgetPercent/100, budget.AvailableTokens())
+
+	// known model budget is shrunk below the available tokens
+	known := ContextBudget{ContextWindow: 4000, ReserveTokens: 1000, BytesPerToken: 4}
+	budget, err = NewEmergencyBudget(known, chain)
+	require.NoError(t, err)
+	assert.Equal(t, 3000*emergencyBudgetPercent/100, budget.AvailableTokens())
+
+	mock := newMockSummarizer("summary", nil, nil)
+	result, err := NewSummarizer(SummarizerConfig{}).WithContextBudget(budget).SummarizeChain(
+		context.Background(), mock.SummarizerHandler(), chain,
+	)
+	require.NoError(t, err)
+	resultAST, err := cast.NewChainAST(result, false)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, budget.EstimateTokens(resultAST.Size()), budget.AvailableTokens())
+}
@@ -8,8 +8,10 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"pentagi/pkg/cast"
+	obs "pentagi/pkg/observability"
 	"pentagi/pkg/tools"
 
 	"github.com/vxcontrol/langchaingo/llms"
@@ -67,10 +69,15 @@ type Summarizer interface {
 		handler tools.SummarizeHandler,
 		chain []llms.MessageContent,
 	) ([]llms.MessageContent, error)
+
+	// WithContextBudget returns a summarizer which derives size limits from the active model
+	// context window, the chain is summarized only when it approaches the budget
+	WithContextBudget(budget ContextBudget) Summarizer
 }
 
 type summarizer struct {
 	config SummarizerConfig
+	budget ContextBudget
 }
 
 // NewSummarizer creates a new summarizer with the given configuration
@@ -101,6 +108,14 @@ func NewSummarizer(config SummarizerConfig) Summarizer {
 	return &summarizer{config: config}
 }
 
+func (s *summarizer) WithContextBudget(budget ContextBudget) Summarizer {
+	if !budget.IsEnabled() {
+		return &summarizer{config: s.config}
+	}
+
+	return &summarizer{config: s.config.withBudget(budget), budget: budget}
+}
+
 // SummarizeChain takes a message chain and summarizes old messages to prevent context from growing too large
 // Uses ChainAST with size tracking for efficient summarization decisions
 func (s *summarizer) SummarizeChain(
@@ -119,6 +134,14 @@ func (s *summarizer) SummarizeChain(
 		return chain, fmt.Errorf("failed to create ChainAST: %w", err)
 	}
 
+	// Skip summarization while the chain is far from the model context window
+	if s.budget.IsEnabled() {
+		threshold := s.budget.AvailableTokens() * budgetTriggerPercent / 100
+		if s.budget.EstimateTokens(ast.Size()) <= threshold {
+			return chain, nil
+		}
+	}
+
 	// Apply different summarization strategies sequentially
 	// Each function modifies the ast directly
 	cfg := s.config
@@ -150,6 +173,13 @@ func (s *summarizer) SummarizeChain(
 		}
 	}
 
+	// 3. Fit the chain into the model context window if the strategies above weren't enough
+	if s.budget.IsEnabled() {
+		if err = fitContextBudget(ctx, ast, handler, s.budget); err != nil {
+			return chain, fmt.Errorf("failed to fit context budget: %w", err)
+		}
+	}
+
 	return ast.Messages(), nil
 }
 
@@ -741,7 +771,9 @@ func GenerateSummary(
 	text := messagesToPrompt(humanMessages, aiMessages)
 
 	// Generate the summary using provided summarizer handler
+	start := time.Now()
 	summary, err := handler(ctx, text)
+	obs.Observer.RecordSummarization(ctx, obs.SummarizationKindChain, time.Since(start), err)
 	if err != nil {
 		return "", fmt.Errorf("summarization failed: %w", err)
 	}
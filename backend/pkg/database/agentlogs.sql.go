@@ -8,6 +8,8 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createAgentLog = `-- name: CreateAgentLog :one
@@ -133,6 +135,150 @@ func (q *Queries) GetFlowAgentLogs(ctx context.Context, flowID int64) ([]Agentlo
 	return items, nil
 }
 
+const getFlowAgentLogsAsc = `-- name: GetFlowAgentLogsAsc :many
+SELECT
+  al.id, al.initiator, al.executor, al.task, al.result, al.flow_id, al.task_id, al.subtask_id, al.created_at
+FROM agentlogs al
+INNER JOIN flows f ON al.flow_id = f.id
+WHERE al.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR al.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR al.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR al.executor::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR al.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR al.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR al.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR al.id < $8::BIGINT)
+ORDER BY al.id ASC
+LIMIT $9
+`
+
+type GetFlowAgentLogsAscParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Executors     []string      `json:"executors"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowAgentLogsAsc(ctx context.Context, arg GetFlowAgentLogsAscParams) ([]Agentlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowAgentLogsAsc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Executors),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Agentlog
+	for rows.Next() {
+		var i Agentlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Task,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlowAgentLogsDesc = `-- name: GetFlowAgentLogsDesc :many
+SELECT
+  al.id, al.initiator, al.executor, al.task, al.result, al.flow_id, al.task_id, al.subtask_id, al.created_at
+FROM agentlogs al
+INNER JOIN flows f ON al.flow_id = f.id
+WHERE al.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR al.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR al.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR al.executor::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR al.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR al.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR al.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR al.id < $8::BIGINT)
+ORDER BY al.id DESC
+LIMIT $9
+`
+
+type GetFlowAgentLogsDescParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Executors     []string      `json:"executors"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowAgentLogsDesc(ctx context.Context, arg GetFlowAgentLogsDescParams) ([]Agentlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowAgentLogsDesc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Executors),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Agentlog
+	for rows.Next() {
+		var i Agentlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Task,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubtaskAgentLogs = `-- name: GetSubtaskAgentLogs :many
 SELECT
   al.id, al.initiator, al.executor, al.task, al.result, al.flow_id, al.task_id, al.subtask_id, al.created_at
@@ -1,13 +1,16 @@
 package converter
 
 import (
+	"database/sql"
 	"encoding/json"
 	"pentagi/pkg/database"
 	"pentagi/pkg/graph/model"
+	"pentagi/pkg/playbook"
 	"pentagi/pkg/providers/pconfig"
 	"pentagi/pkg/providers/tester"
 	"pentagi/pkg/providers/tester/testdata"
 	"pentagi/pkg/templates"
+	"pentagi/pkg/tools"
 
 	"github.com/vxcontrol/langchaingo/llms"
 )
@@ -32,13 +35,15 @@ func ConvertFlow(flow database.Flow, containers []database.Container) *model.Flo
 		Type: model.ProviderType(flow.ModelProviderType),
 	}
 	return &model.Flow{
-		ID:        flow.ID,
-		Title:     flow.Title,
-		Status:    model.StatusType(flow.Status),
-		Terminals: ConvertContainers(containers),
-		Provider:  provider,
-		CreatedAt: flow.CreatedAt.Time,
-		UpdatedAt: flow.UpdatedAt.Time,
+		ID:         flow.ID,
+		Title:      flow.Title,
+		Status:     model.StatusType(flow.Status),
+		Terminals:  ConvertContainers(containers),
+		Provider:   provider,
+		PlanReview: flow.PlanReview,
+		TeamID:     convertTeamID(flow.TeamID),
+		CreatedAt:  flow.CreatedAt.Time,
+		UpdatedAt:  flow.UpdatedAt.Time,
 	}
 }
 
@@ -107,6 +112,7 @@ func ConvertSubtask(subtask database.Subtask) *model.Subtask {
 		Description: subtask.Description,
 		Result:      subtask.Result,
 		TaskID:      subtask.TaskID,
+		DependsOn:   subtask.DependsOn,
 		CreatedAt:   subtask.CreatedAt.Time,
 		UpdatedAt:   subtask.UpdatedAt.Time,
 	}
@@ -388,6 +394,7 @@ func ConvertPrompt(prompt database.Prompt) *model.UserPrompt {
 		ID:        prompt.ID,
 		Type:      model.PromptType(prompt.Type),
 		Template:  prompt.Prompt,
+		TeamID:    convertTeamID(prompt.TeamID),
 		CreatedAt: prompt.CreatedAt.Time,
 		UpdatedAt: prompt.UpdatedAt.Time,
 	}
@@ -429,6 +436,7 @@ func ConvertProvider(prv database.Provider, cfg *pconfig.ProviderConfig) *model.
 		Name:      prv.Name,
 		Type:      model.ProviderType(prv.Type),
 		Agents:    ConvertProviderConfigToGqlModel(cfg),
+		TeamID:    convertTeamID(prv.TeamID),
 		CreatedAt: prv.CreatedAt.Time,
 		UpdatedAt: prv.UpdatedAt.Time,
 	}
@@ -548,6 +556,36 @@ func ConvertAgentsConfigFromGqlModel(cfg *model.AgentsConfig) *pconfig.ProviderC
 	return pc
 }
 
+func ConvertSubtaskPatchFromGqlModel(operations []*model.SubtaskOperationInput) tools.SubtaskPatch {
+	patch := tools.SubtaskPatch{
+		Operations: make([]tools.SubtaskOperation, 0, len(operations)),
+		Message:    "user edited the task plan",
+	}
+
+	for _, op := range operations {
+		if op == nil {
+			continue
+		}
+
+		operation := tools.SubtaskOperation{
+			Op:        tools.SubtaskOperationType(op.Op),
+			ID:        op.ID,
+			AfterID:   op.AfterID,
+			DependsOn: op.DependsOn,
+		}
+		if op.Title != nil {
+			operation.Title = *op.Title
+		}
+		if op.Description != nil {
+			operation.Description = *op.Description
+		}
+
+		patch.Operations = append(patch.Operations, operation)
+	}
+
+	return patch
+}
+
 func ConvertAgentConfigFromGqlModel(ac *model.AgentConfig) *pconfig.AgentConfig {
 	if ac == nil {
 		return nil
@@ -671,3 +709,391 @@ func ConvertProviderTestResults(results tester.ProviderTestResults) *model.Provi
 		Pentester:    ConvertTestResults(results.Pentester),
 	}
 }
+
+func ConvertPlaybooks(playbooks []database.Playbook) []*model.Playbook {
+	gplaybooks := make([]*model.Playbook, 0, len(playbooks))
+	for _, pb := range playbooks {
+		gplaybooks = append(gplaybooks, ConvertPlaybook(pb))
+	}
+
+	return gplaybooks
+}
+
+func ConvertPlaybook(pb database.Playbook) *model.Playbook {
+	gplaybook := &model.Playbook{
+		ID:          pb.ID,
+		Name:        pb.Name,
+		Description: pb.Description,
+		Content:     pb.Content,
+		Tools:       []string{},
+		Variables:   []*model.PlaybookVariable{},
+		TeamID:      convertTeamID(pb.TeamID),
+		CreatedAt:   pb.CreatedAt.Time,
+		UpdatedAt:   pb.UpdatedAt.Time,
+	}
+
+	// content is validated before storing, so the parse error is possible only for legacy documents
+	doc, err := playbook.Parse(pb.Content)
+	if err != nil {
+		return gplaybook
+	}
+
+	if doc.Image != "" {
+		gplaybook.Image = &doc.Image
+	}
+	if len(doc.Tools) != 0 {
+		gplaybook.Tools = doc.Tools
+	}
+	for _, variable := range doc.Variables {
+		gplaybook.Variables = append(gplaybook.Variables, &model.PlaybookVariable{
+			Name:        variable.Name,
+			Description: variable.Description,
+			Default:     variable.Default,
+			Required:    variable.Required,
+		})
+	}
+	gplaybook.Tasks = len(doc.Tasks)
+
+	return gplaybook
+}
+
+func ConvertSchedules(schedules []database.Schedule) []*model.Schedule {
+	gschedules := make([]*model.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		gschedules = append(gschedules, ConvertSchedule(schedule))
+	}
+
+	return gschedules
+}
+
+func ConvertSchedule(schedule database.Schedule) *model.Schedule {
+	gschedule := &model.Schedule{
+		ID:         schedule.ID,
+		Name:       schedule.Name,
+		Cron:       schedule.Cron,
+		Timezone:   schedule.Timezone,
+		Input:      schedule.Input,
+		PlaybookID: database.NullInt64ToInt64(schedule.PlaybookID),
+		Scope:      schedule.Scope,
+		Provider: &model.Provider{
+			Name: schedule.ModelProviderName,
+			Type: model.ProviderType(schedule.ModelProviderType),
+		},
+		PlanReview:   schedule.PlanReview,
+		MissedPolicy: model.ScheduleMissedPolicy(schedule.MissedPolicy),
+		Enabled:      schedule.Enabled,
+		CreatedAt:    schedule.CreatedAt.Time,
+		UpdatedAt:    schedule.UpdatedAt.Time,
+	}
+
+	if schedule.NextRunAt.Valid {
+		gschedule.NextRunAt = &schedule.NextRunAt.Time
+	}
+	if schedule.LastRunAt.Valid {
+		gschedule.LastRunAt = &schedule.LastRunAt.Time
+	}
+
+	return gschedule
+}
+
+func ConvertScheduleRuns(runs []database.ScheduleRun) []*model.ScheduleRun {
+	gruns := make([]*model.ScheduleRun, 0, len(runs))
+	for _, run := range runs {
+		gruns = append(gruns, &model.ScheduleRun{
+			ID:          run.ID,
+			ScheduleID:  run.ScheduleID,
+			FlowID:      database.NullInt64ToInt64(run.FlowID),
+			Status:      model.ScheduleRunStatus(run.Status),
+			Reason:      run.Reason,
+			ScheduledAt: run.ScheduledAt,
+			CreatedAt:   run.CreatedAt.Time,
+		})
+	}
+
+	return gruns
+}
+
+func ConvertCampaigns(campaigns []database.Campaign) []*model.Campaign {
+	gcampaigns := make([]*model.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		gcampaigns = append(gcampaigns, ConvertCampaign(campaign))
+	}
+
+	return gcampaigns
+}
+
+func ConvertCampaign(campaign database.Campaign) *model.Campaign {
+	return &model.Campaign{
+		ID:         campaign.ID,
+		Name:       campaign.Name,
+		Status:     model.CampaignStatus(campaign.Status),
+		Input:      campaign.Input,
+		PlaybookID: database.NullInt64ToInt64(campaign.PlaybookID),
+		Scope:      campaign.Scope,
+		Provider: &model.Provider{
+			Name: campaign.ModelProviderName,
+			Type: model.ProviderType(campaign.ModelProviderType),
+		},
+		PlanReview:  campaign.PlanReview,
+		Concurrency: int(campaign.Concurrency),
+		CreatedAt:   campaign.CreatedAt.Time,
+		UpdatedAt:   campaign.UpdatedAt.Time,
+	}
+}
+
+func ConvertCampaignTargets(targets []database.CampaignTarget, flows []database.Flow) []*model.CampaignTarget {
+	statuses := make(map[int64]model.StatusType, len(flows))
+	for _, flow := range flows {
+		statuses[flow.ID] = model.StatusType(flow.Status)
+	}
+
+	gtargets := make([]*model.CampaignTarget, 0, len(targets))
+	for _, target := range targets {
+		gtarget := &model.CampaignTarget{
+			ID:         target.ID,
+			CampaignID: target.CampaignID,
+			Target:     target.Target,
+			Status:     model.CampaignTargetStatus(target.Status),
+			FlowID:     database.NullInt64ToInt64(target.FlowID),
+			Reason:     target.Reason,
+			CreatedAt:  target.CreatedAt.Time,
+			UpdatedAt:  target.UpdatedAt.Time,
+		}
+		if status, ok := statuses[target.FlowID.Int64]; ok && target.FlowID.Valid {
+			gtarget.FlowStatus = &status
+		}
+		gtargets = append(gtargets, gtarget)
+	}
+
+	return gtargets
+}
+
+func ConvertCampaignFindings(findings []database.GetCampaignFindingsRow) []*model.CampaignFinding {
+	gfindings := make([]*model.CampaignFinding, 0, len(findings))
+	for _, finding := range findings {
+		gfindings = append(gfindings, &model.CampaignFinding{
+			Target:    finding.Target,
+			FlowID:    finding.FlowID,
+			TaskID:    finding.ID,
+			Title:     finding.Title,
+			Result:    finding.Result,
+			UpdatedAt: finding.UpdatedAt.Time,
+		})
+	}
+
+	return gfindings
+}
+
+func ConvertFlowQueueItem(item database.GetFlowQueueRow, position int) *model.FlowQueueItem {
+	return &model.FlowQueueItem{
+		FlowID: item.FlowID,
+		UserID: item.UserID,
+		Provider: &model.Provider{
+			Name: item.ModelProviderName,
+			Type: model.ProviderType(item.ModelProviderType),
+		},
+		Priority:  model.FlowPriority(item.Priority),
+		Position:  position,
+		CreatedAt: item.CreatedAt.Time,
+	}
+}
+
+func ConvertFlowLimits(limits database.FlowLimit) *model.FlowLimits {
+	return &model.FlowLimits{
+		FlowTimeLimit:      int(limits.FlowTimeLimit),
+		TaskTimeLimit:      int(limits.TaskTimeLimit),
+		SubtaskTimeLimit:   int(limits.SubtaskTimeLimit),
+		MaxIterations:      int(limits.MaxIterations),
+		MaxToolCalls:       int(limits.MaxToolCalls),
+		MaxDelegationDepth: int(limits.MaxDelegationDepth),
+	}
+}
+
+func ConvertWebhooks(webhooks []database.Webhook) []*model.Webhook {
+	gwebhooks := make([]*model.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		gwebhooks = append(gwebhooks, ConvertWebhook(webhook))
+	}
+
+	return gwebhooks
+}
+
+func ConvertWebhook(webhook database.Webhook) *model.Webhook {
+	events := make([]model.WebhookEvent, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		events = append(events, model.WebhookEvent(event))
+	}
+
+	return &model.Webhook{
+		ID:        webhook.ID,
+		UserID:    database.NullInt64ToInt64(webhook.UserID),
+		Name:      webhook.Name,
+		URL:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    events,
+		Enabled:   webhook.Enabled,
+		CreatedAt: webhook.CreatedAt.Time,
+		UpdatedAt: webhook.UpdatedAt.Time,
+	}
+}
+
+func ConvertWebhookDeliveries(deliveries []database.WebhookDelivery) []*model.WebhookDelivery {
+	gdeliveries := make([]*model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		gdeliveries = append(gdeliveries, ConvertWebhookDelivery(delivery))
+	}
+
+	return gdeliveries
+}
+
+func ConvertWebhookDelivery(delivery database.WebhookDelivery) *model.WebhookDelivery {
+	gdelivery := &model.WebhookDelivery{
+		ID:           delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		FlowID:       database.NullInt64ToInt64(delivery.FlowID),
+		Event:        delivery.Event,
+		Payload:      string(delivery.Payload),
+		Status:       model.WebhookDeliveryStatus(delivery.Status),
+		Attempts:     int(delivery.Attempts),
+		ResponseCode: int(delivery.ResponseCode),
+		Error:        delivery.Error,
+		CreatedAt:    delivery.CreatedAt.Time,
+		UpdatedAt:    delivery.UpdatedAt.Time,
+	}
+
+	if delivery.NextAttemptAt.Valid {
+		gdelivery.NextAttemptAt = &delivery.NextAttemptAt.Time
+	}
+
+	return gdelivery
+}
+
+func ConvertAPIKeys(keys []database.ApiKey) []*model.APIKey {
+	gkeys := make([]*model.APIKey, 0, len(keys))
+	for _, key := range keys {
+		gkeys = append(gkeys, ConvertAPIKey(key))
+	}
+
+	return gkeys
+}
+
+func ConvertAPIKey(key database.ApiKey) *model.APIKey {
+	gkey := &model.APIKey{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		KeyPrefix:  key.KeyPrefix,
+		Privileges: key.Privileges,
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  key.CreatedAt.Time,
+	}
+
+	if gkey.Privileges == nil {
+		gkey.Privileges = []string{}
+	}
+	if key.LastUsedAt.Valid {
+		gkey.LastUsedAt = &key.LastUsedAt.Time
+	}
+	if key.RevokedAt.Valid {
+		gkey.RevokedAt = &key.RevokedAt.Time
+	}
+
+	return gkey
+}
+
+func ConvertRoles(roles []database.GetRolesRow) []*model.Role {
+	groles := make([]*model.Role, 0, len(roles))
+	for _, role := range roles {
+		groles = append(groles, ConvertRole(database.GetRoleRow(role)))
+	}
+
+	return groles
+}
+
+func ConvertRole(role database.GetRoleRow) *model.Role {
+	grole := &model.Role{
+		ID:         role.ID,
+		Name:       role.Name,
+		Privileges: role.Privileges,
+	}
+
+	if grole.Privileges == nil {
+		grole.Privileges = []string{}
+	}
+
+	return grole
+}
+
+func ConvertTeam(team database.Team, members []database.GetTeamMembersRow) *model.Team {
+	gteam := &model.Team{
+		ID:          team.ID,
+		Name:        team.Name,
+		Description: team.Description,
+		Members:     make([]*model.TeamMember, 0, len(members)),
+		CreatedAt:   team.CreatedAt.Time,
+		UpdatedAt:   team.UpdatedAt.Time,
+	}
+
+	for _, member := range members {
+		gteam.Members = append(gteam.Members, &model.TeamMember{
+			UserID:    member.UserID,
+			Name:      member.UserName,
+			Mail:      member.UserMail,
+			Role:      model.TeamRole(member.Role),
+			CreatedAt: member.CreatedAt.Time,
+		})
+	}
+
+	return gteam
+}
+
+func convertTeamID(teamID sql.NullInt64) *int64 {
+	if !teamID.Valid {
+		return nil
+	}
+
+	return &teamID.Int64
+}
+
+func ConvertFlowShares(shares []database.FlowShare) []*model.FlowShare {
+	gshares := make([]*model.FlowShare, 0, len(shares))
+	for _, share := range shares {
+		gshares = append(gshares, ConvertFlowShare(share))
+	}
+
+	return gshares
+}
+
+func ConvertFlowShare(share database.FlowShare) *model.FlowShare {
+	gshare := &model.FlowShare{
+		ID:               share.ID,
+		FlowID:           share.FlowID,
+		UserID:           share.UserID,
+		TokenPrefix:      share.TokenPrefix,
+		WithTerminalLogs: share.WithTerminalLogs,
+		ExpiresAt:        share.ExpiresAt,
+		CreatedAt:        share.CreatedAt.Time,
+	}
+
+	if share.RevokedAt.Valid {
+		gshare.RevokedAt = &share.RevokedAt.Time
+	}
+
+	return gshare
+}
+
+func ConvertFlowShareAccesses(accesses []database.FlowShareAccess) []*model.FlowShareAccess {
+	gaccesses := make([]*model.FlowShareAccess, 0, len(accesses))
+	for _, access := range accesses {
+		gaccesses = append(gaccesses, &model.FlowShareAccess{
+			ID:         access.ID,
+			ShareID:    access.ShareID,
+			Resource:   access.Resource,
+			RemoteAddr: access.RemoteAddr,
+			UserAgent:  access.UserAgent,
+			CreatedAt:  access.CreatedAt.Time,
+		})
+	}
+
+	return gaccesses
+}
@@ -114,3 +114,103 @@ func NewGorm(dsn, dbType string) (*gorm.DB, error) {
 	db.LogMode(true)
 	return db, nil
 }
+
+// Transactor is implemented by the queries which can run several queries in one transaction
+type Transactor interface {
+	ExecTx(ctx context.Context, fn func(q Querier) error) error
+}
+
+// ExecTx runs fn with the queries bound to a new transaction, the transaction is committed
+// if fn succeeds and rolled back otherwise; the queries which are already bound to a transaction
+// run fn in it
+func (q *Queries) ExecTx(ctx context.Context, fn func(q Querier) error) error {
+	db, ok := q.db.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RunInTx runs fn in one transaction of the querier, it fails if the querier can't run transactions,
+// so the multiple writes are never applied partially
+func RunInTx(ctx context.Context, db Querier, fn func(q Querier) error) error {
+	tx, ok := db.(Transactor)
+	if !ok {
+		return fmt.Errorf("database querier doesn't support transactions")
+	}
+
+	return tx.ExecTx(ctx, fn)
+}
+
+type stateDumper struct {
+	db Querier
+}
+
+// NewStateDumper returns the source of the flows, containers and queues numbers for the state metrics
+func NewStateDumper(db Querier) obs.StateDumper {
+	return &stateDumper{db: db}
+}
+
+func (sd *stateDumper) DumpState(ctx context.Context) (obs.State, error) {
+	flows, err := sd.db.GetFlowsCountByStatus(ctx)
+	if err != nil {
+		return obs.State{}, fmt.Errorf("failed to get flows count: %w", err)
+	}
+
+	containers, err := sd.db.GetContainersCountByStatus(ctx)
+	if err != nil {
+		return obs.State{}, fmt.Errorf("failed to get containers count: %w", err)
+	}
+
+	queues, err := sd.db.GetQueueDepths(ctx)
+	if err != nil {
+		return obs.State{}, fmt.Errorf("failed to get queue depths: %w", err)
+	}
+
+	// known statuses are always reported, so the gauges drop to zero instead of disappearing
+	state := obs.State{
+		Flows: map[string]int64{
+			string(FlowStatusQueued):   0,
+			string(FlowStatusCreated):  0,
+			string(FlowStatusRunning):  0,
+			string(FlowStatusWaiting):  0,
+			string(FlowStatusFinished): 0,
+			string(FlowStatusFailed):   0,
+		},
+		Containers: map[string]int64{
+			string(ContainerStatusStarting): 0,
+			string(ContainerStatusRunning):  0,
+			string(ContainerStatusStopped):  0,
+			string(ContainerStatusDeleted):  0,
+			string(ContainerStatusFailed):   0,
+		},
+		Queues: map[string]int64{
+			"flows":              queues.Flows,
+			"webhook_deliveries": queues.WebhookDeliveries,
+			"campaign_targets":   queues.CampaignTargets,
+		},
+	}
+	for _, row := range flows {
+		state.Flows[string(row.Status)] = row.Count
+	}
+	for _, row := range containers {
+		state.Containers[string(row.Status)] = row.Count
+	}
+
+	return state, nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	obs "pentagi/pkg/observability"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateQuerier struct {
+	Querier
+	flows      []GetFlowsCountByStatusRow
+	containers []GetContainersCountByStatusRow
+	queues     GetQueueDepthsRow
+	err        error
+}
+
+func (q *stateQuerier) GetFlowsCountByStatus(context.Context) ([]GetFlowsCountByStatusRow, error) {
+	return q.flows, q.err
+}
+
+func (q *stateQuerier) GetContainersCountByStatus(context.Context) ([]GetContainersCountByStatusRow, error) {
+	return q.containers, nil
+}
+
+func (q *stateQuerier) GetQueueDepths(context.Context) (GetQueueDepthsRow, error) {
+	return q.queues, nil
+}
+
+func TestStateDumperDumpState(t *testing.T) {
+	db := &stateQuerier{
+		flows: []GetFlowsCountByStatusRow{
+			{Status: FlowStatusRunning, Count: 3},
+			{Status: FlowStatusFinished, Count: 10},
+		},
+		containers: []GetContainersCountByStatusRow{
+			{Status: ContainerStatusRunning, Count: 3},
+		},
+		queues: GetQueueDepthsRow{Flows: 2, WebhookDeliveries: 5, CampaignTargets: 7},
+	}
+
+	state, err := NewStateDumper(db).DumpState(context.Background())
+	require.NoError(t, err)
+
+	// statuses without rows are reported as zero
+	assert.Equal(t, obs.State{
+		Flows: map[string]int64{
+			"queued":   0,
+			"created":  0,
+			"running":  3,
+			"waiting":  0,
+			"finished": 10,
+			"failed":   0,
+		},
+		Containers: map[string]int64{
+			"starting": 0,
+			"running":  3,
+			"stopped":  0,
+			"deleted":  0,
+			"failed":   0,
+		},
+		Queues: map[string]int64{
+			"flows":              2,
+			"webhook_deliveries": 5,
+			"campaign_targets":   7,
+		},
+	}, state)
+}
+
+func TestStateDumperEmptyDatabase(t *testing.T) {
+	state, err := NewStateDumper(&stateQuerier{}).DumpState(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, state.Flows, 6)
+	assert.Len(t, state.Containers, 5)
+	assert.Len(t, state.Queues, 3)
+	for _, values := range []map[string]int64{state.Flows, state.Containers, state.Queues} {
+		for name, value := range values {
+			assert.Zero(t, value, name)
+		}
+	}
+}
+
+func TestStateDumperError(t *testing.T) {
+	db := &stateQuerier{err: errors.New("relation \"flows\" does not exist")}
+
+	_, err := NewStateDumper(db).DumpState(context.Background())
+	assert.ErrorContains(t, err, "failed to get flows count")
+}
@@ -13,12 +13,12 @@ import (
 
 const createFlow = `-- name: CreateFlow :one
 INSERT INTO flows (
-  title, status, model, model_provider_name, model_provider_type, language, functions, user_id
+  title, status, model, model_provider_name, model_provider_type, language, functions, user_id, plan_review
 )
 VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 type CreateFlowParams struct {
@@ -30,6 +30,7 @@ type CreateFlowParams struct {
 	Language          string          `json:"language"`
 	Functions         json.RawMessage `json:"functions"`
 	UserID            int64           `json:"user_id"`
+	PlanReview        bool            `json:"plan_review"`
 }
 
 func (q *Queries) CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, error) {
@@ -42,6 +43,7 @@ func (q *Queries) CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, e
 		arg.Language,
 		arg.Functions,
 		arg.UserID,
+		arg.PlanReview,
 	)
 	var i Flow
 	err := row.Scan(
@@ -58,6 +60,8 @@ func (q *Queries) CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, e
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -66,7 +70,7 @@ const deleteFlow = `-- name: DeleteFlow :one
 UPDATE flows
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 func (q *Queries) DeleteFlow(ctx context.Context, id int64) (Flow, error) {
@@ -86,13 +90,15 @@ func (q *Queries) DeleteFlow(ctx context.Context, id int64) (Flow, error) {
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getFlow = `-- name: GetFlow :one
 SELECT
-  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type
+  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type, f.plan_review, f.team_id
 FROM flows f
 WHERE f.id = $1 AND f.deleted_at IS NULL
 `
@@ -114,13 +120,15 @@ func (q *Queries) GetFlow(ctx context.Context, id int64) (Flow, error) {
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getFlows = `-- name: GetFlows :many
 SELECT
-  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type
+  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type, f.plan_review, f.team_id
 FROM flows f
 WHERE f.deleted_at IS NULL
 ORDER BY f.created_at DESC
@@ -149,6 +157,8 @@ func (q *Queries) GetFlows(ctx context.Context) ([]Flow, error) {
 			&i.DeletedAt,
 			&i.TraceID,
 			&i.ModelProviderType,
+			&i.PlanReview,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -165,10 +175,10 @@ func (q *Queries) GetFlows(ctx context.Context) ([]Flow, error) {
 
 const getUserFlow = `-- name: GetUserFlow :one
 SELECT
-  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type
+  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type, f.plan_review, f.team_id
 FROM flows f
 INNER JOIN users u ON f.user_id = u.id
-WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+WHERE f.id = $1 AND (f.user_id = $2 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2)) AND f.deleted_at IS NULL
 `
 
 type GetUserFlowParams struct {
@@ -193,16 +203,18 @@ func (q *Queries) GetUserFlow(ctx context.Context, arg GetUserFlowParams) (Flow,
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getUserFlows = `-- name: GetUserFlows :many
 SELECT
-  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type
+  f.id, f.status, f.title, f.model, f.model_provider_name, f.language, f.functions, f.user_id, f.created_at, f.updated_at, f.deleted_at, f.trace_id, f.model_provider_type, f.plan_review, f.team_id
 FROM flows f
 INNER JOIN users u ON f.user_id = u.id
-WHERE f.user_id = $1 AND f.deleted_at IS NULL
+WHERE (f.user_id = $1 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND f.deleted_at IS NULL
 ORDER BY f.created_at DESC
 `
 
@@ -229,6 +241,8 @@ func (q *Queries) GetUserFlows(ctx context.Context, userID int64) ([]Flow, error
 			&i.DeletedAt,
 			&i.TraceID,
 			&i.ModelProviderType,
+			&i.PlanReview,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -247,7 +261,7 @@ const updateFlow = `-- name: UpdateFlow :one
 UPDATE flows
 SET title = $1, model = $2, language = $3, functions = $4, trace_id = $5
 WHERE id = $6
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 type UpdateFlowParams struct {
@@ -283,6 +297,8 @@ func (q *Queries) UpdateFlow(ctx context.Context, arg UpdateFlowParams) (Flow, e
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -291,7 +307,7 @@ const updateFlowLanguage = `-- name: UpdateFlowLanguage :one
 UPDATE flows
 SET language = $1
 WHERE id = $2
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 type UpdateFlowLanguageParams struct {
@@ -316,6 +332,8 @@ func (q *Queries) UpdateFlowLanguage(ctx context.Context, arg UpdateFlowLanguage
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -324,7 +342,7 @@ const updateFlowStatus = `-- name: UpdateFlowStatus :one
 UPDATE flows
 SET status = $1
 WHERE id = $2
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 type UpdateFlowStatusParams struct {
@@ -349,6 +367,43 @@ func (q *Queries) UpdateFlowStatus(ctx context.Context, arg UpdateFlowStatusPara
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
+	)
+	return i, err
+}
+
+const updateFlowTeam = `-- name: UpdateFlowTeam :one
+UPDATE flows
+SET team_id = $1
+WHERE id = $2
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
+`
+
+type UpdateFlowTeamParams struct {
+	TeamID sql.NullInt64 `json:"team_id"`
+	ID     int64         `json:"id"`
+}
+
+func (q *Queries) UpdateFlowTeam(ctx context.Context, arg UpdateFlowTeamParams) (Flow, error) {
+	row := q.db.QueryRowContext(ctx, updateFlowTeam, arg.TeamID, arg.ID)
+	var i Flow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.Title,
+		&i.Model,
+		&i.ModelProviderName,
+		&i.Language,
+		&i.Functions,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.TraceID,
+		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -357,7 +412,7 @@ const updateFlowTitle = `-- name: UpdateFlowTitle :one
 UPDATE flows
 SET title = $1
 WHERE id = $2
-RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type
+RETURNING id, status, title, model, model_provider_name, language, functions, user_id, created_at, updated_at, deleted_at, trace_id, model_provider_type, plan_review, team_id
 `
 
 type UpdateFlowTitleParams struct {
@@ -382,6 +437,8 @@ func (q *Queries) UpdateFlowTitle(ctx context.Context, arg UpdateFlowTitleParams
 		&i.DeletedAt,
 		&i.TraceID,
 		&i.ModelProviderType,
+		&i.PlanReview,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -9,6 +9,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type AssistantStatus string
@@ -33,6 +34,49 @@ func (e *AssistantStatus) Scan(src interface{}) error {
 	return nil
 }
 
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
 type NullAssistantStatus struct {
 	AssistantStatus AssistantStatus `json:"assistant_status"`
 	Valid           bool            `json:"valid"` // Valid is true if AssistantStatus is not NULL
@@ -56,6 +100,93 @@ func (ns NullAssistantStatus) Value() (driver.Value, error) {
 	return string(ns.AssistantStatus), nil
 }
 
+type CampaignStatus string
+
+const (
+	CampaignStatusRunning  CampaignStatus = "running"
+	CampaignStatusStopped  CampaignStatus = "stopped"
+	CampaignStatusFinished CampaignStatus = "finished"
+)
+
+func (e *CampaignStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CampaignStatus(s)
+	case string:
+		*e = CampaignStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CampaignStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCampaignStatus struct {
+	CampaignStatus CampaignStatus `json:"campaign_status"`
+	Valid          bool           `json:"valid"` // Valid is true if CampaignStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCampaignStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CampaignStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CampaignStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCampaignStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CampaignStatus), nil
+}
+
+type CampaignTargetStatus string
+
+const (
+	CampaignTargetStatusPending   CampaignTargetStatus = "pending"
+	CampaignTargetStatusStarted   CampaignTargetStatus = "started"
+	CampaignTargetStatusFailed    CampaignTargetStatus = "failed"
+	CampaignTargetStatusCancelled CampaignTargetStatus = "cancelled"
+)
+
+func (e *CampaignTargetStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CampaignTargetStatus(s)
+	case string:
+		*e = CampaignTargetStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CampaignTargetStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCampaignTargetStatus struct {
+	CampaignTargetStatus CampaignTargetStatus `json:"campaign_target_status"`
+	Valid                bool                 `json:"valid"` // Valid is true if CampaignTargetStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCampaignTargetStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CampaignTargetStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CampaignTargetStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCampaignTargetStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CampaignTargetStatus), nil
+}
+
 type ContainerStatus string
 
 const (
@@ -143,9 +274,53 @@ func (ns NullContainerType) Value() (driver.Value, error) {
 	return string(ns.ContainerType), nil
 }
 
+type FlowPriority string
+
+const (
+	FlowPriorityLow    FlowPriority = "low"
+	FlowPriorityNormal FlowPriority = "normal"
+	FlowPriorityHigh   FlowPriority = "high"
+)
+
+func (e *FlowPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FlowPriority(s)
+	case string:
+		*e = FlowPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FlowPriority: %T", src)
+	}
+	return nil
+}
+
+type NullFlowPriority struct {
+	FlowPriority FlowPriority `json:"flow_priority"`
+	Valid        bool         `json:"valid"` // Valid is true if FlowPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFlowPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.FlowPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FlowPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFlowPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FlowPriority), nil
+}
+
 type FlowStatus string
 
 const (
+	FlowStatusQueued   FlowStatus = "queued"
 	FlowStatusCreated  FlowStatus = "created"
 	FlowStatusRunning  FlowStatus = "running"
 	FlowStatusWaiting  FlowStatus = "waiting"
@@ -420,6 +595,8 @@ const (
 	ProviderTypeBedrock   ProviderType = "bedrock"
 	ProviderTypeOllama    ProviderType = "ollama"
 	ProviderTypeCustom    ProviderType = "custom"
+	ProviderTypeReplay    ProviderType = "replay"
+	ProviderTypeMock      ProviderType = "mock"
 )
 
 func (e *ProviderType) Scan(src interface{}) error {
@@ -457,6 +634,91 @@ func (ns NullProviderType) Value() (driver.Value, error) {
 	return string(ns.ProviderType), nil
 }
 
+type ScheduleMissedPolicy string
+
+const (
+	ScheduleMissedPolicySkip    ScheduleMissedPolicy = "skip"
+	ScheduleMissedPolicyRunOnce ScheduleMissedPolicy = "run_once"
+)
+
+func (e *ScheduleMissedPolicy) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleMissedPolicy(s)
+	case string:
+		*e = ScheduleMissedPolicy(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleMissedPolicy: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleMissedPolicy struct {
+	ScheduleMissedPolicy ScheduleMissedPolicy `json:"schedule_missed_policy"`
+	Valid                bool                 `json:"valid"` // Valid is true if ScheduleMissedPolicy is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleMissedPolicy) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleMissedPolicy, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleMissedPolicy.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleMissedPolicy) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleMissedPolicy), nil
+}
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusStarted ScheduleRunStatus = "started"
+	ScheduleRunStatusSkipped ScheduleRunStatus = "skipped"
+	ScheduleRunStatusFailed  ScheduleRunStatus = "failed"
+)
+
+func (e *ScheduleRunStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleRunStatus(s)
+	case string:
+		*e = ScheduleRunStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleRunStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleRunStatus struct {
+	ScheduleRunStatus ScheduleRunStatus `json:"schedule_run_status"`
+	Valid             bool              `json:"valid"` // Valid is true if ScheduleRunStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleRunStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleRunStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleRunStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleRunStatus), nil
+}
+
 type SearchengineType string
 
 const (
@@ -594,6 +856,49 @@ func (ns NullTaskStatus) Value() (driver.Value, error) {
 	return string(ns.TaskStatus), nil
 }
 
+type TeamRole string
+
+const (
+	TeamRoleViewer   TeamRole = "viewer"
+	TeamRoleOperator TeamRole = "operator"
+	TeamRoleAdmin    TeamRole = "admin"
+)
+
+func (e *TeamRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TeamRole(s)
+	case string:
+		*e = TeamRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TeamRole: %T", src)
+	}
+	return nil
+}
+
+type NullTeamRole struct {
+	TeamRole TeamRole `json:"team_role"`
+	Valid    bool     `json:"valid"` // Valid is true if TeamRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTeamRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.TeamRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TeamRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTeamRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TeamRole), nil
+}
+
 type TermlogType string
 
 const (
@@ -729,6 +1034,7 @@ type UserType string
 const (
 	UserTypeLocal UserType = "local"
 	UserTypeOauth UserType = "oauth"
+	UserTypeLdap  UserType = "ldap"
 )
 
 func (e *UserType) Scan(src interface{}) error {
@@ -820,6 +1126,20 @@ type Agentlog struct {
 	CreatedAt sql.NullTime  `json:"created_at"`
 }
 
+type ApiKey struct {
+	ID         int64        `json:"id"`
+	UserID     int64        `json:"user_id"`
+	Name       string       `json:"name"`
+	KeyPrefix  string       `json:"key_prefix"`
+	KeyHash    string       `json:"key_hash"`
+	Privileges []string     `json:"privileges"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	UpdatedAt  sql.NullTime `json:"updated_at"`
+}
+
 type Assistant struct {
 	ID                int64           `json:"id"`
 	Status            AssistantStatus `json:"status"`
@@ -850,6 +1170,50 @@ type Assistantlog struct {
 	Thinking     sql.NullString     `json:"thinking"`
 }
 
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	UserID     sql.NullInt64   `json:"user_id"`
+	UserMail   string          `json:"user_mail"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   sql.NullInt64   `json:"target_id"`
+	TargetName string          `json:"target_name"`
+	OldValues  json.RawMessage `json:"old_values"`
+	NewValues  json.RawMessage `json:"new_values"`
+	RemoteAddr string          `json:"remote_addr"`
+	UserAgent  string          `json:"user_agent"`
+	CreatedAt  sql.NullTime    `json:"created_at"`
+}
+
+type Campaign struct {
+	ID                int64           `json:"id"`
+	UserID            int64           `json:"user_id"`
+	Name              string          `json:"name"`
+	Status            CampaignStatus  `json:"status"`
+	Input             string          `json:"input"`
+	PlaybookID        sql.NullInt64   `json:"playbook_id"`
+	Variables         json.RawMessage `json:"variables"`
+	Scope             string          `json:"scope"`
+	ModelProviderName string          `json:"model_provider_name"`
+	ModelProviderType ProviderType    `json:"model_provider_type"`
+	PlanReview        bool            `json:"plan_review"`
+	Concurrency       int32           `json:"concurrency"`
+	CreatedAt         sql.NullTime    `json:"created_at"`
+	UpdatedAt         sql.NullTime    `json:"updated_at"`
+	DeletedAt         sql.NullTime    `json:"deleted_at"`
+}
+
+type CampaignTarget struct {
+	ID         int64                `json:"id"`
+	CampaignID int64                `json:"campaign_id"`
+	Target     string               `json:"target"`
+	Status     CampaignTargetStatus `json:"status"`
+	FlowID     sql.NullInt64        `json:"flow_id"`
+	Reason     string               `json:"reason"`
+	CreatedAt  sql.NullTime         `json:"created_at"`
+	UpdatedAt  sql.NullTime         `json:"updated_at"`
+}
+
 type Container struct {
 	ID        int64           `json:"id"`
 	Type      ContainerType   `json:"type"`
@@ -877,21 +1241,78 @@ type Flow struct {
 	DeletedAt         sql.NullTime    `json:"deleted_at"`
 	TraceID           sql.NullString  `json:"trace_id"`
 	ModelProviderType ProviderType    `json:"model_provider_type"`
+	PlanReview        bool            `json:"plan_review"`
+	TeamID            sql.NullInt64   `json:"team_id"`
+}
+
+type FlowLimit struct {
+	FlowID             int64        `json:"flow_id"`
+	FlowTimeLimit      int32        `json:"flow_time_limit"`
+	TaskTimeLimit      int32        `json:"task_time_limit"`
+	SubtaskTimeLimit   int32        `json:"subtask_time_limit"`
+	MaxIterations      int32        `json:"max_iterations"`
+	MaxToolCalls       int32        `json:"max_tool_calls"`
+	MaxDelegationDepth int32        `json:"max_delegation_depth"`
+	CreatedAt          sql.NullTime `json:"created_at"`
+	UpdatedAt          sql.NullTime `json:"updated_at"`
+}
+
+type FlowPlaybook struct {
+	FlowID     int64           `json:"flow_id"`
+	PlaybookID sql.NullInt64   `json:"playbook_id"`
+	Plan       json.RawMessage `json:"plan"`
+	NextTask   int32           `json:"next_task"`
+	CreatedAt  sql.NullTime    `json:"created_at"`
+	UpdatedAt  sql.NullTime    `json:"updated_at"`
+}
+
+type FlowQueue struct {
+	FlowID     int64           `json:"flow_id"`
+	Priority   FlowPriority    `json:"priority"`
+	Input      string          `json:"input"`
+	Functions  json.RawMessage `json:"functions"`
+	PlaybookID sql.NullInt64   `json:"playbook_id"`
+	Playbook   json.RawMessage `json:"playbook"`
+	CreatedAt  sql.NullTime    `json:"created_at"`
+}
+
+type FlowShare struct {
+	ID               int64        `json:"id"`
+	FlowID           int64        `json:"flow_id"`
+	UserID           int64        `json:"user_id"`
+	TokenPrefix      string       `json:"token_prefix"`
+	TokenHash        string       `json:"token_hash"`
+	WithTerminalLogs bool         `json:"with_terminal_logs"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RevokedAt        sql.NullTime `json:"revoked_at"`
+	CreatedAt        sql.NullTime `json:"created_at"`
+	UpdatedAt        sql.NullTime `json:"updated_at"`
+}
+
+type FlowShareAccess struct {
+	ID         int64        `json:"id"`
+	ShareID    int64        `json:"share_id"`
+	Resource   string       `json:"resource"`
+	RemoteAddr string       `json:"remote_addr"`
+	UserAgent  string       `json:"user_agent"`
+	CreatedAt  sql.NullTime `json:"created_at"`
 }
 
 type Msgchain struct {
-	ID            int64           `json:"id"`
-	Type          MsgchainType    `json:"type"`
-	Model         string          `json:"model"`
-	ModelProvider string          `json:"model_provider"`
-	UsageIn       int64           `json:"usage_in"`
-	UsageOut      int64           `json:"usage_out"`
-	Chain         json.RawMessage `json:"chain"`
-	FlowID        int64           `json:"flow_id"`
-	TaskID        sql.NullInt64   `json:"task_id"`
-	SubtaskID     sql.NullInt64   `json:"subtask_id"`
-	CreatedAt     sql.NullTime    `json:"created_at"`
-	UpdatedAt     sql.NullTime    `json:"updated_at"`
+	ID              int64           `json:"id"`
+	Type            MsgchainType    `json:"type"`
+	Model           string          `json:"model"`
+	ModelProvider   string          `json:"model_provider"`
+	UsageIn         int64           `json:"usage_in"`
+	UsageOut        int64           `json:"usage_out"`
+	Chain           json.RawMessage `json:"chain"`
+	FlowID          int64           `json:"flow_id"`
+	TaskID          sql.NullInt64   `json:"task_id"`
+	SubtaskID       sql.NullInt64   `json:"subtask_id"`
+	CreatedAt       sql.NullTime    `json:"created_at"`
+	UpdatedAt       sql.NullTime    `json:"updated_at"`
+	UsageCacheRead  int64           `json:"usage_cache_read"`
+	UsageCacheWrite int64           `json:"usage_cache_write"`
 }
 
 type Msglog struct {
@@ -907,6 +1328,18 @@ type Msglog struct {
 	Thinking     sql.NullString     `json:"thinking"`
 }
 
+type Playbook struct {
+	ID          int64         `json:"id"`
+	UserID      int64         `json:"user_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Content     string        `json:"content"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+	UpdatedAt   sql.NullTime  `json:"updated_at"`
+	DeletedAt   sql.NullTime  `json:"deleted_at"`
+	TeamID      sql.NullInt64 `json:"team_id"`
+}
+
 type Privilege struct {
 	ID     int64  `json:"id"`
 	RoleID int64  `json:"role_id"`
@@ -914,12 +1347,13 @@ type Privilege struct {
 }
 
 type Prompt struct {
-	ID        int64        `json:"id"`
-	Type      PromptType   `json:"type"`
-	UserID    int64        `json:"user_id"`
-	Prompt    string       `json:"prompt"`
-	CreatedAt sql.NullTime `json:"created_at"`
-	UpdatedAt sql.NullTime `json:"updated_at"`
+	ID        int64         `json:"id"`
+	Type      PromptType    `json:"type"`
+	UserID    int64         `json:"user_id"`
+	Prompt    string        `json:"prompt"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+	TeamID    sql.NullInt64 `json:"team_id"`
 }
 
 type Provider struct {
@@ -931,6 +1365,7 @@ type Provider struct {
 	CreatedAt sql.NullTime    `json:"created_at"`
 	UpdatedAt sql.NullTime    `json:"updated_at"`
 	DeletedAt sql.NullTime    `json:"deleted_at"`
+	TeamID    sql.NullInt64   `json:"team_id"`
 }
 
 type Role struct {
@@ -938,6 +1373,38 @@ type Role struct {
 	Name string `json:"name"`
 }
 
+type Schedule struct {
+	ID                int64                `json:"id"`
+	UserID            int64                `json:"user_id"`
+	Name              string               `json:"name"`
+	Cron              string               `json:"cron"`
+	Timezone          string               `json:"timezone"`
+	Input             string               `json:"input"`
+	PlaybookID        sql.NullInt64        `json:"playbook_id"`
+	Variables         json.RawMessage      `json:"variables"`
+	Scope             string               `json:"scope"`
+	ModelProviderName string               `json:"model_provider_name"`
+	ModelProviderType ProviderType         `json:"model_provider_type"`
+	PlanReview        bool                 `json:"plan_review"`
+	MissedPolicy      ScheduleMissedPolicy `json:"missed_policy"`
+	Enabled           bool                 `json:"enabled"`
+	NextRunAt         sql.NullTime         `json:"next_run_at"`
+	LastRunAt         sql.NullTime         `json:"last_run_at"`
+	CreatedAt         sql.NullTime         `json:"created_at"`
+	UpdatedAt         sql.NullTime         `json:"updated_at"`
+	DeletedAt         sql.NullTime         `json:"deleted_at"`
+}
+
+type ScheduleRun struct {
+	ID          int64             `json:"id"`
+	ScheduleID  int64             `json:"schedule_id"`
+	FlowID      sql.NullInt64     `json:"flow_id"`
+	Status      ScheduleRunStatus `json:"status"`
+	Reason      string            `json:"reason"`
+	ScheduledAt time.Time         `json:"scheduled_at"`
+	CreatedAt   sql.NullTime      `json:"created_at"`
+}
+
 type Screenshot struct {
 	ID        int64        `json:"id"`
 	Name      string       `json:"name"`
@@ -969,6 +1436,7 @@ type Subtask struct {
 	CreatedAt   sql.NullTime  `json:"created_at"`
 	UpdatedAt   sql.NullTime  `json:"updated_at"`
 	Context     string        `json:"context"`
+	DependsOn   []int64       `json:"depends_on"`
 }
 
 type Task struct {
@@ -982,6 +1450,22 @@ type Task struct {
 	UpdatedAt sql.NullTime `json:"updated_at"`
 }
 
+type Team struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type TeamMember struct {
+	TeamID    int64        `json:"team_id"`
+	UserID    int64        `json:"user_id"`
+	Role      TeamRole     `json:"role"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
 type Termlog struct {
 	ID          int64        `json:"id"`
 	Type        TermlogType  `json:"type"`
@@ -1031,3 +1515,30 @@ type Vecstorelog struct {
 	SubtaskID sql.NullInt64      `json:"subtask_id"`
 	CreatedAt sql.NullTime       `json:"created_at"`
 }
+
+type Webhook struct {
+	ID        int64         `json:"id"`
+	UserID    sql.NullInt64 `json:"user_id"`
+	Name      string        `json:"name"`
+	Url       string        `json:"url"`
+	Secret    string        `json:"secret"`
+	Events    []string      `json:"events"`
+	Enabled   bool          `json:"enabled"`
+	CreatedAt sql.NullTime  `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID            int64                 `json:"id"`
+	WebhookID     int64                 `json:"webhook_id"`
+	FlowID        sql.NullInt64         `json:"flow_id"`
+	Event         string                `json:"event"`
+	Payload       json.RawMessage       `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int32                 `json:"attempts"`
+	ResponseCode  int32                 `json:"response_code"`
+	Error         string                `json:"error"`
+	NextAttemptAt sql.NullTime          `json:"next_attempt_at"`
+	CreatedAt     sql.NullTime          `json:"created_at"`
+	UpdatedAt     sql.NullTime          `json:"updated_at"`
+}
@@ -18,26 +18,30 @@ INSERT INTO msgchains (
   model_provider,
   usage_in,
   usage_out,
+  usage_cache_read,
+  usage_cache_write,
   chain,
   flow_id,
   task_id,
   subtask_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at
+RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at, usage_cache_read, usage_cache_write
 `
 
 type CreateMsgChainParams struct {
-	Type          MsgchainType    `json:"type"`
-	Model         string          `json:"model"`
-	ModelProvider string          `json:"model_provider"`
-	UsageIn       int64           `json:"usage_in"`
-	UsageOut      int64           `json:"usage_out"`
-	Chain         json.RawMessage `json:"chain"`
-	FlowID        int64           `json:"flow_id"`
-	TaskID        sql.NullInt64   `json:"task_id"`
-	SubtaskID     sql.NullInt64   `json:"subtask_id"`
+	Type            MsgchainType    `json:"type"`
+	Model           string          `json:"model"`
+	ModelProvider   string          `json:"model_provider"`
+	UsageIn         int64           `json:"usage_in"`
+	UsageOut        int64           `json:"usage_out"`
+	UsageCacheRead  int64           `json:"usage_cache_read"`
+	UsageCacheWrite int64           `json:"usage_cache_write"`
+	Chain           json.RawMessage `json:"chain"`
+	FlowID          int64           `json:"flow_id"`
+	TaskID          sql.NullInt64   `json:"task_id"`
+	SubtaskID       sql.NullInt64   `json:"subtask_id"`
 }
 
 func (q *Queries) CreateMsgChain(ctx context.Context, arg CreateMsgChainParams) (Msgchain, error) {
@@ -47,6 +51,8 @@ func (q *Queries) CreateMsgChain(ctx context.Context, arg CreateMsgChainParams)
 		arg.ModelProvider,
 		arg.UsageIn,
 		arg.UsageOut,
+		arg.UsageCacheRead,
+		arg.UsageCacheWrite,
 		arg.Chain,
 		arg.FlowID,
 		arg.TaskID,
@@ -66,13 +72,15 @@ func (q *Queries) CreateMsgChain(ctx context.Context, arg CreateMsgChainParams)
 		&i.SubtaskID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsageCacheRead,
+		&i.UsageCacheWrite,
 	)
 	return i, err
 }
 
 const getFlowMsgChains = `-- name: GetFlowMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 LEFT JOIN subtasks s ON mc.subtask_id = s.id
 LEFT JOIN tasks t ON s.task_id = t.id
@@ -102,6 +110,8 @@ func (q *Queries) GetFlowMsgChains(ctx context.Context, flowID int64) ([]Msgchai
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -118,7 +128,7 @@ func (q *Queries) GetFlowMsgChains(ctx context.Context, flowID int64) ([]Msgchai
 
 const getFlowTaskTypeLastMsgChain = `-- name: GetFlowTaskTypeLastMsgChain :one
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 WHERE mc.flow_id = $1 AND (mc.task_id = $2 OR $2 IS NULL) AND mc.type = $3
 ORDER BY mc.created_at DESC
@@ -147,13 +157,15 @@ func (q *Queries) GetFlowTaskTypeLastMsgChain(ctx context.Context, arg GetFlowTa
 		&i.SubtaskID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsageCacheRead,
+		&i.UsageCacheWrite,
 	)
 	return i, err
 }
 
 const getFlowTypeMsgChains = `-- name: GetFlowTypeMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 LEFT JOIN subtasks s ON mc.subtask_id = s.id
 LEFT JOIN tasks t ON s.task_id = t.id
@@ -188,6 +200,8 @@ func (q *Queries) GetFlowTypeMsgChains(ctx context.Context, arg GetFlowTypeMsgCh
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -204,7 +218,7 @@ func (q *Queries) GetFlowTypeMsgChains(ctx context.Context, arg GetFlowTypeMsgCh
 
 const getMsgChain = `-- name: GetMsgChain :one
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 WHERE mc.id = $1
 `
@@ -225,13 +239,15 @@ func (q *Queries) GetMsgChain(ctx context.Context, id int64) (Msgchain, error) {
 		&i.SubtaskID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsageCacheRead,
+		&i.UsageCacheWrite,
 	)
 	return i, err
 }
 
 const getSubtaskMsgChains = `-- name: GetSubtaskMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 WHERE mc.subtask_id = $1
 ORDER BY mc.created_at DESC
@@ -259,6 +275,8 @@ func (q *Queries) GetSubtaskMsgChains(ctx context.Context, subtaskID sql.NullInt
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -275,7 +293,7 @@ func (q *Queries) GetSubtaskMsgChains(ctx context.Context, subtaskID sql.NullInt
 
 const getSubtaskPrimaryMsgChains = `-- name: GetSubtaskPrimaryMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 WHERE mc.subtask_id = $1 AND mc.type = 'primary_agent'
 ORDER BY mc.created_at DESC
@@ -303,6 +321,8 @@ func (q *Queries) GetSubtaskPrimaryMsgChains(ctx context.Context, subtaskID sql.
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -319,7 +339,7 @@ func (q *Queries) GetSubtaskPrimaryMsgChains(ctx context.Context, subtaskID sql.
 
 const getSubtaskTypeMsgChains = `-- name: GetSubtaskTypeMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 WHERE mc.subtask_id = $1 AND mc.type = $2
 ORDER BY mc.created_at DESC
@@ -352,6 +372,8 @@ func (q *Queries) GetSubtaskTypeMsgChains(ctx context.Context, arg GetSubtaskTyp
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -368,7 +390,7 @@ func (q *Queries) GetSubtaskTypeMsgChains(ctx context.Context, arg GetSubtaskTyp
 
 const getTaskMsgChains = `-- name: GetTaskMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 LEFT JOIN subtasks s ON mc.subtask_id = s.id
 WHERE mc.task_id = $1 OR s.task_id = $1
@@ -397,6 +419,8 @@ func (q *Queries) GetTaskMsgChains(ctx context.Context, taskID sql.NullInt64) ([
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -450,7 +474,7 @@ func (q *Queries) GetTaskPrimaryMsgChainIDs(ctx context.Context, taskID sql.Null
 
 const getTaskPrimaryMsgChains = `-- name: GetTaskPrimaryMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 LEFT JOIN subtasks s ON mc.subtask_id = s.id
 WHERE (mc.task_id = $1 OR s.task_id = $1) AND mc.type = 'primary_agent'
@@ -479,6 +503,8 @@ func (q *Queries) GetTaskPrimaryMsgChains(ctx context.Context, taskID sql.NullIn
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -495,7 +521,7 @@ func (q *Queries) GetTaskPrimaryMsgChains(ctx context.Context, taskID sql.NullIn
 
 const getTaskTypeMsgChains = `-- name: GetTaskTypeMsgChains :many
 SELECT
-  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at
+  mc.id, mc.type, mc.model, mc.model_provider, mc.usage_in, mc.usage_out, mc.chain, mc.flow_id, mc.task_id, mc.subtask_id, mc.created_at, mc.updated_at, mc.usage_cache_read, mc.usage_cache_write
 FROM msgchains mc
 LEFT JOIN subtasks s ON mc.subtask_id = s.id
 WHERE (mc.task_id = $1 OR s.task_id = $1) AND mc.type = $2
@@ -529,6 +555,8 @@ func (q *Queries) GetTaskTypeMsgChains(ctx context.Context, arg GetTaskTypeMsgCh
 			&i.SubtaskID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UsageCacheRead,
+			&i.UsageCacheWrite,
 		); err != nil {
 			return nil, err
 		}
@@ -547,7 +575,7 @@ const updateMsgChain = `-- name: UpdateMsgChain :one
 UPDATE msgchains
 SET chain = $1
 WHERE id = $2
-RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at
+RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at, usage_cache_read, usage_cache_write
 `
 
 type UpdateMsgChainParams struct {
@@ -571,25 +599,39 @@ func (q *Queries) UpdateMsgChain(ctx context.Context, arg UpdateMsgChainParams)
 		&i.SubtaskID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsageCacheRead,
+		&i.UsageCacheWrite,
 	)
 	return i, err
 }
 
 const updateMsgChainUsage = `-- name: UpdateMsgChainUsage :one
 UPDATE msgchains
-SET usage_in = usage_in + $1, usage_out = usage_out + $2
-WHERE id = $3
-RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at
+SET
+  usage_in = usage_in + $1,
+  usage_out = usage_out + $2,
+  usage_cache_read = usage_cache_read + $3,
+  usage_cache_write = usage_cache_write + $4
+WHERE id = $5
+RETURNING id, type, model, model_provider, usage_in, usage_out, chain, flow_id, task_id, subtask_id, created_at, updated_at, usage_cache_read, usage_cache_write
 `
 
 type UpdateMsgChainUsageParams struct {
-	UsageIn  int64 `json:"usage_in"`
-	UsageOut int64 `json:"usage_out"`
-	ID       int64 `json:"id"`
+	UsageIn         int64 `json:"usage_in"`
+	UsageOut        int64 `json:"usage_out"`
+	UsageCacheRead  int64 `json:"usage_cache_read"`
+	UsageCacheWrite int64 `json:"usage_cache_write"`
+	ID              int64 `json:"id"`
 }
 
 func (q *Queries) UpdateMsgChainUsage(ctx context.Context, arg UpdateMsgChainUsageParams) (Msgchain, error) {
-	row := q.db.QueryRowContext(ctx, updateMsgChainUsage, arg.UsageIn, arg.UsageOut, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateMsgChainUsage,
+		arg.UsageIn,
+		arg.UsageOut,
+		arg.UsageCacheRead,
+		arg.UsageCacheWrite,
+		arg.ID,
+	)
 	var i Msgchain
 	err := row.Scan(
 		&i.ID,
@@ -604,6 +646,8 @@ func (q *Queries) UpdateMsgChainUsage(ctx context.Context, arg UpdateMsgChainUsa
 		&i.SubtaskID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.UsageCacheRead,
+		&i.UsageCacheWrite,
 	)
 	return i, err
 }
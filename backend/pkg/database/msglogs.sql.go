@@ -8,6 +8,8 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createMsgLog = `-- name: CreateMsgLog :one
@@ -157,6 +159,152 @@ func (q *Queries) GetFlowMsgLogs(ctx context.Context, flowID int64) ([]Msglog, e
 	return items, nil
 }
 
+const getFlowMsgLogsAsc = `-- name: GetFlowMsgLogsAsc :many
+SELECT
+  ml.id, ml.type, ml.message, ml.result, ml.flow_id, ml.task_id, ml.subtask_id, ml.created_at, ml.result_format, ml.thinking
+FROM msglogs ml
+INNER JOIN flows f ON ml.flow_id = f.id
+WHERE ml.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR ml.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR ml.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR ml.type::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR ml.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR ml.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR ml.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR ml.id < $8::BIGINT)
+ORDER BY ml.id ASC
+LIMIT $9
+`
+
+type GetFlowMsgLogsAscParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Types         []string      `json:"types"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowMsgLogsAsc(ctx context.Context, arg GetFlowMsgLogsAscParams) ([]Msglog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowMsgLogsAsc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Types),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Msglog
+	for rows.Next() {
+		var i Msglog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Message,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+			&i.ResultFormat,
+			&i.Thinking,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlowMsgLogsDesc = `-- name: GetFlowMsgLogsDesc :many
+SELECT
+  ml.id, ml.type, ml.message, ml.result, ml.flow_id, ml.task_id, ml.subtask_id, ml.created_at, ml.result_format, ml.thinking
+FROM msglogs ml
+INNER JOIN flows f ON ml.flow_id = f.id
+WHERE ml.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR ml.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR ml.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR ml.type::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR ml.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR ml.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR ml.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR ml.id < $8::BIGINT)
+ORDER BY ml.id DESC
+LIMIT $9
+`
+
+type GetFlowMsgLogsDescParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Types         []string      `json:"types"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowMsgLogsDesc(ctx context.Context, arg GetFlowMsgLogsDescParams) ([]Msglog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowMsgLogsDesc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Types),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Msglog
+	for rows.Next() {
+		var i Msglog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Message,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+			&i.ResultFormat,
+			&i.Thinking,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubtaskMsgLogs = `-- name: GetSubtaskMsgLogs :many
 SELECT
   ml.id, ml.type, ml.message, ml.result, ml.flow_id, ml.task_id, ml.subtask_id, ml.created_at, ml.result_format, ml.thinking
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createUserPrompt = `-- name: CreateUserPrompt :one
@@ -17,7 +18,7 @@ INSERT INTO prompts (
 ) VALUES (
   $1, $2, $3
 )
-RETURNING id, type, user_id, prompt, created_at, updated_at
+RETURNING id, type, user_id, prompt, created_at, updated_at, team_id
 `
 
 type CreateUserPromptParams struct {
@@ -36,6 +37,7 @@ func (q *Queries) CreateUserPrompt(ctx context.Context, arg CreateUserPromptPara
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -67,7 +69,7 @@ func (q *Queries) DeleteUserPrompt(ctx context.Context, arg DeleteUserPromptPara
 
 const getPrompts = `-- name: GetPrompts :many
 SELECT
-  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at
+  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at, p.team_id
 FROM prompts p
 ORDER BY p.user_id ASC, p.type ASC
 `
@@ -88,6 +90,7 @@ func (q *Queries) GetPrompts(ctx context.Context) ([]Prompt, error) {
 			&i.Prompt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -104,7 +107,7 @@ func (q *Queries) GetPrompts(ctx context.Context) ([]Prompt, error) {
 
 const getUserPrompt = `-- name: GetUserPrompt :one
 SELECT
-  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at
+  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at, p.team_id
 FROM prompts p
 INNER JOIN users u ON p.user_id = u.id
 WHERE p.id = $1 AND p.user_id = $2
@@ -125,13 +128,14 @@ func (q *Queries) GetUserPrompt(ctx context.Context, arg GetUserPromptParams) (P
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getUserPromptByType = `-- name: GetUserPromptByType :one
 SELECT
-  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at
+  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at, p.team_id
 FROM prompts p
 INNER JOIN users u ON p.user_id = u.id
 WHERE p.type = $1 AND p.user_id = $2
@@ -153,17 +157,18 @@ func (q *Queries) GetUserPromptByType(ctx context.Context, arg GetUserPromptByTy
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getUserPrompts = `-- name: GetUserPrompts :many
 SELECT
-  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at
+  p.id, p.type, p.user_id, p.prompt, p.created_at, p.updated_at, p.team_id
 FROM prompts p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.user_id = $1
-ORDER BY p.type ASC
+WHERE (p.user_id = $1 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1))
+ORDER BY p.user_id <> $1, p.type ASC
 `
 
 func (q *Queries) GetUserPrompts(ctx context.Context, userID int64) ([]Prompt, error) {
@@ -182,6 +187,7 @@ func (q *Queries) GetUserPrompts(ctx context.Context, userID int64) ([]Prompt, e
 			&i.Prompt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -200,7 +206,7 @@ const updatePrompt = `-- name: UpdatePrompt :one
 UPDATE prompts
 SET prompt = $1
 WHERE id = $2
-RETURNING id, type, user_id, prompt, created_at, updated_at
+RETURNING id, type, user_id, prompt, created_at, updated_at, team_id
 `
 
 type UpdatePromptParams struct {
@@ -218,6 +224,34 @@ func (q *Queries) UpdatePrompt(ctx context.Context, arg UpdatePromptParams) (Pro
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
+	)
+	return i, err
+}
+
+const updatePromptTeam = `-- name: UpdatePromptTeam :one
+UPDATE prompts
+SET team_id = $1
+WHERE id = $2
+RETURNING id, type, user_id, prompt, created_at, updated_at, team_id
+`
+
+type UpdatePromptTeamParams struct {
+	TeamID sql.NullInt64 `json:"team_id"`
+	ID     int64         `json:"id"`
+}
+
+func (q *Queries) UpdatePromptTeam(ctx context.Context, arg UpdatePromptTeamParams) (Prompt, error) {
+	row := q.db.QueryRowContext(ctx, updatePromptTeam, arg.TeamID, arg.ID)
+	var i Prompt
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.UserID,
+		&i.Prompt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -226,7 +260,7 @@ const updateUserPrompt = `-- name: UpdateUserPrompt :one
 UPDATE prompts
 SET prompt = $1
 WHERE id = $2 AND user_id = $3
-RETURNING id, type, user_id, prompt, created_at, updated_at
+RETURNING id, type, user_id, prompt, created_at, updated_at, team_id
 `
 
 type UpdateUserPromptParams struct {
@@ -245,6 +279,7 @@ func (q *Queries) UpdateUserPrompt(ctx context.Context, arg UpdateUserPromptPara
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -253,7 +288,7 @@ const updateUserPromptByType = `-- name: UpdateUserPromptByType :one
 UPDATE prompts
 SET prompt = $1
 WHERE type = $2 AND user_id = $3
-RETURNING id, type, user_id, prompt, created_at, updated_at
+RETURNING id, type, user_id, prompt, created_at, updated_at, team_id
 `
 
 type UpdateUserPromptByTypeParams struct {
@@ -272,6 +307,7 @@ func (q *Queries) UpdateUserPromptByType(ctx context.Context, arg UpdateUserProm
 		&i.Prompt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
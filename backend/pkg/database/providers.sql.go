@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 )
 
@@ -19,7 +20,7 @@ INSERT INTO providers (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
 `
 
 type CreateProviderParams struct {
@@ -46,6 +47,7 @@ func (q *Queries) CreateProvider(ctx context.Context, arg CreateProviderParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -54,7 +56,7 @@ const deleteProvider = `-- name: DeleteProvider :one
 UPDATE providers
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
 `
 
 func (q *Queries) DeleteProvider(ctx context.Context, id int64) (Provider, error) {
@@ -69,6 +71,7 @@ func (q *Queries) DeleteProvider(ctx context.Context, id int64) (Provider, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -77,7 +80,7 @@ const deleteUserProvider = `-- name: DeleteUserProvider :one
 UPDATE providers
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
 `
 
 type DeleteUserProviderParams struct {
@@ -97,13 +100,14 @@ func (q *Queries) DeleteUserProvider(ctx context.Context, arg DeleteUserProvider
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getProvider = `-- name: GetProvider :one
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 WHERE p.id = $1 AND p.deleted_at IS NULL
 `
@@ -120,13 +124,14 @@ func (q *Queries) GetProvider(ctx context.Context, id int64) (Provider, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getProviders = `-- name: GetProviders :many
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 WHERE p.deleted_at IS NULL
 ORDER BY p.created_at ASC
@@ -150,6 +155,7 @@ func (q *Queries) GetProviders(ctx context.Context) ([]Provider, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -166,7 +172,7 @@ func (q *Queries) GetProviders(ctx context.Context) ([]Provider, error) {
 
 const getProvidersByType = `-- name: GetProvidersByType :many
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 WHERE p.type = $1 AND p.deleted_at IS NULL
 ORDER BY p.created_at ASC
@@ -190,6 +196,7 @@ func (q *Queries) GetProvidersByType(ctx context.Context, type_ ProviderType) ([
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -206,7 +213,7 @@ func (q *Queries) GetProvidersByType(ctx context.Context, type_ ProviderType) ([
 
 const getUserProvider = `-- name: GetUserProvider :one
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
 WHERE p.id = $1 AND p.user_id = $2 AND p.deleted_at IS NULL
@@ -229,16 +236,19 @@ func (q *Queries) GetUserProvider(ctx context.Context, arg GetUserProviderParams
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getUserProviderByName = `-- name: GetUserProviderByName :one
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.name = $1 AND p.user_id = $2 AND p.deleted_at IS NULL
+WHERE p.name = $1 AND (p.user_id = $2 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2 AND tm.role IN ('operator', 'admin'))) AND p.deleted_at IS NULL
+ORDER BY p.user_id <> $2, p.created_at ASC
+LIMIT 1
 `
 
 type GetUserProviderByNameParams struct {
@@ -258,17 +268,18 @@ func (q *Queries) GetUserProviderByName(ctx context.Context, arg GetUserProvider
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
 
 const getUserProviders = `-- name: GetUserProviders :many
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.user_id = $1 AND p.deleted_at IS NULL
-ORDER BY p.created_at ASC
+WHERE (p.user_id = $1 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND p.deleted_at IS NULL
+ORDER BY p.user_id <> $1, p.created_at ASC
 `
 
 func (q *Queries) GetUserProviders(ctx context.Context, userID int64) ([]Provider, error) {
@@ -289,6 +300,7 @@ func (q *Queries) GetUserProviders(ctx context.Context, userID int64) ([]Provide
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -305,7 +317,7 @@ func (q *Queries) GetUserProviders(ctx context.Context, userID int64) ([]Provide
 
 const getUserProvidersByType = `-- name: GetUserProvidersByType :many
 SELECT
-  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at
+  p.id, p.user_id, p.type, p.name, p.config, p.created_at, p.updated_at, p.deleted_at, p.team_id
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
 WHERE p.user_id = $1 AND p.type = $2 AND p.deleted_at IS NULL
@@ -335,6 +347,7 @@ func (q *Queries) GetUserProvidersByType(ctx context.Context, arg GetUserProvide
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.TeamID,
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +366,7 @@ const updateProvider = `-- name: UpdateProvider :one
 UPDATE providers
 SET config = $2, name = $3
 WHERE id = $1
-RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
 `
 
 type UpdateProviderParams struct {
@@ -374,6 +387,36 @@ func (q *Queries) UpdateProvider(ctx context.Context, arg UpdateProviderParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
+	)
+	return i, err
+}
+
+const updateProviderTeam = `-- name: UpdateProviderTeam :one
+UPDATE providers
+SET team_id = $1
+WHERE id = $2
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
+`
+
+type UpdateProviderTeamParams struct {
+	TeamID sql.NullInt64 `json:"team_id"`
+	ID     int64         `json:"id"`
+}
+
+func (q *Queries) UpdateProviderTeam(ctx context.Context, arg UpdateProviderTeamParams) (Provider, error) {
+	row := q.db.QueryRowContext(ctx, updateProviderTeam, arg.TeamID, arg.ID)
+	var i Provider
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Name,
+		&i.Config,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
@@ -382,7 +425,7 @@ const updateUserProvider = `-- name: UpdateUserProvider :one
 UPDATE providers
 SET config = $3, name = $4
 WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at
+RETURNING id, user_id, type, name, config, created_at, updated_at, deleted_at, team_id
 `
 
 type UpdateUserProviderParams struct {
@@ -409,6 +452,7 @@ func (q *Queries) UpdateUserProvider(ctx context.Context, arg UpdateUserProvider
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.TeamID,
 	)
 	return i, err
 }
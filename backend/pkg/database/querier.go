@@ -10,52 +10,113 @@ import (
 )
 
 type Querier interface {
+	AdmitQueuedFlow(ctx context.Context, id int64) (Flow, error)
+	CancelCampaignPendingTargets(ctx context.Context, campaignID int64) error
+	CancelQueuedFlow(ctx context.Context, id int64) (Flow, error)
+	CopyContainerTermLogs(ctx context.Context, arg CopyContainerTermLogsParams) error
+	CopyFlowAgentLogs(ctx context.Context, arg CopyFlowAgentLogsParams) error
+	CopyFlowMsgChains(ctx context.Context, arg CopyFlowMsgChainsParams) error
+	CopyFlowMsgLogs(ctx context.Context, arg CopyFlowMsgLogsParams) error
+	CopyFlowSearchLogs(ctx context.Context, arg CopyFlowSearchLogsParams) error
+	CopyFlowToolcalls(ctx context.Context, arg CopyFlowToolcallsParams) error
+	CopyFlowVectorStoreLogs(ctx context.Context, arg CopyFlowVectorStoreLogsParams) error
+	CopySubtask(ctx context.Context, arg CopySubtaskParams) (Subtask, error)
+	CopyTask(ctx context.Context, arg CopyTaskParams) (Task, error)
 	CreateAgentLog(ctx context.Context, arg CreateAgentLogParams) (Agentlog, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
 	CreateAssistant(ctx context.Context, arg CreateAssistantParams) (Assistant, error)
 	CreateAssistantLog(ctx context.Context, arg CreateAssistantLogParams) (Assistantlog, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error)
+	CreateCampaignTargets(ctx context.Context, arg CreateCampaignTargetsParams) error
 	CreateContainer(ctx context.Context, arg CreateContainerParams) (Container, error)
 	CreateFlow(ctx context.Context, arg CreateFlowParams) (Flow, error)
+	CreateFlowPlaybook(ctx context.Context, arg CreateFlowPlaybookParams) (FlowPlaybook, error)
+	CreateFlowQueueItem(ctx context.Context, arg CreateFlowQueueItemParams) (FlowQueue, error)
+	CreateFlowShare(ctx context.Context, arg CreateFlowShareParams) (FlowShare, error)
+	CreateFlowShareAccess(ctx context.Context, arg CreateFlowShareAccessParams) (FlowShareAccess, error)
 	CreateMsgChain(ctx context.Context, arg CreateMsgChainParams) (Msgchain, error)
 	CreateMsgLog(ctx context.Context, arg CreateMsgLogParams) (Msglog, error)
 	CreateProvider(ctx context.Context, arg CreateProviderParams) (Provider, error)
 	CreateResultAssistantLog(ctx context.Context, arg CreateResultAssistantLogParams) (Assistantlog, error)
 	CreateResultMsgLog(ctx context.Context, arg CreateResultMsgLogParams) (Msglog, error)
+	CreateRole(ctx context.Context, name string) (Role, error)
+	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error)
+	CreateScheduleRun(ctx context.Context, arg CreateScheduleRunParams) (ScheduleRun, error)
 	CreateScreenshot(ctx context.Context, arg CreateScreenshotParams) (Screenshot, error)
 	CreateSearchLog(ctx context.Context, arg CreateSearchLogParams) (Searchlog, error)
 	CreateSubtask(ctx context.Context, arg CreateSubtaskParams) (Subtask, error)
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
 	CreateTermLog(ctx context.Context, arg CreateTermLogParams) (Termlog, error)
 	CreateToolcall(ctx context.Context, arg CreateToolcallParams) (Toolcall, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserPlaybook(ctx context.Context, arg CreateUserPlaybookParams) (Playbook, error)
 	CreateUserPrompt(ctx context.Context, arg CreateUserPromptParams) (Prompt, error)
 	CreateVectorStoreLog(ctx context.Context, arg CreateVectorStoreLogParams) (Vecstorelog, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
 	DeleteAssistant(ctx context.Context, id int64) (Assistant, error)
+	DeleteCampaign(ctx context.Context, id int64) (Campaign, error)
 	DeleteFlow(ctx context.Context, id int64) (Flow, error)
+	DeleteFlowQueueItem(ctx context.Context, flowID int64) error
 	DeletePrompt(ctx context.Context, id int64) error
 	DeleteProvider(ctx context.Context, id int64) (Provider, error)
+	DeleteSchedule(ctx context.Context, id int64) (Schedule, error)
 	DeleteSubtask(ctx context.Context, id int64) error
 	DeleteSubtasks(ctx context.Context, ids []int64) error
+	DeleteTeam(ctx context.Context, id int64) error
+	DeleteTeamMember(ctx context.Context, arg DeleteTeamMemberParams) error
 	DeleteUser(ctx context.Context, id int64) error
+	DeleteUserPlaybook(ctx context.Context, arg DeleteUserPlaybookParams) (Playbook, error)
 	DeleteUserPrompt(ctx context.Context, arg DeleteUserPromptParams) error
 	DeleteUserProvider(ctx context.Context, arg DeleteUserProviderParams) (Provider, error)
+	DeleteWebhook(ctx context.Context, id int64) (Webhook, error)
+	GetActiveFlows(ctx context.Context) ([]Flow, error)
+	GetApiKey(ctx context.Context, id int64) (ApiKey, error)
+	GetApiKeys(ctx context.Context) ([]ApiKey, error)
 	GetAssistant(ctx context.Context, id int64) (Assistant, error)
 	GetAssistantUseAgents(ctx context.Context, id int64) (bool, error)
 	GetCallToolcall(ctx context.Context, callID string) (Toolcall, error)
+	GetCampaign(ctx context.Context, id int64) (Campaign, error)
+	GetCampaignFindings(ctx context.Context, campaignID int64) ([]GetCampaignFindingsRow, error)
+	GetCampaignFlows(ctx context.Context, campaignID int64) ([]Flow, error)
+	GetCampaignPendingTargets(ctx context.Context, arg GetCampaignPendingTargetsParams) ([]CampaignTarget, error)
+	GetCampaignTargets(ctx context.Context, campaignID int64) ([]CampaignTarget, error)
+	GetCampaignUsage(ctx context.Context, campaignID int64) ([]GetCampaignUsageRow, error)
+	GetCampaigns(ctx context.Context) ([]Campaign, error)
 	GetContainers(ctx context.Context) ([]Container, error)
+	GetContainersCountByStatus(ctx context.Context) ([]GetContainersCountByStatusRow, error)
+	GetDueSchedules(ctx context.Context, nextRunAt sql.NullTime) ([]Schedule, error)
+	GetDueWebhookDeliveries(ctx context.Context, arg GetDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	GetEventWebhooks(ctx context.Context, arg GetEventWebhooksParams) ([]Webhook, error)
 	GetFlow(ctx context.Context, id int64) (Flow, error)
 	GetFlowAgentLog(ctx context.Context, arg GetFlowAgentLogParams) (Agentlog, error)
 	GetFlowAgentLogs(ctx context.Context, flowID int64) ([]Agentlog, error)
+	GetFlowAgentLogsAsc(ctx context.Context, arg GetFlowAgentLogsAscParams) ([]Agentlog, error)
+	GetFlowAgentLogsDesc(ctx context.Context, arg GetFlowAgentLogsDescParams) ([]Agentlog, error)
 	GetFlowAssistant(ctx context.Context, arg GetFlowAssistantParams) (Assistant, error)
 	GetFlowAssistantLog(ctx context.Context, id int64) (Assistantlog, error)
 	GetFlowAssistantLogs(ctx context.Context, arg GetFlowAssistantLogsParams) ([]Assistantlog, error)
 	GetFlowAssistants(ctx context.Context, flowID int64) ([]Assistant, error)
 	GetFlowContainers(ctx context.Context, flowID int64) ([]Container, error)
+	GetFlowLimits(ctx context.Context, flowID int64) (FlowLimit, error)
 	GetFlowMsgChains(ctx context.Context, flowID int64) ([]Msgchain, error)
 	GetFlowMsgLogs(ctx context.Context, flowID int64) ([]Msglog, error)
+	GetFlowMsgLogsAsc(ctx context.Context, arg GetFlowMsgLogsAscParams) ([]Msglog, error)
+	GetFlowMsgLogsDesc(ctx context.Context, arg GetFlowMsgLogsDescParams) ([]Msglog, error)
+	GetFlowPlaybook(ctx context.Context, flowID int64) (FlowPlaybook, error)
 	GetFlowPrimaryContainer(ctx context.Context, flowID int64) (Container, error)
+	GetFlowQueue(ctx context.Context) ([]GetFlowQueueRow, error)
 	GetFlowScreenshots(ctx context.Context, flowID int64) ([]Screenshot, error)
 	GetFlowSearchLog(ctx context.Context, arg GetFlowSearchLogParams) (Searchlog, error)
 	GetFlowSearchLogs(ctx context.Context, flowID int64) ([]Searchlog, error)
+	GetFlowSearchLogsAsc(ctx context.Context, arg GetFlowSearchLogsAscParams) ([]Searchlog, error)
+	GetFlowSearchLogsDesc(ctx context.Context, arg GetFlowSearchLogsDescParams) ([]Searchlog, error)
+	GetFlowShare(ctx context.Context, id int64) (FlowShare, error)
+	GetFlowShareAccesses(ctx context.Context, shareID int64) ([]FlowShareAccess, error)
+	GetFlowShareByTokenHash(ctx context.Context, tokenHash string) (FlowShare, error)
+	GetFlowShares(ctx context.Context, flowID int64) ([]FlowShare, error)
 	GetFlowSubtask(ctx context.Context, arg GetFlowSubtaskParams) (Subtask, error)
 	GetFlowSubtasks(ctx context.Context, flowID int64) ([]Subtask, error)
 	GetFlowTask(ctx context.Context, arg GetFlowTaskParams) (Task, error)
@@ -63,19 +124,30 @@ type Querier interface {
 	GetFlowTaskTypeLastMsgChain(ctx context.Context, arg GetFlowTaskTypeLastMsgChainParams) (Msgchain, error)
 	GetFlowTasks(ctx context.Context, flowID int64) ([]Task, error)
 	GetFlowTermLogs(ctx context.Context, flowID int64) ([]Termlog, error)
+	GetFlowTermLogsAsc(ctx context.Context, arg GetFlowTermLogsAscParams) ([]Termlog, error)
+	GetFlowTermLogsDesc(ctx context.Context, arg GetFlowTermLogsDescParams) ([]Termlog, error)
 	GetFlowTypeMsgChains(ctx context.Context, arg GetFlowTypeMsgChainsParams) ([]Msgchain, error)
 	GetFlowVectorStoreLog(ctx context.Context, arg GetFlowVectorStoreLogParams) (Vecstorelog, error)
 	GetFlowVectorStoreLogs(ctx context.Context, flowID int64) ([]Vecstorelog, error)
+	GetFlowVectorStoreLogsAsc(ctx context.Context, arg GetFlowVectorStoreLogsAscParams) ([]Vecstorelog, error)
+	GetFlowVectorStoreLogsDesc(ctx context.Context, arg GetFlowVectorStoreLogsDescParams) ([]Vecstorelog, error)
 	GetFlows(ctx context.Context) ([]Flow, error)
+	GetFlowsCountByStatus(ctx context.Context) ([]GetFlowsCountByStatusRow, error)
 	GetMsgChain(ctx context.Context, id int64) (Msgchain, error)
 	GetPrompts(ctx context.Context) ([]Prompt, error)
 	GetProvider(ctx context.Context, id int64) (Provider, error)
 	GetProviders(ctx context.Context) ([]Provider, error)
 	GetProvidersByType(ctx context.Context, type_ ProviderType) ([]Provider, error)
+	GetQueueDepths(ctx context.Context) (GetQueueDepthsRow, error)
 	GetRole(ctx context.Context, id int64) (GetRoleRow, error)
 	GetRoleByName(ctx context.Context, name string) (GetRoleByNameRow, error)
 	GetRoles(ctx context.Context) ([]GetRolesRow, error)
+	GetRunningCampaigns(ctx context.Context) ([]Campaign, error)
 	GetRunningContainers(ctx context.Context) ([]Container, error)
+	GetSchedule(ctx context.Context, id int64) (Schedule, error)
+	GetScheduleActiveFlows(ctx context.Context, scheduleID int64) ([]Flow, error)
+	GetScheduleRuns(ctx context.Context, scheduleID int64) ([]ScheduleRun, error)
+	GetSchedules(ctx context.Context) ([]Schedule, error)
 	GetScreenshot(ctx context.Context, id int64) (Screenshot, error)
 	GetSubtask(ctx context.Context, id int64) (Subtask, error)
 	GetSubtaskAgentLogs(ctx context.Context, subtaskID sql.NullInt64) ([]Agentlog, error)
@@ -98,9 +170,17 @@ type Querier interface {
 	GetTaskSubtasks(ctx context.Context, taskID int64) ([]Subtask, error)
 	GetTaskTypeMsgChains(ctx context.Context, arg GetTaskTypeMsgChainsParams) ([]Msgchain, error)
 	GetTaskVectorStoreLogs(ctx context.Context, taskID sql.NullInt64) ([]Vecstorelog, error)
+	GetTeam(ctx context.Context, id int64) (Team, error)
+	GetTeamByName(ctx context.Context, lower string) (Team, error)
+	GetTeamMember(ctx context.Context, arg GetTeamMemberParams) (TeamMember, error)
+	GetTeamMembers(ctx context.Context, teamID int64) ([]GetTeamMembersRow, error)
+	GetTeamUserIDs(ctx context.Context, teamID int64) ([]int64, error)
+	GetTeams(ctx context.Context) ([]Team, error)
 	GetTermLog(ctx context.Context, id int64) (Termlog, error)
 	GetUser(ctx context.Context, id int64) (GetUserRow, error)
+	GetUserApiKeys(ctx context.Context, userID int64) ([]ApiKey, error)
 	GetUserByHash(ctx context.Context, hash string) (GetUserByHashRow, error)
+	GetUserCampaigns(ctx context.Context, userID int64) ([]Campaign, error)
 	GetUserContainers(ctx context.Context, userID int64) ([]Container, error)
 	GetUserFlow(ctx context.Context, arg GetUserFlowParams) (Flow, error)
 	GetUserFlowAgentLogs(ctx context.Context, arg GetUserFlowAgentLogsParams) ([]Agentlog, error)
@@ -118,6 +198,8 @@ type Querier interface {
 	GetUserFlowTermLogs(ctx context.Context, arg GetUserFlowTermLogsParams) ([]Termlog, error)
 	GetUserFlowVectorStoreLogs(ctx context.Context, arg GetUserFlowVectorStoreLogsParams) ([]Vecstorelog, error)
 	GetUserFlows(ctx context.Context, userID int64) ([]Flow, error)
+	GetUserPlaybook(ctx context.Context, arg GetUserPlaybookParams) (Playbook, error)
+	GetUserPlaybooks(ctx context.Context, userID int64) ([]Playbook, error)
 	GetUserPrompt(ctx context.Context, arg GetUserPromptParams) (Prompt, error)
 	GetUserPromptByType(ctx context.Context, arg GetUserPromptByTypeParams) (Prompt, error)
 	GetUserPrompts(ctx context.Context, userID int64) ([]Prompt, error)
@@ -125,7 +207,24 @@ type Querier interface {
 	GetUserProviderByName(ctx context.Context, arg GetUserProviderByNameParams) (Provider, error)
 	GetUserProviders(ctx context.Context, userID int64) ([]Provider, error)
 	GetUserProvidersByType(ctx context.Context, arg GetUserProvidersByTypeParams) ([]Provider, error)
+	GetUserSchedules(ctx context.Context, userID int64) ([]Schedule, error)
+	GetUserTeams(ctx context.Context, userID int64) ([]Team, error)
+	GetUserWebhooks(ctx context.Context, userID sql.NullInt64) ([]Webhook, error)
 	GetUsers(ctx context.Context) ([]GetUsersRow, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	RequeueFlows(ctx context.Context) ([]Flow, error)
+	RevokeApiKey(ctx context.Context, id int64) (ApiKey, error)
+	RevokeFlowShare(ctx context.Context, id int64) (FlowShare, error)
+	SearchFlows(ctx context.Context, arg SearchFlowsParams) ([]SearchFlowsRow, error)
+	SearchMsgLogs(ctx context.Context, arg SearchMsgLogsParams) ([]SearchMsgLogsRow, error)
+	SearchSearchLogs(ctx context.Context, arg SearchSearchLogsParams) ([]SearchSearchLogsRow, error)
+	SearchSubtasks(ctx context.Context, arg SearchSubtasksParams) ([]SearchSubtasksRow, error)
+	SearchTasks(ctx context.Context, arg SearchTasksParams) ([]SearchTasksRow, error)
+	SearchTermLogs(ctx context.Context, arg SearchTermLogsParams) ([]SearchTermLogsRow, error)
+	SetRolePrivileges(ctx context.Context, arg SetRolePrivilegesParams) error
+	SetTeamMember(ctx context.Context, arg SetTeamMemberParams) (TeamMember, error)
 	UpdateAssistant(ctx context.Context, arg UpdateAssistantParams) (Assistant, error)
 	UpdateAssistantLanguage(ctx context.Context, arg UpdateAssistantLanguageParams) (Assistant, error)
 	UpdateAssistantLog(ctx context.Context, arg UpdateAssistantLogParams) (Assistantlog, error)
@@ -135,6 +234,8 @@ type Querier interface {
 	UpdateAssistantStatus(ctx context.Context, arg UpdateAssistantStatusParams) (Assistant, error)
 	UpdateAssistantTitle(ctx context.Context, arg UpdateAssistantTitleParams) (Assistant, error)
 	UpdateAssistantUseAgents(ctx context.Context, arg UpdateAssistantUseAgentsParams) (Assistant, error)
+	UpdateCampaignStatus(ctx context.Context, arg UpdateCampaignStatusParams) (Campaign, error)
+	UpdateCampaignTarget(ctx context.Context, arg UpdateCampaignTargetParams) (CampaignTarget, error)
 	UpdateContainerImage(ctx context.Context, arg UpdateContainerImageParams) (Container, error)
 	UpdateContainerLocalDir(ctx context.Context, arg UpdateContainerLocalDirParams) (Container, error)
 	UpdateContainerLocalID(ctx context.Context, arg UpdateContainerLocalIDParams) (Container, error)
@@ -142,13 +243,22 @@ type Querier interface {
 	UpdateContainerStatusLocalID(ctx context.Context, arg UpdateContainerStatusLocalIDParams) (Container, error)
 	UpdateFlow(ctx context.Context, arg UpdateFlowParams) (Flow, error)
 	UpdateFlowLanguage(ctx context.Context, arg UpdateFlowLanguageParams) (Flow, error)
+	UpdateFlowPlaybookNextTask(ctx context.Context, arg UpdateFlowPlaybookNextTaskParams) (FlowPlaybook, error)
 	UpdateFlowStatus(ctx context.Context, arg UpdateFlowStatusParams) (Flow, error)
+	UpdateFlowTeam(ctx context.Context, arg UpdateFlowTeamParams) (Flow, error)
 	UpdateFlowTitle(ctx context.Context, arg UpdateFlowTitleParams) (Flow, error)
 	UpdateMsgChain(ctx context.Context, arg UpdateMsgChainParams) (Msgchain, error)
 	UpdateMsgChainUsage(ctx context.Context, arg UpdateMsgChainUsageParams) (Msgchain, error)
 	UpdateMsgLogResult(ctx context.Context, arg UpdateMsgLogResultParams) (Msglog, error)
+	UpdatePlaybookTeam(ctx context.Context, arg UpdatePlaybookTeamParams) (Playbook, error)
 	UpdatePrompt(ctx context.Context, arg UpdatePromptParams) (Prompt, error)
+	UpdatePromptTeam(ctx context.Context, arg UpdatePromptTeamParams) (Prompt, error)
 	UpdateProvider(ctx context.Context, arg UpdateProviderParams) (Provider, error)
+	UpdateProviderTeam(ctx context.Context, arg UpdateProviderTeamParams) (Provider, error)
+	UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error)
+	UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error)
+	UpdateScheduleEnabled(ctx context.Context, arg UpdateScheduleEnabledParams) (Schedule, error)
+	UpdateScheduleNextRun(ctx context.Context, arg UpdateScheduleNextRunParams) (Schedule, error)
 	UpdateSubtaskContext(ctx context.Context, arg UpdateSubtaskContextParams) (Subtask, error)
 	UpdateSubtaskFailedResult(ctx context.Context, arg UpdateSubtaskFailedResultParams) (Subtask, error)
 	UpdateSubtaskFinishedResult(ctx context.Context, arg UpdateSubtaskFinishedResultParams) (Subtask, error)
@@ -158,17 +268,22 @@ type Querier interface {
 	UpdateTaskFinishedResult(ctx context.Context, arg UpdateTaskFinishedResultParams) (Task, error)
 	UpdateTaskResult(ctx context.Context, arg UpdateTaskResultParams) (Task, error)
 	UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) (Task, error)
+	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
 	UpdateToolcallFailedResult(ctx context.Context, arg UpdateToolcallFailedResultParams) (Toolcall, error)
 	UpdateToolcallFinishedResult(ctx context.Context, arg UpdateToolcallFinishedResultParams) (Toolcall, error)
 	UpdateToolcallStatus(ctx context.Context, arg UpdateToolcallStatusParams) (Toolcall, error)
 	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
 	UpdateUserPasswordChangeRequired(ctx context.Context, arg UpdateUserPasswordChangeRequiredParams) (User, error)
+	UpdateUserPlaybook(ctx context.Context, arg UpdateUserPlaybookParams) (Playbook, error)
 	UpdateUserPrompt(ctx context.Context, arg UpdateUserPromptParams) (Prompt, error)
 	UpdateUserPromptByType(ctx context.Context, arg UpdateUserPromptByTypeParams) (Prompt, error)
 	UpdateUserProvider(ctx context.Context, arg UpdateUserProviderParams) (Provider, error)
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	UpsertFlowLimits(ctx context.Context, arg UpsertFlowLimitsParams) (FlowLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,6 +11,22 @@ import (
 	"github.com/lib/pq"
 )
 
+const createRole = `-- name: CreateRole :one
+INSERT INTO roles (
+  name
+) VALUES (
+  $1
+)
+RETURNING id, name
+`
+
+func (q *Queries) CreateRole(ctx context.Context, name string) (Role, error) {
+	row := q.db.QueryRowContext(ctx, createRole, name)
+	var i Role
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
 const getRole = `-- name: GetRole :one
 SELECT
   r.id,
@@ -104,3 +120,42 @@ func (q *Queries) GetRoles(ctx context.Context) ([]GetRolesRow, error) {
 	}
 	return items, nil
 }
+
+const setRolePrivileges = `-- name: SetRolePrivileges :exec
+WITH deleted AS (
+  DELETE FROM privileges
+  WHERE role_id = $1 AND name <> ALL($2::TEXT[])
+)
+INSERT INTO privileges (role_id, name)
+SELECT $1, UNNEST($2::TEXT[])
+ON CONFLICT DO NOTHING
+`
+
+type SetRolePrivilegesParams struct {
+	RoleID     int64    `json:"role_id"`
+	Privileges []string `json:"privileges"`
+}
+
+func (q *Queries) SetRolePrivileges(ctx context.Context, arg SetRolePrivilegesParams) error {
+	_, err := q.db.ExecContext(ctx, setRolePrivileges, arg.RoleID, pq.Array(arg.Privileges))
+	return err
+}
+
+const updateRole = `-- name: UpdateRole :one
+UPDATE roles
+SET name = $1
+WHERE id = $2
+RETURNING id, name
+`
+
+type UpdateRoleParams struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error) {
+	row := q.db.QueryRowContext(ctx, updateRole, arg.Name, arg.ID)
+	var i Role
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
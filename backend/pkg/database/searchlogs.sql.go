@@ -8,6 +8,8 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createSearchLog = `-- name: CreateSearchLog :one
@@ -139,6 +141,152 @@ func (q *Queries) GetFlowSearchLogs(ctx context.Context, flowID int64) ([]Search
 	return items, nil
 }
 
+const getFlowSearchLogsAsc = `-- name: GetFlowSearchLogsAsc :many
+SELECT
+  sl.id, sl.initiator, sl.executor, sl.engine, sl.query, sl.result, sl.flow_id, sl.task_id, sl.subtask_id, sl.created_at
+FROM searchlogs sl
+INNER JOIN flows f ON sl.flow_id = f.id
+WHERE sl.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR sl.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR sl.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR sl.engine::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR sl.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR sl.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR sl.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR sl.id < $8::BIGINT)
+ORDER BY sl.id ASC
+LIMIT $9
+`
+
+type GetFlowSearchLogsAscParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Engines       []string      `json:"engines"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowSearchLogsAsc(ctx context.Context, arg GetFlowSearchLogsAscParams) ([]Searchlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowSearchLogsAsc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Engines),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Searchlog
+	for rows.Next() {
+		var i Searchlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Engine,
+			&i.Query,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlowSearchLogsDesc = `-- name: GetFlowSearchLogsDesc :many
+SELECT
+  sl.id, sl.initiator, sl.executor, sl.engine, sl.query, sl.result, sl.flow_id, sl.task_id, sl.subtask_id, sl.created_at
+FROM searchlogs sl
+INNER JOIN flows f ON sl.flow_id = f.id
+WHERE sl.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR sl.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR sl.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR sl.engine::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR sl.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR sl.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR sl.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR sl.id < $8::BIGINT)
+ORDER BY sl.id DESC
+LIMIT $9
+`
+
+type GetFlowSearchLogsDescParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Engines       []string      `json:"engines"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowSearchLogsDesc(ctx context.Context, arg GetFlowSearchLogsDescParams) ([]Searchlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowSearchLogsDesc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Engines),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Searchlog
+	for rows.Next() {
+		var i Searchlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Engine,
+			&i.Query,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubtaskSearchLogs = `-- name: GetSubtaskSearchLogs :many
 SELECT
   sl.id, sl.initiator, sl.executor, sl.engine, sl.query, sl.result, sl.flow_id, sl.task_id, sl.subtask_id, sl.created_at
@@ -7,6 +7,9 @@ package database
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createTermLog = `-- name: CreateTermLog :one
@@ -79,6 +82,138 @@ func (q *Queries) GetFlowTermLogs(ctx context.Context, flowID int64) ([]Termlog,
 	return items, nil
 }
 
+const getFlowTermLogsAsc = `-- name: GetFlowTermLogsAsc :many
+SELECT
+  tl.id, tl.type, tl.text, tl.container_id, tl.created_at
+FROM termlogs tl
+INNER JOIN containers c ON tl.container_id = c.id
+INNER JOIN flows f ON c.flow_id = f.id
+WHERE c.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR tl.container_id = $2::BIGINT)
+  AND (cardinality($3::TEXT[]) = 0 OR tl.type::TEXT = ANY($3::TEXT[]))
+  AND ($4::TIMESTAMPTZ IS NULL OR tl.created_at >= $4::TIMESTAMPTZ)
+  AND ($5::TIMESTAMPTZ IS NULL OR tl.created_at < $5::TIMESTAMPTZ)
+  AND ($6::BIGINT IS NULL OR tl.id > $6::BIGINT)
+  AND ($7::BIGINT IS NULL OR tl.id < $7::BIGINT)
+ORDER BY tl.id ASC
+LIMIT $8
+`
+
+type GetFlowTermLogsAscParams struct {
+	FlowID        int64         `json:"flow_id"`
+	ContainerID   sql.NullInt64 `json:"container_id"`
+	Types         []string      `json:"types"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowTermLogsAsc(ctx context.Context, arg GetFlowTermLogsAscParams) ([]Termlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowTermLogsAsc,
+		arg.FlowID,
+		arg.ContainerID,
+		pq.Array(arg.Types),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Termlog
+	for rows.Next() {
+		var i Termlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Text,
+			&i.ContainerID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlowTermLogsDesc = `-- name: GetFlowTermLogsDesc :many
+SELECT
+  tl.id, tl.type, tl.text, tl.container_id, tl.created_at
+FROM termlogs tl
+INNER JOIN containers c ON tl.container_id = c.id
+INNER JOIN flows f ON c.flow_id = f.id
+WHERE c.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR tl.container_id = $2::BIGINT)
+  AND (cardinality($3::TEXT[]) = 0 OR tl.type::TEXT = ANY($3::TEXT[]))
+  AND ($4::TIMESTAMPTZ IS NULL OR tl.created_at >= $4::TIMESTAMPTZ)
+  AND ($5::TIMESTAMPTZ IS NULL OR tl.created_at < $5::TIMESTAMPTZ)
+  AND ($6::BIGINT IS NULL OR tl.id > $6::BIGINT)
+  AND ($7::BIGINT IS NULL OR tl.id < $7::BIGINT)
+ORDER BY tl.id DESC
+LIMIT $8
+`
+
+type GetFlowTermLogsDescParams struct {
+	FlowID        int64         `json:"flow_id"`
+	ContainerID   sql.NullInt64 `json:"container_id"`
+	Types         []string      `json:"types"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowTermLogsDesc(ctx context.Context, arg GetFlowTermLogsDescParams) ([]Termlog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowTermLogsDesc,
+		arg.FlowID,
+		arg.ContainerID,
+		pq.Array(arg.Types),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Termlog
+	for rows.Next() {
+		var i Termlog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Text,
+			&i.ContainerID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTermLog = `-- name: GetTermLog :one
 SELECT
   tl.id, tl.type, tl.text, tl.container_id, tl.created_at
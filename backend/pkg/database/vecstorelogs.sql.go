@@ -9,6 +9,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+
+	"github.com/lib/pq"
 )
 
 const createVectorStoreLog = `-- name: CreateVectorStoreLog :one
@@ -146,6 +148,154 @@ func (q *Queries) GetFlowVectorStoreLogs(ctx context.Context, flowID int64) ([]V
 	return items, nil
 }
 
+const getFlowVectorStoreLogsAsc = `-- name: GetFlowVectorStoreLogsAsc :many
+SELECT
+  vl.id, vl.initiator, vl.executor, vl.filter, vl.query, vl.action, vl.result, vl.flow_id, vl.task_id, vl.subtask_id, vl.created_at
+FROM vecstorelogs vl
+INNER JOIN flows f ON vl.flow_id = f.id
+WHERE vl.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR vl.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR vl.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR vl.action::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR vl.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR vl.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR vl.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR vl.id < $8::BIGINT)
+ORDER BY vl.id ASC
+LIMIT $9
+`
+
+type GetFlowVectorStoreLogsAscParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Actions       []string      `json:"actions"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowVectorStoreLogsAsc(ctx context.Context, arg GetFlowVectorStoreLogsAscParams) ([]Vecstorelog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowVectorStoreLogsAsc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Actions),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vecstorelog
+	for rows.Next() {
+		var i Vecstorelog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Filter,
+			&i.Query,
+			&i.Action,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlowVectorStoreLogsDesc = `-- name: GetFlowVectorStoreLogsDesc :many
+SELECT
+  vl.id, vl.initiator, vl.executor, vl.filter, vl.query, vl.action, vl.result, vl.flow_id, vl.task_id, vl.subtask_id, vl.created_at
+FROM vecstorelogs vl
+INNER JOIN flows f ON vl.flow_id = f.id
+WHERE vl.flow_id = $1 AND f.deleted_at IS NULL
+  AND ($2::BIGINT IS NULL OR vl.task_id = $2::BIGINT)
+  AND ($3::BIGINT IS NULL OR vl.subtask_id = $3::BIGINT)
+  AND (cardinality($4::TEXT[]) = 0 OR vl.action::TEXT = ANY($4::TEXT[]))
+  AND ($5::TIMESTAMPTZ IS NULL OR vl.created_at >= $5::TIMESTAMPTZ)
+  AND ($6::TIMESTAMPTZ IS NULL OR vl.created_at < $6::TIMESTAMPTZ)
+  AND ($7::BIGINT IS NULL OR vl.id > $7::BIGINT)
+  AND ($8::BIGINT IS NULL OR vl.id < $8::BIGINT)
+ORDER BY vl.id DESC
+LIMIT $9
+`
+
+type GetFlowVectorStoreLogsDescParams struct {
+	FlowID        int64         `json:"flow_id"`
+	TaskID        sql.NullInt64 `json:"task_id"`
+	SubtaskID     sql.NullInt64 `json:"subtask_id"`
+	Actions       []string      `json:"actions"`
+	CreatedAfter  sql.NullTime  `json:"created_after"`
+	CreatedBefore sql.NullTime  `json:"created_before"`
+	AfterID       sql.NullInt64 `json:"after_id"`
+	BeforeID      sql.NullInt64 `json:"before_id"`
+	RowLimit      int32         `json:"row_limit"`
+}
+
+func (q *Queries) GetFlowVectorStoreLogsDesc(ctx context.Context, arg GetFlowVectorStoreLogsDescParams) ([]Vecstorelog, error) {
+	rows, err := q.db.QueryContext(ctx, getFlowVectorStoreLogsDesc,
+		arg.FlowID,
+		arg.TaskID,
+		arg.SubtaskID,
+		pq.Array(arg.Actions),
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.AfterID,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Vecstorelog
+	for rows.Next() {
+		var i Vecstorelog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Initiator,
+			&i.Executor,
+			&i.Filter,
+			&i.Query,
+			&i.Action,
+			&i.Result,
+			&i.FlowID,
+			&i.TaskID,
+			&i.SubtaskID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSubtaskVectorStoreLogs = `-- name: GetSubtaskVectorStoreLogs :many
 SELECT
   vl.id, vl.initiator, vl.executor, vl.filter, vl.query, vl.action, vl.result, vl.flow_id, vl.task_id, vl.subtask_id, vl.created_at
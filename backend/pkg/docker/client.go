@@ -63,6 +63,7 @@ type DockerClient interface {
 	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	CopyToContainer(ctx context.Context, containerID string, dstPath string, content io.Reader, options container.CopyToContainerOptions) error
 	CopyFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, container.PathStat, error)
+	CommitContainer(ctx context.Context, containerID string, imageRef string) error
 	Cleanup(ctx context.Context) error
 	GetDefaultImage() string
 }
@@ -559,6 +560,28 @@ func (dc *dockerClient) CopyFromContainer(
 	return dc.client.CopyFromContainer(ctx, containerID, srcPath)
 }
 
+// CommitContainer saves the container filesystem as the local image imageRef,
+// the work folder is a mounted volume so it is not a part of the image
+func (dc *dockerClient) CommitContainer(ctx context.Context, containerID string, imageRef string) error {
+	logger := dc.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"local_id": containerID,
+		"image":    imageRef,
+	})
+	logger.Info("committing container")
+
+	_, err := dc.client.ContainerCommit(ctx, containerID, container.CommitOptions{
+		Reference: imageRef,
+		Pause:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit container: %w", err)
+	}
+
+	logger.Info("container committed")
+
+	return nil
+}
+
 func (dc *dockerClient) pullImage(ctx context.Context, imageName string) error {
 	filters := filters.NewArgs()
 	filters.Add("reference", imageName)
@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"context"
+
+	"pentagi/pkg/audit"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to record the audit events of the mutations.
+
+// recordAudit stores the event with the actor of the request, the actor is set by the graphql service
+func (r *Resolver) recordAudit(ctx context.Context, event audit.Event) {
+	if r.Audit == nil {
+		return
+	}
+
+	r.Audit.Record(ctx, event)
+}
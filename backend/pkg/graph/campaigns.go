@@ -0,0 +1,347 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/scheduler"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to validate the campaign input, aggregate the campaign
+// progress and run bulk operations over the campaign flows.
+
+const (
+	defaultCampaignConcurrency = 1
+	maxCampaignConcurrency     = 50
+	maxCampaignTargets         = 1000
+)
+
+// campaignParams validates the campaign input on behalf of the campaign owner and returns
+// the values for the campaign record and the deduplicated list of targets
+func (r *mutationResolver) campaignParams(
+	ctx context.Context,
+	userID int64,
+	campaign model.CampaignInput,
+) (database.CreateCampaignParams, []string, error) {
+	params := database.CreateCampaignParams{
+		UserID:            userID,
+		Name:              campaign.Name,
+		Variables:         json.RawMessage("{}"),
+		ModelProviderName: campaign.ModelProvider,
+		PlanReview:        campaign.PlanReview != nil && *campaign.PlanReview,
+		Concurrency:       defaultCampaignConcurrency,
+	}
+
+	if campaign.Name == "" {
+		return params, nil, fmt.Errorf("campaign name is required")
+	}
+	if campaign.ModelProvider == "" {
+		return params, nil, fmt.Errorf("model provider is required")
+	}
+	if campaign.Input != nil {
+		params.Input = *campaign.Input
+	}
+	if campaign.Scope != nil {
+		params.Scope = *campaign.Scope
+	}
+	if campaign.Concurrency != nil {
+		if *campaign.Concurrency < 1 || *campaign.Concurrency > maxCampaignConcurrency {
+			return params, nil, fmt.Errorf("concurrency must be between 1 and %d", maxCampaignConcurrency)
+		}
+		params.Concurrency = int32(*campaign.Concurrency)
+	}
+
+	targets := make([]string, 0, len(campaign.Targets))
+	seen := make(map[string]struct{}, len(campaign.Targets))
+	for _, target := range campaign.Targets {
+		target = strings.TrimSpace(target)
+		if _, ok := seen[target]; ok || target == "" {
+			continue
+		}
+		seen[target] = struct{}{}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return params, nil, fmt.Errorf("at least one target is required")
+	}
+	if len(targets) > maxCampaignTargets {
+		return params, nil, fmt.Errorf("too many targets, the limit is %d", maxCampaignTargets)
+	}
+
+	prv, err := r.ProvidersCtrl.GetProvider(ctx, provider.ProviderName(campaign.ModelProvider), userID)
+	if err != nil {
+		return params, nil, err
+	}
+	params.ModelProviderType = database.ProviderType(prv.Type())
+
+	switch {
+	case params.Input != "" && campaign.PlaybookID != nil:
+		return params, nil, fmt.Errorf("either input or playbook must be set, not both")
+	case params.Input != "":
+		return params, targets, nil
+	case campaign.PlaybookID == nil:
+		return params, nil, fmt.Errorf("user input or playbook is required")
+	}
+
+	if _, _, err := validatePermission(ctx, "playbooks.view"); err != nil {
+		return params, nil, err
+	}
+
+	pb, err := r.DB.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     *campaign.PlaybookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return params, nil, err
+	}
+	params.PlaybookID = database.Int64ToNullInt64(&pb.ID)
+
+	values := make(map[string]string, len(campaign.Variables))
+	for _, variable := range campaign.Variables {
+		values[variable.Name] = variable.Value
+	}
+	if params.Variables, err = json.Marshal(values); err != nil {
+		return params, nil, fmt.Errorf("failed to marshal playbook variables: %w", err)
+	}
+
+	// the playbook is rendered for each target, here it's checked that the variables fit it
+	variables, err := scheduler.TargetVariables(params.Variables, targets[0])
+	if err != nil {
+		return params, nil, err
+	}
+	if _, err := scheduler.RenderPlaybook(pb.Content, variables, params.Scope); err != nil {
+		return params, nil, err
+	}
+
+	return params, targets, nil
+}
+
+// campaignProgress aggregates the campaign targets by the flow status and sums the token usage,
+// the cost is estimated by the model prices of the campaign provider
+func (r *queryResolver) campaignProgress(
+	ctx context.Context,
+	campaign database.Campaign,
+) (*model.CampaignProgress, error) {
+	targets, err := r.DB.GetCampaignTargets(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := r.DB.GetCampaignFlows(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := r.DB.GetCampaignUsage(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := r.DB.GetCampaignFindings(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &model.CampaignProgress{
+		Total:    len(targets),
+		Findings: len(findings),
+	}
+
+	statuses := make(map[int64]database.FlowStatus, len(flows))
+	for _, flow := range flows {
+		statuses[flow.ID] = flow.Status
+	}
+
+	for _, target := range targets {
+		switch target.Status {
+		case database.CampaignTargetStatusPending:
+			progress.Pending++
+		case database.CampaignTargetStatusCancelled:
+			progress.Cancelled++
+		case database.CampaignTargetStatusFailed:
+			progress.FailedToStart++
+		}
+
+		if !target.FlowID.Valid {
+			continue
+		}
+
+		switch statuses[target.FlowID.Int64] {
+		case database.FlowStatusQueued:
+			progress.Queued++
+		case database.FlowStatusCreated:
+			progress.Created++
+		case database.FlowStatusRunning:
+			progress.Running++
+		case database.FlowStatusWaiting:
+			progress.Waiting++
+		case database.FlowStatusFinished:
+			progress.Finished++
+		case database.FlowStatusFailed:
+			progress.Failed++
+		}
+	}
+
+	// the provider may be deleted since the campaign was created, the usage is still reported
+	prv, err := r.ProvidersCtrl.GetProvider(ctx, provider.ProviderName(campaign.ModelProviderName), campaign.UserID)
+	if err != nil {
+		r.Logger.WithError(err).WithField("campaign", campaign.ID).Warn("failed to get provider, cost is not estimated")
+		prv = nil
+	}
+
+	for _, row := range usage {
+		mu := &model.CampaignModelUsage{
+			Model:           row.Model,
+			UsageIn:         int(row.UsageIn),
+			UsageOut:        int(row.UsageOut),
+			UsageCacheRead:  int(row.UsageCacheRead),
+			UsageCacheWrite: int(row.UsageCacheWrite),
+		}
+		if price := modelPrice(prv, row.Model); price != nil {
+			mu.Cost = (float64(row.UsageIn)*price.Input + float64(row.UsageOut)*price.Output) / 1e6
+		}
+		progress.Cost += mu.Cost
+		progress.Usage = append(progress.Usage, mu)
+	}
+
+	return progress, nil
+}
+
+// modelPrice returns the price per million tokens of the model from the provider models list
+// or from the agent which is configured to use the model
+func modelPrice(prv provider.Provider, modelName string) *pconfig.PriceInfo {
+	if prv == nil {
+		return nil
+	}
+
+	if mc := prv.GetModels().Find(modelName); mc != nil && mc.Price != nil {
+		return mc.Price
+	}
+
+	for _, opt := range pconfig.AllAgentTypes {
+		if prv.Model(opt) == modelName {
+			if price := prv.GetPriceInfo(opt); price != nil {
+				return price
+			}
+		}
+	}
+
+	return nil
+}
+
+// campaignExport renders the combined markdown report with the campaign summary
+// and the results of the finished tasks grouped by the target
+func (r *queryResolver) campaignExport(ctx context.Context, campaign database.Campaign) (string, error) {
+	targets, err := r.DB.GetCampaignTargets(ctx, campaign.ID)
+	if err != nil {
+		return "", err
+	}
+
+	flows, err := r.DB.GetCampaignFlows(ctx, campaign.ID)
+	if err != nil {
+		return "", err
+	}
+
+	findings, err := r.DB.GetCampaignFindings(ctx, campaign.ID)
+	if err != nil {
+		return "", err
+	}
+
+	statuses := make(map[int64]database.FlowStatus, len(flows))
+	for _, flow := range flows {
+		statuses[flow.ID] = flow.Status
+	}
+
+	results := make(map[int64][]database.GetCampaignFindingsRow)
+	for _, finding := range findings {
+		results[finding.FlowID] = append(results[finding.FlowID], finding)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", campaign.Name)
+	fmt.Fprintf(&sb, "- Status: %s\n", campaign.Status)
+	fmt.Fprintf(&sb, "- Provider: %s\n", campaign.ModelProviderName)
+	fmt.Fprintf(&sb, "- Targets: %d\n", len(targets))
+	fmt.Fprintf(&sb, "- Findings: %d\n\n", len(findings))
+
+	sb.WriteString("| Target | Status | Flow | Flow status |\n")
+	sb.WriteString("|--------|--------|------|-------------|\n")
+	for _, target := range targets {
+		flow, status := "-", "-"
+		if target.FlowID.Valid {
+			flow = fmt.Sprintf("%d", target.FlowID.Int64)
+			status = string(statuses[target.FlowID.Int64])
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", target.Target, target.Status, flow, status)
+	}
+
+	for _, target := range targets {
+		fmt.Fprintf(&sb, "\n## %s\n\n", target.Target)
+
+		switch {
+		case target.Reason != "":
+			fmt.Fprintf(&sb, "Flow was not started: %s\n", target.Reason)
+			continue
+		case !target.FlowID.Valid:
+			sb.WriteString("Flow was not started.\n")
+			continue
+		case len(results[target.FlowID.Int64]) == 0:
+			fmt.Fprintf(&sb, "Flow %d has no finished tasks.\n", target.FlowID.Int64)
+			continue
+		}
+
+		for _, result := range results[target.FlowID.Int64] {
+			fmt.Fprintf(&sb, "### %s\n\n%s\n\n", result.Title, strings.TrimSpace(result.Result))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// stopCampaign stops launching of the pending targets and applies the action
+// to every campaign flow which is selected by the filter
+func (r *mutationResolver) stopCampaign(
+	ctx context.Context,
+	campaign database.Campaign,
+	status database.CampaignStatus,
+	filter func(database.FlowStatus) bool,
+	action func(ctx context.Context, flowID int64) error,
+) (database.Campaign, error) {
+	campaign, err := r.DB.UpdateCampaignStatus(ctx, database.UpdateCampaignStatusParams{
+		Status: status,
+		ID:     campaign.ID,
+	})
+	if err != nil {
+		return campaign, err
+	}
+
+	if err := r.DB.CancelCampaignPendingTargets(ctx, campaign.ID); err != nil {
+		return campaign, err
+	}
+
+	flows, err := r.DB.GetCampaignFlows(ctx, campaign.ID)
+	if err != nil {
+		return campaign, err
+	}
+
+	var errs []error
+	for _, flow := range flows {
+		if !filter(flow.Status) {
+			continue
+		}
+		if err := action(ctx, flow.ID); err != nil {
+			errs = append(errs, fmt.Errorf("flow %d: %w", flow.ID, err))
+		}
+	}
+
+	return campaign, errors.Join(errs...)
+}
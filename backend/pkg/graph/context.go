@@ -2,9 +2,11 @@ package graph
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"pentagi/pkg/database"
+	"pentagi/pkg/server/models"
 	"regexp"
 	"slices"
 )
@@ -85,7 +87,119 @@ func validatePermissionWithFlowID(
 		return 0, err
 	}
 
-	if !admin && flow.UserID != int64(uid) {
+	if !admin && flow.UserID != int64(uid) && !allowedByTeam(ctx, db, flow.TeamID, uid, perm) {
+		return 0, fmt.Errorf("not permitted")
+	}
+
+	return uid, nil
+}
+
+// allowedByTeam checks that the user is a member of the team which the resource is shared with
+// and the member role is enough to use the privilege on it
+func allowedByTeam(ctx context.Context, db database.Querier, teamID sql.NullInt64, uid int64, perm string) bool {
+	if !teamID.Valid {
+		return false
+	}
+
+	member, err := db.GetTeamMember(ctx, database.GetTeamMemberParams{
+		TeamID: teamID.Int64,
+		UserID: uid,
+	})
+	if err != nil {
+		return false
+	}
+
+	return models.TeamRole(member.Role).Allows(models.TeamRoleForPrivilege(perm))
+}
+
+func validatePermissionWithScheduleID(
+	ctx context.Context,
+	perm string,
+	scheduleID int64,
+	db database.Querier,
+) (int64, database.Schedule, error) {
+	uid, admin, err := validatePermission(ctx, perm)
+	if err != nil {
+		return 0, database.Schedule{}, err
+	}
+
+	schedule, err := db.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return 0, database.Schedule{}, err
+	}
+
+	if !admin && schedule.UserID != int64(uid) {
+		return 0, database.Schedule{}, fmt.Errorf("not permitted")
+	}
+
+	return uid, schedule, nil
+}
+
+func validatePermissionWithCampaignID(
+	ctx context.Context,
+	perm string,
+	campaignID int64,
+	db database.Querier,
+) (int64, database.Campaign, error) {
+	uid, admin, err := validatePermission(ctx, perm)
+	if err != nil {
+		return 0, database.Campaign{}, err
+	}
+
+	campaign, err := db.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return 0, database.Campaign{}, err
+	}
+
+	if !admin && campaign.UserID != int64(uid) {
+		return 0, database.Campaign{}, fmt.Errorf("not permitted")
+	}
+
+	return uid, campaign, nil
+}
+
+// validatePermissionWithWebhookID permits the system-wide webhooks to the admin only
+func validatePermissionWithWebhookID(
+	ctx context.Context,
+	perm string,
+	webhookID int64,
+	db database.Querier,
+) (int64, database.Webhook, error) {
+	uid, admin, err := validatePermission(ctx, perm)
+	if err != nil {
+		return 0, database.Webhook{}, err
+	}
+
+	webhook, err := db.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return 0, database.Webhook{}, err
+	}
+
+	if !admin && (!webhook.UserID.Valid || webhook.UserID.Int64 != uid) {
+		return 0, database.Webhook{}, fmt.Errorf("not permitted")
+	}
+
+	return uid, webhook, nil
+}
+
+// validatePermissionWithAPIKeyID permits the API keys of the other users to the admin only
+func validatePermissionWithAPIKeyID(
+	ctx context.Context,
+	perm string,
+	apiKeyID int64,
+	db database.Querier,
+) (int64, error) {
+	uid, admin, err := validatePermission(ctx, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := db.GetApiKey(ctx, apiKeyID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !admin && key.UserID != uid {
 		return 0, fmt.Errorf("not permitted")
 	}
 
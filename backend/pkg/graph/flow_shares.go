@@ -0,0 +1,237 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/redact"
+
+	"github.com/sirupsen/logrus"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to serve the read-only flow data by the share link token.
+
+const FlowShareKey GqlContextKey = "flowShare"
+
+// flowShareCheckInterval is how often the shared subscriptions check that the link isn't revoked
+const flowShareCheckInterval = 30 * time.Second
+
+// FlowShareAccess describes the request which is made with the share link token instead of the user session
+type FlowShareAccess struct {
+	ShareID    int64
+	RemoteAddr string
+	UserAgent  string
+}
+
+func GetFlowShareAccess(ctx context.Context) (FlowShareAccess, error) {
+	access, ok := ctx.Value(FlowShareKey).(FlowShareAccess)
+	if !ok {
+		return FlowShareAccess{}, errors.New("flow share not found")
+	}
+	return access, nil
+}
+
+func SetFlowShareAccess(ctx context.Context, access FlowShareAccess) context.Context {
+	return context.WithValue(ctx, FlowShareKey, access)
+}
+
+// validateFlowShareAccess loads the share from the request context and records the access to the resource,
+// the share is loaded on every request so the revoked link stops working on the opened websocket too
+func validateFlowShareAccess(
+	ctx context.Context,
+	resource string,
+	db database.Querier,
+	logger *logrus.Entry,
+) (database.FlowShare, error) {
+	access, err := GetFlowShareAccess(ctx)
+	if err != nil {
+		return database.FlowShare{}, fmt.Errorf("unauthorized: invalid flow share: %v", err)
+	}
+
+	share, err := getActiveFlowShare(ctx, db, access.ShareID)
+	if err != nil {
+		return database.FlowShare{}, err
+	}
+
+	_, err = db.CreateFlowShareAccess(ctx, database.CreateFlowShareAccessParams{
+		ShareID:    share.ID,
+		Resource:   resource,
+		RemoteAddr: access.RemoteAddr,
+		UserAgent:  access.UserAgent,
+	})
+	if err != nil {
+		return database.FlowShare{}, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"share":       share.ID,
+		"flow":        share.FlowID,
+		"resource":    resource,
+		"remote_addr": access.RemoteAddr,
+	}).Info("flow share link access")
+
+	return share, nil
+}
+
+func getActiveFlowShare(ctx context.Context, db database.Querier, shareID int64) (database.FlowShare, error) {
+	share, err := db.GetFlowShare(ctx, shareID)
+	if err != nil {
+		return database.FlowShare{}, fmt.Errorf("unauthorized: invalid flow share: %v", err)
+	}
+
+	if share.RevokedAt.Valid || !time.Now().Before(share.ExpiresAt) {
+		return database.FlowShare{}, errors.New("unauthorized: flow share link is either revoked or expired")
+	}
+
+	// the link to the deleted flow stops working as well
+	if _, err := db.GetFlow(ctx, share.FlowID); err != nil {
+		return database.FlowShare{}, fmt.Errorf("unauthorized: invalid flow share: %v", err)
+	}
+
+	return share, nil
+}
+
+// validatePermissionWithFlowShareID checks the privilege for the flow of the share,
+// the creator of the share link can always manage it
+func validatePermissionWithFlowShareID(
+	ctx context.Context,
+	perm string,
+	shareID int64,
+	db database.Querier,
+) (int64, error) {
+	share, err := db.GetFlowShare(ctx, shareID)
+	if err != nil {
+		return 0, err
+	}
+
+	if uid, _, err := validatePermission(ctx, perm); err == nil && uid == share.UserID {
+		return uid, nil
+	}
+
+	return validatePermissionWithFlowID(ctx, perm, share.FlowID, db)
+}
+
+// subscribeFlowShare forwards the events of the shared flow while the share link is active,
+// the filter drops the events of other flows and redacts the secrets from the rest
+func subscribeFlowShare[T any](
+	ctx context.Context,
+	db database.Querier,
+	share database.FlowShare,
+	subscribe func(ctx context.Context) (<-chan T, error),
+	filter func(data T) (T, bool),
+) (<-chan T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	events, err := subscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	result := make(chan T)
+	go func() {
+		defer close(result)
+		defer cancel()
+
+		expired := time.NewTimer(time.Until(share.ExpiresAt))
+		defer expired.Stop()
+
+		check := time.NewTicker(flowShareCheckInterval)
+		defer check.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expired.C:
+				return
+			case <-check.C:
+				if _, err := getActiveFlowShare(ctx, db, share.ID); err != nil {
+					return
+				}
+			case data, ok := <-events:
+				if !ok {
+					return
+				}
+				if data, ok = filter(data); !ok {
+					continue
+				}
+				select {
+				case result <- data:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+// shareRedactor returns the redactor which also replaces the secrets from the provider configs stored
+// in the database, they are loaded on every request because users change their providers at runtime
+func shareRedactor(ctx context.Context, db database.Querier, r *redact.Redactor) (*redact.Redactor, error) {
+	providers, err := db.GetProviders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get providers: %w", err)
+	}
+
+	var values []string
+	for _, prv := range providers {
+		values = append(values, redact.ConfigValues(prv.Config)...)
+	}
+
+	return r.With(values...), nil
+}
+
+// redact functions return the copies because the same event is delivered to every subscriber of the flow
+
+func redactFlow(r *redact.Redactor, flow *model.Flow) *model.Flow {
+	redacted := *flow
+	redacted.Title = r.String(flow.Title)
+	return &redacted
+}
+
+func redactTask(r *redact.Redactor, task *model.Task) *model.Task {
+	redacted := *task
+	redacted.Title = r.String(task.Title)
+	redacted.Input = r.String(task.Input)
+	redacted.Result = r.String(task.Result)
+	if task.Subtasks != nil {
+		redacted.Subtasks = make([]*model.Subtask, 0, len(task.Subtasks))
+	}
+	for _, subtask := range task.Subtasks {
+		rsubtask := *subtask
+		rsubtask.Title = r.String(subtask.Title)
+		rsubtask.Description = r.String(subtask.Description)
+		rsubtask.Result = r.String(subtask.Result)
+		redacted.Subtasks = append(redacted.Subtasks, &rsubtask)
+	}
+	return &redacted
+}
+
+func redactScreenshot(r *redact.Redactor, screenshot *model.Screenshot) *model.Screenshot {
+	redacted := *screenshot
+	redacted.URL = r.String(screenshot.URL)
+	return &redacted
+}
+
+func redactTerminalLog(r *redact.Redactor, log *model.TerminalLog) *model.TerminalLog {
+	redacted := *log
+	redacted.Text = r.String(log.Text)
+	return &redacted
+}
+
+func redactMessageLog(r *redact.Redactor, log *model.MessageLog) *model.MessageLog {
+	redacted := *log
+	redacted.Message = r.String(log.Message)
+	redacted.Thinking = r.StringPtr(log.Thinking)
+	redacted.Result = r.String(log.Result)
+	return &redacted
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/redact"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providersQuerier struct {
+	database.Querier
+	providers []database.Provider
+	err       error
+}
+
+func (q *providersQuerier) GetProviders(context.Context) ([]database.Provider, error) {
+	return q.providers, q.err
+}
+
+func TestShareRedactorMasksDatabaseProviderKeys(t *testing.T) {
+	db := &providersQuerier{providers: []database.Provider{
+		{ID: 1, Name: "custom", Config: json.RawMessage(`{"api_key": "db-stored-provider-key"}`)},
+		{ID: 2, Name: "defaults", Config: json.RawMessage(`{"simple": {"model": "gpt-4.1"}}`)},
+	}}
+	base := redact.New("configured-env-key")
+
+	r, err := shareRedactor(context.Background(), db, base)
+	require.NoError(t, err)
+
+	thinking := "the key db-stored-provider-key is loaded"
+	log := redactMessageLog(r, &model.MessageLog{
+		Message:  "curl -H 'X-Key: db-stored-provider-key' https://llm.local",
+		Thinking: &thinking,
+		Result:   "configured-env-key and db-stored-provider-key",
+	})
+	assert.Equal(t, "curl -H 'X-Key: [REDACTED]' https://llm.local", log.Message)
+	assert.Equal(t, "the key [REDACTED] is loaded", *log.Thinking)
+	assert.Equal(t, "[REDACTED] and [REDACTED]", log.Result)
+
+	task := redactTask(r, &model.Task{
+		Title:    "use gpt-4.1",
+		Subtasks: []*model.Subtask{{Description: "export KEY=db-stored-provider-key"}},
+	})
+	assert.Equal(t, "use gpt-4.1", task.Title)
+	assert.Equal(t, "export KEY=[REDACTED]", task.Subtasks[0].Description)
+
+	// the base redactor shared by all requests isn't changed
+	assert.Equal(t, "db-stored-provider-key", base.String("db-stored-provider-key"))
+}
+
+func TestShareRedactorProvidersError(t *testing.T) {
+	db := &providersQuerier{err: errors.New("connection refused")}
+
+	_, err := shareRedactor(context.Background(), db, redact.New())
+	assert.ErrorContains(t, err, "connection refused")
+}
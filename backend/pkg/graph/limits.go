@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions for the flow watchdog limits.
+
+// flowLimits validates the flow limits input, nil input keeps the global limits from the config
+func flowLimits(limits *model.FlowLimitsInput) (*database.FlowLimit, error) {
+	if limits == nil {
+		return nil, nil
+	}
+
+	var fl database.FlowLimit
+	fields := []struct {
+		name  string
+		value *int
+		limit *int32
+	}{
+		{"flowTimeLimit", limits.FlowTimeLimit, &fl.FlowTimeLimit},
+		{"taskTimeLimit", limits.TaskTimeLimit, &fl.TaskTimeLimit},
+		{"subtaskTimeLimit", limits.SubtaskTimeLimit, &fl.SubtaskTimeLimit},
+		{"maxIterations", limits.MaxIterations, &fl.MaxIterations},
+		{"maxToolCalls", limits.MaxToolCalls, &fl.MaxToolCalls},
+		{"maxDelegationDepth", limits.MaxDelegationDepth, &fl.MaxDelegationDepth},
+	}
+
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < 0 || *field.value > math.MaxInt32 {
+			return nil, fmt.Errorf("%s must be between 0 and %d", field.name, math.MaxInt32)
+		}
+		*field.limit = int32(*field.value)
+	}
+
+	return &fl, nil
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pentagi/pkg/graph/model"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions for the cursor-based pagination of the flow logs.
+
+const (
+	defaultLogsPageSize = 100
+	maxLogsPageSize     = 1000
+	logCursorPrefix     = "log:"
+)
+
+// logsPage is the keyset window over the log ids, the rows are fetched in the sql order
+// and the backward pages are reversed to the requested order after fetching
+type logsPage struct {
+	afterID   sql.NullInt64 // lower exclusive bound of the log id
+	beforeID  sql.NullInt64 // upper exclusive bound of the log id
+	limit     int
+	desc      bool
+	backward  bool
+	hasAfter  bool
+	hasBefore bool
+}
+
+func newLogsPage(first *int, after *string, last *int, before *string, order *model.LogOrder) (*logsPage, error) {
+	if first != nil && last != nil {
+		return nil, fmt.Errorf("first and last can't be used together")
+	}
+
+	page := &logsPage{
+		limit:     defaultLogsPageSize,
+		backward:  last != nil,
+		hasAfter:  after != nil,
+		hasBefore: before != nil,
+	}
+	for _, size := range []*int{first, last} {
+		if size == nil {
+			continue
+		}
+		if *size < 1 || *size > maxLogsPageSize {
+			return nil, fmt.Errorf("page size must be between 1 and %d", maxLogsPageSize)
+		}
+		page.limit = *size
+	}
+
+	afterID, err := decodeLogCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	beforeID, err := decodeLogCursor(before)
+	if err != nil {
+		return nil, err
+	}
+
+	// cursors are relative to the requested order, so they swap bounds for the descending order
+	reverse := order != nil && *order == model.LogOrderDesc
+	if reverse {
+		page.afterID, page.beforeID = beforeID, afterID
+	} else {
+		page.afterID, page.beforeID = afterID, beforeID
+	}
+	page.desc = reverse != page.backward
+
+	return page, nil
+}
+
+// rowLimit fetches one extra row to detect the next page
+func (p *logsPage) rowLimit() int32 {
+	return int32(p.limit + 1)
+}
+
+// logsPageRows trims the extra row, restores the requested order and builds the page info
+func logsPageRows[T any](p *logsPage, rows []T, id func(T) int64) ([]T, *model.PageInfo) {
+	hasMore := len(rows) > p.limit
+	if hasMore {
+		rows = rows[:p.limit]
+	}
+
+	info := &model.PageInfo{
+		HasNextPage:     hasMore,
+		HasPreviousPage: p.hasAfter,
+	}
+	if p.backward {
+		slices.Reverse(rows)
+		info.HasNextPage, info.HasPreviousPage = p.hasBefore, hasMore
+	}
+
+	if len(rows) > 0 {
+		startCursor := encodeLogCursor(id(rows[0]))
+		endCursor := encodeLogCursor(id(rows[len(rows)-1]))
+		info.StartCursor, info.EndCursor = &startCursor, &endCursor
+	}
+
+	return rows, info
+}
+
+func encodeLogCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(logCursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+func decodeLogCursor(cursor *string) (sql.NullInt64, error) {
+	if cursor == nil {
+		return sql.NullInt64{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil || !strings.HasPrefix(string(data), logCursorPrefix) {
+		return sql.NullInt64{}, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(data), logCursorPrefix), 10, 64)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("invalid cursor")
+	}
+
+	return sql.NullInt64{Int64: id, Valid: true}, nil
+}
+
+// logTypes returns not nil slice because the sql filter treats the empty array as no filter
+func logTypes[T ~string](types []T) []string {
+	result := make([]string, 0, len(types))
+	for _, t := range types {
+		result = append(result, string(t))
+	}
+
+	return result
+}
+
+func logTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
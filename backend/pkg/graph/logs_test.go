@@ -0,0 +1,264 @@
+package graph
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"slices"
+	"testing"
+
+	"pentagi/pkg/graph/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func logCursor(id int64) *string {
+	return ptr(encodeLogCursor(id))
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: true}
+}
+
+func TestNewLogsPage(t *testing.T) {
+	asc, desc := ptr(model.LogOrderAsc), ptr(model.LogOrderDesc)
+
+	tests := []struct {
+		name     string
+		first    *int
+		after    *string
+		last     *int
+		before   *string
+		order    *model.LogOrder
+		want     logsPage
+		wantErr  string
+		rowLimit int32
+	}{
+		{
+			name:     "default page size and order",
+			want:     logsPage{limit: defaultLogsPageSize},
+			rowLimit: defaultLogsPageSize + 1,
+		},
+		{
+			name:     "max page size",
+			first:    ptr(maxLogsPageSize),
+			want:     logsPage{limit: maxLogsPageSize},
+			rowLimit: maxLogsPageSize + 1,
+		},
+		{
+			name:  "asc forward",
+			first: ptr(10), after: logCursor(5), order: asc,
+			want: logsPage{afterID: nullID(5), limit: 10, hasAfter: true},
+		},
+		{
+			name: "asc backward",
+			last: ptr(10), before: logCursor(50), order: asc,
+			want: logsPage{beforeID: nullID(50), limit: 10, desc: true, backward: true, hasBefore: true},
+		},
+		{
+			name:  "asc forward between cursors",
+			first: ptr(10), after: logCursor(5), before: logCursor(50), order: asc,
+			want: logsPage{afterID: nullID(5), beforeID: nullID(50), limit: 10, hasAfter: true, hasBefore: true},
+		},
+		{
+			name:  "desc forward swaps cursor bounds",
+			first: ptr(10), after: logCursor(50), order: desc,
+			want: logsPage{beforeID: nullID(50), limit: 10, desc: true, hasAfter: true},
+		},
+		{
+			name: "desc backward swaps cursor bounds",
+			last: ptr(10), before: logCursor(5), order: desc,
+			want: logsPage{afterID: nullID(5), limit: 10, backward: true, hasBefore: true},
+		},
+		{
+			name:  "desc forward between cursors",
+			first: ptr(10), after: logCursor(50), before: logCursor(5), order: desc,
+			want: logsPage{afterID: nullID(5), beforeID: nullID(50), limit: 10, desc: true, hasAfter: true, hasBefore: true},
+		},
+		{
+			name: "backward without order",
+			last: ptr(20),
+			want: logsPage{limit: 20, desc: true, backward: true},
+		},
+		{
+			name:  "first with last",
+			first: ptr(10), last: ptr(10),
+			wantErr: "first and last can't be used together",
+		},
+		{
+			name:    "zero first",
+			first:   ptr(0),
+			wantErr: "page size must be between 1 and 1000",
+		},
+		{
+			name:    "negative last",
+			last:    ptr(-1),
+			wantErr: "page size must be between 1 and 1000",
+		},
+		{
+			name:    "first over the limit",
+			first:   ptr(maxLogsPageSize + 1),
+			wantErr: "page size must be between 1 and 1000",
+		},
+		{
+			name:    "last over the limit",
+			last:    ptr(maxLogsPageSize + 1),
+			wantErr: "page size must be between 1 and 1000",
+		},
+		{
+			name:    "malformed after cursor",
+			after:   ptr("not base64!"),
+			wantErr: "invalid cursor",
+		},
+		{
+			name:    "before cursor without prefix",
+			before:  ptr(base64.RawURLEncoding.EncodeToString([]byte("42"))),
+			wantErr: "invalid cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newLogsPage(tt.first, tt.after, tt.last, tt.before, tt.order)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *page)
+			if tt.rowLimit != 0 {
+				assert.Equal(t, tt.rowLimit, page.rowLimit())
+			}
+		})
+	}
+}
+
+// fetchLogIDs emulates the keyset page query over the log ids
+func fetchLogIDs(page *logsPage, ids []int64) []int64 {
+	var rows []int64
+	for _, id := range ids {
+		if page.afterID.Valid && id <= page.afterID.Int64 {
+			continue
+		}
+		if page.beforeID.Valid && id >= page.beforeID.Int64 {
+			continue
+		}
+		rows = append(rows, id)
+	}
+
+	slices.Sort(rows)
+	if page.desc {
+		slices.Reverse(rows)
+	}
+	if limit := int(page.rowLimit()); len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	return rows
+}
+
+func TestLogsPageRows(t *testing.T) {
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	size := ptr(3)
+	asc, desc := ptr(model.LogOrderAsc), ptr(model.LogOrderDesc)
+
+	tests := []struct {
+		name         string
+		first, last  *int
+		after        *string
+		before       *string
+		order        *model.LogOrder
+		want         []int64
+		wantNext     bool
+		wantPrevious bool
+	}{
+		{name: "asc first page", first: size, order: asc, want: []int64{1, 2, 3}, wantNext: true},
+		{name: "asc middle page", first: size, after: logCursor(3), order: asc,
+			want: []int64{4, 5, 6}, wantNext: true, wantPrevious: true},
+		{name: "asc tail page", first: size, after: logCursor(8), order: asc,
+			want: []int64{9, 10}, wantPrevious: true},
+		{name: "asc exact tail page", first: size, after: logCursor(7), order: asc,
+			want: []int64{8, 9, 10}, wantPrevious: true},
+		{name: "asc last page", last: size, order: asc, want: []int64{8, 9, 10}, wantPrevious: true},
+		{name: "asc backward middle page", last: size, before: logCursor(8), order: asc,
+			want: []int64{5, 6, 7}, wantNext: true, wantPrevious: true},
+		{name: "asc backward head page", last: size, before: logCursor(3), order: asc,
+			want: []int64{1, 2}, wantNext: true},
+		{name: "desc first page", first: size, order: desc, want: []int64{10, 9, 8}, wantNext: true},
+		{name: "desc middle page", first: size, after: logCursor(8), order: desc,
+			want: []int64{7, 6, 5}, wantNext: true, wantPrevious: true},
+		{name: "desc tail page", first: size, after: logCursor(3), order: desc,
+			want: []int64{2, 1}, wantPrevious: true},
+		{name: "desc last page", last: size, order: desc, want: []int64{3, 2, 1}, wantPrevious: true},
+		{name: "desc backward middle page", last: size, before: logCursor(3), order: desc,
+			want: []int64{6, 5, 4}, wantNext: true, wantPrevious: true},
+		{name: "desc backward head page", last: size, before: logCursor(8), order: desc,
+			want: []int64{10, 9}, wantNext: true},
+		{name: "default order", first: size, want: []int64{1, 2, 3}, wantNext: true},
+		{name: "empty page", first: size, after: logCursor(10), order: asc, wantPrevious: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newLogsPage(tt.first, tt.after, tt.last, tt.before, tt.order)
+			require.NoError(t, err)
+
+			rows, info := logsPageRows(page, fetchLogIDs(page, ids), func(id int64) int64 { return id })
+			assert.Equal(t, tt.want, rows)
+			assert.Equal(t, tt.wantNext, info.HasNextPage, "has next page")
+			assert.Equal(t, tt.wantPrevious, info.HasPreviousPage, "has previous page")
+
+			if len(tt.want) == 0 {
+				assert.Nil(t, info.StartCursor)
+				assert.Nil(t, info.EndCursor)
+				return
+			}
+			require.NotNil(t, info.StartCursor)
+			require.NotNil(t, info.EndCursor)
+			assert.Equal(t, encodeLogCursor(tt.want[0]), *info.StartCursor)
+			assert.Equal(t, encodeLogCursor(tt.want[len(tt.want)-1]), *info.EndCursor)
+		})
+	}
+}
+
+func TestDecodeLogCursor(t *testing.T) {
+	encode := func(s string) *string {
+		return ptr(base64.RawURLEncoding.EncodeToString([]byte(s)))
+	}
+
+	tests := []struct {
+		name    string
+		cursor  *string
+		want    sql.NullInt64
+		wantErr bool
+	}{
+		{name: "no cursor"},
+		{name: "round trip", cursor: logCursor(42), want: nullID(42)},
+		{name: "large id", cursor: logCursor(1 << 40), want: nullID(1 << 40)},
+		{name: "malformed base64", cursor: ptr("%%%"), wantErr: true},
+		{name: "padded base64", cursor: ptr(base64.URLEncoding.EncodeToString([]byte("log:1"))), wantErr: true},
+		{name: "missing prefix", cursor: encode("42"), wantErr: true},
+		{name: "other prefix", cursor: encode("msg:42"), wantErr: true},
+		{name: "missing id", cursor: encode("log:"), wantErr: true},
+		{name: "not numeric id", cursor: encode("log:abc"), wantErr: true},
+		{name: "empty cursor", cursor: ptr(""), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := decodeLogCursor(tt.cursor)
+			if tt.wantErr {
+				assert.EqualError(t, err, "invalid cursor")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+}
@@ -36,6 +36,24 @@ type AgentLog struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type AgentLogConnection struct {
+	Edges    []*AgentLogEdge `json:"edges"`
+	PageInfo *PageInfo       `json:"pageInfo"`
+}
+
+type AgentLogEdge struct {
+	Cursor string    `json:"cursor"`
+	Node   *AgentLog `json:"node"`
+}
+
+type AgentLogFilter struct {
+	TaskID        *int64      `json:"taskId,omitempty"`
+	SubtaskID     *int64      `json:"subtaskId,omitempty"`
+	Executors     []AgentType `json:"executors,omitempty"`
+	CreatedAfter  *time.Time  `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time  `json:"createdBefore,omitempty"`
+}
+
 type AgentPrompt struct {
 	System *DefaultPrompt `json:"system"`
 }
@@ -83,6 +101,29 @@ type AgentsPrompts struct {
 	Summarizer    *AgentPrompt  `json:"summarizer"`
 }
 
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"userId"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"keyPrefix"`
+	Privileges []string   `json:"privileges"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type APIKeyInput struct {
+	Name       string    `json:"name"`
+	Privileges []string  `json:"privileges"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type APIKeyToken struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"apiKey"`
+}
+
 type Assistant struct {
 	ID        int64      `json:"id"`
 	Title     string     `json:"title"`
@@ -107,6 +148,78 @@ type AssistantLog struct {
 	CreatedAt    time.Time      `json:"createdAt"`
 }
 
+type Campaign struct {
+	ID          int64          `json:"id"`
+	Name        string         `json:"name"`
+	Status      CampaignStatus `json:"status"`
+	Input       string         `json:"input"`
+	PlaybookID  *int64         `json:"playbookId,omitempty"`
+	Scope       string         `json:"scope"`
+	Provider    *Provider      `json:"provider"`
+	PlanReview  bool           `json:"planReview"`
+	Concurrency int            `json:"concurrency"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+type CampaignFinding struct {
+	Target    string    `json:"target"`
+	FlowID    int64     `json:"flowId"`
+	TaskID    int64     `json:"taskId"`
+	Title     string    `json:"title"`
+	Result    string    `json:"result"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CampaignInput struct {
+	Name          string                   `json:"name"`
+	Targets       []string                 `json:"targets"`
+	Input         *string                  `json:"input,omitempty"`
+	PlaybookID    *int64                   `json:"playbookId,omitempty"`
+	Variables     []*PlaybookVariableInput `json:"variables,omitempty"`
+	Scope         *string                  `json:"scope,omitempty"`
+	ModelProvider string                   `json:"modelProvider"`
+	PlanReview    *bool                    `json:"planReview,omitempty"`
+	Concurrency   *int                     `json:"concurrency,omitempty"`
+}
+
+type CampaignModelUsage struct {
+	Model           string  `json:"model"`
+	UsageIn         int     `json:"usageIn"`
+	UsageOut        int     `json:"usageOut"`
+	UsageCacheRead  int     `json:"usageCacheRead"`
+	UsageCacheWrite int     `json:"usageCacheWrite"`
+	Cost            float64 `json:"cost"`
+}
+
+type CampaignProgress struct {
+	Total         int                   `json:"total"`
+	Pending       int                   `json:"pending"`
+	Cancelled     int                   `json:"cancelled"`
+	FailedToStart int                   `json:"failedToStart"`
+	Queued        int                   `json:"queued"`
+	Created       int                   `json:"created"`
+	Running       int                   `json:"running"`
+	Waiting       int                   `json:"waiting"`
+	Finished      int                   `json:"finished"`
+	Failed        int                   `json:"failed"`
+	Findings      int                   `json:"findings"`
+	Usage         []*CampaignModelUsage `json:"usage,omitempty"`
+	Cost          float64               `json:"cost"`
+}
+
+type CampaignTarget struct {
+	ID         int64                `json:"id"`
+	CampaignID int64                `json:"campaignId"`
+	Target     string               `json:"target"`
+	Status     CampaignTargetStatus `json:"status"`
+	FlowID     *int64               `json:"flowId,omitempty"`
+	FlowStatus *StatusType          `json:"flowStatus,omitempty"`
+	Reason     string               `json:"reason"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	UpdatedAt  time.Time            `json:"updatedAt"`
+}
+
 type DefaultPrompt struct {
 	Type      PromptType `json:"type"`
 	Template  string     `json:"template"`
@@ -125,16 +238,20 @@ type DefaultProvidersConfig struct {
 	Bedrock   *ProviderConfig `json:"bedrock,omitempty"`
 	Ollama    *ProviderConfig `json:"ollama,omitempty"`
 	Custom    *ProviderConfig `json:"custom,omitempty"`
+	Replay    *ProviderConfig `json:"replay,omitempty"`
+	Mock      *ProviderConfig `json:"mock,omitempty"`
 }
 
 type Flow struct {
-	ID        int64       `json:"id"`
-	Title     string      `json:"title"`
-	Status    StatusType  `json:"status"`
-	Terminals []*Terminal `json:"terminals,omitempty"`
-	Provider  *Provider   `json:"provider"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
+	ID         int64       `json:"id"`
+	Title      string      `json:"title"`
+	Status     StatusType  `json:"status"`
+	Terminals  []*Terminal `json:"terminals,omitempty"`
+	Provider   *Provider   `json:"provider"`
+	PlanReview bool        `json:"planReview"`
+	TeamID     *int64      `json:"teamId,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
 }
 
 type FlowAssistant struct {
@@ -142,6 +259,63 @@ type FlowAssistant struct {
 	Assistant *Assistant `json:"assistant"`
 }
 
+type FlowLimits struct {
+	FlowTimeLimit      int `json:"flowTimeLimit"`
+	TaskTimeLimit      int `json:"taskTimeLimit"`
+	SubtaskTimeLimit   int `json:"subtaskTimeLimit"`
+	MaxIterations      int `json:"maxIterations"`
+	MaxToolCalls       int `json:"maxToolCalls"`
+	MaxDelegationDepth int `json:"maxDelegationDepth"`
+}
+
+type FlowLimitsInput struct {
+	FlowTimeLimit      *int `json:"flowTimeLimit,omitempty"`
+	TaskTimeLimit      *int `json:"taskTimeLimit,omitempty"`
+	SubtaskTimeLimit   *int `json:"subtaskTimeLimit,omitempty"`
+	MaxIterations      *int `json:"maxIterations,omitempty"`
+	MaxToolCalls       *int `json:"maxToolCalls,omitempty"`
+	MaxDelegationDepth *int `json:"maxDelegationDepth,omitempty"`
+}
+
+type FlowQueueItem struct {
+	FlowID    int64        `json:"flowId"`
+	UserID    int64        `json:"userId"`
+	Provider  *Provider    `json:"provider"`
+	Priority  FlowPriority `json:"priority"`
+	Position  int          `json:"position"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type FlowShare struct {
+	ID               int64      `json:"id"`
+	FlowID           int64      `json:"flowId"`
+	UserID           int64      `json:"userId"`
+	TokenPrefix      string     `json:"tokenPrefix"`
+	WithTerminalLogs bool       `json:"withTerminalLogs"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+type FlowShareAccess struct {
+	ID         int64     `json:"id"`
+	ShareID    int64     `json:"shareId"`
+	Resource   string    `json:"resource"`
+	RemoteAddr string    `json:"remoteAddr"`
+	UserAgent  string    `json:"userAgent"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type FlowShareInput struct {
+	ExpiresAt        time.Time `json:"expiresAt"`
+	WithTerminalLogs *bool     `json:"withTerminalLogs,omitempty"`
+}
+
+type FlowShareToken struct {
+	Token string     `json:"token"`
+	Share *FlowShare `json:"share"`
+}
+
 type MessageLog struct {
 	ID           int64          `json:"id"`
 	Type         MessageLogType `json:"type"`
@@ -155,6 +329,24 @@ type MessageLog struct {
 	CreatedAt    time.Time      `json:"createdAt"`
 }
 
+type MessageLogConnection struct {
+	Edges    []*MessageLogEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type MessageLogEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *MessageLog `json:"node"`
+}
+
+type MessageLogFilter struct {
+	TaskID        *int64           `json:"taskId,omitempty"`
+	SubtaskID     *int64           `json:"subtaskId,omitempty"`
+	Types         []MessageLogType `json:"types,omitempty"`
+	CreatedAfter  *time.Time       `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time       `json:"createdBefore,omitempty"`
+}
+
 type ModelConfig struct {
 	Name        string      `json:"name"`
 	Description *string     `json:"description,omitempty"`
@@ -171,6 +363,39 @@ type ModelPrice struct {
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type Playbook struct {
+	ID          int64               `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Content     string              `json:"content"`
+	Image       *string             `json:"image,omitempty"`
+	Tools       []string            `json:"tools"`
+	Variables   []*PlaybookVariable `json:"variables"`
+	Tasks       int                 `json:"tasks"`
+	TeamID      *int64              `json:"teamId,omitempty"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+}
+
+type PlaybookVariable struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Default     *string `json:"default,omitempty"`
+	Required    bool    `json:"required"`
+}
+
+type PlaybookVariableInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 type PromptValidationResult struct {
 	Result    ResultType                 `json:"result"`
 	ErrorType *PromptValidationErrorType `json:"errorType,omitempty"`
@@ -194,6 +419,7 @@ type ProviderConfig struct {
 	Name      string        `json:"name"`
 	Type      ProviderType  `json:"type"`
 	Agents    *AgentsConfig `json:"agents"`
+	TeamID    *int64        `json:"teamId,omitempty"`
 	CreatedAt time.Time     `json:"createdAt"`
 	UpdatedAt time.Time     `json:"updatedAt"`
 }
@@ -237,6 +463,8 @@ type ProvidersReadinessStatus struct {
 	Bedrock   bool `json:"bedrock"`
 	Ollama    bool `json:"ollama"`
 	Custom    bool `json:"custom"`
+	Replay    bool `json:"replay"`
+	Mock      bool `json:"mock"`
 }
 
 type Query struct {
@@ -247,6 +475,58 @@ type ReasoningConfig struct {
 	MaxTokens *int             `json:"maxTokens,omitempty"`
 }
 
+type Role struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Privileges []string `json:"privileges"`
+}
+
+type RoleInput struct {
+	Name       string   `json:"name"`
+	Privileges []string `json:"privileges"`
+}
+
+type Schedule struct {
+	ID           int64                `json:"id"`
+	Name         string               `json:"name"`
+	Cron         string               `json:"cron"`
+	Timezone     string               `json:"timezone"`
+	Input        string               `json:"input"`
+	PlaybookID   *int64               `json:"playbookId,omitempty"`
+	Scope        string               `json:"scope"`
+	Provider     *Provider            `json:"provider"`
+	PlanReview   bool                 `json:"planReview"`
+	MissedPolicy ScheduleMissedPolicy `json:"missedPolicy"`
+	Enabled      bool                 `json:"enabled"`
+	NextRunAt    *time.Time           `json:"nextRunAt,omitempty"`
+	LastRunAt    *time.Time           `json:"lastRunAt,omitempty"`
+	CreatedAt    time.Time            `json:"createdAt"`
+	UpdatedAt    time.Time            `json:"updatedAt"`
+}
+
+type ScheduleInput struct {
+	Name          string                   `json:"name"`
+	Cron          string                   `json:"cron"`
+	Timezone      *string                  `json:"timezone,omitempty"`
+	Input         *string                  `json:"input,omitempty"`
+	PlaybookID    *int64                   `json:"playbookId,omitempty"`
+	Variables     []*PlaybookVariableInput `json:"variables,omitempty"`
+	Scope         *string                  `json:"scope,omitempty"`
+	ModelProvider string                   `json:"modelProvider"`
+	PlanReview    *bool                    `json:"planReview,omitempty"`
+	MissedPolicy  *ScheduleMissedPolicy    `json:"missedPolicy,omitempty"`
+}
+
+type ScheduleRun struct {
+	ID          int64             `json:"id"`
+	ScheduleID  int64             `json:"scheduleId"`
+	FlowID      *int64            `json:"flowId,omitempty"`
+	Status      ScheduleRunStatus `json:"status"`
+	Reason      string            `json:"reason"`
+	ScheduledAt time.Time         `json:"scheduledAt"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
 type Screenshot struct {
 	ID        int64     `json:"id"`
 	FlowID    int64     `json:"flowId"`
@@ -255,6 +535,12 @@ type Screenshot struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type SearchFilter struct {
+	FlowID        *int64     `json:"flowId,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
 type SearchLog struct {
 	ID        int64     `json:"id"`
 	Initiator AgentType `json:"initiator"`
@@ -268,6 +554,36 @@ type SearchLog struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type SearchLogConnection struct {
+	Edges    []*SearchLogEdge `json:"edges"`
+	PageInfo *PageInfo        `json:"pageInfo"`
+}
+
+type SearchLogEdge struct {
+	Cursor string     `json:"cursor"`
+	Node   *SearchLog `json:"node"`
+}
+
+type SearchLogFilter struct {
+	TaskID        *int64     `json:"taskId,omitempty"`
+	SubtaskID     *int64     `json:"subtaskId,omitempty"`
+	Engines       []string   `json:"engines,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+type SearchResult struct {
+	Scope     SearchScope `json:"scope"`
+	ID        int64       `json:"id"`
+	FlowID    int64       `json:"flowId"`
+	FlowTitle string      `json:"flowTitle"`
+	TaskID    *int64      `json:"taskId,omitempty"`
+	SubtaskID *int64      `json:"subtaskId,omitempty"`
+	Snippet   string      `json:"snippet"`
+	Rank      float64     `json:"rank"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
 type Settings struct {
 	Debug              bool `json:"debug"`
 	AskUser            bool `json:"askUser"`
@@ -285,10 +601,20 @@ type Subtask struct {
 	Description string     `json:"description"`
 	Result      string     `json:"result"`
 	TaskID      int64      `json:"taskId"`
+	DependsOn   []int64    `json:"dependsOn"`
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
 }
 
+type SubtaskOperationInput struct {
+	Op          SubtaskOperationType `json:"op"`
+	ID          *int64               `json:"id,omitempty"`
+	AfterID     *int64               `json:"afterId,omitempty"`
+	Title       *string              `json:"title,omitempty"`
+	Description *string              `json:"description,omitempty"`
+	DependsOn   []int64              `json:"dependsOn,omitempty"`
+}
+
 type Task struct {
 	ID        int64      `json:"id"`
 	Title     string     `json:"title"`
@@ -301,6 +627,28 @@ type Task struct {
 	UpdatedAt time.Time  `json:"updatedAt"`
 }
 
+type Team struct {
+	ID          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Members     []*TeamMember `json:"members"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+type TeamInput struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+type TeamMember struct {
+	UserID    int64     `json:"userId"`
+	Name      string    `json:"name"`
+	Mail      string    `json:"mail"`
+	Role      TeamRole  `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type Terminal struct {
 	ID        int64        `json:"id"`
 	Type      TerminalType `json:"type"`
@@ -319,6 +667,23 @@ type TerminalLog struct {
 	CreatedAt time.Time       `json:"createdAt"`
 }
 
+type TerminalLogConnection struct {
+	Edges    []*TerminalLogEdge `json:"edges"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type TerminalLogEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *TerminalLog `json:"node"`
+}
+
+type TerminalLogFilter struct {
+	Terminal      *int64            `json:"terminal,omitempty"`
+	Types         []TerminalLogType `json:"types,omitempty"`
+	CreatedAfter  *time.Time        `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time        `json:"createdBefore,omitempty"`
+}
+
 type TestResult struct {
 	Name      string  `json:"name"`
 	Type      string  `json:"type"`
@@ -343,6 +708,7 @@ type UserPrompt struct {
 	ID        int64      `json:"id"`
 	Type      PromptType `json:"type"`
 	Template  string     `json:"template"`
+	TeamID    *int64     `json:"teamId,omitempty"`
 	CreatedAt time.Time  `json:"createdAt"`
 	UpdatedAt time.Time  `json:"updatedAt"`
 }
@@ -361,6 +727,60 @@ type VectorStoreLog struct {
 	CreatedAt time.Time         `json:"createdAt"`
 }
 
+type VectorStoreLogConnection struct {
+	Edges    []*VectorStoreLogEdge `json:"edges"`
+	PageInfo *PageInfo             `json:"pageInfo"`
+}
+
+type VectorStoreLogEdge struct {
+	Cursor string          `json:"cursor"`
+	Node   *VectorStoreLog `json:"node"`
+}
+
+type VectorStoreLogFilter struct {
+	TaskID        *int64              `json:"taskId,omitempty"`
+	SubtaskID     *int64              `json:"subtaskId,omitempty"`
+	Actions       []VectorStoreAction `json:"actions,omitempty"`
+	CreatedAfter  *time.Time          `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time          `json:"createdBefore,omitempty"`
+}
+
+type Webhook struct {
+	ID        int64          `json:"id"`
+	UserID    *int64         `json:"userId,omitempty"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"secret"`
+	Events    []WebhookEvent `json:"events"`
+	Enabled   bool           `json:"enabled"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+type WebhookDelivery struct {
+	ID            int64                 `json:"id"`
+	WebhookID     int64                 `json:"webhookId"`
+	FlowID        *int64                `json:"flowId,omitempty"`
+	Event         string                `json:"event"`
+	Payload       string                `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	ResponseCode  int                   `json:"responseCode"`
+	Error         string                `json:"error"`
+	NextAttemptAt *time.Time            `json:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+}
+
+type WebhookInput struct {
+	Name    string         `json:"name"`
+	URL     string         `json:"url"`
+	Secret  *string        `json:"secret,omitempty"`
+	Events  []WebhookEvent `json:"events,omitempty"`
+	Enabled *bool          `json:"enabled,omitempty"`
+	System  *bool          `json:"system,omitempty"`
+}
+
 type AgentConfigType string
 
 const (
@@ -491,6 +911,178 @@ func (e AgentType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type CampaignStatus string
+
+const (
+	CampaignStatusRunning  CampaignStatus = "running"
+	CampaignStatusStopped  CampaignStatus = "stopped"
+	CampaignStatusFinished CampaignStatus = "finished"
+)
+
+var AllCampaignStatus = []CampaignStatus{
+	CampaignStatusRunning,
+	CampaignStatusStopped,
+	CampaignStatusFinished,
+}
+
+func (e CampaignStatus) IsValid() bool {
+	switch e {
+	case CampaignStatusRunning, CampaignStatusStopped, CampaignStatusFinished:
+		return true
+	}
+	return false
+}
+
+func (e CampaignStatus) String() string {
+	return string(e)
+}
+
+func (e *CampaignStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CampaignStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CampaignStatus", str)
+	}
+	return nil
+}
+
+func (e CampaignStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CampaignTargetStatus string
+
+const (
+	CampaignTargetStatusPending   CampaignTargetStatus = "pending"
+	CampaignTargetStatusStarted   CampaignTargetStatus = "started"
+	CampaignTargetStatusFailed    CampaignTargetStatus = "failed"
+	CampaignTargetStatusCancelled CampaignTargetStatus = "cancelled"
+)
+
+var AllCampaignTargetStatus = []CampaignTargetStatus{
+	CampaignTargetStatusPending,
+	CampaignTargetStatusStarted,
+	CampaignTargetStatusFailed,
+	CampaignTargetStatusCancelled,
+}
+
+func (e CampaignTargetStatus) IsValid() bool {
+	switch e {
+	case CampaignTargetStatusPending, CampaignTargetStatusStarted, CampaignTargetStatusFailed, CampaignTargetStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e CampaignTargetStatus) String() string {
+	return string(e)
+}
+
+func (e *CampaignTargetStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CampaignTargetStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CampaignTargetStatus", str)
+	}
+	return nil
+}
+
+func (e CampaignTargetStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FlowPriority string
+
+const (
+	FlowPriorityLow    FlowPriority = "low"
+	FlowPriorityNormal FlowPriority = "normal"
+	FlowPriorityHigh   FlowPriority = "high"
+)
+
+var AllFlowPriority = []FlowPriority{
+	FlowPriorityLow,
+	FlowPriorityNormal,
+	FlowPriorityHigh,
+}
+
+func (e FlowPriority) IsValid() bool {
+	switch e {
+	case FlowPriorityLow, FlowPriorityNormal, FlowPriorityHigh:
+		return true
+	}
+	return false
+}
+
+func (e FlowPriority) String() string {
+	return string(e)
+}
+
+func (e *FlowPriority) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FlowPriority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FlowPriority", str)
+	}
+	return nil
+}
+
+func (e FlowPriority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type LogOrder string
+
+const (
+	LogOrderAsc  LogOrder = "asc"
+	LogOrderDesc LogOrder = "desc"
+)
+
+var AllLogOrder = []LogOrder{
+	LogOrderAsc,
+	LogOrderDesc,
+}
+
+func (e LogOrder) IsValid() bool {
+	switch e {
+	case LogOrderAsc, LogOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e LogOrder) String() string {
+	return string(e)
+}
+
+func (e *LogOrder) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LogOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LogOrder", str)
+	}
+	return nil
+}
+
+func (e LogOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type MessageLogType string
 
 const (
@@ -713,6 +1305,8 @@ const (
 	ProviderTypeBedrock   ProviderType = "bedrock"
 	ProviderTypeOllama    ProviderType = "ollama"
 	ProviderTypeCustom    ProviderType = "custom"
+	ProviderTypeReplay    ProviderType = "replay"
+	ProviderTypeMock      ProviderType = "mock"
 )
 
 var AllProviderType = []ProviderType{
@@ -722,11 +1316,13 @@ var AllProviderType = []ProviderType{
 	ProviderTypeBedrock,
 	ProviderTypeOllama,
 	ProviderTypeCustom,
+	ProviderTypeReplay,
+	ProviderTypeMock,
 }
 
 func (e ProviderType) IsValid() bool {
 	switch e {
-	case ProviderTypeOpenai, ProviderTypeAnthropic, ProviderTypeGemini, ProviderTypeBedrock, ProviderTypeOllama, ProviderTypeCustom:
+	case ProviderTypeOpenai, ProviderTypeAnthropic, ProviderTypeGemini, ProviderTypeBedrock, ProviderTypeOllama, ProviderTypeCustom, ProviderTypeReplay, ProviderTypeMock:
 		return true
 	}
 	return false
@@ -880,9 +1476,143 @@ func (e ResultType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type ScheduleMissedPolicy string
+
+const (
+	ScheduleMissedPolicySkip    ScheduleMissedPolicy = "skip"
+	ScheduleMissedPolicyRunOnce ScheduleMissedPolicy = "run_once"
+)
+
+var AllScheduleMissedPolicy = []ScheduleMissedPolicy{
+	ScheduleMissedPolicySkip,
+	ScheduleMissedPolicyRunOnce,
+}
+
+func (e ScheduleMissedPolicy) IsValid() bool {
+	switch e {
+	case ScheduleMissedPolicySkip, ScheduleMissedPolicyRunOnce:
+		return true
+	}
+	return false
+}
+
+func (e ScheduleMissedPolicy) String() string {
+	return string(e)
+}
+
+func (e *ScheduleMissedPolicy) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduleMissedPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduleMissedPolicy", str)
+	}
+	return nil
+}
+
+func (e ScheduleMissedPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusStarted ScheduleRunStatus = "started"
+	ScheduleRunStatusSkipped ScheduleRunStatus = "skipped"
+	ScheduleRunStatusFailed  ScheduleRunStatus = "failed"
+)
+
+var AllScheduleRunStatus = []ScheduleRunStatus{
+	ScheduleRunStatusStarted,
+	ScheduleRunStatusSkipped,
+	ScheduleRunStatusFailed,
+}
+
+func (e ScheduleRunStatus) IsValid() bool {
+	switch e {
+	case ScheduleRunStatusStarted, ScheduleRunStatusSkipped, ScheduleRunStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ScheduleRunStatus) String() string {
+	return string(e)
+}
+
+func (e *ScheduleRunStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduleRunStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduleRunStatus", str)
+	}
+	return nil
+}
+
+func (e ScheduleRunStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchScope string
+
+const (
+	SearchScopeFlow        SearchScope = "flow"
+	SearchScopeTask        SearchScope = "task"
+	SearchScopeSubtask     SearchScope = "subtask"
+	SearchScopeMessageLog  SearchScope = "message_log"
+	SearchScopeTerminalLog SearchScope = "terminal_log"
+	SearchScopeSearchLog   SearchScope = "search_log"
+)
+
+var AllSearchScope = []SearchScope{
+	SearchScopeFlow,
+	SearchScopeTask,
+	SearchScopeSubtask,
+	SearchScopeMessageLog,
+	SearchScopeTerminalLog,
+	SearchScopeSearchLog,
+}
+
+func (e SearchScope) IsValid() bool {
+	switch e {
+	case SearchScopeFlow, SearchScopeTask, SearchScopeSubtask, SearchScopeMessageLog, SearchScopeTerminalLog, SearchScopeSearchLog:
+		return true
+	}
+	return false
+}
+
+func (e SearchScope) String() string {
+	return string(e)
+}
+
+func (e *SearchScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchScope", str)
+	}
+	return nil
+}
+
+func (e SearchScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type StatusType string
 
 const (
+	StatusTypeQueued   StatusType = "queued"
 	StatusTypeCreated  StatusType = "created"
 	StatusTypeRunning  StatusType = "running"
 	StatusTypeWaiting  StatusType = "waiting"
@@ -891,6 +1621,7 @@ const (
 )
 
 var AllStatusType = []StatusType{
+	StatusTypeQueued,
 	StatusTypeCreated,
 	StatusTypeRunning,
 	StatusTypeWaiting,
@@ -900,7 +1631,7 @@ var AllStatusType = []StatusType{
 
 func (e StatusType) IsValid() bool {
 	switch e {
-	case StatusTypeCreated, StatusTypeRunning, StatusTypeWaiting, StatusTypeFinished, StatusTypeFailed:
+	case StatusTypeQueued, StatusTypeCreated, StatusTypeRunning, StatusTypeWaiting, StatusTypeFinished, StatusTypeFailed:
 		return true
 	}
 	return false
@@ -927,6 +1658,94 @@ func (e StatusType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type SubtaskOperationType string
+
+const (
+	SubtaskOperationTypeAdd     SubtaskOperationType = "add"
+	SubtaskOperationTypeRemove  SubtaskOperationType = "remove"
+	SubtaskOperationTypeModify  SubtaskOperationType = "modify"
+	SubtaskOperationTypeReorder SubtaskOperationType = "reorder"
+)
+
+var AllSubtaskOperationType = []SubtaskOperationType{
+	SubtaskOperationTypeAdd,
+	SubtaskOperationTypeRemove,
+	SubtaskOperationTypeModify,
+	SubtaskOperationTypeReorder,
+}
+
+func (e SubtaskOperationType) IsValid() bool {
+	switch e {
+	case SubtaskOperationTypeAdd, SubtaskOperationTypeRemove, SubtaskOperationTypeModify, SubtaskOperationTypeReorder:
+		return true
+	}
+	return false
+}
+
+func (e SubtaskOperationType) String() string {
+	return string(e)
+}
+
+func (e *SubtaskOperationType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SubtaskOperationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SubtaskOperationType", str)
+	}
+	return nil
+}
+
+func (e SubtaskOperationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TeamRole string
+
+const (
+	TeamRoleViewer   TeamRole = "viewer"
+	TeamRoleOperator TeamRole = "operator"
+	TeamRoleAdmin    TeamRole = "admin"
+)
+
+var AllTeamRole = []TeamRole{
+	TeamRoleViewer,
+	TeamRoleOperator,
+	TeamRoleAdmin,
+}
+
+func (e TeamRole) IsValid() bool {
+	switch e {
+	case TeamRoleViewer, TeamRoleOperator, TeamRoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e TeamRole) String() string {
+	return string(e)
+}
+
+func (e *TeamRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TeamRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TeamRole", str)
+	}
+	return nil
+}
+
+func (e TeamRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type TerminalLogType string
 
 const (
@@ -1051,3 +1870,99 @@ func (e *VectorStoreAction) UnmarshalGQL(v interface{}) error {
 func (e VectorStoreAction) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusDelivered,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WebhookEvent string
+
+const (
+	WebhookEventFlowCreated          WebhookEvent = "flow_created"
+	WebhookEventFlowUpdated          WebhookEvent = "flow_updated"
+	WebhookEventFlowFinished         WebhookEvent = "flow_finished"
+	WebhookEventTaskStatusChanged    WebhookEvent = "task_status_changed"
+	WebhookEventSubtaskStatusChanged WebhookEvent = "subtask_status_changed"
+	WebhookEventAskWaiting           WebhookEvent = "ask_waiting"
+	WebhookEventFindingAdded         WebhookEvent = "finding_added"
+	WebhookEventBudgetExceeded       WebhookEvent = "budget_exceeded"
+)
+
+var AllWebhookEvent = []WebhookEvent{
+	WebhookEventFlowCreated,
+	WebhookEventFlowUpdated,
+	WebhookEventFlowFinished,
+	WebhookEventTaskStatusChanged,
+	WebhookEventSubtaskStatusChanged,
+	WebhookEventAskWaiting,
+	WebhookEventFindingAdded,
+	WebhookEventBudgetExceeded,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventFlowCreated, WebhookEventFlowUpdated, WebhookEventFlowFinished, WebhookEventTaskStatusChanged, WebhookEventSubtaskStatusChanged, WebhookEventAskWaiting, WebhookEventFindingAdded, WebhookEventBudgetExceeded:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvent) String() string {
+	return string(e)
+}
+
+func (e *WebhookEvent) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEvent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEvent", str)
+	}
+	return nil
+}
+
+func (e WebhookEvent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions for the flow queue.
+
+// flowPriority returns the priority of the new flow, flows are queued with normal priority by default
+func flowPriority(priority *model.FlowPriority) database.FlowPriority {
+	if priority == nil || !priority.IsValid() {
+		return database.FlowPriorityNormal
+	}
+
+	return database.FlowPriority(*priority)
+}
@@ -1,12 +1,15 @@
 package graph
 
 import (
+	"pentagi/pkg/audit"
 	"pentagi/pkg/config"
 	"pentagi/pkg/controller"
 	"pentagi/pkg/database"
 	"pentagi/pkg/graph/subscriptions"
 	"pentagi/pkg/providers"
+	"pentagi/pkg/redact"
 	"pentagi/pkg/templates"
+	"pentagi/pkg/webhooks"
 
 	"github.com/sirupsen/logrus"
 )
@@ -23,4 +26,7 @@ type Resolver struct {
 	ProvidersCtrl   providers.ProviderController
 	Controller      controller.FlowController
 	Subscriptions   subscriptions.SubscriptionsController
+	Webhooks        webhooks.Dispatcher
+	Redactor        *redact.Redactor
+	Audit           audit.Recorder
 }
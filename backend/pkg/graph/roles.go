@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/database/converter"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/server/models"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to validate the role input and to store the role.
+
+// validateRoleName checks the role name and that it isn't used by another role
+func validateRoleName(ctx context.Context, db database.Querier, name string) error {
+	if err := (models.Role{Name: name}).Valid(); err != nil {
+		return fmt.Errorf("invalid role name: %w", err)
+	}
+
+	if _, err := db.GetRoleByName(ctx, name); err == nil {
+		return fmt.Errorf("role '%s' already exists", name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+// validateRolePrivileges checks that the privileges are known and granted to the current user,
+// so nobody can escalate beyond own privileges by the role management
+func validateRolePrivileges(ctx context.Context, privs []string) error {
+	if err := models.ValidatePrivileges(privs); err != nil {
+		return err
+	}
+
+	userPrivs, err := GetUserPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, priv := range privs {
+		if !slices.Contains(userPrivs, priv) {
+			return fmt.Errorf("privilege '%s' is not granted to the current user", priv)
+		}
+	}
+
+	return nil
+}
+
+// createRole stores the new role with the privileges which are already validated,
+// the role isn't created without its privileges if any write fails
+func createRole(ctx context.Context, db database.Querier, name string, privs []string) (*model.Role, error) {
+	var created database.GetRoleRow
+	err := database.RunInTx(ctx, db, func(tx database.Querier) error {
+		if err := validateRoleName(ctx, tx, name); err != nil {
+			return err
+		}
+
+		role, err := tx.CreateRole(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		err = tx.SetRolePrivileges(ctx, database.SetRolePrivilegesParams{
+			RoleID:     role.ID,
+			Privileges: privs,
+		})
+		if err != nil {
+			return err
+		}
+
+		created, err = tx.GetRole(ctx, role.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertRole(created), nil
+}
+
+// updateRole stores the new name and privileges of the role which are already validated,
+// the name and the privileges are changed together or not at all
+func updateRole(
+	ctx context.Context,
+	db database.Querier,
+	current database.GetRoleRow,
+	name string,
+	privs []string,
+) (database.GetRoleRow, error) {
+	var updated database.GetRoleRow
+	err := database.RunInTx(ctx, db, func(tx database.Querier) error {
+		if name != current.Name {
+			if err := validateRoleName(ctx, tx, name); err != nil {
+				return err
+			}
+			_, err := tx.UpdateRole(ctx, database.UpdateRoleParams{
+				Name: name,
+				ID:   current.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tx.SetRolePrivileges(ctx, database.SetRolePrivilegesParams{
+			RoleID:     current.ID,
+			Privileges: privs,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated, err = tx.GetRole(ctx, current.ID)
+		return err
+	})
+
+	return updated, err
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"testing"
+
+	"pentagi/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rolesQuerier keeps roles in memory, the changes made in a transaction are applied only on its success
+type rolesQuerier struct {
+	database.Querier
+	roles     map[int64]database.GetRoleRow
+	lastID    int64
+	failPrivs bool
+}
+
+func (q *rolesQuerier) ExecTx(ctx context.Context, fn func(q database.Querier) error) error {
+	tx := &rolesQuerier{roles: maps.Clone(q.roles), lastID: q.lastID, failPrivs: q.failPrivs}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	q.roles, q.lastID = tx.roles, tx.lastID
+	return nil
+}
+
+func (q *rolesQuerier) GetRole(_ context.Context, id int64) (database.GetRoleRow, error) {
+	role, ok := q.roles[id]
+	if !ok {
+		return database.GetRoleRow{}, sql.ErrNoRows
+	}
+	return role, nil
+}
+
+func (q *rolesQuerier) GetRoleByName(_ context.Context, name string) (database.GetRoleByNameRow, error) {
+	for _, role := range q.roles {
+		if role.Name == name {
+			return database.GetRoleByNameRow{ID: role.ID, Name: role.Name, Privileges: role.Privileges}, nil
+		}
+	}
+	return database.GetRoleByNameRow{}, sql.ErrNoRows
+}
+
+func (q *rolesQuerier) CreateRole(_ context.Context, name string) (database.Role, error) {
+	q.lastID++
+	q.roles[q.lastID] = database.GetRoleRow{ID: q.lastID, Name: name}
+	return database.Role{ID: q.lastID, Name: name}, nil
+}
+
+func (q *rolesQuerier) UpdateRole(_ context.Context, arg database.UpdateRoleParams) (database.Role, error) {
+	role := q.roles[arg.ID]
+	role.Name = arg.Name
+	q.roles[arg.ID] = role
+	return database.Role{ID: arg.ID, Name: arg.Name}, nil
+}
+
+func (q *rolesQuerier) SetRolePrivileges(_ context.Context, arg database.SetRolePrivilegesParams) error {
+	if q.failPrivs {
+		return errors.New("privileges write failed")
+	}
+	role := q.roles[arg.RoleID]
+	role.Privileges = arg.Privileges
+	q.roles[arg.RoleID] = role
+	return nil
+}
+
+func newRolesQuerier() *rolesQuerier {
+	return &rolesQuerier{
+		roles:  map[int64]database.GetRoleRow{2: {ID: 2, Name: "User", Privileges: []string{"flows.view"}}},
+		lastID: 2,
+	}
+}
+
+func TestCreateRole(t *testing.T) {
+	db := newRolesQuerier()
+
+	created, err := createRole(context.Background(), db, "Auditor", []string{"flows.view"})
+	require.NoError(t, err)
+	assert.Equal(t, "Auditor", created.Name)
+	assert.Equal(t, []string{"flows.view"}, db.roles[created.ID].Privileges)
+
+	_, err = createRole(context.Background(), db, "Auditor", nil)
+	assert.ErrorContains(t, err, "already exists")
+
+	// the role isn't left without privileges if they aren't stored
+	db.failPrivs = true
+	_, err = createRole(context.Background(), db, "Operator", []string{"flows.view"})
+	require.Error(t, err)
+	_, err = db.GetRoleByName(context.Background(), "Operator")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateRoleAtomic(t *testing.T) {
+	db := newRolesQuerier()
+	current := db.roles[2]
+
+	db.failPrivs = true
+	_, err := updateRole(context.Background(), db, current, "Member", []string{"flows.view", "flows.create"})
+	require.Error(t, err)
+	assert.Equal(t, current, db.roles[2], "the name isn't changed without the privileges")
+
+	db.failPrivs = false
+	updated, err := updateRole(context.Background(), db, current, "Member", []string{"flows.view", "flows.create"})
+	require.NoError(t, err)
+	assert.Equal(t, database.GetRoleRow{ID: 2, Name: "Member", Privileges: []string{"flows.view", "flows.create"}}, updated)
+}
+
+func TestCreateRoleWithoutTransactions(t *testing.T) {
+	// the querier which can't run transactions is refused instead of the partial writes
+	db := &struct{ database.Querier }{}
+
+	_, err := createRole(context.Background(), db, "Auditor", nil)
+	assert.ErrorContains(t, err, "doesn't support transactions")
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/scheduler"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to validate the schedule input.
+
+const defaultScheduleTimezone = "UTC"
+
+// scheduleParams validates the schedule input on behalf of the schedule owner and returns
+// the values for the schedule record including the next run time
+func (r *mutationResolver) scheduleParams(
+	ctx context.Context,
+	userID int64,
+	schedule model.ScheduleInput,
+) (database.CreateScheduleParams, error) {
+	params := database.CreateScheduleParams{
+		UserID:            userID,
+		Name:              schedule.Name,
+		Cron:              schedule.Cron,
+		Timezone:          defaultScheduleTimezone,
+		Variables:         json.RawMessage("{}"),
+		ModelProviderName: schedule.ModelProvider,
+		PlanReview:        schedule.PlanReview != nil && *schedule.PlanReview,
+		MissedPolicy:      database.ScheduleMissedPolicySkip,
+		Enabled:           true,
+	}
+
+	if schedule.Name == "" {
+		return params, fmt.Errorf("schedule name is required")
+	}
+	if schedule.ModelProvider == "" {
+		return params, fmt.Errorf("model provider is required")
+	}
+	if schedule.Timezone != nil && *schedule.Timezone != "" {
+		params.Timezone = *schedule.Timezone
+	}
+	if schedule.Input != nil {
+		params.Input = *schedule.Input
+	}
+	if schedule.Scope != nil {
+		params.Scope = *schedule.Scope
+	}
+	if schedule.MissedPolicy != nil {
+		params.MissedPolicy = database.ScheduleMissedPolicy(*schedule.MissedPolicy)
+	}
+
+	next, err := scheduler.NextRun(params.Cron, params.Timezone, time.Now())
+	if err != nil {
+		return params, err
+	}
+	params.NextRunAt = database.TimeToNullTime(next)
+
+	prv, err := r.ProvidersCtrl.GetProvider(ctx, provider.ProviderName(schedule.ModelProvider), userID)
+	if err != nil {
+		return params, err
+	}
+	params.ModelProviderType = database.ProviderType(prv.Type())
+
+	switch {
+	case params.Input != "" && schedule.PlaybookID != nil:
+		return params, fmt.Errorf("either input or playbook must be set, not both")
+	case params.Input != "":
+		return params, nil
+	case schedule.PlaybookID == nil:
+		return params, fmt.Errorf("user input or playbook is required")
+	}
+
+	if _, _, err := validatePermission(ctx, "playbooks.view"); err != nil {
+		return params, err
+	}
+
+	pb, err := r.DB.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     *schedule.PlaybookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return params, err
+	}
+	params.PlaybookID = database.Int64ToNullInt64(&pb.ID)
+
+	values := make(map[string]string, len(schedule.Variables))
+	for _, variable := range schedule.Variables {
+		values[variable.Name] = variable.Value
+	}
+	if params.Variables, err = json.Marshal(values); err != nil {
+		return params, fmt.Errorf("failed to marshal playbook variables: %w", err)
+	}
+
+	// the playbook is rendered on each run, here it's checked that the variables fit it
+	if _, err := scheduler.RenderPlaybook(pb.Content, params.Variables, params.Scope); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
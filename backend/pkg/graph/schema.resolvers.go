@@ -5,29 +5,38 @@ package graph
 // Code generated by github.com/99designs/gqlgen version v0.17.57
 
 import (
+	"cmp"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"pentagi/pkg/audit"
 	"pentagi/pkg/controller"
 	"pentagi/pkg/database"
 	"pentagi/pkg/database/converter"
 	"pentagi/pkg/graph/model"
+	"pentagi/pkg/playbook"
 	"pentagi/pkg/providers/anthropic"
 	"pentagi/pkg/providers/bedrock"
 	"pentagi/pkg/providers/gemini"
 	"pentagi/pkg/providers/openai"
 	"pentagi/pkg/providers/pconfig"
 	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/scheduler"
+	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/models"
 	"pentagi/pkg/templates"
 	"pentagi/pkg/templates/validator"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 // CreateFlow is the resolver for the createFlow field.
-func (r *mutationResolver) CreateFlow(ctx context.Context, modelProvider string, input string) (*model.Flow, error) {
+func (r *mutationResolver) CreateFlow(ctx context.Context, modelProvider string, input string, planReview *bool, priority *model.FlowPriority, limits *model.FlowLimitsInput) (*model.Flow, error) {
 	uid, _, err := validatePermission(ctx, "flows.create")
 	if err != nil {
 		return nil, err
@@ -54,25 +63,60 @@ func (r *mutationResolver) CreateFlow(ctx context.Context, modelProvider string,
 	}
 	prvtype := prv.Type()
 
-	fw, err := r.Controller.CreateFlow(ctx, uid, input, prvname, prvtype, nil)
+	fl, err := flowLimits(limits)
 	if err != nil {
 		return nil, err
 	}
 
-	flow, err := r.DB.GetFlow(ctx, fw.GetFlowID())
+	flow, err := r.Controller.CreateFlow(ctx, uid, input, prvname, prvtype, nil, planReview != nil && *planReview, flowPriority(priority), fl)
 	if err != nil {
 		return nil, err
 	}
 
-	var containers []database.Container
-	if _, _, err = validatePermission(ctx, "containers.view"); err == nil {
-		containers, err = r.DB.GetFlowContainers(ctx, fw.GetFlowID())
-		if err != nil {
-			return nil, err
-		}
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowCreate,
+		TargetType: audit.TargetFlow,
+		TargetID:   flow.ID,
+		TargetName: flow.Title,
+		After:      flow,
+	})
+
+	// the flow is queued, so it has no containers yet
+	return converter.ConvertFlow(flow, nil), nil
+}
+
+// UpdateFlowLimits is the resolver for the updateFlowLimits field.
+func (r *mutationResolver) UpdateFlowLimits(ctx context.Context, flowID int64, limits model.FlowLimitsInput) (*model.FlowLimits, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return nil, err
 	}
 
-	return converter.ConvertFlow(flow, containers), nil
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("update flow limits")
+
+	fl, err := flowLimits(&limits)
+	if err != nil {
+		return nil, err
+	}
+
+	// the running flow applies the new limits from the next task run
+	updated, err := r.DB.UpsertFlowLimits(ctx, database.UpsertFlowLimitsParams{
+		FlowID:             flowID,
+		FlowTimeLimit:      fl.FlowTimeLimit,
+		TaskTimeLimit:      fl.TaskTimeLimit,
+		SubtaskTimeLimit:   fl.SubtaskTimeLimit,
+		MaxIterations:      fl.MaxIterations,
+		MaxToolCalls:       fl.MaxToolCalls,
+		MaxDelegationDepth: fl.MaxDelegationDepth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertFlowLimits(updated), nil
 }
 
 // PutUserInput is the resolver for the putUserInput field.
@@ -99,6 +143,201 @@ func (r *mutationResolver) PutUserInput(ctx context.Context, flowID int64, input
 	return model.ResultTypeSuccess, nil
 }
 
+// PatchTaskPlan is the resolver for the patchTaskPlan field.
+func (r *mutationResolver) PatchTaskPlan(ctx context.Context, flowID int64, taskID int64, operations []*model.SubtaskOperationInput) (model.ResultType, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":        uid,
+		"flow":       flowID,
+		"task":       taskID,
+		"operations": len(operations),
+	}).Debug("patch task plan")
+
+	fw, err := r.Controller.GetFlow(ctx, flowID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	patch := converter.ConvertSubtaskPatchFromGqlModel(operations)
+	if err := fw.PatchTaskPlan(ctx, taskID, patch); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// ApproveTaskPlan is the resolver for the approveTaskPlan field.
+func (r *mutationResolver) ApproveTaskPlan(ctx context.Context, flowID int64, taskID int64) (model.ResultType, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+		"task": taskID,
+	}).Debug("approve task plan")
+
+	fw, err := r.Controller.GetFlow(ctx, flowID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	if err := fw.ApproveTaskPlan(ctx, taskID); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// RetrySubtask is the resolver for the retrySubtask field.
+func (r *mutationResolver) RetrySubtask(ctx context.Context, flowID int64, taskID int64, subtaskID int64, instructions *string) (model.ResultType, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"flow":    flowID,
+		"task":    taskID,
+		"subtask": subtaskID,
+	}).Debug("retry subtask")
+
+	fw, err := r.Controller.GetFlow(ctx, flowID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	var input string
+	if instructions != nil {
+		input = *instructions
+	}
+
+	if err := fw.RetrySubtask(ctx, taskID, subtaskID, input); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// SkipSubtask is the resolver for the skipSubtask field.
+func (r *mutationResolver) SkipSubtask(ctx context.Context, flowID int64, taskID int64) (model.ResultType, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+		"task": taskID,
+	}).Debug("skip subtask")
+
+	fw, err := r.Controller.GetFlow(ctx, flowID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	if err := fw.SkipSubtask(ctx, taskID); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// InsertSubtask is the resolver for the insertSubtask field.
+func (r *mutationResolver) InsertSubtask(ctx context.Context, flowID int64, taskID int64, title string, description string) (model.ResultType, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":   uid,
+		"flow":  flowID,
+		"task":  taskID,
+		"title": title,
+	}).Debug("insert subtask")
+
+	fw, err := r.Controller.GetFlow(ctx, flowID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	if err := fw.InsertSubtask(ctx, taskID, title, description); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// ForkFlow is the resolver for the forkFlow field.
+func (r *mutationResolver) ForkFlow(ctx context.Context, flowID int64, subtaskID int64, modelProvider *string) (*model.Flow, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err = validatePermission(ctx, "flows.create"); err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"flow":    flowID,
+		"subtask": subtaskID,
+	}).Debug("fork flow")
+
+	src, err := r.DB.GetFlow(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	prvname := provider.ProviderName(src.ModelProviderName)
+	if modelProvider != nil && *modelProvider != "" {
+		prvname = provider.ProviderName(*modelProvider)
+	}
+	prv, err := r.ProvidersCtrl.GetProvider(ctx, prvname, uid)
+	if err != nil {
+		return nil, err
+	}
+	prvtype := prv.Type()
+
+	fw, err := r.Controller.ForkFlow(ctx, uid, flowID, subtaskID, prvname, prvtype)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := r.DB.GetFlow(ctx, fw.GetFlowID())
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowCreate,
+		TargetType: audit.TargetFlow,
+		TargetID:   flow.ID,
+		TargetName: flow.Title,
+		After:      flow,
+	})
+
+	var containers []database.Container
+	if _, _, err = validatePermission(ctx, "containers.view"); err == nil {
+		containers, err = r.DB.GetFlowContainers(ctx, fw.GetFlowID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return converter.ConvertFlow(flow, containers), nil
+}
+
 // StopFlow is the resolver for the stopFlow field.
 func (r *mutationResolver) StopFlow(ctx context.Context, flowID int64) (model.ResultType, error) {
 	uid, err := validatePermissionWithFlowID(ctx, "flows.edit", flowID, r.DB)
@@ -156,6 +395,8 @@ func (r *mutationResolver) DeleteFlow(ctx context.Context, flowID int64) (model.
 		}
 	} else if !errors.Is(err, controller.ErrFlowNotFound) {
 		return model.ResultTypeError, err
+	} else if err := r.Controller.FinishFlow(ctx, flowID); err != nil && !errors.Is(err, controller.ErrFlowNotFound) {
+		return model.ResultTypeError, err // the queued flow is removed from the queue
 	}
 
 	flow, err := r.DB.GetFlow(ctx, flowID)
@@ -172,6 +413,14 @@ func (r *mutationResolver) DeleteFlow(ctx context.Context, flowID int64) (model.
 		return model.ResultTypeError, err
 	}
 
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowDelete,
+		TargetType: audit.TargetFlow,
+		TargetID:   flow.ID,
+		TargetName: flow.Title,
+		Before:     flow,
+	})
+
 	publisher := r.Subscriptions.NewFlowPublisher(flow.UserID, flow.ID)
 	publisher.FlowUpdated(ctx, flow, containers)
 	publisher.FlowDeleted(ctx, flow, containers)
@@ -418,6 +667,13 @@ func (r *mutationResolver) CreateProvider(ctx context.Context, name string, type
 	}
 
 	r.Subscriptions.NewFlowPublisher(uid, 0).ProviderCreated(ctx, prv, cfg)
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionProviderCreate,
+		TargetType: audit.TargetProvider,
+		TargetID:   prv.ID,
+		TargetName: prv.Name,
+		After:      prv.Config,
+	})
 
 	return converter.ConvertProvider(prv, cfg), nil
 }
@@ -435,6 +691,14 @@ func (r *mutationResolver) UpdateProvider(ctx context.Context, providerID int64,
 		"name":     name,
 	}).Debug("update provider")
 
+	before, err := r.DB.GetUserProvider(ctx, database.GetUserProviderParams{
+		ID:     providerID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := converter.ConvertAgentsConfigFromGqlModel(&agents)
 	prvname := provider.ProviderName(name)
 	prv, err := r.ProvidersCtrl.UpdateProvider(ctx, uid, providerID, prvname, cfg)
@@ -443,6 +707,14 @@ func (r *mutationResolver) UpdateProvider(ctx context.Context, providerID int64,
 	}
 
 	r.Subscriptions.NewFlowPublisher(uid, 0).ProviderUpdated(ctx, prv, cfg)
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionProviderUpdate,
+		TargetType: audit.TargetProvider,
+		TargetID:   prv.ID,
+		TargetName: prv.Name,
+		Before:     before.Config,
+		After:      prv.Config,
+	})
 
 	return converter.ConvertProvider(prv, cfg), nil
 }
@@ -470,6 +742,13 @@ func (r *mutationResolver) DeleteProvider(ctx context.Context, providerID int64)
 	}
 
 	r.Subscriptions.NewFlowPublisher(uid, 0).ProviderDeleted(ctx, prv, &cfg)
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionProviderDelete,
+		TargetType: audit.TargetProvider,
+		TargetID:   prv.ID,
+		TargetName: prv.Name,
+		Before:     prv.Config,
+	})
 
 	return model.ResultTypeSuccess, nil
 }
@@ -559,6 +838,14 @@ func (r *mutationResolver) CreatePrompt(ctx context.Context, typeArg model.Promp
 		return nil, err
 	}
 
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionPromptCreate,
+		TargetType: audit.TargetPrompt,
+		TargetID:   prompt.ID,
+		TargetName: string(prompt.Type),
+		After:      prompt,
+	})
+
 	return converter.ConvertPrompt(prompt), nil
 }
 
@@ -587,6 +874,7 @@ func (r *mutationResolver) UpdatePrompt(ctx context.Context, promptID int64, tem
 		return nil, err
 	}
 
+	before := prompt
 	prompt, err = r.DB.UpdateUserPrompt(ctx, database.UpdateUserPromptParams{
 		ID:     promptID,
 		Prompt: template,
@@ -596,6 +884,15 @@ func (r *mutationResolver) UpdatePrompt(ctx context.Context, promptID int64, tem
 		return nil, err
 	}
 
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionPromptUpdate,
+		TargetType: audit.TargetPrompt,
+		TargetID:   prompt.ID,
+		TargetName: string(prompt.Type),
+		Before:     before,
+		After:      prompt,
+	})
+
 	return converter.ConvertPrompt(prompt), nil
 }
 
@@ -611,6 +908,14 @@ func (r *mutationResolver) DeletePrompt(ctx context.Context, promptID int64) (mo
 		"prompt": promptID,
 	}).Debug("delete prompt")
 
+	prompt, err := r.DB.GetUserPrompt(ctx, database.GetUserPromptParams{
+		ID:     promptID,
+		UserID: uid,
+	})
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
 	err = r.DB.DeleteUserPrompt(ctx, database.DeleteUserPromptParams{
 		ID:     promptID,
 		UserID: uid,
@@ -619,218 +924,2447 @@ func (r *mutationResolver) DeletePrompt(ctx context.Context, promptID int64) (mo
 		return model.ResultTypeError, err
 	}
 
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionPromptDelete,
+		TargetType: audit.TargetPrompt,
+		TargetID:   prompt.ID,
+		TargetName: string(prompt.Type),
+		Before:     prompt,
+	})
+
 	return model.ResultTypeSuccess, nil
 }
 
-// Providers is the resolver for the providers field.
-func (r *queryResolver) Providers(ctx context.Context) ([]*model.Provider, error) {
-	uid, _, err := validatePermission(ctx, "providers.view")
+// CreatePlaybook is the resolver for the createPlaybook field.
+func (r *mutationResolver) CreatePlaybook(ctx context.Context, content string) (*model.Playbook, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.edit")
 	if err != nil {
 		return nil, err
 	}
 
 	r.Logger.WithFields(logrus.Fields{
-		"uid": uid,
-	}).Debug("get providers")
+		"uid":     uid,
+		"content": content[:min(len(content), 1000)],
+	}).Debug("create playbook")
 
-	providers, err := r.ProvidersCtrl.GetProviders(ctx, uid)
+	doc, err := playbook.Parse(content)
 	if err != nil {
 		return nil, err
 	}
 
-	providersList := make([]*model.Provider, len(providers))
-	for i, prvname := range providers.ListNames() {
-		providersList[i] = &model.Provider{
-			Name: string(prvname),
-			Type: model.ProviderType(providers[prvname].Type()),
-		}
+	pb, err := r.DB.CreateUserPlaybook(ctx, database.CreateUserPlaybookParams{
+		UserID:      uid,
+		Name:        doc.Name,
+		Description: doc.Description,
+		Content:     content,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return providersList, nil
+	return converter.ConvertPlaybook(pb), nil
 }
 
-// Assistants is the resolver for the assistants field.
-func (r *queryResolver) Assistants(ctx context.Context, flowID int64) ([]*model.Assistant, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "assistants.view", flowID, r.DB)
+// UpdatePlaybook is the resolver for the updatePlaybook field.
+func (r *mutationResolver) UpdatePlaybook(ctx context.Context, playbookID int64, content string) (*model.Playbook, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.edit")
 	if err != nil {
 		return nil, err
 	}
 
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get assistants")
+		"uid":      uid,
+		"playbook": playbookID,
+		"content":  content[:min(len(content), 1000)],
+	}).Debug("update playbook")
 
-	assistants, err := r.DB.GetFlowAssistants(ctx, flowID)
+	doc, err := playbook.Parse(content)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertAssistants(assistants), nil
+	pb, err := r.DB.UpdateUserPlaybook(ctx, database.UpdateUserPlaybookParams{
+		ID:          playbookID,
+		UserID:      uid,
+		Name:        doc.Name,
+		Description: doc.Description,
+		Content:     content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertPlaybook(pb), nil
 }
 
-// Flows is the resolver for the flows field.
-func (r *queryResolver) Flows(ctx context.Context) ([]*model.Flow, error) {
-	uid, admin, err := validatePermission(ctx, "flows.view")
+// DeletePlaybook is the resolver for the deletePlaybook field.
+func (r *mutationResolver) DeletePlaybook(ctx context.Context, playbookID int64) (model.ResultType, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.edit")
 	if err != nil {
-		return nil, err
+		return model.ResultTypeError, err
 	}
 
 	r.Logger.WithFields(logrus.Fields{
-		"uid": uid,
-	}).Debug("get flows")
-
-	var (
-		flows      []database.Flow
-		containers []database.Container
-	)
+		"uid":      uid,
+		"playbook": playbookID,
+	}).Debug("delete playbook")
 
-	if admin {
-		flows, err = r.DB.GetFlows(ctx)
-	} else {
-		flows, err = r.DB.GetUserFlows(ctx, uid)
+	_, err = r.DB.DeleteUserPlaybook(ctx, database.DeleteUserPlaybookParams{
+		ID:     playbookID,
+		UserID: uid,
+	})
+	if err != nil {
+		return model.ResultTypeError, err
 	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// CreateFlowFromPlaybook is the resolver for the createFlowFromPlaybook field.
+func (r *mutationResolver) CreateFlowFromPlaybook(ctx context.Context, modelProvider string, playbookID int64, variables []*model.PlaybookVariableInput, planReview *bool, priority *model.FlowPriority, limits *model.FlowLimitsInput) (*model.Flow, error) {
+	uid, _, err := validatePermission(ctx, "flows.create")
 	if err != nil {
 		return nil, err
 	}
 
-	if _, admin, err = validatePermission(ctx, "containers.view"); err == nil {
-		if admin {
-			containers, err = r.DB.GetContainers(ctx)
-		} else {
-			containers, err = r.DB.GetUserContainers(ctx, uid)
-		}
-		if err != nil {
-			return nil, err
-		}
+	if _, _, err = validatePermission(ctx, "playbooks.view"); err != nil {
+		return nil, err
 	}
 
-	return converter.ConvertFlows(flows, containers), nil
+	// variable values aren't logged because they may contain credentials
+	r.Logger.WithFields(logrus.Fields{
+		"uid":       uid,
+		"provider":  modelProvider,
+		"playbook":  playbookID,
+		"variables": len(variables),
+	}).Debug("create flow from playbook")
+
+	if modelProvider == "" {
+		return nil, fmt.Errorf("model provider is required")
+	}
+
+	pb, err := r.DB.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     playbookID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := playbook.Parse(pb.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		values[variable.Name] = variable.Value
+	}
+
+	doc, err = doc.Render(values)
+	if err != nil {
+		return nil, err
+	}
+
+	prvname := provider.ProviderName(modelProvider)
+	prv, err := r.ProvidersCtrl.GetProvider(ctx, prvname, uid)
+	if err != nil {
+		return nil, err
+	}
+	prvtype := prv.Type()
+
+	fl, err := flowLimits(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := r.Controller.CreateFlowFromPlaybook(ctx, uid, &pb.ID, doc, prvname, prvtype, planReview != nil && *planReview, flowPriority(priority), fl)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowCreate,
+		TargetType: audit.TargetFlow,
+		TargetID:   flow.ID,
+		TargetName: flow.Title,
+		After:      flow,
+	})
+
+	// the flow is queued, so it has no containers yet
+	return converter.ConvertFlow(flow, nil), nil
 }
 
-// Flow is the resolver for the flow field.
-func (r *queryResolver) Flow(ctx context.Context, flowID int64) (*model.Flow, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "flows.view", flowID, r.DB)
+// CreateSchedule is the resolver for the createSchedule field.
+func (r *mutationResolver) CreateSchedule(ctx context.Context, schedule model.ScheduleInput) (*model.Schedule, error) {
+	uid, _, err := validatePermission(ctx, "schedules.create")
 	if err != nil {
 		return nil, err
 	}
 
+	if _, _, err = validatePermission(ctx, "flows.create"); err != nil {
+		return nil, err
+	}
+
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get flow")
+		"uid":      uid,
+		"name":     schedule.Name,
+		"cron":     schedule.Cron,
+		"provider": schedule.ModelProvider,
+	}).Debug("create schedule")
 
-	var (
-		flow       database.Flow
-		containers []database.Container
-	)
+	params, err := r.scheduleParams(ctx, uid, schedule)
+	if err != nil {
+		return nil, err
+	}
 
-	flow, err = r.DB.GetFlow(ctx, flowID)
+	sched, err := r.DB.CreateSchedule(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, _, err = validatePermission(ctx, "containers.view"); err == nil {
-		containers, err = r.DB.GetFlowContainers(ctx, flowID)
+	return converter.ConvertSchedule(sched), nil
+}
+
+// UpdateSchedule is the resolver for the updateSchedule field.
+func (r *mutationResolver) UpdateSchedule(ctx context.Context, scheduleID int64, schedule model.ScheduleInput) (*model.Schedule, error) {
+	uid, sched, err := validatePermissionWithScheduleID(ctx, "schedules.edit", scheduleID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"schedule": scheduleID,
+		"name":     schedule.Name,
+		"cron":     schedule.Cron,
+		"provider": schedule.ModelProvider,
+	}).Debug("update schedule")
+
+	params, err := r.scheduleParams(ctx, sched.UserID, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	// disabled schedule keeps no next run until it's enabled again
+	if !sched.Enabled {
+		params.NextRunAt = sql.NullTime{}
+	}
+
+	sched, err = r.DB.UpdateSchedule(ctx, database.UpdateScheduleParams{
+		ID:                scheduleID,
+		Name:              params.Name,
+		Cron:              params.Cron,
+		Timezone:          params.Timezone,
+		Input:             params.Input,
+		PlaybookID:        params.PlaybookID,
+		Variables:         params.Variables,
+		Scope:             params.Scope,
+		ModelProviderName: params.ModelProviderName,
+		ModelProviderType: params.ModelProviderType,
+		PlanReview:        params.PlanReview,
+		MissedPolicy:      params.MissedPolicy,
+		NextRunAt:         params.NextRunAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertSchedule(sched), nil
+}
+
+// EnableSchedule is the resolver for the enableSchedule field.
+func (r *mutationResolver) EnableSchedule(ctx context.Context, scheduleID int64, enabled bool) (*model.Schedule, error) {
+	uid, sched, err := validatePermissionWithScheduleID(ctx, "schedules.edit", scheduleID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"schedule": scheduleID,
+		"enabled":  enabled,
+	}).Debug("enable schedule")
+
+	// the next run is counted from now, so runs missed while the schedule was disabled are not performed
+	var nextRunAt sql.NullTime
+	if enabled {
+		next, err := scheduler.NextRun(sched.Cron, sched.Timezone, time.Now())
 		if err != nil {
 			return nil, err
 		}
+		nextRunAt = database.TimeToNullTime(next)
 	}
 
-	return converter.ConvertFlow(flow, containers), nil
+	sched, err = r.DB.UpdateScheduleEnabled(ctx, database.UpdateScheduleEnabledParams{
+		ID:        scheduleID,
+		Enabled:   enabled,
+		NextRunAt: nextRunAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertSchedule(sched), nil
 }
 
-// Tasks is the resolver for the tasks field.
-func (r *queryResolver) Tasks(ctx context.Context, flowID int64) ([]*model.Task, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "tasks.view", flowID, r.DB)
+// DeleteSchedule is the resolver for the deleteSchedule field.
+func (r *mutationResolver) DeleteSchedule(ctx context.Context, scheduleID int64) (model.ResultType, error) {
+	uid, _, err := validatePermissionWithScheduleID(ctx, "schedules.delete", scheduleID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"schedule": scheduleID,
+	}).Debug("delete schedule")
+
+	if _, err = r.DB.DeleteSchedule(ctx, scheduleID); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// CreateCampaign is the resolver for the createCampaign field.
+func (r *mutationResolver) CreateCampaign(ctx context.Context, campaign model.CampaignInput) (*model.Campaign, error) {
+	uid, _, err := validatePermission(ctx, "campaigns.create")
 	if err != nil {
 		return nil, err
 	}
 
+	if _, _, err = validatePermission(ctx, "flows.create"); err != nil {
+		return nil, err
+	}
+
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get tasks")
+		"uid":      uid,
+		"name":     campaign.Name,
+		"targets":  len(campaign.Targets),
+		"provider": campaign.ModelProvider,
+	}).Debug("create campaign")
 
-	tasks, err := r.DB.GetFlowTasks(ctx, flowID)
+	params, targets, err := r.campaignParams(ctx, uid, campaign)
 	if err != nil {
 		return nil, err
 	}
 
-	var subtasks []database.Subtask
-	if _, _, err = validatePermission(ctx, "subtasks.view"); err == nil {
-		subtasks, err = r.DB.GetFlowSubtasks(ctx, flowID)
+	// the scheduler must not see the running campaign before its targets are stored,
+	// otherwise it's finished as the campaign without pending targets
+	var camp database.Campaign
+	err = database.RunInTx(ctx, r.DB, func(tx database.Querier) error {
+		camp, err = tx.CreateCampaign(ctx, params)
 		if err != nil {
-			return nil, err
+			return err
 		}
+
+		return tx.CreateCampaignTargets(ctx, database.CreateCampaignTargetsParams{
+			CampaignID: camp.ID,
+			Targets:    targets,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return converter.ConvertTasks(tasks, subtasks), nil
+	return converter.ConvertCampaign(camp), nil
 }
 
-// Screenshots is the resolver for the screenshots field.
-func (r *queryResolver) Screenshots(ctx context.Context, flowID int64) ([]*model.Screenshot, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "screenshots.view", flowID, r.DB)
+// StopCampaign is the resolver for the stopCampaign field.
+func (r *mutationResolver) StopCampaign(ctx context.Context, campaignID int64) (*model.Campaign, error) {
+	uid, camp, err := validatePermissionWithCampaignID(ctx, "campaigns.edit", campaignID, r.DB)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, _, err = validatePermission(ctx, "flows.edit"); err != nil {
+		return nil, err
+	}
+
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get screenshots")
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("stop campaign")
 
-	screenshots, err := r.DB.GetFlowScreenshots(ctx, flowID)
+	camp, err = r.stopCampaign(ctx, camp, database.CampaignStatusStopped, scheduler.IsActiveFlow, r.Controller.StopFlow)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertScreenshots(screenshots), nil
+	return converter.ConvertCampaign(camp), nil
 }
 
-// TerminalLogs is the resolver for the terminalLogs field.
-func (r *queryResolver) TerminalLogs(ctx context.Context, flowID int64) ([]*model.TerminalLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "termlogs.view", flowID, r.DB)
+// FinishCampaign is the resolver for the finishCampaign field.
+func (r *mutationResolver) FinishCampaign(ctx context.Context, campaignID int64) (*model.Campaign, error) {
+	uid, camp, err := validatePermissionWithCampaignID(ctx, "campaigns.edit", campaignID, r.DB)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, _, err = validatePermission(ctx, "flows.edit"); err != nil {
+		return nil, err
+	}
+
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get term logs")
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("finish campaign")
 
-	logs, err := r.DB.GetFlowTermLogs(ctx, flowID)
+	notFinished := func(status database.FlowStatus) bool {
+		return status != database.FlowStatusFinished && status != database.FlowStatusFailed
+	}
+	camp, err = r.stopCampaign(ctx, camp, database.CampaignStatusFinished, notFinished, r.Controller.FinishFlow)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertTerminalLogs(logs, flowID), nil
+	return converter.ConvertCampaign(camp), nil
 }
 
-// MessageLogs is the resolver for the messageLogs field.
-func (r *queryResolver) MessageLogs(ctx context.Context, flowID int64) ([]*model.MessageLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "msglogs.view", flowID, r.DB)
+// DeleteCampaign is the resolver for the deleteCampaign field.
+func (r *mutationResolver) DeleteCampaign(ctx context.Context, campaignID int64) (model.ResultType, error) {
+	uid, _, err := validatePermissionWithCampaignID(ctx, "campaigns.delete", campaignID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("delete campaign")
+
+	if _, err = r.DB.DeleteCampaign(ctx, campaignID); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	return model.ResultTypeSuccess, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, webhook model.WebhookInput) (*model.Webhook, error) {
+	uid, admin, err := validatePermission(ctx, "webhooks.create")
 	if err != nil {
 		return nil, err
 	}
 
+	system := webhook.System != nil && *webhook.System
+	if system && !admin {
+		return nil, fmt.Errorf("not permitted to create system-wide webhook")
+	}
+
+	// the url isn't logged because it may contain credentials
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get msg logs")
+		"uid":    uid,
+		"name":   webhook.Name,
+		"system": system,
+	}).Debug("create webhook")
 
-	logs, err := r.DB.GetFlowMsgLogs(ctx, flowID)
+	params, err := webhookParams(webhook, "")
+	if err != nil {
+		return nil, err
+	}
+	if !system {
+		params.UserID = database.Int64ToNullInt64(&uid)
+	}
+
+	hook, err := r.DB.CreateWebhook(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionWebhookCreate,
+		TargetType: audit.TargetWebhook,
+		TargetID:   hook.ID,
+		TargetName: hook.Name,
+		After:      hook,
+	})
+
+	return converter.ConvertWebhook(hook), nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, webhookID int64, webhook model.WebhookInput) (*model.Webhook, error) {
+	uid, hook, err := validatePermissionWithWebhookID(ctx, "webhooks.edit", webhookID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"webhook": webhookID,
+		"name":    webhook.Name,
+	}).Debug("update webhook")
+
+	params, err := webhookParams(webhook, hook.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	before := hook
+	hook, err = r.DB.UpdateWebhook(ctx, database.UpdateWebhookParams{
+		ID:      webhookID,
+		Name:    params.Name,
+		Url:     params.Url,
+		Secret:  params.Secret,
+		Events:  params.Events,
+		Enabled: params.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionWebhookUpdate,
+		TargetType: audit.TargetWebhook,
+		TargetID:   hook.ID,
+		TargetName: hook.Name,
+		Before:     before,
+		After:      hook,
+	})
+
+	return converter.ConvertWebhook(hook), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, webhookID int64) (model.ResultType, error) {
+	uid, _, err := validatePermissionWithWebhookID(ctx, "webhooks.delete", webhookID, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"webhook": webhookID,
+	}).Debug("delete webhook")
+
+	hook, err := r.DB.DeleteWebhook(ctx, webhookID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionWebhookDelete,
+		TargetType: audit.TargetWebhook,
+		TargetID:   hook.ID,
+		TargetName: hook.Name,
+		Before:     hook,
+	})
+
+	return model.ResultTypeSuccess, nil
+}
+
+// TestWebhook is the resolver for the testWebhook field.
+func (r *mutationResolver) TestWebhook(ctx context.Context, webhookID int64) (*model.WebhookDelivery, error) {
+	uid, hook, err := validatePermissionWithWebhookID(ctx, "webhooks.edit", webhookID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"webhook": webhookID,
+	}).Debug("test webhook")
+
+	delivery, err := r.Webhooks.Test(ctx, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertWebhookDelivery(delivery), nil
+}
+
+// CreateAPIKey is the resolver for the createApiKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, apiKey model.APIKeyInput) (*model.APIKeyToken, error) {
+	uid, _, err := validatePermission(ctx, "api_keys.create")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":        uid,
+		"name":       apiKey.Name,
+		"privileges": apiKey.Privileges,
+		"expires_at": apiKey.ExpiresAt,
+	}).Debug("create api key")
+
+	privs, err := GetUserPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.Name == "" {
+		return nil, fmt.Errorf("api key name is required")
+	} else if err := auth.ValidateAPIKeyPrivileges(privs, apiKey.Privileges); err != nil {
+		return nil, err
+	} else if err := auth.ValidateAPIKeyExpiry(apiKey.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.DB.CreateApiKey(ctx, database.CreateApiKeyParams{
+		UserID:     uid,
+		Name:       apiKey.Name,
+		KeyPrefix:  prefix,
+		KeyHash:    hash,
+		Privileges: apiKey.Privileges,
+		ExpiresAt:  apiKey.ExpiresAt,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertMessageLogs(logs), nil
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionAPIKeyCreate,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   created.ID,
+		TargetName: created.Name,
+		After:      created,
+	})
+
+	return &model.APIKeyToken{
+		Key:    key,
+		APIKey: converter.ConvertAPIKey(created),
+	}, nil
+}
+
+// RevokeAPIKey is the resolver for the revokeApiKey field.
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, apiKeyID int64) (*model.APIKey, error) {
+	uid, err := validatePermissionWithAPIKeyID(ctx, "api_keys.delete", apiKeyID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"api_key": apiKeyID,
+	}).Debug("revoke api key")
+
+	key, err := r.DB.RevokeApiKey(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionAPIKeyRevoke,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   key.ID,
+		TargetName: key.Name,
+	})
+
+	return converter.ConvertAPIKey(key), nil
+}
+
+// CreateRole is the resolver for the createRole field.
+func (r *mutationResolver) CreateRole(ctx context.Context, role model.RoleInput) (*model.Role, error) {
+	uid, _, err := validatePermission(ctx, "roles.create")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":        uid,
+		"name":       role.Name,
+		"privileges": role.Privileges,
+	}).Debug("create role")
+
+	if err := validateRolePrivileges(ctx, role.Privileges); err != nil {
+		return nil, err
+	}
+
+	created, err := createRole(ctx, r.DB, role.Name, role.Privileges)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionRoleCreate,
+		TargetType: audit.TargetRole,
+		TargetID:   created.ID,
+		TargetName: created.Name,
+		After:      role,
+	})
+
+	return created, nil
+}
+
+// CloneRole is the resolver for the cloneRole field.
+func (r *mutationResolver) CloneRole(ctx context.Context, roleID int64, name string) (*model.Role, error) {
+	uid, _, err := validatePermission(ctx, "roles.create")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"role": roleID,
+		"name": name,
+	}).Debug("clone role")
+
+	source, err := r.DB.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRolePrivileges(ctx, source.Privileges); err != nil {
+		return nil, err
+	}
+
+	created, err := createRole(ctx, r.DB, name, source.Privileges)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionRoleCreate,
+		TargetType: audit.TargetRole,
+		TargetID:   created.ID,
+		TargetName: created.Name,
+		After:      created,
+	})
+
+	return created, nil
+}
+
+// UpdateRole is the resolver for the updateRole field.
+func (r *mutationResolver) UpdateRole(ctx context.Context, roleID int64, role model.RoleInput) (*model.Role, error) {
+	uid, _, err := validatePermission(ctx, "roles.edit")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":        uid,
+		"role":       roleID,
+		"name":       role.Name,
+		"privileges": role.Privileges,
+	}).Debug("update role")
+
+	current, err := r.DB.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the admin role must keep all privileges, and the names of built-in roles are used by the role rules
+	if roleID == models.RoleAdmin || (roleID == models.RoleUser && role.Name != current.Name) {
+		return nil, fmt.Errorf("built-in role can't be changed")
+	}
+
+	// the current privileges are checked too, so the user can't revoke privileges
+	// from the role which has more privileges than the user has
+	if err := validateRolePrivileges(ctx, role.Privileges); err != nil {
+		return nil, err
+	} else if err := validateRolePrivileges(ctx, current.Privileges); err != nil {
+		return nil, err
+	}
+
+	updated, err := updateRole(ctx, r.DB, current, role.Name, role.Privileges)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionRoleUpdate,
+		TargetType: audit.TargetRole,
+		TargetID:   updated.ID,
+		TargetName: updated.Name,
+		Before:     current,
+		After:      updated,
+	})
+
+	return converter.ConvertRole(updated), nil
+}
+
+// CreateTeam is the resolver for the createTeam field.
+func (r *mutationResolver) CreateTeam(ctx context.Context, team model.TeamInput) (*model.Team, error) {
+	uid, _, err := validatePermission(ctx, "teams.create")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"name": team.Name,
+	}).Debug("create team")
+
+	if err := validateTeamName(ctx, r.DB, team.Name, 0); err != nil {
+		return nil, err
+	}
+
+	created, err := r.DB.CreateTeam(ctx, database.CreateTeamParams{
+		Name:        team.Name,
+		Description: teamDescription(team),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the creator becomes the first team admin, the team without admins can't be managed
+	_, err = r.DB.SetTeamMember(ctx, database.SetTeamMemberParams{
+		TeamID: created.ID,
+		UserID: uid,
+		Role:   database.TeamRoleAdmin,
+	})
+	if err != nil {
+		if err := r.DB.DeleteTeam(ctx, created.ID); err != nil {
+			r.Logger.WithError(err).Errorf("failed to delete team %d", created.ID)
+		}
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionTeamCreate,
+		TargetType: audit.TargetTeam,
+		TargetID:   created.ID,
+		TargetName: created.Name,
+		After:      created,
+	})
+
+	return getTeam(ctx, r.DB, created.ID)
+}
+
+// UpdateTeam is the resolver for the updateTeam field.
+func (r *mutationResolver) UpdateTeam(ctx context.Context, teamID int64, team model.TeamInput) (*model.Team, error) {
+	uid, err := validatePermissionWithTeamID(ctx, "teams.edit", teamID, models.TeamRoleAdmin, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"team": teamID,
+		"name": team.Name,
+	}).Debug("update team")
+
+	if err := validateTeamName(ctx, r.DB, team.Name, teamID); err != nil {
+		return nil, err
+	}
+
+	before, err := r.DB.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := r.DB.UpdateTeam(ctx, database.UpdateTeamParams{
+		Name:        team.Name,
+		Description: teamDescription(team),
+		ID:          teamID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionTeamUpdate,
+		TargetType: audit.TargetTeam,
+		TargetID:   updated.ID,
+		TargetName: updated.Name,
+		Before:     before,
+		After:      updated,
+	})
+
+	return getTeam(ctx, r.DB, teamID)
+}
+
+// DeleteTeam is the resolver for the deleteTeam field.
+func (r *mutationResolver) DeleteTeam(ctx context.Context, teamID int64) (model.ResultType, error) {
+	uid, err := validatePermissionWithTeamID(ctx, "teams.delete", teamID, models.TeamRoleAdmin, r.DB)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"team": teamID,
+	}).Debug("delete team")
+
+	team, err := r.DB.GetTeam(ctx, teamID)
+	if err != nil {
+		return model.ResultTypeError, err
+	}
+
+	if err := r.DB.DeleteTeam(ctx, teamID); err != nil {
+		return model.ResultTypeError, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionTeamDelete,
+		TargetType: audit.TargetTeam,
+		TargetID:   team.ID,
+		TargetName: team.Name,
+		Before:     team,
+	})
+
+	return model.ResultTypeSuccess, nil
+}
+
+// SetTeamMember is the resolver for the setTeamMember field.
+func (r *mutationResolver) SetTeamMember(ctx context.Context, teamID int64, userID int64, role model.TeamRole) (*model.Team, error) {
+	uid, err := validatePermissionWithTeamID(ctx, "teams.edit", teamID, models.TeamRoleAdmin, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":    uid,
+		"team":   teamID,
+		"member": userID,
+		"role":   role.String(),
+	}).Debug("set team member")
+
+	user, err := r.DB.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateNewTeamMember(ctx, r.DB, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	if role != model.TeamRoleAdmin {
+		if err := validateLastTeamAdmin(ctx, r.DB, teamID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = r.DB.SetTeamMember(ctx, database.SetTeamMemberParams{
+		TeamID: teamID,
+		UserID: userID,
+		Role:   database.TeamRole(role),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionTeamMemberSet,
+		TargetType: audit.TargetTeam,
+		TargetID:   teamID,
+		TargetName: "",
+		After:      map[string]any{"user_id": userID, "user_mail": user.Mail, "role": role},
+	})
+
+	return getTeam(ctx, r.DB, teamID)
+}
+
+// RemoveTeamMember is the resolver for the removeTeamMember field.
+func (r *mutationResolver) RemoveTeamMember(ctx context.Context, teamID int64, userID int64) (*model.Team, error) {
+	// any member can leave the team, other members are removed by the team admin
+	required := models.TeamRoleAdmin
+	if uid, _, err := validatePermission(ctx, "teams.view"); err == nil && uid == userID {
+		required = models.TeamRoleViewer
+	}
+
+	uid, err := validatePermissionWithTeamID(ctx, "teams.view", teamID, required, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":    uid,
+		"team":   teamID,
+		"member": userID,
+	}).Debug("remove team member")
+
+	if err := validateLastTeamAdmin(ctx, r.DB, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	err = r.DB.DeleteTeamMember(ctx, database.DeleteTeamMemberParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionTeamMemberRemove,
+		TargetType: audit.TargetTeam,
+		TargetID:   teamID,
+		TargetName: "",
+		Before:     map[string]any{"user_id": userID},
+	})
+
+	return getTeam(ctx, r.DB, teamID)
+}
+
+// ShareFlow is the resolver for the shareFlow field.
+func (r *mutationResolver) ShareFlow(ctx context.Context, flowID int64, teamID *int64) (*model.Flow, error) {
+	uid, admin, err := validatePermission(ctx, "flows.edit")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+		"team": teamID,
+	}).Debug("share flow")
+
+	// only the owner moves the flow between teams, team members can't reshare it
+	flow, err := r.DB.GetFlow(ctx, flowID)
+	if err != nil {
+		return nil, err
+	} else if !admin && flow.UserID != uid {
+		return nil, fmt.Errorf("not permitted")
+	}
+
+	team, err := validateShareTeamID(ctx, r.DB, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := flow
+	flow, err = r.DB.UpdateFlowTeam(ctx, database.UpdateFlowTeamParams{
+		TeamID: team,
+		ID:     flowID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := r.DB.GetFlowContainers(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Subscriptions.NewFlowPublisher(flow.UserID, flow.ID).FlowUpdated(ctx, flow, containers)
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowShare,
+		TargetType: audit.TargetFlow,
+		TargetID:   flow.ID,
+		TargetName: flow.Title,
+		Before:     map[string]any{"team_id": before.TeamID},
+		After:      map[string]any{"team_id": flow.TeamID},
+	})
+
+	return converter.ConvertFlow(flow, containers), nil
+}
+
+// ShareProvider is the resolver for the shareProvider field.
+func (r *mutationResolver) ShareProvider(ctx context.Context, providerID int64, teamID *int64) (*model.ProviderConfig, error) {
+	uid, _, err := validatePermission(ctx, "settings.providers.edit")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"provider": providerID,
+		"team":     teamID,
+	}).Debug("share provider")
+
+	before, err := r.DB.GetUserProvider(ctx, database.GetUserProviderParams{
+		ID:     providerID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := validateShareTeamID(ctx, r.DB, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	prv, err := r.DB.UpdateProviderTeam(ctx, database.UpdateProviderTeamParams{
+		TeamID: team,
+		ID:     providerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg pconfig.ProviderConfig
+	if err := json.Unmarshal(prv.Config, &cfg); err != nil {
+		return nil, err
+	}
+
+	r.Subscriptions.NewFlowPublisher(uid, 0).ProviderUpdated(ctx, prv, &cfg)
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionProviderShare,
+		TargetType: audit.TargetProvider,
+		TargetID:   prv.ID,
+		TargetName: prv.Name,
+		Before:     map[string]any{"team_id": before.TeamID},
+		After:      map[string]any{"team_id": prv.TeamID},
+	})
+
+	return converter.ConvertProvider(prv, &cfg), nil
+}
+
+// SharePrompt is the resolver for the sharePrompt field.
+func (r *mutationResolver) SharePrompt(ctx context.Context, promptID int64, teamID *int64) (*model.UserPrompt, error) {
+	uid, _, err := validatePermission(ctx, "settings.prompts.edit")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":    uid,
+		"prompt": promptID,
+		"team":   teamID,
+	}).Debug("share prompt")
+
+	before, err := r.DB.GetUserPrompt(ctx, database.GetUserPromptParams{
+		ID:     promptID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := validateShareTeamID(ctx, r.DB, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := r.DB.UpdatePromptTeam(ctx, database.UpdatePromptTeamParams{
+		TeamID: team,
+		ID:     promptID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionPromptShare,
+		TargetType: audit.TargetPrompt,
+		TargetID:   prompt.ID,
+		TargetName: string(prompt.Type),
+		Before:     map[string]any{"team_id": before.TeamID},
+		After:      map[string]any{"team_id": prompt.TeamID},
+	})
+
+	return converter.ConvertPrompt(prompt), nil
+}
+
+// SharePlaybook is the resolver for the sharePlaybook field.
+func (r *mutationResolver) SharePlaybook(ctx context.Context, playbookID int64, teamID *int64) (*model.Playbook, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.edit")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"playbook": playbookID,
+		"team":     teamID,
+	}).Debug("share playbook")
+
+	// team members can read the playbook, but only the owner moves it between teams
+	pb, err := r.DB.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     playbookID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	} else if pb.UserID != uid {
+		return nil, fmt.Errorf("not permitted")
+	}
+
+	team, err := validateShareTeamID(ctx, r.DB, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	pb, err = r.DB.UpdatePlaybookTeam(ctx, database.UpdatePlaybookTeamParams{
+		TeamID: team,
+		ID:     playbookID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertPlaybook(pb), nil
+}
+
+// CreateFlowShare is the resolver for the createFlowShare field.
+func (r *mutationResolver) CreateFlowShare(ctx context.Context, flowID int64, share model.FlowShareInput) (*model.FlowShareToken, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flow_shares.create", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":            uid,
+		"flow":           flowID,
+		"expires_at":     share.ExpiresAt,
+		"with_term_logs": share.WithTerminalLogs,
+	}).Debug("create flow share")
+
+	if !share.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry time must be in the future")
+	}
+
+	if _, err := r.DB.GetFlow(ctx, flowID); err != nil {
+		return nil, err
+	}
+
+	token, prefix, hash, err := auth.GenerateFlowShareToken(r.Config.CookieSigningSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.DB.CreateFlowShare(ctx, database.CreateFlowShareParams{
+		FlowID:           flowID,
+		UserID:           uid,
+		TokenPrefix:      prefix,
+		TokenHash:        hash,
+		WithTerminalLogs: share.WithTerminalLogs != nil && *share.WithTerminalLogs,
+		ExpiresAt:        share.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowShareCreate,
+		TargetType: audit.TargetFlowShare,
+		TargetID:   created.ID,
+		TargetName: created.TokenPrefix,
+		After:      created,
+	})
+
+	return &model.FlowShareToken{
+		Token: token,
+		Share: converter.ConvertFlowShare(created),
+	}, nil
+}
+
+// RevokeFlowShare is the resolver for the revokeFlowShare field.
+func (r *mutationResolver) RevokeFlowShare(ctx context.Context, shareID int64) (*model.FlowShare, error) {
+	uid, err := validatePermissionWithFlowShareID(ctx, "flow_shares.delete", shareID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":   uid,
+		"share": shareID,
+	}).Debug("revoke flow share")
+
+	share, err := r.DB.RevokeFlowShare(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordAudit(ctx, audit.Event{
+		Action:     audit.ActionFlowShareRevoke,
+		TargetType: audit.TargetFlowShare,
+		TargetID:   share.ID,
+		TargetName: share.TokenPrefix,
+	})
+
+	return converter.ConvertFlowShare(share), nil
+}
+
+// Providers is the resolver for the providers field.
+func (r *queryResolver) Providers(ctx context.Context) ([]*model.Provider, error) {
+	uid, _, err := validatePermission(ctx, "providers.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get providers")
+
+	providers, err := r.ProvidersCtrl.GetProviders(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	providersList := make([]*model.Provider, len(providers))
+	for i, prvname := range providers.ListNames() {
+		providersList[i] = &model.Provider{
+			Name: string(prvname),
+			Type: model.ProviderType(providers[prvname].Type()),
+		}
+	}
+
+	return providersList, nil
+}
+
+// Assistants is the resolver for the assistants field.
+func (r *queryResolver) Assistants(ctx context.Context, flowID int64) ([]*model.Assistant, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "assistants.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get assistants")
+
+	assistants, err := r.DB.GetFlowAssistants(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertAssistants(assistants), nil
+}
+
+// Flows is the resolver for the flows field.
+func (r *queryResolver) Flows(ctx context.Context) ([]*model.Flow, error) {
+	uid, admin, err := validatePermission(ctx, "flows.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get flows")
+
+	var (
+		flows      []database.Flow
+		containers []database.Container
+	)
+
+	if admin {
+		flows, err = r.DB.GetFlows(ctx)
+	} else {
+		flows, err = r.DB.GetUserFlows(ctx, uid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, admin, err = validatePermission(ctx, "containers.view"); err == nil {
+		if admin {
+			containers, err = r.DB.GetContainers(ctx)
+		} else {
+			containers, err = r.DB.GetUserContainers(ctx, uid)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return converter.ConvertFlows(flows, containers), nil
+}
+
+// Flow is the resolver for the flow field.
+func (r *queryResolver) Flow(ctx context.Context, flowID int64) (*model.Flow, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get flow")
+
+	var (
+		flow       database.Flow
+		containers []database.Container
+	)
+
+	flow, err = r.DB.GetFlow(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err = validatePermission(ctx, "containers.view"); err == nil {
+		containers, err = r.DB.GetFlowContainers(ctx, flowID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return converter.ConvertFlow(flow, containers), nil
+}
+
+// FlowQueue is the resolver for the flowQueue field.
+func (r *queryResolver) FlowQueue(ctx context.Context) ([]*model.FlowQueueItem, error) {
+	uid, admin, err := validatePermission(ctx, "flows.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get flow queue")
+
+	items, err := r.DB.GetFlowQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// positions are counted over the whole queue, users see only their own flows
+	queue := make([]*model.FlowQueueItem, 0, len(items))
+	for idx, item := range items {
+		if admin || item.UserID == uid {
+			queue = append(queue, converter.ConvertFlowQueueItem(item, idx+1))
+		}
+	}
+
+	return queue, nil
+}
+
+// FlowLimits is the resolver for the flowLimits field.
+func (r *queryResolver) FlowLimits(ctx context.Context, flowID int64) (*model.FlowLimits, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flows.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get flow limits")
+
+	// the flow without own limits uses the global limits which are reported as zeros
+	limits, err := r.DB.GetFlowLimits(ctx, flowID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return converter.ConvertFlowLimits(limits), nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, flowID int64) ([]*model.Task, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "tasks.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get tasks")
+
+	tasks, err := r.DB.GetFlowTasks(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtasks []database.Subtask
+	if _, _, err = validatePermission(ctx, "subtasks.view"); err == nil {
+		subtasks, err = r.DB.GetFlowSubtasks(ctx, flowID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return converter.ConvertTasks(tasks, subtasks), nil
+}
+
+// Screenshots is the resolver for the screenshots field.
+func (r *queryResolver) Screenshots(ctx context.Context, flowID int64) ([]*model.Screenshot, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "screenshots.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get screenshots")
+
+	screenshots, err := r.DB.GetFlowScreenshots(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertScreenshots(screenshots), nil
+}
+
+// TerminalLogs is the resolver for the terminalLogs field.
+func (r *queryResolver) TerminalLogs(ctx context.Context, flowID int64) ([]*model.TerminalLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "termlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get term logs")
+
+	logs, err := r.DB.GetFlowTermLogs(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertTerminalLogs(logs, flowID), nil
+}
+
+// MessageLogs is the resolver for the messageLogs field.
+func (r *queryResolver) MessageLogs(ctx context.Context, flowID int64) ([]*model.MessageLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "msglogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get msg logs")
+
+	logs, err := r.DB.GetFlowMsgLogs(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertMessageLogs(logs), nil
+}
+
+// AgentLogs is the resolver for the agentLogs field.
+func (r *queryResolver) AgentLogs(ctx context.Context, flowID int64) ([]*model.AgentLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "agentlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get agent logs")
+
+	logs, err := r.DB.GetFlowAgentLogs(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertAgentLogs(logs), nil
+}
+
+// SearchLogs is the resolver for the searchLogs field.
+func (r *queryResolver) SearchLogs(ctx context.Context, flowID int64) ([]*model.SearchLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "searchlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get search logs")
+
+	logs, err := r.DB.GetFlowSearchLogs(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertSearchLogs(logs), nil
+}
+
+// VectorStoreLogs is the resolver for the vectorStoreLogs field.
+func (r *queryResolver) VectorStoreLogs(ctx context.Context, flowID int64) ([]*model.VectorStoreLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "vecstorelogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get vector store logs")
+
+	logs, err := r.DB.GetFlowVectorStoreLogs(ctx, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertVectorStoreLogs(logs), nil
+}
+
+// AssistantLogs is the resolver for the assistantLogs field.
+func (r *queryResolver) AssistantLogs(ctx context.Context, flowID int64, assistantID int64) ([]*model.AssistantLog, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "assistantlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":       uid,
+		"flow":      flowID,
+		"assistant": assistantID,
+	}).Debug("get assistant logs")
+
+	logs, err := r.DB.GetFlowAssistantLogs(ctx, database.GetFlowAssistantLogsParams{
+		FlowID:      flowID,
+		AssistantID: assistantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertAssistantLogs(logs), nil
+}
+
+// TerminalLogsConnection is the resolver for the terminalLogsConnection field.
+func (r *queryResolver) TerminalLogsConnection(ctx context.Context, flowID int64, first *int, after *string, last *int, before *string, filter *model.TerminalLogFilter, order *model.LogOrder) (*model.TerminalLogConnection, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "termlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get term logs page")
+
+	page, err := newLogsPage(first, after, last, before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &model.TerminalLogFilter{}
+	}
+
+	params := database.GetFlowTermLogsAscParams{
+		FlowID:        flowID,
+		ContainerID:   database.Int64ToNullInt64(filter.Terminal),
+		Types:         logTypes(filter.Types),
+		CreatedAfter:  logTime(filter.CreatedAfter),
+		CreatedBefore: logTime(filter.CreatedBefore),
+		AfterID:       page.afterID,
+		BeforeID:      page.beforeID,
+		RowLimit:      page.rowLimit(),
+	}
+
+	var logs []database.Termlog
+	if page.desc {
+		logs, err = r.DB.GetFlowTermLogsDesc(ctx, database.GetFlowTermLogsDescParams(params))
+	} else {
+		logs, err = r.DB.GetFlowTermLogsAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logs, pageInfo := logsPageRows(page, logs, func(log database.Termlog) int64 { return log.ID })
+	edges := make([]*model.TerminalLogEdge, 0, len(logs))
+	for _, log := range logs {
+		edges = append(edges, &model.TerminalLogEdge{
+			Cursor: encodeLogCursor(log.ID),
+			Node:   converter.ConvertTerminalLog(log, flowID),
+		})
+	}
+
+	return &model.TerminalLogConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// MessageLogsConnection is the resolver for the messageLogsConnection field.
+func (r *queryResolver) MessageLogsConnection(ctx context.Context, flowID int64, first *int, after *string, last *int, before *string, filter *model.MessageLogFilter, order *model.LogOrder) (*model.MessageLogConnection, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "msglogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get msg logs page")
+
+	page, err := newLogsPage(first, after, last, before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &model.MessageLogFilter{}
+	}
+
+	params := database.GetFlowMsgLogsAscParams{
+		FlowID:        flowID,
+		TaskID:        database.Int64ToNullInt64(filter.TaskID),
+		SubtaskID:     database.Int64ToNullInt64(filter.SubtaskID),
+		Types:         logTypes(filter.Types),
+		CreatedAfter:  logTime(filter.CreatedAfter),
+		CreatedBefore: logTime(filter.CreatedBefore),
+		AfterID:       page.afterID,
+		BeforeID:      page.beforeID,
+		RowLimit:      page.rowLimit(),
+	}
+
+	var logs []database.Msglog
+	if page.desc {
+		logs, err = r.DB.GetFlowMsgLogsDesc(ctx, database.GetFlowMsgLogsDescParams(params))
+	} else {
+		logs, err = r.DB.GetFlowMsgLogsAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logs, pageInfo := logsPageRows(page, logs, func(log database.Msglog) int64 { return log.ID })
+	edges := make([]*model.MessageLogEdge, 0, len(logs))
+	for _, log := range logs {
+		edges = append(edges, &model.MessageLogEdge{
+			Cursor: encodeLogCursor(log.ID),
+			Node:   converter.ConvertMessageLog(log),
+		})
+	}
+
+	return &model.MessageLogConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// AgentLogsConnection is the resolver for the agentLogsConnection field.
+func (r *queryResolver) AgentLogsConnection(ctx context.Context, flowID int64, first *int, after *string, last *int, before *string, filter *model.AgentLogFilter, order *model.LogOrder) (*model.AgentLogConnection, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "agentlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get agent logs page")
+
+	page, err := newLogsPage(first, after, last, before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &model.AgentLogFilter{}
+	}
+
+	params := database.GetFlowAgentLogsAscParams{
+		FlowID:        flowID,
+		TaskID:        database.Int64ToNullInt64(filter.TaskID),
+		SubtaskID:     database.Int64ToNullInt64(filter.SubtaskID),
+		Executors:     logTypes(filter.Executors),
+		CreatedAfter:  logTime(filter.CreatedAfter),
+		CreatedBefore: logTime(filter.CreatedBefore),
+		AfterID:       page.afterID,
+		BeforeID:      page.beforeID,
+		RowLimit:      page.rowLimit(),
+	}
+
+	var logs []database.Agentlog
+	if page.desc {
+		logs, err = r.DB.GetFlowAgentLogsDesc(ctx, database.GetFlowAgentLogsDescParams(params))
+	} else {
+		logs, err = r.DB.GetFlowAgentLogsAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logs, pageInfo := logsPageRows(page, logs, func(log database.Agentlog) int64 { return log.ID })
+	edges := make([]*model.AgentLogEdge, 0, len(logs))
+	for _, log := range logs {
+		edges = append(edges, &model.AgentLogEdge{
+			Cursor: encodeLogCursor(log.ID),
+			Node:   converter.ConvertAgentLog(log),
+		})
+	}
+
+	return &model.AgentLogConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// SearchLogsConnection is the resolver for the searchLogsConnection field.
+func (r *queryResolver) SearchLogsConnection(ctx context.Context, flowID int64, first *int, after *string, last *int, before *string, filter *model.SearchLogFilter, order *model.LogOrder) (*model.SearchLogConnection, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "searchlogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get search logs page")
+
+	page, err := newLogsPage(first, after, last, before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &model.SearchLogFilter{}
+	}
+
+	params := database.GetFlowSearchLogsAscParams{
+		FlowID:        flowID,
+		TaskID:        database.Int64ToNullInt64(filter.TaskID),
+		SubtaskID:     database.Int64ToNullInt64(filter.SubtaskID),
+		Engines:       logTypes(filter.Engines),
+		CreatedAfter:  logTime(filter.CreatedAfter),
+		CreatedBefore: logTime(filter.CreatedBefore),
+		AfterID:       page.afterID,
+		BeforeID:      page.beforeID,
+		RowLimit:      page.rowLimit(),
+	}
+
+	var logs []database.Searchlog
+	if page.desc {
+		logs, err = r.DB.GetFlowSearchLogsDesc(ctx, database.GetFlowSearchLogsDescParams(params))
+	} else {
+		logs, err = r.DB.GetFlowSearchLogsAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logs, pageInfo := logsPageRows(page, logs, func(log database.Searchlog) int64 { return log.ID })
+	edges := make([]*model.SearchLogEdge, 0, len(logs))
+	for _, log := range logs {
+		edges = append(edges, &model.SearchLogEdge{
+			Cursor: encodeLogCursor(log.ID),
+			Node:   converter.ConvertSearchLog(log),
+		})
+	}
+
+	return &model.SearchLogConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// VectorStoreLogsConnection is the resolver for the vectorStoreLogsConnection field.
+func (r *queryResolver) VectorStoreLogsConnection(ctx context.Context, flowID int64, first *int, after *string, last *int, before *string, filter *model.VectorStoreLogFilter, order *model.LogOrder) (*model.VectorStoreLogConnection, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "vecstorelogs.view", flowID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":  uid,
+		"flow": flowID,
+	}).Debug("get vector store logs page")
+
+	page, err := newLogsPage(first, after, last, before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &model.VectorStoreLogFilter{}
+	}
+
+	params := database.GetFlowVectorStoreLogsAscParams{
+		FlowID:        flowID,
+		TaskID:        database.Int64ToNullInt64(filter.TaskID),
+		SubtaskID:     database.Int64ToNullInt64(filter.SubtaskID),
+		Actions:       logTypes(filter.Actions),
+		CreatedAfter:  logTime(filter.CreatedAfter),
+		CreatedBefore: logTime(filter.CreatedBefore),
+		AfterID:       page.afterID,
+		BeforeID:      page.beforeID,
+		RowLimit:      page.rowLimit(),
+	}
+
+	var logs []database.Vecstorelog
+	if page.desc {
+		logs, err = r.DB.GetFlowVectorStoreLogsDesc(ctx, database.GetFlowVectorStoreLogsDescParams(params))
+	} else {
+		logs, err = r.DB.GetFlowVectorStoreLogsAsc(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logs, pageInfo := logsPageRows(page, logs, func(log database.Vecstorelog) int64 { return log.ID })
+	edges := make([]*model.VectorStoreLogEdge, 0, len(logs))
+	for _, log := range logs {
+		edges = append(edges, &model.VectorStoreLogEdge{
+			Cursor: encodeLogCursor(log.ID),
+			Node:   converter.ConvertVectorStoreLog(log),
+		})
+	}
+
+	return &model.VectorStoreLogConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, scope []model.SearchScope, filters *model.SearchFilter, limit *int) ([]*model.SearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+
+	rowLimit, err := searchLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scope) == 0 {
+		scope = model.AllSearchScope
+	}
+	if filters == nil {
+		filters = &model.SearchFilter{}
+	}
+
+	var (
+		results   []*model.SearchResult
+		permitted bool
+		searched  = make(map[model.SearchScope]struct{}, len(scope))
+	)
+
+	// scopes without the view privilege are skipped, the admin privilege allows to search in all flows
+	for _, s := range scope {
+		if _, ok := searched[s]; ok {
+			continue
+		}
+		searched[s] = struct{}{}
+
+		uid, admin, err := validatePermission(ctx, searchPrivileges[s])
+		if err != nil {
+			continue
+		}
+		permitted = true
+
+		r.Logger.WithFields(logrus.Fields{
+			"uid":   uid,
+			"scope": s,
+		}).Debug("search")
+
+		scopeResults, err := r.searchScope(ctx, s, database.SearchFlowsParams{
+			Query:           query,
+			HeadlineOptions: searchHeadlineOptions,
+			Admin:           admin,
+			UserID:          uid,
+			FlowID:          database.Int64ToNullInt64(filters.FlowID),
+			CreatedAfter:    logTime(filters.CreatedAfter),
+			CreatedBefore:   logTime(filters.CreatedBefore),
+			RowLimit:        int32(rowLimit),
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, scopeResults...)
+	}
+
+	if !permitted {
+		return nil, fmt.Errorf("not permitted")
+	}
+
+	slices.SortStableFunc(results, func(a, b *model.SearchResult) int {
+		return cmp.Compare(b.Rank, a.Rank)
+	})
+	if len(results) > rowLimit {
+		results = results[:rowLimit]
+	}
+
+	return results, nil
+}
+
+// Settings is the resolver for the settings field.
+func (r *queryResolver) Settings(ctx context.Context) (*model.Settings, error) {
+	_, _, err := validatePermission(ctx, "settings.view")
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &model.Settings{
+		Debug:              r.Config.Debug,
+		AskUser:            r.Config.AskUser,
+		DockerInside:       r.Config.DockerInside,
+		AssistantUseAgents: r.Config.AssistantUseAgents,
+	}
+
+	return settings, nil
+}
+
+// SettingsProviders is the resolver for the settingsProviders field.
+func (r *queryResolver) SettingsProviders(ctx context.Context) (*model.ProvidersConfig, error) {
+	uid, _, err := validatePermission(ctx, "settings.providers.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get providers")
+
+	config := model.ProvidersConfig{
+		Enabled:     &model.ProvidersReadinessStatus{},
+		Default:     &model.DefaultProvidersConfig{},
+		Models:      &model.ProvidersModelsList{},
+		UserDefined: make([]*model.ProviderConfig, 0),
+	}
+
+	now := time.Now()
+	defaultProvidersConfig := r.ProvidersCtrl.DefaultProvidersConfig()
+	for prvtype, pcfg := range defaultProvidersConfig {
+		mpcfg := &model.ProviderConfig{
+			Name:      string(prvtype),
+			Type:      model.ProviderType(prvtype),
+			Agents:    converter.ConvertProviderConfigToGqlModel(pcfg),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		switch prvtype {
+		case provider.ProviderOpenAI:
+			config.Default.Openai = mpcfg
+			if models, err := openai.DefaultModels(); err == nil {
+				config.Models.Openai = converter.ConvertModels(models)
+			}
+		case provider.ProviderAnthropic:
+			config.Default.Anthropic = mpcfg
+			if models, err := anthropic.DefaultModels(); err == nil {
+				config.Models.Anthropic = converter.ConvertModels(models)
+			}
+		case provider.ProviderGemini:
+			config.Default.Gemini = mpcfg
+			if models, err := gemini.DefaultModels(); err == nil {
+				config.Models.Gemini = converter.ConvertModels(models)
+			}
+		case provider.ProviderBedrock:
+			config.Default.Bedrock = mpcfg
+			if models, err := bedrock.DefaultModels(); err == nil {
+				config.Models.Bedrock = converter.ConvertModels(models)
+			}
+		case provider.ProviderOllama:
+			config.Default.Ollama = mpcfg
+		case provider.ProviderCustom:
+			config.Default.Custom = mpcfg
+		case provider.ProviderReplay:
+			config.Default.Replay = mpcfg
+		case provider.ProviderMock:
+			config.Default.Mock = mpcfg
+		}
+	}
+
+	defaultProviders := r.ProvidersCtrl.DefaultProviders()
+	for _, prvtype := range defaultProviders.ListTypes() {
+		switch prvtype {
+		case provider.ProviderOpenAI:
+			config.Enabled.Openai = true
+		case provider.ProviderAnthropic:
+			config.Enabled.Anthropic = true
+		case provider.ProviderGemini:
+			config.Enabled.Gemini = true
+		case provider.ProviderBedrock:
+			config.Enabled.Bedrock = true
+		case provider.ProviderOllama:
+			config.Enabled.Ollama = true
+			if p, ok := defaultProviders[provider.DefaultProviderNameOllama]; ok {
+				config.Models.Ollama = converter.ConvertModels(p.GetModels())
+			}
+		case provider.ProviderCustom:
+			config.Enabled.Custom = true
+			if p, ok := defaultProviders[provider.DefaultProviderNameCustom]; ok {
+				config.Models.Custom = converter.ConvertModels(p.GetModels())
+			}
+		case provider.ProviderReplay:
+			config.Enabled.Replay = true
+		case provider.ProviderMock:
+			config.Enabled.Mock = true
+		}
+	}
+
+	providers, err := r.DB.GetUserProviders(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user providers: %w", err)
+	}
+
+	for _, prv := range providers {
+		var cfg pconfig.ProviderConfig
+
+		if len(prv.Config) == 0 {
+			prv.Config = []byte(pconfig.EmptyProviderConfigRaw)
+		}
+		if err := json.Unmarshal(prv.Config, &cfg); err != nil {
+			r.Logger.WithError(err).Errorf("failed to unmarshal provider config: %s", prv.Config)
+			continue
+		}
+
+		config.UserDefined = append(config.UserDefined, converter.ConvertProvider(prv, &cfg))
+	}
+
+	return &config, nil
+}
+
+// SettingsPrompts is the resolver for the settingsPrompts field.
+func (r *queryResolver) SettingsPrompts(ctx context.Context) (*model.PromptsConfig, error) {
+	uid, _, err := validatePermission(ctx, "settings.prompts.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get prompts")
+
+	prompts, err := r.DB.GetUserPrompts(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultPrompts, err := templates.GetDefaultPrompts()
+	if err != nil {
+		return nil, err
+	}
+
+	promptsConfig := model.PromptsConfig{
+		Default:     converter.ConvertDefaultPrompts(defaultPrompts),
+		UserDefined: make([]*model.UserPrompt, 0, len(prompts)),
+	}
+
+	for _, prompt := range prompts {
+		promptsConfig.UserDefined = append(promptsConfig.UserDefined, converter.ConvertPrompt(prompt))
+	}
+
+	return &promptsConfig, nil
+}
+
+// Playbooks is the resolver for the playbooks field.
+func (r *queryResolver) Playbooks(ctx context.Context) ([]*model.Playbook, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get playbooks")
+
+	playbooks, err := r.DB.GetUserPlaybooks(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertPlaybooks(playbooks), nil
+}
+
+// Playbook is the resolver for the playbook field.
+func (r *queryResolver) Playbook(ctx context.Context, playbookID int64) (*model.Playbook, error) {
+	uid, _, err := validatePermission(ctx, "playbooks.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"playbook": playbookID,
+	}).Debug("get playbook")
+
+	pb, err := r.DB.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     playbookID,
+		UserID: uid,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertPlaybook(pb), nil
+}
+
+// Schedules is the resolver for the schedules field.
+func (r *queryResolver) Schedules(ctx context.Context) ([]*model.Schedule, error) {
+	uid, admin, err := validatePermission(ctx, "schedules.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get schedules")
+
+	var schedules []database.Schedule
+	if admin {
+		schedules, err = r.DB.GetSchedules(ctx)
+	} else {
+		schedules, err = r.DB.GetUserSchedules(ctx, uid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertSchedules(schedules), nil
+}
+
+// Schedule is the resolver for the schedule field.
+func (r *queryResolver) Schedule(ctx context.Context, scheduleID int64) (*model.Schedule, error) {
+	uid, schedule, err := validatePermissionWithScheduleID(ctx, "schedules.view", scheduleID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"schedule": scheduleID,
+	}).Debug("get schedule")
+
+	return converter.ConvertSchedule(schedule), nil
+}
+
+// ScheduleRuns is the resolver for the scheduleRuns field.
+func (r *queryResolver) ScheduleRuns(ctx context.Context, scheduleID int64) ([]*model.ScheduleRun, error) {
+	uid, _, err := validatePermissionWithScheduleID(ctx, "schedules.view", scheduleID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"schedule": scheduleID,
+	}).Debug("get schedule runs")
+
+	runs, err := r.DB.GetScheduleRuns(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertScheduleRuns(runs), nil
+}
+
+// Campaigns is the resolver for the campaigns field.
+func (r *queryResolver) Campaigns(ctx context.Context) ([]*model.Campaign, error) {
+	uid, admin, err := validatePermission(ctx, "campaigns.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get campaigns")
+
+	var campaigns []database.Campaign
+	if admin {
+		campaigns, err = r.DB.GetCampaigns(ctx)
+	} else {
+		campaigns, err = r.DB.GetUserCampaigns(ctx, uid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertCampaigns(campaigns), nil
+}
+
+// Campaign is the resolver for the campaign field.
+func (r *queryResolver) Campaign(ctx context.Context, campaignID int64) (*model.Campaign, error) {
+	uid, campaign, err := validatePermissionWithCampaignID(ctx, "campaigns.view", campaignID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("get campaign")
+
+	return converter.ConvertCampaign(campaign), nil
+}
+
+// CampaignTargets is the resolver for the campaignTargets field.
+func (r *queryResolver) CampaignTargets(ctx context.Context, campaignID int64) ([]*model.CampaignTarget, error) {
+	uid, _, err := validatePermissionWithCampaignID(ctx, "campaigns.view", campaignID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("get campaign targets")
+
+	targets, err := r.DB.GetCampaignTargets(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := r.DB.GetCampaignFlows(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertCampaignTargets(targets, flows), nil
+}
+
+// CampaignProgress is the resolver for the campaignProgress field.
+func (r *queryResolver) CampaignProgress(ctx context.Context, campaignID int64) (*model.CampaignProgress, error) {
+	uid, campaign, err := validatePermissionWithCampaignID(ctx, "campaigns.view", campaignID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("get campaign progress")
+
+	return r.campaignProgress(ctx, campaign)
+}
+
+// CampaignFindings is the resolver for the campaignFindings field.
+func (r *queryResolver) CampaignFindings(ctx context.Context, campaignID int64) ([]*model.CampaignFinding, error) {
+	uid, _, err := validatePermissionWithCampaignID(ctx, "campaigns.view", campaignID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("get campaign findings")
+
+	findings, err := r.DB.GetCampaignFindings(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertCampaignFindings(findings), nil
+}
+
+// CampaignExport is the resolver for the campaignExport field.
+func (r *queryResolver) CampaignExport(ctx context.Context, campaignID int64) (string, error) {
+	uid, campaign, err := validatePermissionWithCampaignID(ctx, "campaigns.view", campaignID, r.DB)
+	if err != nil {
+		return "", err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":      uid,
+		"campaign": campaignID,
+	}).Debug("export campaign")
+
+	return r.campaignExport(ctx, campaign)
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	uid, admin, err := validatePermission(ctx, "webhooks.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get webhooks")
+
+	var webhooks []database.Webhook
+	if admin {
+		webhooks, err = r.DB.GetWebhooks(ctx)
+	} else {
+		webhooks, err = r.DB.GetUserWebhooks(ctx, database.Int64ToNullInt64(&uid))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertWebhooks(webhooks), nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID int64) ([]*model.WebhookDelivery, error) {
+	uid, _, err := validatePermissionWithWebhookID(ctx, "webhooks.view", webhookID, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid":     uid,
+		"webhook": webhookID,
+	}).Debug("get webhook deliveries")
+
+	deliveries, err := r.DB.GetWebhookDeliveries(ctx, database.GetWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Limit:     webhookDeliveriesLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertWebhookDeliveries(deliveries), nil
+}
+
+// APIKeys is the resolver for the apiKeys field.
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	uid, admin, err := validatePermission(ctx, "api_keys.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get api keys")
+
+	var keys []database.ApiKey
+	if admin {
+		keys, err = r.DB.GetApiKeys(ctx)
+	} else {
+		keys, err = r.DB.GetUserApiKeys(ctx, uid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertAPIKeys(keys), nil
+}
+
+// Roles is the resolver for the roles field.
+func (r *queryResolver) Roles(ctx context.Context) ([]*model.Role, error) {
+	uid, _, err := validatePermission(ctx, "roles.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get roles")
+
+	roles, err := r.DB.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertRoles(roles), nil
+}
+
+// KnownPrivileges is the resolver for the knownPrivileges field.
+func (r *queryResolver) KnownPrivileges(ctx context.Context) ([]string, error) {
+	uid, _, err := validatePermission(ctx, "roles.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get known privileges")
+
+	return models.Privileges, nil
+}
+
+// Teams is the resolver for the teams field.
+func (r *queryResolver) Teams(ctx context.Context) ([]*model.Team, error) {
+	uid, admin, err := validatePermission(ctx, "teams.view")
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.WithFields(logrus.Fields{
+		"uid": uid,
+	}).Debug("get teams")
+
+	var teams []database.Team
+	if admin {
+		teams, err = r.DB.GetTeams(ctx)
+	} else {
+		teams, err = r.DB.GetUserTeams(ctx, uid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gteams := make([]*model.Team, 0, len(teams))
+	for _, team := range teams {
+		members, err := r.DB.GetTeamMembers(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		gteams = append(gteams, converter.ConvertTeam(team, members))
+	}
+
+	return gteams, nil
 }
 
-// AgentLogs is the resolver for the agentLogs field.
-func (r *queryResolver) AgentLogs(ctx context.Context, flowID int64) ([]*model.AgentLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "agentlogs.view", flowID, r.DB)
+// FlowShares is the resolver for the flowShares field.
+func (r *queryResolver) FlowShares(ctx context.Context, flowID int64) ([]*model.FlowShare, error) {
+	uid, err := validatePermissionWithFlowID(ctx, "flow_shares.view", flowID, r.DB)
 	if err != nil {
 		return nil, err
 	}
@@ -838,231 +3372,164 @@ func (r *queryResolver) AgentLogs(ctx context.Context, flowID int64) ([]*model.A
 	r.Logger.WithFields(logrus.Fields{
 		"uid":  uid,
 		"flow": flowID,
-	}).Debug("get agent logs")
+	}).Debug("get flow shares")
 
-	logs, err := r.DB.GetFlowAgentLogs(ctx, flowID)
+	shares, err := r.DB.GetFlowShares(ctx, flowID)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertAgentLogs(logs), nil
+	return converter.ConvertFlowShares(shares), nil
 }
 
-// SearchLogs is the resolver for the searchLogs field.
-func (r *queryResolver) SearchLogs(ctx context.Context, flowID int64) ([]*model.SearchLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "searchlogs.view", flowID, r.DB)
+// FlowShareAccesses is the resolver for the flowShareAccesses field.
+func (r *queryResolver) FlowShareAccesses(ctx context.Context, shareID int64) ([]*model.FlowShareAccess, error) {
+	uid, err := validatePermissionWithFlowShareID(ctx, "flow_shares.view", shareID, r.DB)
 	if err != nil {
 		return nil, err
 	}
 
 	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get search logs")
+		"uid":   uid,
+		"share": shareID,
+	}).Debug("get flow share accesses")
 
-	logs, err := r.DB.GetFlowSearchLogs(ctx, flowID)
+	accesses, err := r.DB.GetFlowShareAccesses(ctx, shareID)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertSearchLogs(logs), nil
+	return converter.ConvertFlowShareAccesses(accesses), nil
 }
 
-// VectorStoreLogs is the resolver for the vectorStoreLogs field.
-func (r *queryResolver) VectorStoreLogs(ctx context.Context, flowID int64) ([]*model.VectorStoreLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "vecstorelogs.view", flowID, r.DB)
+// SharedFlow is the resolver for the sharedFlow field.
+func (r *queryResolver) SharedFlow(ctx context.Context) (*model.Flow, error) {
+	share, err := validateFlowShareAccess(ctx, "flow", r.DB, r.Logger)
 	if err != nil {
 		return nil, err
 	}
 
-	r.Logger.WithFields(logrus.Fields{
-		"uid":  uid,
-		"flow": flowID,
-	}).Debug("get vector store logs")
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
 
-	logs, err := r.DB.GetFlowVectorStoreLogs(ctx, flowID)
+	flow, err := r.DB.GetFlow(ctx, share.FlowID)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertVectorStoreLogs(logs), nil
+	// the terminals are omitted because they show the container images and ports of the flow
+	return redactFlow(redactor, converter.ConvertFlow(flow, nil)), nil
 }
 
-// AssistantLogs is the resolver for the assistantLogs field.
-func (r *queryResolver) AssistantLogs(ctx context.Context, flowID int64, assistantID int64) ([]*model.AssistantLog, error) {
-	uid, err := validatePermissionWithFlowID(ctx, "assistantlogs.view", flowID, r.DB)
+// SharedTasks is the resolver for the sharedTasks field.
+func (r *queryResolver) SharedTasks(ctx context.Context) ([]*model.Task, error) {
+	share, err := validateFlowShareAccess(ctx, "tasks", r.DB, r.Logger)
 	if err != nil {
 		return nil, err
 	}
 
-	r.Logger.WithFields(logrus.Fields{
-		"uid":       uid,
-		"flow":      flowID,
-		"assistant": assistantID,
-	}).Debug("get assistant logs")
-
-	logs, err := r.DB.GetFlowAssistantLogs(ctx, database.GetFlowAssistantLogsParams{
-		FlowID:      flowID,
-		AssistantID: assistantID,
-	})
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
 	if err != nil {
 		return nil, err
 	}
 
-	return converter.ConvertAssistantLogs(logs), nil
-}
+	tasks, err := r.DB.GetFlowTasks(ctx, share.FlowID)
+	if err != nil {
+		return nil, err
+	}
 
-// Settings is the resolver for the settings field.
-func (r *queryResolver) Settings(ctx context.Context) (*model.Settings, error) {
-	_, _, err := validatePermission(ctx, "settings.view")
+	subtasks, err := r.DB.GetFlowSubtasks(ctx, share.FlowID)
 	if err != nil {
 		return nil, err
 	}
 
-	settings := &model.Settings{
-		Debug:              r.Config.Debug,
-		AskUser:            r.Config.AskUser,
-		DockerInside:       r.Config.DockerInside,
-		AssistantUseAgents: r.Config.AssistantUseAgents,
+	gtasks := converter.ConvertTasks(tasks, subtasks)
+	for i, task := range gtasks {
+		gtasks[i] = redactTask(redactor, task)
 	}
 
-	return settings, nil
+	return gtasks, nil
 }
 
-// SettingsProviders is the resolver for the settingsProviders field.
-func (r *queryResolver) SettingsProviders(ctx context.Context) (*model.ProvidersConfig, error) {
-	uid, _, err := validatePermission(ctx, "settings.providers.view")
+// SharedScreenshots is the resolver for the sharedScreenshots field.
+func (r *queryResolver) SharedScreenshots(ctx context.Context) ([]*model.Screenshot, error) {
+	share, err := validateFlowShareAccess(ctx, "screenshots", r.DB, r.Logger)
 	if err != nil {
 		return nil, err
 	}
 
-	r.Logger.WithFields(logrus.Fields{
-		"uid": uid,
-	}).Debug("get providers")
-
-	config := model.ProvidersConfig{
-		Enabled:     &model.ProvidersReadinessStatus{},
-		Default:     &model.DefaultProvidersConfig{},
-		Models:      &model.ProvidersModelsList{},
-		UserDefined: make([]*model.ProviderConfig, 0),
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
 	}
 
-	now := time.Now()
-	defaultProvidersConfig := r.ProvidersCtrl.DefaultProvidersConfig()
-	for prvtype, pcfg := range defaultProvidersConfig {
-		mpcfg := &model.ProviderConfig{
-			Name:      string(prvtype),
-			Type:      model.ProviderType(prvtype),
-			Agents:    converter.ConvertProviderConfigToGqlModel(pcfg),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
+	screenshots, err := r.DB.GetFlowScreenshots(ctx, share.FlowID)
+	if err != nil {
+		return nil, err
+	}
 
-		switch prvtype {
-		case provider.ProviderOpenAI:
-			config.Default.Openai = mpcfg
-			if models, err := openai.DefaultModels(); err == nil {
-				config.Models.Openai = converter.ConvertModels(models)
-			}
-		case provider.ProviderAnthropic:
-			config.Default.Anthropic = mpcfg
-			if models, err := anthropic.DefaultModels(); err == nil {
-				config.Models.Anthropic = converter.ConvertModels(models)
-			}
-		case provider.ProviderGemini:
-			config.Default.Gemini = mpcfg
-			if models, err := gemini.DefaultModels(); err == nil {
-				config.Models.Gemini = converter.ConvertModels(models)
-			}
-		case provider.ProviderBedrock:
-			config.Default.Bedrock = mpcfg
-			if models, err := bedrock.DefaultModels(); err == nil {
-				config.Models.Bedrock = converter.ConvertModels(models)
-			}
-		case provider.ProviderOllama:
-			config.Default.Ollama = mpcfg
-		case provider.ProviderCustom:
-			config.Default.Custom = mpcfg
-		}
+	gscreenshots := converter.ConvertScreenshots(screenshots)
+	for i, screenshot := range gscreenshots {
+		gscreenshots[i] = redactScreenshot(redactor, screenshot)
 	}
 
-	defaultProviders := r.ProvidersCtrl.DefaultProviders()
-	for _, prvtype := range defaultProviders.ListTypes() {
-		switch prvtype {
-		case provider.ProviderOpenAI:
-			config.Enabled.Openai = true
-		case provider.ProviderAnthropic:
-			config.Enabled.Anthropic = true
-		case provider.ProviderGemini:
-			config.Enabled.Gemini = true
-		case provider.ProviderBedrock:
-			config.Enabled.Bedrock = true
-		case provider.ProviderOllama:
-			config.Enabled.Ollama = true
-			if p, ok := defaultProviders[provider.DefaultProviderNameOllama]; ok {
-				config.Models.Ollama = converter.ConvertModels(p.GetModels())
-			}
-		case provider.ProviderCustom:
-			config.Enabled.Custom = true
-			if p, ok := defaultProviders[provider.DefaultProviderNameCustom]; ok {
-				config.Models.Custom = converter.ConvertModels(p.GetModels())
-			}
-		}
+	return gscreenshots, nil
+}
+
+// SharedTerminalLogs is the resolver for the sharedTerminalLogs field.
+func (r *queryResolver) SharedTerminalLogs(ctx context.Context) ([]*model.TerminalLog, error) {
+	share, err := validateFlowShareAccess(ctx, "termlogs", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
 	}
 
-	providers, err := r.DB.GetUserProviders(ctx, uid)
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user providers: %w", err)
+		return nil, err
 	}
 
-	for _, prv := range providers {
-		var cfg pconfig.ProviderConfig
+	if !share.WithTerminalLogs {
+		return nil, fmt.Errorf("terminal logs are not shared by the link")
+	}
 
-		if len(prv.Config) == 0 {
-			prv.Config = []byte(pconfig.EmptyProviderConfigRaw)
-		}
-		if err := json.Unmarshal(prv.Config, &cfg); err != nil {
-			r.Logger.WithError(err).Errorf("failed to unmarshal provider config: %s", prv.Config)
-			continue
-		}
+	logs, err := r.DB.GetFlowTermLogs(ctx, share.FlowID)
+	if err != nil {
+		return nil, err
+	}
 
-		config.UserDefined = append(config.UserDefined, converter.ConvertProvider(prv, &cfg))
+	glogs := converter.ConvertTerminalLogs(logs, share.FlowID)
+	for i, log := range glogs {
+		glogs[i] = redactTerminalLog(redactor, log)
 	}
 
-	return &config, nil
+	return glogs, nil
 }
 
-// SettingsPrompts is the resolver for the settingsPrompts field.
-func (r *queryResolver) SettingsPrompts(ctx context.Context) (*model.PromptsConfig, error) {
-	uid, _, err := validatePermission(ctx, "settings.prompts.view")
+// SharedMessageLogs is the resolver for the sharedMessageLogs field.
+func (r *queryResolver) SharedMessageLogs(ctx context.Context) ([]*model.MessageLog, error) {
+	share, err := validateFlowShareAccess(ctx, "msglogs", r.DB, r.Logger)
 	if err != nil {
 		return nil, err
 	}
 
-	r.Logger.WithFields(logrus.Fields{
-		"uid": uid,
-	}).Debug("get prompts")
-
-	prompts, err := r.DB.GetUserPrompts(ctx, uid)
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
 	if err != nil {
 		return nil, err
 	}
 
-	defaultPrompts, err := templates.GetDefaultPrompts()
+	logs, err := r.DB.GetFlowMsgLogs(ctx, share.FlowID)
 	if err != nil {
 		return nil, err
 	}
 
-	promptsConfig := model.PromptsConfig{
-		Default:     converter.ConvertDefaultPrompts(defaultPrompts),
-		UserDefined: make([]*model.UserPrompt, 0, len(prompts)),
-	}
-
-	for _, prompt := range prompts {
-		promptsConfig.UserDefined = append(promptsConfig.UserDefined, converter.ConvertPrompt(prompt))
+	glogs := converter.ConvertMessageLogs(logs)
+	for i, log := range glogs {
+		glogs[i] = redactMessageLog(redactor, log)
 	}
 
-	return &promptsConfig, nil
+	return glogs, nil
 }
 
 // FlowCreated is the resolver for the flowCreated field.
@@ -1280,6 +3747,148 @@ func (r *subscriptionResolver) ProviderDeleted(ctx context.Context) (<-chan *mod
 	return r.Subscriptions.NewFlowSubscriber(uid, 0).ProviderDeleted(ctx)
 }
 
+// SharedFlowUpdated is the resolver for the sharedFlowUpdated field.
+func (r *subscriptionResolver) SharedFlowUpdated(ctx context.Context) (<-chan *model.Flow, error) {
+	share, err := validateFlowShareAccess(ctx, "flow/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := r.DB.GetFlow(ctx, share.FlowID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the flow owner receives the updates of all own flows, so the other flows are filtered out
+	subscriber := r.Subscriptions.NewFlowSubscriber(flow.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.FlowUpdated, func(flow *model.Flow) (*model.Flow, bool) {
+		if flow.ID != share.FlowID {
+			return nil, false
+		}
+
+		flow = redactFlow(redactor, flow)
+		flow.Terminals = nil
+		return flow, true
+	})
+}
+
+// SharedTaskCreated is the resolver for the sharedTaskCreated field.
+func (r *subscriptionResolver) SharedTaskCreated(ctx context.Context) (<-chan *model.Task, error) {
+	share, err := validateFlowShareAccess(ctx, "tasks/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.TaskCreated, func(task *model.Task) (*model.Task, bool) {
+		return redactTask(redactor, task), true
+	})
+}
+
+// SharedTaskUpdated is the resolver for the sharedTaskUpdated field.
+func (r *subscriptionResolver) SharedTaskUpdated(ctx context.Context) (<-chan *model.Task, error) {
+	share, err := validateFlowShareAccess(ctx, "tasks/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.TaskUpdated, func(task *model.Task) (*model.Task, bool) {
+		return redactTask(redactor, task), true
+	})
+}
+
+// SharedScreenshotAdded is the resolver for the sharedScreenshotAdded field.
+func (r *subscriptionResolver) SharedScreenshotAdded(ctx context.Context) (<-chan *model.Screenshot, error) {
+	share, err := validateFlowShareAccess(ctx, "screenshots/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.ScreenshotAdded, func(s *model.Screenshot) (*model.Screenshot, bool) {
+		return redactScreenshot(redactor, s), true
+	})
+}
+
+// SharedTerminalLogAdded is the resolver for the sharedTerminalLogAdded field.
+func (r *subscriptionResolver) SharedTerminalLogAdded(ctx context.Context) (<-chan *model.TerminalLog, error) {
+	share, err := validateFlowShareAccess(ctx, "termlogs/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	if !share.WithTerminalLogs {
+		return nil, fmt.Errorf("terminal logs are not shared by the link")
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.TerminalLogAdded, func(log *model.TerminalLog) (*model.TerminalLog, bool) {
+		return redactTerminalLog(redactor, log), true
+	})
+}
+
+// SharedMessageLogAdded is the resolver for the sharedMessageLogAdded field.
+func (r *subscriptionResolver) SharedMessageLogAdded(ctx context.Context) (<-chan *model.MessageLog, error) {
+	share, err := validateFlowShareAccess(ctx, "msglogs/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.MessageLogAdded, func(log *model.MessageLog) (*model.MessageLog, bool) {
+		return redactMessageLog(redactor, log), true
+	})
+}
+
+// SharedMessageLogUpdated is the resolver for the sharedMessageLogUpdated field.
+func (r *subscriptionResolver) SharedMessageLogUpdated(ctx context.Context) (<-chan *model.MessageLog, error) {
+	share, err := validateFlowShareAccess(ctx, "msglogs/subscribe", r.DB, r.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := shareRedactor(ctx, r.DB, r.Redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := r.Subscriptions.NewFlowSubscriber(share.UserID, share.FlowID)
+	return subscribeFlowShare(ctx, r.DB, share, subscriber.MessageLogUpdated, func(log *model.MessageLog) (*model.MessageLog, bool) {
+		return redactMessageLog(redactor, log), true
+	})
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
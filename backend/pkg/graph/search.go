@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions for the full-text search over the flows content.
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+
+	// private use characters delimit the matches in the headline, so the snippet can be escaped after it
+	searchMarkStart = "\ue000"
+	searchMarkStop  = "\ue001"
+)
+
+var searchHeadlineOptions = fmt.Sprintf(
+	`StartSel=%s, StopSel=%s, MinWords=15, MaxWords=35, MaxFragments=3, FragmentDelimiter=" ... "`,
+	searchMarkStart, searchMarkStop,
+)
+
+// searchPrivileges are the view privileges which are required to search in the scope
+var searchPrivileges = map[model.SearchScope]string{
+	model.SearchScopeFlow:        "flows.view",
+	model.SearchScopeTask:        "tasks.view",
+	model.SearchScopeSubtask:     "subtasks.view",
+	model.SearchScopeMessageLog:  "msglogs.view",
+	model.SearchScopeTerminalLog: "termlogs.view",
+	model.SearchScopeSearchLog:   "searchlogs.view",
+}
+
+func searchLimit(limit *int) (int, error) {
+	if limit == nil {
+		return defaultSearchLimit, nil
+	}
+	if *limit < 1 || *limit > maxSearchLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxSearchLimit)
+	}
+
+	return *limit, nil
+}
+
+// searchSnippet escapes the headline and replaces the match delimiters by the mark tags
+func searchSnippet(headline string) string {
+	return strings.NewReplacer(
+		searchMarkStart, "<mark>",
+		searchMarkStop, "</mark>",
+	).Replace(html.EscapeString(headline))
+}
+
+// searchScope runs the search in the scope, all scopes share the same query parameters
+func (r *Resolver) searchScope(
+	ctx context.Context,
+	scope model.SearchScope,
+	params database.SearchFlowsParams,
+) ([]*model.SearchResult, error) {
+	var results []*model.SearchResult
+
+	switch scope {
+	case model.SearchScopeFlow:
+		rows, err := r.DB.SearchFlows(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.ID,
+				FlowTitle: row.Title,
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	case model.SearchScopeTask:
+		rows, err := r.DB.SearchTasks(ctx, database.SearchTasksParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.FlowID,
+				FlowTitle: row.FlowTitle,
+				TaskID:    &row.ID,
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	case model.SearchScopeSubtask:
+		rows, err := r.DB.SearchSubtasks(ctx, database.SearchSubtasksParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.FlowID,
+				FlowTitle: row.FlowTitle,
+				TaskID:    &row.TaskID,
+				SubtaskID: &row.ID,
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	case model.SearchScopeMessageLog:
+		rows, err := r.DB.SearchMsgLogs(ctx, database.SearchMsgLogsParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.FlowID,
+				FlowTitle: row.FlowTitle,
+				TaskID:    database.NullInt64ToInt64(row.TaskID),
+				SubtaskID: database.NullInt64ToInt64(row.SubtaskID),
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	case model.SearchScopeTerminalLog:
+		rows, err := r.DB.SearchTermLogs(ctx, database.SearchTermLogsParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.FlowID,
+				FlowTitle: row.FlowTitle,
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	case model.SearchScopeSearchLog:
+		rows, err := r.DB.SearchSearchLogs(ctx, database.SearchSearchLogsParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, &model.SearchResult{
+				Scope:     scope,
+				ID:        row.ID,
+				FlowID:    row.FlowID,
+				FlowTitle: row.FlowTitle,
+				TaskID:    database.NullInt64ToInt64(row.TaskID),
+				SubtaskID: database.NullInt64ToInt64(row.SubtaskID),
+				Snippet:   searchSnippet(row.Snippet),
+				Rank:      float64(row.Rank),
+				CreatedAt: row.CreatedAt.Time,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unknown search scope: %s", scope)
+	}
+
+	return results, nil
+}
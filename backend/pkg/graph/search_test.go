@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchQuerier returns one row for every scope and records the scopes with their parameters
+type searchQuerier struct {
+	database.Querier
+	calls map[model.SearchScope]database.SearchFlowsParams
+}
+
+func (q *searchQuerier) record(scope model.SearchScope, params database.SearchFlowsParams) {
+	q.calls[scope] = params
+}
+
+func (q *searchQuerier) SearchFlows(_ context.Context, arg database.SearchFlowsParams) ([]database.SearchFlowsRow, error) {
+	q.record(model.SearchScopeFlow, arg)
+	return []database.SearchFlowsRow{{ID: 1, Title: "flow", Snippet: "flow", Rank: 0.6}}, nil
+}
+
+func (q *searchQuerier) SearchTasks(_ context.Context, arg database.SearchTasksParams) ([]database.SearchTasksRow, error) {
+	q.record(model.SearchScopeTask, database.SearchFlowsParams(arg))
+	return []database.SearchTasksRow{{ID: 2, FlowID: 1, Snippet: "task", Rank: 0.5}}, nil
+}
+
+func (q *searchQuerier) SearchSubtasks(
+	_ context.Context,
+	arg database.SearchSubtasksParams,
+) ([]database.SearchSubtasksRow, error) {
+	q.record(model.SearchScopeSubtask, database.SearchFlowsParams(arg))
+	return []database.SearchSubtasksRow{{ID: 3, TaskID: 2, FlowID: 1, Snippet: "subtask", Rank: 0.4}}, nil
+}
+
+func (q *searchQuerier) SearchMsgLogs(_ context.Context, arg database.SearchMsgLogsParams) ([]database.SearchMsgLogsRow, error) {
+	q.record(model.SearchScopeMessageLog, database.SearchFlowsParams(arg))
+	return []database.SearchMsgLogsRow{{ID: 4, FlowID: 1, Snippet: "message", Rank: 0.3}}, nil
+}
+
+func (q *searchQuerier) SearchTermLogs(
+	_ context.Context,
+	arg database.SearchTermLogsParams,
+) ([]database.SearchTermLogsRow, error) {
+	q.record(model.SearchScopeTerminalLog, database.SearchFlowsParams(arg))
+	return []database.SearchTermLogsRow{{ID: 5, FlowID: 1, Snippet: "terminal", Rank: 0.2}}, nil
+}
+
+func (q *searchQuerier) SearchSearchLogs(
+	_ context.Context,
+	arg database.SearchSearchLogsParams,
+) ([]database.SearchSearchLogsRow, error) {
+	q.record(model.SearchScopeSearchLog, database.SearchFlowsParams(arg))
+	return []database.SearchSearchLogsRow{{ID: 6, FlowID: 1, Snippet: "search", Rank: 0.1}}, nil
+}
+
+func TestSearchScopePrivileges(t *testing.T) {
+	type testCase struct {
+		name      string
+		privs     []string
+		scope     []model.SearchScope
+		want      []model.SearchScope
+		wantAdmin bool
+		wantErr   bool
+	}
+
+	tests := []testCase{
+		{
+			name:  "all scopes with one privilege",
+			privs: []string{"flows.view"},
+			want:  []model.SearchScope{model.SearchScopeFlow},
+		},
+		{
+			name:  "requested scopes without privilege are skipped",
+			privs: []string{"msglogs.view", "termlogs.view"},
+			scope: []model.SearchScope{model.SearchScopeFlow, model.SearchScopeMessageLog},
+			want:  []model.SearchScope{model.SearchScopeMessageLog},
+		},
+		{
+			name:  "duplicated scopes are searched once",
+			privs: []string{"tasks.view"},
+			scope: []model.SearchScope{model.SearchScopeTask, model.SearchScopeTask},
+			want:  []model.SearchScope{model.SearchScopeTask},
+		},
+		{
+			name:      "admin privilege searches in all flows",
+			privs:     []string{"subtasks.admin"},
+			scope:     []model.SearchScope{model.SearchScopeSubtask},
+			want:      []model.SearchScope{model.SearchScopeSubtask},
+			wantAdmin: true,
+		},
+		{
+			name:    "no privilege for requested scopes",
+			privs:   []string{"flows.view"},
+			scope:   []model.SearchScope{model.SearchScopeTerminalLog, model.SearchScopeSearchLog},
+			wantErr: true,
+		},
+		{
+			name:    "no privileges",
+			wantErr: true,
+		},
+	}
+
+	// every scope is searched with its own view privilege only
+	for _, scope := range model.AllSearchScope {
+		tests = append(tests, testCase{
+			name:  "only " + scope.String(),
+			privs: []string{searchPrivileges[scope]},
+			want:  []model.SearchScope{scope},
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &searchQuerier{calls: make(map[model.SearchScope]database.SearchFlowsParams)}
+			r := &queryResolver{&Resolver{DB: db, Logger: logrus.NewEntry(logrus.New())}}
+			ctx := SetUserPermissions(SetUserID(context.Background(), 7), tt.privs)
+
+			results, err := r.Search(ctx, "nmap", tt.scope, nil, nil)
+			if tt.wantErr {
+				assert.EqualError(t, err, "not permitted")
+				assert.Empty(t, db.calls)
+				return
+			}
+			require.NoError(t, err)
+
+			scopes := make([]model.SearchScope, 0, len(results))
+			for _, result := range results {
+				scopes = append(scopes, result.Scope)
+			}
+			assert.ElementsMatch(t, tt.want, scopes)
+			assert.Len(t, db.calls, len(tt.want))
+
+			for scope, params := range db.calls {
+				assert.Equal(t, int64(7), params.UserID, scope)
+				assert.Equal(t, tt.wantAdmin, params.Admin, scope)
+				assert.Equal(t, "nmap", params.Query, scope)
+				assert.Equal(t, searchHeadlineOptions, params.HeadlineOptions, scope)
+				assert.Equal(t, int32(defaultSearchLimit), params.RowLimit, scope)
+			}
+		})
+	}
+}
+
+func TestSearchPrivilegesCoverAllScopes(t *testing.T) {
+	for _, scope := range model.AllSearchScope {
+		assert.NotEmpty(t, searchPrivileges[scope], scope)
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	mark := func(s string) string {
+		return searchMarkStart + s + searchMarkStop
+	}
+
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{"plain text", "nmap scan finished", "nmap scan finished"},
+		{"match", "run " + mark("nmap") + " -sV", "run <mark>nmap</mark> -sV"},
+		{"several matches", mark("a") + " ... " + mark("b"), "<mark>a</mark> ... <mark>b</mark>"},
+		{"html is escaped", `<script>alert("x")</script>`, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;"},
+		{"match inside html", "<b>" + mark("admin") + "</b>", "&lt;b&gt;<mark>admin</mark>&lt;/b&gt;"},
+		{"mark tags from data are escaped", "<mark>fake</mark> & " + mark("real"),
+			"&lt;mark&gt;fake&lt;/mark&gt; &amp; <mark>real</mark>"},
+		{"ampersand entity", "a &amp; b", "a &amp;amp; b"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchSnippet(tt.headline))
+		})
+	}
+}
+
+func TestSearchHeadlineOptionsUsePrivateMarks(t *testing.T) {
+	assert.Contains(t, searchHeadlineOptions, "StartSel="+searchMarkStart)
+	assert.Contains(t, searchHeadlineOptions, "StopSel="+searchMarkStop)
+}
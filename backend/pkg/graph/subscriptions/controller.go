@@ -20,6 +20,9 @@ type SubscriptionsController interface {
 	NewFlowPublisher(userID, flowID int64) FlowPublisher
 }
 
+// TeamMembers returns the users of the team to deliver them the events of the resources shared with the team
+type TeamMembers func(ctx context.Context, teamID int64) ([]int64, error)
+
 type FlowContext interface {
 	GetFlowID() int64
 	SetFlowID(flowID int64)
@@ -102,9 +105,12 @@ type controller struct {
 	providerCreated     Channel[*model.ProviderConfig]
 	providerUpdated     Channel[*model.ProviderConfig]
 	providerDeleted     Channel[*model.ProviderConfig]
+	members             TeamMembers
 }
 
-func NewSubscriptionsController() SubscriptionsController {
+// NewSubscriptionsController returns the controller which delivers the flow events to the subscribers,
+// the team members get the events of the team flows only if members is set
+func NewSubscriptionsController(members TeamMembers) SubscriptionsController {
 	return &controller{
 		flowCreatedAdmin:    NewChannel[*model.Flow](),
 		flowCreated:         NewChannel[*model.Flow](),
@@ -129,6 +135,7 @@ func NewSubscriptionsController() SubscriptionsController {
 		providerCreated:     NewChannel[*model.ProviderConfig](),
 		providerUpdated:     NewChannel[*model.ProviderConfig](),
 		providerDeleted:     NewChannel[*model.ProviderConfig](),
+		members:             members,
 	}
 }
 
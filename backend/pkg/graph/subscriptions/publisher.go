@@ -2,6 +2,8 @@ package subscriptions
 
 import (
 	"context"
+	"database/sql"
+	"slices"
 
 	"pentagi/pkg/database"
 	"pentagi/pkg/database/converter"
@@ -30,21 +32,49 @@ func (p *flowPublisher) SetUserID(userID int64) {
 	p.userID = userID
 }
 
+// recipients returns the user and the members of the team which the resource is shared with,
+// the team members are skipped if they can't be loaded because the event is still useful for the user
+func (p *flowPublisher) recipients(ctx context.Context, teamID sql.NullInt64) []int64 {
+	userIDs := []int64{p.userID}
+	if !teamID.Valid || p.ctrl.members == nil {
+		return userIDs
+	}
+
+	members, err := p.ctrl.members(ctx, teamID.Int64)
+	if err != nil {
+		return userIDs
+	}
+
+	for _, userID := range members {
+		if !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	return userIDs
+}
+
 func (p *flowPublisher) FlowCreated(ctx context.Context, flow database.Flow, terms []database.Container) {
 	flowModel := converter.ConvertFlow(flow, terms)
-	p.ctrl.flowCreated.Publish(ctx, p.userID, flowModel)
+	for _, userID := range p.recipients(ctx, flow.TeamID) {
+		p.ctrl.flowCreated.Publish(ctx, userID, flowModel)
+	}
 	p.ctrl.flowCreatedAdmin.Broadcast(ctx, flowModel)
 }
 
 func (p *flowPublisher) FlowDeleted(ctx context.Context, flow database.Flow, terms []database.Container) {
 	flowModel := converter.ConvertFlow(flow, terms)
-	p.ctrl.flowDeleted.Publish(ctx, p.userID, flowModel)
+	for _, userID := range p.recipients(ctx, flow.TeamID) {
+		p.ctrl.flowDeleted.Publish(ctx, userID, flowModel)
+	}
 	p.ctrl.flowDeletedAdmin.Broadcast(ctx, flowModel)
 }
 
 func (p *flowPublisher) FlowUpdated(ctx context.Context, flow database.Flow, terms []database.Container) {
 	flowModel := converter.ConvertFlow(flow, terms)
-	p.ctrl.flowUpdated.Publish(ctx, p.flowID, flowModel)
+	for _, userID := range p.recipients(ctx, flow.TeamID) {
+		p.ctrl.flowUpdated.Publish(ctx, userID, flowModel)
+	}
 	p.ctrl.flowUpdatedAdmin.Broadcast(ctx, flowModel)
 }
 
@@ -105,13 +135,22 @@ func (p *flowPublisher) AssistantLogUpdated(ctx context.Context, assistantLog da
 }
 
 func (p *flowPublisher) ProviderCreated(ctx context.Context, provider database.Provider, cfg *pconfig.ProviderConfig) {
-	p.ctrl.providerCreated.Publish(ctx, p.userID, converter.ConvertProvider(provider, cfg))
+	providerModel := converter.ConvertProvider(provider, cfg)
+	for _, userID := range p.recipients(ctx, provider.TeamID) {
+		p.ctrl.providerCreated.Publish(ctx, userID, providerModel)
+	}
 }
 
 func (p *flowPublisher) ProviderUpdated(ctx context.Context, provider database.Provider, cfg *pconfig.ProviderConfig) {
-	p.ctrl.providerUpdated.Publish(ctx, p.userID, converter.ConvertProvider(provider, cfg))
+	providerModel := converter.ConvertProvider(provider, cfg)
+	for _, userID := range p.recipients(ctx, provider.TeamID) {
+		p.ctrl.providerUpdated.Publish(ctx, userID, providerModel)
+	}
 }
 
 func (p *flowPublisher) ProviderDeleted(ctx context.Context, provider database.Provider, cfg *pconfig.ProviderConfig) {
-	p.ctrl.providerDeleted.Publish(ctx, p.userID, converter.ConvertProvider(provider, cfg))
+	providerModel := converter.ConvertProvider(provider, cfg)
+	for _, userID := range p.recipients(ctx, provider.TeamID) {
+		p.ctrl.providerDeleted.Publish(ctx, userID, providerModel)
+	}
 }
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/database/converter"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/server/models"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to check the team membership and to load the team with its members.
+
+// validatePermissionWithTeamID checks the privilege and that the user has the required role in the team,
+// the team admin privilege grants access to all teams
+func validatePermissionWithTeamID(
+	ctx context.Context,
+	perm string,
+	teamID int64,
+	required models.TeamRole,
+	db database.Querier,
+) (int64, error) {
+	uid, _, err := validatePermission(ctx, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := db.GetTeam(ctx, teamID); err != nil {
+		return 0, err
+	}
+
+	if privs, err := GetUserPermissions(ctx); err == nil && slices.Contains(privs, "teams.admin") {
+		return uid, nil
+	}
+
+	member, err := db.GetTeamMember(ctx, database.GetTeamMemberParams{
+		TeamID: teamID,
+		UserID: uid,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("not permitted")
+	} else if err != nil {
+		return 0, err
+	}
+
+	if !models.TeamRole(member.Role).Allows(required) {
+		return 0, fmt.Errorf("not permitted")
+	}
+
+	return uid, nil
+}
+
+// validateShareTeamID checks that the resource can be moved to the team by the current user,
+// null team id is always allowed because it returns the resource to the owner only
+func validateShareTeamID(ctx context.Context, db database.Querier, teamID *int64) (sql.NullInt64, error) {
+	if teamID == nil {
+		return sql.NullInt64{}, nil
+	}
+
+	if _, err := validatePermissionWithTeamID(ctx, "teams.view", *teamID, models.TeamRoleOperator, db); err != nil {
+		return sql.NullInt64{}, fmt.Errorf("can't share with the team %d: %w", *teamID, err)
+	}
+
+	return sql.NullInt64{Int64: *teamID, Valid: true}, nil
+}
+
+// validateTeamName checks the team name and that it isn't used by another team
+func validateTeamName(ctx context.Context, db database.Querier, name string, teamID int64) error {
+	if err := (models.Team{Name: name}).Valid(); err != nil {
+		return fmt.Errorf("invalid team name: %w", err)
+	}
+
+	if team, err := db.GetTeamByName(ctx, name); err == nil && team.ID != teamID {
+		return fmt.Errorf("team '%s' already exists", name)
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+// validateLastTeamAdmin checks that the team keeps at least one admin after the member role is changed or the member is removed
+func validateLastTeamAdmin(ctx context.Context, db database.Querier, teamID, userID int64) error {
+	members, err := db.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	admins, isAdmin := 0, false
+	for _, member := range members {
+		if member.Role != database.TeamRoleAdmin {
+			continue
+		}
+		if member.UserID == userID {
+			isAdmin = true
+		} else {
+			admins++
+		}
+	}
+
+	if isAdmin && admins == 0 {
+		return fmt.Errorf("team must have at least one admin")
+	}
+
+	return nil
+}
+
+// validateNewTeamMember lets only the users managers add the users to the team without their consent,
+// the other team admins can change roles of the existing members only
+func validateNewTeamMember(ctx context.Context, db database.Querier, teamID, userID int64) error {
+	_, err := db.GetTeamMember(ctx, database.GetTeamMemberParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	privs, err := GetUserPermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("unauthorized: invalid user permissions: %v", err)
+	}
+
+	if slices.Contains(privs, "teams.admin") || slices.Contains(privs, "users.edit") {
+		return nil
+	}
+
+	return fmt.Errorf("not permitted: adding new team members requires 'users.edit' permission")
+}
+
+func getTeam(ctx context.Context, db database.Querier, teamID int64) (*model.Team, error) {
+	team, err := db.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := db.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.ConvertTeam(team, members), nil
+}
+
+func teamDescription(team model.TeamInput) string {
+	if team.Description == nil {
+		return ""
+	}
+
+	return *team.Description
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"pentagi/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type teamMembersQuerier struct {
+	database.Querier
+	members []database.TeamMember
+}
+
+func (q *teamMembersQuerier) GetTeamMember(
+	_ context.Context,
+	arg database.GetTeamMemberParams,
+) (database.TeamMember, error) {
+	for _, member := range q.members {
+		if member.TeamID == arg.TeamID && member.UserID == arg.UserID {
+			return member, nil
+		}
+	}
+	return database.TeamMember{}, sql.ErrNoRows
+}
+
+func TestValidateNewTeamMember(t *testing.T) {
+	db := &teamMembersQuerier{members: []database.TeamMember{
+		{TeamID: 1, UserID: 2, Role: database.TeamRoleAdmin},
+		{TeamID: 1, UserID: 3, Role: database.TeamRoleViewer},
+	}}
+	teamAdmin := []string{"teams.view", "teams.edit"}
+
+	tests := []struct {
+		name    string
+		privs   []string
+		userID  int64
+		wantErr bool
+	}{
+		{"team admin changes member role", teamAdmin, 3, false},
+		{"team admin adds new member", teamAdmin, 4, true},
+		{"users manager adds new member", append(teamAdmin, "users.edit"), 4, false},
+		{"teams admin adds new member", []string{"teams.admin"}, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := SetUserPermissions(SetUserID(context.Background(), 2), tt.privs)
+			err := validateNewTeamMember(ctx, db, 1, tt.userID)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/model"
+)
+
+// This file will not be regenerated automatically.
+//
+// It contains helper functions to validate the webhook input.
+
+const (
+	webhookSecretLength = 32
+	// the delivery log is shown from the latest deliveries
+	webhookDeliveriesLimit = 100
+)
+
+// webhookParams validates the webhook input and returns the values for the webhook record,
+// the secret is kept if it's not set in the input and generated for the new webhook
+func webhookParams(webhook model.WebhookInput, secret string) (database.CreateWebhookParams, error) {
+	params := database.CreateWebhookParams{
+		Name:    webhook.Name,
+		Url:     webhook.URL,
+		Secret:  secret,
+		Events:  make([]string, 0, len(webhook.Events)),
+		Enabled: webhook.Enabled == nil || *webhook.Enabled,
+	}
+
+	if webhook.Name == "" {
+		return params, fmt.Errorf("webhook name is required")
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil {
+		return params, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return params, fmt.Errorf("webhook url must be an absolute http or https url")
+	}
+
+	for _, event := range webhook.Events {
+		params.Events = append(params.Events, string(event))
+	}
+
+	if webhook.Secret != nil && *webhook.Secret != "" {
+		params.Secret = *webhook.Secret
+	}
+	if params.Secret == "" {
+		buf := make([]byte, webhookSecretLength)
+		if _, err := rand.Read(buf); err != nil {
+			return params, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		params.Secret = hex.EncodeToString(buf)
+	}
+
+	return params, nil
+}
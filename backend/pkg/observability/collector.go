@@ -180,3 +180,64 @@ func startDumperMetricCollect(stats Dumper, meter otelmetric.Meter, attrs []attr
 
 	return nil
 }
+
+func startStateMetricCollect(stats StateDumper, meter otelmetric.Meter, attrs []attribute.KeyValue) error {
+	var (
+		lastState  State
+		lastUpdate time.Time
+		mx         sync.Mutex
+	)
+
+	// the state is read from the DB, so it's cached to not query it on each reader collection
+	getState := func(ctx context.Context) State {
+		mx.Lock()
+		defer mx.Unlock()
+
+		now := time.Now()
+		if now.Sub(lastUpdate) <= defCollectPeriod {
+			return lastState
+		}
+		state, err := stats.DumpState(ctx)
+		if err != nil {
+			logrus.WithContext(ctx).WithError(err).Errorf("failed to get state dump")
+			return lastState
+		}
+		lastState, lastUpdate = state, now
+		return lastState
+	}
+
+	flows, err := meter.Int64ObservableGauge(metricFlows,
+		otelmetric.WithDescription("Number of flows by status"))
+	if err != nil {
+		return fmt.Errorf("failed to create flows gauge: %w", err)
+	}
+	containers, err := meter.Int64ObservableGauge(metricContainers,
+		otelmetric.WithDescription("Number of flow containers by status"))
+	if err != nil {
+		return fmt.Errorf("failed to create containers gauge: %w", err)
+	}
+	queues, err := meter.Int64ObservableGauge(metricQueueDepth,
+		otelmetric.WithDescription("Number of items waiting in the queue"))
+	if err != nil {
+		return fmt.Errorf("failed to create queue depth gauge: %w", err)
+	}
+
+	observe := func(o otelmetric.Observer, gauge otelmetric.Int64Observable, key attribute.Key, values map[string]int64) {
+		for name, value := range values {
+			o.ObserveInt64(gauge, value, otelmetric.WithAttributes(attrs...), otelmetric.WithAttributes(key.String(name)))
+		}
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o otelmetric.Observer) error {
+		state := getState(ctx)
+		observe(o, flows, metricStatusKey, state.Flows)
+		observe(o, containers, metricStatusKey, state.Containers)
+		observe(o, queues, metricQueueKey, state.Queues)
+		return nil
+	}, flows, containers, queues)
+	if err != nil {
+		return fmt.Errorf("failed to register state metrics callback: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type stubStateDumper struct {
+	state State
+	err   error
+	calls int
+}
+
+func (s *stubStateDumper) DumpState(context.Context) (State, error) {
+	s.calls++
+	return s.state, s.err
+}
+
+// collectGauges returns the gauge values by the metric name and the value of the label
+func collectGauges(t *testing.T, reader sdkmetric.Reader) map[string]map[string]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	result := make(map[string]map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			require.True(t, ok, m.Name)
+
+			values := make(map[string]int64)
+			for _, dp := range gauge.DataPoints {
+				component, _ := dp.Attributes.Value("component")
+				assert.Equal(t, "server", component.AsString(), m.Name)
+
+				label, ok := dp.Attributes.Value(metricStatusKey)
+				if !ok {
+					label, ok = dp.Attributes.Value(metricQueueKey)
+				}
+				require.True(t, ok, m.Name)
+				values[label.AsString()] = dp.Value
+			}
+			result[m.Name] = values
+		}
+	}
+
+	return result
+}
+
+func TestStartStateMetricCollect(t *testing.T) {
+	stats := &stubStateDumper{state: State{
+		Flows:      map[string]int64{"running": 3, "failed": 0},
+		Containers: map[string]int64{"running": 2},
+		Queues:     map[string]int64{"flows": 4, "webhook_deliveries": 1},
+	}}
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	attrs := []attribute.KeyValue{attribute.String("component", "server")}
+	require.NoError(t, startStateMetricCollect(stats, meter, attrs))
+
+	assert.Equal(t, map[string]map[string]int64{
+		metricFlows:      {"running": 3, "failed": 0},
+		metricContainers: {"running": 2},
+		metricQueueDepth: {"flows": 4, "webhook_deliveries": 1},
+	}, collectGauges(t, reader))
+
+	// the state is cached between the collections within the collect period
+	stats.state.Flows = map[string]int64{"running": 5}
+	assert.Equal(t, map[string]int64{"running": 3, "failed": 0}, collectGauges(t, reader)[metricFlows])
+	assert.Equal(t, 1, stats.calls)
+}
+
+func TestStartStateMetricCollectError(t *testing.T) {
+	stats := &stubStateDumper{err: errors.New("database is not available")}
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	require.NoError(t, startStateMetricCollect(stats, meter, nil))
+
+	// nothing is observed until the state is read successfully
+	assert.Empty(t, collectGauges(t, reader))
+	assert.Equal(t, 1, stats.calls)
+}
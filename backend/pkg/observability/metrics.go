@@ -0,0 +1,191 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// PentAGI domain metrics, they are created on the observer meter, so the OpenTelemetry collector
+// and the Prometheus endpoint get the same values
+const (
+	metricFlows                 = "pentagi_flows"
+	metricContainers            = "pentagi_containers"
+	metricQueueDepth            = "pentagi_queue_depth"
+	metricToolCalls             = "pentagi_tool_calls"
+	metricToolCallDuration      = "pentagi_tool_call_duration"
+	metricLLMCalls              = "pentagi_llm_calls"
+	metricLLMCallDuration       = "pentagi_llm_call_duration"
+	metricLLMTokens             = "pentagi_llm_tokens"
+	metricSummarizations        = "pentagi_summarizations"
+	metricSummarizationDuration = "pentagi_summarization_duration"
+	metricSearchCalls           = "pentagi_search_calls"
+	metricSearchCallDuration    = "pentagi_search_call_duration"
+)
+
+const (
+	MetricResultSuccess = "success"
+	MetricResultError   = "error"
+)
+
+// Summarization kinds which are recorded by RecordSummarization
+const (
+	SummarizationKindChain      = "chain"
+	SummarizationKindToolResult = "tool_result"
+)
+
+var (
+	metricStatusKey   = attribute.Key("status")
+	metricQueueKey    = attribute.Key("queue")
+	metricToolKey     = attribute.Key("tool")
+	metricResultKey   = attribute.Key("result")
+	metricProviderKey = attribute.Key("provider")
+	metricModelKey    = attribute.Key("model")
+	metricAgentKey    = attribute.Key("agent")
+	metricTokenKey    = attribute.Key("type")
+	metricKindKey     = attribute.Key("kind")
+	metricEngineKey   = attribute.Key("engine")
+)
+
+// durationBuckets are in seconds, the calls take from milliseconds for the local tools
+// up to several minutes for the long terminal commands and LLM generations
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+type Metrics interface {
+	RecordToolCall(ctx context.Context, tool string, duration time.Duration, err error)
+	RecordLLMCall(ctx context.Context, call LLMCall)
+	RecordSummarization(ctx context.Context, kind string, duration time.Duration, err error)
+	RecordSearchCall(ctx context.Context, engine string, duration time.Duration, err error)
+}
+
+// LLMCall describes the finished generation request to the LLM provider including the retries
+type LLMCall struct {
+	Provider         string
+	Model            string
+	Agent            string
+	Duration         time.Duration
+	InputTokens      int64
+	OutputTokens     int64
+	CacheReadTokens  int64
+	CacheWriteTokens int64
+	Err              error
+}
+
+// State holds the current numbers of objects, the keys are statuses for flows and containers
+// and queue names for queues
+type State struct {
+	Flows      map[string]int64
+	Containers map[string]int64
+	Queues     map[string]int64
+}
+
+type domainMetrics struct {
+	toolCalls             otelmetric.Int64Counter
+	toolCallDuration      otelmetric.Float64Histogram
+	llmCalls              otelmetric.Int64Counter
+	llmCallDuration       otelmetric.Float64Histogram
+	llmTokens             otelmetric.Int64Counter
+	summarizations        otelmetric.Int64Counter
+	summarizationDuration otelmetric.Float64Histogram
+	searchCalls           otelmetric.Int64Counter
+	searchCallDuration    otelmetric.Float64Histogram
+}
+
+func newDomainMetrics(meter otelmetric.Meter) *domainMetrics {
+	var (
+		m    domainMetrics
+		err  error
+		errs []error
+	)
+
+	counter := func(name, description, unit string) otelmetric.Int64Counter {
+		var counter otelmetric.Int64Counter
+		counter, err = meter.Int64Counter(name, otelmetric.WithDescription(description), otelmetric.WithUnit(unit))
+		errs = append(errs, err)
+		return counter
+	}
+	histogram := func(name, description string) otelmetric.Float64Histogram {
+		var histogram otelmetric.Float64Histogram
+		histogram, err = meter.Float64Histogram(name,
+			otelmetric.WithDescription(description),
+			otelmetric.WithUnit("s"),
+			otelmetric.WithExplicitBucketBoundaries(durationBuckets...),
+		)
+		errs = append(errs, err)
+		return histogram
+	}
+
+	m.toolCalls = counter(metricToolCalls, "Number of tool calls by tool name and result", "{call}")
+	m.toolCallDuration = histogram(metricToolCallDuration, "Duration of tool calls")
+	m.llmCalls = counter(metricLLMCalls, "Number of LLM calls by provider, model and result", "{call}")
+	m.llmCallDuration = histogram(metricLLMCallDuration, "Latency of LLM calls including rate limit retries")
+	m.llmTokens = counter(metricLLMTokens, "Number of LLM tokens by provider, model and type", "{token}")
+	m.summarizations = counter(metricSummarizations, "Number of summarizations by kind and result", "{event}")
+	m.summarizationDuration = histogram(metricSummarizationDuration, "Duration of summarizations")
+	m.searchCalls = counter(metricSearchCalls, "Number of search engine calls by engine and result", "{call}")
+	m.searchCallDuration = histogram(metricSearchCallDuration, "Duration of search engine calls")
+
+	// the instruments are usable even if the meter rejected their options, so the error is only logged
+	if err = errors.Join(errs...); err != nil {
+		logrus.WithError(err).Error("failed to create domain metrics")
+	}
+
+	return &m
+}
+
+func (obs *observer) RecordToolCall(ctx context.Context, tool string, duration time.Duration, err error) {
+	attrs := otelmetric.WithAttributes(metricToolKey.String(tool), metricResult(err))
+	obs.metrics.toolCalls.Add(ctx, 1, attrs)
+	obs.metrics.toolCallDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+func (obs *observer) RecordLLMCall(ctx context.Context, call LLMCall) {
+	labels := []attribute.KeyValue{
+		metricProviderKey.String(call.Provider),
+		metricModelKey.String(call.Model),
+		metricAgentKey.String(call.Agent),
+	}
+
+	attrs := otelmetric.WithAttributes(append(labels, metricResult(call.Err))...)
+	obs.metrics.llmCalls.Add(ctx, 1, attrs)
+	obs.metrics.llmCallDuration.Record(ctx, call.Duration.Seconds(), attrs)
+
+	tokens := map[string]int64{
+		"input":       call.InputTokens,
+		"output":      call.OutputTokens,
+		"cache_read":  call.CacheReadTokens,
+		"cache_write": call.CacheWriteTokens,
+	}
+	for tokenType, value := range tokens {
+		if value <= 0 {
+			continue
+		}
+		obs.metrics.llmTokens.Add(ctx, value,
+			otelmetric.WithAttributes(labels...),
+			otelmetric.WithAttributes(metricTokenKey.String(tokenType)),
+		)
+	}
+}
+
+func (obs *observer) RecordSummarization(ctx context.Context, kind string, duration time.Duration, err error) {
+	attrs := otelmetric.WithAttributes(metricKindKey.String(kind), metricResult(err))
+	obs.metrics.summarizations.Add(ctx, 1, attrs)
+	obs.metrics.summarizationDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+func (obs *observer) RecordSearchCall(ctx context.Context, engine string, duration time.Duration, err error) {
+	attrs := otelmetric.WithAttributes(metricEngineKey.String(engine), metricResult(err))
+	obs.metrics.searchCalls.Add(ctx, 1, attrs)
+	obs.metrics.searchCallDuration.Record(ctx, duration.Seconds(), attrs)
+}
+
+func metricResult(err error) attribute.KeyValue {
+	if err != nil {
+		return metricResultKey.String(MetricResultError)
+	}
+	return metricResultKey.String(MetricResultSuccess)
+}
@@ -81,6 +81,7 @@ type Observability interface {
 	Flush(ctx context.Context) error
 	Shutdown(ctx context.Context) error
 	Meter
+	Metrics
 	Tracer
 	Collector
 	Langfuse
@@ -125,17 +126,24 @@ type Collector interface {
 	StartProcessMetricCollect(attrs ...attribute.KeyValue) error
 	StartGoRuntimeMetricCollect(attrs ...attribute.KeyValue) error
 	StartDumperMetricCollect(stats Dumper, attrs ...attribute.KeyValue) error
+	StartStateMetricCollect(state StateDumper, attrs ...attribute.KeyValue) error
 }
 
 type Dumper interface {
 	DumpStats() (map[string]float64, error)
 }
 
+// StateDumper returns the current numbers of objects by their states which are collected as gauges
+type StateDumper interface {
+	DumpState(ctx context.Context) (State, error)
+}
+
 type observer struct {
 	levels     []logrus.Level
 	logger     otellog.Logger
 	tracer     oteltrace.Tracer
 	meter      otelmetric.Meter
+	metrics    *domainMetrics
 	lfclient   LangfuseClient
 	otelclient TelemetryClient
 	observer   langfuse.Observer
@@ -165,7 +173,7 @@ func InitObserver(ctx context.Context, lfclient LangfuseClient, otelclient Telem
 		obs.observer = langfuse.NewNoopObserver()
 	}
 
-	if otelclient != nil {
+	if otelclient != nil && otelclient.Logger() != nil {
 		provider := otelclient.Logger()
 		global.SetLoggerProvider(provider)
 		obs.logger = provider.Logger(tname, otellog.WithInstrumentationVersion(tversion))
@@ -173,7 +181,7 @@ func InitObserver(ctx context.Context, lfclient LangfuseClient, otelclient Telem
 		obs.logger = otelloggernoop.NewLoggerProvider().Logger(tname)
 	}
 
-	if otelclient != nil {
+	if otelclient != nil && otelclient.Tracer() != nil {
 		provider := otelclient.Tracer()
 		otel.SetTracerProvider(provider)
 		otel.SetTextMapPropagator(
@@ -196,6 +204,7 @@ func InitObserver(ctx context.Context, lfclient LangfuseClient, otelclient Telem
 	} else {
 		obs.meter = otelmetricnoop.NewMeterProvider().Meter(tname)
 	}
+	obs.metrics = newDomainMetrics(obs.meter)
 
 	Observer = obs
 }
@@ -260,6 +269,18 @@ func (obs *observer) StartDumperMetricCollect(stats Dumper, attrs ...attribute.K
 	return startDumperMetricCollect(stats, obs.meter, attrs)
 }
 
+func (obs *observer) StartStateMetricCollect(state StateDumper, attrs ...attribute.KeyValue) error {
+	if obs.meter == nil {
+		return nil
+	}
+
+	attrs = append(attrs,
+		semconv.ServiceNameKey.String(version.GetBinaryName()),
+		semconv.ServiceVersionKey.String(version.GetBinaryVersion()),
+	)
+	return startStateMetricCollect(state, obs.meter, attrs)
+}
+
 func (obs *observer) NewInt64Counter(
 	name string, options ...otelmetric.Int64CounterOption,
 ) (otelmetric.Int64Counter, error) {
@@ -3,16 +3,20 @@ package observability
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"pentagi/pkg/config"
 	"pentagi/pkg/version"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
 	otellog "go.opentelemetry.io/otel/log"
 	otelmetric "go.opentelemetry.io/otel/metric"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
@@ -38,22 +42,32 @@ type TelemetryClient interface {
 	Logger() otellog.LoggerProvider
 	Tracer() oteltrace.TracerProvider
 	Meter() otelmetric.MeterProvider
+	// MetricsHandler returns the Prometheus scrape handler or nil if the metrics endpoint is disabled
+	MetricsHandler() http.Handler
 	Shutdown(ctx context.Context) error
 	ForceFlush(ctx context.Context) error
 }
 
+// telemetryClient has no logger and tracer and the connection when only the metrics endpoint is enabled
 type telemetryClient struct {
-	conn   *grpc.ClientConn
-	logger *sdklog.LoggerProvider
-	tracer *sdktrace.TracerProvider
-	meter  *sdkmetric.MeterProvider
+	conn    *grpc.ClientConn
+	logger  *sdklog.LoggerProvider
+	tracer  *sdktrace.TracerProvider
+	meter   *sdkmetric.MeterProvider
+	metrics http.Handler
 }
 
 func (c *telemetryClient) Logger() otellog.LoggerProvider {
+	if c.logger == nil {
+		return nil
+	}
 	return c.logger
 }
 
 func (c *telemetryClient) Tracer() oteltrace.TracerProvider {
+	if c.tracer == nil {
+		return nil
+	}
 	return c.tracer
 }
 
@@ -61,37 +75,78 @@ func (c *telemetryClient) Meter() otelmetric.MeterProvider {
 	return c.meter
 }
 
+func (c *telemetryClient) MetricsHandler() http.Handler {
+	return c.metrics
+}
+
 func (c *telemetryClient) Shutdown(ctx context.Context) error {
-	if err := c.logger.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown logger: %w", err)
+	if c.logger != nil {
+		if err := c.logger.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown logger: %w", err)
+		}
 	}
 	if err := c.meter.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown meter: %w", err)
 	}
-	if err := c.tracer.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown tracer: %w", err)
+	if c.tracer != nil {
+		if err := c.tracer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown tracer: %w", err)
+		}
+	}
+	if c.conn == nil {
+		return nil
 	}
 	return c.conn.Close()
 }
 
 func (c *telemetryClient) ForceFlush(ctx context.Context) error {
-	if err := c.logger.ForceFlush(ctx); err != nil {
-		return fmt.Errorf("failed to force flush logger: %w", err)
+	if c.logger != nil {
+		if err := c.logger.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("failed to force flush logger: %w", err)
+		}
 	}
 	if err := c.meter.ForceFlush(ctx); err != nil {
 		return fmt.Errorf("failed to force flush meter: %w", err)
 	}
-	if err := c.tracer.ForceFlush(ctx); err != nil {
-		return fmt.Errorf("failed to force flush tracer: %w", err)
+	if c.tracer != nil {
+		if err := c.tracer.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("failed to force flush tracer: %w", err)
+		}
 	}
 	return nil
 }
 
+// NewTelemetryClient creates the providers which export to the OpenTelemetry collector, the meter provider
+// is also read by the Prometheus exporter if the metrics endpoint is enabled, so both get the same meters
 func NewTelemetryClient(ctx context.Context, cfg *config.Config) (TelemetryClient, error) {
-	if cfg.TelemetryEndpoint == "" {
+	if cfg.TelemetryEndpoint == "" && !cfg.MetricsEnabled {
 		return nil, fmt.Errorf("telemetry endpoint is not set: %w", ErrNotConfigured)
 	}
 
+	client := &telemetryClient{}
+	meterOpts := []sdkmetric.Option{
+		sdkmetric.WithResource(newResource()),
+	}
+
+	if cfg.MetricsEnabled {
+		registry := prometheus.NewRegistry()
+		promExporter, err := otelprom.New(
+			otelprom.WithRegisterer(registry),
+			otelprom.WithoutScopeInfo(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+		}
+
+		meterOpts = append(meterOpts, sdkmetric.WithReader(promExporter))
+		client.metrics = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	}
+
+	if cfg.TelemetryEndpoint == "" {
+		client.meter = sdkmetric.NewMeterProvider(meterOpts...)
+		return client, nil
+	}
+
 	opts := []grpc.DialOption{
 		grpc.WithBlock(),
 		grpc.WithInsecure(),
@@ -135,10 +190,8 @@ func NewTelemetryClient(ctx context.Context, cfg *config.Config) (TelemetryClien
 		sdkmetric.WithTimeout(DefaultMetricTimeout),
 	)
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(metricProcessor),
-		sdkmetric.WithResource(newResource()),
-	)
+	meterOpts = append(meterOpts, sdkmetric.WithReader(metricProcessor))
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
 
 	spanExporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
 	if err != nil {
@@ -155,12 +208,12 @@ func NewTelemetryClient(ctx context.Context, cfg *config.Config) (TelemetryClien
 		sdktrace.WithResource(newResource()),
 	)
 
-	return &telemetryClient{
-		conn:   conn,
-		logger: logProvider,
-		meter:  meterProvider,
-		tracer: tracerProvider,
-	}, nil
+	client.conn = conn
+	client.logger = logProvider
+	client.meter = meterProvider
+	client.tracer = tracerProvider
+
+	return client, nil
 }
 
 func newResource(opts ...attribute.KeyValue) *resource.Resource {
@@ -0,0 +1,244 @@
+package playbook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"pentagi/pkg/tools"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrEmptyName        = errors.New("playbook name is required")
+	ErrEmptyTasks       = errors.New("playbook must contain at least one task")
+	ErrEmptyTaskInput   = errors.New("playbook task input is required")
+	ErrInvalidVariable  = errors.New("invalid playbook variable")
+	ErrMissingVariable  = errors.New("required playbook variable is missing")
+	ErrUnknownVariable  = errors.New("unknown playbook variable")
+	ErrUnknownTool      = errors.New("unknown tool in playbook allow-list")
+	ErrInvalidSubtask   = errors.New("playbook subtask title and description are required")
+	ErrTooManySubtasks  = errors.New("too many subtasks in playbook task")
+	ErrInvalidDepends   = errors.New("playbook subtask may depend only on previous subtasks of the task")
+	ErrTemplateNotValid = errors.New("playbook template is not valid")
+)
+
+// maxTaskSubtasks matches the limit of subtasks which the generator is allowed to plan for a task
+const maxTaskSubtasks = 15
+
+var variableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Variable describes a value which is substituted into task inputs and subtasks
+// by the {{.name}} placeholder when the flow is created from the playbook
+type Variable struct {
+	Name        string  `json:"name" yaml:"name"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     *string `json:"default,omitempty" yaml:"default,omitempty"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// Task is a single user input of the flow, predefined subtasks replace the generator output
+// for this task while the refiner still can patch them after each completed subtask
+type Task struct {
+	Input    string              `json:"input" yaml:"input"`
+	Subtasks []tools.SubtaskInfo `json:"subtasks,omitempty" yaml:"subtasks,omitempty"`
+}
+
+type Playbook struct {
+	Name        string     `json:"name" yaml:"name"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Image       string     `json:"image,omitempty" yaml:"image,omitempty"`
+	Tools       []string   `json:"tools,omitempty" yaml:"tools,omitempty"`
+	Variables   []Variable `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Tasks       []Task     `json:"tasks" yaml:"tasks"`
+}
+
+// Parse reads the playbook from YAML document (JSON is accepted as well) and validates it
+func Parse(content string) (*Playbook, error) {
+	var pb Playbook
+
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&pb); err != nil {
+		return nil, fmt.Errorf("failed to parse playbook: %w", err)
+	}
+
+	if err := pb.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &pb, nil
+}
+
+// Load reads the rendered playbook which was stored as JSON for the flow
+func Load(data []byte) (*Playbook, error) {
+	var pb Playbook
+	if err := json.Unmarshal(data, &pb); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal playbook: %w", err)
+	}
+
+	return &pb, nil
+}
+
+func (pb *Playbook) Validate() error {
+	if strings.TrimSpace(pb.Name) == "" {
+		return ErrEmptyName
+	}
+
+	if len(pb.Tasks) == 0 {
+		return ErrEmptyTasks
+	}
+
+	names := make(map[string]struct{}, len(pb.Variables))
+	for _, variable := range pb.Variables {
+		if !variableNameRegexp.MatchString(variable.Name) {
+			return fmt.Errorf("%w: name '%s' must be a valid identifier", ErrInvalidVariable, variable.Name)
+		}
+		if _, ok := names[variable.Name]; ok {
+			return fmt.Errorf("%w: name '%s' is duplicated", ErrInvalidVariable, variable.Name)
+		}
+		names[variable.Name] = struct{}{}
+	}
+
+	knownTools := tools.GetToolTypeMapping()
+	for _, name := range pb.Tools {
+		if _, ok := knownTools[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownTool, name)
+		}
+	}
+
+	for idx, task := range pb.Tasks {
+		if strings.TrimSpace(task.Input) == "" {
+			return fmt.Errorf("task %d: %w", idx+1, ErrEmptyTaskInput)
+		}
+		if len(task.Subtasks) > maxTaskSubtasks {
+			return fmt.Errorf("task %d: %w: %d of %d allowed", idx+1, ErrTooManySubtasks, len(task.Subtasks), maxTaskSubtasks)
+		}
+		for sdx, subtask := range task.Subtasks {
+			if strings.TrimSpace(subtask.Title) == "" || strings.TrimSpace(subtask.Description) == "" {
+				return fmt.Errorf("task %d subtask %d: %w", idx+1, sdx+1, ErrInvalidSubtask)
+			}
+			for _, position := range subtask.DependsOn {
+				if position < 1 || position > int64(sdx) {
+					return fmt.Errorf("task %d subtask %d: %w: %d", idx+1, sdx+1, ErrInvalidDepends, position)
+				}
+			}
+		}
+	}
+
+	// check that all placeholders refer to declared variables
+	values := make(map[string]string, len(names))
+	for name := range names {
+		values[name] = ""
+	}
+	if _, err := pb.render(values); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Render substitutes variable values into the task inputs and subtasks, missing optional
+// variables are replaced by their default value or an empty string
+func (pb *Playbook) Render(values map[string]string) (*Playbook, error) {
+	vars := make(map[string]string, len(pb.Variables))
+	for _, variable := range pb.Variables {
+		value, ok := values[variable.Name]
+		switch {
+		case ok:
+		case variable.Default != nil:
+			value = *variable.Default
+		case variable.Required:
+			return nil, fmt.Errorf("%w: %s", ErrMissingVariable, variable.Name)
+		}
+		vars[variable.Name] = value
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if _, ok := vars[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownVariable, name)
+		}
+	}
+
+	return pb.render(vars)
+}
+
+// Functions returns the flow functions settings which disable all tools out of the allow-list,
+// it returns nil if the playbook doesn't restrict tools
+func (pb *Playbook) Functions() *tools.Functions {
+	if len(pb.Tools) == 0 {
+		return nil
+	}
+
+	return &tools.Functions{
+		Disabled: tools.DisableFunctionsExcept(pb.Tools),
+	}
+}
+
+// GetTask returns the playbook task by index or nil if the playbook has no more tasks
+func (pb *Playbook) GetTask(idx int) *Task {
+	if idx < 0 || idx >= len(pb.Tasks) {
+		return nil
+	}
+
+	return &pb.Tasks[idx]
+}
+
+func (pb *Playbook) render(vars map[string]string) (*Playbook, error) {
+	result := *pb
+	result.Tools = slices.Clone(pb.Tools)
+	result.Variables = slices.Clone(pb.Variables)
+	result.Tasks = make([]Task, 0, len(pb.Tasks))
+
+	for idx, task := range pb.Tasks {
+		input, err := renderText(task.Input, vars)
+		if err != nil {
+			return nil, fmt.Errorf("task %d input: %w", idx+1, err)
+		}
+
+		subtasks := make([]tools.SubtaskInfo, 0, len(task.Subtasks))
+		for sdx, subtask := range task.Subtasks {
+			title, err := renderText(subtask.Title, vars)
+			if err != nil {
+				return nil, fmt.Errorf("task %d subtask %d title: %w", idx+1, sdx+1, err)
+			}
+			description, err := renderText(subtask.Description, vars)
+			if err != nil {
+				return nil, fmt.Errorf("task %d subtask %d description: %w", idx+1, sdx+1, err)
+			}
+			subtasks = append(subtasks, tools.SubtaskInfo{
+				Title:       title,
+				Description: description,
+				DependsOn:   slices.Clone(subtask.DependsOn),
+			})
+		}
+
+		result.Tasks = append(result.Tasks, Task{
+			Input:    input,
+			Subtasks: subtasks,
+		})
+	}
+
+	return &result, nil
+}
+
+func renderText(text string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("playbook").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateNotValid, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnknownVariable, err)
+	}
+
+	return buf.String(), nil
+}
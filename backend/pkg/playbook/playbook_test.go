@@ -0,0 +1,151 @@
+package playbook
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"pentagi/pkg/tools"
+)
+
+const testPlaybook = `
+name: Web application assessment
+description: Recurring assessment of the customer portal
+image: vxcontrol/kali-linux
+tools: [terminal, file, browser, pentester]
+variables:
+  - name: target_url
+    description: Target URL
+    required: true
+  - name: credentials
+    default: "guest:guest"
+tasks:
+  - input: "Perform reconnaissance of {{.target_url}}"
+    subtasks:
+      - title: Port scan
+        description: "Scan open ports of {{.target_url}} with nmap"
+      - title: Content discovery
+        description: Enumerate directories and files
+        depends_on: [1]
+  - input: "Test authentication of {{.target_url}} with {{.credentials}}"
+`
+
+func TestParse(t *testing.T) {
+	pb, err := Parse(testPlaybook)
+	if err != nil {
+		t.Fatalf("Failed to parse playbook: %v", err)
+	}
+
+	if pb.Name != "Web application assessment" || pb.Image != "vxcontrol/kali-linux" {
+		t.Errorf("Unexpected playbook header: %+v", pb)
+	}
+	if len(pb.Tasks) != 2 || len(pb.Tasks[0].Subtasks) != 2 || len(pb.Tasks[1].Subtasks) != 0 {
+		t.Fatalf("Unexpected playbook tasks: %+v", pb.Tasks)
+	}
+	if pb.GetTask(1) == nil || pb.GetTask(2) != nil {
+		t.Errorf("Unexpected task lookup result")
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{"empty name", "tasks: [{input: scan}]", ErrEmptyName},
+		{"no tasks", "name: test", ErrEmptyTasks},
+		{"empty input", "name: test\ntasks: [{input: ' '}]", ErrEmptyTaskInput},
+		{"invalid variable", "name: test\nvariables: [{name: 'target-url'}]\ntasks: [{input: scan}]", ErrInvalidVariable},
+		{"undeclared variable", "name: test\ntasks: [{input: 'scan {{.target}}'}]", ErrUnknownVariable},
+		{"unknown tool", "name: test\ntools: [nmap]\ntasks: [{input: scan}]", ErrUnknownTool},
+		{"invalid subtask", "name: test\ntasks: [{input: scan, subtasks: [{title: scan}]}]", ErrInvalidSubtask},
+		{"forward dependency", "name: test\ntasks: [{input: scan, subtasks: [{title: a, description: b, depends_on: [1]}]}]", ErrInvalidDepends},
+		{"broken template", "name: test\ntasks: [{input: 'scan {{.target'}]", ErrTemplateNotValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.content); !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := Parse("name: test\nunknown: field\ntasks: [{input: scan}]"); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+}
+
+func TestRender(t *testing.T) {
+	pb, err := Parse(testPlaybook)
+	if err != nil {
+		t.Fatalf("Failed to parse playbook: %v", err)
+	}
+
+	rendered, err := pb.Render(map[string]string{"target_url": "https://example.com"})
+	if err != nil {
+		t.Fatalf("Failed to render playbook: %v", err)
+	}
+
+	if input := rendered.Tasks[0].Input; input != "Perform reconnaissance of https://example.com" {
+		t.Errorf("Unexpected rendered input: %q", input)
+	}
+	if desc := rendered.Tasks[0].Subtasks[0].Description; desc != "Scan open ports of https://example.com with nmap" {
+		t.Errorf("Unexpected rendered subtask: %q", desc)
+	}
+	if deps := rendered.Tasks[0].Subtasks[1].DependsOn; !slices.Equal(deps, []int64{1}) {
+		t.Errorf("Unexpected rendered subtask dependencies: %v", deps)
+	}
+	if input := rendered.Tasks[1].Input; input != "Test authentication of https://example.com with guest:guest" {
+		t.Errorf("Expected default value substitution, got %q", input)
+	}
+	if pb.Tasks[0].Input != "Perform reconnaissance of {{.target_url}}" {
+		t.Errorf("Source playbook must not be modified")
+	}
+
+	if _, err := pb.Render(map[string]string{}); !errors.Is(err, ErrMissingVariable) {
+		t.Errorf("Expected missing variable error, got %v", err)
+	}
+
+	values := map[string]string{"target_url": "https://example.com", "scope": "all"}
+	if _, err := pb.Render(values); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("Expected unknown variable error, got %v", err)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	pb, err := Parse(testPlaybook)
+	if err != nil {
+		t.Fatalf("Failed to parse playbook: %v", err)
+	}
+
+	functions := pb.Functions()
+	if functions == nil {
+		t.Fatalf("Expected functions restrictions")
+	}
+
+	disabled := make([]string, 0, len(functions.Disabled))
+	for _, function := range functions.Disabled {
+		disabled = append(disabled, function.Name)
+	}
+
+	for _, name := range []string{tools.CoderToolName, tools.GoogleToolName, tools.SearchToolName} {
+		if !slices.Contains(disabled, name) {
+			t.Errorf("Expected function %s to be disabled", name)
+		}
+	}
+	for _, name := range pb.Tools {
+		if slices.Contains(disabled, name) {
+			t.Errorf("Expected function %s to be allowed", name)
+		}
+	}
+	for _, name := range []string{tools.FinalyToolName, tools.HackResultToolName, tools.SubtaskListToolName} {
+		if slices.Contains(disabled, name) {
+			t.Errorf("Expected function %s to be always available", name)
+		}
+	}
+
+	pb.Tools = nil
+	if pb.Functions() != nil {
+		t.Errorf("Expected no restrictions without allow-list")
+	}
+}
@@ -65,6 +65,7 @@ func New(cfg *config.Config, providerConfig *pconfig.ProviderConfig) (provider.P
 	if err != nil {
 		return nil, err
 	}
+	httpClient.Transport = newCacheTransport(httpClient.Transport)
 
 	models, err := DefaultModels()
 	if err != nil {
@@ -135,7 +136,7 @@ func (p *anthropicProvider) CallEx(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generator(opt), chain,
 		append([]llms.CallOption{
 			llms.WithStreamingFunc(streamCb),
 		}, p.providerConfig.GetOptionsForType(opt)...)...,
@@ -150,7 +151,7 @@ func (p *anthropicProvider) CallWithTools(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generator(opt), chain,
 		append([]llms.CallOption{
 			llms.WithTools(tools),
 			llms.WithStreamingFunc(streamCb),
@@ -158,7 +159,11 @@ func (p *anthropicProvider) CallWithTools(
 	)
 }
 
-func (p *anthropicProvider) GetUsage(info map[string]any) (int64, int64) {
+func (p *anthropicProvider) generator(opt pconfig.ProviderOptionsType) provider.GenerateContentFunc {
+	return provider.WrapPromptCache(p.llm.GenerateContent, p.providerConfig.GetCacheConfigForType(opt), countMessages)
+}
+
+func (p *anthropicProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["InputTokens"]; ok {
@@ -183,5 +188,12 @@ func (p *anthropicProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	cacheRead, cacheWrite := provider.GetCacheUsage(info)
+
+	return provider.CallUsage{
+		Input:      inputTokens,
+		Output:     outputTokens,
+		CacheRead:  cacheRead,
+		CacheWrite: cacheWrite,
+	}
 }
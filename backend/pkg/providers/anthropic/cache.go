@@ -0,0 +1,212 @@
+package anthropic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"pentagi/pkg/providers/provider"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+var ephemeralCacheControl = map[string]any{"type": "ephemeral"}
+
+type cacheUsage struct {
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+type cacheEvent struct {
+	Type    string      `json:"type"`
+	Usage   *cacheUsage `json:"usage,omitempty"`
+	Message *struct {
+		Usage *cacheUsage `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// countMessages follows the messages conversion of the anthropic client:
+// system messages are merged into the system prompt, other messages are sent as is
+func countMessages(msg llms.MessageContent) int {
+	if msg.Role == llms.ChatMessageTypeSystem {
+		return 0
+	}
+	return 1
+}
+
+// cacheTransport places cache_control breakpoints into the messages API requests
+// and collects cache usage from the responses for calls wrapped by provider.WrapPromptCache
+type cacheTransport struct {
+	base http.RoundTripper
+}
+
+func newCacheTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cacheTransport{base: base}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	state := provider.GetCacheState(req.Context())
+	if state == nil || req.Body == nil || !strings.HasSuffix(req.URL.Path, "/messages") {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if patched, err := applyCacheControl(body, state.Breakpoints); err != nil {
+		logrus.WithError(err).Warn("failed to place prompt cache breakpoints")
+	} else {
+		body = patched
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body = newUsageStreamReader(resp.Body, state)
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var event cacheEvent
+	if err := json.Unmarshal(data, &event); err == nil && event.Usage != nil {
+		state.AddUsage(event.Usage.CacheReadInputTokens, event.Usage.CacheCreationInputTokens)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	return resp, nil
+}
+
+// applyCacheControl patches the request payload, it keeps unknown fields and numbers as is
+func applyCacheControl(body []byte, breakpoints provider.CacheBreakpoints) ([]byte, error) {
+	var payload map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	if system, ok := payload["system"].(string); ok && breakpoints.System && system != "" {
+		payload["system"] = []any{map[string]any{
+			"type":          "text",
+			"text":          system,
+			"cache_control": ephemeralCacheControl,
+		}}
+	}
+
+	if tools, ok := payload["tools"].([]any); ok && breakpoints.Tools && len(tools) > 0 {
+		if tool, ok := tools[len(tools)-1].(map[string]any); ok {
+			tool["cache_control"] = ephemeralCacheControl
+		}
+	}
+
+	messages, _ := payload["messages"].([]any)
+	if idx := breakpoints.Message; idx >= 0 && idx < len(messages) {
+		if message, ok := messages[idx].(map[string]any); ok {
+			setMessageCacheControl(message)
+		}
+	}
+
+	return json.Marshal(payload)
+}
+
+func setMessageCacheControl(message map[string]any) {
+	switch content := message["content"].(type) {
+	case string:
+		message["content"] = []any{map[string]any{
+			"type":          "text",
+			"text":          content,
+			"cache_control": ephemeralCacheControl,
+		}}
+	case []any:
+		// thinking blocks can't be cached directly, so we use the last cacheable block
+		for i := len(content) - 1; i >= 0; i-- {
+			block, ok := content[i].(map[string]any)
+			if !ok {
+				continue
+			}
+			if blockType, _ := block["type"].(string); blockType == "thinking" || blockType == "redacted_thinking" {
+				continue
+			}
+			block["cache_control"] = ephemeralCacheControl
+			return
+		}
+	}
+}
+
+// usageStreamReader passes the SSE stream through and reads cache usage from the message_start event
+type usageStreamReader struct {
+	body  io.ReadCloser
+	state *provider.CacheState
+	line  []byte
+	done  bool
+}
+
+func newUsageStreamReader(body io.ReadCloser, state *provider.CacheState) io.ReadCloser {
+	return &usageStreamReader{body: body, state: state}
+}
+
+func (r *usageStreamReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 && !r.done {
+		r.scan(p[:n])
+	}
+	return n, err
+}
+
+func (r *usageStreamReader) Close() error {
+	return r.body.Close()
+}
+
+func (r *usageStreamReader) scan(data []byte) {
+	r.line = append(r.line, data...)
+
+	for {
+		idx := bytes.IndexByte(r.line, '\n')
+		if idx < 0 {
+			return
+		}
+
+		line := bytes.TrimSpace(r.line[:idx])
+		r.line = r.line[idx+1:]
+
+		payload, ok := bytes.CutPrefix(line, []byte("data:"))
+		if !ok {
+			continue
+		}
+
+		var event cacheEvent
+		if err := json.Unmarshal(bytes.TrimSpace(payload), &event); err != nil {
+			continue
+		}
+		if event.Type == "message_start" && event.Message != nil && event.Message.Usage != nil {
+			r.state.AddUsage(event.Message.Usage.CacheReadInputTokens, event.Message.Usage.CacheCreationInputTokens)
+			r.done = true
+			r.line = nil
+			return
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const testMessageResponse = `{
+	"id": "msg_1",
+	"type": "message",
+	"role": "assistant",
+	"model": "claude-sonnet-4-5",
+	"content": [{"type": "text", "text": "done"}],
+	"stop_reason": "end_turn",
+	"usage": {
+		"input_tokens": 20,
+		"output_tokens": 5,
+		"cache_creation_input_tokens": 300,
+		"cache_read_input_tokens": 1200
+	}
+}`
+
+var testStreamResponse = []string{
+	`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4-5","content":[],"usage":{"input_tokens":20,"output_tokens":1,"cache_creation_input_tokens":300,"cache_read_input_tokens":1200}}}`,
+	`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+	`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"done"}}`,
+	`{"type":"content_block_stop","index":0}`,
+	`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":5}}`,
+	`{"type":"message_stop"}`,
+}
+
+func toolCallMessages(id string) []llms.MessageContent {
+	return []llms.MessageContent{
+		{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{llms.ToolCall{
+				ID:           id,
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "terminal", Arguments: `{"input":"ls"}`},
+			}},
+		},
+		{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: id,
+				Name:       "terminal",
+				Content:    "file.txt",
+			}},
+		},
+	}
+}
+
+func newCacheTestServer(t *testing.T, stream bool, payloads chan<- map[string]any) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request: %v", err)
+		}
+
+		var payload map[string]any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Failed to parse request: %v", err)
+		}
+		payloads <- payload
+
+		if !stream {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, testMessageResponse)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range testStreamResponse {
+			var data struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal([]byte(event), &data)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", data.Type, event)
+		}
+	}))
+}
+
+func TestPromptCache(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%v", stream), func(t *testing.T) {
+			payloads := make(chan map[string]any, 1)
+			server := newCacheTestServer(t, stream, payloads)
+			defer server.Close()
+
+			providerConfig, err := BuildProviderConfig([]byte(`
+pentester:
+  model: claude-sonnet-4-5
+  max_tokens: 1000
+  cache:
+    system: true
+    tools: true
+    chain: true
+`))
+			if err != nil {
+				t.Fatalf("Failed to create provider config: %v", err)
+			}
+
+			prov, err := New(&config.Config{
+				AnthropicAPIKey:    "test-key",
+				AnthropicServerURL: server.URL + "/v1",
+			}, providerConfig)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+
+			chain := []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeSystem, "You are a pentester"),
+				llms.TextParts(llms.ChatMessageTypeHuman, "scan the target"),
+			}
+			chain = append(chain, toolCallMessages("call_1")...)
+			chain = append(chain, toolCallMessages("call_2")...)
+
+			tools := []llms.Tool{
+				{Type: "function", Function: &llms.FunctionDefinition{Name: "terminal", Parameters: map[string]any{"type": "object"}}},
+				{Type: "function", Function: &llms.FunctionDefinition{Name: "done", Parameters: map[string]any{"type": "object"}}},
+			}
+
+			var streamCb streaming.Callback
+			if stream {
+				streamCb = func(ctx context.Context, chunk streaming.Chunk) error { return nil }
+			}
+
+			resp, err := prov.CallWithTools(context.Background(), pconfig.OptionsTypePentester, chain, tools, streamCb)
+			if err != nil {
+				t.Fatalf("Failed to call provider: %v", err)
+			}
+
+			payload := <-payloads
+			system, ok := payload["system"].([]any)
+			if !ok || len(system) != 1 || system[0].(map[string]any)["cache_control"] == nil {
+				t.Errorf("Expected cached system block, got %v", payload["system"])
+			}
+
+			requestTools := payload["tools"].([]any)
+			if requestTools[0].(map[string]any)["cache_control"] != nil ||
+				requestTools[1].(map[string]any)["cache_control"] == nil {
+				t.Errorf("Expected cache breakpoint on the last tool only, got %v", requestTools)
+			}
+
+			// the breakpoint is placed on the first tool result, the newest body pair isn't stable yet
+			messages := payload["messages"].([]any)
+			for idx, message := range messages {
+				content := message.(map[string]any)["content"].([]any)
+				cached := content[len(content)-1].(map[string]any)["cache_control"] != nil
+				if cached != (idx == 2) {
+					t.Errorf("Unexpected cache breakpoint state %v for message %d", cached, idx)
+				}
+			}
+
+			if len(resp.Choices) == 0 {
+				t.Fatal("Expected response choices")
+			}
+			usage := prov.GetUsage(resp.Choices[0].GenerationInfo)
+			if usage.CacheRead != 1200 || usage.CacheWrite != 300 {
+				t.Errorf("Expected cache usage 1200/300, got %d/%d", usage.CacheRead, usage.CacheWrite)
+			}
+		})
+	}
+}
+
+func TestPromptCacheDisabled(t *testing.T) {
+	body := []byte(`{"system":"prompt","messages":[{"role":"user","content":[{"type":"text","text":"hi"}]}]}`)
+	breakpoints := provider.NewCacheBreakpoints(nil, nil, countMessages)
+
+	patched, err := applyCacheControl(body, breakpoints)
+	if err != nil {
+		t.Fatalf("Failed to apply cache control: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(patched, &payload); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+	if payload["system"] != "prompt" {
+		t.Errorf("System prompt must be kept as is, got %v", payload["system"])
+	}
+}
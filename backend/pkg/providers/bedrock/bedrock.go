@@ -93,7 +93,7 @@ func New(cfg *config.Config, providerConfig *pconfig.ProviderConfig) (provider.P
 		return nil, fmt.Errorf("failed to load default config: %w", err)
 	}
 
-	bclient := bedrockruntime.NewFromConfig(bcfg)
+	bclient := bedrockruntime.NewFromConfig(bcfg, withPromptCache)
 
 	models, err := DefaultModels()
 	if err != nil {
@@ -163,7 +163,7 @@ func (p *bedrockProvider) CallEx(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generator(opt), chain,
 		append([]llms.CallOption{
 			llms.WithStreamingFunc(streamCb),
 		}, p.providerConfig.GetOptionsForType(opt)...)...,
@@ -178,7 +178,7 @@ func (p *bedrockProvider) CallWithTools(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generator(opt), chain,
 		append([]llms.CallOption{
 			llms.WithTools(tools),
 			llms.WithStreamingFunc(streamCb),
@@ -186,7 +186,11 @@ func (p *bedrockProvider) CallWithTools(
 	)
 }
 
-func (p *bedrockProvider) GetUsage(info map[string]any) (int64, int64) {
+func (p *bedrockProvider) generator(opt pconfig.ProviderOptionsType) provider.GenerateContentFunc {
+	return provider.WrapPromptCache(p.llm.GenerateContent, p.providerConfig.GetCacheConfigForType(opt), countMessages)
+}
+
+func (p *bedrockProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["input_tokens"]; ok {
@@ -215,5 +219,12 @@ func (p *bedrockProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	cacheRead, cacheWrite := provider.GetCacheUsage(info)
+
+	return provider.CallUsage{
+		Input:      inputTokens,
+		Output:     outputTokens,
+		CacheRead:  cacheRead,
+		CacheWrite: cacheWrite,
+	}
 }
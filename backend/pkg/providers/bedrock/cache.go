@@ -0,0 +1,96 @@
+package bedrock
+
+import (
+	"context"
+
+	"pentagi/pkg/providers/provider"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+const promptCacheMiddlewareID = "PromptCache"
+
+// countMessages follows the messages conversion of the bedrock client:
+// system messages become system blocks, every other message part is sent as a separate message
+func countMessages(msg llms.MessageContent) int {
+	if msg.Role == llms.ChatMessageTypeSystem {
+		return 0
+	}
+	return len(msg.Parts)
+}
+
+// withPromptCache registers the middleware which places cache points into Converse API requests
+// and collects cache usage from the responses for calls wrapped by provider.WrapPromptCache
+func withPromptCache(options *bedrockruntime.Options) {
+	options.APIOptions = append(options.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(
+			middleware.InitializeMiddlewareFunc(promptCacheMiddlewareID, handlePromptCache),
+			middleware.Before,
+		)
+	})
+}
+
+func handlePromptCache(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	state := provider.GetCacheState(ctx)
+	if state == nil {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	switch params := in.Parameters.(type) {
+	case *bedrockruntime.ConverseInput:
+		params.System = applyCachePoints(state.Breakpoints, params.System, params.ToolConfig, params.Messages)
+	case *bedrockruntime.ConverseStreamInput:
+		params.System = applyCachePoints(state.Breakpoints, params.System, params.ToolConfig, params.Messages)
+	}
+
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	if err != nil {
+		return out, metadata, err
+	}
+
+	// stream usage is delivered in the metadata event which isn't handled by the client yet
+	if result, ok := out.Result.(*bedrockruntime.ConverseOutput); ok && result.Usage != nil {
+		state.AddUsage(int64From(result.Usage.CacheReadInputTokens), int64From(result.Usage.CacheWriteInputTokens))
+	}
+
+	return out, metadata, nil
+}
+
+// applyCachePoints appends cache point blocks after the system prompt, the tool definitions
+// and the last message of the stable chain prefix, it returns updated system blocks
+func applyCachePoints(
+	breakpoints provider.CacheBreakpoints,
+	system []types.SystemContentBlock,
+	toolConfig *types.ToolConfiguration,
+	messages []types.Message,
+) []types.SystemContentBlock {
+	cachePoint := types.CachePointBlock{Type: types.CachePointTypeDefault}
+
+	if breakpoints.System && len(system) > 0 {
+		system = append(system, &types.SystemContentBlockMemberCachePoint{Value: cachePoint})
+	}
+
+	if breakpoints.Tools && toolConfig != nil && len(toolConfig.Tools) > 0 {
+		toolConfig.Tools = append(toolConfig.Tools, &types.ToolMemberCachePoint{Value: cachePoint})
+	}
+
+	if idx := breakpoints.Message; idx >= 0 && idx < len(messages) && len(messages[idx].Content) > 0 {
+		messages[idx].Content = append(messages[idx].Content, &types.ContentBlockMemberCachePoint{Value: cachePoint})
+	}
+
+	return system
+}
+
+func int64From(value *int32) int64 {
+	if value == nil {
+		return 0
+	}
+	return int64(*value)
+}
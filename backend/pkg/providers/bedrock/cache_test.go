@@ -0,0 +1,108 @@
+package bedrock
+
+import (
+	"context"
+	"testing"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+func TestPromptCacheMiddleware(t *testing.T) {
+	chain := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a pentester"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "scan the target"),
+		{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{
+				llms.TextContent{Text: "running"},
+				llms.ToolCall{ID: "call_1", FunctionCall: &llms.FunctionCall{Name: "terminal", Arguments: "{}"}},
+			},
+		},
+		{
+			Role:  llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_1", Name: "terminal", Content: "ok"}},
+		},
+		{
+			Role:  llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{llms.ToolCall{ID: "call_2", FunctionCall: &llms.FunctionCall{Name: "done", Arguments: "{}"}}},
+		},
+		{
+			Role:  llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_2", Name: "done", Content: "ok"}},
+		},
+	}
+
+	cacheConfig := &pconfig.CacheConfig{System: true, Tools: true, Chain: true}
+	state := &provider.CacheState{Breakpoints: provider.NewCacheBreakpoints(cacheConfig, chain, countMessages)}
+
+	// the AI message with text and tool call is converted to two wire messages
+	if state.Breakpoints.Message != 3 {
+		t.Fatalf("Expected breakpoint on wire message 3, got %d", state.Breakpoints.Message)
+	}
+
+	messages := make([]types.Message, 6)
+	for idx := range messages {
+		messages[idx].Content = []types.ContentBlock{&types.ContentBlockMemberText{Value: "text"}}
+	}
+	input := &bedrockruntime.ConverseInput{
+		System:     []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: "You are a pentester"}},
+		ToolConfig: &types.ToolConfiguration{Tools: []types.Tool{&types.ToolMemberToolSpec{}}},
+		Messages:   messages,
+	}
+
+	next := middleware.InitializeHandlerFunc(func(
+		ctx context.Context,
+		in middleware.InitializeInput,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		return middleware.InitializeOutput{Result: &bedrockruntime.ConverseOutput{
+			Usage: &types.TokenUsage{
+				CacheReadInputTokens:  aws.Int32(1200),
+				CacheWriteInputTokens: aws.Int32(300),
+			},
+		}}, middleware.Metadata{}, nil
+	})
+
+	ctx := provider.WithCacheState(context.Background(), state)
+	if _, _, err := handlePromptCache(ctx, middleware.InitializeInput{Parameters: input}, next); err != nil {
+		t.Fatalf("Failed to handle request: %v", err)
+	}
+
+	if len(input.System) != 2 {
+		t.Errorf("Expected cache point after system prompt, got %d blocks", len(input.System))
+	}
+	if len(input.ToolConfig.Tools) != 2 {
+		t.Errorf("Expected cache point after tools, got %d tools", len(input.ToolConfig.Tools))
+	}
+	for idx, message := range input.Messages {
+		_, cached := message.Content[len(message.Content)-1].(*types.ContentBlockMemberCachePoint)
+		if cached != (idx == 3) {
+			t.Errorf("Unexpected cache point state %v for message %d", cached, idx)
+		}
+	}
+
+	generate := provider.WrapPromptCache(func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		provider.GetCacheState(ctx).AddUsage(1200, 300)
+		return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "done"}}}, nil
+	}, cacheConfig, countMessages)
+
+	resp, err := generate(context.Background(), chain)
+	if err != nil {
+		t.Fatalf("Failed to generate content: %v", err)
+	}
+
+	usage := (&bedrockProvider{}).GetUsage(resp.Choices[0].GenerationInfo)
+	if usage.CacheRead != 1200 || usage.CacheWrite != 300 {
+		t.Errorf("Expected cache usage 1200/300, got %d/%d", usage.CacheRead, usage.CacheWrite)
+	}
+}
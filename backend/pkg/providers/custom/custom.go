@@ -184,7 +184,7 @@ func (p *customProvider) CallWithTools(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generatorWithTools(opt), chain,
 		append([]llms.CallOption{
 			llms.WithTools(tools),
 			llms.WithStreamingFunc(streamCb),
@@ -192,7 +192,12 @@ func (p *customProvider) CallWithTools(
 	)
 }
 
-func (p *customProvider) GetUsage(info map[string]any) (int64, int64) {
+// generatorWithTools emulates tool calling via the prompt for models without native function calling
+func (p *customProvider) generatorWithTools(opt pconfig.ProviderOptionsType) provider.GenerateContentFunc {
+	return provider.WrapToolCallingEmulation(p.llm.GenerateContent, p.providerConfig.GetToolCallingForType(opt))
+}
+
+func (p *customProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["PromptTokens"]; ok {
@@ -217,7 +222,7 @@ func (p *customProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	return provider.CallUsage{Input: inputTokens, Output: outputTokens}
 }
 
 func loadModelsFromServer(baseURL, baseKey string, client *http.Client) pconfig.ModelsConfig {
@@ -236,7 +236,7 @@ func (p *geminiProvider) CallWithTools(
 	)
 }
 
-func (p *geminiProvider) GetUsage(info map[string]any) (int64, int64) {
+func (p *geminiProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["input_tokens"]; ok {
@@ -265,5 +265,5 @@ func (p *geminiProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	return provider.CallUsage{Input: inputTokens, Output: outputTokens}
 }
@@ -161,19 +161,19 @@ func TestGetUsage(t *testing.T) {
 		"output_tokens": int32(50),
 	}
 
-	inputTokens, outputTokens := prov.GetUsage(usageInfo)
-	if inputTokens != 100 {
-		t.Errorf("Expected input tokens 100, got %d", inputTokens)
+	usage := prov.GetUsage(usageInfo)
+	if usage.Input != 100 {
+		t.Errorf("Expected input tokens 100, got %d", usage.Input)
 	}
-	if outputTokens != 50 {
-		t.Errorf("Expected output tokens 50, got %d", outputTokens)
+	if usage.Output != 50 {
+		t.Errorf("Expected output tokens 50, got %d", usage.Output)
 	}
 
 	// Test with missing usage info
 	emptyInfo := map[string]any{}
-	inputTokens, outputTokens = prov.GetUsage(emptyInfo)
-	if inputTokens != 0 || outputTokens != 0 {
-		t.Errorf("Expected zero tokens with empty usage info, got input: %d, output: %d", inputTokens, outputTokens)
+	usage = prov.GetUsage(emptyInfo)
+	if usage.Input != 0 || usage.Output != 0 {
+		t.Errorf("Expected zero tokens with empty usage info, got input: %d, output: %d", usage.Input, usage.Output)
 	}
 }
 
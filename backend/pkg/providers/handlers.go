@@ -9,7 +9,6 @@ import (
 	"pentagi/pkg/cast"
 	"pentagi/pkg/csum"
 	"pentagi/pkg/database"
-	"pentagi/pkg/docker"
 	obs "pentagi/pkg/observability"
 	"pentagi/pkg/observability/langfuse"
 	"pentagi/pkg/providers/pconfig"
@@ -256,7 +255,7 @@ func (fp *flowProvider) GetCoderHandler(ctx context.Context, taskID, subtaskID *
 				"SummarizationToolName":   cast.SummarizationToolName,
 				"SummarizedContentPrefix": strings.ReplaceAll(csum.SummarizedContentPrefix, "\n", "\\n"),
 				"DockerImage":             fp.image,
-				"Cwd":                     docker.WorkFolderPathInContainer,
+				"Cwd":                     tools.GetWorkDir(ctx),
 				"ContainerPorts":          fp.getContainerPortsDescription(),
 				"ExecutionContext":        executionContext,
 				"Lang":                    fp.language,
@@ -384,7 +383,7 @@ func (fp *flowProvider) GetInstallerHandler(ctx context.Context, taskID, subtask
 				"SummarizationToolName":     cast.SummarizationToolName,
 				"SummarizedContentPrefix":   strings.ReplaceAll(csum.SummarizedContentPrefix, "\n", "\\n"),
 				"DockerImage":               fp.image,
-				"Cwd":                       docker.WorkFolderPathInContainer,
+				"Cwd":                       tools.GetWorkDir(ctx),
 				"ContainerPorts":            fp.getContainerPortsDescription(),
 				"ExecutionContext":          executionContext,
 				"Lang":                      fp.language,
@@ -552,7 +551,7 @@ func (fp *flowProvider) GetMemoristHandler(ctx context.Context, taskID, subtaskI
 				"SummarizationToolName":   cast.SummarizationToolName,
 				"SummarizedContentPrefix": strings.ReplaceAll(csum.SummarizedContentPrefix, "\n", "\\n"),
 				"DockerImage":             fp.image,
-				"Cwd":                     docker.WorkFolderPathInContainer,
+				"Cwd":                     tools.GetWorkDir(ctx),
 				"ContainerPorts":          fp.getContainerPortsDescription(),
 				"ExecutionContext":        executionContext,
 				"Lang":                    fp.language,
@@ -688,7 +687,7 @@ func (fp *flowProvider) GetPentesterHandler(ctx context.Context, taskID, subtask
 				"SummarizedContentPrefix": strings.ReplaceAll(csum.SummarizedContentPrefix, "\n", "\\n"),
 				"IsDefaultDockerImage":    strings.HasPrefix(strings.ToLower(fp.image), pentestDockerImage),
 				"DockerImage":             fp.image,
-				"Cwd":                     docker.WorkFolderPathInContainer,
+				"Cwd":                     tools.GetWorkDir(ctx),
 				"ContainerPorts":          fp.getContainerPortsDescription(),
 				"ExecutionContext":        executionContext,
 				"Lang":                    fp.language,
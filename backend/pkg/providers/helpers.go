@@ -30,6 +30,7 @@ const (
 	maxQABytesAfterRestore       = 20 * 1024 // 20 KB
 	msgLogResultSummarySizeLimit = 70 * 1024 // 70 KB
 	msgLogResultEntrySizeLimit   = 1024      // 1 KB
+	defaultCompletionReserve     = 4096      // tokens
 )
 
 type repeatingDetector struct {
@@ -83,6 +84,29 @@ func (rd *repeatingDetector) clearCallArguments(toolCall *llms.FunctionCall) llm
 	}
 }
 
+// getContextBudget returns the context window budget of the agent model, the completion limit
+// and tool definitions are reserved because they aren't part of the messages chain
+func (fp *flowProvider) getContextBudget(optAgentType pconfig.ProviderOptionsType, tools []llms.Tool) csum.ContextBudget {
+	limits := fp.GetProviderConfig().GetContextLimitsForType(optAgentType, fp.Model(optAgentType), fp.GetModels())
+	if limits.ContextWindow <= 0 {
+		return csum.ContextBudget{}
+	}
+
+	budget := csum.ContextBudget{
+		ContextWindow: limits.ContextWindow,
+		ReserveTokens: limits.MaxTokens,
+		BytesPerToken: limits.Tokenizer.BytesPerToken(),
+	}
+	if budget.ReserveTokens <= 0 {
+		budget.ReserveTokens = defaultCompletionReserve
+	}
+	if toolsBlob, err := json.Marshal(tools); err == nil {
+		budget.ReserveTokens += budget.EstimateTokens(len(toolsBlob))
+	}
+
+	return budget
+}
+
 func (fp *flowProvider) getTasksInfo(ctx context.Context, taskID int64) (*tasksInfo, error) {
 	var (
 		err  error
@@ -580,6 +604,9 @@ func (fp *flowProvider) subtasksToMarkdown(subtasks []tools.SubtaskInfo) string
 	for sid, subtask := range subtasks {
 		buffer.WriteString(fmt.Sprintf("# Subtask %d\n\n", sid+1))
 		buffer.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", subtask.Title, subtask.Description))
+		if len(subtask.DependsOn) != 0 {
+			buffer.WriteString(fmt.Sprintf("Depends on: %v\n\n", subtask.DependsOn))
+		}
 	}
 
 	return buffer.String()
@@ -0,0 +1,336 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const MockAgentModel = "mock"
+
+var ErrNoScriptedResponse = errors.New("no scripted response")
+
+func BuildProviderConfig(configData []byte) (*pconfig.ProviderConfig, error) {
+	defaultOptions := []llms.CallOption{
+		llms.WithModel(MockAgentModel),
+	}
+
+	providerConfig, err := pconfig.LoadConfigData(configData, defaultOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerConfig, nil
+}
+
+func DefaultProviderConfig() (*pconfig.ProviderConfig, error) {
+	return BuildProviderConfig([]byte(pconfig.EmptyProviderConfigRaw))
+}
+
+type ruleCursor struct {
+	opt  pconfig.ProviderOptionsType
+	rule int
+}
+
+type mockProvider struct {
+	mx             *sync.Mutex
+	script         *Script
+	cursors        map[ruleCursor]int
+	callNumber     *atomic.Int64
+	providerConfig *pconfig.ProviderConfig
+}
+
+func New(script *Script, providerConfig *pconfig.ProviderConfig) (provider.Provider, error) {
+	if script == nil {
+		return nil, fmt.Errorf("mock script is not set")
+	}
+
+	return &mockProvider{
+		mx:             &sync.Mutex{},
+		script:         script,
+		cursors:        make(map[ruleCursor]int),
+		callNumber:     &atomic.Int64{},
+		providerConfig: providerConfig,
+	}, nil
+}
+
+func (p *mockProvider) Type() provider.ProviderType {
+	return provider.ProviderMock
+}
+
+func (p *mockProvider) GetRawConfig() []byte {
+	return p.providerConfig.GetRawConfig()
+}
+
+func (p *mockProvider) GetProviderConfig() *pconfig.ProviderConfig {
+	return p.providerConfig
+}
+
+func (p *mockProvider) GetPriceInfo(opt pconfig.ProviderOptionsType) *pconfig.PriceInfo {
+	return p.providerConfig.GetPriceInfoForType(opt)
+}
+
+func (p *mockProvider) GetModels() pconfig.ModelsConfig {
+	return nil
+}
+
+func (p *mockProvider) Model(opt pconfig.ProviderOptionsType) string {
+	opts := llms.CallOptions{Model: MockAgentModel}
+	for _, option := range p.providerConfig.GetOptionsForType(opt) {
+		option(&opts)
+	}
+
+	return opts.Model
+}
+
+func (p *mockProvider) Call(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	prompt string,
+) (string, error) {
+	chain := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}
+
+	resp, err := provider.WrapGenerateContent(ctx, p, opt, p.generator(opt), chain)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("scripted response has no choices")
+	}
+
+	return resp.Choices[0].Content, nil
+}
+
+func (p *mockProvider) CallEx(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	return provider.WrapGenerateContent(
+		ctx, p, opt, p.generator(opt), chain,
+		llms.WithStreamingFunc(streamCb),
+	)
+}
+
+func (p *mockProvider) CallWithTools(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	tools []llms.Tool,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	return provider.WrapGenerateContent(
+		ctx, p, opt, p.generator(opt), chain,
+		llms.WithTools(tools),
+		llms.WithStreamingFunc(streamCb),
+	)
+}
+
+func (p *mockProvider) GetUsage(info map[string]any) provider.CallUsage {
+	var inputTokens, outputTokens int64
+
+	if value, ok := info["InputTokens"].(int64); ok {
+		inputTokens = value
+	}
+
+	if value, ok := info["OutputTokens"].(int64); ok {
+		outputTokens = value
+	}
+
+	return provider.CallUsage{Input: inputTokens, Output: outputTokens}
+}
+
+// next returns the next scripted response for the agent type and the last human message
+func (p *mockProvider) next(opt pconfig.ProviderOptionsType, message string) (*Response, error) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	idx := p.script.match(opt, message)
+	if idx < 0 {
+		if p.script.Default != nil {
+			return p.script.Default, nil
+		}
+		return nil, fmt.Errorf("%w for agent '%s'", ErrNoScriptedResponse, opt)
+	}
+
+	rule := p.script.Agents[opt][idx]
+	cursor := ruleCursor{opt: opt, rule: idx}
+	pos := p.cursors[cursor]
+	p.cursors[cursor] = pos + 1
+
+	if pos >= len(rule.Responses) {
+		if rule.Loop {
+			pos %= len(rule.Responses)
+		} else {
+			pos = len(rule.Responses) - 1
+		}
+	}
+
+	return rule.Responses[pos], nil
+}
+
+func (p *mockProvider) generator(opt pconfig.ProviderOptionsType) provider.GenerateContentFunc {
+	return func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		opts := llms.CallOptions{}
+		for _, option := range options {
+			option(&opts)
+		}
+
+		resp, err := p.next(opt, lastHumanMessage(messages))
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(resp.delay):
+			}
+		}
+
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+
+		toolCalls, err := p.buildToolCalls(resp.ToolCalls)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := streamResponse(ctx, opts.StreamingFunc, resp, toolCalls); err != nil {
+			return nil, err
+		}
+
+		choice := &llms.ContentChoice{
+			Content:          resp.Content,
+			ReasoningContent: resp.Reasoning,
+			ToolCalls:        toolCalls,
+			GenerationInfo: map[string]any{
+				"InputTokens":  estimateTokens(messages),
+				"OutputTokens": int64(len(resp.Content)+len(resp.Reasoning)) / 4,
+			},
+		}
+		if len(toolCalls) > 0 {
+			choice.FuncCall = toolCalls[0].FunctionCall
+			choice.StopReason = "tool_calls"
+		} else {
+			choice.StopReason = "stop"
+		}
+
+		return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}}, nil
+	}
+}
+
+func (p *mockProvider) buildToolCalls(calls []*ToolCall) ([]llms.ToolCall, error) {
+	toolCalls := make([]llms.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		args := call.Args
+		if args == nil {
+			args = map[string]any{}
+		}
+
+		data, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tool call '%s' arguments: %w", call.Name, err)
+		}
+
+		toolCalls = append(toolCalls, llms.ToolCall{
+			ID:   fmt.Sprintf("call_mock_%d", p.callNumber.Add(1)),
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name:      call.Name,
+				Arguments: string(data),
+			},
+		})
+	}
+
+	return toolCalls, nil
+}
+
+func streamResponse(
+	ctx context.Context,
+	streamCb streaming.Callback,
+	resp *Response,
+	toolCalls []llms.ToolCall,
+) error {
+	if streamCb == nil {
+		return nil
+	}
+
+	if err := streaming.CallWithReasoning(ctx, streamCb, resp.Reasoning); err != nil {
+		return err
+	}
+
+	if err := streaming.CallWithText(ctx, streamCb, resp.Content); err != nil {
+		return err
+	}
+
+	for _, toolCall := range toolCalls {
+		err := streaming.CallWithToolCall(ctx, streamCb, streaming.NewToolCall(
+			toolCall.ID, toolCall.FunctionCall.Name, toolCall.FunctionCall.Arguments,
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return streaming.CallWithDone(ctx, streamCb)
+}
+
+func lastHumanMessage(chain []llms.MessageContent) string {
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Role != llms.ChatMessageTypeHuman {
+			continue
+		}
+
+		parts := make([]string, 0, len(chain[i].Parts))
+		for _, part := range chain[i].Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				parts = append(parts, text.Text)
+			}
+		}
+
+		return strings.Join(parts, "\n")
+	}
+
+	return ""
+}
+
+// estimateTokens uses rough 4 bytes per token ratio to keep usage stats realistic
+func estimateTokens(chain []llms.MessageContent) int64 {
+	var size int
+	for _, msg := range chain {
+		for _, part := range msg.Parts {
+			switch part := part.(type) {
+			case llms.TextContent:
+				size += len(part.Text)
+			case llms.ToolCall:
+				if part.FunctionCall != nil {
+					size += len(part.FunctionCall.Name) + len(part.FunctionCall.Arguments)
+				}
+			case llms.ToolCallResponse:
+				size += len(part.Name) + len(part.Content)
+			}
+		}
+	}
+
+	return int64(size) / 4
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const testScript = `
+agents:
+  simple:
+    - contains: "title"
+      responses:
+        - content: "Scan title"
+  primary_agent:
+    - regex: "(?i)^scan .+"
+      responses:
+        - content: "delegating"
+          tool_calls:
+            - name: pentester
+              args: {question: "run nmap", message: "scan"}
+        - tool_calls:
+            - name: done
+              args: {success: true, result: "ok", message: "done"}
+    - loop: true
+      responses:
+        - content: "first"
+        - content: "second"
+  reflector:
+    - responses:
+        - error: "rate limit"
+`
+
+func newTestProvider(t *testing.T) provider.Provider {
+	t.Helper()
+
+	script, err := ParseScript([]byte(testScript))
+	if err != nil {
+		t.Fatalf("Failed to parse script: %v", err)
+	}
+
+	providerConfig, err := DefaultProviderConfig()
+	if err != nil {
+		t.Fatalf("Failed to create provider config: %v", err)
+	}
+
+	prv, err := New(script, providerConfig)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	return prv
+}
+
+func humanChain(message string) []llms.MessageContent {
+	return []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "system prompt"),
+		llms.TextParts(llms.ChatMessageTypeHuman, message),
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"unknown agent type", "agents:\n  unknown:\n    - responses: [{content: x}]\n"},
+		{"empty responses", "agents:\n  simple:\n    - contains: x\n"},
+		{"empty response", "agents:\n  simple:\n    - responses: [{reasoning: x}]\n"},
+		{"invalid regex", "agents:\n  simple:\n    - regex: \"(\"\n      responses: [{content: x}]\n"},
+		{"invalid delay", "agents:\n  simple:\n    - responses: [{content: x, delay: soon}]\n"},
+		{"tool call without name", "agents:\n  simple:\n    - responses: [{tool_calls: [{args: {a: 1}}]}]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseScript([]byte(tt.script)); err == nil {
+				t.Error("Expected script validation error")
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "script.yml")
+	if err := os.WriteFile(path, []byte(testScript), 0644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	if _, err := LoadScript(path); err != nil {
+		t.Errorf("Failed to load valid script: %v", err)
+	}
+}
+
+func TestScriptedSequence(t *testing.T) {
+	ctx := context.Background()
+	prv := newTestProvider(t)
+	opt := pconfig.OptionsTypePrimaryAgent
+	tools := []llms.Tool{{Type: "function", Function: &llms.FunctionDefinition{Name: "pentester"}}}
+
+	if prv.Type() != provider.ProviderMock {
+		t.Errorf("Expected type %s, got %s", provider.ProviderMock, prv.Type())
+	}
+
+	var chunks []streaming.Chunk
+	resp, err := prv.CallWithTools(ctx, opt, humanChain("scan localhost"), tools,
+		func(ctx context.Context, chunk streaming.Chunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to call provider: %v", err)
+	}
+
+	choice := resp.Choices[0]
+	if choice.Content != "delegating" || len(choice.ToolCalls) != 1 {
+		t.Fatalf("Unexpected first response: %+v", choice)
+	}
+	if call := choice.ToolCalls[0]; call.ID == "" || call.FunctionCall.Name != "pentester" ||
+		call.FunctionCall.Arguments != `{"message":"scan","question":"run nmap"}` {
+		t.Errorf("Unexpected tool call: %+v %+v", call, call.FunctionCall)
+	}
+	if len(chunks) != 3 || chunks[2].Type != streaming.ChunkTypeDone {
+		t.Errorf("Expected text, tool call and done chunks, got %v", chunks)
+	}
+
+	usage := prv.GetUsage(choice.GenerationInfo)
+	if usage.Input <= 0 || usage.Output <= 0 {
+		t.Errorf("Expected estimated usage, got %d/%d", usage.Input, usage.Output)
+	}
+
+	// the last response repeats after the end of the sequence
+	for i := 0; i < 2; i++ {
+		resp, err = prv.CallWithTools(ctx, opt, humanChain("scan localhost"), tools, nil)
+		if err != nil {
+			t.Fatalf("Failed to call provider: %v", err)
+		}
+		if calls := resp.Choices[0].ToolCalls; len(calls) != 1 || calls[0].FunctionCall.Name != "done" {
+			t.Errorf("Expected done tool call, got %+v", calls)
+		}
+	}
+
+	// the loop rule cycles through responses
+	expected := []string{"first", "second", "first"}
+	for _, content := range expected {
+		resp, err = prv.CallEx(ctx, opt, humanChain("something else"), nil)
+		if err != nil {
+			t.Fatalf("Failed to call provider: %v", err)
+		}
+		if resp.Choices[0].Content != content {
+			t.Errorf("Expected %q, got %q", content, resp.Choices[0].Content)
+		}
+	}
+}
+
+func TestScriptedCallAndErrors(t *testing.T) {
+	ctx := context.Background()
+	prv := newTestProvider(t)
+
+	title, err := prv.Call(ctx, pconfig.OptionsTypeSimple, "generate a title for the flow")
+	if err != nil {
+		t.Fatalf("Failed to call provider: %v", err)
+	}
+	if title != "Scan title" {
+		t.Errorf("Expected scripted title, got %q", title)
+	}
+
+	if _, err := prv.Call(ctx, pconfig.OptionsTypeSimple, "unmatched prompt"); !errors.Is(err, ErrNoScriptedResponse) {
+		t.Errorf("Expected no scripted response error, got %v", err)
+	}
+
+	if _, err := prv.CallEx(ctx, pconfig.OptionsTypeReflector, humanChain("anything"), nil); err == nil {
+		t.Error("Expected scripted error")
+	}
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"pentagi/pkg/providers/pconfig"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Script describes scripted responses of the mock provider per agent type
+//
+// Example:
+//
+//	default:
+//	  content: "nothing to do"
+//	agents:
+//	  generator:
+//	    - responses:
+//	        - tool_calls:
+//	            - name: subtask_list
+//	              args: {subtasks: [{title: "Scan", description: "Run nmap"}], message: "plan"}
+//	  primary_agent:
+//	    - contains: "scan"
+//	      responses:
+//	        - tool_calls: [{name: pentester, args: {question: "run nmap", message: "scan"}}]
+//	        - tool_calls: [{name: done, args: {success: true, result: "ok", message: "done"}}]
+type Script struct {
+	Default *Response                               `yaml:"default,omitempty"`
+	Agents  map[pconfig.ProviderOptionsType][]*Rule `yaml:"agents"`
+}
+
+// Rule is a sequence of responses which is used when the last human message matches
+type Rule struct {
+	Name      string      `yaml:"name,omitempty"`
+	Contains  string      `yaml:"contains,omitempty"`
+	Regex     string      `yaml:"regex,omitempty"`
+	Loop      bool        `yaml:"loop,omitempty"`
+	Responses []*Response `yaml:"responses"`
+
+	re *regexp.Regexp
+}
+
+// Response is a single scripted LLM answer, it may contain text, reasoning and tool calls
+// or an error message to simulate provider failures
+type Response struct {
+	Content   string      `yaml:"content,omitempty"`
+	Reasoning string      `yaml:"reasoning,omitempty"`
+	ToolCalls []*ToolCall `yaml:"tool_calls,omitempty"`
+	Error     string      `yaml:"error,omitempty"`
+	Delay     string      `yaml:"delay,omitempty"`
+
+	delay time.Duration
+}
+
+type ToolCall struct {
+	Name string         `yaml:"name"`
+	Args map[string]any `yaml:"args,omitempty"`
+}
+
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock script: %w", err)
+	}
+
+	return ParseScript(data)
+}
+
+func ParseScript(data []byte) (*Script, error) {
+	var script Script
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("failed to parse mock script: %w", err)
+	}
+
+	if err := script.prepare(); err != nil {
+		return nil, fmt.Errorf("invalid mock script: %w", err)
+	}
+
+	return &script, nil
+}
+
+func (s *Script) prepare() error {
+	if s.Default != nil {
+		if err := s.Default.prepare(); err != nil {
+			return fmt.Errorf("default response: %w", err)
+		}
+	}
+
+	for opt, rules := range s.Agents {
+		if !slices.Contains(pconfig.AllAgentTypes, opt) {
+			return fmt.Errorf("unknown agent type '%s'", opt)
+		}
+
+		for idx, rule := range rules {
+			if rule == nil {
+				return fmt.Errorf("agent '%s' rule %d is empty", opt, idx)
+			}
+			if err := rule.prepare(); err != nil {
+				return fmt.Errorf("agent '%s' rule %d: %w", opt, idx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// match returns the index of the first rule which matches the message or -1
+func (s *Script) match(opt pconfig.ProviderOptionsType, message string) int {
+	for idx, rule := range s.Agents[opt] {
+		if rule.matches(message) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func (r *Rule) prepare() error {
+	if len(r.Responses) == 0 {
+		return fmt.Errorf("responses list is empty")
+	}
+
+	if r.Regex != "" {
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("failed to compile regex: %w", err)
+		}
+		r.re = re
+	}
+
+	for idx, resp := range r.Responses {
+		if resp == nil {
+			return fmt.Errorf("response %d is empty", idx)
+		}
+		if err := resp.prepare(); err != nil {
+			return fmt.Errorf("response %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) matches(message string) bool {
+	if r.Contains != "" && !strings.Contains(strings.ToLower(message), strings.ToLower(r.Contains)) {
+		return false
+	}
+
+	if r.re != nil && !r.re.MatchString(message) {
+		return false
+	}
+
+	return true
+}
+
+func (r *Response) prepare() error {
+	if r.Content == "" && len(r.ToolCalls) == 0 && r.Error == "" {
+		return fmt.Errorf("one of content, tool_calls or error must be set")
+	}
+
+	for idx, toolCall := range r.ToolCalls {
+		if toolCall == nil || toolCall.Name == "" {
+			return fmt.Errorf("tool call %d has no name", idx)
+		}
+	}
+
+	if r.Delay != "" {
+		delay, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return fmt.Errorf("failed to parse delay: %w", err)
+		}
+		r.delay = delay
+	}
+
+	return nil
+}
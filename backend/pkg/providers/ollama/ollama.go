@@ -268,7 +268,7 @@ func (p *ollamaProvider) CallWithTools(
 	streamCb streaming.Callback,
 ) (*llms.ContentResponse, error) {
 	return provider.WrapGenerateContent(
-		ctx, p, opt, p.llm.GenerateContent, chain,
+		ctx, p, opt, p.generatorWithTools(opt), chain,
 		append([]llms.CallOption{
 			llms.WithTools(tools),
 			llms.WithStreamingFunc(streamCb),
@@ -276,7 +276,12 @@ func (p *ollamaProvider) CallWithTools(
 	)
 }
 
-func (p *ollamaProvider) GetUsage(info map[string]any) (int64, int64) {
+// generatorWithTools emulates tool calling via the prompt for models without native function calling
+func (p *ollamaProvider) generatorWithTools(opt pconfig.ProviderOptionsType) provider.GenerateContentFunc {
+	return provider.WrapToolCallingEmulation(p.llm.GenerateContent, p.providerConfig.GetToolCallingForType(opt))
+}
+
+func (p *ollamaProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["PromptTokens"]; ok {
@@ -301,5 +306,5 @@ func (p *ollamaProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	return provider.CallUsage{Input: inputTokens, Output: outputTokens}
 }
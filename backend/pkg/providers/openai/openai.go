@@ -157,7 +157,7 @@ func (p *openaiProvider) CallWithTools(
 	)
 }
 
-func (p *openaiProvider) GetUsage(info map[string]any) (int64, int64) {
+func (p *openaiProvider) GetUsage(info map[string]any) provider.CallUsage {
 	var inputTokens, outputTokens int64
 
 	if value, ok := info["PromptTokens"]; ok {
@@ -182,5 +182,5 @@ func (p *openaiProvider) GetUsage(info map[string]any) (int64, int64) {
 		}
 	}
 
-	return inputTokens, outputTokens
+	return provider.CallUsage{Input: inputTokens, Output: outputTokens}
 }
@@ -46,15 +46,65 @@ var AllAgentTypes = []ProviderOptionsType{
 }
 
 type ModelConfig struct {
-	Name        string     `json:"name,omitempty" yaml:"name,omitempty"`
-	Description *string    `json:"description,omitempty" yaml:"description,omitempty"`
-	ReleaseDate *time.Time `json:"release_date,omitempty" yaml:"release_date,omitempty"`
-	Thinking    *bool      `json:"thinking,omitempty" yaml:"thinking,omitempty"`
-	Price       *PriceInfo `json:"price,omitempty" yaml:"price,omitempty"`
+	Name          string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Description   *string         `json:"description,omitempty" yaml:"description,omitempty"`
+	ReleaseDate   *time.Time      `json:"release_date,omitempty" yaml:"release_date,omitempty"`
+	Thinking      *bool           `json:"thinking,omitempty" yaml:"thinking,omitempty"`
+	ContextWindow int             `json:"context_window,omitempty" yaml:"context_window,omitempty"`
+	Tokenizer     TokenizerFamily `json:"tokenizer,omitempty" yaml:"tokenizer,omitempty"`
+	Price         *PriceInfo      `json:"price,omitempty" yaml:"price,omitempty"`
 }
 
 type ModelsConfig []ModelConfig
 
+// Find returns the model config by name or nil if the model is unknown
+func (mc ModelsConfig) Find(name string) *ModelConfig {
+	for idx := range mc {
+		if mc[idx].Name == name {
+			return &mc[idx]
+		}
+	}
+
+	return nil
+}
+
+// ContextLimits describes the context window of the agent model in tokens
+type ContextLimits struct {
+	ContextWindow int
+	MaxTokens     int
+	Tokenizer     TokenizerFamily
+}
+
+// TokenizerFamily is used to estimate tokens count of the text without the model tokenizer
+type TokenizerFamily string
+
+const (
+	TokenizerOpenAI  TokenizerFamily = "openai"
+	TokenizerClaude  TokenizerFamily = "claude"
+	TokenizerGemini  TokenizerFamily = "gemini"
+	TokenizerLlama   TokenizerFamily = "llama"
+	TokenizerQwen    TokenizerFamily = "qwen"
+	TokenizerMistral TokenizerFamily = "mistral"
+)
+
+// defaultBytesPerToken is a pessimistic estimation for unknown tokenizers
+const defaultBytesPerToken = 3.0
+
+// BytesPerToken returns the average number of bytes per token for mixed text,
+// code and terminal output which agents usually work with
+func (tf TokenizerFamily) BytesPerToken() float64 {
+	switch tf {
+	case TokenizerOpenAI, TokenizerGemini:
+		return 3.8
+	case TokenizerLlama, TokenizerQwen:
+		return 3.6
+	case TokenizerClaude, TokenizerMistral:
+		return 3.4
+	default:
+		return defaultBytesPerToken
+	}
+}
+
 type PriceInfo struct {
 	Input  float64 `json:"input,omitempty" yaml:"input,omitempty"`
 	Output float64 `json:"output,omitempty" yaml:"output,omitempty"`
@@ -65,6 +115,31 @@ type ReasoningConfig struct {
 	MaxTokens int                  `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
 }
 
+// CacheConfig controls prompt cache breakpoints placement for providers which support it
+type CacheConfig struct {
+	System bool `json:"system,omitempty" yaml:"system,omitempty"`
+	Tools  bool `json:"tools,omitempty" yaml:"tools,omitempty"`
+	Chain  bool `json:"chain,omitempty" yaml:"chain,omitempty"`
+}
+
+func (cc *CacheConfig) IsEnabled() bool {
+	return cc != nil && (cc.System || cc.Tools || cc.Chain)
+}
+
+// ToolCallingMode defines how tools are passed to the model,
+// emulated modes render tool schemas into the prompt for models without native function calling
+type ToolCallingMode string
+
+const (
+	ToolCallingNative       ToolCallingMode = "native"
+	ToolCallingEmulatedJSON ToolCallingMode = "emulated_json"
+	ToolCallingEmulatedXML  ToolCallingMode = "emulated_xml"
+)
+
+func (m ToolCallingMode) IsEmulated() bool {
+	return m == ToolCallingEmulatedJSON || m == ToolCallingEmulatedXML
+}
+
 // AgentConfig represents the configuration for a single agent
 type AgentConfig struct {
 	Model             string          `json:"model,omitempty" yaml:"model,omitempty"`
@@ -82,6 +157,10 @@ type AgentConfig struct {
 	ResponseMIMEType  string          `json:"response_mime_type,omitempty" yaml:"response_mime_type,omitempty"`
 	Reasoning         ReasoningConfig `json:"reasoning,omitempty" yaml:"reasoning,omitempty"`
 	Price             *PriceInfo      `json:"price,omitempty" yaml:"price,omitempty"`
+	Cache             *CacheConfig    `json:"cache,omitempty" yaml:"cache,omitempty"`
+	ToolCalling       ToolCallingMode `json:"tool_calling,omitempty" yaml:"tool_calling,omitempty"`
+	ContextWindow     int             `json:"context_window,omitempty" yaml:"context_window,omitempty"`
+	Tokenizer         TokenizerFamily `json:"tokenizer,omitempty" yaml:"tokenizer,omitempty"`
 	raw               map[string]any  `json:"-" yaml:"-"`
 }
 
@@ -202,6 +281,14 @@ func (mc *ModelConfig) UnmarshalJSON(data []byte) error {
 		mc.Thinking = &thinking
 	}
 
+	if contextWindow, ok := raw["context_window"].(float64); ok {
+		mc.ContextWindow = int(contextWindow)
+	}
+
+	if tokenizer, ok := raw["tokenizer"].(string); ok {
+		mc.Tokenizer = TokenizerFamily(tokenizer)
+	}
+
 	if dateStr, ok := raw["release_date"].(string); ok && dateStr != "" {
 		parsedDate, err := time.Parse("2006-01-02", dateStr)
 		if err != nil {
@@ -245,6 +332,14 @@ func (mc *ModelConfig) UnmarshalYAML(value *yaml.Node) error {
 		mc.Thinking = &thinking
 	}
 
+	if contextWindow, ok := raw["context_window"].(int); ok {
+		mc.ContextWindow = contextWindow
+	}
+
+	if tokenizer, ok := raw["tokenizer"].(string); ok {
+		mc.Tokenizer = TokenizerFamily(tokenizer)
+	}
+
 	// Handle release_date - YAML can parse it as string or time.Time
 	if dateValue, ok := raw["release_date"]; ok && dateValue != nil {
 		switch v := dateValue.(type) {
@@ -293,6 +388,12 @@ func (mc ModelConfig) MarshalJSON() ([]byte, error) {
 	if mc.ReleaseDate != nil {
 		aux["release_date"] = mc.ReleaseDate.Format("2006-01-02")
 	}
+	if mc.ContextWindow != 0 {
+		aux["context_window"] = mc.ContextWindow
+	}
+	if mc.Tokenizer != "" {
+		aux["tokenizer"] = mc.Tokenizer
+	}
 	if mc.Price != nil {
 		aux["price"] = mc.Price
 	}
@@ -316,6 +417,12 @@ func (mc ModelConfig) MarshalYAML() (any, error) {
 	if mc.ReleaseDate != nil {
 		aux["release_date"] = mc.ReleaseDate.Format("2006-01-02")
 	}
+	if mc.ContextWindow != 0 {
+		aux["context_window"] = mc.ContextWindow
+	}
+	if mc.Tokenizer != "" {
+		aux["tokenizer"] = mc.Tokenizer
+	}
 	if mc.Price != nil {
 		aux["price"] = mc.Price
 	}
@@ -506,6 +613,18 @@ func (ac *AgentConfig) marshalMap() map[string]any {
 	if ac.Price != nil {
 		output["price"] = ac.Price
 	}
+	if ac.Cache != nil {
+		output["cache"] = ac.Cache
+	}
+	if ac.ToolCalling != "" {
+		output["tool_calling"] = ac.ToolCalling
+	}
+	if ac.ContextWindow != 0 {
+		output["context_window"] = ac.ContextWindow
+	}
+	if ac.Tokenizer != "" {
+		output["tokenizer"] = ac.Tokenizer
+	}
 
 	return output
 }
@@ -674,6 +793,93 @@ func (pc *ProviderConfig) GetPriceInfoForType(optType ProviderOptionsType) *Pric
 	return nil
 }
 
+func (pc *ProviderConfig) GetCacheConfigForType(optType ProviderOptionsType) *CacheConfig {
+	if agentConfig := pc.getAgentConfigForType(optType); agentConfig != nil {
+		return agentConfig.Cache
+	}
+
+	return nil
+}
+
+// GetToolCallingForType returns the tool calling mode of the agent, native mode is used by default
+func (pc *ProviderConfig) GetToolCallingForType(optType ProviderOptionsType) ToolCallingMode {
+	if agentConfig := pc.getAgentConfigForType(optType); agentConfig != nil && agentConfig.ToolCalling != "" {
+		return agentConfig.ToolCalling
+	}
+
+	return ToolCallingNative
+}
+
+// GetContextLimitsForType returns the context window and tokenizer family of the agent model,
+// the agent settings override values from the models list which is used as a fallback
+func (pc *ProviderConfig) GetContextLimitsForType(
+	optType ProviderOptionsType,
+	model string,
+	models ModelsConfig,
+) ContextLimits {
+	var limits ContextLimits
+
+	if modelConfig := models.Find(model); modelConfig != nil {
+		limits.ContextWindow = modelConfig.ContextWindow
+		limits.Tokenizer = modelConfig.Tokenizer
+	}
+
+	if agentConfig := pc.getAgentConfigForType(optType); agentConfig != nil {
+		limits.MaxTokens = agentConfig.MaxTokens
+		if agentConfig.ContextWindow > 0 {
+			limits.ContextWindow = agentConfig.ContextWindow
+		}
+		if agentConfig.Tokenizer != "" {
+			limits.Tokenizer = agentConfig.Tokenizer
+		}
+	}
+
+	return limits
+}
+
+func (pc *ProviderConfig) getAgentConfigForType(optType ProviderOptionsType) *AgentConfig {
+	if pc == nil {
+		return nil
+	}
+
+	switch optType {
+	case OptionsTypeSimple:
+		return pc.Simple
+	case OptionsTypeSimpleJSON:
+		if pc.SimpleJSON != nil {
+			return pc.SimpleJSON
+		}
+		return pc.Simple
+	case OptionsTypePrimaryAgent:
+		return pc.PrimaryAgent
+	case OptionsTypeAssistant:
+		if pc.Assistant != nil {
+			return pc.Assistant
+		}
+		return pc.PrimaryAgent
+	case OptionsTypeGenerator:
+		return pc.Generator
+	case OptionsTypeRefiner:
+		return pc.Refiner
+	case OptionsTypeAdviser:
+		return pc.Adviser
+	case OptionsTypeReflector:
+		return pc.Reflector
+	case OptionsTypeSearcher:
+		return pc.Searcher
+	case OptionsTypeEnricher:
+		return pc.Enricher
+	case OptionsTypeCoder:
+		return pc.Coder
+	case OptionsTypeInstaller:
+		return pc.Installer
+	case OptionsTypePentester:
+		return pc.Pentester
+	default:
+		return nil
+	}
+}
+
 func (pc *ProviderConfig) BuildOptionsMap() map[ProviderOptionsType][]llms.CallOption {
 	if pc == nil {
 		return nil
@@ -1080,6 +1080,111 @@ func TestProvidersConfig_GetOptionsForType(t *testing.T) {
 	}
 }
 
+func TestProvidersConfig_GetCacheConfigForType(t *testing.T) {
+	configData := `
+primary_agent:
+  model: test-model
+  cache:
+    system: true
+    tools: true
+    chain: true
+simple:
+  model: test-model
+  cache:
+    system: true
+`
+	config, err := LoadConfigData([]byte(configData), nil)
+	require.NoError(t, err)
+
+	cache := config.GetCacheConfigForType(OptionsTypePrimaryAgent)
+	require.NotNil(t, cache)
+	assert.True(t, cache.IsEnabled())
+	assert.Equal(t, CacheConfig{System: true, Tools: true, Chain: true}, *cache)
+
+	// assistant and simple_json fall back to primary_agent and simple configs
+	assert.Equal(t, cache, config.GetCacheConfigForType(OptionsTypeAssistant))
+	assert.Equal(t, &CacheConfig{System: true}, config.GetCacheConfigForType(OptionsTypeSimpleJSON))
+
+	assert.Nil(t, config.GetCacheConfigForType(OptionsTypePentester))
+	assert.False(t, config.GetCacheConfigForType(OptionsTypePentester).IsEnabled())
+	assert.Nil(t, (*ProviderConfig)(nil).GetCacheConfigForType(OptionsTypeSimple))
+
+	data, err := json.Marshal(config.PrimaryAgent)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cache":{"chain":true,"system":true,"tools":true}`)
+
+	// cache settings must not produce call options
+	assert.Len(t, config.PrimaryAgent.BuildOptions(), 1)
+}
+
+func TestProvidersConfig_GetToolCallingForType(t *testing.T) {
+	configData := `
+primary_agent:
+  model: test-model
+  tool_calling: emulated_xml
+coder:
+  model: test-model
+  tool_calling: emulated_json
+`
+	config, err := LoadConfigData([]byte(configData), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, ToolCallingEmulatedXML, config.GetToolCallingForType(OptionsTypePrimaryAgent))
+	assert.Equal(t, ToolCallingEmulatedXML, config.GetToolCallingForType(OptionsTypeAssistant))
+	assert.Equal(t, ToolCallingEmulatedJSON, config.GetToolCallingForType(OptionsTypeCoder))
+	assert.Equal(t, ToolCallingNative, config.GetToolCallingForType(OptionsTypePentester))
+	assert.Equal(t, ToolCallingNative, (*ProviderConfig)(nil).GetToolCallingForType(OptionsTypeCoder))
+
+	assert.True(t, config.GetToolCallingForType(OptionsTypeCoder).IsEmulated())
+	assert.False(t, ToolCallingNative.IsEmulated())
+
+	data, err := json.Marshal(config.Coder)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tool_calling":"emulated_json"`)
+
+	// tool calling mode must not produce call options
+	assert.Len(t, config.Coder.BuildOptions(), 1)
+}
+
+func TestProvidersConfig_GetContextLimitsForType(t *testing.T) {
+	models, err := LoadModelsConfigData([]byte(`
+- name: big-model
+  context_window: 200000
+  tokenizer: claude
+- name: unknown-model
+`))
+	require.NoError(t, err)
+	require.Equal(t, 200000, models[0].ContextWindow)
+	assert.Nil(t, models.Find("missing-model"))
+
+	data, err := json.Marshal(models[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"context_window":200000`)
+	assert.Contains(t, string(data), `"tokenizer":"claude"`)
+
+	config, err := LoadConfigData([]byte(`
+primary_agent:
+  model: big-model
+  max_tokens: 4000
+coder:
+  model: unknown-model
+  context_window: 8192
+  tokenizer: llama
+`), nil)
+	require.NoError(t, err)
+
+	limits := config.GetContextLimitsForType(OptionsTypePrimaryAgent, "big-model", models)
+	assert.Equal(t, ContextLimits{ContextWindow: 200000, MaxTokens: 4000, Tokenizer: TokenizerClaude}, limits)
+
+	// agent settings override the models list
+	limits = config.GetContextLimitsForType(OptionsTypeCoder, "unknown-model", models)
+	assert.Equal(t, ContextLimits{ContextWindow: 8192, Tokenizer: TokenizerLlama}, limits)
+
+	assert.Zero(t, config.GetContextLimitsForType(OptionsTypePentester, "missing-model", models).ContextWindow)
+	assert.Equal(t, 3.4, TokenizerClaude.BytesPerToken())
+	assert.Equal(t, defaultBytesPerToken, TokenizerFamily("").BytesPerToken())
+}
+
 func TestAgentConfig_MarshalJSON(t *testing.T) {
 	tests := []struct {
 		name    string
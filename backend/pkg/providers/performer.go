@@ -16,6 +16,7 @@ import (
 	obs "pentagi/pkg/observability"
 	"pentagi/pkg/observability/langfuse"
 	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
 	"pentagi/pkg/templates"
 	"pentagi/pkg/tools"
 
@@ -54,6 +55,8 @@ func (fp *flowProvider) performAgentChain(
 
 	var (
 		wantToStop        bool
+		iterations        int
+		toolCalls         int
 		detector          = &repeatingDetector{}
 		summarizerHandler = fp.GetSummarizeResultHandler(taskID, subtaskID)
 	)
@@ -73,6 +76,11 @@ func (fp *flowProvider) performAgentChain(
 
 	logger := logrus.WithContext(ctx).WithFields(fields)
 
+	ctx, wd := enterAgentChain(ctx)
+	if reason := wd.checkDelegation(); reason != "" {
+		return fp.stopAgentChain(ctx, logger, nil, reason, summarizerHandler)
+	}
+
 	executionContext, err := fp.getExecutionContext(ctx, taskID, subtaskID)
 	if err != nil {
 		logger.WithError(err).Error("failed to get execution context")
@@ -82,8 +90,25 @@ func (fp *flowProvider) performAgentChain(
 	groupID := fmt.Sprintf("flow-%d", fp.flowID)
 	toolTypeMapping := tools.GetToolTypeMapping()
 
+	budget := fp.getContextBudget(optAgentType, executor.Tools())
+	if summarizer != nil {
+		summarizer = summarizer.WithContextBudget(budget)
+	}
+
 	for {
+		if reason := wd.check(iterations, toolCalls); reason != "" {
+			return fp.stopAgentChain(ctx, logger, chain, reason, summarizerHandler)
+		}
+		iterations++
+
 		result, err := fp.callWithRetries(ctx, chain, optAgentType, executor)
+		if provider.IsContextLengthError(err) && summarizer != nil {
+			logger.WithError(err).Warn("chain exceeds model context window, performing emergency summarization")
+			chain, err = fp.summarizeChainToFit(ctx, chainID, chain, budget, summarizer, summarizerHandler)
+			if err == nil {
+				result, err = fp.callWithRetries(ctx, chain, optAgentType, executor)
+			}
+		}
 		if err != nil {
 			logger.WithError(err).Error("failed to call agent chain")
 			return err
@@ -134,6 +159,7 @@ func (fp *flowProvider) performAgentChain(
 				continue
 			}
 
+			toolCalls++
 			funcName := toolCall.FunctionCall.Name
 			response, err := fp.execToolCall(ctx, chainID, idx, result, detector, executor)
 
@@ -392,6 +418,9 @@ func (fp *flowProvider) callWithRetries(
 		}
 		if err == nil {
 			break
+		} else if provider.IsContextLengthError(err) {
+			// retries can't help here, the chain should be summarized by the caller
+			return nil, err
 		} else {
 			errs = append(errs, err)
 		}
@@ -612,6 +641,33 @@ func (fp *flowProvider) processAssistantResult(
 	return nil
 }
 
+// summarizeChainToFit summarizes the chain which was rejected by the provider because of the context length,
+// the budget is derived from the rejected chain size because the token estimation was too optimistic
+func (fp *flowProvider) summarizeChainToFit(
+	ctx context.Context,
+	chainID int64,
+	chain []llms.MessageContent,
+	budget csum.ContextBudget,
+	summarizer csum.Summarizer,
+	summarizerHandler tools.SummarizeHandler,
+) ([]llms.MessageContent, error) {
+	emergencyBudget, err := csum.NewEmergencyBudget(budget, chain)
+	if err != nil {
+		return chain, fmt.Errorf("failed to calculate emergency budget: %w", err)
+	}
+
+	chain, err = summarizer.WithContextBudget(emergencyBudget).SummarizeChain(ctx, summarizerHandler, chain)
+	if err != nil {
+		return chain, fmt.Errorf("failed to summarize chain: %w", err)
+	}
+
+	if err := fp.updateMsgChain(ctx, chainID, chain); err != nil {
+		return chain, err
+	}
+
+	return chain, nil
+}
+
 func (fp *flowProvider) updateMsgChain(ctx context.Context, chainID int64, chain []llms.MessageContent) error {
 	chainBlob, err := json.Marshal(chain)
 	if err != nil {
@@ -630,15 +686,17 @@ func (fp *flowProvider) updateMsgChain(ctx context.Context, chainID int64, chain
 }
 
 func (fp *flowProvider) updateMsgChainUsage(ctx context.Context, chainID int64, info map[string]any) error {
-	inputTokens, outputTokens := int64(0), int64(0)
+	var usage provider.CallUsage
 	if info != nil {
-		inputTokens, outputTokens = fp.GetUsage(info)
+		usage = fp.GetUsage(info)
 	}
 
 	_, err := fp.db.UpdateMsgChainUsage(ctx, database.UpdateMsgChainUsageParams{
-		UsageIn:  inputTokens,
-		UsageOut: outputTokens,
-		ID:       chainID,
+		UsageIn:         usage.Input,
+		UsageOut:        usage.Output,
+		UsageCacheRead:  usage.CacheRead,
+		UsageCacheWrite: usage.CacheWrite,
+		ID:              chainID,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update msg chain usage in DB: %w", err)
@@ -11,6 +11,7 @@ import (
 	"pentagi/pkg/cast"
 	"pentagi/pkg/database"
 	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
 	"pentagi/pkg/tools"
 
 	"github.com/sirupsen/logrus"
@@ -173,7 +174,7 @@ func (fp *flowProvider) performSubtasksRefiner(
 	taskID int64,
 	plannedSubtasks []database.Subtask,
 	systemRefinerTmpl, userRefinerTmpl, input string,
-) ([]tools.SubtaskInfo, error) {
+) ([]tools.SubtaskInfoPatch, error) {
 	var (
 		subtaskPatch tools.SubtaskPatch
 		chain        []llms.MessageContent
@@ -333,20 +334,19 @@ func (fp *flowProvider) performSubtasksRefiner(
 		"operations":   len(subtaskPatch.Operations),
 	}).Debug("successfully applied subtask patch")
 
-	subtasks := convertSubtaskInfoPatch(result)
 	if agentCtx, ok := tools.GetAgentContext(ctx); ok {
 		fp.agentLog.PutLog(
 			ctx,
 			agentCtx.ParentAgentType,
 			agentCtx.CurrentAgentType,
 			input,
-			fp.subtasksToMarkdown(subtasks),
+			fp.subtasksToMarkdown(convertSubtaskInfoPatch(result)),
 			&taskID,
 			nil,
 		)
 	}
 
-	return subtasks, nil
+	return result, nil
 }
 
 func (fp *flowProvider) performCoder(
@@ -410,7 +410,9 @@ func (fp *flowProvider) performCoder(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		codeResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task coder result: %w", err)
 	}
 
@@ -484,7 +486,9 @@ func (fp *flowProvider) performInstaller(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		maintenanceResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task installer result: %w", err)
 	}
 
@@ -540,7 +544,9 @@ func (fp *flowProvider) performMemorist(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		memoristResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task memorist result: %w", err)
 	}
 
@@ -626,7 +632,9 @@ func (fp *flowProvider) performPentester(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		hackResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task pentester result: %w", err)
 	}
 
@@ -688,7 +696,9 @@ func (fp *flowProvider) performSearcher(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		searchResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task searcher result: %w", err)
 	}
 
@@ -755,7 +765,9 @@ func (fp *flowProvider) performEnricher(
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
 	err = fp.performAgentChain(ctx, optAgentType, msgChainID, taskID, subtaskID, chain, executor, fp.summarizer)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		enricherResult.Result = result
+	} else if err != nil {
 		return "", fmt.Errorf("failed to get task enricher result: %w", err)
 	}
 
@@ -818,10 +830,10 @@ func (fp *flowProvider) performSimpleChain(
 	}
 
 	var parts []string
-	var inputTokens, outputTokens int64
+	var usage provider.CallUsage
 	for _, choice := range resp.Choices {
 		parts = append(parts, choice.Content)
-		inputTokens, outputTokens = fp.GetUsage(choice.GenerationInfo)
+		usage = fp.GetUsage(choice.GenerationInfo)
 	}
 	chain = append(chain, llms.TextParts(llms.ChatMessageTypeAI, parts...))
 
@@ -831,15 +843,17 @@ func (fp *flowProvider) performSimpleChain(
 	}
 
 	_, err = fp.db.CreateMsgChain(ctx, database.CreateMsgChainParams{
-		Type:          msgChainType,
-		Model:         fp.Model(opt),
-		ModelProvider: string(fp.Type()),
-		UsageIn:       inputTokens,
-		UsageOut:      outputTokens,
-		Chain:         chainBlob,
-		FlowID:        fp.flowID,
-		TaskID:        database.Int64ToNullInt64(taskID),
-		SubtaskID:     database.Int64ToNullInt64(subtaskID),
+		Type:            msgChainType,
+		Model:           fp.Model(opt),
+		ModelProvider:   string(fp.Type()),
+		UsageIn:         usage.Input,
+		UsageOut:        usage.Output,
+		UsageCacheRead:  usage.CacheRead,
+		UsageCacheWrite: usage.CacheWrite,
+		Chain:           chainBlob,
+		FlowID:          fp.flowID,
+		TaskID:          database.Int64ToNullInt64(taskID),
+		SubtaskID:       database.Int64ToNullInt64(subtaskID),
 	})
 
 	return strings.Join(parts, "\n\n"), nil
@@ -80,7 +80,7 @@ type FlowProvider interface {
 
 	GetTaskTitle(ctx context.Context, input string) (string, error)
 	GenerateSubtasks(ctx context.Context, taskID int64) ([]tools.SubtaskInfo, error)
-	RefineSubtasks(ctx context.Context, taskID int64) ([]tools.SubtaskInfo, error)
+	RefineSubtasks(ctx context.Context, taskID int64) ([]tools.SubtaskInfoPatch, error)
 	GetTaskResult(ctx context.Context, taskID int64) (*tools.TaskResult, error)
 
 	PrepareAgentChain(ctx context.Context, taskID, subtaskID int64) (int64, error)
@@ -283,7 +283,7 @@ func (fp *flowProvider) GenerateSubtasks(ctx context.Context, taskID int64) ([]t
 	return subtasks, nil
 }
 
-func (fp *flowProvider) RefineSubtasks(ctx context.Context, taskID int64) ([]tools.SubtaskInfo, error) {
+func (fp *flowProvider) RefineSubtasks(ctx context.Context, taskID int64) ([]tools.SubtaskInfoPatch, error) {
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "providers.flowProvider.RefineSubtasks")
 	defer span.End()
 
@@ -755,10 +755,23 @@ func (fp *flowProvider) PerformAgentChain(ctx context.Context, taskID, subtaskID
 	}
 
 	ctx = tools.PutAgentContext(ctx, msgChainType)
+	ctx = putSubtaskWatchdog(ctx)
 	err = fp.performAgentChain(
 		ctx, optAgentType, msgChain.ID, &taskID, &subtaskID, chain, executor, fp.summarizer,
 	)
-	if err != nil {
+	if result, ok := limitResult(err); ok {
+		notifyLimitReached(ctx, taskID, subtaskID, err)
+
+		// the subtask is finished with the forced summary in the same way as by the done function
+		args, err := json.Marshal(tools.Done{Success: true, Result: result, Message: result})
+		if err != nil {
+			return PerformResultError, wrapErrorEndSpan(ctx, executorSpan, "failed to marshal forced result", err)
+		}
+		if _, err := cfg.Barrier(ctx, tools.FinalyToolName, args); err != nil {
+			return PerformResultError, fmt.Errorf("failed to set forced result: %w", err)
+		}
+		return performResult, nil
+	} else if err != nil {
 		return PerformResultError, wrapErrorEndSpan(ctx, executorSpan, "failed to perform primary agent chain", err)
 	}
 
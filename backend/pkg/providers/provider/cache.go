@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"sync/atomic"
+
+	"pentagi/pkg/cast"
+	"pentagi/pkg/providers/pconfig"
+
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+const (
+	GenerationInfoCacheReadTokens  = "CacheReadInputTokens"
+	GenerationInfoCacheWriteTokens = "CacheWriteInputTokens"
+)
+
+// CallUsage represents token usage of a single LLM call,
+// cache tokens are reported separately from the input tokens
+type CallUsage struct {
+	Input      int64
+	Output     int64
+	CacheRead  int64
+	CacheWrite int64
+}
+
+// CacheBreakpoints describes where prompt cache breakpoints should be placed for a call
+type CacheBreakpoints struct {
+	System bool
+	Tools  bool
+	// Message is a wire index of the last request message of the stable chain prefix or -1
+	Message int
+}
+
+// CacheState is passed through the call context to the provider transport
+// which places breakpoints into the request and collects cache usage from the response
+type CacheState struct {
+	Breakpoints CacheBreakpoints
+	readTokens  atomic.Int64
+	writeTokens atomic.Int64
+}
+
+type cacheStateKey struct{}
+
+func WithCacheState(ctx context.Context, state *CacheState) context.Context {
+	return context.WithValue(ctx, cacheStateKey{}, state)
+}
+
+func GetCacheState(ctx context.Context) *CacheState {
+	state, _ := ctx.Value(cacheStateKey{}).(*CacheState)
+	return state
+}
+
+func (s *CacheState) AddUsage(read, write int64) {
+	s.readTokens.Add(read)
+	s.writeTokens.Add(write)
+}
+
+// MessageCounter returns the number of wire messages produced by the chain message of the provider,
+// it's used to map the stable chain prefix to the request messages index
+type MessageCounter func(msg llms.MessageContent) int
+
+// NewCacheBreakpoints places breakpoints on the system prompt, tool definitions and the end
+// of the stable chain prefix: all sections except the last one and the last section without
+// its newest body pair which may still be rewritten by the summarizer or the tool handler
+func NewCacheBreakpoints(
+	cfg *pconfig.CacheConfig,
+	chain []llms.MessageContent,
+	count MessageCounter,
+) CacheBreakpoints {
+	breakpoints := CacheBreakpoints{Message: -1}
+	if !cfg.IsEnabled() {
+		return breakpoints
+	}
+
+	breakpoints.System = cfg.System
+	breakpoints.Tools = cfg.Tools
+	if !cfg.Chain {
+		return breakpoints
+	}
+
+	ast, err := cast.NewChainAST(chain, false)
+	if err != nil || len(ast.Sections) == 0 {
+		return breakpoints
+	}
+
+	size := 0
+	sections := ast.Sections
+	for _, section := range sections[:len(sections)-1] {
+		size += len(section.Messages())
+	}
+
+	last := sections[len(sections)-1]
+	size += len(last.Header.Messages())
+	if len(last.Body) > 1 {
+		for _, pair := range last.Body[:len(last.Body)-1] {
+			size += len(pair.Messages())
+		}
+	}
+
+	// the AST may drop or add messages in a broken chain, so we skip the breakpoint
+	if size == 0 || len(ast.Messages()) != len(chain) {
+		return breakpoints
+	}
+
+	wireSize := 0
+	for _, msg := range chain[:size] {
+		wireSize += count(msg)
+	}
+	breakpoints.Message = wireSize - 1
+
+	return breakpoints
+}
+
+// WrapPromptCache returns a generate function which passes cache breakpoints to the provider
+// transport via context and reports cache usage in the generation info of the response choices
+func WrapPromptCache(fn GenerateContentFunc, cfg *pconfig.CacheConfig, count MessageCounter) GenerateContentFunc {
+	if !cfg.IsEnabled() {
+		return fn
+	}
+
+	return func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		state := &CacheState{Breakpoints: NewCacheBreakpoints(cfg, messages, count)}
+		resp, err := fn(WithCacheState(ctx, state), messages, options...)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+
+		read, write := state.readTokens.Load(), state.writeTokens.Load()
+		for _, choice := range resp.Choices {
+			if choice.GenerationInfo == nil {
+				choice.GenerationInfo = make(map[string]any)
+			}
+			choice.GenerationInfo[GenerationInfoCacheReadTokens] = read
+			choice.GenerationInfo[GenerationInfoCacheWriteTokens] = write
+		}
+
+		return resp, nil
+	}
+}
+
+// GetCacheUsage reads cache tokens which were stored by WrapPromptCache
+func GetCacheUsage(info map[string]any) (int64, int64) {
+	return getInt64(info, GenerationInfoCacheReadTokens), getInt64(info, GenerationInfoCacheWriteTokens)
+}
+
+func getInt64(info map[string]any, key string) int64 {
+	switch v := info[key].(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+
+	return 0
+}
@@ -25,6 +25,8 @@ const (
 	ProviderBedrock   ProviderType = "bedrock"
 	ProviderOllama    ProviderType = "ollama"
 	ProviderCustom    ProviderType = "custom"
+	ProviderReplay    ProviderType = "replay"
+	ProviderMock      ProviderType = "mock"
 )
 
 type ProviderName string
@@ -40,12 +42,27 @@ const (
 	DefaultProviderNameBedrock   ProviderName = ProviderName(ProviderBedrock)
 	DefaultProviderNameOllama    ProviderName = ProviderName(ProviderOllama)
 	DefaultProviderNameCustom    ProviderName = ProviderName(ProviderCustom)
+	DefaultProviderNameReplay    ProviderName = ProviderName(ProviderReplay)
+	DefaultProviderNameMock      ProviderName = ProviderName(ProviderMock)
 )
 
+// IsDefault reports whether the name refers to the default provider which is shared by all users,
+// the default providers take precedence over the user defined providers with the same name
+func (p ProviderName) IsDefault() bool {
+	switch p {
+	case DefaultProviderNameOpenAI, DefaultProviderNameAnthropic, DefaultProviderNameGemini,
+		DefaultProviderNameBedrock, DefaultProviderNameOllama, DefaultProviderNameCustom,
+		DefaultProviderNameReplay, DefaultProviderNameMock:
+		return true
+	default:
+		return false
+	}
+}
+
 type Provider interface {
 	Type() ProviderType
 	Model(opt pconfig.ProviderOptionsType) string
-	GetUsage(info map[string]any) (int64, int64)
+	GetUsage(info map[string]any) CallUsage
 
 	Call(ctx context.Context, opt pconfig.ProviderOptionsType, prompt string) (string, error)
 	CallEx(
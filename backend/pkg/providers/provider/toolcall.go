@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"pentagi/pkg/providers/pconfig"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const emulatedToolCallIDPrefix = "call_emulated_"
+
+var (
+	emulatedToolCallCounter atomic.Int64
+
+	xmlToolCallRegex  = regexp.MustCompile(`(?s)<tool_call>\s*(.*?)\s*(?:</tool_call>|$)`)
+	codeFenceRegex    = regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)\\s*```")
+	toolResultEscaper = strings.NewReplacer("</tool_result>", "&lt;/tool_result&gt;")
+
+	brokenToolCallRegex = regexp.MustCompile(`(?s)"name"\s*:\s*"([^"]+)".*?"arguments"\s*:\s*(.*?)\s*}?\s*$`)
+)
+
+// emulatedToolCall is a single tool call of the envelope in both JSON and XML modes
+type emulatedToolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// emulatedEnvelope is a strict JSON response envelope of the emulated_json mode
+type emulatedEnvelope struct {
+	Content   *string            `json:"content,omitempty"`
+	ToolCalls []emulatedToolCall `json:"tool_calls"`
+}
+
+// WrapToolCallingEmulation returns a generate function which doesn't pass tools to the model API,
+// it renders tool schemas into the system prompt, converts tool calls history into plain text messages
+// and parses the tool call envelope from the model response back into llms.ToolCall
+func WrapToolCallingEmulation(fn GenerateContentFunc, mode pconfig.ToolCallingMode) GenerateContentFunc {
+	if !mode.IsEmulated() {
+		return fn
+	}
+
+	return func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		opts := llms.CallOptions{}
+		for _, option := range options {
+			option(&opts)
+		}
+		if len(opts.Tools) == 0 {
+			return fn(ctx, messages, options...)
+		}
+
+		messages, err := renderEmulatedChain(mode, messages, opts.Tools)
+		if err != nil {
+			return nil, err
+		}
+
+		// the raw envelope must not be streamed to the user, so text chunks are sent after parsing
+		streamCb := opts.StreamingFunc
+		options = append(options, llms.WithTools(nil), llms.WithToolChoice(nil))
+		if streamCb != nil {
+			options = append(options, llms.WithStreamingFunc(
+				func(ctx context.Context, chunk streaming.Chunk) error {
+					switch chunk.Type {
+					case streaming.ChunkTypeText, streaming.ChunkTypeDone:
+						return nil
+					}
+					return streamCb(ctx, chunk)
+				},
+			))
+		}
+
+		resp, err := fn(ctx, messages, options...)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+
+		for _, choice := range resp.Choices {
+			if len(choice.ToolCalls) != 0 {
+				continue
+			}
+			choice.Content, choice.ToolCalls = ParseEmulatedToolCalls(mode, choice.Content)
+			if len(choice.ToolCalls) != 0 {
+				choice.FuncCall = choice.ToolCalls[0].FunctionCall
+			}
+		}
+
+		if streamCb != nil {
+			for _, choice := range resp.Choices {
+				if choice.Content == "" {
+					continue
+				}
+				if err := streaming.CallWithText(ctx, streamCb, choice.Content); err != nil {
+					return nil, err
+				}
+			}
+			if err := streaming.CallWithDone(ctx, streamCb); err != nil {
+				return nil, err
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// ParseEmulatedToolCalls extracts tool calls from the model response and returns the rest of the text,
+// arguments which can't be parsed as JSON are kept as is to be repaired by the tool call fixer
+func ParseEmulatedToolCalls(mode pconfig.ToolCallingMode, content string) (string, []llms.ToolCall) {
+	var calls []emulatedToolCall
+
+	switch mode {
+	case pconfig.ToolCallingEmulatedXML:
+		for _, match := range xmlToolCallRegex.FindAllStringSubmatch(content, -1) {
+			if call, ok := parseEmulatedToolCall(match[1]); ok {
+				calls = append(calls, call)
+			}
+		}
+		if len(calls) != 0 {
+			content = xmlToolCallRegex.ReplaceAllString(content, "")
+		}
+	case pconfig.ToolCallingEmulatedJSON:
+		text, envelope, ok := findEmulatedEnvelope(content)
+		if !ok {
+			return content, nil
+		}
+		calls = envelope.ToolCalls
+		content = text
+		if envelope.Content != nil {
+			content = strings.TrimSpace(strings.Join([]string{text, *envelope.Content}, "\n"))
+		}
+	default:
+		return content, nil
+	}
+
+	toolCalls := make([]llms.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		if call.Name == "" {
+			continue
+		}
+		toolCalls = append(toolCalls, llms.ToolCall{
+			ID:   fmt.Sprintf("%s%d", emulatedToolCallIDPrefix, emulatedToolCallCounter.Add(1)),
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name:      call.Name,
+				Arguments: normalizeEmulatedArguments(call.Arguments),
+			},
+		})
+	}
+
+	return strings.TrimSpace(content), toolCalls
+}
+
+func parseEmulatedToolCall(text string) (emulatedToolCall, bool) {
+	var call emulatedToolCall
+
+	if match := codeFenceRegex.FindStringSubmatch(text); match != nil {
+		text = match[1]
+	}
+
+	if err := json.Unmarshal([]byte(text), &call); err == nil {
+		return call, call.Name != ""
+	}
+
+	// keep broken arguments as raw text to let the tool call fixer repair them
+	match := brokenToolCallRegex.FindStringSubmatch(text)
+	if match == nil {
+		return call, false
+	}
+	call.Name = match[1]
+	call.Arguments = json.RawMessage(strings.TrimSpace(match[2]))
+
+	return call, true
+}
+
+// findEmulatedEnvelope looks for the first JSON object with tool_calls field in the response,
+// it allows code fences and text around the envelope which small models often add
+func findEmulatedEnvelope(content string) (string, emulatedEnvelope, bool) {
+	for start := strings.Index(content, "{"); start >= 0; {
+		var envelope emulatedEnvelope
+		decoder := json.NewDecoder(strings.NewReader(content[start:]))
+		if err := decoder.Decode(&envelope); err == nil && envelope.ToolCalls != nil {
+			end := start + int(decoder.InputOffset())
+			text := codeFenceRegex.ReplaceAllStringFunc(content[:start]+content[end:], func(fence string) string {
+				if codeFenceRegex.FindStringSubmatch(fence)[1] == "" {
+					return ""
+				}
+				return fence
+			})
+			return strings.TrimSpace(text), envelope, true
+		}
+
+		next := strings.Index(content[start+1:], "{")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	return content, emulatedEnvelope{}, false
+}
+
+// normalizeEmulatedArguments unwraps arguments which were encoded as a JSON string
+func normalizeEmulatedArguments(args json.RawMessage) string {
+	args = bytes.TrimSpace(args)
+	if len(args) == 0 || string(args) == "null" {
+		return "{}"
+	}
+
+	var text string
+	if args[0] == '"' && json.Unmarshal(args, &text) == nil && json.Valid([]byte(text)) {
+		return text
+	}
+
+	return string(args)
+}
+
+func renderEmulatedChain(
+	mode pconfig.ToolCallingMode,
+	chain []llms.MessageContent,
+	tools []llms.Tool,
+) ([]llms.MessageContent, error) {
+	instructions, err := renderEmulatedInstructions(mode, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasSystem, lastToolResults bool
+
+	result := make([]llms.MessageContent, 0, len(chain)+1)
+	for _, msg := range chain {
+		isToolResults := msg.Role == llms.ChatMessageTypeTool
+
+		switch msg.Role {
+		case llms.ChatMessageTypeSystem:
+			if !hasSystem {
+				hasSystem = true
+				msg.Parts = append(append([]llms.ContentPart{}, msg.Parts...), llms.TextContent{Text: instructions})
+			}
+			result = append(result, msg)
+		case llms.ChatMessageTypeAI:
+			result = append(result, renderEmulatedAIMessage(mode, msg))
+		case llms.ChatMessageTypeTool:
+			part := llms.TextContent{Text: renderEmulatedToolResults(msg)}
+			// merge tool results of parallel tool calls into a single user message
+			if lastToolResults {
+				last := &result[len(result)-1]
+				last.Parts = append(last.Parts, part)
+			} else {
+				result = append(result, llms.MessageContent{
+					Role:  llms.ChatMessageTypeHuman,
+					Parts: []llms.ContentPart{part},
+				})
+			}
+		default:
+			result = append(result, msg)
+		}
+
+		lastToolResults = isToolResults
+	}
+
+	if !hasSystem {
+		result = append([]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeSystem, strings.TrimSpace(instructions))}, result...)
+	}
+
+	return result, nil
+}
+
+func renderEmulatedInstructions(mode pconfig.ToolCallingMode, tools []llms.Tool) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("\n\n# Tools\n\n")
+	b.WriteString("You can call the following tools, each one is described by a JSON schema of its arguments:\n\n")
+	for _, tool := range tools {
+		if tool.Function == nil {
+			continue
+		}
+		schema, err := json.Marshal(map[string]any{
+			"name":        tool.Function.Name,
+			"description": tool.Function.Description,
+			"parameters":  tool.Function.Parameters,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal tool '%s' schema: %w", tool.Function.Name, err)
+		}
+		b.Write(schema)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n# Tool call format\n\n")
+	switch mode {
+	case pconfig.ToolCallingEmulatedXML:
+		b.WriteString("To call tools, write one <tool_call> block per call with a JSON object inside:\n")
+		b.WriteString("<tool_call>\n{\"name\": \"tool_name\", \"arguments\": {\"arg\": \"value\"}}\n</tool_call>\n\n")
+		b.WriteString("Arguments must be a valid JSON object matching the tool schema. ")
+		b.WriteString("Tool results are returned in <tool_result> blocks of the next user message.")
+	case pconfig.ToolCallingEmulatedJSON:
+		b.WriteString("To call tools, respond with a single JSON object and nothing else:\n")
+		b.WriteString("{\"content\": \"optional text for the user\", \"tool_calls\": ")
+		b.WriteString("[{\"name\": \"tool_name\", \"arguments\": {\"arg\": \"value\"}}]}\n\n")
+		b.WriteString("Arguments must be a valid JSON object matching the tool schema. ")
+		b.WriteString("Tool results are returned in <tool_result> blocks of the next user message.")
+	}
+
+	return b.String(), nil
+}
+
+func renderEmulatedAIMessage(mode pconfig.ToolCallingMode, msg llms.MessageContent) llms.MessageContent {
+	var (
+		texts []string
+		calls []emulatedToolCall
+	)
+
+	for _, part := range msg.Parts {
+		switch part := part.(type) {
+		case llms.TextContent:
+			if text := strings.TrimSpace(part.Text); text != "" {
+				texts = append(texts, text)
+			}
+		case llms.ToolCall:
+			if part.FunctionCall == nil {
+				continue
+			}
+			args := json.RawMessage(part.FunctionCall.Arguments)
+			if !json.Valid(args) {
+				args, _ = json.Marshal(part.FunctionCall.Arguments)
+			}
+			calls = append(calls, emulatedToolCall{Name: part.FunctionCall.Name, Arguments: args})
+		}
+	}
+
+	if len(calls) == 0 {
+		return msg
+	}
+
+	var text string
+	switch mode {
+	case pconfig.ToolCallingEmulatedXML:
+		blocks := texts
+		for _, call := range calls {
+			data, _ := json.Marshal(call)
+			blocks = append(blocks, fmt.Sprintf("<tool_call>\n%s\n</tool_call>", data))
+		}
+		text = strings.Join(blocks, "\n")
+	case pconfig.ToolCallingEmulatedJSON:
+		envelope := emulatedEnvelope{ToolCalls: calls}
+		if len(texts) != 0 {
+			content := strings.Join(texts, "\n")
+			envelope.Content = &content
+		}
+		data, _ := json.Marshal(envelope)
+		text = string(data)
+	}
+
+	return llms.MessageContent{
+		Role:  llms.ChatMessageTypeAI,
+		Parts: []llms.ContentPart{llms.TextContent{Text: text}},
+	}
+}
+
+func renderEmulatedToolResults(msg llms.MessageContent) string {
+	var results []string
+
+	for _, part := range msg.Parts {
+		response, ok := part.(llms.ToolCallResponse)
+		if !ok {
+			continue
+		}
+		results = append(results, fmt.Sprintf("<tool_result name=%q>\n%s\n</tool_result>",
+			response.Name, toolResultEscaper.Replace(response.Content)))
+	}
+
+	return strings.Join(results, "\n")
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"pentagi/pkg/providers/pconfig"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+func TestParseEmulatedToolCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    pconfig.ToolCallingMode
+		content string
+		text    string
+		calls   []llms.FunctionCall
+	}{
+		{
+			name:    "xml tool calls",
+			mode:    pconfig.ToolCallingEmulatedXML,
+			content: "Let me check.\n<tool_call>\n{\"name\": \"terminal\", \"arguments\": {\"input\": \"ls\"}}\n</tool_call>\n<tool_call>{\"name\": \"done\"}</tool_call>",
+			text:    "Let me check.",
+			calls: []llms.FunctionCall{
+				{Name: "terminal", Arguments: `{"input": "ls"}`},
+				{Name: "done", Arguments: "{}"},
+			},
+		},
+		{
+			name:    "xml unclosed tag with broken arguments",
+			mode:    pconfig.ToolCallingEmulatedXML,
+			content: "<tool_call>{\"name\": \"terminal\", \"arguments\": {\"input\": \"ls -la\",}}",
+			calls:   []llms.FunctionCall{{Name: "terminal", Arguments: `{"input": "ls -la",}`}},
+		},
+		{
+			name:    "xml without tool calls",
+			mode:    pconfig.ToolCallingEmulatedXML,
+			content: "The scan is finished",
+			text:    "The scan is finished",
+		},
+		{
+			name:    "json envelope",
+			mode:    pconfig.ToolCallingEmulatedJSON,
+			content: `{"content": "running", "tool_calls": [{"name": "terminal", "arguments": {"input": "id"}}]}`,
+			text:    "running",
+			calls:   []llms.FunctionCall{{Name: "terminal", Arguments: `{"input": "id"}`}},
+		},
+		{
+			name:    "json envelope in code fence with string arguments",
+			mode:    pconfig.ToolCallingEmulatedJSON,
+			content: "Sure:\n```json\n{\"tool_calls\": [{\"name\": \"terminal\", \"arguments\": \"{\\\"input\\\": \\\"id\\\"}\"}]}\n```",
+			text:    "Sure:",
+			calls:   []llms.FunctionCall{{Name: "terminal", Arguments: `{"input": "id"}`}},
+		},
+		{
+			name:    "json without envelope",
+			mode:    pconfig.ToolCallingEmulatedJSON,
+			content: `The result is {"status": "ok"}`,
+			text:    `The result is {"status": "ok"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, calls := ParseEmulatedToolCalls(tt.mode, tt.content)
+			if text != tt.text {
+				t.Errorf("Expected text %q, got %q", tt.text, text)
+			}
+			if len(calls) != len(tt.calls) {
+				t.Fatalf("Expected %d tool calls, got %d", len(tt.calls), len(calls))
+			}
+			for idx, call := range calls {
+				if !strings.HasPrefix(call.ID, emulatedToolCallIDPrefix) || call.Type != "function" {
+					t.Errorf("Unexpected tool call id %q or type %q", call.ID, call.Type)
+				}
+				if *call.FunctionCall != tt.calls[idx] {
+					t.Errorf("Expected tool call %+v, got %+v", tt.calls[idx], *call.FunctionCall)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapToolCallingEmulation(t *testing.T) {
+	chain := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a pentester"),
+		llms.TextParts(llms.ChatMessageTypeHuman, "scan the target"),
+		{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{
+				llms.ToolCall{ID: "call_1", FunctionCall: &llms.FunctionCall{Name: "terminal", Arguments: `{"input":"id"}`}},
+				llms.ToolCall{ID: "call_2", FunctionCall: &llms.FunctionCall{Name: "terminal", Arguments: `{"input":"ls"}`}},
+			},
+		},
+		{
+			Role:  llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_1", Name: "terminal", Content: "root"}},
+		},
+		{
+			Role:  llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{ToolCallID: "call_2", Name: "terminal", Content: "file.txt"}},
+		},
+	}
+	tools := []llms.Tool{{
+		Type: "function",
+		Function: &llms.FunctionDefinition{
+			Name:        "terminal",
+			Description: "run a command",
+			Parameters:  map[string]any{"type": "object"},
+		},
+	}}
+
+	var sent []llms.MessageContent
+	generate := WrapToolCallingEmulation(func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		opts := llms.CallOptions{}
+		for _, option := range options {
+			option(&opts)
+		}
+		if len(opts.Tools) != 0 {
+			t.Errorf("Tools must not be passed to the model, got %d", len(opts.Tools))
+		}
+
+		sent = messages
+		content := "Checking\n<tool_call>{\"name\": \"terminal\", \"arguments\": {\"input\": \"whoami\"}}</tool_call>"
+		_ = streaming.CallWithText(ctx, opts.StreamingFunc, content)
+		_ = streaming.CallWithDone(ctx, opts.StreamingFunc)
+
+		return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content}}}, nil
+	}, pconfig.ToolCallingEmulatedXML)
+
+	var chunks []streaming.Chunk
+	resp, err := generate(context.Background(), chain, llms.WithTools(tools),
+		llms.WithStreamingFunc(func(ctx context.Context, chunk streaming.Chunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to generate content: %v", err)
+	}
+
+	if len(sent) != 4 {
+		t.Fatalf("Expected tool results to be merged into a single user message, got %d messages", len(sent))
+	}
+	system := sent[0].Parts[len(sent[0].Parts)-1].(llms.TextContent).Text
+	if !strings.Contains(system, `"name":"terminal"`) || !strings.Contains(system, "<tool_call>") {
+		t.Errorf("Expected tool schemas and format in the system prompt, got %q", system)
+	}
+	if ai := sent[2].Parts[0].(llms.TextContent).Text; strings.Count(ai, "<tool_call>") != 2 {
+		t.Errorf("Expected tool calls rendered as text, got %q", ai)
+	}
+	if results := sent[3]; results.Role != llms.ChatMessageTypeHuman || len(results.Parts) != 2 {
+		t.Errorf("Expected tool results in the user message, got %+v", results)
+	}
+
+	choice := resp.Choices[0]
+	if choice.Content != "Checking" || len(choice.ToolCalls) != 1 ||
+		choice.ToolCalls[0].FunctionCall.Arguments != `{"input": "whoami"}` {
+		t.Errorf("Unexpected parsed choice: %+v", choice)
+	}
+
+	if len(chunks) != 2 || chunks[0].Content != "Checking" || chunks[1].Type != streaming.ChunkTypeDone {
+		t.Errorf("Expected parsed text and done chunks, got %v", chunks)
+	}
+}
@@ -0,0 +1,30 @@
+package provider
+
+// Generation info keys of the usage which was already resolved by GetUsage of the live provider,
+// they are used to pass the stored usage through the generation info of the replayed responses
+const (
+	GenerationInfoResolvedInputTokens  = "ResolvedInputTokens"
+	GenerationInfoResolvedOutputTokens = "ResolvedOutputTokens"
+)
+
+// NewResolvedUsageInfo returns the generation info which holds the resolved call usage
+func NewResolvedUsageInfo(usage CallUsage) map[string]any {
+	return map[string]any{
+		GenerationInfoResolvedInputTokens:  usage.Input,
+		GenerationInfoResolvedOutputTokens: usage.Output,
+		GenerationInfoCacheReadTokens:      usage.CacheRead,
+		GenerationInfoCacheWriteTokens:     usage.CacheWrite,
+	}
+}
+
+// GetResolvedUsage reads the call usage which was stored by NewResolvedUsageInfo
+func GetResolvedUsage(info map[string]any) CallUsage {
+	cacheRead, cacheWrite := GetCacheUsage(info)
+
+	return CallUsage{
+		Input:      getInt64(info, GenerationInfoResolvedInputTokens),
+		Output:     getInt64(info, GenerationInfoResolvedOutputTokens),
+		CacheRead:  cacheRead,
+		CacheWrite: cacheWrite,
+	}
+}
@@ -125,8 +125,9 @@ func WrapGenerateFromSinglePrompt(
 	}
 
 	var (
-		err  error
-		resp *llms.ContentResponse
+		err   error
+		resp  *llms.ContentResponse
+		start = time.Now()
 	)
 
 	for idx := range MaxTooManyRequestsRetries {
@@ -135,6 +136,7 @@ func WrapGenerateFromSinglePrompt(
 			if isTooManyRequestsError(err) {
 				select {
 				case <-ctx.Done():
+					recordLLMCall(ctx, provider, opt, start, CallUsage{}, ctx.Err())
 					return "", ctx.Err()
 				case <-time.After(TooManyRequestsRetryDelay + time.Duration(idx)*time.Second):
 				}
@@ -145,6 +147,7 @@ func WrapGenerateFromSinglePrompt(
 	}
 
 	if err != nil {
+		recordLLMCall(ctx, provider, opt, start, CallUsage{}, err)
 		generation.End(
 			langfuse.WithEndGenerationStatus(err.Error()),
 			langfuse.WithEndGenerationLevel(langfuse.ObservationLevelError),
@@ -155,6 +158,7 @@ func WrapGenerateFromSinglePrompt(
 	choices := resp.Choices
 	if len(choices) < 1 {
 		err = fmt.Errorf("empty response from model")
+		recordLLMCall(ctx, provider, opt, start, CallUsage{}, err)
 		generation.End(
 			langfuse.WithEndGenerationStatus(err.Error()),
 			langfuse.WithEndGenerationLevel(langfuse.ObservationLevelError),
@@ -163,16 +167,18 @@ func WrapGenerateFromSinglePrompt(
 		return "", err
 	}
 
+	usage := responseUsage(provider, resp.Choices)
+	recordLLMCall(ctx, provider, opt, start, usage, nil)
+
 	if len(resp.Choices) == 1 {
 		choice := resp.Choices[0]
-		input, output := provider.GetUsage(choice.GenerationInfo)
 
 		generation.End(
 			langfuse.WithEndGenerationOutput(choice),
 			langfuse.WithEndGenerationStatus("success"),
 			langfuse.WithEndGenerationUsage(&langfuse.GenerationUsage{
-				Input:  int(input),
-				Output: int(output),
+				Input:  int(usage.Input),
+				Output: int(usage.Output),
 			}),
 		)
 
@@ -180,15 +186,7 @@ func WrapGenerateFromSinglePrompt(
 	}
 
 	choicesOutput := make([]string, 0, len(resp.Choices))
-	totalInput, totalOutput := int64(0), int64(0)
 	for _, choice := range resp.Choices {
-		input, output := provider.GetUsage(choice.GenerationInfo)
-		if input > 0 {
-			totalInput = input
-		}
-		if output > 0 {
-			totalOutput = output
-		}
 		choicesOutput = append(choicesOutput, choice.Content)
 	}
 
@@ -197,8 +195,8 @@ func WrapGenerateFromSinglePrompt(
 		langfuse.WithEndGenerationOutput(resp.Choices),
 		langfuse.WithEndGenerationStatus("success"),
 		langfuse.WithEndGenerationUsage(&langfuse.GenerationUsage{
-			Input:  int(totalInput),
-			Output: int(totalOutput),
+			Input:  int(usage.Input),
+			Output: int(usage.Output),
 		}),
 	)
 
@@ -223,8 +221,9 @@ func WrapGenerateContent(
 	)
 
 	var (
-		err  error
-		resp *llms.ContentResponse
+		err   error
+		resp  *llms.ContentResponse
+		start = time.Now()
 	)
 
 	for idx := range MaxTooManyRequestsRetries {
@@ -233,6 +232,7 @@ func WrapGenerateContent(
 			if isTooManyRequestsError(err) {
 				select {
 				case <-ctx.Done():
+					recordLLMCall(ctx, provider, opt, start, CallUsage{}, ctx.Err())
 					return nil, ctx.Err()
 				case <-time.After(TooManyRequestsRetryDelay + time.Duration(idx)*time.Second):
 				}
@@ -243,6 +243,7 @@ func WrapGenerateContent(
 	}
 
 	if err != nil {
+		recordLLMCall(ctx, provider, opt, start, CallUsage{}, err)
 		generation.End(
 			langfuse.WithEndGenerationStatus(err.Error()),
 			langfuse.WithEndGenerationLevel(langfuse.ObservationLevelError),
@@ -250,45 +251,79 @@ func WrapGenerateContent(
 		return nil, err
 	}
 
-	if len(resp.Choices) == 1 {
-		choice := resp.Choices[0]
-		input, output := provider.GetUsage(choice.GenerationInfo)
+	usage := responseUsage(provider, resp.Choices)
+	recordLLMCall(ctx, provider, opt, start, usage, nil)
 
+	if len(resp.Choices) == 1 {
 		generation.End(
-			langfuse.WithEndGenerationOutput(choice),
+			langfuse.WithEndGenerationOutput(resp.Choices[0]),
 			langfuse.WithEndGenerationStatus("success"),
 			langfuse.WithEndGenerationUsage(&langfuse.GenerationUsage{
-				Input:  int(input),
-				Output: int(output),
+				Input:  int(usage.Input),
+				Output: int(usage.Output),
 			}),
 		)
 
 		return resp, nil
 	}
 
-	totalInput, totalOutput := int64(0), int64(0)
-	for _, choice := range resp.Choices {
-		input, output := provider.GetUsage(choice.GenerationInfo)
-		if input > 0 {
-			totalInput = input
-		}
-		if output > 0 {
-			totalOutput = output
-		}
-	}
-
 	generation.End(
 		langfuse.WithEndGenerationOutput(resp.Choices),
 		langfuse.WithEndGenerationStatus("success"),
 		langfuse.WithEndGenerationUsage(&langfuse.GenerationUsage{
-			Input:  int(totalInput),
-			Output: int(totalOutput),
+			Input:  int(usage.Input),
+			Output: int(usage.Output),
 		}),
 	)
 
 	return resp, nil
 }
 
+// responseUsage returns the usage of the response, the providers put it into one of the choices,
+// so the last non-zero values are taken if there are several choices
+func responseUsage(provider Provider, choices []*llms.ContentChoice) CallUsage {
+	var total CallUsage
+	for _, choice := range choices {
+		usage := provider.GetUsage(choice.GenerationInfo)
+		if usage.Input > 0 {
+			total.Input = usage.Input
+		}
+		if usage.Output > 0 {
+			total.Output = usage.Output
+		}
+		if usage.CacheRead > 0 {
+			total.CacheRead = usage.CacheRead
+		}
+		if usage.CacheWrite > 0 {
+			total.CacheWrite = usage.CacheWrite
+		}
+	}
+
+	return total
+}
+
+// recordLLMCall records the generation metrics, the duration includes the rate limit retries
+func recordLLMCall(
+	ctx context.Context,
+	provider Provider,
+	opt pconfig.ProviderOptionsType,
+	start time.Time,
+	usage CallUsage,
+	err error,
+) {
+	obs.Observer.RecordLLMCall(ctx, obs.LLMCall{
+		Provider:         provider.Type().String(),
+		Model:            provider.Model(opt),
+		Agent:            string(opt),
+		Duration:         time.Since(start),
+		InputTokens:      usage.Input,
+		OutputTokens:     usage.Output,
+		CacheReadTokens:  usage.CacheRead,
+		CacheWriteTokens: usage.CacheWrite,
+		Err:              err,
+	})
+}
+
 func isTooManyRequestsError(err error) bool {
 	if err == nil {
 		return false
@@ -313,3 +348,32 @@ func isTooManyRequestsError(err error) bool {
 
 	return false
 }
+
+var contextLengthErrorPatterns = []string{
+	"context_length_exceeded",
+	"maximum context length",
+	"context length exceeded",
+	"exceeds the context window",
+	"prompt is too long",
+	"input is too long",
+	"too many input tokens",
+	"exceeds the maximum number of tokens",
+	"reduce the length of the messages",
+}
+
+// IsContextLengthError returns true if the provider rejected the request because
+// the messages chain doesn't fit into the model context window
+func IsContextLengthError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errStr := strings.ToLower(err.Error())
+	for _, pattern := range contextLengthErrorPatterns {
+		if strings.Contains(errStr, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
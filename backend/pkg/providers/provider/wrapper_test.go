@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsContextLengthError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("API returned unexpected status code: 400: This model's maximum context length is 8192 tokens"), true},
+		{errors.New(`{"error":{"code":"context_length_exceeded"}}`), true},
+		{errors.New("anthropic: prompt is too long: 210000 tokens > 200000 maximum"), true},
+		{errors.New("ValidationException: Input is too long for requested model."), true},
+		{fmt.Errorf("gemini: %w", errors.New("The input token count (1200000) exceeds the maximum number of tokens allowed")), true},
+		{errors.New("API returned unexpected status code: 429: too many requests"), false},
+		{errors.New("connection reset by peer"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsContextLengthError(tt.err); got != tt.want {
+			t.Errorf("IsContextLengthError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -22,10 +22,12 @@ import (
 	"pentagi/pkg/providers/custom"
 	"pentagi/pkg/providers/embeddings"
 	"pentagi/pkg/providers/gemini"
+	"pentagi/pkg/providers/mock"
 	"pentagi/pkg/providers/ollama"
 	"pentagi/pkg/providers/openai"
 	"pentagi/pkg/providers/pconfig"
 	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/providers/replay"
 	"pentagi/pkg/providers/tester"
 	"pentagi/pkg/templates"
 	"pentagi/pkg/tools"
@@ -47,7 +49,7 @@ type ProviderController interface {
 		executor tools.FlowToolsExecutor,
 		flowID, userID int64,
 		askUser bool,
-		input string,
+		image, input string,
 	) (FlowProvider, error)
 	LoadFlowProvider(
 		ctx context.Context,
@@ -141,6 +143,8 @@ type providerController struct {
 	summarizerAssistant csum.Summarizer
 
 	defaultConfigs provider.ProvidersConfig
+	replayStore    *replay.Store
+	mockScript     *mock.Script
 
 	provider.Providers
 }
@@ -198,6 +202,18 @@ func NewProviderController(
 		defaultConfigs[provider.ProviderCustom] = config
 	}
 
+	if config, err := replay.DefaultProviderConfig(); err != nil {
+		return nil, fmt.Errorf("failed to create replay provider config: %w", err)
+	} else {
+		defaultConfigs[provider.ProviderReplay] = config
+	}
+
+	if config, err := mock.DefaultProviderConfig(); err != nil {
+		return nil, fmt.Errorf("failed to create mock provider config: %w", err)
+	} else {
+		defaultConfigs[provider.ProviderMock] = config
+	}
+
 	if cfg.OpenAIKey != "" {
 		p, err := openai.New(cfg, defaultConfigs[provider.ProviderOpenAI])
 		if err != nil {
@@ -250,6 +266,48 @@ func NewProviderController(
 		providers[provider.DefaultProviderNameCustom] = p
 	}
 
+	var mockScript *mock.Script
+	if cfg.MockScriptPath != "" {
+		mockScript, err = mock.LoadScript(cfg.MockScriptPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mock provider script: %w", err)
+		}
+
+		p, err := mock.New(mockScript, defaultConfigs[provider.ProviderMock])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create mock provider: %w", err)
+		}
+
+		providers[provider.DefaultProviderNameMock] = p
+	}
+
+	var replayStore *replay.Store
+	if cfg.ReplayCassettesDir != "" {
+		replayStore, err = replay.NewStore(cfg.ReplayCassettesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replay cassettes store: %w", err)
+		}
+
+		// record all calls of real providers, replay misses on fallthrough are recorded too
+		if cfg.ReplayRecordEnabled {
+			for prvname, prv := range providers {
+				providers[prvname] = replay.NewRecorder(prv, replayStore)
+			}
+		}
+
+		p, err := replay.New(
+			replayStore,
+			replay.MissPolicy(cfg.ReplayMissPolicy),
+			providers[provider.ProviderName(cfg.ReplayFallbackProvider)],
+			defaultConfigs[provider.ProviderReplay],
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replay provider: %w", err)
+		}
+
+		providers[provider.DefaultProviderNameReplay] = p
+	}
+
 	summarizerAgent := csum.NewSummarizer(csum.SummarizerConfig{
 		PreserveLast:   cfg.SummarizerPreserveLast,
 		UseQA:          cfg.SummarizerUseQA,
@@ -298,6 +356,8 @@ func NewProviderController(
 		summarizerAssistant: summarizerAssistant,
 
 		defaultConfigs: defaultConfigs,
+		replayStore:    replayStore,
+		mockScript:     mockScript,
 
 		Providers: providers,
 	}, nil
@@ -310,7 +370,7 @@ func (pc *providerController) NewFlowProvider(
 	executor tools.FlowToolsExecutor,
 	flowID, userID int64,
 	askUser bool,
-	input string,
+	image, input string,
 ) (FlowProvider, error) {
 	ctx, span := obs.Observer.NewSpan(ctx, obs.SpanKindInternal, "providers.NewFlowProvider")
 	defer span.End()
@@ -320,18 +380,21 @@ func (pc *providerController) NewFlowProvider(
 		return nil, fmt.Errorf("failed to get provider: %w", err)
 	}
 
-	imageTmpl, err := prompter.RenderTemplate(templates.PromptTypeImageChooser, map[string]any{
-		"DefaultImage":           pc.docker.GetDefaultImage(),
-		"DefaultImageForPentest": pc.defaultDockerImageForPentest,
-		"Input":                  input,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get primary docker image template: %w", err)
-	}
+	// preferred image is used as is, otherwise the image is chosen by the model from the user input
+	if image == "" {
+		imageTmpl, err := prompter.RenderTemplate(templates.PromptTypeImageChooser, map[string]any{
+			"DefaultImage":           pc.docker.GetDefaultImage(),
+			"DefaultImageForPentest": pc.defaultDockerImageForPentest,
+			"Input":                  input,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get primary docker image template: %w", err)
+		}
 
-	image, err := prv.Call(ctx, pconfig.OptionsTypeSimple, imageTmpl)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get primary docker image: %w", err)
+		image, err = prv.Call(ctx, pconfig.OptionsTypeSimple, imageTmpl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get primary docker image: %w", err)
+		}
 	}
 	image = strings.ToLower(strings.TrimSpace(image))
 
@@ -567,6 +630,14 @@ func (pc *providerController) GetProvider(
 		return pc.Providers.Get(provider.DefaultProviderNameOllama)
 	case provider.DefaultProviderNameCustom:
 		return pc.Providers.Get(provider.DefaultProviderNameCustom)
+	case provider.DefaultProviderNameReplay:
+		return pc.Providers.Get(provider.DefaultProviderNameReplay)
+	case provider.DefaultProviderNameMock:
+		if _, err := pc.Providers.Get(provider.DefaultProviderNameMock); err != nil {
+			return nil, err
+		}
+		// new instance keeps own position in the script for every flow and assistant
+		return pc.newMockProvider(pc.defaultConfigs[provider.ProviderMock])
 	}
 
 	// Lookup user defined providers by name and build it
@@ -598,18 +669,39 @@ func (pc *providerController) GetProviders(
 		return nil, fmt.Errorf("failed to get user providers: %w", err)
 	}
 
+	// own providers go first, so the team providers with the same name don't override them
+	userProviders := make(map[provider.ProviderName]struct{}, len(providers))
 	for _, prv := range providers {
+		prvname := provider.ProviderName(prv.Name)
+		if _, ok := userProviders[prvname]; ok {
+			continue
+		}
+
 		p, err := pc.NewProvider(prv)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build provider: %w", err)
 		}
-		providersMap[provider.ProviderName(prv.Name)] = p
+		providersMap[prvname] = p
+		userProviders[prvname] = struct{}{}
 	}
 
 	return providersMap, nil
 }
 
 func (pc *providerController) NewProvider(prv database.Provider) (provider.Provider, error) {
+	p, err := pc.buildProvider(prv)
+	if err != nil {
+		return nil, err
+	}
+
+	if pc.replayStore != nil && pc.cfg.ReplayRecordEnabled && p.Type() != provider.ProviderReplay {
+		return replay.NewRecorder(p, pc.replayStore), nil
+	}
+
+	return p, nil
+}
+
+func (pc *providerController) buildProvider(prv database.Provider) (provider.Provider, error) {
 	if len(prv.Config) == 0 {
 		prv.Config = []byte(pconfig.EmptyProviderConfigRaw)
 	}
@@ -657,6 +749,18 @@ func (pc *providerController) NewProvider(prv database.Provider) (provider.Provi
 			return nil, fmt.Errorf("failed to build custom provider config: %w", err)
 		}
 		return custom.New(pc.cfg, customConfig)
+	case provider.ProviderReplay:
+		replayConfig, err := replay.BuildProviderConfig(prv.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build replay provider config: %w", err)
+		}
+		return pc.newReplayProvider(replayConfig)
+	case provider.ProviderMock:
+		mockConfig, err := mock.BuildProviderConfig(prv.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build mock provider config: %w", err)
+		}
+		return pc.newMockProvider(mockConfig)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", prv.Type)
 	}
@@ -984,11 +1088,36 @@ func (pc *providerController) buildProviderFromConfig(
 		return bedrock.New(pc.cfg, config)
 	case provider.ProviderOllama:
 		return ollama.New(pc.cfg, config)
+	case provider.ProviderReplay:
+		return pc.newReplayProvider(config)
+	case provider.ProviderMock:
+		return pc.newMockProvider(config)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", prvtype)
 	}
 }
 
+func (pc *providerController) newReplayProvider(config *pconfig.ProviderConfig) (provider.Provider, error) {
+	if pc.replayStore == nil {
+		return nil, fmt.Errorf("replay cassettes directory is not set")
+	}
+
+	return replay.New(
+		pc.replayStore,
+		replay.MissPolicy(pc.cfg.ReplayMissPolicy),
+		pc.Providers[provider.ProviderName(pc.cfg.ReplayFallbackProvider)],
+		config,
+	)
+}
+
+func (pc *providerController) newMockProvider(config *pconfig.ProviderConfig) (provider.Provider, error) {
+	if pc.mockScript == nil {
+		return nil, fmt.Errorf("mock provider script is not set")
+	}
+
+	return mock.New(pc.mockScript, config)
+}
+
 func newAtomicInt64(seed int64) *atomic.Int64 {
 	var number atomic.Int64
 
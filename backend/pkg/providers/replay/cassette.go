@@ -0,0 +1,339 @@
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const cassetteFileExt = ".json"
+
+var ErrCassetteMiss = errors.New("cassette miss")
+
+// volatile values which are rendered into prompts and must not affect the chain hash
+var scrubPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{
+		re:   regexp.MustCompile(`\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`),
+		repl: "<datetime>",
+	},
+	{
+		re:   regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`),
+		repl: "<uuid>",
+	},
+}
+
+// Cassette is a set of recorded interactions for the same normalized request
+type Cassette struct {
+	Key          string                      `json:"key"`
+	Opt          pconfig.ProviderOptionsType `json:"opt"`
+	Interactions []Interaction               `json:"interactions"`
+}
+
+// Interaction is a single recorded provider call
+type Interaction struct {
+	Provider   provider.ProviderType `json:"provider"`
+	Model      string                `json:"model"`
+	Tools      []string              `json:"tools,omitempty"`
+	Chain      []llms.MessageContent `json:"chain"`
+	Choices    []Choice              `json:"choices"`
+	Chunks     []streaming.Chunk     `json:"chunks,omitempty"`
+	RecordedAt time.Time             `json:"recorded_at"`
+}
+
+// Choice is a serializable copy of llms.ContentChoice with resolved usage
+type Choice struct {
+	Content          string          `json:"content"`
+	ReasoningContent string          `json:"reasoning_content,omitempty"`
+	StopReason       string          `json:"stop_reason,omitempty"`
+	ToolCalls        []llms.ToolCall `json:"tool_calls,omitempty"`
+	UsageIn          int64           `json:"usage_in"`
+	UsageOut         int64           `json:"usage_out"`
+	UsageCacheRead   int64           `json:"usage_cache_read,omitempty"`
+	UsageCacheWrite  int64           `json:"usage_cache_write,omitempty"`
+}
+
+func newChoices(prv provider.Provider, resp *llms.ContentResponse) []Choice {
+	if resp == nil {
+		return nil
+	}
+
+	choices := make([]Choice, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		if choice == nil {
+			continue
+		}
+
+		usage := prv.GetUsage(choice.GenerationInfo)
+		choices = append(choices, Choice{
+			Content:          choice.Content,
+			ReasoningContent: choice.ReasoningContent,
+			StopReason:       choice.StopReason,
+			ToolCalls:        choice.ToolCalls,
+			UsageIn:          usage.Input,
+			UsageOut:         usage.Output,
+			UsageCacheRead:   usage.CacheRead,
+			UsageCacheWrite:  usage.CacheWrite,
+		})
+	}
+
+	return choices
+}
+
+func (i *Interaction) Response() *llms.ContentResponse {
+	resp := &llms.ContentResponse{
+		Choices: make([]*llms.ContentChoice, 0, len(i.Choices)),
+	}
+
+	for _, choice := range i.Choices {
+		var funcCall *llms.FunctionCall
+		if len(choice.ToolCalls) > 0 {
+			funcCall = choice.ToolCalls[0].FunctionCall
+		}
+
+		resp.Choices = append(resp.Choices, &llms.ContentChoice{
+			Content:          choice.Content,
+			ReasoningContent: choice.ReasoningContent,
+			StopReason:       choice.StopReason,
+			FuncCall:         funcCall,
+			ToolCalls:        choice.ToolCalls,
+			GenerationInfo: provider.NewResolvedUsageInfo(provider.CallUsage{
+				Input:      choice.UsageIn,
+				Output:     choice.UsageOut,
+				CacheRead:  choice.UsageCacheRead,
+				CacheWrite: choice.UsageCacheWrite,
+			}),
+		})
+	}
+
+	return resp
+}
+
+type normalizedPart struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	Name string `json:"name,omitempty"`
+	Args string `json:"args,omitempty"`
+}
+
+type normalizedMessage struct {
+	Role  llms.ChatMessageType `json:"role"`
+	Parts []normalizedPart     `json:"parts"`
+}
+
+type normalizedRequest struct {
+	Opt   pconfig.ProviderOptionsType `json:"opt"`
+	Tools []string                    `json:"tools"`
+	Chain []normalizedMessage         `json:"chain"`
+}
+
+// ChainHash returns a stable key of the request which doesn't depend on
+// tool call IDs, whitespace, JSON keys order and volatile prompt values
+func ChainHash(opt pconfig.ProviderOptionsType, chain []llms.MessageContent, tools []llms.Tool) string {
+	req := normalizedRequest{
+		Opt:   opt,
+		Tools: toolNames(tools),
+		Chain: make([]normalizedMessage, 0, len(chain)),
+	}
+
+	for _, msg := range chain {
+		nmsg := normalizedMessage{
+			Role:  msg.Role,
+			Parts: make([]normalizedPart, 0, len(msg.Parts)),
+		}
+
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				nmsg.Parts = append(nmsg.Parts, normalizedPart{Type: "text", Text: normalizeText(p.Text)})
+			case llms.ImageURLContent:
+				nmsg.Parts = append(nmsg.Parts, normalizedPart{Type: "image_url", Text: p.URL})
+			case llms.BinaryContent:
+				sum := sha256.Sum256(p.Data)
+				nmsg.Parts = append(nmsg.Parts, normalizedPart{Type: "binary", Text: hex.EncodeToString(sum[:])})
+			case llms.ToolCall:
+				if p.FunctionCall == nil {
+					continue
+				}
+				nmsg.Parts = append(nmsg.Parts, normalizedPart{
+					Type: "tool_call",
+					Name: p.FunctionCall.Name,
+					Args: normalizeArgs(p.FunctionCall.Arguments),
+				})
+			case llms.ToolCallResponse:
+				nmsg.Parts = append(nmsg.Parts, normalizedPart{
+					Type: "tool_response",
+					Name: p.Name,
+					Text: normalizeText(p.Content),
+				})
+			}
+		}
+
+		req.Chain = append(req.Chain, nmsg)
+	}
+
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func toolNames(tools []llms.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Function != nil {
+			names = append(names, tool.Function.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func normalizeText(text string) string {
+	for _, p := range scrubPatterns {
+		text = p.re.ReplaceAllString(text, p.repl)
+	}
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func normalizeArgs(args string) string {
+	var value any
+	if err := json.Unmarshal([]byte(args), &value); err != nil {
+		return normalizeText(args)
+	}
+
+	// json.Marshal sorts map keys so the result doesn't depend on keys order
+	data, err := json.Marshal(value)
+	if err != nil {
+		return normalizeText(args)
+	}
+
+	return normalizeText(string(data))
+}
+
+// Store keeps cassettes in a directory, one file per chain hash
+type Store struct {
+	mx        *sync.Mutex
+	dir       string
+	cassettes map[string]*Cassette
+	cursors   map[string]int
+}
+
+func NewStore(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cassettes directory is not set")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cassettes directory: %w", err)
+	}
+
+	return &Store{
+		mx:        &sync.Mutex{},
+		dir:       dir,
+		cassettes: make(map[string]*Cassette),
+		cursors:   make(map[string]int),
+	}, nil
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Record appends the interaction to the cassette and flushes it to disk
+func (s *Store) Record(key string, opt pconfig.ProviderOptionsType, interaction Interaction) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	cassette, err := s.load(key)
+	if err != nil {
+		return err
+	}
+	if cassette == nil {
+		cassette = &Cassette{Key: key, Opt: opt}
+		s.cassettes[key] = cassette
+	}
+
+	cassette.Interactions = append(cassette.Interactions, interaction)
+
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette %s: %w", key, err)
+	}
+
+	if err := os.WriteFile(s.path(key), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Next returns interactions for the key in recorded order and
+// repeats the last one when the cassette is exhausted
+func (s *Store) Next(key string) (*Interaction, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	cassette, err := s.load(key)
+	if err != nil {
+		return nil, err
+	}
+	if cassette == nil || len(cassette.Interactions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCassetteMiss, key)
+	}
+
+	idx := s.cursors[key]
+	if idx >= len(cassette.Interactions) {
+		idx = len(cassette.Interactions) - 1
+	} else {
+		s.cursors[key] = idx + 1
+	}
+
+	interaction := cassette.Interactions[idx]
+	return &interaction, nil
+}
+
+// load must be called under the lock, it returns nil cassette if it doesn't exist
+func (s *Store) load(key string) (*Cassette, error) {
+	if cassette, ok := s.cassettes[key]; ok {
+		return cassette, nil
+	}
+
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", key, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cassette %s: %w", key, err)
+	}
+
+	s.cassettes[key] = &cassette
+	return &cassette, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+cassetteFileExt)
+}
@@ -0,0 +1,145 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+// recorder wraps any provider and writes every successful call into the store,
+// all other methods are passed through to the wrapped provider as is
+type recorder struct {
+	provider.Provider
+	store *Store
+}
+
+func NewRecorder(prv provider.Provider, store *Store) provider.Provider {
+	return &recorder{
+		Provider: prv,
+		store:    store,
+	}
+}
+
+func (r *recorder) Call(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	prompt string,
+) (string, error) {
+	result, err := r.Provider.Call(ctx, opt, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	chain := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}
+	resp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: result}},
+	}
+	r.record(ctx, opt, chain, nil, resp, nil)
+
+	return result, nil
+}
+
+func (r *recorder) CallEx(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	capture := newChunksCapture(streamCb)
+
+	resp, err := r.Provider.CallEx(ctx, opt, chain, capture.callback())
+	if err != nil {
+		return nil, err
+	}
+
+	r.record(ctx, opt, chain, nil, resp, capture.list())
+
+	return resp, nil
+}
+
+func (r *recorder) CallWithTools(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	tools []llms.Tool,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	capture := newChunksCapture(streamCb)
+
+	resp, err := r.Provider.CallWithTools(ctx, opt, chain, tools, capture.callback())
+	if err != nil {
+		return nil, err
+	}
+
+	r.record(ctx, opt, chain, tools, resp, capture.list())
+
+	return resp, nil
+}
+
+func (r *recorder) record(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	tools []llms.Tool,
+	resp *llms.ContentResponse,
+	chunks []streaming.Chunk,
+) {
+	key := ChainHash(opt, chain, tools)
+	err := r.store.Record(key, opt, Interaction{
+		Provider:   r.Provider.Type(),
+		Model:      r.Provider.Model(opt),
+		Tools:      toolNames(tools),
+		Chain:      chain,
+		Choices:    newChoices(r.Provider, resp),
+		Chunks:     chunks,
+		RecordedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		// recording must never break the flow execution
+		logrus.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"key": key,
+			"opt": opt,
+		}).Error("failed to record provider call")
+	}
+}
+
+type chunksCapture struct {
+	mx       *sync.Mutex
+	streamCb streaming.Callback
+	chunks   []streaming.Chunk
+}
+
+func newChunksCapture(streamCb streaming.Callback) *chunksCapture {
+	return &chunksCapture{
+		mx:       &sync.Mutex{},
+		streamCb: streamCb,
+	}
+}
+
+func (c *chunksCapture) callback() streaming.Callback {
+	if c.streamCb == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, chunk streaming.Chunk) error {
+		c.mx.Lock()
+		c.chunks = append(c.chunks, chunk)
+		c.mx.Unlock()
+
+		return c.streamCb(ctx, chunk)
+	}
+}
+
+func (c *chunksCapture) list() []streaming.Chunk {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.chunks
+}
@@ -0,0 +1,242 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+const ReplayAgentModel = "replay"
+
+type MissPolicy string
+
+const (
+	// MissPolicyFail returns an error for any request which is not found in cassettes
+	MissPolicyFail MissPolicy = "fail"
+	// MissPolicyFallthrough sends missed requests to the fallback provider
+	MissPolicyFallthrough MissPolicy = "fallthrough"
+)
+
+func (p MissPolicy) Valid() error {
+	switch p {
+	case MissPolicyFail, MissPolicyFallthrough:
+		return nil
+	default:
+		return fmt.Errorf("invalid replay miss policy: %s", p)
+	}
+}
+
+func BuildProviderConfig(configData []byte) (*pconfig.ProviderConfig, error) {
+	defaultOptions := []llms.CallOption{
+		llms.WithModel(ReplayAgentModel),
+	}
+
+	providerConfig, err := pconfig.LoadConfigData(configData, defaultOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerConfig, nil
+}
+
+func DefaultProviderConfig() (*pconfig.ProviderConfig, error) {
+	return BuildProviderConfig([]byte(pconfig.EmptyProviderConfigRaw))
+}
+
+type replayProvider struct {
+	store          *Store
+	policy         MissPolicy
+	fallback       provider.Provider
+	providerConfig *pconfig.ProviderConfig
+}
+
+func New(
+	store *Store,
+	policy MissPolicy,
+	fallback provider.Provider,
+	providerConfig *pconfig.ProviderConfig,
+) (provider.Provider, error) {
+	if store == nil {
+		return nil, fmt.Errorf("cassettes store is not set")
+	}
+
+	if err := policy.Valid(); err != nil {
+		return nil, err
+	}
+
+	if policy == MissPolicyFallthrough && fallback == nil {
+		return nil, fmt.Errorf("fallback provider is required for '%s' miss policy", policy)
+	}
+
+	return &replayProvider{
+		store:          store,
+		policy:         policy,
+		fallback:       fallback,
+		providerConfig: providerConfig,
+	}, nil
+}
+
+func (p *replayProvider) Type() provider.ProviderType {
+	return provider.ProviderReplay
+}
+
+func (p *replayProvider) GetRawConfig() []byte {
+	return p.providerConfig.GetRawConfig()
+}
+
+func (p *replayProvider) GetProviderConfig() *pconfig.ProviderConfig {
+	return p.providerConfig
+}
+
+func (p *replayProvider) GetPriceInfo(opt pconfig.ProviderOptionsType) *pconfig.PriceInfo {
+	return p.providerConfig.GetPriceInfoForType(opt)
+}
+
+func (p *replayProvider) GetModels() pconfig.ModelsConfig {
+	return nil
+}
+
+func (p *replayProvider) Model(opt pconfig.ProviderOptionsType) string {
+	opts := llms.CallOptions{Model: ReplayAgentModel}
+	for _, option := range p.providerConfig.GetOptionsForType(opt) {
+		option(&opts)
+	}
+
+	return opts.Model
+}
+
+func (p *replayProvider) Call(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	prompt string,
+) (string, error) {
+	chain := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, prompt)}
+
+	interaction, err := p.lookup(ctx, opt, ChainHash(opt, chain, nil))
+	if err != nil {
+		if p.canFallthrough(err) {
+			return p.fallback.Call(ctx, opt, prompt)
+		}
+		return "", err
+	}
+
+	resp, err := provider.WrapGenerateContent(ctx, p, opt, p.generator(interaction), chain)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("recorded response has no choices")
+	}
+
+	return resp.Choices[0].Content, nil
+}
+
+func (p *replayProvider) CallEx(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	interaction, err := p.lookup(ctx, opt, ChainHash(opt, chain, nil))
+	if err != nil {
+		if p.canFallthrough(err) {
+			return p.fallback.CallEx(ctx, opt, chain, streamCb)
+		}
+		return nil, err
+	}
+
+	return provider.WrapGenerateContent(
+		ctx, p, opt, p.generator(interaction), chain,
+		llms.WithStreamingFunc(streamCb),
+	)
+}
+
+func (p *replayProvider) CallWithTools(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	chain []llms.MessageContent,
+	tools []llms.Tool,
+	streamCb streaming.Callback,
+) (*llms.ContentResponse, error) {
+	interaction, err := p.lookup(ctx, opt, ChainHash(opt, chain, tools))
+	if err != nil {
+		if p.canFallthrough(err) {
+			return p.fallback.CallWithTools(ctx, opt, chain, tools, streamCb)
+		}
+		return nil, err
+	}
+
+	return provider.WrapGenerateContent(
+		ctx, p, opt, p.generator(interaction), chain,
+		llms.WithTools(tools),
+		llms.WithStreamingFunc(streamCb),
+	)
+}
+
+// GetUsage returns the usage which was resolved by the recorded provider while recording,
+// so the replayed calls report the same tokens as the live calls
+func (p *replayProvider) GetUsage(info map[string]any) provider.CallUsage {
+	return provider.GetResolvedUsage(info)
+}
+
+func (p *replayProvider) lookup(
+	ctx context.Context,
+	opt pconfig.ProviderOptionsType,
+	key string,
+) (*Interaction, error) {
+	interaction, err := p.store.Next(key)
+	if err == nil {
+		return interaction, nil
+	}
+
+	logger := logrus.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+		"key":       key,
+		"opt":       opt,
+		"policy":    p.policy,
+		"cassettes": p.store.Dir(),
+	})
+	if p.policy == MissPolicyFallthrough && errors.Is(err, ErrCassetteMiss) {
+		logger.Warn("replay cassette not found, falling through to the fallback provider")
+	} else {
+		logger.Error("replay cassette not found")
+	}
+
+	return nil, err
+}
+
+func (p *replayProvider) canFallthrough(err error) bool {
+	return p.policy == MissPolicyFallthrough && p.fallback != nil && errors.Is(err, ErrCassetteMiss)
+}
+
+// generator returns the content function which replays recorded streaming chunks
+// and the final response so it can be observed the same way as real providers
+func (p *replayProvider) generator(interaction *Interaction) provider.GenerateContentFunc {
+	return func(
+		ctx context.Context,
+		messages []llms.MessageContent,
+		options ...llms.CallOption,
+	) (*llms.ContentResponse, error) {
+		opts := llms.CallOptions{}
+		for _, option := range options {
+			option(&opts)
+		}
+
+		if opts.StreamingFunc != nil {
+			for _, chunk := range interaction.Chunks {
+				if err := opts.StreamingFunc(ctx, chunk); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return interaction.Response(), nil
+	}
+}
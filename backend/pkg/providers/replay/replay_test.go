@@ -0,0 +1,302 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/providers/openai"
+	"pentagi/pkg/providers/pconfig"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/providers/tester/mock"
+
+	"github.com/vxcontrol/langchaingo/llms"
+	"github.com/vxcontrol/langchaingo/llms/streaming"
+)
+
+func testChain(callID, currentTime string) []llms.MessageContent {
+	return []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a pentester. Current time: "+currentTime),
+		llms.TextParts(llms.ChatMessageTypeHuman, "scan   the target"),
+		{
+			Role: llms.ChatMessageTypeAI,
+			Parts: []llms.ContentPart{llms.ToolCall{
+				ID:   callID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      "terminal",
+					Arguments: `{"input": "nmap localhost", "timeout": 60}`,
+				},
+			}},
+		},
+		{
+			Role: llms.ChatMessageTypeTool,
+			Parts: []llms.ContentPart{llms.ToolCallResponse{
+				ToolCallID: callID,
+				Name:       "terminal",
+				Content:    "22/tcp open ssh",
+			}},
+		},
+	}
+}
+
+func testTools() []llms.Tool {
+	return []llms.Tool{
+		{Type: "function", Function: &llms.FunctionDefinition{Name: "terminal"}},
+		{Type: "function", Function: &llms.FunctionDefinition{Name: "done"}},
+	}
+}
+
+func TestChainHash(t *testing.T) {
+	opt := pconfig.OptionsTypePentester
+	base := ChainHash(opt, testChain("call_1", "2025-01-01 10:00:00"), testTools())
+
+	if other := ChainHash(opt, testChain("call_2", "2025-10-20 18:30:15"), testTools()); other != base {
+		t.Error("hash must not depend on tool call IDs and timestamps")
+	}
+
+	chain := testChain("call_1", "2025-01-01 10:00:00")
+	chain[2].Parts[0] = llms.ToolCall{
+		ID:   "call_1",
+		Type: "function",
+		FunctionCall: &llms.FunctionCall{
+			Name:      "terminal",
+			Arguments: `{"timeout":60,"input":"nmap localhost"}`,
+		},
+	}
+	if other := ChainHash(opt, chain, testTools()); other != base {
+		t.Error("hash must not depend on arguments keys order")
+	}
+
+	tools := testTools()
+	tools[0], tools[1] = tools[1], tools[0]
+	if other := ChainHash(opt, testChain("call_1", "2025-01-01 10:00:00"), tools); other != base {
+		t.Error("hash must not depend on tools order")
+	}
+
+	if other := ChainHash(pconfig.OptionsTypeCoder, testChain("call_1", "2025-01-01 10:00:00"), testTools()); other == base {
+		t.Error("hash must depend on agent type")
+	}
+
+	chain = testChain("call_1", "2025-01-01 10:00:00")
+	chain[1] = llms.TextParts(llms.ChatMessageTypeHuman, "scan another target")
+	if other := ChainHash(opt, chain, testTools()); other == base {
+		t.Error("hash must depend on messages content")
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	opt := pconfig.OptionsTypePentester
+	chain := testChain("call_1", "2025-01-01 10:00:00")
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	mockProvider := mock.NewProvider(provider.ProviderOpenAI, "gpt-4o")
+	mockProvider.SetStreamingDelay(0)
+	mockProvider.SetResponses([]mock.ResponseConfig{
+		{
+			Key: "tools:You are a pentester. Current time: 2025-01-01 10:00:00 scan   the target",
+			Response: &llms.ContentResponse{
+				Choices: []*llms.ContentChoice{{
+					Content: "port 22 is open",
+					ToolCalls: []llms.ToolCall{{
+						ID:   "call_3",
+						Type: "function",
+						FunctionCall: &llms.FunctionCall{
+							Name:      "done",
+							Arguments: `{"result": "ssh found"}`,
+						},
+					}},
+					GenerationInfo: map[string]any{},
+				}},
+			},
+		},
+	})
+
+	var recordedChunks []streaming.Chunk
+	recorder := NewRecorder(mockProvider, store)
+	if recorder.Type() != provider.ProviderOpenAI {
+		t.Errorf("Recorder must keep wrapped provider type, got %s", recorder.Type())
+	}
+
+	_, err = recorder.CallWithTools(ctx, opt, chain, testTools(), func(ctx context.Context, chunk streaming.Chunk) error {
+		recordedChunks = append(recordedChunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to call recorder: %v", err)
+	}
+	if len(recordedChunks) == 0 {
+		t.Fatal("Expected streaming chunks to be passed through recorder")
+	}
+
+	// new store to make sure cassettes are read from disk
+	store, err = NewStore(store.Dir())
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+
+	providerConfig, err := DefaultProviderConfig()
+	if err != nil {
+		t.Fatalf("Failed to create provider config: %v", err)
+	}
+
+	prv, err := New(store, MissPolicyFail, nil, providerConfig)
+	if err != nil {
+		t.Fatalf("Failed to create replay provider: %v", err)
+	}
+
+	if prv.Type() != provider.ProviderReplay {
+		t.Errorf("Expected type %s, got %s", provider.ProviderReplay, prv.Type())
+	}
+
+	var replayedChunks []streaming.Chunk
+	resp, err := prv.CallWithTools(ctx, opt, testChain("call_9", "2026-02-02 12:12:12"), testTools(),
+		func(ctx context.Context, chunk streaming.Chunk) error {
+			replayedChunks = append(replayedChunks, chunk)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to replay call: %v", err)
+	}
+
+	if len(resp.Choices) != 1 || resp.Choices[0].Content != "port 22 is open" {
+		t.Fatalf("Unexpected replayed response: %+v", resp.Choices)
+	}
+	toolCalls := resp.Choices[0].ToolCalls
+	if len(toolCalls) != 1 || toolCalls[0].ID != "call_3" || toolCalls[0].FunctionCall.Name != "done" {
+		t.Errorf("Unexpected replayed tool calls: %+v", toolCalls)
+	}
+	if len(replayedChunks) != len(recordedChunks) {
+		t.Errorf("Expected %d replayed chunks, got %d", len(recordedChunks), len(replayedChunks))
+	}
+
+	usage := prv.GetUsage(resp.Choices[0].GenerationInfo)
+	if usage.Input != 100 || usage.Output != 50 {
+		t.Errorf("Expected recorded usage 100/50, got %d/%d", usage.Input, usage.Output)
+	}
+
+	_, err = prv.CallEx(ctx, opt, chain, nil)
+	if !errors.Is(err, ErrCassetteMiss) {
+		t.Errorf("Expected cassette miss error, got %v", err)
+	}
+}
+
+// liveUsageProvider returns the mocked responses and parses their usage like the live provider
+type liveUsageProvider struct {
+	*mock.Provider
+	live provider.Provider
+}
+
+func (p *liveUsageProvider) GetUsage(info map[string]any) provider.CallUsage {
+	return p.live.GetUsage(info)
+}
+
+func TestReplayUsageMatchesLiveProvider(t *testing.T) {
+	ctx := context.Background()
+	opt := pconfig.OptionsTypeSimple
+
+	live, err := openai.New(&config.Config{OpenAIKey: "test-key"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create openai provider: %v", err)
+	}
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	info := map[string]any{"PromptTokens": 1200, "CompletionTokens": 300}
+	mockProvider := mock.NewProvider(provider.ProviderOpenAI, "gpt-4o")
+	mockProvider.SetStreamingDelay(0)
+	mockProvider.SetResponses([]mock.ResponseConfig{
+		{
+			Key: "hello",
+			Response: &llms.ContentResponse{
+				Choices: []*llms.ContentChoice{{Content: "hi", GenerationInfo: info}},
+			},
+		},
+	})
+
+	chain := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hello")}
+	recorder := NewRecorder(&liveUsageProvider{Provider: mockProvider, live: live}, store)
+	if _, err := recorder.CallEx(ctx, opt, chain, nil); err != nil {
+		t.Fatalf("Failed to call recorder: %v", err)
+	}
+
+	providerConfig, err := DefaultProviderConfig()
+	if err != nil {
+		t.Fatalf("Failed to create provider config: %v", err)
+	}
+
+	prv, err := New(store, MissPolicyFail, nil, providerConfig)
+	if err != nil {
+		t.Fatalf("Failed to create replay provider: %v", err)
+	}
+
+	resp, err := prv.CallEx(ctx, opt, chain, nil)
+	if err != nil {
+		t.Fatalf("Failed to replay call: %v", err)
+	}
+
+	expected := live.GetUsage(info)
+	if expected.Input != 1200 || expected.Output != 300 {
+		t.Fatalf("Unexpected live usage: %+v", expected)
+	}
+	if usage := prv.GetUsage(resp.Choices[0].GenerationInfo); usage != expected {
+		t.Errorf("Expected replayed usage %+v, got %+v", expected, usage)
+	}
+}
+
+func TestReplayFallthrough(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	providerConfig, err := DefaultProviderConfig()
+	if err != nil {
+		t.Fatalf("Failed to create provider config: %v", err)
+	}
+
+	if _, err := New(store, MissPolicyFallthrough, nil, providerConfig); err == nil {
+		t.Error("Expected error for fallthrough policy without fallback provider")
+	}
+	if _, err := New(store, MissPolicy("unknown"), nil, providerConfig); err == nil {
+		t.Error("Expected error for unknown miss policy")
+	}
+
+	fallback := mock.NewProvider(provider.ProviderOpenAI, "gpt-4o")
+	fallback.SetDefaultResponse("fallback response")
+
+	prv, err := New(store, MissPolicyFallthrough, NewRecorder(fallback, store), providerConfig)
+	if err != nil {
+		t.Fatalf("Failed to create replay provider: %v", err)
+	}
+
+	result, err := prv.Call(ctx, pconfig.OptionsTypeSimple, "hello")
+	if err != nil {
+		t.Fatalf("Failed to call replay provider: %v", err)
+	}
+	if result != "fallback response" {
+		t.Errorf("Expected fallback response, got %q", result)
+	}
+
+	// the miss was recorded by the fallback recorder and must be replayed now
+	fallback.SetDefaultResponse("changed response")
+	result, err = prv.Call(ctx, pconfig.OptionsTypeSimple, "hello")
+	if err != nil {
+		t.Fatalf("Failed to call replay provider: %v", err)
+	}
+	if result != "fallback response" {
+		t.Errorf("Expected recorded response, got %q", result)
+	}
+}
@@ -32,6 +32,7 @@ func applySubtaskOperations(
 			SubtaskInfo: tools.SubtaskInfo{
 				Title:       st.Title,
 				Description: st.Description,
+				DependsOn:   slices.Clone(st.DependsOn),
 			},
 		})
 	}
@@ -72,8 +73,8 @@ func applySubtaskOperations(
 				opLogger.Error(err.Error())
 				return nil, err
 			}
-			if op.Title == "" && op.Description == "" {
-				err := fmt.Errorf("operation %d: modify operation missing both title and description fields", i)
+			if op.Title == "" && op.Description == "" && op.DependsOn == nil {
+				err := fmt.Errorf("operation %d: modify operation missing both title and description fields and depends_on", i)
 				opLogger.Error(err.Error())
 				return nil, err
 			}
@@ -92,6 +93,10 @@ func applySubtaskOperations(
 				result[idx].Description = op.Description
 				opLogger.WithField("new_description_len", len(op.Description)).Debug("updated subtask description")
 			}
+			if op.DependsOn != nil {
+				result[idx].DependsOn = slices.Clone(op.DependsOn)
+				opLogger.WithField("new_depends_on", op.DependsOn).Debug("updated subtask dependencies")
+			}
 		}
 	}
 
@@ -137,6 +142,7 @@ func applySubtaskOperations(
 				SubtaskInfo: tools.SubtaskInfo{
 					Title:       op.Title,
 					Description: op.Description,
+					DependsOn:   slices.Clone(op.DependsOn),
 				},
 			}
 
@@ -194,6 +200,34 @@ func applySubtaskOperations(
 	return result, nil
 }
 
+// PatchSubtasks validates and applies the user edits to the planned subtasks, it has the same
+// semantics as the refiner patch and returns the new plan which replaces the planned subtasks,
+// existing subtasks keep their IDs to resolve the dependencies between them
+func PatchSubtasks(
+	planned []database.Subtask,
+	patch tools.SubtaskPatch,
+	logger *logrus.Entry,
+) ([]tools.SubtaskInfoPatch, error) {
+	if err := ValidateSubtaskPatch(patch); err != nil {
+		return nil, fmt.Errorf("invalid subtask patch: %w", err)
+	}
+
+	result, err := applySubtaskOperations(planned, patch, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply subtask operations: %w", err)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("subtask patch removes all planned subtasks")
+	}
+
+	if len(result) > TasksNumberLimit {
+		return nil, fmt.Errorf("too many subtasks in the plan: %d of %d allowed", len(result), TasksNumberLimit)
+	}
+
+	return result, nil
+}
+
 // convertSubtaskInfoPatch removes the ID field from the subtasks info patches
 func convertSubtaskInfoPatch(subtasks []tools.SubtaskInfoPatch) []tools.SubtaskInfo {
 	result := make([]tools.SubtaskInfo, 0, len(subtasks))
@@ -201,6 +235,7 @@ func convertSubtaskInfoPatch(subtasks []tools.SubtaskInfoPatch) []tools.SubtaskI
 		result = append(result, tools.SubtaskInfo{
 			Title:       st.Title,
 			Description: st.Description,
+			DependsOn:   st.DependsOn,
 		})
 	}
 	return result
@@ -250,8 +285,8 @@ func ValidateSubtaskPatch(patch tools.SubtaskPatch) error {
 			if op.ID == nil {
 				return fmt.Errorf("operation %d: modify requires id", i)
 			}
-			if op.Title == "" && op.Description == "" {
-				return fmt.Errorf("operation %d: modify requires at least title or description", i)
+			if op.Title == "" && op.Description == "" && op.DependsOn == nil {
+				return fmt.Errorf("operation %d: modify requires at least title or description or depends_on", i)
 			}
 		case tools.SubtaskOpReorder:
 			if op.ID == nil {
@@ -146,7 +146,7 @@ func TestApplySubtaskOperations_ModifyDescription(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Len(t, result, 1)
-	assert.Equal(t, "Task 1", result[0].Title)              // Title unchanged
+	assert.Equal(t, "Task 1", result[0].Title) // Title unchanged
 	assert.Equal(t, "New Description", result[0].Description)
 }
 
@@ -532,6 +532,39 @@ func TestApplySubtaskOperations_MultipleAddsWithPositioning(t *testing.T) {
 	assert.Equal(t, "Task B", result[2].Title)
 }
 
+func TestApplySubtaskOperations_DependsOn(t *testing.T) {
+	planned := []database.Subtask{
+		{ID: 1, Title: "Task 1", Description: "Description 1"},
+		{ID: 2, Title: "Task 2", Description: "Description 2", DependsOn: []int64{1}},
+		{ID: 3, Title: "Task 3", Description: "Description 3", DependsOn: []int64{1, 2}},
+	}
+
+	id2 := int64(2)
+	id3 := int64(3)
+	patch := tools.SubtaskPatch{
+		Operations: []tools.SubtaskOperation{
+			{Op: tools.SubtaskOpModify, ID: &id2, DependsOn: []int64{}},
+			{Op: tools.SubtaskOpModify, ID: &id3, Title: "New Task 3"},
+			{Op: tools.SubtaskOpAdd, AfterID: &id3, Title: "Task 4", Description: "Description 4", DependsOn: []int64{3}},
+		},
+	}
+
+	result, err := applySubtaskOperations(planned, patch, newTestLogger())
+	require.NoError(t, err)
+
+	require.Len(t, result, 4)
+	assert.Empty(t, result[0].DependsOn)
+	assert.Empty(t, result[1].DependsOn)
+	assert.Equal(t, "Task 2", result[1].Title)
+	assert.Equal(t, []int64{1, 2}, result[2].DependsOn)
+	assert.Equal(t, "New Task 3", result[2].Title)
+	assert.Equal(t, int64(0), result[3].ID)
+	assert.Equal(t, []int64{3}, result[3].DependsOn)
+
+	// source subtasks must not be changed by the patch
+	assert.Equal(t, []int64{1}, planned[1].DependsOn)
+}
+
 func TestValidateSubtaskPatch_ValidOperations(t *testing.T) {
 	id := int64(1)
 
@@ -677,3 +710,68 @@ func TestValidateSubtaskPatch_InvalidOperations(t *testing.T) {
 	}
 }
 
+func TestPatchSubtasks(t *testing.T) {
+	planned := []database.Subtask{
+		{ID: 1, Title: "Task 1", Description: "Description 1"},
+		{ID: 2, Title: "Task 2", Description: "Description 2"},
+	}
+
+	id1 := int64(1)
+	id2 := int64(2)
+	patch := tools.SubtaskPatch{
+		Operations: []tools.SubtaskOperation{
+			{Op: tools.SubtaskOpModify, ID: &id1, Title: "Recon"},
+			{Op: tools.SubtaskOpAdd, AfterID: &id2, Title: "Report", Description: "Write the report"},
+			{Op: tools.SubtaskOpReorder, ID: &id2},
+		},
+	}
+
+	result, err := PatchSubtasks(planned, patch, newTestLogger())
+	require.NoError(t, err)
+
+	require.Len(t, result, 3)
+	assert.Equal(t, "Task 2", result[0].Title)
+	assert.Equal(t, "Recon", result[1].Title)
+	assert.Equal(t, "Description 1", result[1].Description)
+	assert.Equal(t, "Report", result[2].Title)
+}
+
+func TestPatchSubtasks_Invalid(t *testing.T) {
+	planned := []database.Subtask{
+		{ID: 1, Title: "Task 1", Description: "Description 1"},
+	}
+
+	id1 := int64(1)
+	tests := []struct {
+		name          string
+		operations    []tools.SubtaskOperation
+		expectedError string
+	}{
+		{
+			name:          "unknown operation",
+			operations:    []tools.SubtaskOperation{{Op: "split", ID: &id1}},
+			expectedError: "unknown operation type",
+		},
+		{
+			name:          "remove all subtasks",
+			operations:    []tools.SubtaskOperation{{Op: tools.SubtaskOpRemove, ID: &id1}},
+			expectedError: "removes all planned subtasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PatchSubtasks(planned, tools.SubtaskPatch{Operations: tt.operations}, newTestLogger())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+
+	operations := make([]tools.SubtaskOperation, 0, TasksNumberLimit)
+	for range TasksNumberLimit {
+		operations = append(operations, tools.SubtaskOperation{Op: tools.SubtaskOpAdd, Title: "T", Description: "D"})
+	}
+	_, err := PatchSubtasks(planned, tools.SubtaskPatch{Operations: operations}, newTestLogger())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many subtasks")
+}
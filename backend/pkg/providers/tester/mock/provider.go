@@ -67,8 +67,8 @@ func (p *Provider) Model(opt pconfig.ProviderOptionsType) string {
 }
 
 // GetUsage implements provider.Provider
-func (p *Provider) GetUsage(info map[string]any) (int64, int64) {
-	return 100, 50 // Mock token counts
+func (p *Provider) GetUsage(info map[string]any) provider.CallUsage {
+	return provider.CallUsage{Input: 100, Output: 50} // Mock token counts
 }
 
 // GetModels implements provider.Provider
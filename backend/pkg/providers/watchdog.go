@@ -0,0 +1,272 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/csum"
+	"pentagi/pkg/database"
+	"pentagi/pkg/tools"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+// ErrLimitReached is returned by the agent chain which is stopped by the watchdog
+var ErrLimitReached = errors.New("watchdog limit is reached")
+
+// Limits are the watchdog limits of the subtask execution, zero value means no limit
+type Limits struct {
+	FlowTimeLimit      time.Duration
+	TaskTimeLimit      time.Duration
+	SubtaskTimeLimit   time.Duration
+	MaxIterations      int
+	MaxToolCalls       int
+	MaxDelegationDepth int
+}
+
+// NewLimits returns the global limits from the config
+func NewLimits(cfg *config.Config) Limits {
+	return Limits{
+		FlowTimeLimit:      time.Duration(cfg.FlowTimeLimit) * time.Second,
+		TaskTimeLimit:      time.Duration(cfg.TaskTimeLimit) * time.Second,
+		SubtaskTimeLimit:   time.Duration(cfg.SubtaskTimeLimit) * time.Second,
+		MaxIterations:      cfg.AgentMaxIterations,
+		MaxToolCalls:       cfg.AgentMaxToolCalls,
+		MaxDelegationDepth: cfg.AgentMaxDelegationDepth,
+	}
+}
+
+// Override returns the limits with the values which are set for the flow
+func (l Limits) Override(fl database.FlowLimit) Limits {
+	if fl.FlowTimeLimit > 0 {
+		l.FlowTimeLimit = time.Duration(fl.FlowTimeLimit) * time.Second
+	}
+	if fl.TaskTimeLimit > 0 {
+		l.TaskTimeLimit = time.Duration(fl.TaskTimeLimit) * time.Second
+	}
+	if fl.SubtaskTimeLimit > 0 {
+		l.SubtaskTimeLimit = time.Duration(fl.SubtaskTimeLimit) * time.Second
+	}
+	if fl.MaxIterations > 0 {
+		l.MaxIterations = int(fl.MaxIterations)
+	}
+	if fl.MaxToolCalls > 0 {
+		l.MaxToolCalls = int(fl.MaxToolCalls)
+	}
+	if fl.MaxDelegationDepth > 0 {
+		l.MaxDelegationDepth = int(fl.MaxDelegationDepth)
+	}
+
+	return l
+}
+
+// LimitError stops the agent chain with the forced summary of the work done so far,
+// the summary is empty if the agent had no chance to do anything
+type LimitError struct {
+	Reason  string
+	Summary string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrLimitReached, e.Reason)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitReached
+}
+
+// Result returns the text which is used as the agent result instead of the regular one
+func (e *LimitError) Result() string {
+	if e.Summary == "" {
+		return fmt.Sprintf("The work was stopped by the watchdog because the %s, no results were collected.", e.Reason)
+	}
+
+	return fmt.Sprintf("The work was stopped by the watchdog because the %s. Summary of the work done:\n\n%s", e.Reason, e.Summary)
+}
+
+// limitResult returns the forced result if the agent chain was stopped by the watchdog
+func limitResult(err error) (string, bool) {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Result(), true
+	}
+
+	return "", false
+}
+
+type watchdogContextKey struct{}
+
+// watchdog is carried by the context from the flow worker down to the nested agents,
+// the limits are enforced only within the subtask execution after it's armed
+type watchdog struct {
+	limits   Limits
+	deadline time.Time
+	reason   string
+	armed    bool
+	depth    int
+	onLimit  LimitHandler
+}
+
+func getWatchdog(ctx context.Context) (watchdog, bool) {
+	wd, ok := ctx.Value(watchdogContextKey{}).(watchdog)
+	return wd, ok
+}
+
+func putWatchdog(ctx context.Context, wd watchdog) context.Context {
+	return context.WithValue(ctx, watchdogContextKey{}, wd)
+}
+
+// withTimeLimit moves the deadline earlier if the time limit from the start ends before it
+func (wd watchdog) withTimeLimit(start time.Time, limit time.Duration, scope string) watchdog {
+	if limit <= 0 {
+		return wd
+	}
+
+	if deadline := start.Add(limit); wd.deadline.IsZero() || deadline.Before(wd.deadline) {
+		wd.deadline = deadline
+		wd.reason = fmt.Sprintf("%s time limit of %s is reached", scope, limit)
+	}
+
+	return wd
+}
+
+// PutFlowWatchdog puts the flow limits to the context, the flow time is counted from the start
+func PutFlowWatchdog(ctx context.Context, limits Limits, start time.Time) context.Context {
+	wd := watchdog{limits: limits}
+	return putWatchdog(ctx, wd.withTimeLimit(start, limits.FlowTimeLimit, "flow"))
+}
+
+// PutTaskWatchdog starts counting the task time limit from now
+func PutTaskWatchdog(ctx context.Context) context.Context {
+	wd, ok := getWatchdog(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return putWatchdog(ctx, wd.withTimeLimit(time.Now(), wd.limits.TaskTimeLimit, "task"))
+}
+
+// LimitHandler is called when the subtask is finished by the watchdog with the forced result
+type LimitHandler func(ctx context.Context, taskID, subtaskID int64, reason string)
+
+// PutLimitHandler sets the handler of the stopped subtasks to the flow watchdog in the context
+func PutLimitHandler(ctx context.Context, handler LimitHandler) context.Context {
+	wd, ok := getWatchdog(ctx)
+	if !ok {
+		return ctx
+	}
+
+	wd.onLimit = handler
+
+	return putWatchdog(ctx, wd)
+}
+
+// notifyLimitReached calls the limit handler if the primary agent chain was stopped by the watchdog
+func notifyLimitReached(ctx context.Context, taskID, subtaskID int64, err error) {
+	var limitErr *LimitError
+	wd, ok := getWatchdog(ctx)
+	if !ok || wd.onLimit == nil || !errors.As(err, &limitErr) {
+		return
+	}
+
+	wd.onLimit(ctx, taskID, subtaskID, limitErr.Reason)
+}
+
+// IsDeadlineExceeded reports whether the flow or the task time limit is reached
+func IsDeadlineExceeded(ctx context.Context) bool {
+	wd, ok := getWatchdog(ctx)
+	return ok && !wd.deadline.IsZero() && time.Now().After(wd.deadline)
+}
+
+// putSubtaskWatchdog starts counting the subtask time limit and enables the chain limits
+func putSubtaskWatchdog(ctx context.Context) context.Context {
+	wd, ok := getWatchdog(ctx)
+	if !ok {
+		return ctx
+	}
+
+	wd = wd.withTimeLimit(time.Now(), wd.limits.SubtaskTimeLimit, "subtask")
+	wd.armed = true
+
+	return putWatchdog(ctx, wd)
+}
+
+// enterAgentChain increases the depth of nested agents, the primary agent chain has depth one
+func enterAgentChain(ctx context.Context) (context.Context, *watchdog) {
+	wd, ok := getWatchdog(ctx)
+	if !ok || !wd.armed {
+		return ctx, nil
+	}
+
+	wd.depth++
+
+	return putWatchdog(ctx, wd), &wd
+}
+
+// checkDelegation returns the reason if the agent is nested deeper than allowed
+func (wd *watchdog) checkDelegation() string {
+	if wd == nil || wd.limits.MaxDelegationDepth <= 0 || wd.depth-1 <= wd.limits.MaxDelegationDepth {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"maximum delegation depth of %d is reached, the work must be done without the delegation",
+		wd.limits.MaxDelegationDepth,
+	)
+}
+
+// check returns the reason if any limit is reached before the next call of the agent chain
+func (wd *watchdog) check(iterations, toolCalls int) string {
+	switch {
+	case wd == nil:
+		return ""
+	case !wd.deadline.IsZero() && time.Now().After(wd.deadline):
+		return wd.reason
+	case wd.limits.MaxIterations > 0 && iterations >= wd.limits.MaxIterations:
+		return fmt.Sprintf("maximum of %d agent iterations is reached", wd.limits.MaxIterations)
+	case wd.limits.MaxToolCalls > 0 && toolCalls >= wd.limits.MaxToolCalls:
+		return fmt.Sprintf("maximum of %d tool calls is reached", wd.limits.MaxToolCalls)
+	default:
+		return ""
+	}
+}
+
+// stopAgentChain returns the limit error with the forced summary of the current chain
+func (fp *flowProvider) stopAgentChain(
+	ctx context.Context,
+	logger *logrus.Entry,
+	chain []llms.MessageContent,
+	reason string,
+	summarizerHandler tools.SummarizeHandler,
+) error {
+	var humanMessages, aiMessages []llms.MessageContent
+	for _, msg := range chain {
+		switch msg.Role {
+		case llms.ChatMessageTypeSystem:
+		case llms.ChatMessageTypeHuman:
+			humanMessages = append(humanMessages, msg)
+		default:
+			aiMessages = append(aiMessages, msg)
+		}
+	}
+
+	logger.WithField("reason", reason).Warn("agent chain is stopped by the watchdog")
+
+	limitErr := &LimitError{Reason: reason}
+	if len(aiMessages) == 0 {
+		return limitErr
+	}
+
+	summary, err := csum.GenerateSummary(ctx, summarizerHandler, humanMessages, aiMessages)
+	if err != nil {
+		logger.WithError(err).Warn("failed to generate forced summary of the agent chain")
+		return limitErr
+	}
+	limitErr.Summary = summary
+
+	return limitErr
+}
@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/database"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vxcontrol/langchaingo/llms"
+)
+
+func TestLimitsOverride(t *testing.T) {
+	limits := NewLimits(&config.Config{
+		FlowTimeLimit:      3600,
+		SubtaskTimeLimit:   600,
+		AgentMaxIterations: 100,
+	})
+
+	limits = limits.Override(database.FlowLimit{
+		SubtaskTimeLimit:   60,
+		MaxToolCalls:       20,
+		MaxDelegationDepth: 1,
+	})
+
+	assert.Equal(t, Limits{
+		FlowTimeLimit:      time.Hour,
+		SubtaskTimeLimit:   time.Minute,
+		MaxIterations:      100,
+		MaxToolCalls:       20,
+		MaxDelegationDepth: 1,
+	}, limits)
+}
+
+func TestWatchdogDeadline(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsDeadlineExceeded(ctx))
+	assert.Equal(t, ctx, PutTaskWatchdog(ctx), "task watchdog needs the flow limits")
+
+	// the flow was started long ago, so its deadline is earlier than the task one
+	ctx = PutFlowWatchdog(ctx, Limits{FlowTimeLimit: time.Hour, TaskTimeLimit: time.Minute}, time.Now().Add(-2*time.Hour))
+	ctx = PutTaskWatchdog(ctx)
+	assert.True(t, IsDeadlineExceeded(ctx))
+
+	wd, _ := getWatchdog(ctx)
+	assert.Equal(t, "flow time limit of 1h0m0s is reached", wd.reason)
+
+	ctx = PutFlowWatchdog(context.Background(), Limits{TaskTimeLimit: time.Minute}, time.Now())
+	ctx = PutTaskWatchdog(ctx)
+	assert.False(t, IsDeadlineExceeded(ctx))
+
+	wd, _ = getWatchdog(ctx)
+	assert.Equal(t, "task time limit of 1m0s is reached", wd.reason)
+}
+
+func TestWatchdogCheck(t *testing.T) {
+	limits := Limits{MaxIterations: 10, MaxToolCalls: 5, MaxDelegationDepth: 1}
+
+	// the chains out of the subtask execution are not limited
+	ctx, wd := enterAgentChain(PutFlowWatchdog(context.Background(), limits, time.Now()))
+	assert.Nil(t, wd)
+	assert.Empty(t, wd.check(100, 100))
+
+	ctx, wd = enterAgentChain(putSubtaskWatchdog(ctx))
+	assert.Empty(t, wd.check(9, 4))
+	assert.Equal(t, "maximum of 10 agent iterations is reached", wd.check(10, 0))
+	assert.Equal(t, "maximum of 5 tool calls is reached", wd.check(0, 5))
+	assert.Empty(t, wd.checkDelegation())
+
+	ctx, wd = enterAgentChain(ctx)
+	assert.Empty(t, wd.checkDelegation())
+
+	_, wd = enterAgentChain(ctx)
+	assert.True(t, strings.HasPrefix(wd.checkDelegation(), "maximum delegation depth of 1 is reached"))
+
+	wd.deadline, wd.reason = time.Now().Add(-time.Second), "subtask time limit of 1s is reached"
+	assert.Equal(t, wd.reason, wd.check(0, 0))
+}
+
+func TestLimitResult(t *testing.T) {
+	_, ok := limitResult(errors.New("failed to call agent chain"))
+	assert.False(t, ok)
+
+	err := fmt.Errorf("failed to perform chain: %w", &LimitError{Reason: "maximum of 5 tool calls is reached", Summary: "Port 22 is open"})
+	assert.ErrorIs(t, err, ErrLimitReached)
+
+	result, ok := limitResult(err)
+	assert.True(t, ok)
+	assert.Contains(t, result, "maximum of 5 tool calls is reached")
+	assert.True(t, strings.HasSuffix(result, "Port 22 is open"))
+}
+
+func TestStopAgentChainWithoutWork(t *testing.T) {
+	fp := &flowProvider{}
+	chain := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "You are a pentester."),
+		llms.TextParts(llms.ChatMessageTypeHuman, "Scan the target."),
+	}
+
+	// the summarizer isn't called if the agent has done nothing yet
+	err := fp.stopAgentChain(context.Background(), logrus.NewEntry(logrus.New()), chain, "maximum of 1 agent iterations is reached", nil)
+
+	var limitErr *LimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Empty(t, limitErr.Summary)
+	assert.Contains(t, limitErr.Result(), "no results were collected")
+}
+
+func TestNotifyLimitReached(t *testing.T) {
+	var reasons []string
+	handler := func(_ context.Context, taskID, subtaskID int64, reason string) {
+		reasons = append(reasons, fmt.Sprintf("%d/%d: %s", taskID, subtaskID, reason))
+	}
+
+	// the handler needs the flow watchdog
+	ctx := PutLimitHandler(context.Background(), handler)
+	notifyLimitReached(ctx, 1, 2, &LimitError{Reason: "maximum of 5 tool calls is reached"})
+	assert.Empty(t, reasons)
+
+	ctx = PutLimitHandler(PutFlowWatchdog(context.Background(), Limits{}, time.Now()), handler)
+	notifyLimitReached(ctx, 1, 2, errors.New("failed to call agent chain"))
+	notifyLimitReached(ctx, 1, 2, fmt.Errorf("failed to perform chain: %w", &LimitError{Reason: "maximum of 5 tool calls is reached"}))
+	assert.Equal(t, []string{"1/2: maximum of 5 tool calls is reached"}, reasons)
+}
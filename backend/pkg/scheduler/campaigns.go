@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TargetPlaceholder is replaced by the campaign target in the flow input,
+// playbook campaigns get the target as the "target" variable instead
+const TargetPlaceholder = "{{.target}}"
+
+// TargetInput returns the flow input for the campaign target, the target is appended
+// to the input if it doesn't contain the placeholder
+func TargetInput(input, target string) string {
+	if strings.Contains(input, TargetPlaceholder) {
+		return strings.ReplaceAll(input, TargetPlaceholder, target)
+	}
+
+	return fmt.Sprintf("%s\n\nTarget: %s", input, target)
+}
+
+// TargetVariables returns the playbook variables with the campaign target set as "target"
+func TargetVariables(variables json.RawMessage, target string) (json.RawMessage, error) {
+	values := make(map[string]string)
+	if len(variables) != 0 {
+		if err := json.Unmarshal(variables, &values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal playbook variables: %w", err)
+		}
+	}
+
+	values["target"] = target
+
+	return json.Marshal(values)
+}
+
+// IsActiveFlow reports whether the flow is still consuming the campaign concurrency slot,
+// waiting flows have finished the task and wait for the user input
+func IsActiveFlow(status database.FlowStatus) bool {
+	switch status {
+	case database.FlowStatusQueued, database.FlowStatusCreated, database.FlowStatusRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *scheduler) dispatchCampaigns(ctx context.Context) {
+	campaigns, err := s.db.GetRunningCampaigns(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get running campaigns")
+		return
+	}
+
+	for _, campaign := range campaigns {
+		if ctx.Err() != nil {
+			return
+		}
+		s.dispatch(ctx, campaign)
+	}
+}
+
+func (s *scheduler) dispatch(ctx context.Context, campaign database.Campaign) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"campaign_id": campaign.ID,
+		"user_id":     campaign.UserID,
+	})
+
+	flows, err := s.db.GetCampaignFlows(ctx, campaign.ID)
+	if err != nil {
+		logger.WithError(err).Error("failed to get campaign flows")
+		return
+	}
+
+	var active int32
+	for _, flow := range flows {
+		if IsActiveFlow(flow.Status) {
+			active++
+		}
+	}
+
+	var pending []database.CampaignTarget
+	if active < campaign.Concurrency {
+		pending, err = s.db.GetCampaignPendingTargets(ctx, database.GetCampaignPendingTargetsParams{
+			CampaignID: campaign.ID,
+			Limit:      campaign.Concurrency - active,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to get campaign pending targets")
+			return
+		}
+	}
+
+	if active == 0 && len(pending) == 0 {
+		_, err := s.db.UpdateCampaignStatus(ctx, database.UpdateCampaignStatusParams{
+			Status: database.CampaignStatusFinished,
+			ID:     campaign.ID,
+		})
+		if err != nil {
+			logger.WithError(err).Error("failed to finish campaign")
+		} else {
+			logger.Info("all campaign targets are processed")
+		}
+		return
+	}
+
+	// targets aren't started on behalf of the owner who was blocked or lost the privileges,
+	// they are failed with the reason and the campaign is finished as with any other failures
+	var ownerErr error
+	if len(pending) != 0 {
+		ownerErr = s.checkOwner(ctx, campaign.UserID, "campaigns.create")
+		if ownerErr != nil && !errors.Is(ownerErr, errOwnerNotPermitted) {
+			logger.WithError(ownerErr).Error("failed to check campaign owner")
+			return
+		}
+	}
+
+	for _, target := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		if ownerErr != nil {
+			s.failTarget(ctx, target, ownerErr, logger.WithField("target_id", target.ID))
+			continue
+		}
+		s.startTarget(ctx, campaign, target, logger.WithField("target_id", target.ID))
+	}
+}
+
+func (s *scheduler) failTarget(ctx context.Context, target database.CampaignTarget, reason error, logger *logrus.Entry) {
+	logger.WithError(reason).Warn("campaign target is not started")
+
+	_, err := s.db.UpdateCampaignTarget(ctx, database.UpdateCampaignTargetParams{
+		ID:     target.ID,
+		Status: database.CampaignTargetStatusFailed,
+		Reason: reason.Error(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to update campaign target")
+	}
+}
+
+func (s *scheduler) startTarget(
+	ctx context.Context,
+	campaign database.Campaign,
+	target database.CampaignTarget,
+	logger *logrus.Entry,
+) {
+	params := database.UpdateCampaignTargetParams{
+		ID: target.ID,
+	}
+
+	fl := flowLaunch{
+		userID:     campaign.UserID,
+		playbookID: campaign.PlaybookID,
+		scope:      campaign.Scope,
+		prvname:    provider.ProviderName(campaign.ModelProviderName),
+		planReview: campaign.PlanReview,
+		// batch flows give way to the flows which are created by users
+		priority: database.FlowPriorityLow,
+	}
+
+	var (
+		flowID int64
+		err    error
+	)
+	if campaign.PlaybookID.Valid {
+		fl.variables, err = TargetVariables(campaign.Variables, target.Target)
+	} else {
+		fl.input = TargetInput(campaign.Input, target.Target)
+	}
+	if err == nil {
+		flowID, err = s.launch(ctx, fl)
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("failed to create campaign flow")
+		params.Status = database.CampaignTargetStatusFailed
+		params.Reason = err.Error()
+	} else {
+		logger.WithField("flow_id", flowID).Info("campaign flow created")
+		params.Status = database.CampaignTargetStatusStarted
+		params.FlowID = database.Int64ToNullInt64(&flowID)
+	}
+
+	if _, err := s.db.UpdateCampaignTarget(ctx, params); err != nil {
+		logger.WithError(err).Error("failed to update campaign target")
+	}
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"pentagi/pkg/database"
+)
+
+func TestTargetInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"placeholder", "Scan {{.target}} and check {{.target}} for TLS issues", "Scan 10.0.0.1 and check 10.0.0.1 for TLS issues"},
+		{"appended", "Run the web application assessment", "Run the web application assessment\n\nTarget: 10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if input := TargetInput(tt.input, "10.0.0.1"); input != tt.want {
+				t.Errorf("Expected input %q, got %q", tt.want, input)
+			}
+		})
+	}
+}
+
+func TestTargetVariables(t *testing.T) {
+	variables, err := TargetVariables(json.RawMessage(`{"target":"old","port":"443"}`), "example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(variables, &values); err != nil {
+		t.Fatalf("Failed to unmarshal variables: %v", err)
+	}
+	if values["target"] != "example.com" || values["port"] != "443" {
+		t.Errorf("Unexpected variables: %v", values)
+	}
+
+	if _, err := TargetVariables(json.RawMessage(`[]`), "example.com"); err == nil {
+		t.Errorf("Expected error for invalid variables")
+	}
+}
+
+// campaignQuerier keeps the pending targets of the single campaign and records the target updates
+type campaignQuerier struct {
+	*schedulerQuerier
+	pending []database.CampaignTarget
+	updates []database.UpdateCampaignTargetParams
+}
+
+func (q *campaignQuerier) GetCampaignFlows(_ context.Context, _ int64) ([]database.Flow, error) {
+	return nil, nil
+}
+
+func (q *campaignQuerier) GetCampaignPendingTargets(
+	_ context.Context,
+	arg database.GetCampaignPendingTargetsParams,
+) ([]database.CampaignTarget, error) {
+	return q.pending[:min(len(q.pending), int(arg.Limit))], nil
+}
+
+func (q *campaignQuerier) UpdateCampaignTarget(
+	_ context.Context,
+	arg database.UpdateCampaignTargetParams,
+) (database.CampaignTarget, error) {
+	q.updates = append(q.updates, arg)
+	return database.CampaignTarget{ID: arg.ID, Status: arg.Status}, nil
+}
+
+func TestDispatchOwner(t *testing.T) {
+	campaign := database.Campaign{
+		ID:                3,
+		UserID:            1,
+		Input:             "Scan {{.target}}",
+		ModelProviderName: "openai",
+		Concurrency:       2,
+	}
+
+	tests := []struct {
+		name   string
+		status database.UserStatus
+		privs  []string
+		want   database.CampaignTargetStatus
+		flows  int
+	}{
+		{"permitted", database.UserStatusActive, []string{"flows.create", "campaigns.create"}, database.CampaignTargetStatusStarted, 2},
+		{"blocked", database.UserStatusBlocked, []string{"flows.create", "campaigns.create"}, database.CampaignTargetStatusFailed, 0},
+		{"campaigns revoked", database.UserStatusActive, []string{"flows.create"}, database.CampaignTargetStatusFailed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &campaignQuerier{
+				schedulerQuerier: &schedulerQuerier{owner: database.GetUserRow{ID: 1, Status: tt.status, Privileges: tt.privs}},
+				pending: []database.CampaignTarget{
+					{ID: 11, CampaignID: 3, Target: "10.0.0.1"},
+					{ID: 12, CampaignID: 3, Target: "10.0.0.2"},
+				},
+			}
+			flows := &stubFlows{}
+			s := NewScheduler(db, flows, stubProviders{}).(*scheduler)
+
+			s.dispatch(context.Background(), campaign)
+
+			if len(flows.inputs) != tt.flows {
+				t.Errorf("Expected %d flows, got %v", tt.flows, flows.inputs)
+			}
+			if len(db.updates) != 2 {
+				t.Fatalf("Expected both targets to be updated, got %v", db.updates)
+			}
+			for _, update := range db.updates {
+				if update.Status != tt.want {
+					t.Errorf("Expected target %d to be %s, got %s", update.ID, tt.want, update.Status)
+				}
+				if tt.want == database.CampaignTargetStatusFailed && !strings.Contains(update.Reason, "not permitted") {
+					t.Errorf("Expected the owner reason, got %q", update.Reason)
+				}
+			}
+		})
+	}
+}
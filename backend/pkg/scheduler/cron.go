@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrInvalidCron     = errors.New("invalid cron expression")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrNeverFires      = errors.New("cron expression never fires")
+)
+
+// standard five fields cron expression, descriptors like @daily and @every 1h are accepted as well
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// NextRun returns the first fire time of the cron expression after the given time,
+// the expression is evaluated in the timezone (IANA name, e.g. Europe/Berlin)
+func NextRun(expr, timezone string, after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTimezone, timezone)
+	}
+
+	if expr = strings.TrimSpace(expr); strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		return time.Time{}, fmt.Errorf("%w: timezone must be set separately", ErrInvalidCron)
+	}
+
+	sched, err := cronParser.Parse(expr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidCron, err)
+	}
+
+	next := sched.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrNeverFires, expr)
+	}
+
+	return next, nil
+}
+
+// WithScope appends the scope of work to the flow input, so every scheduled flow
+// is restricted by the same targets and rules
+func WithScope(input, scope string) string {
+	if scope = strings.TrimSpace(scope); scope == "" {
+		return input
+	}
+
+	return fmt.Sprintf("%s\n\nScope of work:\n%s", input, scope)
+}
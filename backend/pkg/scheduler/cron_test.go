@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2025, 11, 10, 10, 30, 0, 0, time.UTC) // Monday
+
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		want     time.Time
+	}{
+		{"weekly", "0 3 * * 1", "UTC", time.Date(2025, 11, 17, 3, 0, 0, 0, time.UTC)},
+		{"hourly", "15 * * * *", "UTC", time.Date(2025, 11, 10, 11, 15, 0, 0, time.UTC)},
+		{"descriptor", "@daily", "UTC", time.Date(2025, 11, 11, 0, 0, 0, 0, time.UTC)},
+		{"timezone", "0 12 * * *", "Europe/Berlin", time.Date(2025, 11, 10, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := NextRun(tt.expr, tt.timezone, after)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !next.Equal(tt.want) {
+				t.Errorf("Expected next run %v, got %v", tt.want, next.UTC())
+			}
+		})
+	}
+}
+
+func TestNextRunErrors(t *testing.T) {
+	after := time.Date(2025, 11, 10, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		err      error
+	}{
+		{"invalid expression", "every monday", "UTC", ErrInvalidCron},
+		{"seconds field", "0 0 3 * * 1", "UTC", ErrInvalidCron},
+		{"inline timezone", "CRON_TZ=Europe/Berlin 0 3 * * 1", "UTC", ErrInvalidCron},
+		{"invalid timezone", "0 3 * * 1", "Mars/Olympus", ErrInvalidTimezone},
+		{"never fires", "0 0 30 2 *", "UTC", ErrNeverFires},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NextRun(tt.expr, tt.timezone, after); !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRenderPlaybook(t *testing.T) {
+	const content = `
+name: Weekly retest
+variables:
+  - name: target
+    required: true
+tasks:
+  - input: "Scan {{.target}}"
+  - input: "Report findings for {{.target}}"
+`
+
+	variables, _ := json.Marshal(map[string]string{"target": "10.0.0.1"})
+	doc, err := RenderPlaybook(content, variables, "Only 10.0.0.0/24, no DoS")
+	if err != nil {
+		t.Fatalf("Failed to render playbook: %v", err)
+	}
+
+	for _, task := range doc.Tasks {
+		if !strings.Contains(task.Input, "10.0.0.1") || !strings.HasSuffix(task.Input, "Only 10.0.0.0/24, no DoS") {
+			t.Errorf("Unexpected task input: %q", task.Input)
+		}
+	}
+
+	if _, err := RenderPlaybook(content, json.RawMessage("{}"), ""); err == nil {
+		t.Errorf("Expected error for missing required variable")
+	}
+
+	if input := WithScope("Scan target", " "); input != "Scan target" {
+		t.Errorf("Expected input without scope, got %q", input)
+	}
+}
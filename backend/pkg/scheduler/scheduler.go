@@ -0,0 +1,288 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pentagi/pkg/controller"
+	"pentagi/pkg/database"
+	"pentagi/pkg/playbook"
+	"pentagi/pkg/providers"
+	"pentagi/pkg/providers/provider"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tickInterval = 30 * time.Second
+	// fire time which is older than the threshold is treated as missed (e.g. the backend was down)
+	// and it's handled by the schedule missed run policy
+	missedRunThreshold = 5 * time.Minute
+)
+
+var (
+	errPreviousRunActive = errors.New("previous run is still in progress")
+	errOwnerNotPermitted = errors.New("owner is not permitted to create flows")
+)
+
+type Scheduler interface {
+	Start()
+	Stop()
+}
+
+type scheduler struct {
+	db     database.Querier
+	flows  controller.FlowController
+	provs  providers.ProviderController
+	wg     *sync.WaitGroup
+	cancel context.CancelFunc
+	logger *logrus.Entry
+}
+
+// NewScheduler returns the scheduler which creates flows of enabled schedules on time
+// and starts pending campaign targets from the single goroutine, so the schedule is never
+// fired twice for the same time and the campaign never exceeds its concurrency limit
+func NewScheduler(
+	db database.Querier,
+	flows controller.FlowController,
+	provs providers.ProviderController,
+) Scheduler {
+	return &scheduler{
+		db:     db,
+		flows:  flows,
+		provs:  provs,
+		wg:     &sync.WaitGroup{},
+		cancel: func() {},
+		logger: logrus.WithField("component", "scheduler"),
+	}
+}
+
+func (s *scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		for {
+			s.tick(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *scheduler) tick(ctx context.Context, now time.Time) {
+	schedules, err := s.db.GetDueSchedules(ctx, database.TimeToNullTime(now))
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get due schedules")
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		s.fire(ctx, schedule, now)
+	}
+
+	s.dispatchCampaigns(ctx)
+}
+
+func (s *scheduler) fire(ctx context.Context, schedule database.Schedule, now time.Time) {
+	logger := s.logger.WithFields(logrus.Fields{
+		"schedule_id":  schedule.ID,
+		"user_id":      schedule.UserID,
+		"scheduled_at": schedule.NextRunAt.Time,
+	})
+
+	// next run is calculated from the current time, so several missed fire times produce only one run
+	nextRunAt := sql.NullTime{}
+	if next, err := NextRun(schedule.Cron, schedule.Timezone, now); err != nil {
+		logger.WithError(err).Error("failed to calculate next run, schedule is suspended")
+	} else {
+		nextRunAt = database.TimeToNullTime(next)
+	}
+
+	run := database.CreateScheduleRunParams{
+		ScheduleID:  schedule.ID,
+		ScheduledAt: schedule.NextRunAt.Time,
+	}
+
+	late := now.Sub(schedule.NextRunAt.Time)
+	if late > missedRunThreshold && schedule.MissedPolicy == database.ScheduleMissedPolicySkip {
+		run.Status = database.ScheduleRunStatusSkipped
+		run.Reason = fmt.Sprintf("missed run, the scheduler was late by %s", late.Round(time.Second))
+	} else if flowID, err := s.run(ctx, schedule); errors.Is(err, errPreviousRunActive) || errors.Is(err, errOwnerNotPermitted) {
+		run.Status = database.ScheduleRunStatusSkipped
+		run.Reason = err.Error()
+	} else if err != nil {
+		logger.WithError(err).Error("failed to create scheduled flow")
+		run.Status = database.ScheduleRunStatusFailed
+		run.Reason = err.Error()
+	} else {
+		logger.WithField("flow_id", flowID).Info("scheduled flow created")
+		run.Status = database.ScheduleRunStatusStarted
+		run.FlowID = database.Int64ToNullInt64(&flowID)
+	}
+
+	if _, err := s.db.CreateScheduleRun(ctx, run); err != nil {
+		logger.WithError(err).Error("failed to store schedule run")
+	}
+
+	_, err := s.db.UpdateScheduleNextRun(ctx, database.UpdateScheduleNextRunParams{
+		ID:        schedule.ID,
+		NextRunAt: nextRunAt,
+		LastRunAt: database.TimeToNullTime(now),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to update schedule next run")
+	}
+}
+
+func (s *scheduler) run(ctx context.Context, schedule database.Schedule) (int64, error) {
+	if err := s.checkOwner(ctx, schedule.UserID, "schedules.create"); err != nil {
+		return 0, err
+	}
+
+	active, err := s.db.GetScheduleActiveFlows(ctx, schedule.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schedule active flows: %w", err)
+	}
+	if len(active) != 0 {
+		return 0, fmt.Errorf("%w: flow %d", errPreviousRunActive, active[0].ID)
+	}
+
+	return s.launch(ctx, flowLaunch{
+		userID:     schedule.UserID,
+		input:      schedule.Input,
+		playbookID: schedule.PlaybookID,
+		variables:  schedule.Variables,
+		scope:      schedule.Scope,
+		prvname:    provider.ProviderName(schedule.ModelProviderName),
+		planReview: schedule.PlanReview,
+		priority:   database.FlowPriorityNormal,
+	})
+}
+
+// checkOwner checks that the owner of the schedule or the campaign is still active and keeps
+// the privileges which were required to create it, the owner can be blocked or lose the privileges later
+func (s *scheduler) checkOwner(ctx context.Context, userID int64, priv string) error {
+	user, err := s.db.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get owner %d: %w", userID, err)
+	}
+
+	if user.Status != database.UserStatusActive {
+		return fmt.Errorf("%w: user status is '%s'", errOwnerNotPermitted, user.Status)
+	}
+
+	for _, priv := range []string{priv, "flows.create"} {
+		// the admin privilege of the same resource grants all its actions like in the API
+		admin := priv[:strings.LastIndex(priv, ".")] + ".admin"
+		if !slices.Contains(user.Privileges, priv) && !slices.Contains(user.Privileges, admin) {
+			return fmt.Errorf("%w: privilege '%s' is revoked", errOwnerNotPermitted, priv)
+		}
+	}
+
+	return nil
+}
+
+// flowLaunch holds the flow definition shared by schedules and campaigns
+type flowLaunch struct {
+	userID     int64
+	input      string
+	playbookID sql.NullInt64
+	variables  json.RawMessage
+	scope      string
+	prvname    provider.ProviderName
+	planReview bool
+	priority   database.FlowPriority
+}
+
+func (s *scheduler) launch(ctx context.Context, fl flowLaunch) (int64, error) {
+	prv, err := s.provs.GetProvider(ctx, fl.prvname, fl.userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get provider: %w", err)
+	}
+	prvtype := prv.Type()
+
+	if !fl.playbookID.Valid {
+		if fl.input == "" {
+			return 0, fmt.Errorf("neither input nor playbook is set, the playbook may be deleted")
+		}
+
+		input := WithScope(fl.input, fl.scope)
+		flow, err := s.flows.CreateFlow(ctx, fl.userID, input, fl.prvname, prvtype, nil, fl.planReview, fl.priority, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		return flow.ID, nil
+	}
+
+	pb, err := s.db.GetUserPlaybook(ctx, database.GetUserPlaybookParams{
+		ID:     fl.playbookID.Int64,
+		UserID: fl.userID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get playbook %d: %w", fl.playbookID.Int64, err)
+	}
+
+	doc, err := RenderPlaybook(pb.Content, fl.variables, fl.scope)
+	if err != nil {
+		return 0, err
+	}
+
+	flow, err := s.flows.CreateFlowFromPlaybook(ctx, fl.userID, &pb.ID, doc, fl.prvname, prvtype, fl.planReview, fl.priority, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return flow.ID, nil
+}
+
+// RenderPlaybook parses the playbook and renders it with the stored variable values,
+// the scope of work is appended to the input of every playbook task
+func RenderPlaybook(content string, variables json.RawMessage, scope string) (*playbook.Playbook, error) {
+	doc, err := playbook.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if len(variables) != 0 {
+		if err := json.Unmarshal(variables, &values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal playbook variables: %w", err)
+		}
+	}
+
+	if doc, err = doc.Render(values); err != nil {
+		return nil, err
+	}
+
+	for idx := range doc.Tasks {
+		doc.Tasks[idx].Input = WithScope(doc.Tasks[idx].Input, scope)
+	}
+
+	return doc, nil
+}
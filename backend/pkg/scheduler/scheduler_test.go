@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"pentagi/pkg/controller"
+	"pentagi/pkg/database"
+	"pentagi/pkg/providers"
+	"pentagi/pkg/providers/provider"
+	"pentagi/pkg/tools"
+)
+
+// schedulerQuerier keeps the state of the single schedule owner and records the schedule runs
+type schedulerQuerier struct {
+	database.Querier
+	owner    database.GetUserRow
+	active   []database.Flow
+	runs     []database.CreateScheduleRunParams
+	nextRuns []database.UpdateScheduleNextRunParams
+}
+
+func (q *schedulerQuerier) GetUser(_ context.Context, id int64) (database.GetUserRow, error) {
+	if id != q.owner.ID {
+		return database.GetUserRow{}, sql.ErrNoRows
+	}
+	return q.owner, nil
+}
+
+func (q *schedulerQuerier) GetScheduleActiveFlows(_ context.Context, _ int64) ([]database.Flow, error) {
+	return q.active, nil
+}
+
+func (q *schedulerQuerier) CreateScheduleRun(
+	_ context.Context,
+	arg database.CreateScheduleRunParams,
+) (database.ScheduleRun, error) {
+	q.runs = append(q.runs, arg)
+	return database.ScheduleRun{ID: int64(len(q.runs)), ScheduleID: arg.ScheduleID, Status: arg.Status}, nil
+}
+
+func (q *schedulerQuerier) UpdateScheduleNextRun(
+	_ context.Context,
+	arg database.UpdateScheduleNextRunParams,
+) (database.Schedule, error) {
+	q.nextRuns = append(q.nextRuns, arg)
+	return database.Schedule{ID: arg.ID, NextRunAt: arg.NextRunAt, LastRunAt: arg.LastRunAt}, nil
+}
+
+// stubFlows creates flows with sequential IDs and keeps their inputs
+type stubFlows struct {
+	controller.FlowController
+	inputs []string
+}
+
+func (fc *stubFlows) CreateFlow(
+	_ context.Context,
+	userID int64,
+	input string,
+	_ provider.ProviderName,
+	_ provider.ProviderType,
+	_ *tools.Functions,
+	_ bool,
+	_ database.FlowPriority,
+	_ *database.FlowLimit,
+) (database.Flow, error) {
+	fc.inputs = append(fc.inputs, input)
+	return database.Flow{ID: int64(100 + len(fc.inputs)), UserID: userID}, nil
+}
+
+type stubProvider struct {
+	provider.Provider
+}
+
+func (stubProvider) Type() provider.ProviderType { return provider.ProviderOpenAI }
+
+type stubProviders struct {
+	providers.ProviderController
+}
+
+func (stubProviders) GetProvider(context.Context, provider.ProviderName, int64) (provider.Provider, error) {
+	return stubProvider{}, nil
+}
+
+func newTestScheduler() (*scheduler, *schedulerQuerier, *stubFlows) {
+	db := &schedulerQuerier{owner: database.GetUserRow{
+		ID:         1,
+		Status:     database.UserStatusActive,
+		Privileges: []string{"flows.create", "schedules.create"},
+	}}
+	flows := &stubFlows{}
+
+	return NewScheduler(db, flows, stubProviders{}).(*scheduler), db, flows
+}
+
+func testSchedule(nextRunAt time.Time, policy database.ScheduleMissedPolicy) database.Schedule {
+	return database.Schedule{
+		ID:                7,
+		UserID:            1,
+		Cron:              "0 3 * * *",
+		Timezone:          "UTC",
+		Input:             "Scan the perimeter",
+		ModelProviderName: "openai",
+		MissedPolicy:      policy,
+		Enabled:           true,
+		NextRunAt:         database.TimeToNullTime(nextRunAt),
+	}
+}
+
+func TestFireRunHistory(t *testing.T) {
+	s, db, flows := newTestScheduler()
+	now := time.Date(2025, 11, 10, 3, 0, 10, 0, time.UTC)
+
+	s.fire(context.Background(), testSchedule(now.Add(-10*time.Second), database.ScheduleMissedPolicySkip), now)
+
+	if len(flows.inputs) != 1 || flows.inputs[0] != "Scan the perimeter" {
+		t.Fatalf("Expected one flow with the schedule input, got %v", flows.inputs)
+	}
+	if len(db.runs) != 1 {
+		t.Fatalf("Expected one schedule run, got %d", len(db.runs))
+	}
+
+	run := db.runs[0]
+	if run.Status != database.ScheduleRunStatusStarted || run.FlowID.Int64 != 101 {
+		t.Errorf("Expected started run of flow 101, got %s run of flow %v", run.Status, run.FlowID)
+	}
+	if !run.ScheduledAt.Equal(now.Add(-10 * time.Second)) {
+		t.Errorf("Expected run scheduled at the fire time, got %v", run.ScheduledAt)
+	}
+
+	if len(db.nextRuns) != 1 {
+		t.Fatalf("Expected next run update, got %d", len(db.nextRuns))
+	}
+	next := db.nextRuns[0]
+	if want := time.Date(2025, 11, 11, 3, 0, 0, 0, time.UTC); !next.NextRunAt.Time.Equal(want) {
+		t.Errorf("Expected next run %v, got %v", want, next.NextRunAt.Time)
+	}
+	if !next.LastRunAt.Time.Equal(now) {
+		t.Errorf("Expected last run %v, got %v", now, next.LastRunAt.Time)
+	}
+}
+
+func TestFireOverlap(t *testing.T) {
+	s, db, flows := newTestScheduler()
+	db.active = []database.Flow{{ID: 42, Status: database.FlowStatusRunning}}
+	now := time.Date(2025, 11, 10, 3, 0, 10, 0, time.UTC)
+
+	s.fire(context.Background(), testSchedule(now, database.ScheduleMissedPolicyRunOnce), now)
+
+	if len(flows.inputs) != 0 {
+		t.Errorf("Expected no flow while the previous run is active, got %v", flows.inputs)
+	}
+	if len(db.runs) != 1 || db.runs[0].Status != database.ScheduleRunStatusSkipped {
+		t.Fatalf("Expected skipped run, got %v", db.runs)
+	}
+	if !strings.Contains(db.runs[0].Reason, "flow 42") {
+		t.Errorf("Expected reason with the active flow, got %q", db.runs[0].Reason)
+	}
+	if len(db.nextRuns) != 1 || !db.nextRuns[0].NextRunAt.Valid {
+		t.Errorf("Expected next run to be scheduled after the skipped one, got %v", db.nextRuns)
+	}
+}
+
+func TestFireMissedRun(t *testing.T) {
+	now := time.Date(2025, 11, 10, 3, 30, 0, 0, time.UTC)
+	missed := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		policy database.ScheduleMissedPolicy
+		status database.ScheduleRunStatus
+		flows  int
+	}{
+		{"skip", database.ScheduleMissedPolicySkip, database.ScheduleRunStatusSkipped, 0},
+		{"run once", database.ScheduleMissedPolicyRunOnce, database.ScheduleRunStatusStarted, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, flows := newTestScheduler()
+
+			s.fire(context.Background(), testSchedule(missed, tt.policy), now)
+
+			if len(flows.inputs) != tt.flows {
+				t.Errorf("Expected %d flows, got %d", tt.flows, len(flows.inputs))
+			}
+			if len(db.runs) != 1 || db.runs[0].Status != tt.status {
+				t.Fatalf("Expected %s run, got %v", tt.status, db.runs)
+			}
+			if !db.runs[0].ScheduledAt.Equal(missed) {
+				t.Errorf("Expected run of the missed fire time, got %v", db.runs[0].ScheduledAt)
+			}
+		})
+	}
+}
+
+func TestFireOwnerNotPermitted(t *testing.T) {
+	now := time.Date(2025, 11, 10, 3, 0, 10, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status database.UserStatus
+		privs  []string
+		reason string
+	}{
+		{"blocked", database.UserStatusBlocked, []string{"flows.create", "schedules.create"}, "blocked"},
+		{"schedules revoked", database.UserStatusActive, []string{"flows.create"}, "schedules.create"},
+		{"flows revoked", database.UserStatusActive, []string{"schedules.create"}, "flows.create"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db, flows := newTestScheduler()
+			db.owner.Status, db.owner.Privileges = tt.status, tt.privs
+
+			s.fire(context.Background(), testSchedule(now, database.ScheduleMissedPolicySkip), now)
+
+			if len(flows.inputs) != 0 {
+				t.Errorf("Expected no flow on behalf of the owner, got %v", flows.inputs)
+			}
+			if len(db.runs) != 1 || db.runs[0].Status != database.ScheduleRunStatusSkipped {
+				t.Fatalf("Expected skipped run, got %v", db.runs)
+			}
+			if !strings.Contains(db.runs[0].Reason, tt.reason) {
+				t.Errorf("Expected reason with %q, got %q", tt.reason, db.runs[0].Reason)
+			}
+		})
+	}
+}
+
+func TestCheckOwnerAdminPrivilege(t *testing.T) {
+	s, db, _ := newTestScheduler()
+	db.owner.Privileges = []string{"flows.admin", "schedules.admin"}
+
+	if err := s.checkOwner(context.Background(), 1, "schedules.create"); err != nil {
+		t.Errorf("Expected admin privileges to be enough, got %v", err)
+	}
+}
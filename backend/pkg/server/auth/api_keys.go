@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix marks the personal API key in the bearer token to tell it apart from the proto JWT
+	APIKeyPrefix = "pak_"
+	// PrivilegesAPIKeysPrefix is the prefix of the privileges to manage API keys, these privileges
+	// are never granted to API keys, so a leaked key can't issue new keys
+	PrivilegesAPIKeysPrefix = "api_keys."
+
+	apiKeyLength        = 32
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	// last used timestamp is updated not more often than this interval to avoid writes on each request
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// GenerateAPIKey returns the new random API key, its prefix to display and the hash to store
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	buf := make([]byte, apiKeyLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key = APIKeyPrefix + hex.EncodeToString(buf)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash of the API key which is stored in the DB, the key has enough
+// entropy, so the plain SHA-256 is used to find the key by the hash
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsAPIKey returns true if the token looks like the personal API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// ValidateAPIKeyPrivileges checks that the requested privileges are the subset of the user privileges
+func ValidateAPIKeyPrivileges(userPrivs, keyPrivs []string) error {
+	if len(keyPrivs) == 0 {
+		return errors.New("at least one privilege is required")
+	}
+
+	for _, priv := range keyPrivs {
+		if strings.HasPrefix(priv, PrivilegesAPIKeysPrefix) {
+			return fmt.Errorf("privilege '%s' can't be granted to api key", priv)
+		}
+		if !slices.Contains(userPrivs, priv) {
+			return fmt.Errorf("privilege '%s' is not granted to user", priv)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAPIKeyExpiry checks that the expiry time is set in the future
+func ValidateAPIKeyExpiry(expiresAt time.Time) error {
+	if expiresAt.IsZero() {
+		return errors.New("expiry time is required")
+	}
+	if !expiresAt.After(time.Now()) {
+		return errors.New("expiry time must be in the future")
+	}
+
+	return nil
+}
+
+// APIKeyPrivileges returns the privileges of the API key which are still granted to the user role,
+// so the key loses the privileges which are revoked from the user after the key creation
+func APIKeyPrivileges(userPrivs, keyPrivs []string) []string {
+	privs := make([]string, 0, len(keyPrivs))
+	for _, priv := range keyPrivs {
+		if strings.HasPrefix(priv, PrivilegesAPIKeysPrefix) || !slices.Contains(userPrivs, priv) {
+			continue
+		}
+		privs = append(privs, priv)
+	}
+
+	return privs
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, IsAPIKey(key))
+	assert.Len(t, key, len(APIKeyPrefix)+2*apiKeyLength)
+	assert.Equal(t, key[:apiKeyDisplayLength], prefix)
+	assert.Equal(t, HashAPIKey(key), hash)
+	assert.Len(t, hash, 64)
+	assert.NotContains(t, hash, key)
+
+	other, _, otherHash, err := GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, hash, otherHash)
+
+	assert.False(t, IsAPIKey("eyJhbGciOiJIUzI1NiJ9"))
+}
+
+func TestValidateAPIKeyPrivileges(t *testing.T) {
+	userPrivs := []string{"flows.view", "flows.create", "api_keys.create"}
+
+	assert.NoError(t, ValidateAPIKeyPrivileges(userPrivs, []string{"flows.view"}))
+	assert.NoError(t, ValidateAPIKeyPrivileges(userPrivs, []string{"flows.view", "flows.create"}))
+
+	assert.Error(t, ValidateAPIKeyPrivileges(userPrivs, nil))
+	assert.Error(t, ValidateAPIKeyPrivileges(userPrivs, []string{"flows.delete"}))
+	assert.Error(t, ValidateAPIKeyPrivileges(userPrivs, []string{"flows.view", "api_keys.create"}))
+}
+
+func TestValidateAPIKeyExpiry(t *testing.T) {
+	assert.NoError(t, ValidateAPIKeyExpiry(time.Now().Add(time.Hour)))
+	assert.Error(t, ValidateAPIKeyExpiry(time.Time{}))
+	assert.Error(t, ValidateAPIKeyExpiry(time.Now().Add(-time.Second)))
+}
+
+func TestAPIKeyPrivileges(t *testing.T) {
+	userPrivs := []string{"flows.view", "flows.create", "api_keys.view"}
+
+	assert.Equal(t,
+		[]string{"flows.view"},
+		APIKeyPrivileges(userPrivs, []string{"flows.view", "flows.delete", "api_keys.view"}),
+	)
+	assert.Empty(t, APIKeyPrivileges(nil, []string{"flows.view"}))
+}
+
+func TestAuthRequiredWithAPIKeyWithoutDB(t *testing.T) {
+	authMiddleware := NewAuthMiddleware("/base/url", "test", nil)
+
+	server := newTestServer(t, "/test", authMiddleware.AuthRequired)
+	defer server.Close()
+
+	key, _, _, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.False(t, server.CallAndGetStatus(t, "Bearer "+key))
+}
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
+	"pentagi/pkg/server/logger"
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/response"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/jinzhu/gorm"
 )
 
 type authResult int
@@ -25,24 +28,27 @@ const (
 
 type AuthMiddleware struct {
 	globalSalt string
+	db         *gorm.DB
 }
 
-func NewAuthMiddleware(baseURL, globalSalt string) *AuthMiddleware {
+// NewAuthMiddleware returns the auth middleware, the personal API keys are checked only if db is set
+func NewAuthMiddleware(baseURL, globalSalt string, db *gorm.DB) *AuthMiddleware {
 	return &AuthMiddleware{
 		globalSalt: globalSalt,
+		db:         db,
 	}
 }
 
 func (p *AuthMiddleware) AuthRequired(c *gin.Context) {
-	p.tryAuth(c, true, p.tryUserCookieAuthentication)
+	p.tryAuth(c, true, p.tryAPIKeyAuthentication, p.tryUserCookieAuthentication)
 }
 
 func (p *AuthMiddleware) AuthTokenProtoRequired(c *gin.Context) {
-	p.tryAuth(c, true, p.tryProtoTokenAuthentication, p.tryUserCookieAuthentication)
+	p.tryAuth(c, true, p.tryAPIKeyAuthentication, p.tryProtoTokenAuthentication, p.tryUserCookieAuthentication)
 }
 
 func (p *AuthMiddleware) TryAuth(c *gin.Context) {
-	p.tryAuth(c, false, p.tryUserCookieAuthentication)
+	p.tryAuth(c, false, p.tryAPIKeyAuthentication, p.tryUserCookieAuthentication)
 }
 
 func (p *AuthMiddleware) tryAuth(
@@ -104,6 +110,21 @@ func (p *AuthMiddleware) tryUserCookieAuthentication(c *gin.Context) (authResult
 		return authResultFail, errors.New("no pemissions granted")
 	}
 
+	// role privileges can be changed while the session is alive, so the current ones are used
+	if p.db != nil {
+		userSession, err := p.checkUserSession(c, session.Get("sid"))
+		if err != nil {
+			return authResultFail, err
+		}
+		c.Set("sid", userSession.ID)
+
+		var rolePrivs []string
+		if err := p.db.Table("privileges").Where("role_id = ?", rid).Pluck("name", &rolePrivs).Error; err != nil {
+			return authResultFail, fmt.Errorf("error getting role privileges: %w", err)
+		}
+		prms = rolePrivs
+	}
+
 	c.Set("prm", prms)
 	c.Set("uid", uid.(uint64))
 	c.Set("uhash", uhash.(string))
@@ -122,6 +143,33 @@ func (p *AuthMiddleware) tryUserCookieAuthentication(c *gin.Context) (authResult
 
 const PrivilegeAutomation = "pentagi.automation"
 
+// checkUserSession returns the login session of the cookie, the session must be neither revoked nor expired
+func (p *AuthMiddleware) checkUserSession(c *gin.Context, sid interface{}) (*models.UserSession, error) {
+	sidStr, ok := sid.(string)
+	if !ok || sidStr == "" {
+		return nil, errors.New("session id is missing")
+	}
+
+	var userSession models.UserSession
+	if err := p.db.Take(&userSession, "sid_hash = ?", HashSessionID(sidStr)).Error; err != nil {
+		return nil, errors.New("session is unknown")
+	}
+
+	now := time.Now()
+	if !userSession.Active(now) {
+		return nil, errors.New("session is either revoked or expired")
+	}
+
+	if userSession.LastSeenAt == nil || now.Sub(*userSession.LastSeenAt) > sessionLastSeenInterval {
+		err := p.db.Model(&userSession).UpdateColumn("last_seen_at", now).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error updating session last seen time")
+		}
+	}
+
+	return &userSession, nil
+}
+
 func (p *AuthMiddleware) tryProtoTokenAuthentication(c *gin.Context) (authResult, error) {
 	authHeader := c.Request.Header.Get("Authorization")
 	if authHeader == "" {
@@ -153,6 +201,56 @@ func (p *AuthMiddleware) tryProtoTokenAuthentication(c *gin.Context) (authResult
 	return authResultOk, nil
 }
 
+// tryAPIKeyAuthentication authorizes the request by the personal API key from the bearer token,
+// the key privileges are limited by the current privileges of the user role
+func (p *AuthMiddleware) tryAPIKeyAuthentication(c *gin.Context) (authResult, error) {
+	token, ok := strings.CutPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+	if !ok || !IsAPIKey(token) || p.db == nil {
+		return authResultSkip, errors.New("api key required")
+	}
+
+	var key models.APIKey
+	if err := p.db.Take(&key, "key_hash = ?", HashAPIKey(token)).Error; err != nil {
+		return authResultFail, errors.New("api key is invalid")
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return authResultFail, errors.New("api key is either revoked or expired")
+	}
+
+	var user models.User
+	if err := p.db.Take(&user, "id = ?", key.UserID).Error; err != nil {
+		return authResultFail, errors.New("api key user not found")
+	} else if user.Status != models.UserStatusActive {
+		return authResultFail, errors.New("api key user is inactive")
+	}
+
+	var userPrivs []string
+	if err := p.db.Table("privileges").Where("role_id = ?", user.RoleID).Pluck("name", &userPrivs).Error; err != nil {
+		return authResultFail, fmt.Errorf("error getting user privileges: %w", err)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyLastUsedInterval {
+		err := p.db.Model(&key).UpdateColumn("last_used_at", now).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error updating api key last used time")
+		}
+	}
+
+	c.Set("prm", APIKeyPrivileges(userPrivs, key.Privileges))
+	c.Set("uid", user.ID)
+	c.Set("uhash", user.Hash)
+	c.Set("rid", user.RoleID)
+	c.Set("exp", key.ExpiresAt.Unix())
+	c.Set("gtm", now.Unix())
+	c.Set("tid", user.Type.String())
+	c.Set("uname", user.Name)
+	c.Set("kid", key.ID)
+
+	return authResultOk, nil
+}
+
 func ValidateToken(tokenString, globalSalt string) (*models.ProtoAuthTokenClaims, error) {
 	var claims models.ProtoAuthTokenClaims
 	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
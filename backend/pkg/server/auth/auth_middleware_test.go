@@ -22,7 +22,7 @@ import (
 )
 
 func TestAuthTokenProtoRequiredAuthWithCookie(t *testing.T) {
-	authMiddleware := NewAuthMiddleware("/base/url", "test")
+	authMiddleware := NewAuthMiddleware("/base/url", "test", nil)
 
 	t.Run("test URL", func(t *testing.T) {
 		server := newTestServer(t, "/test", authMiddleware.AuthTokenProtoRequired)
@@ -55,7 +55,7 @@ func TestAuthTokenProtoRequiredAuthWithCookie(t *testing.T) {
 }
 
 func TestAuthTokenProtoRequiredAuthWithToken(t *testing.T) {
-	authMiddleware := NewAuthMiddleware("/base/url", "test")
+	authMiddleware := NewAuthMiddleware("/base/url", "test", nil)
 
 	server := newTestServer(t, "/test", authMiddleware.AuthTokenProtoRequired)
 	defer server.Close()
@@ -89,7 +89,7 @@ func TestAuthTokenProtoRequiredAuthWithToken(t *testing.T) {
 }
 
 func TestAuthRequiredAuthWithCookie(t *testing.T) {
-	authMiddleware := NewAuthMiddleware("/base/url", "test")
+	authMiddleware := NewAuthMiddleware("/base/url", "test", nil)
 
 	server := newTestServer(t, "/test", authMiddleware.AuthRequired)
 	defer server.Close()
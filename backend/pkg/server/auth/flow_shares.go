@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FlowShareTokenPrefix marks the flow share token to tell it apart from other tokens in the links
+	FlowShareTokenPrefix = "pfs_"
+
+	flowShareRandomLength    = 16
+	flowShareSignatureLength = 16
+	flowShareTokenLength     = len(FlowShareTokenPrefix) + 2*(flowShareRandomLength+flowShareSignatureLength)
+	flowShareDisplayLength   = len(FlowShareTokenPrefix) + 8
+)
+
+// GenerateFlowShareToken returns the new signed flow share token, its prefix to display and the hash to store,
+// the signature lets to reject forged tokens before looking for them in the DB
+func GenerateFlowShareToken(globalSalt string) (token, prefix, hash string, err error) {
+	buf := make([]byte, flowShareRandomLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate flow share token: %w", err)
+	}
+
+	payload := hex.EncodeToString(buf)
+	token = FlowShareTokenPrefix + payload + hex.EncodeToString(signFlowSharePayload(payload, globalSalt))
+	return token, token[:flowShareDisplayLength], HashAPIKey(token), nil
+}
+
+// VerifyFlowShareToken checks the token format and signature and returns the hash to find the share in the DB
+func VerifyFlowShareToken(token, globalSalt string) (string, error) {
+	if len(token) != flowShareTokenLength || !IsFlowShareToken(token) {
+		return "", errors.New("flow share token is malformed")
+	}
+
+	payload := token[len(FlowShareTokenPrefix) : len(token)-2*flowShareSignatureLength]
+	signature, err := hex.DecodeString(token[len(token)-2*flowShareSignatureLength:])
+	if err != nil {
+		return "", errors.New("flow share token is malformed")
+	}
+
+	if !hmac.Equal(signature, signFlowSharePayload(payload, globalSalt)) {
+		return "", errors.New("flow share token signature is invalid")
+	}
+
+	return HashAPIKey(token), nil
+}
+
+// IsFlowShareToken returns true if the token looks like the flow share token
+func IsFlowShareToken(token string) bool {
+	return strings.HasPrefix(token, FlowShareTokenPrefix)
+}
+
+// signFlowSharePayload uses the key which depends on the global salt only,
+// so the share links survive the server upgrades unlike the session cookies
+func signFlowSharePayload(payload, globalSalt string) []byte {
+	key := sha256.Sum256([]byte("pentagi-flow-share|" + globalSalt))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)[:flowShareSignatureLength]
+}
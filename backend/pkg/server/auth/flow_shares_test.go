@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFlowShareToken(t *testing.T) {
+	token, prefix, hash, err := GenerateFlowShareToken("salt")
+	require.NoError(t, err)
+
+	assert.True(t, IsFlowShareToken(token))
+	assert.Len(t, token, flowShareTokenLength)
+	assert.Equal(t, token[:flowShareDisplayLength], prefix)
+	assert.Equal(t, HashAPIKey(token), hash)
+
+	verified, err := VerifyFlowShareToken(token, "salt")
+	require.NoError(t, err)
+	assert.Equal(t, hash, verified)
+
+	other, _, _, err := GenerateFlowShareToken("salt")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestVerifyFlowShareToken(t *testing.T) {
+	token, _, _, err := GenerateFlowShareToken("salt")
+	require.NoError(t, err)
+
+	_, err = VerifyFlowShareToken(token, "other-salt")
+	assert.Error(t, err)
+
+	// flip the last character of the random part to forge the token
+	payloadEnd := len(FlowShareTokenPrefix) + 2*flowShareRandomLength - 1
+	forged := []byte(token)
+	if forged[payloadEnd] == '0' {
+		forged[payloadEnd] = '1'
+	} else {
+		forged[payloadEnd] = '0'
+	}
+	_, err = VerifyFlowShareToken(string(forged), "salt")
+	assert.Error(t, err)
+
+	for _, invalid := range []string{"", token[:len(token)-1], "pak_" + token[4:], token[:len(token)-2] + "zz"} {
+		_, err = VerifyFlowShareToken(invalid, "salt")
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pentagi/pkg/server/models"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	loginThrottleAccountPrefix = "account:"
+	loginThrottleAddressPrefix = "ip:"
+)
+
+// LoginThrottleConfig limits the failed login attempts, the zero attempts number disables the limit
+type LoginThrottleConfig struct {
+	AccountMaxAttempts int
+	AddressMaxAttempts int
+	LockoutBase        time.Duration
+	LockoutMax         time.Duration
+	// Window is the time without failures after which the failures counter starts over
+	Window time.Duration
+}
+
+// LoginThrottle keeps the failed login attempts in the DB, so the limits are shared by all server instances
+type LoginThrottle struct {
+	cfg LoginThrottleConfig
+	db  *gorm.DB
+}
+
+type loginThrottleKey struct {
+	key         string
+	maxAttempts int
+}
+
+func NewLoginThrottle(cfg LoginThrottleConfig, db *gorm.DB) *LoginThrottle {
+	return &LoginThrottle{
+		cfg: cfg,
+		db:  db,
+	}
+}
+
+// Locked returns the time left until the account and the client address are unlocked
+func (t *LoginThrottle) Locked(login, addr string) (time.Duration, error) {
+	keys := t.keys(login, addr)
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, key.key)
+	}
+
+	now := time.Now()
+	var throttles []models.LoginThrottle
+	err := t.db.Where("key IN (?) AND locked_until > ?", names, now).Find(&throttles).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get login throttles: %w", err)
+	}
+
+	var left time.Duration
+	for _, throttle := range throttles {
+		left = max(left, throttle.LockedUntil.Sub(now))
+	}
+
+	return left, nil
+}
+
+// Fail counts the failed attempt for the account and the client address and returns the lockout
+// duration if any of them is locked by this attempt
+func (t *LoginThrottle) Fail(login, addr string) (time.Duration, error) {
+	var lockout time.Duration
+
+	now := time.Now()
+	for _, key := range t.keys(login, addr) {
+		var throttle models.LoginThrottle
+		err := t.db.Raw(`
+			INSERT INTO login_throttles (key, failures, last_failure_at) VALUES (?, 1, ?)
+			ON CONFLICT (key) DO UPDATE SET
+			  failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			  last_failure_at = EXCLUDED.last_failure_at
+			RETURNING *`, key.key, now, now.Add(-t.cfg.Window)).Scan(&throttle).Error
+		if err != nil {
+			return 0, fmt.Errorf("failed to count failed login attempt: %w", err)
+		}
+
+		duration := LockoutDuration(throttle.Failures, key.maxAttempts, t.cfg.LockoutBase, t.cfg.LockoutMax)
+		if duration <= 0 {
+			continue
+		}
+
+		err = t.db.Model(&throttle).UpdateColumn("locked_until", now.Add(duration)).Error
+		if err != nil {
+			return 0, fmt.Errorf("failed to lock login: %w", err)
+		}
+		lockout = max(lockout, duration)
+	}
+
+	return lockout, nil
+}
+
+// Reset forgets the failed attempts of the account after the successful login, the client address
+// counter isn't reset because the attacker may own some valid account
+func (t *LoginThrottle) Reset(login string) error {
+	err := t.db.Where("key = ?", accountThrottleKey(login)).Delete(&models.LoginThrottle{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to reset login throttle: %w", err)
+	}
+
+	return nil
+}
+
+func (t *LoginThrottle) keys(login, addr string) []loginThrottleKey {
+	var keys []loginThrottleKey
+	if t.cfg.AccountMaxAttempts > 0 && login != "" {
+		keys = append(keys, loginThrottleKey{accountThrottleKey(login), t.cfg.AccountMaxAttempts})
+	}
+	if t.cfg.AddressMaxAttempts > 0 && addr != "" {
+		keys = append(keys, loginThrottleKey{loginThrottleAddressPrefix + addr, t.cfg.AddressMaxAttempts})
+	}
+
+	return keys
+}
+
+func accountThrottleKey(login string) string {
+	return loginThrottleAccountPrefix + strings.ToLower(strings.TrimSpace(login))
+}
+
+// LockoutDuration returns the lockout after the failed attempt, it starts from the base duration when
+// the failures reach the allowed attempts number and it's doubled on each next failure up to the limit
+func LockoutDuration(failures, maxAttempts int, base, limit time.Duration) time.Duration {
+	if maxAttempts <= 0 || failures < maxAttempts || base <= 0 {
+		return 0
+	}
+
+	limit = max(limit, base)
+	duration := base
+	for i := maxAttempts; i < failures && duration < limit; i++ {
+		duration *= 2
+	}
+
+	return min(duration, limit)
+}
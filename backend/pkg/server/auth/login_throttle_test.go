@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	base, limit := 30*time.Second, 5*time.Minute
+
+	assert.Zero(t, LockoutDuration(4, 5, base, limit))
+	assert.Equal(t, 30*time.Second, LockoutDuration(5, 5, base, limit))
+	assert.Equal(t, time.Minute, LockoutDuration(6, 5, base, limit))
+	assert.Equal(t, 4*time.Minute, LockoutDuration(8, 5, base, limit))
+	assert.Equal(t, limit, LockoutDuration(9, 5, base, limit))
+	assert.Equal(t, limit, LockoutDuration(1000, 5, base, limit))
+
+	assert.Zero(t, LockoutDuration(10, 0, base, limit), "zero attempts disables the limit")
+	assert.Zero(t, LockoutDuration(10, 5, 0, limit))
+	assert.Equal(t, base, LockoutDuration(10, 5, base, 0), "limit below the base keeps the base")
+}
+
+func TestLoginThrottleKeys(t *testing.T) {
+	throttle := NewLoginThrottle(LoginThrottleConfig{AccountMaxAttempts: 5, AddressMaxAttempts: 20}, nil)
+
+	assert.Equal(t, []loginThrottleKey{
+		{"account:admin@pentagi.com", 5},
+		{"ip:10.0.0.1", 20},
+	}, throttle.keys(" Admin@PentAGI.com", "10.0.0.1"))
+	assert.Equal(t, []loginThrottleKey{{"account:admin@pentagi.com", 5}}, throttle.keys("admin@pentagi.com", ""))
+
+	throttle = NewLoginThrottle(LoginThrottleConfig{}, nil)
+	assert.Empty(t, throttle.keys("admin@pentagi.com", "10.0.0.1"))
+
+	left, err := throttle.Locked("admin@pentagi.com", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Zero(t, left)
+}
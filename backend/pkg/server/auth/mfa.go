@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// MFAIssuer is the issuer name which is shown in the authenticator app
+	MFAIssuer = "PentAGI"
+	// PrivilegeMFARequired marks the roles whose local users must use the second factor to login
+	PrivilegeMFARequired = "mfa.required"
+
+	mfaPeriod = 30
+	// codes of the previous and the next period are accepted to tolerate the clock drift
+	mfaSkew             = 1
+	mfaQRCodeSize       = 256
+	mfaRecoveryCodes    = 10
+	mfaRecoveryCodeSize = 10
+)
+
+var mfaValidateOpts = totp.ValidateOpts{
+	Period:    mfaPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// GenerateMFAKey returns the new TOTP secret with the provisioning URL and the QR code of this URL as PNG data URI
+func GenerateMFAKey(account string) (secret, url, qrCode string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      MFAIssuer,
+		AccountName: account,
+		Period:      mfaPeriod,
+		Digits:      mfaValidateOpts.Digits,
+		Algorithm:   mfaValidateOpts.Algorithm,
+	})
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to generate mfa key: %w", err)
+	}
+
+	img, err := key.Image(mfaQRCodeSize, mfaQRCodeSize)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to render mfa qr code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", "", "", fmt.Errorf("failed to encode mfa qr code: %w", err)
+	}
+
+	qrCode = "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	return key.Secret(), key.URL(), qrCode, nil
+}
+
+// ValidateMFACode checks the TOTP code against the periods around now and returns the matched period,
+// the periods up to the last used one are rejected, so the same code can't be replayed
+func ValidateMFACode(secret, code string, now time.Time, lastUsedStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	step := now.Unix() / mfaPeriod
+	for s := step - mfaSkew; s <= step+mfaSkew; s++ {
+		if s <= lastUsedStep {
+			continue
+		}
+
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(s*mfaPeriod, 0), mfaValidateOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns the new one-time recovery codes to show and their hashes to store
+func GenerateRecoveryCodes() (codes, hashes []string, err error) {
+	buf := make([]byte, mfaRecoveryCodes*mfaRecoveryCodeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < mfaRecoveryCodes; i++ {
+		chunk := buf[i*mfaRecoveryCodeSize : (i+1)*mfaRecoveryCodeSize]
+		code := strings.ToLower(encoding.EncodeToString(chunk))[:mfaRecoveryCodeSize]
+		code = code[:mfaRecoveryCodeSize/2] + "-" + code[mfaRecoveryCodeSize/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode returns the hash of the recovery code which is stored in the DB, the code is
+// normalized, so it may be entered in any case and without the dash
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	if len(code) == mfaRecoveryCodeSize {
+		code = code[:mfaRecoveryCodeSize/2] + "-" + code[mfaRecoveryCodeSize/2:]
+	}
+
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsMFACode returns true if the code looks like the TOTP code rather than the recovery code
+func IsMFACode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != mfaValidateOpts.Digits.Length() {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMFAKey(t *testing.T) {
+	secret, url, qrCode, err := GenerateMFAKey("admin@pentagi.com")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, secret)
+	assert.True(t, strings.HasPrefix(url, "otpauth://totp/PentAGI:admin@pentagi.com?"))
+	assert.Contains(t, url, "secret="+secret)
+	assert.True(t, strings.HasPrefix(qrCode, "data:image/png;base64,"))
+}
+
+func TestValidateMFACode(t *testing.T) {
+	secret, _, _, err := GenerateMFAKey("admin@pentagi.com")
+	require.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := totp.GenerateCodeCustom(secret, now, mfaValidateOpts)
+	require.NoError(t, err)
+
+	step, ok := ValidateMFACode(secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/mfaPeriod, step)
+
+	_, ok = ValidateMFACode(secret, code, now, step)
+	assert.False(t, ok, "used code must be rejected")
+
+	_, ok = ValidateMFACode(secret, code, now.Add(mfaPeriod*time.Second), 0)
+	assert.True(t, ok, "code of the previous period must be accepted")
+
+	_, ok = ValidateMFACode(secret, code, now.Add(3*mfaPeriod*time.Second), 0)
+	assert.False(t, ok, "expired code must be rejected")
+
+	_, ok = ValidateMFACode(secret, "abcdef", now, 0)
+	assert.False(t, ok)
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, mfaRecoveryCodes)
+	require.Len(t, hashes, mfaRecoveryCodes)
+
+	seen := make(map[string]struct{})
+	for i, code := range codes {
+		assert.Len(t, code, mfaRecoveryCodeSize+1)
+		assert.False(t, IsMFACode(code))
+		assert.Equal(t, hashes[i], HashRecoveryCode(code))
+		assert.Equal(t, hashes[i], HashRecoveryCode(strings.ToUpper(code)))
+		assert.Equal(t, hashes[i], HashRecoveryCode(strings.ReplaceAll(code, "-", "")))
+
+		seen[code] = struct{}{}
+	}
+	assert.Len(t, seen, mfaRecoveryCodes)
+}
+
+func TestIsMFACode(t *testing.T) {
+	assert.True(t, IsMFACode("123456"))
+	assert.True(t, IsMFACode(" 123456 "))
+	assert.False(t, IsMFACode("12345"))
+	assert.False(t, IsMFACode("12345a"))
+	assert.False(t, IsMFACode("abcde-fghij"))
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestPrivilegesRequired(t *testing.T) {
-	authMiddleware := NewAuthMiddleware("/base/url", "test")
+	authMiddleware := NewAuthMiddleware("/base/url", "test", nil)
 	server := newTestServer(t, "/test", authMiddleware.AuthRequired, PrivilegesRequired("priv1", "priv2"))
 	defer server.Close()
 
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	sessionIDLength = 32
+	// last seen timestamp is updated not more often than this interval to avoid writes on each request
+	sessionLastSeenInterval = time.Minute
+)
+
+// GenerateSessionID returns the new random session id to keep in the cookie and the hash to store
+func GenerateSessionID() (sid, hash string, err error) {
+	buf := make([]byte, sessionIDLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	sid = hex.EncodeToString(buf)
+	return sid, HashSessionID(sid), nil
+}
+
+// HashSessionID returns the hash of the session id which is stored in the DB
+func HashSessionID(sid string) string {
+	hash := sha256.Sum256([]byte(sid))
+	return hex.EncodeToString(hash[:])
+}
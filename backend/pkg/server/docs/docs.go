@@ -134,15 +134,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/assistantlogs/": {
+        "/api_keys/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assistantlogs"
+                    "APIKeys"
                 ],
-                "summary": "Retrieve assistantlogs list",
+                "summary": "Retrieve personal API keys list",
                 "parameters": [
                     {
                         "type": "array",
@@ -205,7 +205,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "assistantlogs list received successful",
+                        "description": "api keys list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -215,7 +215,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.assistantlogs"
+                                            "$ref": "#/definitions/services.apiKeys"
                                         }
                                     }
                                 }
@@ -229,71 +229,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting assistantlogs not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "internal error on getting assistantlogs",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/authorize": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Public"
-                ],
-                "summary": "Login user into OAuth2 external system via HTTP redirect",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "default": "/",
-                        "description": "URI to redirect user there after login",
-                        "name": "return_uri",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "google",
-                        "description": "OAuth provider name (google, github, etc.)",
-                        "name": "provider",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "307": {
-                        "description": "redirect to SSO login page"
-                    },
-                    "400": {
-                        "description": "invalid autorizarion query",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "authorize not permitted",
+                        "description": "getting api keys not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on autorizarion",
+                        "description": "internal error on getting api keys",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/login": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -302,47 +250,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Public"
+                    "APIKeys"
                 ],
-                "summary": "Login user into system",
+                "summary": "Create new personal API key, the key is returned only in this response",
                 "parameters": [
                     {
-                        "description": "Login form JSON data",
+                        "description": "api key model to create",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Login"
+                            "$ref": "#/definitions/models.CreateAPIKey"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "login successful",
+                    "201": {
+                        "description": "api key created successful",
                         "schema": {
-                            "$ref": "#/definitions/SuccessResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.APIKeyToken"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "invalid login data",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "invalid login or password",
+                        "description": "invalid api key request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "login not permitted",
+                        "description": "creating api key not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on login",
+                        "description": "internal error on creating api key",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -350,102 +304,127 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login-callback": {
+        "/api_keys/{keyID}": {
             "get": {
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public"
+                    "APIKeys"
                 ],
-                "summary": "Login user from external OAuth application",
+                "summary": "Retrieve personal API key by id",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Auth code from OAuth provider to exchange token",
-                        "name": "code",
-                        "in": "query"
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "api key id",
+                        "name": "keyID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "303": {
-                        "description": "redirect to registered return_uri path in the state"
+                    "200": {
+                        "description": "api key received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.APIKey"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
-                        "description": "invalid login data",
+                        "description": "invalid api key request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "invalid login or password",
+                    "403": {
+                        "description": "getting api key not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "login not permitted",
+                    "404": {
+                        "description": "api key not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on login",
+                        "description": "internal error on getting api key",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public"
+                    "APIKeys"
                 ],
-                "summary": "Login user from external OAuth application",
+                "summary": "Revoke personal API key by id, the revoked key is kept in the list",
                 "parameters": [
                     {
-                        "description": "Auth form JSON data",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AuthCallback"
-                        }
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "api key id",
+                        "name": "keyID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "303": {
-                        "description": "redirect to registered return_uri path in the state"
+                    "200": {
+                        "description": "api key revoked successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.APIKey"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
-                        "description": "invalid login data",
+                        "description": "invalid api key request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "invalid login or password",
+                    "403": {
+                        "description": "revoking api key not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "login not permitted",
+                    "404": {
+                        "description": "api key not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on login",
+                        "description": "internal error on revoking api key",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -453,59 +432,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Public"
-                ],
-                "summary": "Logout current user via HTTP redirect",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "default": "/",
-                        "description": "URI to redirect user there after logout",
-                        "name": "return_uri",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "307": {
-                        "description": "redirect to input return_uri path"
-                    }
-                }
-            }
-        },
-        "/auth/logout-callback": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Public"
-                ],
-                "summary": "Logout current user from external OAuth application",
-                "responses": {
-                    "303": {
-                        "description": "logout successful",
-                        "schema": {
-                            "$ref": "#/definitions/SuccessResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/containers/": {
+        "/assistantlogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Containers"
+                    "Assistantlogs"
                 ],
-                "summary": "Retrieve containers list",
+                "summary": "Retrieve assistantlogs list",
                 "parameters": [
                     {
                         "type": "array",
@@ -568,7 +503,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "containers list received successful",
+                        "description": "assistantlogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -578,7 +513,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.containers"
+                                            "$ref": "#/definitions/services.assistantlogs"
                                         }
                                     }
                                 }
@@ -592,13 +527,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting containers not permitted",
+                        "description": "getting assistantlogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting containers",
+                        "description": "internal error on getting assistantlogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -606,15 +541,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/": {
+        "/audit_events/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flows"
+                    "AuditEvents"
                 ],
-                "summary": "Retrieve flows list",
+                "summary": "Retrieve audit events list",
                 "parameters": [
                     {
                         "type": "array",
@@ -677,7 +612,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "flows list received successful",
+                        "description": "audit events list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -687,7 +622,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.flows"
+                                            "$ref": "#/definitions/services.auditEvents"
                                         }
                                     }
                                 }
@@ -701,44 +636,2478 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flows not permitted",
+                        "description": "getting audit events not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flows",
+                        "description": "internal error on getting audit events",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/auth/authorize": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flows"
+                    "Public"
                 ],
-                "summary": "Create new flow with custom functions",
+                "summary": "Login user into OAuth2 external system via HTTP redirect",
                 "parameters": [
                     {
-                        "description": "flow model to create",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateFlow"
-                        }
+                        "type": "string",
+                        "default": "/",
+                        "description": "URI to redirect user there after login",
+                        "name": "return_uri",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "google",
+                        "description": "OAuth provider name (google, github or OIDC_PROVIDER_NAME)",
+                        "name": "provider",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "307": {
+                        "description": "redirect to SSO login page"
+                    },
+                    "400": {
+                        "description": "invalid autorizarion query",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "authorize not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on autorizarion",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Login user into system",
+                "parameters": [
+                    {
+                        "description": "Login form JSON data",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Login"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "login successful or second factor required",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LoginStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid login data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid login or password",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "login not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many failed login attempts",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on login",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login-callback": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Login user from external OAuth application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Auth code from OAuth provider to exchange token",
+                        "name": "code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "303": {
+                        "description": "redirect to registered return_uri path in the state"
+                    },
+                    "400": {
+                        "description": "invalid login data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid login or password",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "login not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on login",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Login user from external OAuth application",
+                "parameters": [
+                    {
+                        "description": "Auth form JSON data",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthCallback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "303": {
+                        "description": "redirect to registered return_uri path in the state"
+                    },
+                    "400": {
+                        "description": "invalid login data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid login or password",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "login not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on login",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/mfa": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Verify second factor code to finish login",
+                "parameters": [
+                    {
+                        "description": "TOTP code or recovery code",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MFACode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LoginStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid code data or no pending login",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "login not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many failed login attempts",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on login",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/mfa/enroll": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Enroll second factor required by the role to finish login",
+                "responses": {
+                    "200": {
+                        "description": "second factor enrollment created successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MFAEnrollment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "no pending login or second factor is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "login not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on second factor enrollment",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Logout current user via HTTP redirect",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "/",
+                        "description": "URI to redirect user there after logout",
+                        "name": "return_uri",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "307": {
+                        "description": "redirect to input return_uri path"
+                    }
+                }
+            }
+        },
+        "/auth/logout-callback": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Logout current user from external OAuth application",
+                "responses": {
+                    "303": {
+                        "description": "logout successful",
+                        "schema": {
+                            "$ref": "#/definitions/SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/containers/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Containers"
+                ],
+                "summary": "Retrieve containers list",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "containers list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.containers"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting containers not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting containers",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Retrieve flows list",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flows list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.flows"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting flows not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flows",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Create new flow with custom functions",
+                "parameters": [
+                    {
+                        "description": "flow model to create",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateFlow"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "flow created successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Flow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid flow request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "creating flow not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on creating flow",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Retrieve flow by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Flow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "getting flow not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "flow not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flow",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Patch flow",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "flow model to patch",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchFlow"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow patched successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Flow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid flow request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "patching flow not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on patching flow",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Delete flow by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow deleted successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Flow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "deleting flow not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "flow not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on deleting flow",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/agentlogs/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agentlogs"
+                ],
+                "summary": "Retrieve agentlogs list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "agentlogs list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.agentlogs"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting agentlogs not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting agentlogs",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/assistantlogs/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assistantlogs"
+                ],
+                "summary": "Retrieve assistantlogs list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "assistantlogs list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.assistantlogs"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting assistantlogs not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting assistantlogs",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/assistants/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assistants"
+                ],
+                "summary": "Retrieve assistants list",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "assistants list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.assistants"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting assistants not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting assistants",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assistants"
+                ],
+                "summary": "Create new assistant with custom functions",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "assistant model to create",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateAssistant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "assistant created successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AssistantFlow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid assistant request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "creating assistant not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on creating assistant",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/assistants/{assistantID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assistants"
+                ],
+                "summary": "Retrieve flow assistant by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "assistant id",
+                        "name": "assistantID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow assistant received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Assistant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "getting flow assistant not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "flow assistant not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flow assistant",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assistants"
+                ],
+                "summary": "Patch assistant",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "assistant id",
+                        "name": "assistantID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "assistant model to patch",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchAssistant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "assistant patched successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AssistantFlow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid assistant request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "patching assistant not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on patching assistant",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Assistants"
+                ],
+                "summary": "Delete assistant by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "assistant id",
+                        "name": "assistantID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "assistant deleted successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AssistantFlow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "deleting assistant not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "assistant not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on deleting assistant",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/containers/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Containers"
+                ],
+                "summary": "Retrieve containers list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "containers list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.containers"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting containers not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting containers",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/containers/{containerID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Containers"
+                ],
+                "summary": "Retrieve container info by id and flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "container id",
+                        "name": "containerID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "container info received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Container"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "getting container not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "container not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting container",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/graph": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flows"
+                ],
+                "summary": "Retrieve flow graph by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow graph received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FlowTasksSubtasks"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "getting flow graph not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "flow graph not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flow graph",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/msglogs/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Msglogs"
+                ],
+                "summary": "Retrieve msglogs list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "msglogs list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.msglogs"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting msglogs not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting msglogs",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/screenshots/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Screenshots"
+                ],
+                "summary": "Retrieve screenshots list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "screenshots list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.screenshots"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting screenshots not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting screenshots",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/screenshots/{screenshotID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Screenshots"
+                ],
+                "summary": "Retrieve screenshot info by id and flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "screenshot id",
+                        "name": "screenshotID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "screenshot info received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Screenshot"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "getting screenshot not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "screenshot not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting screenshot",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/screenshots/{screenshotID}/file": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "application/json"
+                ],
+                "tags": [
+                    "Screenshots"
+                ],
+                "summary": "Retrieve screenshot file by id and flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "screenshot id",
+                        "name": "screenshotID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "screenshot file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "getting screenshot not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting screenshot",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/searchlogs/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Searchlogs"
+                ],
+                "summary": "Retrieve searchlogs list by flow id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "searchlogs list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.searchlogs"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting searchlogs not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting searchlogs",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/subtasks/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subtasks"
+                ],
+                "summary": "Retrieve flow subtasks list",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow subtasks list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.subtasks"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting flow subtasks not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flow subtasks",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/tasks/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Retrieve flow tasks list",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "flow tasks list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/services.tasks"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "getting flow tasks not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting flow tasks",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flows/{flowID}/tasks/{taskID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Retrieve flow task by id",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "task id",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "flow created successful",
+                    "200": {
+                        "description": "flow task received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -748,27 +3117,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Flow"
+                                            "$ref": "#/definitions/models.Task"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "invalid flow request data",
+                    "403": {
+                        "description": "getting flow task not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "creating flow not permitted",
+                    "404": {
+                        "description": "flow task not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on creating flow",
+                        "description": "internal error on getting flow task",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -776,15 +3145,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}": {
+        "/flows/{flowID}/tasks/{taskID}/graph": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flows"
+                    "Tasks"
                 ],
-                "summary": "Retrieve flow by id",
+                "summary": "Retrieve flow task graph by id",
                 "parameters": [
                     {
                         "minimum": 0,
@@ -793,11 +3162,19 @@ const docTemplate = `{
                         "name": "flowID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "task id",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow received successful",
+                        "description": "flow task graph received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -807,7 +3184,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Flow"
+                                            "$ref": "#/definitions/models.FlowTasksSubtasks"
                                         }
                                     }
                                 }
@@ -815,36 +3192,35 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flow not permitted",
+                        "description": "getting flow task graph not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "flow not found",
+                        "description": "flow task graph not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow",
+                        "description": "internal error on getting flow task graph",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/flows/{flowID}/tasks/{taskID}/subtasks/": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flows"
+                    "Subtasks"
                 ],
-                "summary": "Patch flow",
+                "summary": "Retrieve flow task subtasks list",
                 "parameters": [
                     {
                         "minimum": 0,
@@ -855,18 +3231,75 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "flow model to patch",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchFlow"
-                        }
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "task id",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow patched successful",
+                        "description": "flow task subtasks list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -876,7 +3309,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Flow"
+                                            "$ref": "#/definitions/services.subtasks"
                                         }
                                     }
                                 }
@@ -884,30 +3317,35 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid flow request data",
+                        "description": "invalid query request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "patching flow not permitted",
+                        "description": "getting flow task subtasks not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on patching flow",
+                        "description": "internal error on getting flow subtasks",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/flows/{flowID}/tasks/{taskID}/subtasks/{subtaskID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Flows"
+                    "Subtasks"
                 ],
-                "summary": "Delete flow by id",
+                "summary": "Retrieve flow task subtask by id",
                 "parameters": [
                     {
                         "minimum": 0,
@@ -916,11 +3354,27 @@ const docTemplate = `{
                         "name": "flowID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "task id",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "subtask id",
+                        "name": "subtaskID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow deleted successful",
+                        "description": "flow task subtask received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -930,7 +3384,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Flow"
+                                            "$ref": "#/definitions/models.Subtask"
                                         }
                                     }
                                 }
@@ -938,19 +3392,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "deleting flow not permitted",
+                        "description": "getting flow task subtask not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "flow not found",
+                        "description": "flow task subtask not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on deleting flow",
+                        "description": "internal error on getting flow task subtask",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -958,86 +3412,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/agentlogs/": {
-            "get": {
+        "/flows/{flowID}/team": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agentlogs"
+                    "Flows"
                 ],
-                "summary": "Retrieve agentlogs list by flow id",
+                "summary": "Share flow with the team, the flow owner must be the team operator or admin",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "maximum": 1000,
-                        "minimum": -1,
-                        "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "flow id",
+                        "name": "flowID",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                        "description": "team to share the flow with, null returns the flow to the owner",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareFlow"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "agentlogs list received successful",
+                        "description": "flow shared successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1047,7 +3455,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.agentlogs"
+                                            "$ref": "#/definitions/models.Flow"
                                         }
                                     }
                                 }
@@ -1055,19 +3463,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "invalid flow request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting agentlogs not permitted",
+                        "description": "sharing flow not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "flow or team not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting agentlogs",
+                        "description": "internal error on sharing flow",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1075,15 +3489,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/assistantlogs/": {
+        "/flows/{flowID}/termlogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assistantlogs"
+                    "Termlogs"
                 ],
-                "summary": "Retrieve assistantlogs list by flow id",
+                "summary": "Retrieve termlogs list by flow id",
                 "parameters": [
                     {
                         "minimum": 0,
@@ -1154,7 +3568,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "assistantlogs list received successful",
+                        "description": "termlogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1164,7 +3578,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.assistantlogs"
+                                            "$ref": "#/definitions/services.termlogs"
                                         }
                                     }
                                 }
@@ -1178,13 +3592,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting assistantlogs not permitted",
+                        "description": "getting termlogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting assistantlogs",
+                        "description": "internal error on getting termlogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1192,15 +3606,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/assistants/": {
+        "/flows/{flowID}/vecstorelogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assistants"
+                    "Vecstorelogs"
                 ],
-                "summary": "Retrieve assistants list",
+                "summary": "Retrieve vecstorelogs list by flow id",
                 "parameters": [
                     {
                         "minimum": 0,
@@ -1271,7 +3685,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "assistants list received successful",
+                        "description": "vecstorelogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1281,7 +3695,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.assistants"
+                                            "$ref": "#/definitions/services.vecstorelogs"
                                         }
                                     }
                                 }
@@ -1295,19 +3709,21 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting assistants not permitted",
+                        "description": "getting vecstorelogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting assistants",
+                        "description": "internal error on getting vecstorelogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/graphql": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1316,98 +3732,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Assistants"
+                    "GraphQL"
                 ],
-                "summary": "Create new assistant with custom functions",
+                "summary": "Perform graphql requests",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "assistant model to create",
+                        "description": "graphql request",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateAssistant"
+                            "$ref": "#/definitions/graphql.RawParams"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "assistant created successful",
+                    "200": {
+                        "description": "graphql response",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.AssistantFlow"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     },
                     "400": {
-                        "description": "invalid assistant request data",
+                        "description": "invalid graphql request data",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     },
                     "403": {
-                        "description": "creating assistant not permitted",
+                        "description": "unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     },
                     "500": {
-                        "description": "internal error on creating assistant",
+                        "description": "internal error on graphql request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     }
                 }
             }
         },
-        "/flows/{flowID}/assistants/{assistantID}": {
+        "/info": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assistants"
+                    "Public"
                 ],
-                "summary": "Retrieve flow assistant by id",
+                "summary": "Retrieve current user and system settings",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "assistant id",
-                        "name": "assistantID",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "boolean arg to refresh current cookie, use explicit false",
+                        "name": "refresh_cookie",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow assistant received successful",
+                        "description": "info received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1417,7 +3803,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Assistant"
+                                            "$ref": "#/definitions/services.info"
                                         }
                                     }
                                 }
@@ -1425,128 +3811,98 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flow assistant not permitted",
+                        "description": "getting info not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "flow assistant not found",
+                        "description": "user not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow assistant",
+                        "description": "internal error on getting information about system and config",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/msglogs/": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Assistants"
+                    "Msglogs"
                 ],
-                "summary": "Patch assistant",
+                "summary": "Retrieve msglogs list",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
                     },
                     {
-                        "minimum": 0,
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "assistant id",
-                        "name": "assistantID",
-                        "in": "path",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "assistant model to patch",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchAssistant"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "assistant patched successful",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.AssistantFlow"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "invalid assistant request data",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "patching assistant not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
                     },
-                    "500": {
-                        "description": "internal error on patching assistant",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "tags": [
-                    "Assistants"
-                ],
-                "summary": "Delete assistant by id",
-                "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "assistant id",
-                        "name": "assistantID",
-                        "in": "path",
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "assistant deleted successful",
+                        "description": "msglogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1556,27 +3912,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.AssistantFlow"
+                                            "$ref": "#/definitions/services.msglogs"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "403": {
-                        "description": "deleting assistant not permitted",
+                    "400": {
+                        "description": "invalid query request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "assistant not found",
+                    "403": {
+                        "description": "getting msglogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on deleting assistant",
+                        "description": "internal error on getting msglogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1584,24 +3940,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/containers/": {
+        "/prompts/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Containers"
+                    "Prompts"
                 ],
-                "summary": "Retrieve containers list by flow id",
+                "summary": "Retrieve prompts list",
                 "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "array",
                         "items": {
@@ -1663,7 +4011,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "containers list received successful",
+                        "description": "prompts list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1673,7 +4021,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.containers"
+                                            "$ref": "#/definitions/services.prompts"
                                         }
                                     }
                                 }
@@ -1687,13 +4035,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting containers not permitted",
+                        "description": "getting prompts not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting containers",
+                        "description": "internal error on getting prompts",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1701,36 +4049,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/containers/{containerID}": {
+        "/prompts/{promptType}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Containers"
+                    "Prompts"
                 ],
-                "summary": "Retrieve container info by id and flow id",
+                "summary": "Retrieve prompt by type",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "container id",
-                        "name": "containerID",
+                        "type": "string",
+                        "description": "prompt type",
+                        "name": "promptType",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "container info received successful",
+                        "description": "prompt received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1740,56 +4079,71 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Container"
+                                            "$ref": "#/definitions/models.Prompt"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "invalid prompt request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "403": {
-                        "description": "getting container not permitted",
+                        "description": "getting prompt not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "container not found",
+                        "description": "prompt not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting container",
+                        "description": "internal error on getting prompt",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/flows/{flowID}/graph": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flows"
+                    "Prompts"
                 ],
-                "summary": "Retrieve flow graph by id",
+                "summary": "Update prompt",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "type": "string",
+                        "description": "prompt type",
+                        "name": "promptType",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "prompt model to update",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchPrompt"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow graph received successful",
+                        "description": "prompt updated successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1799,27 +4153,33 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.FlowTasksSubtasks"
+                                            "$ref": "#/definitions/models.Prompt"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "invalid prompt request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "403": {
-                        "description": "getting flow graph not permitted",
+                        "description": "updating prompt not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "flow graph not found",
+                        "description": "prompt not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow graph",
+                        "description": "internal error on updating prompt",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1827,86 +4187,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/msglogs/": {
-            "get": {
+        "/prompts/{promptType}/default": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Msglogs"
+                    "Prompts"
                 ],
-                "summary": "Retrieve msglogs list by flow id",
+                "summary": "Reset prompt by type to default value",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "type": "string",
+                        "description": "prompt type",
+                        "name": "promptType",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "prompt reset successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Prompt"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                    "400": {
+                        "description": "invalid prompt request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "maximum": 1000,
-                        "minimum": -1,
-                        "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
+                    "403": {
+                        "description": "updating prompt not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
+                    "404": {
+                        "description": "prompt not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                    "500": {
+                        "description": "internal error on resetting prompt",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
+                }
+            }
+        },
+        "/providers/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Providers"
                 ],
+                "summary": "Retrieve providers list",
                 "responses": {
                     "200": {
-                        "description": "msglogs list received successful",
+                        "description": "providers list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -1916,27 +4275,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.msglogs"
+                                            "$ref": "#/definitions/models.ProviderInfo"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "invalid query request data",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
                     "403": {
-                        "description": "getting msglogs not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "internal error on getting msglogs",
+                        "description": "getting providers not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -1944,24 +4291,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/screenshots/": {
+        "/roles/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Screenshots"
+                    "Roles"
                 ],
-                "summary": "Retrieve screenshots list by flow id",
+                "summary": "Retrieve roles list",
                 "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "array",
                         "items": {
@@ -2023,7 +4362,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "screenshots list received successful",
+                        "description": "roles list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2033,7 +4372,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.screenshots"
+                                            "$ref": "#/definitions/services.roles"
                                         }
                                     }
                                 }
@@ -2047,50 +4386,44 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting screenshots not permitted",
+                        "description": "getting roles not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting screenshots",
+                        "description": "internal error on getting roles",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/flows/{flowID}/screenshots/{screenshotID}": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Screenshots"
+                    "Roles"
                 ],
-                "summary": "Retrieve screenshot info by id and flow id",
+                "summary": "Create new role, the privileges must be granted to the current user",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "screenshot id",
-                        "name": "screenshotID",
-                        "in": "path",
-                        "required": true
+                        "description": "role model to create",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRole"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "screenshot info received successful",
+                    "201": {
+                        "description": "role created successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2100,27 +4433,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Screenshot"
+                                            "$ref": "#/definitions/models.RolePrivileges"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "403": {
-                        "description": "getting screenshot not permitted",
+                    "400": {
+                        "description": "invalid role request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "screenshot not found",
+                    "403": {
+                        "description": "creating role not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting screenshot",
+                        "description": "internal error on creating role",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -2128,136 +4461,211 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/screenshots/{screenshotID}/file": {
+        "/roles/privileges": {
             "get": {
                 "produces": [
-                    "image/png",
                     "application/json"
                 ],
                 "tags": [
-                    "Screenshots"
+                    "Roles"
                 ],
-                "summary": "Retrieve screenshot file by id and flow id",
-                "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
+                "summary": "Retrieve known privileges list",
+                "responses": {
+                    "200": {
+                        "description": "privileges list received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
+                    "403": {
+                        "description": "getting privileges not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleID}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Retrieve role by id",
+                "parameters": [
                     {
-                        "minimum": 0,
                         "type": "integer",
-                        "description": "screenshot id",
-                        "name": "screenshotID",
+                        "description": "role id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "screenshot file",
+                        "description": "role received successful",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePrivileges"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting screenshot not permitted",
+                        "description": "getting role not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting screenshot",
+                        "description": "internal error on getting role",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/flows/{flowID}/searchlogs/": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Searchlogs"
+                    "Roles"
                 ],
-                "summary": "Retrieve searchlogs list by flow id",
+                "summary": "Update role, the privileges replace the current ones and must be granted to the current user",
                 "parameters": [
                     {
-                        "minimum": 0,
                         "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "description": "role id",
+                        "name": "roleID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
+                        "description": "role model to update",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "role updated successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RolePrivileges"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
+                    "400": {
+                        "description": "invalid role request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                    "403": {
+                        "description": "updating role not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "role not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "internal error on updating role",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{roleID}/clone": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Create new role with the privileges of the role by id",
+                "parameters": [
                     {
-                        "maximum": 1000,
-                        "minimum": -1,
                         "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
+                        "description": "source role id",
+                        "name": "roleID",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                        "description": "role name to create",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CloneRole"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "searchlogs list received successful",
+                    "201": {
+                        "description": "role cloned successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2267,7 +4675,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.searchlogs"
+                                            "$ref": "#/definitions/models.RolePrivileges"
                                         }
                                     }
                                 }
@@ -2275,19 +4683,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "invalid role request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting searchlogs not permitted",
+                        "description": "cloning role not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "source role not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting searchlogs",
+                        "description": "internal error on cloning role",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -2295,24 +4709,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/subtasks/": {
+        "/screenshots/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Subtasks"
+                    "Screenshots"
                 ],
-                "summary": "Retrieve flow subtasks list",
+                "summary": "Retrieve screenshots list",
                 "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "array",
                         "items": {
@@ -2374,7 +4780,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow subtasks list received successful",
+                        "description": "screenshots list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2384,7 +4790,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.subtasks"
+                                            "$ref": "#/definitions/services.screenshots"
                                         }
                                     }
                                 }
@@ -2398,13 +4804,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flow subtasks not permitted",
+                        "description": "getting screenshots not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow subtasks",
+                        "description": "internal error on getting screenshots",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -2412,24 +4818,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/tasks/": {
+        "/searchlogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tasks"
+                    "Searchlogs"
                 ],
-                "summary": "Retrieve flow tasks list",
+                "summary": "Retrieve searchlogs list",
                 "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "array",
                         "items": {
@@ -2491,7 +4889,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow tasks list received successful",
+                        "description": "searchlogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2501,7 +4899,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.tasks"
+                                            "$ref": "#/definitions/services.searchlogs"
                                         }
                                     }
                                 }
@@ -2515,180 +4913,30 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flow tasks not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "internal error on getting flow tasks",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/flows/{flowID}/tasks/{taskID}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tasks"
-                ],
-                "summary": "Retrieve flow task by id",
-                "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "task id",
-                        "name": "taskID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "flow task received successful",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.Task"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "403": {
-                        "description": "getting flow task not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "flow task not found",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "internal error on getting flow task",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/flows/{flowID}/tasks/{taskID}/graph": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Tasks"
-                ],
-                "summary": "Retrieve flow task graph by id",
-                "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "task id",
-                        "name": "taskID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "flow task graph received successful",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/models.FlowTasksSubtasks"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "403": {
-                        "description": "getting flow task graph not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "flow task graph not found",
+                        "description": "getting searchlogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow task graph",
+                        "description": "internal error on getting searchlogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/flows/{flowID}/tasks/{taskID}/subtasks/": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Subtasks"
-                ],
-                "summary": "Retrieve flow task subtasks list",
-                "parameters": [
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "task id",
-                        "name": "taskID",
-                        "in": "path",
-                        "required": true
-                    },
+            }
+        },
+        "/sessions/": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Retrieve login sessions list, the session of the current request is marked as current",
+                "parameters": [
                     {
                         "type": "array",
                         "items": {
@@ -2750,7 +4998,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow task subtasks list received successful",
+                        "description": "sessions list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2760,7 +5008,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.subtasks"
+                                            "$ref": "#/definitions/services.userSessions"
                                         }
                                     }
                                 }
@@ -2774,13 +5022,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting flow task subtasks not permitted",
+                        "description": "getting sessions not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow subtasks",
+                        "description": "internal error on getting sessions",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -2788,44 +5036,92 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/tasks/{taskID}/subtasks/{subtaskID}": {
-            "get": {
+        "/sessions/{sessionID}": {
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Subtasks"
+                    "Sessions"
                 ],
-                "summary": "Retrieve flow task subtask by id",
+                "summary": "Revoke login session by id, the revoked session is kept in the list",
                 "parameters": [
                     {
                         "minimum": 0,
                         "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "description": "session id",
+                        "name": "sessionID",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "session revoked successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "task id",
-                        "name": "taskID",
-                        "in": "path",
-                        "required": true
+                    "400": {
+                        "description": "invalid session request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "revoking session not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "session not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "internal error on revoking session",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shared/{token}/flow": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "FlowShares"
+                ],
+                "summary": "Retrieve the flow by the share link token",
+                "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "subtask id",
-                        "name": "subtaskID",
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "flow task subtask received successful",
+                        "description": "shared flow received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2835,49 +5131,101 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Subtask"
+                                            "$ref": "#/definitions/models.SharedFlow"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "403": {
-                        "description": "getting flow task subtask not permitted",
+                    "404": {
+                        "description": "share link not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on getting shared flow",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/shared/{token}/graphql": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL",
+                    "FlowShares"
+                ],
+                "summary": "Perform graphql requests by the flow share link token, only the shared queries are allowed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "graphql request",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphql.RawParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "graphql response",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid graphql request data",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
                     },
                     "404": {
-                        "description": "flow task subtask not found",
+                        "description": "share link not found or expired",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting flow task subtask",
+                        "description": "internal error on graphql request",
                         "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/graphql.Response"
                         }
                     }
                 }
             }
         },
-        "/flows/{flowID}/termlogs/": {
+        "/shared/{token}/msglogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Termlogs"
+                    "FlowShares"
                 ],
-                "summary": "Retrieve termlogs list by flow id",
+                "summary": "Retrieve the flow message logs by the share link token",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     },
@@ -2942,7 +5290,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "termlogs list received successful",
+                        "description": "shared flow msglogs received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -2952,7 +5300,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.termlogs"
+                                            "$ref": "#/definitions/services.msglogs"
                                         }
                                     }
                                 }
@@ -2965,14 +5313,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "getting termlogs not permitted",
+                    "404": {
+                        "description": "share link not found or expired",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting termlogs",
+                        "description": "internal error on getting shared flow msglogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -2980,21 +5328,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/flows/{flowID}/vecstorelogs/": {
+        "/shared/{token}/screenshots/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vecstorelogs"
+                    "FlowShares"
                 ],
-                "summary": "Retrieve vecstorelogs list by flow id",
+                "summary": "Retrieve the flow screenshots by the share link token",
                 "parameters": [
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "description": "flow id",
-                        "name": "flowID",
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     },
@@ -3059,7 +5406,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "vecstorelogs list received successful",
+                        "description": "shared flow screenshots received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3069,7 +5416,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.vecstorelogs"
+                                            "$ref": "#/definitions/services.screenshots"
                                         }
                                     }
                                 }
@@ -3082,14 +5429,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "getting vecstorelogs not permitted",
+                    "404": {
+                        "description": "share link not found or expired",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting vecstorelogs",
+                        "description": "internal error on getting shared flow screenshots",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3097,77 +5444,140 @@ const docTemplate = `{
                 }
             }
         },
-        "/graphql": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/shared/{token}/screenshots/{screenshotID}/file": {
+            "get": {
                 "produces": [
+                    "image/png",
                     "application/json"
                 ],
                 "tags": [
-                    "GraphQL"
+                    "FlowShares"
                 ],
-                "summary": "Perform graphql requests",
+                "summary": "Retrieve the flow screenshot file by the share link token",
                 "parameters": [
                     {
-                        "description": "graphql request",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/graphql.RawParams"
-                        }
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "screenshot id",
+                        "name": "screenshotID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "graphql response",
+                        "description": "shared screenshot file",
                         "schema": {
-                            "$ref": "#/definitions/graphql.Response"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "invalid graphql request data",
+                        "description": "invalid screenshot request data",
                         "schema": {
-                            "$ref": "#/definitions/graphql.Response"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "unauthorized",
+                    "404": {
+                        "description": "share link or screenshot not found",
                         "schema": {
-                            "$ref": "#/definitions/graphql.Response"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on graphql request",
+                        "description": "internal error on getting shared screenshot",
                         "schema": {
-                            "$ref": "#/definitions/graphql.Response"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/info": {
+        "/shared/{token}/tasks/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Public"
+                    "FlowShares"
                 ],
-                "summary": "Retrieve current user and system settings",
+                "summary": "Retrieve the flow tasks with subtasks by the share link token",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "boolean arg to refresh current cookie, use explicit false",
-                        "name": "refresh_cookie",
-                        "in": "query"
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
+                        "name": "filters[]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to group results by",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Number of page (since 1)",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": -1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
+                        "name": "pageSize",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "{}",
+                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "sort",
+                            "filter",
+                            "init",
+                            "page",
+                            "size"
+                        ],
+                        "type": "string",
+                        "default": "init",
+                        "description": "Type of request",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "info received successful",
+                        "description": "shared flow tasks received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3177,27 +5587,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.info"
+                                            "$ref": "#/definitions/services.sharedTasks"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "403": {
-                        "description": "getting info not permitted",
+                    "400": {
+                        "description": "invalid query request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "user not found",
+                        "description": "share link not found or expired",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting information about system and config",
+                        "description": "internal error on getting shared flow tasks",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3205,16 +5615,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/msglogs/": {
+        "/shared/{token}/termlogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Msglogs"
+                    "FlowShares"
                 ],
-                "summary": "Retrieve msglogs list",
+                "summary": "Retrieve the flow terminal logs by the share link token",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "array",
                         "items": {
@@ -3276,7 +5693,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "msglogs list received successful",
+                        "description": "shared flow termlogs received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3286,7 +5703,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.msglogs"
+                                            "$ref": "#/definitions/services.termlogs"
                                         }
                                     }
                                 }
@@ -3300,13 +5717,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting msglogs not permitted",
+                        "description": "terminal logs are not shared by the link",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "share link not found or expired",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting msglogs",
+                        "description": "internal error on getting shared flow termlogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3314,15 +5737,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/prompts/": {
+        "/teams/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Prompts"
+                    "Teams"
                 ],
-                "summary": "Retrieve prompts list",
+                "summary": "Retrieve teams list, the teams of the current user unless the user is admin",
                 "parameters": [
                     {
                         "type": "array",
@@ -3385,7 +5808,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "prompts list received successful",
+                        "description": "teams list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3395,7 +5818,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.prompts"
+                                            "$ref": "#/definitions/services.teams"
                                         }
                                     }
                                 }
@@ -3409,41 +5832,44 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting prompts not permitted",
+                        "description": "getting teams not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting prompts",
+                        "description": "internal error on getting teams",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/prompts/{promptType}": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Prompts"
+                    "Teams"
                 ],
-                "summary": "Retrieve prompt by type",
+                "summary": "Create new team, the current user becomes the team admin",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "prompt type",
-                        "name": "promptType",
-                        "in": "path",
-                        "required": true
+                        "description": "team model to create",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTeam"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "prompt received successful",
+                    "201": {
+                        "description": "team created successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3453,7 +5879,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Prompt"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
@@ -3461,63 +5887,48 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid prompt request data",
+                        "description": "invalid team request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting prompt not permitted",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "prompt not found",
+                        "description": "creating team not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting prompt",
+                        "description": "internal error on creating team",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/teams/{teamID}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Prompts"
+                    "Teams"
                 ],
-                "summary": "Update prompt",
+                "summary": "Retrieve team by id with the members",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "prompt type",
-                        "name": "promptType",
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "teamID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "prompt model to update",
-                        "name": "json",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.PatchPrompt"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "prompt updated successful",
+                        "description": "team received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3527,7 +5938,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Prompt"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
@@ -3535,34 +5946,32 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid prompt request data",
+                        "description": "invalid team request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "updating prompt not permitted",
+                        "description": "getting team not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "prompt not found",
+                        "description": "team not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on updating prompt",
+                        "description": "internal error on getting team",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/prompts/{promptType}/default": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -3570,21 +5979,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Prompts"
+                    "Teams"
                 ],
-                "summary": "Reset prompt by type to default value",
+                "summary": "Update team, it's permitted to the team admins",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "prompt type",
-                        "name": "promptType",
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "teamID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "team model to update",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PatchTeam"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "prompt reset successful",
+                        "description": "team updated successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3594,7 +6013,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.Prompt"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
@@ -3602,44 +6021,52 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid prompt request data",
+                        "description": "invalid team request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "updating prompt not permitted",
+                        "description": "updating team not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "prompt not found",
+                        "description": "team not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on resetting prompt",
+                        "description": "internal error on updating team",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/providers/": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Teams"
+                ],
+                "summary": "Delete team by id, the team flows and resources are returned to their owners",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "teamID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Retrieve providers list",
                 "responses": {
                     "200": {
-                        "description": "providers list received successful",
+                        "description": "team deleted successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3649,15 +6076,33 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.ProviderInfo"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "invalid team request data",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "403": {
-                        "description": "getting providers not permitted",
+                        "description": "deleting team not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "team not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on deleting team",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3665,78 +6110,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/roles/": {
-            "get": {
+        "/teams/{teamID}/members/{userID}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Teams"
                 ],
-                "summary": "Retrieve roles list",
+                "summary": "Add the user to the team or change the member role, it's permitted to the team admins, new members are added only with users.edit privilege",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
+                        "minimum": 0,
                         "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
+                        "description": "team id",
+                        "name": "teamID",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "maximum": 1000,
-                        "minimum": -1,
+                        "minimum": 0,
                         "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
+                        "description": "user id",
+                        "name": "userID",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                        "description": "team member role",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetTeamMember"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "roles list received successful",
+                        "description": "team member set successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3746,7 +6161,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.roles"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
@@ -3754,47 +6169,60 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "invalid team member request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting roles not permitted",
+                        "description": "setting team member not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "team or user not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting roles",
+                        "description": "internal error on setting team member",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/roles/{roleID}": {
-            "get": {
+                    }
+                }
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Roles"
+                    "Teams"
                 ],
-                "summary": "Retrieve role by id",
+                "summary": "Remove the user from the team, it's permitted to the team admins and to the member itself",
                 "parameters": [
                     {
+                        "minimum": 0,
                         "type": "integer",
-                        "description": "role id",
-                        "name": "id",
+                        "description": "team id",
+                        "name": "teamID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "userID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "role received successful",
+                        "description": "team member removed successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3804,7 +6232,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.RolePrivileges"
+                                            "$ref": "#/definitions/models.TeamMembers"
                                         }
                                     }
                                 }
@@ -3812,19 +6240,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "invalid team member request data",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting role not permitted",
+                        "description": "removing team member not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "team or team member not found",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting role",
+                        "description": "internal error on removing team member",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3832,15 +6266,15 @@ const docTemplate = `{
                 }
             }
         },
-        "/screenshots/": {
+        "/termlogs/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Screenshots"
+                    "Termlogs"
                 ],
-                "summary": "Retrieve screenshots list",
+                "summary": "Retrieve termlogs list",
                 "parameters": [
                     {
                         "type": "array",
@@ -3903,7 +6337,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "screenshots list received successful",
+                        "description": "termlogs list received successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -3913,7 +6347,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.screenshots"
+                                            "$ref": "#/definitions/services.termlogs"
                                         }
                                     }
                                 }
@@ -3927,13 +6361,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "getting screenshots not permitted",
+                        "description": "getting termlogs not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting screenshots",
+                        "description": "internal error on getting termlogs",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -3941,78 +6375,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/searchlogs/": {
-            "get": {
+        "/token": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Searchlogs"
+                    "Proto"
                 ],
-                "summary": "Retrieve searchlogs list",
+                "summary": "Create new JWT token to use it into automation connections",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "maximum": 1000,
-                        "minimum": -1,
-                        "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                        "description": "Proto auth token request JSON data",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProtoAuthTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "searchlogs list received successful",
+                    "201": {
+                        "description": "token created successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -4022,7 +6410,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.searchlogs"
+                                            "$ref": "#/definitions/models.ProtoAuthToken"
                                         }
                                     }
                                 }
@@ -4030,19 +6418,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "invalid requested token info",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting searchlogs not permitted",
+                        "description": "creating token not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting searchlogs",
+                        "description": "internal error on creating token",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -4050,78 +6438,108 @@ const docTemplate = `{
                 }
             }
         },
-        "/termlogs/": {
+        "/user/": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Termlogs"
+                    "Users"
                 ],
-                "summary": "Retrieve termlogs list",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "Filtering result on server e.g. {\"value\":[...],\"field\":\"...\"}\n  field value should be integer or string or array type",
-                        "name": "filters[]",
-                        "in": "query"
+                "summary": "Retrieve current user information",
+                "responses": {
+                    "200": {
+                        "description": "user info received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserRolePrivileges"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Field to group results by",
-                        "name": "group",
-                        "in": "query"
+                    "403": {
+                        "description": "getting current user not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Number of page (since 1)",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
+                    "404": {
+                        "description": "current user not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "maximum": 1000,
-                        "minimum": -1,
-                        "type": "integer",
-                        "default": 5,
-                        "description": "Amount items per page (min -1, max 1000, -1 means unlimited)",
-                        "name": "pageSize",
-                        "in": "query",
-                        "required": true
+                    "500": {
+                        "description": "internal error on getting current user",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MFA"
+                ],
+                "summary": "Retrieve second factor state of the current user",
+                "responses": {
+                    "200": {
+                        "description": "second factor state received successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MFAStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "string",
-                        "default": "{}",
-                        "description": "Sorting result on server e.g. {\"prop\":\"...\",\"order\":\"...\"}\n  field order is \"ascending\" or \"descending\" value",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
+                    "403": {
+                        "description": "getting second factor state not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     },
-                    {
-                        "enum": [
-                            "sort",
-                            "filter",
-                            "init",
-                            "page",
-                            "size"
-                        ],
-                        "type": "string",
-                        "default": "init",
-                        "description": "Type of request",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
+                    "500": {
+                        "description": "internal error on getting second factor state",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "MFA"
+                ],
+                "summary": "Create TOTP secret and recovery codes, the second factor is enabled after the confirmation",
                 "responses": {
-                    "200": {
-                        "description": "termlogs list received successful",
+                    "201": {
+                        "description": "second factor enrollment created successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -4131,7 +6549,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/services.termlogs"
+                                            "$ref": "#/definitions/models.MFAEnrollment"
                                         }
                                     }
                                 }
@@ -4139,19 +6557,19 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid query request data",
+                        "description": "second factor is already enabled",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "getting termlogs not permitted",
+                        "description": "enrolling second factor not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting termlogs",
+                        "description": "internal error on enrolling second factor",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -4159,7 +6577,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/token": {
+        "/user/mfa/confirm": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -4168,23 +6586,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Proto"
+                    "MFA"
                 ],
-                "summary": "Create new JWT token to use it into automation connections",
+                "summary": "Confirm second factor enrollment of the current user",
                 "parameters": [
                     {
-                        "description": "Proto auth token request JSON data",
+                        "description": "TOTP code from the authenticator app",
                         "name": "json",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProtoAuthTokenRequest"
+                            "$ref": "#/definitions/models.MFACode"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "token created successful",
+                    "200": {
+                        "description": "second factor enabled successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -4194,7 +6612,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.ProtoAuthToken"
+                                            "$ref": "#/definitions/models.MFAStatus"
                                         }
                                     }
                                 }
@@ -4202,19 +6620,31 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "invalid requested token info",
+                        "description": "invalid code data or second factor is not enrolled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid code",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "creating token not permitted",
+                        "description": "confirming second factor not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many failed attempts",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on creating token",
+                        "description": "internal error on confirming second factor",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -4222,18 +6652,95 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/": {
-            "get": {
+        "/user/mfa/disable": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "MFA"
+                ],
+                "summary": "Disable second factor of the current user, the role may require to enroll it again on next login",
+                "parameters": [
+                    {
+                        "description": "TOTP code or recovery code",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MFACode"
+                        }
+                    }
                 ],
-                "summary": "Retrieve current user information",
                 "responses": {
                     "200": {
-                        "description": "user info received successful",
+                        "description": "second factor disabled successful",
+                        "schema": {
+                            "$ref": "#/definitions/SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid code data or second factor is not enrolled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "disabling second factor not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on disabling second factor",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mfa/recovery_codes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MFA"
+                ],
+                "summary": "Replace recovery codes of the current user, the new codes are returned only in this response",
+                "parameters": [
+                    {
+                        "description": "TOTP code or recovery code",
+                        "name": "json",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MFACode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "recovery codes replaced successful",
                         "schema": {
                             "allOf": [
                                 {
@@ -4243,27 +6750,39 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.UserRolePrivileges"
+                                            "$ref": "#/definitions/models.MFARecoveryCodes"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "invalid code data or second factor is not enrolled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "403": {
-                        "description": "getting current user not permitted",
+                        "description": "replacing recovery codes not permitted",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "current user not found",
+                    "429": {
+                        "description": "too many failed attempts",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error on getting current user",
+                        "description": "internal error on replacing recovery codes",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -4680,6 +7199,54 @@ const docTemplate = `{
                 }
             }
         },
+        "/users/{hash}/mfa": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MFA"
+                ],
+                "summary": "Disable second factor of the user by hash",
+                "parameters": [
+                    {
+                        "maxLength": 32,
+                        "minLength": 32,
+                        "type": "string",
+                        "description": "hash in hex format (md5)",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "second factor disabled successful",
+                        "schema": {
+                            "$ref": "#/definitions/SuccessResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "disabling second factor not permitted",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user or second factor not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error on disabling second factor",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/vecstorelogs/": {
             "get": {
                 "produces": [
@@ -4864,58 +7431,156 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "headers": {
-                    "$ref": "#/definitions/http.Header"
+                "headers": {
+                    "$ref": "#/definitions/http.Header"
+                },
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gqlerror.Error"
+                    }
+                },
+                "extensions": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "hasNext": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "http.Header": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "key_prefix",
+                "name",
+                "privileges"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "key_prefix": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "privileges": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.APIKeyToken": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "key",
+                "key_prefix",
+                "name",
+                "privileges"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
                 },
-                "operationName": {
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "key": {
                     "type": "string"
                 },
-                "query": {
+                "key_prefix": {
                     "type": "string"
                 },
-                "variables": {
-                    "type": "object",
-                    "additionalProperties": {}
-                }
-            }
-        },
-        "graphql.Response": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "last_used_at": {
+                    "type": "string"
                 },
-                "errors": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "privileges": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/gqlerror.Error"
+                        "type": "string"
                     }
                 },
-                "extensions": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "hasNext": {
-                    "type": "boolean"
-                },
-                "label": {
+                "revoked_at": {
                     "type": "string"
                 },
-                "path": {
-                    "type": "array",
-                    "items": {}
-                }
-            }
-        },
-        "http.Header": {
-            "type": "object",
-            "additionalProperties": {
-                "type": "array",
-                "items": {
+                "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
@@ -5109,12 +7774,59 @@ const docTemplate = `{
                 }
             }
         },
+        "models.AuditEvent": {
+            "type": "object",
+            "required": [
+                "action",
+                "target_type"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "new_values": {
+                    "type": "object"
+                },
+                "old_values": {
+                    "type": "object"
+                },
+                "remote_addr": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "target_name": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "user_mail": {
+                    "type": "string"
+                }
+            }
+        },
         "models.AuthCallback": {
             "type": "object",
             "required": [
                 "code",
                 "id_token",
-                "scope",
                 "state"
             ],
             "properties": {
@@ -5132,6 +7844,19 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CloneRole": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Pentester"
+                }
+            }
+        },
         "models.Container": {
             "type": "object",
             "required": [
@@ -5177,6 +7902,35 @@ const docTemplate = `{
                 }
             }
         },
+        "models.CreateAPIKey": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "name",
+                "privileges"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "CI pipeline"
+                },
+                "privileges": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "flows.view"
+                    ]
+                }
+            }
+        },
         "models.CreateAssistant": {
             "type": "object",
             "required": [
@@ -5215,12 +7969,66 @@ const docTemplate = `{
                     "type": "string",
                     "example": "user input for first task in the flow"
                 },
+                "plan_review": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "normal",
+                        "high"
+                    ],
+                    "example": "normal"
+                },
                 "provider": {
                     "type": "string",
                     "example": "openai"
                 }
             }
         },
+        "models.CreateRole": {
+            "type": "object",
+            "required": [
+                "name",
+                "privileges"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Pentester"
+                },
+                "privileges": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "flows.view"
+                    ]
+                }
+            }
+        },
+        "models.CreateTeam": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "external perimeter assessments"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Red Team"
+                }
+            }
+        },
         "models.Flow": {
             "type": "object",
             "required": [
@@ -5260,9 +8068,16 @@ const docTemplate = `{
                 "model_provider_type": {
                     "type": "string"
                 },
+                "plan_review": {
+                    "type": "boolean"
+                },
                 "status": {
                     "type": "string"
                 },
+                "team_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "title": {
                     "type": "string"
                 },
@@ -5315,6 +8130,9 @@ const docTemplate = `{
                 "model_provider_type": {
                     "type": "string"
                 },
+                "plan_review": {
+                    "type": "boolean"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -5324,6 +8142,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.TaskSubtasks"
                     }
                 },
+                "team_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "title": {
                     "type": "string"
                 },
@@ -5354,6 +8176,91 @@ const docTemplate = `{
                 }
             }
         },
+        "models.LoginStatus": {
+            "type": "object",
+            "properties": {
+                "mfa_enroll_required": {
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MFACode": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 6,
+                    "example": "123456"
+                }
+            }
+        },
+        "models.MFAEnrollment": {
+            "type": "object",
+            "required": [
+                "qr_code",
+                "recovery_codes",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "qr_code": {
+                    "type": "string",
+                    "example": "data:image/png;base64,..."
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "otpauth://totp/PentAGI:admin@pentagi.com?issuer=PentAGI\u0026secret=..."
+                }
+            }
+        },
+        "models.MFARecoveryCodes": {
+            "type": "object",
+            "required": [
+                "recovery_codes"
+            ],
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.MFAStatus": {
+            "type": "object",
+            "properties": {
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "recovery_codes_left": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
         "models.Msglog": {
             "type": "object",
             "required": [
@@ -5458,18 +8365,59 @@ const docTemplate = `{
                 },
                 "input": {
                     "type": "string",
-                    "example": "user input for waiting flow"
+                    "example": "user input for waiting flow"
+                }
+            }
+        },
+        "models.PatchPrompt": {
+            "type": "object",
+            "required": [
+                "prompt"
+            ],
+            "properties": {
+                "prompt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PatchRole": {
+            "type": "object",
+            "required": [
+                "name",
+                "privileges"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "Pentester"
+                },
+                "privileges": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "flows.view"
+                    ]
                 }
             }
         },
-        "models.PatchPrompt": {
+        "models.PatchTeam": {
             "type": "object",
             "required": [
-                "prompt"
+                "name"
             ],
             "properties": {
-                "prompt": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "external perimeter assessments"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Red Team"
                 }
             }
         },
@@ -5510,6 +8458,10 @@ const docTemplate = `{
                 "prompt": {
                     "type": "string"
                 },
+                "team_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
                 "type": {
                     "type": "string"
                 },
@@ -5690,6 +8642,69 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SetTeamMember": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "operator"
+                }
+            }
+        },
+        "models.ShareFlow": {
+            "type": "object",
+            "properties": {
+                "team_id": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
+                }
+            }
+        },
+        "models.SharedFlow": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "model_provider_name",
+                "model_provider_type",
+                "status",
+                "title"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "model_provider_name": {
+                    "type": "string",
+                    "maxLength": 70
+                },
+                "model_provider_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "with_terminal_logs": {
+                    "type": "boolean"
+                }
+            }
+        },
         "models.Subtask": {
             "type": "object",
             "required": [
@@ -5808,6 +8823,98 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Team": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TeamMemberUser": {
+            "type": "object",
+            "required": [
+                "mail",
+                "role"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "mail": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 70
+                },
+                "role": {
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.TeamMembers": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeamMemberUser"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Termlog": {
             "type": "object",
             "required": [
@@ -6026,6 +9133,50 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UserSession": {
+            "type": "object",
+            "required": [
+                "expires_at",
+                "method"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "remote_addr": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "models.Vecstorelog": {
             "type": "object",
             "required": [
@@ -6088,6 +9239,20 @@ const docTemplate = `{
                 }
             }
         },
+        "services.apiKeys": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIKey"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "services.assistantlogs": {
             "type": "object",
             "properties": {
@@ -6116,6 +9281,20 @@ const docTemplate = `{
                 }
             }
         },
+        "services.auditEvents": {
+            "type": "object",
+            "properties": {
+                "audit_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEvent"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "services.containers": {
             "type": "object",
             "properties": {
@@ -6252,6 +9431,20 @@ const docTemplate = `{
                 }
             }
         },
+        "services.sharedTasks": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskSubtasks"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "services.subtasks": {
             "type": "object",
             "properties": {
@@ -6280,6 +9473,20 @@ const docTemplate = `{
                 }
             }
         },
+        "services.teams": {
+            "type": "object",
+            "properties": {
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Team"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "services.termlogs": {
             "type": "object",
             "properties": {
@@ -6294,6 +9501,20 @@ const docTemplate = `{
                 }
             }
         },
+        "services.userSessions": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserSession"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "services.users": {
             "type": "object",
             "properties": {
@@ -0,0 +1,168 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const (
+	dialTimeout    = 10 * time.Second
+	requestTimeout = 10 * time.Second
+	// ProviderName is the provider name of the LDAP users and the key of their role rules
+	ProviderName = "ldap"
+	// LoginPlaceholder is replaced by the escaped login in the user search filter
+	LoginPlaceholder = "{login}"
+)
+
+var ErrInvalidCredentials = errors.New("invalid login or password")
+
+type Config struct {
+	URL            string
+	StartTLS       bool
+	SkipVerify     bool
+	BindDN         string
+	BindPassword   string
+	BaseDN         string
+	UserFilter     string
+	MailAttribute  string
+	NameAttribute  string
+	GroupAttribute string
+}
+
+// User is the directory entry of the authenticated user, groups contain both the full DNs
+// of the groups and their common names to match the role rules
+type User struct {
+	DN     string
+	Mail   string
+	Name   string
+	Groups []string
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, login, password string) (User, error)
+}
+
+type authenticator struct {
+	cfg Config
+}
+
+// NewAuthenticator returns the authenticator which finds the user entry by the service account
+// (or anonymously if the bind DN isn't set) and checks the password by the bind as the user
+func NewAuthenticator(cfg Config) Authenticator {
+	return &authenticator{cfg: cfg}
+}
+
+func (a *authenticator) Authenticate(ctx context.Context, login, password string) (User, error) {
+	// empty password makes the unauthenticated bind which succeeds on many servers
+	if login == "" || password == "" {
+		return User{}, ErrInvalidCredentials
+	}
+
+	conn, err := a.dial(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	defer conn.Close()
+
+	if a.cfg.BindDN != "" {
+		if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+			return User{}, fmt.Errorf("failed to bind service account: %w", err)
+		}
+	}
+
+	attributes := []string{a.cfg.MailAttribute, a.cfg.NameAttribute, a.cfg.GroupAttribute}
+	result, err := conn.Search(goldap.NewSearchRequest(
+		a.cfg.BaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, int(requestTimeout/time.Second), false,
+		UserFilter(a.cfg.UserFilter, login),
+		attributes,
+		nil,
+	))
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return User{}, fmt.Errorf("failed to search user: %w", err)
+	}
+	if result == nil || len(result.Entries) != 1 {
+		// the login is ambiguous or unknown, both cases are reported as invalid credentials
+		return User{}, ErrInvalidCredentials
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return User{}, ErrInvalidCredentials
+		}
+		return User{}, fmt.Errorf("failed to bind user: %w", err)
+	}
+
+	user := User{
+		DN:     entry.DN,
+		Mail:   strings.ToLower(entry.GetAttributeValue(a.cfg.MailAttribute)),
+		Name:   entry.GetAttributeValue(a.cfg.NameAttribute),
+		Groups: GroupNames(entry.GetAttributeValues(a.cfg.GroupAttribute)),
+	}
+	if user.Mail == "" {
+		return User{}, fmt.Errorf("user entry '%s' has no '%s' attribute", entry.DN, a.cfg.MailAttribute)
+	}
+
+	return user, nil
+}
+
+func (a *authenticator) dial(ctx context.Context) (*goldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: a.cfg.SkipVerify} // nolint:gosec
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := goldap.DialURL(a.cfg.URL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	conn.SetTimeout(requestTimeout)
+
+	if a.cfg.StartTLS {
+		if host, _, err := net.SplitHostPort(strings.TrimPrefix(a.cfg.URL, "ldap://")); err == nil {
+			tlsConfig.ServerName = host
+		}
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// UserFilter returns the search filter with the escaped login in place of the placeholder
+func UserFilter(filter, login string) string {
+	return strings.ReplaceAll(filter, LoginPlaceholder, goldap.EscapeFilter(login))
+}
+
+// GroupNames returns the group DNs with their common names, so the role rules can use both
+func GroupNames(groups []string) []string {
+	names := make([]string, 0, len(groups)*2)
+	for _, group := range groups {
+		names = append(names, group)
+
+		dn, err := goldap.ParseDN(group)
+		if err != nil || len(dn.RDNs) == 0 {
+			continue
+		}
+		for _, attr := range dn.RDNs[0].Attributes {
+			if strings.EqualFold(attr.Type, "cn") {
+				names = append(names, attr.Value)
+			}
+		}
+	}
+
+	return names
+}
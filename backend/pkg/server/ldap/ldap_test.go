@@ -0,0 +1,40 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserFilter(t *testing.T) {
+	filter := "(|(uid={login})(mail={login}))"
+
+	assert.Equal(t, "(|(uid=jdoe)(mail=jdoe))", UserFilter(filter, "jdoe"))
+	assert.Equal(t, `(|(uid=\2a\29\28uid=\2a)(mail=\2a\29\28uid=\2a))`, UserFilter(filter, "*)(uid=*"))
+}
+
+func TestGroupNames(t *testing.T) {
+	groups := GroupNames([]string{
+		"cn=pentagi-admins,ou=groups,dc=example,dc=com",
+		"CN=Domain Users,CN=Users,DC=example,DC=com",
+		"not a dn",
+	})
+
+	assert.Equal(t, []string{
+		"cn=pentagi-admins,ou=groups,dc=example,dc=com",
+		"pentagi-admins",
+		"CN=Domain Users,CN=Users,DC=example,DC=com",
+		"Domain Users",
+		"not a dn",
+	}, groups)
+}
+
+func TestAuthenticateEmptyCredentials(t *testing.T) {
+	authenticator := NewAuthenticator(Config{URL: "ldap://127.0.0.1:1"})
+
+	_, err := authenticator.Authenticate(context.Background(), "jdoe", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = authenticator.Authenticate(context.Background(), "", "secret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -1,6 +1,9 @@
 package router
 
 import (
+	"crypto/subtle"
+	"strings"
+
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/response"
 
@@ -34,3 +37,22 @@ func noCacheMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// metricsTokenRequired checks the static bearer token of the metrics scraper, empty token disables the check
+func metricsTokenRequired(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		auth := c.GetHeader("Authorization")
+		bearer, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			response.Error(c, response.ErrAuthRequired, nil)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsTokenRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"disabled without header", "", "", http.StatusOK},
+		{"disabled with any header", "", "Bearer anything", http.StatusOK},
+		{"valid token", "scrape-token", "Bearer scrape-token", http.StatusOK},
+		{"missing header", "scrape-token", "", http.StatusForbidden},
+		{"wrong token", "scrape-token", "Bearer other-token", http.StatusForbidden},
+		{"token prefix", "scrape-token", "Bearer scrape", http.StatusForbidden},
+		{"token with suffix", "scrape-token", "Bearer scrape-token2", http.StatusForbidden},
+		{"empty bearer", "scrape-token", "Bearer ", http.StatusForbidden},
+		{"token without scheme", "scrape-token", "scrape-token", http.StatusForbidden},
+		{"basic scheme", "scrape-token", "Basic scrape-token", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			router := gin.New()
+			router.GET("/metrics", metricsTokenRequired(tt.token), func(c *gin.Context) {
+				reached = true
+				c.String(http.StatusOK, "metrics")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, tt.want == http.StatusOK, reached)
+		})
+	}
+}
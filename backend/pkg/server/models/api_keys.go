@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// APIKey is model to contain personal API key information, the key itself is never stored
+// nolint:lll
+type APIKey struct {
+	ID         uint64         `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	UserID     uint64         `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	Name       string         `form:"name" json:"name" validate:"max=100,required" gorm:"type:TEXT;NOT NULL"`
+	KeyPrefix  string         `form:"key_prefix" json:"key_prefix" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	KeyHash    string         `form:"-" json:"-" validate:"len=64,hexadecimal,lowercase" gorm:"type:TEXT;NOT NULL;UNIQUE_INDEX"`
+	Privileges pq.StringArray `form:"privileges" json:"privileges" validate:"required" swaggertype:"array,string" gorm:"type:TEXT[];NOT NULL;default:'{}'"`
+	ExpiresAt  time.Time      `form:"expires_at" json:"expires_at" validate:"required" gorm:"type:TIMESTAMPTZ;NOT NULL"`
+	LastUsedAt *time.Time     `form:"last_used_at,omitempty" json:"last_used_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	RevokedAt  *time.Time     `form:"revoked_at,omitempty" json:"revoked_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	CreatedAt  time.Time      `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (ak *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Valid is function to control input/output data
+func (ak APIKey) Valid() error {
+	return validate.Struct(ak)
+}
+
+// Validate is function to use callback to control input/output data
+func (ak APIKey) Validate(db *gorm.DB) {
+	if err := ak.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// Active returns true if the API key isn't revoked and isn't expired yet
+func (ak APIKey) Active(now time.Time) bool {
+	return ak.RevokedAt == nil && now.Before(ak.ExpiresAt)
+}
+
+// CreateAPIKey is model to contain personal API key creation paylaod
+// nolint:lll
+type CreateAPIKey struct {
+	Name       string    `form:"name" json:"name" validate:"max=100,required" example:"CI pipeline"`
+	Privileges []string  `form:"privileges" json:"privileges" validate:"min=1,dive,required" example:"flows.view"`
+	ExpiresAt  time.Time `form:"expires_at" json:"expires_at" validate:"required" example:"2026-01-01T00:00:00Z"`
+}
+
+// Valid is function to control input/output data
+func (cak CreateAPIKey) Valid() error {
+	return validate.Struct(cak)
+}
+
+// APIKeyToken is model to contain just created personal API key, the key is returned only once
+type APIKeyToken struct {
+	Key    string `form:"key" json:"key" validate:"required"`
+	APIKey `form:"" json:""`
+}
+
+// Valid is function to control input/output data
+func (akt APIKeyToken) Valid() error {
+	if err := akt.APIKey.Valid(); err != nil {
+		return err
+	}
+	return validate.Struct(akt)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEvent is model to contain the record of the security-relevant action, the records are never changed
+// nolint:lll
+type AuditEvent struct {
+	ID         uint64          `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	UserID     *uint64         `form:"user_id,omitempty" json:"user_id,omitempty" validate:"omitempty,min=0,numeric" gorm:"type:BIGINT"`
+	UserMail   string          `form:"user_mail" json:"user_mail" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	Action     string          `form:"action" json:"action" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	TargetType string          `form:"target_type" json:"target_type" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	TargetID   *uint64         `form:"target_id,omitempty" json:"target_id,omitempty" validate:"omitempty,min=0,numeric" gorm:"type:BIGINT"`
+	TargetName string          `form:"target_name" json:"target_name" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	OldValues  json.RawMessage `form:"old_values" json:"old_values" validate:"omitempty" swaggertype:"object" gorm:"type:JSON;NOT NULL;default:'{}'"`
+	NewValues  json.RawMessage `form:"new_values" json:"new_values" validate:"omitempty" swaggertype:"object" gorm:"type:JSON;NOT NULL;default:'{}'"`
+	RemoteAddr string          `form:"remote_addr" json:"remote_addr" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	UserAgent  string          `form:"user_agent" json:"user_agent" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	CreatedAt  time.Time       `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (ae *AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// Valid is function to control input/output data
+func (ae AuditEvent) Valid() error {
+	return validate.Struct(ae)
+}
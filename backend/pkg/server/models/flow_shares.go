@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// FlowShare is model to contain read-only flow share link information, the token itself is never stored
+// nolint:lll
+type FlowShare struct {
+	ID               uint64     `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	FlowID           uint64     `form:"flow_id" json:"flow_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	UserID           uint64     `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	TokenPrefix      string     `form:"token_prefix" json:"token_prefix" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	TokenHash        string     `form:"-" json:"-" validate:"len=64,hexadecimal,lowercase" gorm:"type:TEXT;NOT NULL;UNIQUE_INDEX"`
+	WithTerminalLogs bool       `form:"with_terminal_logs" json:"with_terminal_logs" validate:"omitempty" gorm:"type:BOOLEAN;NOT NULL;default:false"`
+	ExpiresAt        time.Time  `form:"expires_at" json:"expires_at" validate:"required" gorm:"type:TIMESTAMPTZ;NOT NULL"`
+	RevokedAt        *time.Time `form:"revoked_at,omitempty" json:"revoked_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	CreatedAt        time.Time  `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt        time.Time  `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (fs *FlowShare) TableName() string {
+	return "flow_shares"
+}
+
+// Valid is function to control input/output data
+func (fs FlowShare) Valid() error {
+	return validate.Struct(fs)
+}
+
+// Validate is function to use callback to control input/output data
+func (fs FlowShare) Validate(db *gorm.DB) {
+	if err := fs.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// Active returns true if the share link isn't revoked and isn't expired yet
+func (fs FlowShare) Active(now time.Time) bool {
+	return fs.RevokedAt == nil && now.Before(fs.ExpiresAt)
+}
+
+// FlowShareAccess is model to contain the record of the request made with the share link
+// nolint:lll
+type FlowShareAccess struct {
+	ID         uint64    `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	ShareID    uint64    `form:"share_id" json:"share_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	Resource   string    `form:"resource" json:"resource" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	RemoteAddr string    `form:"remote_addr" json:"remote_addr" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	UserAgent  string    `form:"user_agent" json:"user_agent" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	CreatedAt  time.Time `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (fsa *FlowShareAccess) TableName() string {
+	return "flow_share_accesses"
+}
+
+// Valid is function to control input/output data
+func (fsa FlowShareAccess) Valid() error {
+	return validate.Struct(fsa)
+}
+
+// SharedFlow is model to contain the flow information which is shown by the share link,
+// it omits the owner and the flow configuration
+// nolint:lll
+type SharedFlow struct {
+	ID                uint64       `form:"id" json:"id" validate:"min=0,numeric"`
+	Status            FlowStatus   `form:"status" json:"status" validate:"valid,required"`
+	Title             string       `form:"title" json:"title" validate:"required"`
+	ModelProviderName string       `form:"model_provider_name" json:"model_provider_name" validate:"max=70,required"`
+	ModelProviderType ProviderType `form:"model_provider_type" json:"model_provider_type" validate:"valid,required"`
+	WithTerminalLogs  bool         `form:"with_terminal_logs" json:"with_terminal_logs" validate:"omitempty"`
+	ExpiresAt         time.Time    `form:"expires_at" json:"expires_at" validate:"required"`
+	CreatedAt         time.Time    `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty"`
+	UpdatedAt         time.Time    `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty"`
+}
+
+// Valid is function to control input/output data
+func (sf SharedFlow) Valid() error {
+	return validate.Struct(sf)
+}
@@ -12,6 +12,7 @@ import (
 type FlowStatus string
 
 const (
+	FlowStatusQueued   FlowStatus = "queued"
 	FlowStatusCreated  FlowStatus = "created"
 	FlowStatusRunning  FlowStatus = "running"
 	FlowStatusWaiting  FlowStatus = "waiting"
@@ -26,7 +27,8 @@ func (s FlowStatus) String() string {
 // Valid is function to control input/output data
 func (s FlowStatus) Valid() error {
 	switch s {
-	case FlowStatusCreated,
+	case FlowStatusQueued,
+		FlowStatusCreated,
 		FlowStatusRunning,
 		FlowStatusWaiting,
 		FlowStatusFinished,
@@ -55,7 +57,9 @@ type Flow struct {
 	ModelProviderType ProviderType     `form:"model_provider_type" json:"model_provider_type" validate:"valid,required" gorm:"type:PROVIDER_TYPE;NOT NULL"`
 	Language          string           `form:"language" json:"language" validate:"max=70,required" gorm:"type:TEXT;NOT NULL"`
 	Functions         *tools.Functions `form:"functions,omitempty" json:"functions,omitempty" validate:"omitempty,valid" gorm:"type:JSON;NOT NULL;default:'{}'"`
+	PlanReview        bool             `form:"plan_review" json:"plan_review" validate:"omitempty" gorm:"type:BOOLEAN;NOT NULL;default:false"`
 	UserID            uint64           `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	TeamID            *uint64          `form:"team_id,omitempty" json:"team_id,omitempty" validate:"omitempty,min=0,numeric" gorm:"type:BIGINT"`
 	CreatedAt         time.Time        `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
 	UpdatedAt         time.Time        `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
 	DeletedAt         *time.Time       `form:"deleted_at,omitempty" json:"deleted_at,omitempty" validate:"omitempty" sql:"index" gorm:"type:TIMESTAMPTZ"`
@@ -81,9 +85,11 @@ func (f Flow) Validate(db *gorm.DB) {
 // CreateFlow is model to contain flow creation paylaod
 // nolint:lll
 type CreateFlow struct {
-	Input     string           `form:"input" json:"input" validate:"required" example:"user input for first task in the flow"`
-	Provider  string           `form:"provider" json:"provider" validate:"required" example:"openai"`
-	Functions *tools.Functions `form:"functions,omitempty" json:"functions,omitempty" validate:"omitempty,valid"`
+	Input      string           `form:"input" json:"input" validate:"required" example:"user input for first task in the flow"`
+	Provider   string           `form:"provider" json:"provider" validate:"required" example:"openai"`
+	Functions  *tools.Functions `form:"functions,omitempty" json:"functions,omitempty" validate:"omitempty,valid"`
+	PlanReview bool             `form:"plan_review,omitempty" json:"plan_review,omitempty" example:"false"`
+	Priority   string           `form:"priority,omitempty" json:"priority,omitempty" validate:"omitempty,oneof=low normal high" enums:"low,normal,high" example:"normal"`
 }
 
 // Valid is function to control input/output data
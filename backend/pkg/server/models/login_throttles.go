@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginThrottle is model to contain failed login attempts by the account or the client address key
+// nolint:lll
+type LoginThrottle struct {
+	Key           string     `form:"key" json:"key" validate:"required" gorm:"type:TEXT;NOT NULL;PRIMARY_KEY"`
+	Failures      int        `form:"failures" json:"failures" validate:"min=0" gorm:"type:INTEGER;NOT NULL;default:0"`
+	LastFailureAt time.Time  `form:"last_failure_at" json:"last_failure_at" validate:"omitempty" gorm:"type:TIMESTAMPTZ;NOT NULL;default:CURRENT_TIMESTAMP"`
+	LockedUntil   *time.Time `form:"locked_until,omitempty" json:"locked_until,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	CreatedAt     time.Time  `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (lt *LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// Valid is function to control input/output data
+func (lt LoginThrottle) Valid() error {
+	return validate.Struct(lt)
+}
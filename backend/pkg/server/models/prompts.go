@@ -58,6 +58,7 @@ type Prompt struct {
 	ID        uint64     `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
 	Type      PromptType `form:"type" json:"type" validate:"valid,required" gorm:"type:PROMPT_TYPE;NOT NULL"`
 	UserID    uint64     `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	TeamID    *uint64    `form:"team_id,omitempty" json:"team_id,omitempty" validate:"omitempty,min=0,numeric" gorm:"type:BIGINT"`
 	Prompt    string     `form:"prompt" json:"prompt" validate:"required" gorm:"type:TEXT;NOT NULL"`
 	CreatedAt time.Time  `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time  `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
@@ -25,7 +25,9 @@ func (s ProviderType) Valid() error {
 		provider.ProviderGemini,
 		provider.ProviderBedrock,
 		provider.ProviderOllama,
-		provider.ProviderCustom:
+		provider.ProviderCustom,
+		provider.ProviderReplay,
+		provider.ProviderMock:
 		return nil
 	default:
 		return fmt.Errorf("invalid ProviderType: %s", s)
@@ -44,6 +46,7 @@ func (s ProviderType) Validate(db *gorm.DB) {
 type Provider struct {
 	ID        uint64          `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
 	UserID    uint64          `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	TeamID    *uint64         `form:"team_id,omitempty" json:"team_id,omitempty" validate:"omitempty,min=0,numeric" gorm:"type:BIGINT"`
 	Type      ProviderType    `form:"type" json:"type" validate:"valid,required" gorm:"type:PROVIDER_TYPE;NOT NULL"`
 	Name      string          `form:"name" json:"name" validate:"required" gorm:"type:TEXT;NOT NULL"`
 	Config    json.RawMessage `form:"config" json:"config" validate:"required" gorm:"type:JSON;NOT NULL"`
@@ -1,6 +1,12 @@
 package models
 
-import "github.com/jinzhu/gorm"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
 
 // Role is model to contain user role information
 // nolint:lll
@@ -72,3 +78,144 @@ func (rp RolePrivileges) Validate(db *gorm.DB) {
 		db.AddError(err)
 	}
 }
+
+// Privileges is the registry of known privileges which can be granted to the roles,
+// it must be extended together with the migrations which seed the new privileges
+var Privileges = []string{
+	"agentlogs.admin", "agentlogs.subscribe", "agentlogs.view",
+	"api_keys.admin", "api_keys.create", "api_keys.delete", "api_keys.view",
+	"assistantlogs.admin", "assistantlogs.subscribe", "assistantlogs.view",
+	"assistants.admin", "assistants.create", "assistants.delete", "assistants.edit", "assistants.subscribe", "assistants.view",
+	"audit_events.view",
+	"campaigns.admin", "campaigns.create", "campaigns.delete", "campaigns.edit", "campaigns.view",
+	"containers.admin", "containers.view",
+	"flow_shares.admin", "flow_shares.create", "flow_shares.delete", "flow_shares.view",
+	"flows.admin", "flows.create", "flows.delete", "flows.edit", "flows.subscribe", "flows.view",
+	"mfa.admin", "mfa.required",
+	"msglogs.admin", "msglogs.subscribe", "msglogs.view",
+	"playbooks.admin", "playbooks.edit", "playbooks.view",
+	"providers.view",
+	"roles.create", "roles.edit", "roles.view",
+	"schedules.admin", "schedules.create", "schedules.delete", "schedules.edit", "schedules.view",
+	"screenshots.admin", "screenshots.download", "screenshots.subscribe", "screenshots.view",
+	"searchlogs.admin", "searchlogs.subscribe", "searchlogs.view",
+	"sessions.admin", "sessions.delete", "sessions.view",
+	"settings.admin", "settings.view",
+	"settings.prompts.admin", "settings.prompts.edit", "settings.prompts.view",
+	"settings.providers.admin", "settings.providers.edit", "settings.providers.subscribe", "settings.providers.view",
+	"subtasks.admin", "subtasks.view",
+	"tasks.admin", "tasks.subscribe", "tasks.view",
+	"teams.admin", "teams.create", "teams.delete", "teams.edit", "teams.view",
+	"termlogs.admin", "termlogs.subscribe", "termlogs.view",
+	"users.create", "users.delete", "users.edit", "users.view",
+	"vecstorelogs.admin", "vecstorelogs.subscribe", "vecstorelogs.view",
+	"webhooks.admin", "webhooks.create", "webhooks.delete", "webhooks.edit", "webhooks.view",
+}
+
+// ValidatePrivileges checks that the privileges are known and not duplicated
+func ValidatePrivileges(privs []string) error {
+	seen := make(map[string]struct{}, len(privs))
+	for _, priv := range privs {
+		if !slices.Contains(Privileges, priv) {
+			return fmt.Errorf("unknown privilege '%s'", priv)
+		}
+		if _, ok := seen[priv]; ok {
+			return fmt.Errorf("duplicated privilege '%s'", priv)
+		}
+		seen[priv] = struct{}{}
+	}
+
+	return nil
+}
+
+// CreateRole is model to contain role creation payload
+// nolint:lll
+type CreateRole struct {
+	Name       string   `form:"name" json:"name" validate:"max=50,required" example:"Pentester"`
+	Privileges []string `form:"privileges" json:"privileges" validate:"required,dive,required" example:"flows.view"`
+}
+
+// Valid is function to control input/output data
+func (cr CreateRole) Valid() error {
+	if err := validate.Struct(cr); err != nil {
+		return err
+	}
+	return ValidatePrivileges(cr.Privileges)
+}
+
+// CloneRole is model to contain payload to create the role with the privileges of the other one
+// nolint:lll
+type CloneRole struct {
+	Name string `form:"name" json:"name" validate:"max=50,required" example:"Pentester"`
+}
+
+// Valid is function to control input/output data
+func (cr CloneRole) Valid() error {
+	return validate.Struct(cr)
+}
+
+// PatchRole is model to contain role update payload, the privileges replace the current ones
+// nolint:lll
+type PatchRole struct {
+	Name       string   `form:"name" json:"name" validate:"max=50,required" example:"Pentester"`
+	Privileges []string `form:"privileges" json:"privileges" validate:"required,dive,required" example:"flows.view"`
+}
+
+// Valid is function to control input/output data
+func (pr PatchRole) Valid() error {
+	if err := validate.Struct(pr); err != nil {
+		return err
+	}
+	return ValidatePrivileges(pr.Privileges)
+}
+
+// RoleRuleAnyGroup is the group of the role rule which matches any external user
+const RoleRuleAnyGroup = "*"
+
+// RoleRule assigns the role by name to the external user who is a member of the group
+type RoleRule struct {
+	Group string
+	Role  string
+}
+
+// RoleRules is the ordered list of the role rules of the external login provider, the first matched rule wins
+type RoleRules []RoleRule
+
+// ParseRoleRules parses rules from the comma separated "group:Role" pairs, e.g. "pentagi-admins:Admin,*:User"
+func ParseRoleRules(rules string) (RoleRules, error) {
+	var result RoleRules
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		idx := strings.LastIndex(rule, ":")
+		if idx <= 0 || idx == len(rule)-1 {
+			return nil, fmt.Errorf("invalid role rule '%s', expected 'group:Role'", rule)
+		}
+
+		result = append(result, RoleRule{
+			Group: strings.TrimSpace(rule[:idx]),
+			Role:  strings.TrimSpace(rule[idx+1:]),
+		})
+	}
+
+	return result, nil
+}
+
+// Match returns the first rule which group is in the user groups, group names are case insensitive
+func (rr RoleRules) Match(groups []string) (RoleRule, bool) {
+	for _, rule := range rr {
+		if rule.Group == RoleRuleAnyGroup {
+			return rule, true
+		}
+		for _, group := range groups {
+			if strings.EqualFold(rule.Group, group) {
+				return rule, true
+			}
+		}
+	}
+
+	return RoleRule{}, false
+}
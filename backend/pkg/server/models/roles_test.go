@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoleRules(t *testing.T) {
+	rules, err := ParseRoleRules(" pentagi-admins:Admin, urn:group:testers:User ,*:User,")
+	require.NoError(t, err)
+	assert.Equal(t, RoleRules{
+		{Group: "pentagi-admins", Role: "Admin"},
+		{Group: "urn:group:testers", Role: "User"},
+		{Group: RoleRuleAnyGroup, Role: "User"},
+	}, rules)
+
+	rules, err = ParseRoleRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{"admins", ":Admin", "admins:"} {
+		_, err = ParseRoleRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRoleRulesMatch(t *testing.T) {
+	rules := RoleRules{
+		{Group: "pentagi-admins", Role: "Admin"},
+		{Group: "pentagi-users", Role: "User"},
+	}
+
+	rule, ok := rules.Match([]string{"other", "PentAGI-Users", "pentagi-admins"})
+	require.True(t, ok)
+	assert.Equal(t, "Admin", rule.Role)
+
+	_, ok = rules.Match([]string{"other"})
+	assert.False(t, ok)
+	_, ok = rules.Match(nil)
+	assert.False(t, ok)
+
+	rules = append(rules, RoleRule{Group: RoleRuleAnyGroup, Role: "User"})
+	rule, ok = rules.Match(nil)
+	require.True(t, ok)
+	assert.Equal(t, RoleRuleAnyGroup, rule.Group)
+}
+
+func TestValidatePrivileges(t *testing.T) {
+	assert.NoError(t, ValidatePrivileges(nil))
+	assert.NoError(t, ValidatePrivileges([]string{"flows.view", "settings.providers.edit"}))
+
+	assert.Error(t, ValidatePrivileges([]string{"flows.view", "flows.view"}))
+	assert.Error(t, ValidatePrivileges([]string{"flows.unknown"}))
+	assert.Error(t, ValidatePrivileges([]string{"pentagi.automation"}))
+
+	assert.NoError(t, ValidatePrivileges(Privileges), "registry must not contain duplicates")
+}
+
+func TestCreateRoleValid(t *testing.T) {
+	assert.NoError(t, CreateRole{Name: "Pentester", Privileges: []string{"flows.view"}}.Valid())
+	assert.NoError(t, CreateRole{Name: "Empty", Privileges: []string{}}.Valid())
+
+	assert.Error(t, CreateRole{Privileges: []string{"flows.view"}}.Valid())
+	assert.Error(t, CreateRole{Name: "Pentester"}.Valid())
+	assert.Error(t, CreateRole{Name: "Pentester", Privileges: []string{"flows.unknown"}}.Valid())
+}
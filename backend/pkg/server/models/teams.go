@@ -0,0 +1,207 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type TeamRole string
+
+const (
+	TeamRoleViewer   TeamRole = "viewer"
+	TeamRoleOperator TeamRole = "operator"
+	TeamRoleAdmin    TeamRole = "admin"
+)
+
+func (s TeamRole) String() string {
+	return string(s)
+}
+
+// Valid is function to control input/output data
+func (s TeamRole) Valid() error {
+	switch s {
+	case TeamRoleViewer, TeamRoleOperator, TeamRoleAdmin:
+		return nil
+	default:
+		return fmt.Errorf("invalid TeamRole: %s", s)
+	}
+}
+
+// Validate is function to use callback to control input/output data
+func (s TeamRole) Validate(db *gorm.DB) {
+	if err := s.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+func (s TeamRole) level() int {
+	switch s {
+	case TeamRoleViewer:
+		return 1
+	case TeamRoleOperator:
+		return 2
+	case TeamRoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows returns true if the team role is the required one or grants more
+func (s TeamRole) Allows(required TeamRole) bool {
+	return s.level() > 0 && s.level() >= required.level()
+}
+
+// TeamRolesAllowing returns the team roles which are the required one or grant more
+func TeamRolesAllowing(required TeamRole) []string {
+	var roles []string
+	for _, role := range []TeamRole{TeamRoleViewer, TeamRoleOperator, TeamRoleAdmin} {
+		if role.Allows(required) {
+			roles = append(roles, role.String())
+		}
+	}
+
+	return roles
+}
+
+// TeamRoleForPrivilege returns the minimal team role to use the privilege on the team flows:
+// viewers read and subscribe, operators create and edit, admins delete
+func TeamRoleForPrivilege(priv string) TeamRole {
+	action := priv[strings.LastIndex(priv, ".")+1:]
+	switch action {
+	case "view", "subscribe", "download":
+		return TeamRoleViewer
+	case "delete":
+		return TeamRoleAdmin
+	default:
+		return TeamRoleOperator
+	}
+}
+
+// Team is model to contain team information
+// nolint:lll
+type Team struct {
+	ID          uint64    `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	Name        string    `form:"name" json:"name" validate:"max=100,required" gorm:"type:TEXT;NOT NULL"`
+	Description string    `form:"description" json:"description" validate:"max=1000" gorm:"type:TEXT;NOT NULL;default:''"`
+	CreatedAt   time.Time `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (t *Team) TableName() string {
+	return "teams"
+}
+
+// Valid is function to control input/output data
+func (t Team) Valid() error {
+	return validate.Struct(t)
+}
+
+// Validate is function to use callback to control input/output data
+func (t Team) Validate(db *gorm.DB) {
+	if err := t.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// TeamMember is model to contain team membership information
+// nolint:lll
+type TeamMember struct {
+	TeamID    uint64    `form:"team_id" json:"team_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY"`
+	UserID    uint64    `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY"`
+	Role      TeamRole  `form:"role" json:"role" validate:"valid,required" gorm:"type:TEAM_ROLE;NOT NULL;default:'viewer'"`
+	CreatedAt time.Time `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (tm *TeamMember) TableName() string {
+	return "team_members"
+}
+
+// Valid is function to control input/output data
+func (tm TeamMember) Valid() error {
+	return validate.Struct(tm)
+}
+
+// Validate is function to use callback to control input/output data
+func (tm TeamMember) Validate(db *gorm.DB) {
+	if err := tm.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// TeamMemberUser is model to contain team membership information with the member name and mail
+// nolint:lll
+type TeamMemberUser struct {
+	Name       string `form:"name" json:"name" validate:"max=70,omitempty"`
+	Mail       string `form:"mail" json:"mail" validate:"max=50,required"`
+	TeamMember `form:"" json:""`
+}
+
+// Valid is function to control input/output data
+func (tmu TeamMemberUser) Valid() error {
+	return validate.Struct(tmu)
+}
+
+// TeamMembers is model to contain team information with the list of members
+// nolint:lll
+type TeamMembers struct {
+	Members []TeamMemberUser `form:"members" json:"members" validate:"omitempty,dive"`
+	Team    `form:"" json:""`
+}
+
+// Valid is function to control input/output data
+func (tm TeamMembers) Valid() error {
+	return validate.Struct(tm)
+}
+
+// CreateTeam is model to contain team creation payload, the creator becomes the team admin
+// nolint:lll
+type CreateTeam struct {
+	Name        string `form:"name" json:"name" validate:"max=100,required" example:"Red Team"`
+	Description string `form:"description" json:"description" validate:"max=1000" example:"external perimeter assessments"`
+}
+
+// Valid is function to control input/output data
+func (ct CreateTeam) Valid() error {
+	return validate.Struct(ct)
+}
+
+// PatchTeam is model to contain team update payload
+// nolint:lll
+type PatchTeam struct {
+	Name        string `form:"name" json:"name" validate:"max=100,required" example:"Red Team"`
+	Description string `form:"description" json:"description" validate:"max=1000" example:"external perimeter assessments"`
+}
+
+// Valid is function to control input/output data
+func (pt PatchTeam) Valid() error {
+	return validate.Struct(pt)
+}
+
+// SetTeamMember is model to contain payload to add the user to the team or to change the member role
+// nolint:lll
+type SetTeamMember struct {
+	Role TeamRole `form:"role" json:"role" validate:"valid,required" example:"operator"`
+}
+
+// Valid is function to control input/output data
+func (stm SetTeamMember) Valid() error {
+	return validate.Struct(stm)
+}
+
+// ShareFlow is model to contain payload to move the flow to the team or back to the owner, null team id unshares it
+// nolint:lll
+type ShareFlow struct {
+	TeamID *uint64 `form:"team_id,omitempty" json:"team_id,omitempty" validate:"omitempty,min=1,numeric" example:"1"`
+}
+
+// Valid is function to control input/output data
+func (sf ShareFlow) Valid() error {
+	return validate.Struct(sf)
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamRoleAllows(t *testing.T) {
+	assert.True(t, TeamRoleAdmin.Allows(TeamRoleViewer))
+	assert.True(t, TeamRoleOperator.Allows(TeamRoleOperator))
+	assert.False(t, TeamRoleViewer.Allows(TeamRoleOperator))
+	assert.False(t, TeamRoleOperator.Allows(TeamRoleAdmin))
+	assert.False(t, TeamRole("owner").Allows(TeamRole("owner")))
+
+	assert.Equal(t, []string{"operator", "admin"}, TeamRolesAllowing(TeamRoleOperator))
+	assert.Equal(t, []string{"viewer", "operator", "admin"}, TeamRolesAllowing(TeamRoleViewer))
+}
+
+func TestTeamRoleForPrivilege(t *testing.T) {
+	for priv, role := range map[string]TeamRole{
+		"flows.view":      TeamRoleViewer,
+		"flows.subscribe": TeamRoleViewer,
+		"files.download":  TeamRoleViewer,
+		"flows.create":    TeamRoleOperator,
+		"flows.edit":      TeamRoleOperator,
+		"assistants.edit": TeamRoleOperator,
+		"flows.delete":    TeamRoleAdmin,
+	} {
+		assert.Equal(t, role, TeamRoleForPrivilege(priv), priv)
+	}
+}
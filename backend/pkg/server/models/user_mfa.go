@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// UserMFA is model to contain TOTP second factor of the local user, the secret and codes are never returned
+// nolint:lll
+type UserMFA struct {
+	UserID        uint64         `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY"`
+	Secret        string         `form:"-" json:"-" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	RecoveryCodes pq.StringArray `form:"-" json:"-" validate:"omitempty" gorm:"type:TEXT[];NOT NULL;default:'{}'"`
+	LastUsedStep  int64          `form:"-" json:"-" validate:"min=0" gorm:"type:BIGINT;NOT NULL;default:0"`
+	ConfirmedAt   *time.Time     `form:"confirmed_at,omitempty" json:"confirmed_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	CreatedAt     time.Time      `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time      `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (um *UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// Valid is function to control input/output data
+func (um UserMFA) Valid() error {
+	return validate.Struct(um)
+}
+
+// Validate is function to use callback to control input/output data
+func (um UserMFA) Validate(db *gorm.DB) {
+	if err := um.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// Confirmed returns true if the second factor is enforced on login
+func (um UserMFA) Confirmed() bool {
+	return um.ConfirmedAt != nil
+}
+
+// MFACode is model to contain TOTP code or recovery code to verify the second factor
+// nolint:lll
+type MFACode struct {
+	Code string `form:"code" json:"code" validate:"min=6,max=32,required" example:"123456"`
+}
+
+// Valid is function to control input/output data
+func (mc MFACode) Valid() error {
+	return validate.Struct(mc)
+}
+
+// MFAStatus is model to contain second factor state of the current user
+// nolint:lll
+type MFAStatus struct {
+	Enabled           bool       `form:"enabled" json:"enabled" validate:"omitempty"`
+	Required          bool       `form:"required" json:"required" validate:"omitempty"`
+	ConfirmedAt       *time.Time `form:"confirmed_at,omitempty" json:"confirmed_at,omitempty" validate:"omitempty"`
+	RecoveryCodesLeft int        `form:"recovery_codes_left" json:"recovery_codes_left" validate:"min=0"`
+}
+
+// Valid is function to control input/output data
+func (ms MFAStatus) Valid() error {
+	return validate.Struct(ms)
+}
+
+// MFAEnrollment is model to contain just generated TOTP secret with its provisioning URL and QR code,
+// the secret and the recovery codes are returned only once
+// nolint:lll
+type MFAEnrollment struct {
+	Secret        string   `form:"secret" json:"secret" validate:"required"`
+	URL           string   `form:"url" json:"url" validate:"required" example:"otpauth://totp/PentAGI:admin@pentagi.com?issuer=PentAGI&secret=..."`
+	QRCode        string   `form:"qr_code" json:"qr_code" validate:"required" example:"data:image/png;base64,..."`
+	RecoveryCodes []string `form:"recovery_codes" json:"recovery_codes" validate:"required"`
+}
+
+// Valid is function to control input/output data
+func (me MFAEnrollment) Valid() error {
+	return validate.Struct(me)
+}
+
+// MFARecoveryCodes is model to contain just generated recovery codes, they are returned only once
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `form:"recovery_codes" json:"recovery_codes" validate:"required"`
+}
+
+// Valid is function to control input/output data
+func (mrc MFARecoveryCodes) Valid() error {
+	return validate.Struct(mrc)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// UserSession is model to contain login session information, the session id itself is kept in the cookie only
+// nolint:lll
+type UserSession struct {
+	ID         uint64     `form:"id" json:"id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL;PRIMARY_KEY;AUTO_INCREMENT"`
+	UserID     uint64     `form:"user_id" json:"user_id" validate:"min=0,numeric" gorm:"type:BIGINT;NOT NULL"`
+	SIDHash    string     `form:"-" json:"-" validate:"len=64,hexadecimal,lowercase" gorm:"column:sid_hash;type:TEXT;NOT NULL;UNIQUE_INDEX"`
+	Method     string     `form:"method" json:"method" validate:"required" gorm:"type:TEXT;NOT NULL"`
+	RemoteAddr string     `form:"remote_addr" json:"remote_addr" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	UserAgent  string     `form:"user_agent" json:"user_agent" validate:"omitempty" gorm:"type:TEXT;NOT NULL;default:''"`
+	ExpiresAt  time.Time  `form:"expires_at" json:"expires_at" validate:"required" gorm:"type:TIMESTAMPTZ;NOT NULL"`
+	LastSeenAt *time.Time `form:"last_seen_at,omitempty" json:"last_seen_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	RevokedAt  *time.Time `form:"revoked_at,omitempty" json:"revoked_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ"`
+	CreatedAt  time.Time  `form:"created_at,omitempty" json:"created_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time  `form:"updated_at,omitempty" json:"updated_at,omitempty" validate:"omitempty" gorm:"type:TIMESTAMPTZ;default:CURRENT_TIMESTAMP"`
+	Current    bool       `form:"current" json:"current" validate:"omitempty" gorm:"-"`
+}
+
+// TableName returns the table name string to guaranty use correct table
+func (us *UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// Valid is function to control input/output data
+func (us UserSession) Valid() error {
+	return validate.Struct(us)
+}
+
+// Validate is function to use callback to control input/output data
+func (us UserSession) Validate(db *gorm.DB) {
+	if err := us.Valid(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// Active returns true if the session isn't revoked and isn't expired yet
+func (us UserSession) Active(now time.Time) bool {
+	return us.RevokedAt == nil && now.Before(us.ExpiresAt)
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/jinzhu/gorm"
 )
 
-const RoleUser = 2
+const (
+	RoleAdmin = 1
+	RoleUser  = 2
+)
 
 type UserStatus string
 
@@ -43,6 +46,7 @@ type UserType string
 const (
 	UserTypeLocal UserType = "local"
 	UserTypeOAuth UserType = "oauth"
+	UserTypeLDAP  UserType = "ldap"
 )
 
 func (s UserType) String() string {
@@ -52,7 +56,7 @@ func (s UserType) String() string {
 // Valid is function to control input/output data
 func (s UserType) Valid() error {
 	switch s {
-	case UserTypeLocal, UserTypeOAuth:
+	case UserTypeLocal, UserTypeOAuth, UserTypeLDAP:
 		return nil
 	default:
 		return fmt.Errorf("invalid UserType: %s", s)
@@ -148,11 +152,24 @@ func (sin Login) Validate(db *gorm.DB) {
 	}
 }
 
+// LoginStatus is model to contain the result of the password check, the session is created only
+// when the second factor isn't needed, otherwise the login is finished by the code verification
+// nolint:lll
+type LoginStatus struct {
+	MFARequired       bool `form:"mfa_required" json:"mfa_required" validate:"omitempty"`
+	MFAEnrollRequired bool `form:"mfa_enroll_required" json:"mfa_enroll_required" validate:"omitempty"`
+}
+
+// Valid is function to control input/output data
+func (ls LoginStatus) Valid() error {
+	return validate.Struct(ls)
+}
+
 // AuthCallback is model to contain auth data information from external OAuth application
 type AuthCallback struct {
 	Code    string `form:"code" json:"code" validate:"required"`
 	IdToken string `form:"id_token" json:"id_token" validate:"required,jwt"`
-	Scope   string `form:"scope" json:"scope" validate:"required,oauth_min_scope"`
+	Scope   string `form:"scope" json:"scope" validate:"omitempty,oauth_min_scope"`
 	State   string `form:"state" json:"state" validate:"required"`
 }
 
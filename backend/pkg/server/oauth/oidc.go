@@ -0,0 +1,188 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OAuthUser is the user information resolved from the ID token of the OpenID provider
+type OAuthUser struct {
+	Email  string
+	Name   string
+	Groups []string
+}
+
+// OAuthUserResolver is implemented by the clients which can resolve the user name and groups
+// besides the email, the groups are used to assign the role to the user
+type OAuthUserResolver interface {
+	ResolveUser(ctx context.Context, nonce string, token *oauth2.Token) (OAuthUser, error)
+}
+
+type OIDCConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim is the claim with the user groups or roles, nested claims are separated by dots,
+	// e.g. "realm_access.roles" for Keycloak realm roles
+	GroupsClaim string
+	// UsernameAsEmail allows the preferred_username claim as the email if there is no verified email,
+	// it must be enabled only for the providers where the username can't be chosen by the user
+	// (e.g. the UPN of Azure AD users)
+	UsernameAsEmail bool
+}
+
+type oidcClient struct {
+	OAuthClient
+	name            string
+	verifier        *oidc.IDTokenVerifier
+	groupsClaim     string
+	usernameAsEmail bool
+}
+
+// NewOIDCOAuthClient returns the client of the generic OpenID Connect provider,
+// the provider endpoints are resolved from the issuer discovery document
+func NewOIDCOAuthClient(ctx context.Context, cfg OIDCConfig) (OAuthClient, error) {
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not discover OpenID provider '%s': %w", cfg.IssuerURL, err)
+	}
+
+	scopes := cfg.Scopes
+	if !containsFold(scopes, oidc.ScopeOpenID) {
+		scopes = append([]string{oidc.ScopeOpenID}, scopes...)
+	}
+
+	client := &oidcClient{
+		name:            cfg.Name,
+		verifier:        provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		groupsClaim:     cfg.GroupsClaim,
+		usernameAsEmail: cfg.UsernameAsEmail,
+	}
+	client.OAuthClient = NewOAuthClient(cfg.Name, &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       scopes,
+		Endpoint:     provider.Endpoint(),
+	}, client.resolveEmail)
+
+	return client, nil
+}
+
+func (o *oidcClient) resolveEmail(ctx context.Context, nonce string, token *oauth2.Token) (string, error) {
+	user, err := o.ResolveUser(ctx, nonce, token)
+	if err != nil {
+		return "", err
+	}
+
+	return user.Email, nil
+}
+
+func (o *oidcClient) ResolveUser(ctx context.Context, nonce string, token *oauth2.Token) (OAuthUser, error) {
+	rawToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return OAuthUser{}, fmt.Errorf("id_token is not present in the token")
+	}
+
+	idToken, err := o.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return OAuthUser{}, fmt.Errorf("could not verify %s ID Token: %w", o.name, err)
+	}
+
+	if idToken.Nonce != nonce {
+		return OAuthUser{}, fmt.Errorf("nonce mismatch in %s ID Token", o.name)
+	}
+
+	if idToken.AccessTokenHash != "" {
+		if err = idToken.VerifyAccessToken(token.AccessToken); err != nil {
+			return OAuthUser{}, fmt.Errorf("failed to verify %s Access Token: %w", o.name, err)
+		}
+	}
+
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return OAuthUser{}, fmt.Errorf("failed to parse %s ID Token claims: %w", o.name, err)
+	}
+
+	return o.userFromClaims(claims)
+}
+
+// userFromClaims returns the user from the ID token claims, the email is accepted only if the provider
+// marks it as verified, otherwise anybody who can set the email in the provider could take over the user
+// with this email; the preferred username is used instead of it only if the client is configured so
+func (o *oidcClient) userFromClaims(claims map[string]any) (OAuthUser, error) {
+	var user OAuthUser
+
+	email, _ := claims["email"].(string)
+	verified, _ := claims["email_verified"].(bool)
+	username, _ := claims["preferred_username"].(string)
+
+	switch {
+	case email != "" && verified:
+		user.Email = email
+	case o.usernameAsEmail && strings.Contains(username, "@"):
+		user.Email = username
+	case email != "":
+		return user, fmt.Errorf("email not verified in %s ID Token claims", o.name)
+	default:
+		return user, fmt.Errorf("email is empty in %s ID Token claims", o.name)
+	}
+	user.Email = strings.ToLower(user.Email)
+
+	user.Name, _ = claims["name"].(string)
+	if o.groupsClaim != "" {
+		user.Groups = claimStrings(lookupClaim(claims, o.groupsClaim))
+	}
+
+	return user, nil
+}
+
+// lookupClaim returns the claim value by the dot separated path
+func lookupClaim(claims map[string]any, path string) any {
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if value, ok = object[key]; !ok {
+			return nil
+		}
+	}
+
+	return value
+}
+
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCUserFromClaims(t *testing.T) {
+	client := &oidcClient{name: "keycloak", groupsClaim: "realm_access.roles"}
+
+	user, err := client.userFromClaims(map[string]any{
+		"email":          "John.Doe@Example.com",
+		"email_verified": true,
+		"name":           "John Doe",
+		"realm_access": map[string]any{
+			"roles": []any{"pentagi-admins", "offline_access"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, OAuthUser{
+		Email:  "john.doe@example.com",
+		Name:   "John Doe",
+		Groups: []string{"pentagi-admins", "offline_access"},
+	}, user)
+
+	// the email which isn't verified by the provider is never accepted
+	_, err = client.userFromClaims(map[string]any{"email": "jdoe@example.com", "email_verified": false})
+	assert.Error(t, err)
+	_, err = client.userFromClaims(map[string]any{"email": "jdoe@example.com"})
+	assert.Error(t, err)
+
+	// the preferred username is used only if it's enabled for the provider
+	_, err = client.userFromClaims(map[string]any{"preferred_username": "jdoe@example.com"})
+	assert.Error(t, err)
+
+	client.usernameAsEmail = true
+	user, err = client.userFromClaims(map[string]any{
+		"email":              "personal@example.org",
+		"preferred_username": "jdoe@example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "jdoe@example.com", user.Email)
+	assert.Empty(t, user.Groups)
+
+	_, err = client.userFromClaims(map[string]any{"preferred_username": "jdoe"})
+	assert.Error(t, err)
+}
+
+func TestLookupClaim(t *testing.T) {
+	claims := map[string]any{
+		"groups": "admins",
+		"nested": map[string]any{"roles": []any{"a", 1, "b"}},
+	}
+
+	assert.Equal(t, []string{"admins"}, claimStrings(lookupClaim(claims, "groups")))
+	assert.Equal(t, []string{"a", "b"}, claimStrings(lookupClaim(claims, "nested.roles")))
+	assert.Nil(t, lookupClaim(claims, "groups.roles"))
+	assert.Nil(t, lookupClaim(claims, "missing"))
+}
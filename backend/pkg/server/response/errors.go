@@ -24,9 +24,13 @@ var ErrAuthInvalidAuthorizationNonce = NewHttpError(400, "Auth.InvalidAuthorizat
 var ErrAuthInvalidCredentials = NewHttpError(401, "Auth.InvalidCredentials", "invalid login or password")
 var ErrAuthInvalidUserData = NewHttpError(500, "Auth.InvalidUserData", "invalid user data")
 var ErrAuthInactiveUser = NewHttpError(403, "Auth.InactiveUser", "user is inactive")
+var ErrAuthRoleNotMapped = NewHttpError(403, "Auth.RoleNotMapped", "user groups are not mapped to any role")
+var ErrAuthUserTypeMismatch = NewHttpError(403, "Auth.UserTypeMismatch", "user is registered with another login type")
+var ErrAuthProviderMismatch = NewHttpError(403, "Auth.ProviderMismatch", "user is registered with another login provider")
 var ErrAuthExchangeTokenFail = NewHttpError(403, "Auth.ExchangeTokenFail", "error on exchanging token")
 var ErrAuthTokenExpired = NewHttpError(403, "Auth.TokenExpired", "token is expired")
 var ErrAuthVerificationTokenFail = NewHttpError(403, "Auth.VerificationTokenFail", "error on verifying token")
+var ErrAuthTooManyAttempts = NewHttpError(429, "Auth.TooManyAttempts", "too many failed login attempts, try again later")
 var ErrAuthInvalidServiceData = NewHttpError(500, "Auth.InvalidServiceData", "invalid service data")
 var ErrAuthInvalidTenantData = NewHttpError(500, "Auth.InvalidTenantData", "invalid tenant data")
 
@@ -36,6 +40,21 @@ var ErrInfoUserNotFound = NewHttpError(404, "Info.UserNotFound", "user not found
 var ErrInfoInvalidUserData = NewHttpError(500, "Info.InvalidUserData", "invalid user data")
 var ErrInfoInvalidServiceData = NewHttpError(500, "Info.InvalidServiceData", "invalid service data")
 
+// mfa
+
+var ErrMFAInvalidRequest = NewHttpError(400, "MFA.InvalidRequest", "invalid second factor request data")
+var ErrMFALoginRequired = NewHttpError(400, "MFA.LoginRequired", "no pending login waits for second factor")
+var ErrMFANotEnrolled = NewHttpError(400, "MFA.NotEnrolled", "second factor is not enrolled")
+var ErrMFAAlreadyEnabled = NewHttpError(400, "MFA.AlreadyEnabled", "second factor is already enabled")
+var ErrMFAInvalidCode = NewHttpError(401, "MFA.InvalidCode", "invalid second factor code")
+var ErrMFANotFound = NewHttpError(404, "MFA.NotFound", "second factor not found")
+
+// sessions
+
+var ErrSessionsInvalidRequest = NewHttpError(400, "Sessions.InvalidRequest", "invalid session request data")
+var ErrSessionsNotFound = NewHttpError(404, "Sessions.NotFound", "session not found")
+var ErrSessionsInvalidData = NewHttpError(500, "Sessions.InvalidData", "invalid session data")
+
 // proto
 
 var ErrProtoInvalidRequest = NewHttpError(400, "Proto.InvalidRequest", "failed to validate auth token request")
@@ -60,6 +79,8 @@ var ErrDeleteUserModelsNotFound = NewHttpError(404, "Users.DeleteUser.ModelsNotF
 var ErrRolesInvalidRequest = NewHttpError(400, "Roles.InvalidRequest", "invalid role request data")
 var ErrRolesInvalidData = NewHttpError(500, "Roles.InvalidData", "invalid role data")
 var ErrRolesNotFound = NewHttpError(404, "Roles.NotFound", "role not found")
+var ErrRolesNameExists = NewHttpError(400, "Roles.NameExists", "role with this name already exists")
+var ErrRolesProtected = NewHttpError(403, "Roles.Protected", "built-in role can't be changed")
 
 // prompts
 
@@ -132,3 +153,30 @@ var ErrSubtasksInvalidData = NewHttpError(500, "Subtasks.InvalidData", "invalid
 var ErrAssistantsInvalidRequest = NewHttpError(400, "Assistants.InvalidRequest", "invalid assistant request data")
 var ErrAssistantsNotFound = NewHttpError(404, "Assistants.NotFound", "assistant not found")
 var ErrAssistantsInvalidData = NewHttpError(500, "Assistants.InvalidData", "invalid assistant data")
+
+// api keys
+
+var ErrAPIKeysInvalidRequest = NewHttpError(400, "APIKeys.InvalidRequest", "invalid api key request data")
+var ErrAPIKeysNotFound = NewHttpError(404, "APIKeys.NotFound", "api key not found")
+var ErrAPIKeysInvalidData = NewHttpError(500, "APIKeys.InvalidData", "invalid api key data")
+
+// audit events
+
+var ErrAuditEventsInvalidRequest = NewHttpError(400, "AuditEvents.InvalidRequest", "invalid audit events request data")
+var ErrAuditEventsInvalidData = NewHttpError(500, "AuditEvents.InvalidData", "invalid audit event data")
+
+// teams
+
+var ErrTeamsInvalidRequest = NewHttpError(400, "Teams.InvalidRequest", "invalid team request data")
+var ErrTeamsInvalidData = NewHttpError(500, "Teams.InvalidData", "invalid team data")
+var ErrTeamsNotFound = NewHttpError(404, "Teams.NotFound", "team not found")
+var ErrTeamsNameExists = NewHttpError(400, "Teams.NameExists", "team with this name already exists")
+var ErrTeamsMemberNotFound = NewHttpError(404, "Teams.MemberNotFound", "team member not found")
+var ErrTeamsLastAdmin = NewHttpError(400, "Teams.LastAdmin", "team must have at least one admin")
+
+// flow shares
+
+var ErrFlowSharesInvalidRequest = NewHttpError(400, "FlowShares.InvalidRequest", "invalid flow share request data")
+var ErrFlowSharesNotFound = NewHttpError(404, "FlowShares.NotFound", "flow share link not found or expired")
+var ErrFlowSharesInvalidData = NewHttpError(500, "FlowShares.InvalidData", "invalid flow share data")
+var ErrFlowSharesTermlogsDisabled = NewHttpError(403, "FlowShares.TermlogsDisabled", "terminal logs are not shared by this link")
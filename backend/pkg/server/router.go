@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"encoding/gob"
 	"net"
 	"net/http"
@@ -13,15 +14,20 @@ import (
 	"strings"
 	"time"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/config"
 	"pentagi/pkg/controller"
 	"pentagi/pkg/database"
 	"pentagi/pkg/graph/subscriptions"
 	"pentagi/pkg/providers"
+	"pentagi/pkg/redact"
 	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/ldap"
 	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/oauth"
 	"pentagi/pkg/server/services"
+	"pentagi/pkg/webhooks"
 
 	_ "pentagi/pkg/server/docs" // swagger docs
 
@@ -68,6 +74,9 @@ func NewRouter(
 	providers providers.ProviderController,
 	controller controller.FlowController,
 	subscriptions subscriptions.SubscriptionsController,
+	webhooks webhooks.Dispatcher,
+	audit audit.Recorder,
+	metrics http.Handler,
 ) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	if cfg.Debug {
@@ -76,7 +85,7 @@ func NewRouter(
 
 	gob.Register([]string{})
 
-	authMiddleware := auth.NewAuthMiddleware(baseURL, cfg.CookieSigningSalt)
+	authMiddleware := auth.NewAuthMiddleware(baseURL, cfg.CookieSigningSalt, orm)
 	oauthClients := make(map[string]oauth.OAuthClient)
 	oauthLoginCallbackURL := "/auth/login-callback"
 
@@ -103,20 +112,78 @@ func NewRouter(
 		oauthClients[githubClient.ProviderName()] = githubClient
 	}
 
+	roleRules := make(map[string]models.RoleRules)
+	if publicURL != nil && cfg.OIDCIssuerURL != "" && cfg.OIDCClientID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		oidcClient, err := oauth.NewOIDCOAuthClient(ctx, oauth.OIDCConfig{
+			Name:            cfg.OIDCProviderName,
+			IssuerURL:       cfg.OIDCIssuerURL,
+			ClientID:        cfg.OIDCClientID,
+			ClientSecret:    cfg.OIDCClientSecret,
+			RedirectURL:     publicURL.String(),
+			Scopes:          cfg.OIDCScopes,
+			GroupsClaim:     cfg.OIDCGroupsClaim,
+			UsernameAsEmail: cfg.OIDCUsernameAsEmail,
+		})
+		cancel()
+		if err != nil {
+			logrus.WithError(err).Error("failed to initialize OIDC provider")
+		} else if rules, err := models.ParseRoleRules(cfg.OIDCRoleMapping); err != nil {
+			logrus.WithError(err).Error("failed to parse OIDC role mapping")
+		} else {
+			oauthClients[oidcClient.ProviderName()] = oidcClient
+			roleRules[oidcClient.ProviderName()] = rules
+		}
+	}
+
+	var ldapAuthenticator ldap.Authenticator
+	if cfg.LDAPURL != "" {
+		if rules, err := models.ParseRoleRules(cfg.LDAPRoleMapping); err != nil {
+			logrus.WithError(err).Error("failed to parse LDAP role mapping")
+		} else {
+			ldapAuthenticator = ldap.NewAuthenticator(ldap.Config{
+				URL:            cfg.LDAPURL,
+				StartTLS:       cfg.LDAPStartTLS,
+				SkipVerify:     cfg.LDAPSkipVerify,
+				BindDN:         cfg.LDAPBindDN,
+				BindPassword:   cfg.LDAPBindPassword,
+				BaseDN:         cfg.LDAPBaseDN,
+				UserFilter:     cfg.LDAPUserFilter,
+				MailAttribute:  cfg.LDAPMailAttribute,
+				NameAttribute:  cfg.LDAPNameAttribute,
+				GroupAttribute: cfg.LDAPGroupAttribute,
+			})
+			roleRules[ldap.ProviderName] = rules
+		}
+	}
+
+	loginThrottle := auth.NewLoginThrottle(auth.LoginThrottleConfig{
+		AccountMaxAttempts: cfg.LoginMaxAttempts,
+		AddressMaxAttempts: cfg.LoginIPMaxAttempts,
+		LockoutBase:        time.Duration(cfg.LoginLockoutBase) * time.Second,
+		LockoutMax:         time.Duration(cfg.LoginLockoutMax) * time.Second,
+		Window:             time.Duration(cfg.LoginFailureWindow) * time.Second,
+	}, orm)
+
 	// services
 	authService := services.NewAuthService(
 		services.AuthServiceConfig{
 			BaseURL:          baseURL,
 			LoginCallbackURL: oauthLoginCallbackURL,
 			SessionTimeout:   4 * 60 * 60, // 4 hours
+			RoleRules:        roleRules,
 		},
 		orm,
 		oauthClients,
+		ldapAuthenticator,
+		loginThrottle,
+		audit,
 	)
-	userService := services.NewUserService(orm)
-	roleService := services.NewRoleService(orm)
+	userService := services.NewUserService(orm, audit)
+	roleService := services.NewRoleService(orm, userService, audit)
+	teamService := services.NewTeamService(orm, audit)
 	providerService := services.NewProviderService(providers)
-	flowService := services.NewFlowService(orm, providers, controller)
+	flowService := services.NewFlowService(orm, providers, controller, audit)
 	taskService := services.NewTaskService(orm)
 	subtaskService := services.NewSubtaskService(orm)
 	containerService := services.NewContainerService(orm)
@@ -128,9 +195,15 @@ func NewRouter(
 	vecstorelogService := services.NewVecstorelogService(orm)
 	termlogService := services.NewTermlogService(orm)
 	screenshotService := services.NewScreenshotService(orm, cfg.DataDir)
-	promptService := services.NewPromptService(orm)
+	promptService := services.NewPromptService(orm, audit)
+	apiKeyService := services.NewAPIKeyService(orm, audit)
+	mfaService := services.NewMFAService(orm, loginThrottle, audit)
+	sessionService := services.NewSessionService(orm, audit)
+	auditEventService := services.NewAuditEventService(orm)
+	redactor := redact.New(cfg.SecretValues()...)
+	flowShareService := services.NewFlowShareService(orm, cfg.DataDir, cfg.CookieSigningSalt, redactor)
 	graphqlService := services.NewGraphqlService(
-		db, cfg, baseURL, cfg.CorsOrigins, providers, controller, subscriptions,
+		db, cfg, baseURL, cfg.CorsOrigins, providers, controller, subscriptions, webhooks, redactor, audit,
 	)
 
 	router := gin.Default()
@@ -166,6 +239,18 @@ func NewRouter(
 	changePasswordGroup.Use(localUserRequired())
 	changePasswordGroup.PUT("/password", userService.ChangePasswordCurrentUser)
 
+	// Second factor of the local user is managed by the user itself in the cookie session
+	mfaGroup := api.Group("/user/mfa")
+	mfaGroup.Use(authMiddleware.AuthRequired)
+	mfaGroup.Use(localUserRequired())
+	{
+		mfaGroup.GET("", mfaService.GetMFA)
+		mfaGroup.POST("", mfaService.EnrollMFA)
+		mfaGroup.POST("/confirm", mfaService.ConfirmMFA)
+		mfaGroup.POST("/disable", mfaService.DisableMFA)
+		mfaGroup.POST("/recovery_codes", mfaService.RegenerateRecoveryCodes)
+	}
+
 	publicGroup := api.Group("/")
 	publicGroup.Use(authMiddleware.TryAuth)
 	{
@@ -180,6 +265,8 @@ func NewRouter(
 		authGroup := publicGroup.Group("/auth")
 		{
 			authGroup.POST("/login", authService.AuthLogin)
+			authGroup.POST("/login/mfa", authService.AuthLoginMFA)
+			authGroup.POST("/login/mfa/enroll", authService.AuthLoginMFAEnroll)
 			authGroup.GET("/logout", authService.AuthLogout)
 			authGroup.GET("/authorize", authService.AuthAuthorize)
 			authGroup.GET("/login-callback", authService.AuthLoginGetCallback)
@@ -188,13 +275,23 @@ func NewRouter(
 		}
 	}
 
+	// Prometheus scrape endpoint is out of the API base path and it's registered only if it's enabled
+	if metrics != nil {
+		router.GET("/metrics", metricsTokenRequired(cfg.MetricsToken), gin.WrapH(metrics))
+	}
+
+	// Read-only flow access by the share link token, the token replaces the authentication
+	setSharedGroup(api, flowShareService, graphqlService)
+
 	privateGroup := api.Group("/")
 	privateGroup.Use(authMiddleware.AuthRequired)
 	{
 		setGraphqlGroup(privateGroup, graphqlService)
 
 		setRolesGroup(privateGroup, roleService)
-		setUsersGroup(privateGroup, userService)
+		setUsersGroup(privateGroup, userService, mfaService)
+		setSessionsGroup(privateGroup, sessionService)
+		setTeamsGroup(privateGroup, teamService)
 
 		setProvidersGroup(privateGroup, providerService)
 		setFlowsGroup(privateGroup, flowService)
@@ -210,6 +307,8 @@ func NewRouter(
 		setVecstorelogsGroup(privateGroup, vecstorelogService)
 		setScreenshotsGroup(privateGroup, screenshotService)
 		setPromptsGroup(privateGroup, promptService)
+		setAPIKeysGroup(privateGroup, apiKeyService)
+		setAuditEventsGroup(privateGroup, auditEventService)
 	}
 
 	if cfg.StaticURL != nil && cfg.StaticURL.Scheme != "" && cfg.StaticURL.Host != "" {
@@ -322,6 +421,7 @@ func setFlowsGroup(parent *gin.RouterGroup, svc *services.FlowService) {
 	flowEditGroup := parent.Group("/flows")
 	{
 		flowEditGroup.PUT("/:flowID", svc.PatchFlow)
+		flowEditGroup.PUT("/:flowID/team", svc.ShareFlow)
 	}
 
 	flowsViewGroup := parent.Group("/flows")
@@ -468,15 +568,102 @@ func setPromptsGroup(parent *gin.RouterGroup, svc *services.PromptService) {
 	}
 }
 
+func setAPIKeysGroup(parent *gin.RouterGroup, svc *services.APIKeyService) {
+	apiKeysCreateGroup := parent.Group("/api_keys")
+	{
+		apiKeysCreateGroup.POST("/", svc.CreateAPIKey)
+	}
+
+	apiKeysDeleteGroup := parent.Group("/api_keys")
+	{
+		apiKeysDeleteGroup.DELETE("/:keyID", svc.RevokeAPIKey)
+	}
+
+	apiKeysViewGroup := parent.Group("/api_keys")
+	{
+		apiKeysViewGroup.GET("/", svc.GetAPIKeys)
+		apiKeysViewGroup.GET("/:keyID", svc.GetAPIKey)
+	}
+}
+
+func setSessionsGroup(parent *gin.RouterGroup, svc *services.SessionService) {
+	sessionsDeleteGroup := parent.Group("/sessions")
+	{
+		sessionsDeleteGroup.DELETE("/:sessionID", svc.RevokeSession)
+	}
+
+	sessionsViewGroup := parent.Group("/sessions")
+	{
+		sessionsViewGroup.GET("/", svc.GetSessions)
+	}
+}
+
+func setAuditEventsGroup(parent *gin.RouterGroup, svc *services.AuditEventService) {
+	auditEventsViewGroup := parent.Group("/audit_events")
+	{
+		auditEventsViewGroup.GET("/", svc.GetAuditEvents)
+	}
+}
+
+func setSharedGroup(parent *gin.RouterGroup, svc *services.FlowShareService, gql *services.GraphqlService) {
+	sharedGroup := parent.Group("/shared/:token")
+	{
+		sharedGroup.Any("/graphql", gql.ServeSharedGraphql)
+		sharedGroup.GET("/flow", svc.GetSharedFlow)
+		sharedGroup.GET("/tasks/", svc.GetSharedTasks)
+		sharedGroup.GET("/msglogs/", svc.GetSharedMsglogs)
+		sharedGroup.GET("/termlogs/", svc.GetSharedTermlogs)
+		sharedGroup.GET("/screenshots/", svc.GetSharedScreenshots)
+		sharedGroup.GET("/screenshots/:screenshotID/file", svc.GetSharedScreenshotFile)
+	}
+}
+
 func setRolesGroup(parent *gin.RouterGroup, svc *services.RoleService) {
+	rolesCreateGroup := parent.Group("/roles")
+	{
+		rolesCreateGroup.POST("/", svc.CreateRole)
+		rolesCreateGroup.POST("/:roleID/clone", svc.CloneRole)
+	}
+
+	rolesEditGroup := parent.Group("/roles")
+	{
+		rolesEditGroup.PUT("/:roleID", svc.PatchRole)
+	}
+
 	rolesViewGroup := parent.Group("/roles")
 	{
 		rolesViewGroup.GET("/", svc.GetRoles)
+		rolesViewGroup.GET("/privileges", svc.GetPrivileges)
 		rolesViewGroup.GET("/:roleID", svc.GetRole)
 	}
 }
 
-func setUsersGroup(parent *gin.RouterGroup, svc *services.UserService) {
+func setTeamsGroup(parent *gin.RouterGroup, svc *services.TeamService) {
+	teamsCreateGroup := parent.Group("/teams")
+	{
+		teamsCreateGroup.POST("/", svc.CreateTeam)
+	}
+
+	teamsDeleteGroup := parent.Group("/teams")
+	{
+		teamsDeleteGroup.DELETE("/:teamID", svc.DeleteTeam)
+	}
+
+	teamsEditGroup := parent.Group("/teams")
+	{
+		teamsEditGroup.PUT("/:teamID", svc.PatchTeam)
+		teamsEditGroup.PUT("/:teamID/members/:userID", svc.SetTeamMember)
+		teamsEditGroup.DELETE("/:teamID/members/:userID", svc.DeleteTeamMember)
+	}
+
+	teamsViewGroup := parent.Group("/teams")
+	{
+		teamsViewGroup.GET("/", svc.GetTeams)
+		teamsViewGroup.GET("/:teamID", svc.GetTeam)
+	}
+}
+
+func setUsersGroup(parent *gin.RouterGroup, svc *services.UserService, mfa *services.MFAService) {
 	usersCreateGroup := parent.Group("/users")
 	{
 		usersCreateGroup.POST("/", svc.CreateUser)
@@ -485,6 +672,7 @@ func setUsersGroup(parent *gin.RouterGroup, svc *services.UserService) {
 	usersDeleteGroup := parent.Group("/users")
 	{
 		usersDeleteGroup.DELETE("/:hash", svc.DeleteUser)
+		usersDeleteGroup.DELETE("/:hash/mfa", mfa.ResetUserMFA)
 	}
 
 	usersEditGroup := parent.Group("/users")
@@ -81,7 +81,7 @@ func (s *AgentlogService) GetAgentlogs(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.user_id = ?", uid)
+				Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -164,7 +164,7 @@ func (s *AgentlogService) GetFlowAgentlogs(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/rdb"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type apiKeys struct {
+	APIKeys []models.APIKey `json:"api_keys"`
+	Total   uint64          `json:"total"`
+}
+
+var apiKeysSQLMappers = map[string]interface{}{
+	"id":           "{{table}}.id",
+	"user_id":      "{{table}}.user_id",
+	"name":         "{{table}}.name",
+	"key_prefix":   "{{table}}.key_prefix",
+	"expires_at":   "{{table}}.expires_at",
+	"last_used_at": "{{table}}.last_used_at",
+	"revoked_at":   "{{table}}.revoked_at",
+	"created_at":   "{{table}}.created_at",
+	"data":         "({{table}}.name || ' ' || {{table}}.key_prefix)",
+}
+
+type APIKeyService struct {
+	db    *gorm.DB
+	audit audit.Recorder
+}
+
+func NewAPIKeyService(db *gorm.DB, audit audit.Recorder) *APIKeyService {
+	return &APIKeyService{
+		db:    db,
+		audit: audit,
+	}
+}
+
+// GetAPIKeys is a function to return personal API keys list
+// @Summary Retrieve personal API keys list
+// @Tags APIKeys
+// @Produce json
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=apiKeys} "api keys list received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 403 {object} response.errorResp "getting api keys not permitted"
+// @Failure 500 {object} response.errorResp "internal error on getting api keys"
+// @Router /api_keys/ [get]
+func (s *APIKeyService) GetAPIKeys(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  apiKeys
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	}
+
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	var scope func(db *gorm.DB) *gorm.DB
+	if slices.Contains(privs, "api_keys.admin") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db
+		}
+	} else if slices.Contains(privs, "api_keys.view") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ?", uid)
+		}
+	} else {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	query.Init("api_keys", apiKeysSQLMappers)
+
+	if query.Group != "" {
+		logger.FromContext(c).Errorf("error grouping api keys: not allowed")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.APIKeys, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding api keys")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := 0; i < len(resp.APIKeys); i++ {
+		if err = resp.APIKeys[i].Valid(); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error validating api key data '%d'", resp.APIKeys[i].ID)
+			response.Error(c, response.ErrAPIKeysInvalidData, err)
+			return
+		}
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetAPIKey is a function to return personal API key by id
+// @Summary Retrieve personal API key by id
+// @Tags APIKeys
+// @Produce json
+// @Param keyID path int true "api key id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.APIKey} "api key received successful"
+// @Failure 400 {object} response.errorResp "invalid api key request data"
+// @Failure 403 {object} response.errorResp "getting api key not permitted"
+// @Failure 404 {object} response.errorResp "api key not found"
+// @Failure 500 {object} response.errorResp "internal error on getting api key"
+// @Router /api_keys/{keyID} [get]
+func (s *APIKeyService) GetAPIKey(c *gin.Context) {
+	var (
+		err   error
+		keyID uint64
+		resp  models.APIKey
+	)
+
+	if keyID, err = strconv.ParseUint(c.Param("keyID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing api key id")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	}
+
+	scope, ok := apiKeyScope(c, keyID, "api_keys.view")
+	if !ok {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = s.db.Model(&resp).Scopes(scope).Take(&resp).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error on getting api key by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrAPIKeysNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CreateAPIKey is a function to create new personal API key
+// @Summary Create new personal API key, the key is returned only in this response
+// @Tags APIKeys
+// @Accept json
+// @Produce json
+// @Param json body models.CreateAPIKey true "api key model to create"
+// @Success 201 {object} response.successResp{data=models.APIKeyToken} "api key created successful"
+// @Failure 400 {object} response.errorResp "invalid api key request data"
+// @Failure 403 {object} response.errorResp "creating api key not permitted"
+// @Failure 500 {object} response.errorResp "internal error on creating api key"
+// @Router /api_keys/ [post]
+func (s *APIKeyService) CreateAPIKey(c *gin.Context) {
+	var (
+		err  error
+		req  models.CreateAPIKey
+		resp models.APIKeyToken
+	)
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating api key JSON")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	}
+
+	privs := c.GetStringSlice("prm")
+	if !slices.Contains(privs, "api_keys.create") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = auth.ValidateAPIKeyPrivileges(privs, req.Privileges); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating api key privileges")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	} else if err = auth.ValidateAPIKeyExpiry(req.ExpiresAt); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating api key expiry")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error generating api key")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	resp.Key = key
+	resp.APIKey = models.APIKey{
+		UserID:     c.GetUint64("uid"),
+		Name:       req.Name,
+		KeyPrefix:  prefix,
+		KeyHash:    hash,
+		Privileges: req.Privileges,
+		ExpiresAt:  req.ExpiresAt,
+	}
+
+	if err = s.db.Create(&resp.APIKey).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error creating api key")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionAPIKeyCreate,
+		TargetType: audit.TargetAPIKey,
+		TargetID:   int64(resp.ID),
+		TargetName: resp.Name,
+		After:      resp.APIKey,
+	})
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// RevokeAPIKey is a function to revoke personal API key by id
+// @Summary Revoke personal API key by id, the revoked key is kept in the list
+// @Tags APIKeys
+// @Produce json
+// @Param keyID path int true "api key id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.APIKey} "api key revoked successful"
+// @Failure 400 {object} response.errorResp "invalid api key request data"
+// @Failure 403 {object} response.errorResp "revoking api key not permitted"
+// @Failure 404 {object} response.errorResp "api key not found"
+// @Failure 500 {object} response.errorResp "internal error on revoking api key"
+// @Router /api_keys/{keyID} [delete]
+func (s *APIKeyService) RevokeAPIKey(c *gin.Context) {
+	var (
+		err   error
+		keyID uint64
+		resp  models.APIKey
+	)
+
+	if keyID, err = strconv.ParseUint(c.Param("keyID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing api key id")
+		response.Error(c, response.ErrAPIKeysInvalidRequest, err)
+		return
+	}
+
+	scope, ok := apiKeyScope(c, keyID, "api_keys.delete")
+	if !ok {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = s.db.Model(&resp).Scopes(scope).Take(&resp).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error on getting api key by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrAPIKeysNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	if resp.RevokedAt == nil {
+		err = s.db.Model(&resp).UpdateColumn("revoked_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error revoking api key by id '%d'", keyID)
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+
+		if err = s.db.Scopes(scope).Take(&resp).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error finding revoked api key by id '%d'", keyID)
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+
+		s.audit.Record(auditContext(c), audit.Event{
+			Action:     audit.ActionAPIKeyRevoke,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   int64(resp.ID),
+			TargetName: resp.Name,
+		})
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// apiKeyScope returns the scope to find the API key by id, admin can access keys of all users
+func apiKeyScope(c *gin.Context, keyID uint64, perm string) (func(db *gorm.DB) *gorm.DB, bool) {
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	if slices.Contains(privs, "api_keys.admin") {
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ?", keyID)
+		}, true
+	} else if slices.Contains(privs, perm) {
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ? AND user_id = ?", keyID, uid)
+		}, true
+	}
+
+	return nil, false
+}
@@ -80,7 +80,7 @@ func (s *AssistantlogService) GetAssistantlogs(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.user_id = ?", uid)
+				Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -163,7 +163,7 @@ func (s *AssistantlogService) GetFlowAssistantlogs(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
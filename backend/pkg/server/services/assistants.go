@@ -94,7 +94,7 @@ func (s *AssistantService) GetFlowAssistants(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -177,7 +177,7 @@ func (s *AssistantService) GetFlowAssistant(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.
 				Joins("INNER JOIN flows f ON f.id = flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -336,7 +336,7 @@ func (s *AssistantService) PatchAssistant(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.Where("assistants.id = ? AND assistants.flow_id = ?", assistantID, flowID).
 				Joins("INNER JOIN flows f ON f.id = assistants.flow_id").
-				Where("f.user_id = ?", uid)
+				Scopes(teamAccessScope("f", uid, models.TeamRoleOperator))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -449,7 +449,7 @@ func (s *AssistantService) DeleteAssistant(c *gin.Context) {
 		scope = func(db *gorm.DB) *gorm.DB {
 			return db.Where("assistants.id = ? AND assistants.flow_id = ?", assistantID, flowID).
 				Joins("INNER JOIN flows f ON f.id = assistants.flow_id").
-				Where("f.user_id = ?", uid)
+				Scopes(teamAccessScope("f", uid, models.TeamRoleAdmin))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/rdb"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type auditEvents struct {
+	AuditEvents []models.AuditEvent `json:"audit_events"`
+	Total       uint64              `json:"total"`
+}
+
+var auditEventsSQLMappers = map[string]interface{}{
+	"id":          "{{table}}.id",
+	"user_id":     "{{table}}.user_id",
+	"user_mail":   "{{table}}.user_mail",
+	"action":      "{{table}}.action",
+	"target_type": "{{table}}.target_type",
+	"target_id":   "{{table}}.target_id",
+	"target_name": "{{table}}.target_name",
+	"remote_addr": "{{table}}.remote_addr",
+	"user_agent":  "{{table}}.user_agent",
+	"created_at":  "{{table}}.created_at",
+	"data":        "({{table}}.user_mail || ' ' || {{table}}.action || ' ' || {{table}}.target_name)",
+}
+
+type AuditEventService struct {
+	db *gorm.DB
+}
+
+func NewAuditEventService(db *gorm.DB) *AuditEventService {
+	return &AuditEventService{
+		db: db,
+	}
+}
+
+// GetAuditEvents is a function to return audit events list
+// @Summary Retrieve audit events list
+// @Tags AuditEvents
+// @Produce json
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=auditEvents} "audit events list received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 403 {object} response.errorResp "getting audit events not permitted"
+// @Failure 500 {object} response.errorResp "internal error on getting audit events"
+// @Router /audit_events/ [get]
+func (s *AuditEventService) GetAuditEvents(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  auditEvents
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrAuditEventsInvalidRequest, err)
+		return
+	}
+
+	privs := c.GetStringSlice("prm")
+	if !slices.Contains(privs, "audit_events.view") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	query.Init("audit_events", auditEventsSQLMappers)
+
+	if query.Group != "" {
+		logger.FromContext(c).Errorf("error grouping audit events: not allowed")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.AuditEvents, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding audit events")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := 0; i < len(resp.AuditEvents); i++ {
+		if err = resp.AuditEvents[i].Valid(); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error validating audit event data '%d'", resp.AuditEvents[i].ID)
+			response.Error(c, response.ErrAuditEventsInvalidData, err)
+			return
+		}
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// auditContext returns the request context with the actor of the audit events,
+// the user is unknown before the authentication, e.g. on the failed login
+func auditContext(c *gin.Context) context.Context {
+	return audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:     int64(c.GetUint64("uid")),
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
@@ -16,6 +17,9 @@ import (
 	"strings"
 	"time"
 
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/ldap"
 	"pentagi/pkg/server/logger"
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/oauth"
@@ -35,25 +39,35 @@ const (
 	authStateCookieName = "state"
 	authNonceCookieName = "nonce"
 	authStateRequestTTL = 5 * time.Minute
+	authMFALoginTTL     = 5 * time.Minute
 )
 
 type AuthServiceConfig struct {
 	BaseURL          string
 	LoginCallbackURL string
 	SessionTimeout   int // in seconds
+	// RoleRules assign the roles to the users of the external providers by the provider name,
+	// the users of the providers without the rules get the default user role
+	RoleRules map[string]models.RoleRules
 }
 
 type AuthService struct {
-	cfg   AuthServiceConfig
-	db    *gorm.DB
-	key   []byte
-	oauth map[string]oauth.OAuthClient
+	cfg      AuthServiceConfig
+	db       *gorm.DB
+	key      []byte
+	oauth    map[string]oauth.OAuthClient
+	ldap     ldap.Authenticator
+	throttle *auth.LoginThrottle
+	audit    audit.Recorder
 }
 
 func NewAuthService(
 	cfg AuthServiceConfig,
 	db *gorm.DB,
 	oauth map[string]oauth.OAuthClient,
+	ldap ldap.Authenticator,
+	throttle *auth.LoginThrottle,
+	audit audit.Recorder,
 ) *AuthService {
 	var count int
 	err := db.Model(&models.User{}).Where("type = 'local'").Count(&count).Error
@@ -67,23 +81,28 @@ func NewAuthService(
 	}
 
 	return &AuthService{
-		cfg:   cfg,
-		db:    db,
-		key:   key,
-		oauth: oauth,
+		cfg:      cfg,
+		db:       db,
+		key:      key,
+		oauth:    oauth,
+		ldap:     ldap,
+		throttle: throttle,
+		audit:    audit,
 	}
 }
 
-// AuthLogin is function to login user in the system
+// AuthLogin is function to login user in the system, the local user with the second factor
+// gets the pending login which is finished by AuthLoginMFA
 // @Summary Login user into system
 // @Tags Public
 // @Accept json
 // @Produce json
 // @Param json body models.Login true "Login form JSON data"
-// @Success 200 {object} response.successResp "login successful"
+// @Success 200 {object} response.successResp{data=models.LoginStatus} "login successful or second factor required"
 // @Failure 400 {object} response.errorResp "invalid login data"
 // @Failure 401 {object} response.errorResp "invalid login or password"
 // @Failure 403 {object} response.errorResp "login not permitted"
+// @Failure 429 {object} response.errorResp "too many failed login attempts"
 // @Failure 500 {object} response.errorResp "internal error on login"
 // @Router /auth/login [post]
 func (s *AuthService) AuthLogin(c *gin.Context) {
@@ -97,9 +116,18 @@ func (s *AuthService) AuthLogin(c *gin.Context) {
 		return
 	}
 
+	if !s.checkLoginThrottle(c, data.Mail) {
+		return
+	}
+
 	var user models.UserPassword
 	if err := s.db.Take(&user, "mail = ? AND password IS NOT NULL", data.Mail).Error; err != nil {
+		if s.ldap != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+			s.authLDAPLogin(c, data)
+			return
+		}
 		logrus.WithError(err).Errorf("error getting user by mail '%s'", data.Mail)
+		s.loginFailed(c, data.Mail, "local")
 		response.Error(c, response.ErrAuthInvalidCredentials, err)
 		return
 	} else if err = user.Valid(); err != nil {
@@ -114,6 +142,7 @@ func (s *AuthService) AuthLogin(c *gin.Context) {
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(data.Password)); err != nil {
 		logger.FromContext(c).Errorf("error matching user input password")
+		s.loginFailed(c, data.Mail, "local")
 		response.Error(c, response.ErrAuthInvalidCredentials, err)
 		return
 	}
@@ -124,6 +153,127 @@ func (s *AuthService) AuthLogin(c *gin.Context) {
 		return
 	}
 
+	status, err := s.loginMFAStatus(user.User)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting second factor state '%s'", user.Hash)
+		response.Error(c, response.ErrAuthInvalidServiceData, err)
+		return
+	}
+
+	// the throttle isn't reset until the second factor is verified, so the codes can't be guessed
+	// by repeating the login with the known password
+	if status.MFARequired || status.MFAEnrollRequired {
+		if err := s.savePendingMFASession(c, user.User); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error saving session")
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+
+		logger.FromContext(c).Infof("user passed password check and must verify second factor for '%s'", data.Mail)
+		response.Success(c, http.StatusOK, status)
+		return
+	}
+
+	s.finishLocalLogin(c, user.User)
+}
+
+// AuthLoginMFA is function to finish the pending login by the second factor code
+// @Summary Verify second factor code to finish login
+// @Tags Public
+// @Accept json
+// @Produce json
+// @Param json body models.MFACode true "TOTP code or recovery code"
+// @Success 200 {object} response.successResp{data=models.LoginStatus} "login successful"
+// @Failure 400 {object} response.errorResp "invalid code data or no pending login"
+// @Failure 401 {object} response.errorResp "invalid code"
+// @Failure 403 {object} response.errorResp "login not permitted"
+// @Failure 429 {object} response.errorResp "too many failed login attempts"
+// @Failure 500 {object} response.errorResp "internal error on login"
+// @Router /auth/login/mfa [post]
+func (s *AuthService) AuthLoginMFA(c *gin.Context) {
+	var data models.MFACode
+	if err := c.ShouldBindJSON(&data); err != nil || data.Valid() != nil {
+		if err == nil {
+			err = data.Valid()
+		}
+		logger.FromContext(c).WithError(err).Errorf("error validating request data")
+		response.Error(c, response.ErrAuthInvalidLoginRequest, err)
+		return
+	}
+
+	user, ok := s.pendingMFAUser(c)
+	if !ok || !s.checkLoginThrottle(c, user.Mail) {
+		return
+	}
+
+	var mfa models.UserMFA
+	if err := s.db.Take(&mfa, "user_id = ?", user.ID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting second factor of user '%s'", user.Hash)
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrMFANotEnrolled, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	ok, err := verifyUserMFACode(s.db, &mfa, data.Code)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error verifying second factor of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return
+	} else if !ok {
+		logger.FromContext(c).Errorf("error matching second factor code of user '%s'", user.Hash)
+		s.loginFailed(c, user.Mail, "mfa")
+		response.Error(c, response.ErrMFAInvalidCode, nil)
+		return
+	}
+
+	// the enrollment which is required by the role is confirmed by the first valid code
+	if !mfa.Confirmed() {
+		if err := confirmUserMFA(s.db, &mfa); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error confirming second factor of user '%s'", user.Hash)
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+		s.auditMFA(c, audit.ActionMFAEnable, user)
+	}
+
+	s.finishLocalLogin(c, user)
+}
+
+// AuthLoginMFAEnroll is function to enroll the second factor which is required by the user role
+// before the pending login is finished
+// @Summary Enroll second factor required by the role to finish login
+// @Tags Public
+// @Produce json
+// @Success 200 {object} response.successResp{data=models.MFAEnrollment} "second factor enrollment created successful"
+// @Failure 400 {object} response.errorResp "no pending login or second factor is already enabled"
+// @Failure 403 {object} response.errorResp "login not permitted"
+// @Failure 500 {object} response.errorResp "internal error on second factor enrollment"
+// @Router /auth/login/mfa/enroll [post]
+func (s *AuthService) AuthLoginMFAEnroll(c *gin.Context) {
+	user, ok := s.pendingMFAUser(c)
+	if !ok {
+		return
+	}
+
+	resp, err := enrollUserMFA(s.db, user)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error enrolling second factor of user '%s'", user.Hash)
+		if errors.Is(err, errMFAAlreadyEnabled) {
+			response.Error(c, response.ErrMFAAlreadyEnabled, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// finishLocalLogin creates the session of the local user who passed all authentication factors
+func (s *AuthService) finishLocalLogin(c *gin.Context, user models.User) {
 	var privs []string
 	err := s.db.Table("privileges").
 		Where("role_id = ?", user.RoleID).
@@ -143,27 +293,16 @@ func (s *AuthService) AuthLogin(c *gin.Context) {
 
 	expires := s.cfg.SessionTimeout
 	session := sessions.Default(c)
-	session.Set("uid", user.ID)
-	session.Set("uhash", user.Hash)
-	session.Set("rid", user.RoleID)
-	session.Set("tid", models.UserTypeLocal.String())
-	session.Set("prm", privs)
-	session.Set("gtm", time.Now().Unix())
-	session.Set("exp", time.Now().Add(time.Duration(expires)*time.Second).Unix())
-	session.Set("uuid", uuid)
-	session.Set("uname", user.Name)
-	session.Options(sessions.Options{
-		HttpOnly: true,
-		Secure:   c.Request.TLS != nil,
-		Path:     s.cfg.BaseURL,
-		MaxAge:   int(expires),
-	})
-	if err := session.Save(); err != nil {
+	if err := s.saveUserSession(c, user, privs, uuid, "local"); err != nil {
 		logger.FromContext(c).WithError(err).Errorf("error saving session")
 		response.Error(c, response.ErrInternal, err)
 		return
 	}
 
+	if err := s.throttle.Reset(user.Mail); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error resetting login throttle of user '%s'", user.Hash)
+	}
+
 	logger.FromContext(c).
 		WithFields(logrus.Fields{
 			"age":   expires,
@@ -175,9 +314,10 @@ func (s *AuthService) AuthLogin(c *gin.Context) {
 			"exp":   session.Get("exp"),
 			"prm":   session.Get("prm"),
 		}).
-		Infof("user made successful local login for '%s'", data.Mail)
+		Infof("user made successful local login for '%s'", user.Mail)
+	s.auditLogin(c, audit.ActionLogin, user.ID, user.Mail, "local")
 
-	response.Success(c, http.StatusOK, struct{}{})
+	response.Success(c, http.StatusOK, models.LoginStatus{})
 }
 
 func (s *AuthService) refreshCookie(c *gin.Context, resp *info, privs []string) error {
@@ -203,6 +343,16 @@ func (s *AuthService) refreshCookie(c *gin.Context, resp *info, privs []string)
 		return err
 	}
 
+	if sid, ok := session.Get("sid").(string); ok {
+		err := s.db.Model(&models.UserSession{}).
+			Where("sid_hash = ?", auth.HashSessionID(sid)).
+			UpdateColumn("expires_at", time.Unix(session.Get("exp").(int64), 0)).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error extending session")
+			return err
+		}
+	}
+
 	logger.FromContext(c).
 		WithFields(logrus.Fields{
 			"age":   expires,
@@ -224,7 +374,7 @@ func (s *AuthService) refreshCookie(c *gin.Context, resp *info, privs []string)
 // @Tags Public
 // @Produce json
 // @Param return_uri query string false "URI to redirect user there after login" default(/)
-// @Param provider query string false "OAuth provider name (google, github, etc.)" default(google) enums:"google,github"
+// @Param provider query string false "OAuth provider name (google, github or OIDC_PROVIDER_NAME)" default(google)
 // @Success 307 "redirect to SSO login page"
 // @Failure 400 {object} response.errorResp "invalid autorizarion query"
 // @Failure 403 {object} response.errorResp "authorize not permitted"
@@ -419,18 +569,15 @@ func (s *AuthService) AuthLogout(c *gin.Context) {
 			"prm":   session.Get("prm"),
 		}).
 		Info("user made successful logout")
+	if uid, ok := session.Get("uid").(uint64); ok {
+		s.auditLogin(c, audit.ActionLogout, uid, "", "session")
+	}
 
 	s.resetSession(c)
 	http.Redirect(c.Writer, c.Request, returnURI, http.StatusTemporaryRedirect)
 }
 
 func (s *AuthService) authLoginCallback(c *gin.Context, stateData map[string]string, code string) {
-	var (
-		privs []string
-		role  models.Role
-		user  models.User
-	)
-
 	provider := stateData["provider"]
 	oauthClient, ok := s.oauth[provider]
 	if !ok {
@@ -461,87 +608,446 @@ func (s *AuthService) authLoginCallback(c *gin.Context, stateData map[string]str
 		return
 	}
 
-	email, err := oauthClient.ResolveEmail(ctx, nonce.Value, oauth2Token)
+	var oauthUser oauth.OAuthUser
+	if resolver, ok := oauthClient.(oauth.OAuthUserResolver); ok {
+		oauthUser, err = resolver.ResolveUser(ctx, nonce.Value, oauth2Token)
+	} else {
+		oauthUser.Email, err = oauthClient.ResolveEmail(ctx, nonce.Value, oauth2Token)
+	}
 	if err != nil {
 		logger.FromContext(c).WithError(err).Errorf("failed to resolve email")
 		response.Error(c, response.ErrAuthInvalidUserData, err)
 		return
 	}
 
+	user, privs, ok := s.externalUser(c, models.UserTypeOAuth, provider, oauthUser)
+	if !ok {
+		return
+	}
+
+	expires := s.cfg.SessionTimeout
+	session := sessions.Default(c)
+	if err := s.saveUserSession(c, user, privs, user.Mail, provider); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error saving session")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	// delete temporary cookies
+	s.setCallbackCookie(c.Writer, c.Request, authStateCookieName, "", 0)
+	s.setCallbackCookie(c.Writer, c.Request, authNonceCookieName, "", 0)
+
+	logger.FromContext(c).
+		WithFields(logrus.Fields{
+			"age":   expires,
+			"uid":   user.ID,
+			"uhash": user.Hash,
+			"rid":   user.RoleID,
+			"tid":   user.Type,
+			"gtm":   session.Get("gtm"),
+			"exp":   session.Get("exp"),
+			"prm":   session.Get("prm"),
+		}).
+		Infof("user made successful SSO login for '%s' '%s'", user.Mail, user.Name)
+	s.auditLogin(c, audit.ActionLogin, user.ID, user.Mail, provider)
+
+	if returnURI := stateData["return_uri"]; returnURI == "" {
+		response.Success(c, http.StatusOK, nil)
+	} else {
+		u, err := url.Parse(returnURI)
+		if err != nil {
+			response.Success(c, http.StatusOK, nil)
+		}
+		query := u.Query()
+		query.Add("status", "success")
+		u.RawQuery = query.Encode()
+		http.Redirect(c.Writer, c.Request, u.RequestURI(), http.StatusSeeOther)
+	}
+}
+
+// authLDAPLogin is function to login user by the LDAP bind if the local user isn't found
+func (s *AuthService) authLDAPLogin(c *gin.Context, data models.Login) {
+	ldapUser, err := s.ldap.Authenticate(c.Request.Context(), data.Mail, data.Password)
+	if errors.Is(err, ldap.ErrInvalidCredentials) {
+		logger.FromContext(c).Errorf("error matching LDAP user credentials for '%s'", data.Mail)
+		s.loginFailed(c, data.Mail, ldap.ProviderName)
+		response.Error(c, response.ErrAuthInvalidCredentials, err)
+		return
+	} else if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error authenticating LDAP user '%s'", data.Mail)
+		response.Error(c, response.ErrAuthInvalidServiceData, err)
+		return
+	}
+
+	user, privs, ok := s.externalUser(c, models.UserTypeLDAP, ldap.ProviderName, oauth.OAuthUser{
+		Email:  ldapUser.Mail,
+		Name:   ldapUser.Name,
+		Groups: ldapUser.Groups,
+	})
+	if !ok {
+		return
+	}
+
+	expires := s.cfg.SessionTimeout
+	session := sessions.Default(c)
+	if err := s.saveUserSession(c, user, privs, user.Mail, ldap.ProviderName); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error saving session")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	logger.FromContext(c).
+		WithFields(logrus.Fields{
+			"age":   expires,
+			"uid":   user.ID,
+			"uhash": user.Hash,
+			"rid":   user.RoleID,
+			"tid":   user.Type,
+			"gtm":   session.Get("gtm"),
+			"exp":   session.Get("exp"),
+			"prm":   session.Get("prm"),
+		}).
+		Infof("user made successful LDAP login for '%s' '%s'", user.Mail, user.Name)
+	if err := s.throttle.Reset(data.Mail); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error resetting login throttle of user '%s'", user.Hash)
+	}
+	s.auditLogin(c, audit.ActionLogin, user.ID, user.Mail, ldap.ProviderName)
+
+	response.Success(c, http.StatusOK, struct{}{})
+}
+
+// externalUser returns the user of the external provider with the role privileges, the user is created
+// on the first login; if the provider has role rules then the user without matched rule is rejected,
+// the group rule keeps the role in sync on every login while "*" rule sets the role of new users only
+func (s *AuthService) externalUser(
+	c *gin.Context,
+	userType models.UserType,
+	provider string,
+	extUser oauth.OAuthUser,
+) (models.User, []string, bool) {
+	var (
+		privs []string
+		role  models.Role
+		user  models.User
+	)
+
+	email := strings.ToLower(extUser.Email)
 	if !strings.Contains(email, "@") {
 		logger.FromContext(c).Errorf("invalid email format '%s'", email)
 		response.Error(c, response.ErrAuthInvalidUserData, fmt.Errorf("invalid email format"))
-		return
+		return user, nil, false
 	}
 
-	username := strings.Split(email, "@")[0]
+	username := extUser.Name
+	if username == "" {
+		username = strings.Split(email, "@")[0]
+	}
 	if username == "" {
 		logger.FromContext(c).Errorf("empty username from email '%s'", email)
 		response.Error(c, response.ErrAuthInvalidUserData, fmt.Errorf("empty username"))
-		return
+		return user, nil, false
 	}
 
-	err = s.db.Take(&role, "id = ?", models.RoleUser).Error
-	if err != nil {
-		logger.FromContext(c).WithError(err).Errorf("error getting user role '%d'", models.RoleUser)
-		response.Error(c, response.ErrAuthInvalidServiceData, err)
-		return
+	syncRole := false
+	roleQuery, roleArg := "id = ?", any(models.RoleUser)
+	if rules, ok := s.cfg.RoleRules[provider]; ok {
+		rule, ok := rules.Match(extUser.Groups)
+		if !ok {
+			logger.FromContext(c).Errorf("no role rule matched groups %v of user '%s'", extUser.Groups, email)
+			response.Error(c, response.ErrAuthRoleNotMapped, fmt.Errorf("role not mapped"))
+			return user, nil, false
+		}
+		roleQuery, roleArg = "LOWER(name) = LOWER(?)", rule.Role
+		syncRole = rule.Group != models.RoleRuleAnyGroup
 	}
 
-	err = s.db.Table("privileges").
-		Where("role_id = ?", models.RoleUser).
-		Pluck("name", &privs).Error
-	if err != nil {
-		logger.FromContext(c).WithError(err).Errorf("error getting user privileges list '%s'", user.Hash)
+	if err := s.db.Take(&role, roleQuery, roleArg).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting user role '%v'", roleArg)
 		response.Error(c, response.ErrAuthInvalidServiceData, err)
-		return
+		return user, nil, false
 	}
 
-	filterQuery := "mail = ? AND type = ?"
-	if err = s.db.Take(&user, filterQuery, email, models.UserTypeOAuth).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			user = models.User{
-				Hash:   rdb.MakeUserHash(email),
-				Mail:   email,
-				Name:   username,
-				RoleID: models.RoleUser,
-				Status: "active",
-				Type:   models.UserTypeOAuth,
-			}
-			if err = s.db.Create(&user).Error; err != nil {
-				logger.FromContext(c).WithError(err).Errorf("error creating user")
-				response.Error(c, response.ErrInternal, err)
-				return
-			}
-		} else {
+	if err := s.db.Take(&user, "mail = ?", email).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.FromContext(c).WithError(err).Errorf("error searching user by email '%s'", email)
 			response.Error(c, response.ErrInternal, err)
-			return
+			return user, nil, false
+		}
+
+		user = models.User{
+			Hash:     rdb.MakeUserHash(email),
+			Mail:     email,
+			Name:     username,
+			RoleID:   role.ID,
+			Status:   "active",
+			Type:     userType,
+			Provider: &provider,
+		}
+		if err = s.db.Create(&user).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error creating user")
+			response.Error(c, response.ErrInternal, err)
+			return user, nil, false
 		}
 	} else if err = user.Valid(); err != nil {
 		logger.FromContext(c).WithError(err).Errorf("error validating user data '%s'", user.Hash)
 		response.Error(c, response.ErrAuthInvalidUserData, err)
-		return
+		return user, nil, false
+	} else if user.Type != userType {
+		logger.FromContext(c).Errorf("user '%s' is registered with type '%s'", user.Hash, user.Type)
+		response.Error(c, response.ErrAuthUserTypeMismatch, fmt.Errorf("user type mismatch"))
+		return user, nil, false
+	} else if !userProviderMatches(user, provider) {
+		// the same email from another provider isn't the same person, the provider may not verify it
+		logger.FromContext(c).Errorf("user '%s' is registered with another provider than '%s'", user.Hash, provider)
+		response.Error(c, response.ErrAuthProviderMismatch, fmt.Errorf("user provider mismatch"))
+		return user, nil, false
+	} else if syncRole && user.RoleID != role.ID {
+		if err = s.db.Model(&user).UpdateColumn("role_id", role.ID).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error updating user role '%s'", user.Hash)
+			response.Error(c, response.ErrInternal, err)
+			return user, nil, false
+		}
+		user.RoleID = role.ID
+	}
+
+	if user.Provider == nil {
+		if err := s.db.Model(&user).UpdateColumn("provider", provider).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error updating user provider '%s'", user.Hash)
+			response.Error(c, response.ErrInternal, err)
+			return user, nil, false
+		}
+		user.Provider = &provider
 	}
 
 	if user.Status != "active" {
 		logger.FromContext(c).Errorf("error checking active state for user '%s'", user.Status)
 		response.Error(c, response.ErrAuthInactiveUser, fmt.Errorf("user is inactive"))
+		return user, nil, false
+	}
+
+	err := s.db.Table("privileges").
+		Where("role_id = ?", user.RoleID).
+		Pluck("name", &privs).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting user privileges list '%s'", user.Hash)
+		response.Error(c, response.ErrAuthInvalidServiceData, err)
+		return user, nil, false
+	}
+
+	return user, privs, true
+}
+
+// userProviderMatches reports whether the existing user may log in through the provider, the users
+// which were created by the Google and GitHub logins before the provider was stored have no provider
+// and are bound to the first of these providers which they log in with
+func userProviderMatches(user models.User, provider string) bool {
+	if user.Provider == nil {
+		return user.Type == models.UserTypeOAuth && (provider == "google" || provider == "github")
+	}
+
+	return *user.Provider == provider
+}
+
+// auditLogin records the login attempt or the logout on behalf of the user itself because the request
+// isn't authenticated yet, the failed attempt has no actor and keeps the entered login only
+func (s *AuthService) auditLogin(c *gin.Context, action audit.Action, userID uint64, login, method string) {
+	ctx := audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:     int64(userID),
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+
+	s.audit.Record(ctx, audit.Event{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(userID),
+		TargetName: login,
+		After:      map[string]string{"method": method},
+	})
+}
+
+// loginFailed records the failed login attempt and counts it for the account and the client address
+func (s *AuthService) loginFailed(c *gin.Context, login, method string) {
+	s.auditLogin(c, audit.ActionLoginFailed, 0, login, method)
+
+	lockout, err := s.throttle.Fail(login, c.ClientIP())
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error counting failed login attempt of '%s'", login)
+	} else if lockout > 0 {
+		logger.FromContext(c).Warnf("login of '%s' from '%s' is locked for %s", login, c.ClientIP(), lockout)
+		s.auditLogin(c, audit.ActionLoginLocked, 0, login, method)
+	}
+}
+
+// checkLoginThrottle rejects the login attempt while the account or the client address is locked,
+// the credentials aren't checked in this case, so the locked login doesn't reveal the valid password
+func (s *AuthService) checkLoginThrottle(c *gin.Context, login string) bool {
+	left, err := s.throttle.Locked(login, c.ClientIP())
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error checking login throttle of '%s'", login)
+		response.Error(c, response.ErrInternal, err)
+		return false
+	}
+
+	if left > 0 {
+		logger.FromContext(c).Errorf("login of '%s' from '%s' is locked for %s", login, c.ClientIP(), left)
+		c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(left.Seconds())), 10))
+		response.Error(c, response.ErrAuthTooManyAttempts, nil)
+		return false
+	}
+
+	return true
+}
+
+// loginMFAStatus returns whether the local user must verify the enabled second factor or must enroll
+// the one because it's required by the user role
+func (s *AuthService) loginMFAStatus(user models.User) (models.LoginStatus, error) {
+	var (
+		status models.LoginStatus
+		mfa    models.UserMFA
+		count  int
+	)
+
+	if user.Type != models.UserTypeLocal {
+		return status, nil
+	}
+
+	if err := s.db.Take(&mfa, "user_id = ?", user.ID).Error; err == nil && mfa.Confirmed() {
+		status.MFARequired = true
+		return status, nil
+	} else if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return status, err
+	}
+
+	err := s.db.Model(&models.Privilege{}).
+		Where("role_id = ? AND name = ?", user.RoleID, auth.PrivilegeMFARequired).
+		Count(&count).Error
+	if err != nil {
+		return status, err
+	}
+	status.MFAEnrollRequired = count > 0
+
+	return status, nil
+}
+
+// savePendingMFASession keeps the user who passed the password check in the cookie for the short time,
+// the pending session has no user attributes, so it isn't accepted by the auth middleware
+func (s *AuthService) savePendingMFASession(c *gin.Context, user models.User) error {
+	s.revokeCookieSession(c)
+
+	session := sessions.Default(c)
+	session.Clear()
+	session.Set("mfa_uid", user.ID)
+	session.Set("mfa_exp", time.Now().Add(authMFALoginTTL).Unix())
+	session.Options(sessions.Options{
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		Path:     s.cfg.BaseURL,
+		MaxAge:   int(authMFALoginTTL / time.Second),
+	})
+
+	return session.Save()
+}
+
+// pendingMFAUser returns the user of the pending login which waits for the second factor
+func (s *AuthService) pendingMFAUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+
+	session := sessions.Default(c)
+	uid, ok := session.Get("mfa_uid").(uint64)
+	exp, _ := session.Get("mfa_exp").(int64)
+	if !ok || time.Now().Unix() > exp {
+		logger.FromContext(c).Errorf("error getting pending login from session")
+		response.Error(c, response.ErrMFALoginRequired, nil)
+		return user, false
+	}
+
+	if err := s.db.Take(&user, "id = ?", uid).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting user of pending login '%d'", uid)
+		response.Error(c, response.ErrAuthInvalidUserData, err)
+		return user, false
+	} else if user.Status != models.UserStatusActive {
+		logger.FromContext(c).Errorf("error checking active state for user '%s'", user.Status)
+		response.Error(c, response.ErrAuthInactiveUser, fmt.Errorf("user is inactive"))
+		return user, false
+	}
+
+	return user, true
+}
+
+// revokeCookieSession revokes the login session of the current cookie if it has one
+func (s *AuthService) revokeCookieSession(c *gin.Context) {
+	session := sessions.Default(c)
+	sid, ok := session.Get("sid").(string)
+	if !ok || sid == "" {
 		return
 	}
 
+	err := s.db.Model(&models.UserSession{}).
+		Where("sid_hash = ? AND revoked_at IS NULL", auth.HashSessionID(sid)).
+		UpdateColumn("revoked_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error revoking session")
+	}
+	session.Delete("sid")
+}
+
+// auditMFA records the second factor change of the pending login on behalf of the user itself
+func (s *AuthService) auditMFA(c *gin.Context, action audit.Action, user models.User) {
+	ctx := audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:     int64(user.ID),
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+
+	s.audit.Record(ctx, audit.Event{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+}
+
+// saveUserSession stores the login session in the DB and its id in the cookie, the previous session
+// of the cookie is revoked
+func (s *AuthService) saveUserSession(
+	c *gin.Context,
+	user models.User,
+	privs []string,
+	uuid, method string,
+) error {
+	s.revokeCookieSession(c)
+
+	sid, sidHash, err := auth.GenerateSessionID()
+	if err != nil {
+		return err
+	}
+
 	expires := s.cfg.SessionTimeout
-	gtm := time.Now().Unix()
-	exp := time.Now().Add(time.Duration(expires) * time.Second).Unix()
+	userSession := models.UserSession{
+		UserID:     user.ID,
+		SIDHash:    sidHash,
+		Method:     method,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ExpiresAt:  time.Now().Add(time.Duration(expires) * time.Second),
+	}
+	if err := s.db.Create(&userSession).Error; err != nil {
+		return fmt.Errorf("error creating user session: %w", err)
+	}
+
 	session := sessions.Default(c)
+	session.Delete("mfa_uid")
+	session.Delete("mfa_exp")
+	session.Set("sid", sid)
 	session.Set("uid", user.ID)
 	session.Set("uhash", user.Hash)
 	session.Set("rid", user.RoleID)
 	session.Set("tid", user.Type.String())
 	session.Set("prm", privs)
-	session.Set("gtm", gtm)
-	session.Set("exp", exp)
-	session.Set("uuid", user.Mail)
+	session.Set("gtm", time.Now().Unix())
+	session.Set("exp", time.Now().Add(time.Duration(expires)*time.Second).Unix())
+	session.Set("uuid", uuid)
 	session.Set("uname", user.Name)
 	session.Options(sessions.Options{
 		HttpOnly: true,
@@ -549,41 +1055,8 @@ func (s *AuthService) authLoginCallback(c *gin.Context, stateData map[string]str
 		Path:     s.cfg.BaseURL,
 		MaxAge:   expires,
 	})
-	if err := session.Save(); err != nil {
-		logger.FromContext(c).WithError(err).Errorf("error saving session")
-		response.Error(c, response.ErrInternal, err)
-		return
-	}
 
-	// delete temporary cookies
-	s.setCallbackCookie(c.Writer, c.Request, authStateCookieName, "", 0)
-	s.setCallbackCookie(c.Writer, c.Request, authNonceCookieName, "", 0)
-
-	logger.FromContext(c).
-		WithFields(logrus.Fields{
-			"age":   expires,
-			"uid":   user.ID,
-			"uhash": user.Hash,
-			"rid":   user.RoleID,
-			"tid":   user.Type,
-			"gtm":   session.Get("gtm"),
-			"exp":   session.Get("exp"),
-			"prm":   session.Get("prm"),
-		}).
-		Infof("user made successful SSO login for '%s' '%s'", user.Mail, user.Name)
-
-	if returnURI := stateData["return_uri"]; returnURI == "" {
-		response.Success(c, http.StatusOK, nil)
-	} else {
-		u, err := url.Parse(returnURI)
-		if err != nil {
-			response.Success(c, http.StatusOK, nil)
-		}
-		query := u.Query()
-		query.Add("status", "success")
-		u.RawQuery = query.Encode()
-		http.Redirect(c.Writer, c.Request, u.RequestURI(), http.StatusSeeOther)
-	}
+	return session.Save()
 }
 
 func (s *AuthService) parseState(c *gin.Context, state string) (map[string]string, error) {
@@ -653,6 +1126,8 @@ func (s *AuthService) setCallbackCookie(w http.ResponseWriter, r *http.Request,
 }
 
 func (s *AuthService) resetSession(c *gin.Context) {
+	s.revokeCookieSession(c)
+
 	now := time.Now().Add(-1 * time.Second)
 	session := sessions.Default(c)
 	session.Set("gtm", now.Unix())
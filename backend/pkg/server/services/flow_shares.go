@@ -0,0 +1,449 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"pentagi/pkg/redact"
+	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/rdb"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type sharedTasks struct {
+	Tasks []models.TaskSubtasks `json:"tasks"`
+	Total uint64                `json:"total"`
+}
+
+// FlowShareService serves the read-only flow data by the share link token without authentication,
+// every request is recorded to the share accesses and the secrets are redacted from the text
+type FlowShareService struct {
+	db         *gorm.DB
+	dataDir    string
+	globalSalt string
+	redactor   *redact.Redactor
+}
+
+func NewFlowShareService(db *gorm.DB, dataDir, globalSalt string, redactor *redact.Redactor) *FlowShareService {
+	return &FlowShareService{
+		db:         db,
+		dataDir:    dataDir,
+		globalSalt: globalSalt,
+		redactor:   redactor,
+	}
+}
+
+// GetSharedFlow is a function to return the shared flow
+// @Summary Retrieve the flow by the share link token
+// @Tags FlowShares
+// @Produce json
+// @Param token path string true "share link token"
+// @Success 200 {object} response.successResp{data=models.SharedFlow} "shared flow received successful"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} response.errorResp "internal error on getting shared flow"
+// @Router /shared/{token}/flow [get]
+func (s *FlowShareService) GetSharedFlow(c *gin.Context) {
+	share, ok := s.getShare(c, "flow")
+	if !ok {
+		return
+	}
+
+	redactor, ok := s.getRedactor(c)
+	if !ok {
+		return
+	}
+
+	var flow models.Flow
+	if err := s.db.Take(&flow, "id = ?", share.FlowID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error on getting shared flow by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrFlowSharesNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	resp := models.SharedFlow{
+		ID:                flow.ID,
+		Status:            flow.Status,
+		Title:             redactor.String(flow.Title),
+		ModelProviderName: flow.ModelProviderName,
+		ModelProviderType: flow.ModelProviderType,
+		WithTerminalLogs:  share.WithTerminalLogs,
+		ExpiresAt:         share.ExpiresAt,
+		CreatedAt:         flow.CreatedAt,
+		UpdatedAt:         flow.UpdatedAt,
+	}
+	if err := resp.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating shared flow data '%d'", flow.ID)
+		response.Error(c, response.ErrFlowSharesInvalidData, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetSharedTasks is a function to return the shared flow tasks with subtasks
+// @Summary Retrieve the flow tasks with subtasks by the share link token
+// @Tags FlowShares
+// @Produce json
+// @Param token path string true "share link token"
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=sharedTasks} "shared flow tasks received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} response.errorResp "internal error on getting shared flow tasks"
+// @Router /shared/{token}/tasks/ [get]
+func (s *FlowShareService) GetSharedTasks(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		tasks []models.Task
+		resp  sharedTasks
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrFlowSharesInvalidRequest, err)
+		return
+	}
+
+	share, ok := s.getShare(c, "tasks")
+	if !ok {
+		return
+	}
+
+	redactor, ok := s.getRedactor(c)
+	if !ok {
+		return
+	}
+
+	query.Init("tasks", tasksSQLMappers)
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Where("flow_id = ?", share.FlowID)
+	}
+
+	if resp.Total, err = query.Query(s.db, &tasks, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding shared tasks")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	taskIDs := make([]uint64, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	var subtasks []models.Subtask
+	if len(taskIDs) != 0 {
+		err = s.db.Where("task_id IN (?)", taskIDs).Order("id ASC").Find(&subtasks).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error finding shared subtasks")
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+	}
+
+	resp.Tasks = make([]models.TaskSubtasks, 0, len(tasks))
+	for _, task := range tasks {
+		task.Title = redactor.String(task.Title)
+		task.Input = redactor.String(task.Input)
+		task.Result = redactor.String(task.Result)
+
+		ts := models.TaskSubtasks{Task: task, Subtasks: []models.Subtask{}}
+		for _, subtask := range subtasks {
+			if subtask.TaskID != task.ID {
+				continue
+			}
+			subtask.Title = redactor.String(subtask.Title)
+			subtask.Description = redactor.String(subtask.Description)
+			subtask.Context = redactor.String(subtask.Context)
+			subtask.Result = redactor.String(subtask.Result)
+			ts.Subtasks = append(ts.Subtasks, subtask)
+		}
+		resp.Tasks = append(resp.Tasks, ts)
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetSharedMsglogs is a function to return the shared flow message logs
+// @Summary Retrieve the flow message logs by the share link token
+// @Tags FlowShares
+// @Produce json
+// @Param token path string true "share link token"
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=msglogs} "shared flow msglogs received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} response.errorResp "internal error on getting shared flow msglogs"
+// @Router /shared/{token}/msglogs/ [get]
+func (s *FlowShareService) GetSharedMsglogs(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  msglogs
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrFlowSharesInvalidRequest, err)
+		return
+	}
+
+	share, ok := s.getShare(c, "msglogs")
+	if !ok {
+		return
+	}
+
+	redactor, ok := s.getRedactor(c)
+	if !ok {
+		return
+	}
+
+	query.Init("msglogs", msglogsSQLMappers)
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Where("flow_id = ?", share.FlowID)
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.MsgLogs, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding shared msglogs")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := range resp.MsgLogs {
+		resp.MsgLogs[i].Message = redactor.String(resp.MsgLogs[i].Message)
+		resp.MsgLogs[i].Thinking = redactor.String(resp.MsgLogs[i].Thinking)
+		resp.MsgLogs[i].Result = redactor.String(resp.MsgLogs[i].Result)
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetSharedTermlogs is a function to return the shared flow terminal logs if the link allows it
+// @Summary Retrieve the flow terminal logs by the share link token
+// @Tags FlowShares
+// @Produce json
+// @Param token path string true "share link token"
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=termlogs} "shared flow termlogs received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 403 {object} response.errorResp "terminal logs are not shared by the link"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} response.errorResp "internal error on getting shared flow termlogs"
+// @Router /shared/{token}/termlogs/ [get]
+func (s *FlowShareService) GetSharedTermlogs(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  termlogs
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrFlowSharesInvalidRequest, err)
+		return
+	}
+
+	share, ok := s.getShare(c, "termlogs")
+	if !ok {
+		return
+	}
+
+	redactor, ok := s.getRedactor(c)
+	if !ok {
+		return
+	}
+
+	if !share.WithTerminalLogs {
+		response.Error(c, response.ErrFlowSharesTermlogsDisabled, nil)
+		return
+	}
+
+	query.Init("termlogs", termlogsSQLMappers)
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("INNER JOIN containers c ON c.id = container_id").
+			Where("c.flow_id = ?", share.FlowID)
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.TermLogs, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding shared termlogs")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := range resp.TermLogs {
+		resp.TermLogs[i].Text = redactor.String(resp.TermLogs[i].Text)
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetSharedScreenshots is a function to return the shared flow screenshots
+// @Summary Retrieve the flow screenshots by the share link token
+// @Tags FlowShares
+// @Produce json
+// @Param token path string true "share link token"
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=screenshots} "shared flow screenshots received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} response.errorResp "internal error on getting shared flow screenshots"
+// @Router /shared/{token}/screenshots/ [get]
+func (s *FlowShareService) GetSharedScreenshots(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  screenshots
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrFlowSharesInvalidRequest, err)
+		return
+	}
+
+	share, ok := s.getShare(c, "screenshots")
+	if !ok {
+		return
+	}
+
+	redactor, ok := s.getRedactor(c)
+	if !ok {
+		return
+	}
+
+	query.Init("screenshots", screenshotsSQLMappers)
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Where("flow_id = ?", share.FlowID)
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.Screenshots, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding shared screenshots")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := range resp.Screenshots {
+		resp.Screenshots[i].URL = redactor.String(resp.Screenshots[i].URL)
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetSharedScreenshotFile is a function to return the shared flow screenshot file
+// @Summary Retrieve the flow screenshot file by the share link token
+// @Tags FlowShares
+// @Produce png,json
+// @Param token path string true "share link token"
+// @Param screenshotID path int true "screenshot id" minimum(0)
+// @Success 200 {file} file "shared screenshot file"
+// @Failure 400 {object} response.errorResp "invalid screenshot request data"
+// @Failure 404 {object} response.errorResp "share link or screenshot not found"
+// @Failure 500 {object} response.errorResp "internal error on getting shared screenshot"
+// @Router /shared/{token}/screenshots/{screenshotID}/file [get]
+func (s *FlowShareService) GetSharedScreenshotFile(c *gin.Context) {
+	var (
+		err          error
+		screenshotID uint64
+		resp         models.Screenshot
+	)
+
+	if screenshotID, err = strconv.ParseUint(c.Param("screenshotID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing screenshot id")
+		response.Error(c, response.ErrFlowSharesInvalidRequest, err)
+		return
+	}
+
+	share, ok := s.getShare(c, fmt.Sprintf("screenshots/%d/file", screenshotID))
+	if !ok {
+		return
+	}
+
+	err = s.db.Take(&resp, "id = ? AND flow_id = ?", screenshotID, share.FlowID).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error on getting shared screenshot by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrScreenshotsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	flowDirName := fmt.Sprintf("flow-%d", resp.FlowID)
+	c.File(filepath.Join(s.dataDir, "screenshots", flowDirName, resp.Name))
+}
+
+// getShare finds the active share of the existing flow by the token from the path and records the access to the resource,
+// all failures are reported as not found to not disclose which links exist
+// getRedactor returns the redactor which also replaces the secrets from the provider configs stored
+// in the database, they are loaded on every request because users change their providers at runtime
+func (s *FlowShareService) getRedactor(c *gin.Context) (*redact.Redactor, bool) {
+	var providers []models.Provider
+	if err := s.db.Select("config").Find(&providers).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error loading providers configs")
+		response.Error(c, response.ErrInternal, err)
+		return nil, false
+	}
+
+	var values []string
+	for _, prv := range providers {
+		values = append(values, redact.ConfigValues(prv.Config)...)
+	}
+
+	return s.redactor.With(values...), true
+}
+
+func (s *FlowShareService) getShare(c *gin.Context, resource string) (models.FlowShare, bool) {
+	var share models.FlowShare
+
+	hash, err := auth.VerifyFlowShareToken(c.Param("token"), s.globalSalt)
+	if err == nil {
+		err = s.db.Take(&share, "token_hash = ?", hash).Error
+	}
+	if err == nil && !share.Active(time.Now()) {
+		err = errors.New("flow share link is either revoked or expired")
+	}
+	if err == nil {
+		// the link to the deleted flow stops working as well
+		err = s.db.Select("id").Take(&models.Flow{}, "id = ?", share.FlowID).Error
+	}
+	if err != nil {
+		logger.FromContext(c).WithError(err).Warn("flow share link access denied")
+		response.Error(c, response.ErrFlowSharesNotFound, nil)
+		return share, false
+	}
+
+	access := models.FlowShareAccess{
+		ShareID:    share.ID,
+		Resource:   resource,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+	if err := s.db.Create(&access).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error recording flow share access")
+		response.Error(c, response.ErrInternal, err)
+		return share, false
+	}
+
+	logger.FromContext(c).
+		WithField("share_id", share.ID).
+		WithField("flow_id", share.FlowID).
+		WithField("resource", resource).
+		Info("flow share link access")
+
+	return share, true
+}
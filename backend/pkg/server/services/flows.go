@@ -5,7 +5,9 @@ import (
 	"slices"
 	"strconv"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/controller"
+	"pentagi/pkg/database"
 	"pentagi/pkg/providers"
 	"pentagi/pkg/providers/provider"
 	"pentagi/pkg/server/logger"
@@ -39,16 +41,23 @@ var flowsSQLMappers = map[string]interface{}{
 }
 
 type FlowService struct {
-	db *gorm.DB
-	pc providers.ProviderController
-	fc controller.FlowController
+	db    *gorm.DB
+	pc    providers.ProviderController
+	fc    controller.FlowController
+	audit audit.Recorder
 }
 
-func NewFlowService(db *gorm.DB, pc providers.ProviderController, fc controller.FlowController) *FlowService {
+func NewFlowService(
+	db *gorm.DB,
+	pc providers.ProviderController,
+	fc controller.FlowController,
+	audit audit.Recorder,
+) *FlowService {
 	return &FlowService{
-		db: db,
-		pc: pc,
-		fc: fc,
+		db:    db,
+		pc:    pc,
+		fc:    fc,
+		audit: audit,
 	}
 }
 
@@ -84,7 +93,7 @@ func (s *FlowService) GetFlows(c *gin.Context) {
 		}
 	} else if slices.Contains(privs, "flows.view") {
 		scope = func(db *gorm.DB) *gorm.DB {
-			return db.Where("user_id = ?", uid)
+			return db.Scopes(teamAccessScope("flows", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -155,7 +164,7 @@ func (s *FlowService) GetFlow(c *gin.Context) {
 		}
 	} else if slices.Contains(privs, "flows.view") {
 		scope = func(db *gorm.DB) *gorm.DB {
-			return db.Where("id = ? AND user_id = ?", flowID, uid)
+			return db.Where("id = ?", flowID).Scopes(teamAccessScope("flows", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -209,7 +218,7 @@ func (s *FlowService) GetFlowGraph(c *gin.Context) {
 		}
 	} else if slices.Contains(privs, "flows.view") {
 		scope = func(db *gorm.DB) *gorm.DB {
-			return db.Where("id = ? AND user_id = ?", flowID, uid)
+			return db.Where("id = ?", flowID).Scopes(teamAccessScope("flows", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -230,14 +239,17 @@ func (s *FlowService) GetFlowGraph(c *gin.Context) {
 		return
 	}
 
+	// without the admin privilege the flow is found only if the user owns it or it's shared with the user team
+	own := resp.UserID == uid || !slices.Contains(privs, "flows.admin")
+
 	isTasksAdmin := slices.Contains(privs, "tasks.admin")
 	isTasksView := slices.Contains(privs, "tasks.view")
-	if !(resp.UserID == uid && isTasksView) && !(resp.UserID != uid && isTasksAdmin) {
+	if !(own && isTasksView) && !(!own && isTasksAdmin) {
 		response.Success(c, http.StatusOK, resp)
 		return
 	}
 
-	if resp.UserID != uid && !slices.Contains(privs, "tasks.admin") {
+	if !own && !slices.Contains(privs, "tasks.admin") {
 		response.Success(c, http.StatusOK, resp)
 		return
 	}
@@ -251,7 +263,7 @@ func (s *FlowService) GetFlowGraph(c *gin.Context) {
 
 	isSubtasksAdmin := slices.Contains(privs, "subtasks.admin")
 	isSubtasksView := slices.Contains(privs, "subtasks.view")
-	if !(resp.UserID == uid && isSubtasksView) && !(resp.UserID != uid && isSubtasksAdmin) {
+	if !(own && isSubtasksView) && !(!own && isSubtasksAdmin) {
 		response.Success(c, http.StatusOK, resp)
 		return
 	}
@@ -334,20 +346,33 @@ func (s *FlowService) CreateFlow(c *gin.Context) {
 	}
 	prvtype := prv.Type()
 
-	fw, err := s.fc.CreateFlow(c, int64(uid), createFlow.Input, prvname, prvtype, createFlow.Functions)
+	priority := database.FlowPriorityNormal
+	if createFlow.Priority != "" {
+		priority = database.FlowPriority(createFlow.Priority)
+	}
+
+	queued, err := s.fc.CreateFlow(c, int64(uid), createFlow.Input, prvname, prvtype, createFlow.Functions, createFlow.PlanReview, priority, nil)
 	if err != nil {
 		logger.FromContext(c).WithError(err).Errorf("error creating flow")
 		response.Error(c, response.ErrInternal, err)
 		return
 	}
 
-	err = s.db.Model(&flow).Where("id = ?", fw.GetFlowID()).Take(&flow).Error
+	err = s.db.Model(&flow).Where("id = ?", queued.ID).Take(&flow).Error
 	if err != nil {
 		logger.FromContext(c).WithError(err).Errorf("error getting flow by id")
 		response.Error(c, response.ErrInternal, err)
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionFlowCreate,
+		TargetType: audit.TargetFlow,
+		TargetID:   int64(flow.ID),
+		TargetName: flow.Title,
+		After:      flow,
+	})
+
 	response.Success(c, http.StatusCreated, flow)
 }
 
@@ -399,7 +424,7 @@ func (s *FlowService) PatchFlow(c *gin.Context) {
 		}
 	} else if slices.Contains(privs, "flows.edit") {
 		scope = func(db *gorm.DB) *gorm.DB {
-			return db.Where("id = ? AND user_id = ?", flowID, uid)
+			return db.Where("id = ?", flowID).Scopes(teamAccessScope("flows", uid, models.TeamRoleOperator))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -468,6 +493,121 @@ func (s *FlowService) PatchFlow(c *gin.Context) {
 	response.Success(c, http.StatusOK, flow)
 }
 
+// ShareFlow is a function to move the flow to the team or to return it to the owner
+// @Summary Share flow with the team, the flow owner must be the team operator or admin
+// @Tags Flows
+// @Accept json
+// @Produce json
+// @Param flowID path int true "flow id" minimum(0)
+// @Param json body models.ShareFlow true "team to share the flow with, null returns the flow to the owner"
+// @Success 200 {object} response.successResp{data=models.Flow} "flow shared successful"
+// @Failure 400 {object} response.errorResp "invalid flow request data"
+// @Failure 403 {object} response.errorResp "sharing flow not permitted"
+// @Failure 404 {object} response.errorResp "flow or team not found"
+// @Failure 500 {object} response.errorResp "internal error on sharing flow"
+// @Router /flows/{flowID}/team [put]
+func (s *FlowService) ShareFlow(c *gin.Context) {
+	var (
+		err    error
+		flow   models.Flow
+		flowID uint64
+		req    models.ShareFlow
+	)
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrFlowsInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating flow share JSON")
+		response.Error(c, response.ErrFlowsInvalidRequest, err)
+		return
+	}
+
+	if flowID, err = strconv.ParseUint(c.Param("flowID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing flow id")
+		response.Error(c, response.ErrFlowsInvalidRequest, err)
+		return
+	}
+
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	var scope func(db *gorm.DB) *gorm.DB
+	if slices.Contains(privs, "flows.admin") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ?", flowID)
+		}
+	} else if slices.Contains(privs, "flows.edit") {
+		// only the owner decides whom the flow is shared with
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ? AND user_id = ?", flowID, uid)
+		}
+	} else {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = s.db.Model(&flow).Scopes(scope).Take(&flow).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting flow by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrFlowsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	if req.TeamID != nil {
+		var member models.TeamMember
+		err = s.db.Take(&member, "team_id = ? AND user_id = ?", *req.TeamID, uid).Error
+		if err != nil && !gorm.IsRecordNotFoundError(err) {
+			logger.FromContext(c).WithError(err).Errorf("error finding team member")
+			response.Error(c, response.ErrInternal, err)
+			return
+		} else if (err != nil || !member.Role.Allows(models.TeamRoleOperator)) && !slices.Contains(privs, "teams.admin") {
+			logger.FromContext(c).Errorf("error sharing flow with team '%d': operator role is required", *req.TeamID)
+			response.Error(c, response.ErrNotPermitted, nil)
+			return
+		}
+
+		var team models.Team
+		if err = s.db.Take(&team, "id = ?", *req.TeamID).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error finding team by id")
+			if gorm.IsRecordNotFoundError(err) {
+				response.Error(c, response.ErrTeamsNotFound, err)
+			} else {
+				response.Error(c, response.ErrInternal, err)
+			}
+			return
+		}
+	}
+
+	before := map[string]any{"team_id": flow.TeamID}
+	if err = s.db.Model(&flow).UpdateColumn("team_id", req.TeamID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error sharing flow by id '%d'", flowID)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if err = s.db.Model(&flow).Scopes(scope).Take(&flow).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting flow by id")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionFlowShare,
+		TargetType: audit.TargetFlow,
+		TargetID:   int64(flow.ID),
+		TargetName: flow.Title,
+		Before:     before,
+		After:      map[string]any{"team_id": flow.TeamID},
+	})
+
+	response.Success(c, http.StatusOK, flow)
+}
+
 // DeleteFlow is a function to delete flow by id
 // @Summary Delete flow by id
 // @Tags Flows
@@ -500,7 +640,7 @@ func (s *FlowService) DeleteFlow(c *gin.Context) {
 		}
 	} else if slices.Contains(privs, "flows.delete") {
 		scope = func(db *gorm.DB) *gorm.DB {
-			return db.Where("id = ? AND user_id = ?", flowID, uid)
+			return db.Where("id = ?", flowID).Scopes(teamAccessScope("flows", uid, models.TeamRoleAdmin))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -534,5 +674,13 @@ func (s *FlowService) DeleteFlow(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionFlowDelete,
+		TargetType: audit.TargetFlow,
+		TargetID:   int64(flow.ID),
+		TargetName: flow.Title,
+		Before:     flow,
+	})
+
 	response.Success(c, http.StatusOK, flow)
 }
@@ -8,14 +8,19 @@ import (
 	"strings"
 	"time"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/config"
 	"pentagi/pkg/controller"
 	"pentagi/pkg/database"
 	"pentagi/pkg/graph"
 	"pentagi/pkg/graph/subscriptions"
 	"pentagi/pkg/providers"
+	"pentagi/pkg/redact"
+	"pentagi/pkg/server/auth"
 	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/response"
 	"pentagi/pkg/templates"
+	"pentagi/pkg/webhooks"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -35,8 +40,10 @@ var (
 )
 
 type GraphqlService struct {
-	srv  *handler.Server
-	play http.HandlerFunc
+	db         database.Querier
+	globalSalt string
+	srv        *handler.Server
+	play       http.HandlerFunc
 }
 
 type originValidator struct {
@@ -54,6 +61,9 @@ func NewGraphqlService(
 	providers providers.ProviderController,
 	controller controller.FlowController,
 	subscriptions subscriptions.SubscriptionsController,
+	webhooks webhooks.Dispatcher,
+	redactor *redact.Redactor,
+	audit audit.Recorder,
 ) *GraphqlService {
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
 		DB:              db,
@@ -63,6 +73,9 @@ func NewGraphqlService(
 		ProvidersCtrl:   providers,
 		Controller:      controller,
 		Subscriptions:   subscriptions,
+		Webhooks:        webhooks,
+		Redactor:        redactor,
+		Audit:           audit,
 	}}))
 
 	component := "pentagi-gql"
@@ -90,6 +103,11 @@ func NewGraphqlService(
 	srv.AddTransport(&transport.Websocket{
 		KeepAlivePingInterval: 10 * time.Second,
 		InitFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			if access, err := graph.GetFlowShareAccess(ctx); err == nil {
+				logger.WithField("share", access.ShareID).Info("graphql websocket upgrade by flow share link")
+				return ctx, &initPayload, nil
+			}
+
 			uid, err := graph.GetUserID(ctx)
 			if err != nil {
 				return nil, nil, fmt.Errorf("unauthorized: invalid user: %v", err)
@@ -107,8 +125,10 @@ func NewGraphqlService(
 	})
 
 	return &GraphqlService{
-		srv:  srv,
-		play: playground.Handler("GraphQL", baseURL+"/graphql"),
+		db:         db,
+		globalSalt: cfg.CookieSigningSalt,
+		srv:        srv,
+		play:       playground.Handler("GraphQL", baseURL+"/graphql"),
 	}
 }
 
@@ -135,6 +155,54 @@ func (s *GraphqlService) ServeGraphql(c *gin.Context) {
 	ctx := savedCtx
 	ctx = graph.SetUserID(ctx, uid)
 	ctx = graph.SetUserPermissions(ctx, privs)
+	ctx = audit.WithActor(ctx, audit.Actor{
+		UserID:     int64(uid),
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
+	c.Request = c.Request.WithContext(ctx)
+
+	s.srv.ServeHTTP(c.Writer, c.Request)
+}
+
+// ServeSharedGraphql is a function to perform graphql requests by the flow share link token
+// @Summary Perform graphql requests by the flow share link token, only the shared queries are allowed
+// @Tags GraphQL, FlowShares
+// @Accept json
+// @Produce json
+// @Param token path string true "share link token"
+// @Param json body graphql.RawParams true "graphql request"
+// @Success 200 {object} graphql.Response "graphql response"
+// @Failure 400 {object} graphql.Response "invalid graphql request data"
+// @Failure 404 {object} response.errorResp "share link not found or expired"
+// @Failure 500 {object} graphql.Response "internal error on graphql request"
+// @Router /shared/{token}/graphql [post]
+func (s *GraphqlService) ServeSharedGraphql(c *gin.Context) {
+	savedCtx := c.Request.Context()
+	defer func() {
+		c.Request = c.Request.WithContext(savedCtx)
+	}()
+
+	hash, err := auth.VerifyFlowShareToken(c.Param("token"), s.globalSalt)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Warn("flow share link access denied")
+		response.Error(c, response.ErrFlowSharesNotFound, nil)
+		return
+	}
+
+	share, err := s.db.GetFlowShareByTokenHash(savedCtx, hash)
+	if err != nil || share.RevokedAt.Valid || !time.Now().Before(share.ExpiresAt) {
+		logger.FromContext(c).WithError(err).Warn("flow share link access denied")
+		response.Error(c, response.ErrFlowSharesNotFound, nil)
+		return
+	}
+
+	// the user and the privileges aren't set, so only the shared queries resolve for this request
+	ctx := graph.SetFlowShareAccess(savedCtx, graph.FlowShareAccess{
+		ShareID:    share.ID,
+		RemoteAddr: c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	})
 	c.Request = c.Request.WithContext(ctx)
 
 	s.srv.ServeHTTP(c.Writer, c.Request)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"slices"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/server/logger"
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/rdb"
@@ -26,12 +27,14 @@ var promptsSQLMappers = map[string]interface{}{
 }
 
 type PromptService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	audit audit.Recorder
 }
 
-func NewPromptService(db *gorm.DB) *PromptService {
+func NewPromptService(db *gorm.DB, audit audit.Recorder) *PromptService {
 	return &PromptService{
-		db: db,
+		db:    db,
+		audit: audit,
 	}
 }
 
@@ -67,7 +70,7 @@ func (s *PromptService) GetPrompts(c *gin.Context) {
 
 	uid := c.GetUint64("uid")
 	scope := func(db *gorm.DB) *gorm.DB {
-		return db.Where("user_id = ?", uid)
+		return db.Scopes(teamAccessScope("prompts", uid, models.TeamRoleViewer))
 	}
 
 	query.Init("prompts", promptsSQLMappers)
@@ -186,6 +189,17 @@ func (s *PromptService) PatchPrompt(c *gin.Context) {
 		return db.Where("type = ? AND user_id = ?", promptType, uid)
 	}
 
+	var before models.Prompt
+	if err = s.db.Scopes(scope).Take(&before).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding prompt by type '%s'", promptType)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrPromptsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
 	err = s.db.Model(&resp).Scopes(scope).UpdateColumn("prompt", prompt.Prompt).Error
 	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
 		logger.FromContext(c).Errorf("error updating prompt by type '%s', prompt not found", promptType)
@@ -203,6 +217,15 @@ func (s *PromptService) PatchPrompt(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionPromptUpdate,
+		TargetType: audit.TargetPrompt,
+		TargetID:   int64(resp.ID),
+		TargetName: string(resp.Type),
+		Before:     before,
+		After:      resp,
+	})
+
 	response.Success(c, http.StatusOK, resp)
 }
 
@@ -238,6 +261,17 @@ func (s *PromptService) ResetPrompt(c *gin.Context) {
 	}
 
 	// TODO: use templates.GetTemplate
+	var before models.Prompt
+	if err = s.db.Scopes(scope).Take(&before).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding prompt by type '%s'", promptType)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrPromptsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
 	err = s.db.Model(&resp).Scopes(scope).UpdateColumn("prompt", "").Error
 	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
 		logger.FromContext(c).Errorf("error updating prompt by type '%s', prompt not found", promptType)
@@ -255,5 +289,14 @@ func (s *PromptService) ResetPrompt(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionPromptDelete,
+		TargetType: audit.TargetPrompt,
+		TargetID:   int64(resp.ID),
+		TargetName: string(resp.Type),
+		Before:     before,
+		After:      resp,
+	})
+
 	response.Success(c, http.StatusOK, resp)
 }
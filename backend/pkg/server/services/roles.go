@@ -6,6 +6,7 @@ import (
 	"slices"
 	"strconv"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/server/logger"
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/rdb"
@@ -27,12 +28,16 @@ var rolesSQLMappers = map[string]interface{}{
 }
 
 type RoleService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	users *UserService
+	audit audit.Recorder
 }
 
-func NewRoleService(db *gorm.DB) *RoleService {
+func NewRoleService(db *gorm.DB, users *UserService, audit audit.Recorder) *RoleService {
 	return &RoleService{
-		db: db,
+		db:    db,
+		users: users,
+		audit: audit,
 	}
 }
 
@@ -63,8 +68,8 @@ func (s *RoleService) GetRoles(c *gin.Context) {
 	rid := c.GetUint64("rid")
 	privs := c.GetStringSlice("prm")
 	scope := func(db *gorm.DB) *gorm.DB {
-		if !slices.Contains(privs, "roles.view'") {
-			return db.Where("role_id = ?", rid)
+		if !slices.Contains(privs, "roles.view") {
+			return db.Where("id = ?", rid)
 		}
 		return db
 	}
@@ -128,8 +133,8 @@ func (s *RoleService) GetRole(c *gin.Context) {
 	rid := c.GetUint64("rid")
 	privs := c.GetStringSlice("prm")
 	scope := func(db *gorm.DB) *gorm.DB {
-		if !slices.Contains(privs, "roles.view'") {
-			return db.Where("role_id = ?", rid)
+		if !slices.Contains(privs, "roles.view") {
+			return db.Where("id = ?", rid)
 		}
 		return db
 	}
@@ -162,3 +167,315 @@ func (s *RoleService) GetRole(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, resp)
 }
+
+// GetPrivileges is a function to return the registry of privileges which can be granted to roles
+// @Summary Retrieve known privileges list
+// @Tags Roles
+// @Produce json
+// @Success 200 {object} response.successResp{data=[]string} "privileges list received successful"
+// @Failure 403 {object} response.errorResp "getting privileges not permitted"
+// @Router /roles/privileges [get]
+func (s *RoleService) GetPrivileges(c *gin.Context) {
+	privs := c.GetStringSlice("prm")
+	if !slices.Contains(privs, "roles.view") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	response.Success(c, http.StatusOK, models.Privileges)
+}
+
+// CreateRole is a function to create new role with the privileges
+// @Summary Create new role, the privileges must be granted to the current user
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param json body models.CreateRole true "role model to create"
+// @Success 201 {object} response.successResp{data=models.RolePrivileges} "role created successful"
+// @Failure 400 {object} response.errorResp "invalid role request data"
+// @Failure 403 {object} response.errorResp "creating role not permitted"
+// @Failure 500 {object} response.errorResp "internal error on creating role"
+// @Router /roles/ [post]
+func (s *RoleService) CreateRole(c *gin.Context) {
+	var (
+		err error
+		req models.CreateRole
+	)
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating role JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	}
+
+	if !slices.Contains(c.GetStringSlice("prm"), "roles.create") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	s.createRole(c, req.Name, req.Privileges)
+}
+
+// CloneRole is a function to create new role with the privileges of the existing one
+// @Summary Create new role with the privileges of the role by id
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param roleID path uint64 true "source role id"
+// @Param json body models.CloneRole true "role name to create"
+// @Success 201 {object} response.successResp{data=models.RolePrivileges} "role cloned successful"
+// @Failure 400 {object} response.errorResp "invalid role request data"
+// @Failure 403 {object} response.errorResp "cloning role not permitted"
+// @Failure 404 {object} response.errorResp "source role not found"
+// @Failure 500 {object} response.errorResp "internal error on cloning role"
+// @Router /roles/{roleID}/clone [post]
+func (s *RoleService) CloneRole(c *gin.Context) {
+	var (
+		err    error
+		req    models.CloneRole
+		roleID uint64
+		source models.RolePrivileges
+	)
+
+	if roleID, err = strconv.ParseUint(c.Param("roleID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing role id")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	}
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating role JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	}
+
+	if !slices.Contains(c.GetStringSlice("prm"), "roles.create") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if !s.findRole(c, roleID, &source) {
+		return
+	}
+
+	privs := make([]string, 0, len(source.Privileges))
+	for _, priv := range source.Privileges {
+		privs = append(privs, priv.Name)
+	}
+
+	s.createRole(c, req.Name, privs)
+}
+
+// PatchRole is a function to update role name and privileges
+// @Summary Update role, the privileges replace the current ones and must be granted to the current user
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param roleID path uint64 true "role id"
+// @Param json body models.PatchRole true "role model to update"
+// @Success 200 {object} response.successResp{data=models.RolePrivileges} "role updated successful"
+// @Failure 400 {object} response.errorResp "invalid role request data"
+// @Failure 403 {object} response.errorResp "updating role not permitted"
+// @Failure 404 {object} response.errorResp "role not found"
+// @Failure 500 {object} response.errorResp "internal error on updating role"
+// @Router /roles/{roleID} [put]
+func (s *RoleService) PatchRole(c *gin.Context) {
+	var (
+		err    error
+		req    models.PatchRole
+		resp   models.RolePrivileges
+		roleID uint64
+	)
+
+	if roleID, err = strconv.ParseUint(c.Param("roleID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing role id")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	}
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating role JSON")
+		response.Error(c, response.ErrRolesInvalidRequest, err)
+		return
+	}
+
+	if !slices.Contains(c.GetStringSlice("prm"), "roles.edit") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if !s.findRole(c, roleID, &resp) {
+		return
+	}
+
+	// the admin role must keep all privileges, and the names of built-in roles are used by the role rules
+	if roleID == models.RoleAdmin || (roleID == models.RoleUser && req.Name != resp.Name) {
+		logger.FromContext(c).Errorf("error updating built-in role '%d'", roleID)
+		response.Error(c, response.ErrRolesProtected, nil)
+		return
+	}
+
+	// both the current and the new privileges are checked, so the user can't revoke privileges
+	// from the role which has more privileges than the user has
+	currentPrivs := make([]string, 0, len(resp.Privileges))
+	for _, priv := range resp.Privileges {
+		currentPrivs = append(currentPrivs, priv.Name)
+	}
+	if !s.checkPrivileges(c, append(currentPrivs, req.Privileges...)) {
+		return
+	}
+
+	if req.Name != resp.Name && !s.checkRoleName(c, req.Name) {
+		return
+	}
+
+	before := resp.Name
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&resp.Role).UpdateColumn("name", req.Name).Error; err != nil {
+			return err
+		}
+		return setRolePrivileges(tx, roleID, req.Privileges)
+	})
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error updating role by id '%d'", roleID)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if !s.findRole(c, roleID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionRoleUpdate,
+		TargetType: audit.TargetRole,
+		TargetID:   int64(roleID),
+		TargetName: resp.Name,
+		Before:     models.PatchRole{Name: before, Privileges: currentPrivs},
+		After:      req,
+	})
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+func (s *RoleService) createRole(c *gin.Context, name string, privs []string) {
+	var resp models.RolePrivileges
+
+	if !s.checkPrivileges(c, privs) || !s.checkRoleName(c, name) {
+		return
+	}
+
+	resp.Name = name
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&resp.Role).Error; err != nil {
+			return err
+		}
+		return setRolePrivileges(tx, resp.ID, privs)
+	})
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error creating role '%s'", name)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if !s.findRole(c, resp.ID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionRoleCreate,
+		TargetType: audit.TargetRole,
+		TargetID:   int64(resp.ID),
+		TargetName: resp.Name,
+		After:      models.CreateRole{Name: name, Privileges: privs},
+	})
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+func (s *RoleService) findRole(c *gin.Context, roleID uint64, role *models.RolePrivileges) bool {
+	if err := s.db.Take(role, "id = ?", roleID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding role by id")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrRolesNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return false
+	}
+	if err := s.db.Model(role).Association("privileges").Find(&role.Privileges).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding role privileges by role model")
+		response.Error(c, response.ErrInternal, err)
+		return false
+	}
+	if err := role.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating role data '%d'", role.ID)
+		response.Error(c, response.ErrRolesInvalidData, err)
+		return false
+	}
+
+	return true
+}
+
+// checkPrivileges prevents the privilege escalation, the current user can grant only own privileges
+func (s *RoleService) checkPrivileges(c *gin.Context, privs []string) bool {
+	privsCurrentUser, err := s.users.GetUserPrivileges(c, c.GetUint64("rid"))
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error getting current user privileges")
+		response.Error(c, response.ErrInternal, err)
+		return false
+	}
+
+	if !s.users.CheckPrivilege(c, privsCurrentUser, privs) {
+		logger.FromContext(c).Errorf("error checking role privileges: not granted to current user")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return false
+	}
+
+	return true
+}
+
+func (s *RoleService) checkRoleName(c *gin.Context, name string) bool {
+	var count int
+	if err := s.db.Model(&models.Role{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error checking role name '%s'", name)
+		response.Error(c, response.ErrInternal, err)
+		return false
+	} else if count != 0 {
+		logger.FromContext(c).Errorf("error checking role name '%s': already exists", name)
+		response.Error(c, response.ErrRolesNameExists, nil)
+		return false
+	}
+
+	return true
+}
+
+func setRolePrivileges(tx *gorm.DB, roleID uint64, privs []string) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&models.Privilege{}).Error; err != nil {
+		return err
+	}
+	for _, name := range privs {
+		if err := tx.Create(&models.Privilege{RoleID: roleID, Name: name}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -90,7 +90,7 @@ func (s *SubtaskService) GetFlowSubtasks(c *gin.Context) {
 			return db.
 				Joins("INNER JOIN tasks t ON t.id = task_id").
 				Joins("INNER JOIN flows f ON f.id = t.flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -183,7 +183,7 @@ func (s *SubtaskService) GetFlowTaskSubtasks(c *gin.Context) {
 			return db.
 				Joins("INNER JOIN tasks t ON t.id = task_id").
 				Joins("INNER JOIN flows f ON f.id = t.flow_id").
-				Where("f.id = ? AND f.user_id = ? AND t.id = ?", flowID, uid, taskID)
+				Where("f.id = ? AND t.id = ?", flowID, taskID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -276,7 +276,7 @@ func (s *SubtaskService) GetFlowTaskSubtask(c *gin.Context) {
 			return db.
 				Joins("INNER JOIN tasks t ON t.id = task_id").
 				Joins("INNER JOIN flows f ON f.id = t.flow_id").
-				Where("f.id = ? AND f.user_id = ? AND t.id = ?", flowID, uid, taskID)
+				Where("f.id = ? AND t.id = ?", flowID, taskID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -0,0 +1,625 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/rdb"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type teams struct {
+	Teams []models.Team `json:"teams"`
+	Total uint64        `json:"total"`
+}
+
+var teamsSQLMappers = map[string]interface{}{
+	"id":          "{{table}}.id",
+	"name":        "{{table}}.name",
+	"description": "{{table}}.description",
+	"created_at":  "{{table}}.created_at",
+	"data":        "({{table}}.name || ' ' || {{table}}.description)",
+}
+
+type TeamService struct {
+	db    *gorm.DB
+	audit audit.Recorder
+}
+
+func NewTeamService(db *gorm.DB, audit audit.Recorder) *TeamService {
+	return &TeamService{
+		db:    db,
+		audit: audit,
+	}
+}
+
+// GetTeams is a function to return teams list
+// @Summary Retrieve teams list, the teams of the current user unless the user is admin
+// @Tags Teams
+// @Produce json
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=teams} "teams list received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 403 {object} response.errorResp "getting teams not permitted"
+// @Failure 500 {object} response.errorResp "internal error on getting teams"
+// @Router /teams/ [get]
+func (s *TeamService) GetTeams(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  teams
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	var scope func(db *gorm.DB) *gorm.DB
+	if slices.Contains(privs, "teams.admin") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db
+		}
+	} else if slices.Contains(privs, "teams.view") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("id IN (SELECT team_id FROM team_members WHERE user_id = ?)", uid)
+		}
+	} else {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	query.Init("teams", teamsSQLMappers)
+
+	if query.Group != "" {
+		logger.FromContext(c).Errorf("error grouping teams: not allowed")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.Teams, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding teams")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	for i := 0; i < len(resp.Teams); i++ {
+		if err = resp.Teams[i].Valid(); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error validating team data '%d'", resp.Teams[i].ID)
+			response.Error(c, response.ErrTeamsInvalidData, err)
+			return
+		}
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetTeam is a function to return team by id with the members
+// @Summary Retrieve team by id with the members
+// @Tags Teams
+// @Produce json
+// @Param teamID path int true "team id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.TeamMembers} "team received successful"
+// @Failure 400 {object} response.errorResp "invalid team request data"
+// @Failure 403 {object} response.errorResp "getting team not permitted"
+// @Failure 404 {object} response.errorResp "team not found"
+// @Failure 500 {object} response.errorResp "internal error on getting team"
+// @Router /teams/{teamID} [get]
+func (s *TeamService) GetTeam(c *gin.Context) {
+	var (
+		err    error
+		resp   models.TeamMembers
+		teamID uint64
+	)
+
+	if teamID, err = strconv.ParseUint(c.Param("teamID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing team id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if !s.checkTeamRole(c, "teams.view", teamID, models.TeamRoleViewer) || !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CreateTeam is a function to create new team
+// @Summary Create new team, the current user becomes the team admin
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param json body models.CreateTeam true "team model to create"
+// @Success 201 {object} response.successResp{data=models.TeamMembers} "team created successful"
+// @Failure 400 {object} response.errorResp "invalid team request data"
+// @Failure 403 {object} response.errorResp "creating team not permitted"
+// @Failure 500 {object} response.errorResp "internal error on creating team"
+// @Router /teams/ [post]
+func (s *TeamService) CreateTeam(c *gin.Context) {
+	var (
+		err  error
+		req  models.CreateTeam
+		resp models.TeamMembers
+	)
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating team JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if !slices.Contains(c.GetStringSlice("prm"), "teams.create") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if !s.checkTeamName(c, req.Name, 0) {
+		return
+	}
+
+	resp.Name = req.Name
+	resp.Description = req.Description
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&resp.Team).Error; err != nil {
+			return err
+		}
+		return setTeamMember(tx, resp.ID, c.GetUint64("uid"), models.TeamRoleAdmin)
+	})
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error creating team '%s'", req.Name)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if !s.findTeam(c, resp.ID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionTeamCreate,
+		TargetType: audit.TargetTeam,
+		TargetID:   int64(resp.ID),
+		TargetName: resp.Name,
+		After:      resp.Team,
+	})
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// PatchTeam is a function to update team name and description
+// @Summary Update team, it's permitted to the team admins
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param teamID path int true "team id" minimum(0)
+// @Param json body models.PatchTeam true "team model to update"
+// @Success 200 {object} response.successResp{data=models.TeamMembers} "team updated successful"
+// @Failure 400 {object} response.errorResp "invalid team request data"
+// @Failure 403 {object} response.errorResp "updating team not permitted"
+// @Failure 404 {object} response.errorResp "team not found"
+// @Failure 500 {object} response.errorResp "internal error on updating team"
+// @Router /teams/{teamID} [put]
+func (s *TeamService) PatchTeam(c *gin.Context) {
+	var (
+		err    error
+		req    models.PatchTeam
+		resp   models.TeamMembers
+		teamID uint64
+	)
+
+	if teamID, err = strconv.ParseUint(c.Param("teamID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing team id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating team JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if !s.checkTeamRole(c, "teams.edit", teamID, models.TeamRoleAdmin) || !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	if !s.checkTeamName(c, req.Name, teamID) {
+		return
+	}
+
+	before := resp.Team
+	err = s.db.Model(&resp.Team).Updates(map[string]interface{}{
+		"name":        req.Name,
+		"description": req.Description,
+	}).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error updating team by id '%d'", teamID)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionTeamUpdate,
+		TargetType: audit.TargetTeam,
+		TargetID:   int64(teamID),
+		TargetName: resp.Name,
+		Before:     before,
+		After:      resp.Team,
+	})
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// DeleteTeam is a function to delete team by id
+// @Summary Delete team by id, the team flows and resources are returned to their owners
+// @Tags Teams
+// @Produce json
+// @Param teamID path int true "team id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.TeamMembers} "team deleted successful"
+// @Failure 400 {object} response.errorResp "invalid team request data"
+// @Failure 403 {object} response.errorResp "deleting team not permitted"
+// @Failure 404 {object} response.errorResp "team not found"
+// @Failure 500 {object} response.errorResp "internal error on deleting team"
+// @Router /teams/{teamID} [delete]
+func (s *TeamService) DeleteTeam(c *gin.Context) {
+	var (
+		err    error
+		resp   models.TeamMembers
+		teamID uint64
+	)
+
+	if teamID, err = strconv.ParseUint(c.Param("teamID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing team id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if !s.checkTeamRole(c, "teams.delete", teamID, models.TeamRoleAdmin) || !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	if err = s.db.Delete(&resp.Team).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error deleting team by id '%d'", teamID)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionTeamDelete,
+		TargetType: audit.TargetTeam,
+		TargetID:   int64(teamID),
+		TargetName: resp.Name,
+		Before:     resp.Team,
+	})
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// SetTeamMember is a function to add the user to the team or to change the member role
+// @Summary Add the user to the team or change the member role, it's permitted to the team admins, new members are added only with users.edit privilege
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param teamID path int true "team id" minimum(0)
+// @Param userID path int true "user id" minimum(0)
+// @Param json body models.SetTeamMember true "team member role"
+// @Success 200 {object} response.successResp{data=models.TeamMembers} "team member set successful"
+// @Failure 400 {object} response.errorResp "invalid team member request data"
+// @Failure 403 {object} response.errorResp "setting team member not permitted"
+// @Failure 404 {object} response.errorResp "team or user not found"
+// @Failure 500 {object} response.errorResp "internal error on setting team member"
+// @Router /teams/{teamID}/members/{userID} [put]
+func (s *TeamService) SetTeamMember(c *gin.Context) {
+	var (
+		err    error
+		req    models.SetTeamMember
+		resp   models.TeamMembers
+		teamID uint64
+		userID uint64
+	)
+
+	if teamID, err = strconv.ParseUint(c.Param("teamID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing team id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if userID, err = strconv.ParseUint(c.Param("userID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing user id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if err = c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	} else if err = req.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating team member JSON")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if !s.checkTeamRole(c, "teams.edit", teamID, models.TeamRoleAdmin) || !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	var user models.User
+	if err = s.db.Take(&user, "id = ?", userID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding user by id '%d'", userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrUsersNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	if !s.checkNewMember(c, &resp, userID) {
+		return
+	}
+
+	if req.Role != models.TeamRoleAdmin && !s.checkLastAdmin(c, &resp, userID) {
+		return
+	}
+
+	if err = setTeamMember(s.db, teamID, userID, req.Role); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error setting team '%d' member '%d'", teamID, userID)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionTeamMemberSet,
+		TargetType: audit.TargetTeam,
+		TargetID:   int64(teamID),
+		TargetName: resp.Name,
+		After:      map[string]any{"user_id": userID, "user_mail": user.Mail, "role": req.Role},
+	})
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// DeleteTeamMember is a function to remove the user from the team
+// @Summary Remove the user from the team, it's permitted to the team admins and to the member itself
+// @Tags Teams
+// @Produce json
+// @Param teamID path int true "team id" minimum(0)
+// @Param userID path int true "user id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.TeamMembers} "team member removed successful"
+// @Failure 400 {object} response.errorResp "invalid team member request data"
+// @Failure 403 {object} response.errorResp "removing team member not permitted"
+// @Failure 404 {object} response.errorResp "team or team member not found"
+// @Failure 500 {object} response.errorResp "internal error on removing team member"
+// @Router /teams/{teamID}/members/{userID} [delete]
+func (s *TeamService) DeleteTeamMember(c *gin.Context) {
+	var (
+		err    error
+		resp   models.TeamMembers
+		teamID uint64
+		userID uint64
+	)
+
+	if teamID, err = strconv.ParseUint(c.Param("teamID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing team id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	if userID, err = strconv.ParseUint(c.Param("userID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing user id")
+		response.Error(c, response.ErrTeamsInvalidRequest, err)
+		return
+	}
+
+	// any member can leave the team
+	requiredRole := models.TeamRoleAdmin
+	if userID == c.GetUint64("uid") {
+		requiredRole = models.TeamRoleViewer
+	}
+
+	if !s.checkTeamRole(c, "teams.edit", teamID, requiredRole) || !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	if !s.checkLastAdmin(c, &resp, userID) {
+		return
+	}
+
+	result := s.db.Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.TeamMember{})
+	if result.Error != nil {
+		logger.FromContext(c).WithError(result.Error).Errorf("error removing team '%d' member '%d'", teamID, userID)
+		response.Error(c, response.ErrInternal, result.Error)
+		return
+	} else if result.RowsAffected == 0 {
+		logger.FromContext(c).Errorf("error removing team '%d' member '%d': not found", teamID, userID)
+		response.Error(c, response.ErrTeamsMemberNotFound, nil)
+		return
+	}
+
+	if !s.findTeam(c, teamID, &resp) {
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionTeamMemberRemove,
+		TargetType: audit.TargetTeam,
+		TargetID:   int64(teamID),
+		TargetName: resp.Name,
+		Before:     map[string]any{"user_id": userID},
+	})
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+func (s *TeamService) findTeam(c *gin.Context, teamID uint64, team *models.TeamMembers) bool {
+	if err := s.db.Take(&team.Team, "id = ?", teamID).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding team by id")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrTeamsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return false
+	}
+
+	team.Members = []models.TeamMemberUser{}
+	err := s.db.Table("team_members").
+		Select("team_members.*, users.name, users.mail").
+		Joins("INNER JOIN users ON users.id = team_members.user_id").
+		Where("team_members.team_id = ?", teamID).
+		Order("team_members.created_at ASC").
+		Scan(&team.Members).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding team members by team id")
+		response.Error(c, response.ErrInternal, err)
+		return false
+	}
+
+	if err := team.Valid(); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error validating team data '%d'", teamID)
+		response.Error(c, response.ErrTeamsInvalidData, err)
+		return false
+	}
+
+	return true
+}
+
+// checkTeamRole checks the privilege and the role of the current user in the team,
+// the admin privilege of the teams grants access to all of them
+func (s *TeamService) checkTeamRole(c *gin.Context, priv string, teamID uint64, required models.TeamRole) bool {
+	privs := c.GetStringSlice("prm")
+	if slices.Contains(privs, "teams.admin") {
+		return true
+	} else if !slices.Contains(privs, priv) {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return false
+	}
+
+	var member models.TeamMember
+	err := s.db.Take(&member, "team_id = ? AND user_id = ?", teamID, c.GetUint64("uid")).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(c).WithError(err).Errorf("error finding team member")
+		response.Error(c, response.ErrInternal, err)
+		return false
+	} else if err != nil {
+		// the teams of other users are hidden from the user
+		logger.FromContext(c).Errorf("error finding team by id '%d': user isn't a member", teamID)
+		response.Error(c, response.ErrTeamsNotFound, nil)
+		return false
+	}
+
+	if !member.Role.Allows(required) {
+		logger.FromContext(c).Errorf("error checking team role: '%s' role is required", required)
+		response.Error(c, response.ErrNotPermitted, nil)
+		return false
+	}
+
+	return true
+}
+
+// checkNewMember lets only the users managers add the users to the team without their consent,
+// the other team admins can change roles of the existing members only
+func (s *TeamService) checkNewMember(c *gin.Context, team *models.TeamMembers, userID uint64) bool {
+	for _, member := range team.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+
+	privs := c.GetStringSlice("prm")
+	if slices.Contains(privs, "teams.admin") || slices.Contains(privs, "users.edit") {
+		return true
+	}
+
+	logger.FromContext(c).Errorf("error adding user '%d' to the team: 'users.edit' permission is required", userID)
+	response.Error(c, response.ErrNotPermitted, nil)
+	return false
+}
+
+// checkLastAdmin prevents the team from losing the last admin when the member is removed or demoted
+func (s *TeamService) checkLastAdmin(c *gin.Context, team *models.TeamMembers, userID uint64) bool {
+	admins := 0
+	isAdmin := false
+	for _, member := range team.Members {
+		if member.Role == models.TeamRoleAdmin {
+			admins++
+			isAdmin = isAdmin || member.UserID == userID
+		}
+	}
+
+	if isAdmin && admins == 1 {
+		logger.FromContext(c).Errorf("error changing team '%d' member '%d': last admin", team.ID, userID)
+		response.Error(c, response.ErrTeamsLastAdmin, nil)
+		return false
+	}
+
+	return true
+}
+
+func (s *TeamService) checkTeamName(c *gin.Context, name string, teamID uint64) bool {
+	var count int
+	err := s.db.Model(&models.Team{}).Where("LOWER(name) = LOWER(?) AND id <> ?", name, teamID).Count(&count).Error
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error checking team name '%s'", name)
+		response.Error(c, response.ErrInternal, err)
+		return false
+	} else if count != 0 {
+		logger.FromContext(c).Errorf("error checking team name '%s': already exists", name)
+		response.Error(c, response.ErrTeamsNameExists, nil)
+		return false
+	}
+
+	return true
+}
+
+func setTeamMember(db *gorm.DB, teamID, userID uint64, role models.TeamRole) error {
+	return db.Exec(`INSERT INTO team_members (team_id, user_id, role) VALUES (?, ?, ?)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role`, teamID, userID, role).Error
+}
+
+// teamAccessScope returns the scope to find the records of the user and the records shared with the teams
+// where the user has the required role or a higher one, the table is the name or the alias of the records table
+func teamAccessScope(table string, uid uint64, required models.TeamRole) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			fmt.Sprintf(
+				"(%[1]s.user_id = ? OR %[1]s.team_id IN (SELECT team_id FROM team_members WHERE user_id = ? AND role IN (?)))",
+				table,
+			),
+			uid, uid, models.TeamRolesAllowing(required),
+		)
+	}
+}
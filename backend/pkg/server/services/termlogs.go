@@ -79,7 +79,7 @@ func (s *TermlogService) GetTermlogs(c *gin.Context) {
 			return db.
 				Joins("INNER JOIN containers c ON c.id = container_id").
 				Joins("INNER JOIN flows f ON f.id = c.flow_id").
-				Where("f.user_id = ?", uid)
+				Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
@@ -164,7 +164,7 @@ func (s *TermlogService) GetFlowTermlogs(c *gin.Context) {
 			return db.
 				Joins("INNER JOIN containers c ON c.id = container_id").
 				Joins("INNER JOIN flows f ON f.id = c.flow_id").
-				Where("f.id = ? AND f.user_id = ?", flowID, uid)
+				Where("f.id = ?", flowID).Scopes(teamAccessScope("f", uid, models.TeamRoleViewer))
 		}
 	} else {
 		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
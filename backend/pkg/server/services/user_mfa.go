@@ -0,0 +1,432 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/auth"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+var errMFAAlreadyEnabled = errors.New("second factor is already enabled")
+
+type MFAService struct {
+	db       *gorm.DB
+	throttle *auth.LoginThrottle
+	audit    audit.Recorder
+}
+
+func NewMFAService(db *gorm.DB, throttle *auth.LoginThrottle, audit audit.Recorder) *MFAService {
+	return &MFAService{
+		db:       db,
+		throttle: throttle,
+		audit:    audit,
+	}
+}
+
+// GetMFA is a function to return second factor state of the current user
+// @Summary Retrieve second factor state of the current user
+// @Tags MFA
+// @Produce json
+// @Success 200 {object} response.successResp{data=models.MFAStatus} "second factor state received successful"
+// @Failure 403 {object} response.errorResp "getting second factor state not permitted"
+// @Failure 500 {object} response.errorResp "internal error on getting second factor state"
+// @Router /user/mfa [get]
+func (s *MFAService) GetMFA(c *gin.Context) {
+	var (
+		mfa  models.UserMFA
+		resp models.MFAStatus
+	)
+
+	resp.Required = slices.Contains(c.GetStringSlice("prm"), auth.PrivilegeMFARequired)
+
+	err := s.db.Take(&mfa, "user_id = ?", c.GetUint64("uid")).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		logger.FromContext(c).WithError(err).Errorf("error getting second factor of current user")
+		response.Error(c, response.ErrInternal, err)
+		return
+	} else if err == nil && mfa.Confirmed() {
+		resp.Enabled = true
+		resp.ConfirmedAt = mfa.ConfirmedAt
+		resp.RecoveryCodesLeft = len(mfa.RecoveryCodes)
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// EnrollMFA is a function to start the second factor enrollment of the current user
+// @Summary Create TOTP secret and recovery codes, the second factor is enabled after the confirmation
+// @Tags MFA
+// @Produce json
+// @Success 201 {object} response.successResp{data=models.MFAEnrollment} "second factor enrollment created successful"
+// @Failure 400 {object} response.errorResp "second factor is already enabled"
+// @Failure 403 {object} response.errorResp "enrolling second factor not permitted"
+// @Failure 500 {object} response.errorResp "internal error on enrolling second factor"
+// @Router /user/mfa [post]
+func (s *MFAService) EnrollMFA(c *gin.Context) {
+	user, ok := s.currentUser(c)
+	if !ok {
+		return
+	}
+
+	resp, err := enrollUserMFA(s.db, user)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error enrolling second factor of user '%s'", user.Hash)
+		if errors.Is(err, errMFAAlreadyEnabled) {
+			response.Error(c, response.ErrMFAAlreadyEnabled, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// ConfirmMFA is a function to enable the enrolled second factor by the first valid TOTP code
+// @Summary Confirm second factor enrollment of the current user
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param json body models.MFACode true "TOTP code from the authenticator app"
+// @Success 200 {object} response.successResp{data=models.MFAStatus} "second factor enabled successful"
+// @Failure 400 {object} response.errorResp "invalid code data or second factor is not enrolled"
+// @Failure 401 {object} response.errorResp "invalid code"
+// @Failure 403 {object} response.errorResp "confirming second factor not permitted"
+// @Failure 429 {object} response.errorResp "too many failed attempts"
+// @Failure 500 {object} response.errorResp "internal error on confirming second factor"
+// @Router /user/mfa/confirm [post]
+func (s *MFAService) ConfirmMFA(c *gin.Context) {
+	user, mfa, ok := s.verifyCurrentUser(c, false)
+	if !ok {
+		return
+	}
+
+	if err := confirmUserMFA(s.db, &mfa); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error confirming second factor of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionMFAEnable,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+
+	response.Success(c, http.StatusOK, models.MFAStatus{
+		Enabled:           true,
+		Required:          slices.Contains(c.GetStringSlice("prm"), auth.PrivilegeMFARequired),
+		ConfirmedAt:       mfa.ConfirmedAt,
+		RecoveryCodesLeft: len(mfa.RecoveryCodes),
+	})
+}
+
+// DisableMFA is a function to disable the second factor of the current user
+// @Summary Disable second factor of the current user, the role may require to enroll it again on next login
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param json body models.MFACode true "TOTP code or recovery code"
+// @Success 200 {object} response.successResp "second factor disabled successful"
+// @Failure 400 {object} response.errorResp "invalid code data or second factor is not enrolled"
+// @Failure 401 {object} response.errorResp "invalid code"
+// @Failure 403 {object} response.errorResp "disabling second factor not permitted"
+// @Failure 429 {object} response.errorResp "too many failed attempts"
+// @Failure 500 {object} response.errorResp "internal error on disabling second factor"
+// @Router /user/mfa/disable [post]
+func (s *MFAService) DisableMFA(c *gin.Context) {
+	user, mfa, ok := s.verifyCurrentUser(c, true)
+	if !ok {
+		return
+	}
+
+	if err := s.db.Delete(&mfa).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error disabling second factor of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionMFADisable,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+
+	response.Success(c, http.StatusOK, struct{}{})
+}
+
+// RegenerateRecoveryCodes is a function to replace the recovery codes of the current user
+// @Summary Replace recovery codes of the current user, the new codes are returned only in this response
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Param json body models.MFACode true "TOTP code or recovery code"
+// @Success 200 {object} response.successResp{data=models.MFARecoveryCodes} "recovery codes replaced successful"
+// @Failure 400 {object} response.errorResp "invalid code data or second factor is not enrolled"
+// @Failure 401 {object} response.errorResp "invalid code"
+// @Failure 403 {object} response.errorResp "replacing recovery codes not permitted"
+// @Failure 429 {object} response.errorResp "too many failed attempts"
+// @Failure 500 {object} response.errorResp "internal error on replacing recovery codes"
+// @Router /user/mfa/recovery_codes [post]
+func (s *MFAService) RegenerateRecoveryCodes(c *gin.Context) {
+	user, mfa, ok := s.verifyCurrentUser(c, true)
+	if !ok {
+		return
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error generating recovery codes")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	if err = s.db.Model(&mfa).UpdateColumn("recovery_codes", pq.StringArray(hashes)).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error updating recovery codes of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionMFARecoveryCodes,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+
+	response.Success(c, http.StatusOK, models.MFARecoveryCodes{RecoveryCodes: codes})
+}
+
+// ResetUserMFA is a function to disable the second factor of the user who lost the device and the recovery codes
+// @Summary Disable second factor of the user by hash
+// @Tags MFA
+// @Produce json
+// @Param hash path string true "hash in hex format (md5)" minlength(32) maxlength(32)
+// @Success 200 {object} response.successResp "second factor disabled successful"
+// @Failure 403 {object} response.errorResp "disabling second factor not permitted"
+// @Failure 404 {object} response.errorResp "user or second factor not found"
+// @Failure 500 {object} response.errorResp "internal error on disabling second factor"
+// @Router /users/{hash}/mfa [delete]
+func (s *MFAService) ResetUserMFA(c *gin.Context) {
+	var (
+		err  error
+		hash = c.Param("hash")
+		user models.User
+	)
+
+	if !slices.Contains(c.GetStringSlice("prm"), "mfa.admin") {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = s.db.Take(&user, "hash = ?", hash).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding user by hash")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrUsersNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	result := s.db.Where("user_id = ?", user.ID).Delete(&models.UserMFA{})
+	if result.Error != nil {
+		logger.FromContext(c).WithError(result.Error).Errorf("error disabling second factor of user '%s'", hash)
+		response.Error(c, response.ErrInternal, result.Error)
+		return
+	} else if result.RowsAffected == 0 {
+		logger.FromContext(c).Errorf("error finding second factor of user '%s'", hash)
+		response.Error(c, response.ErrMFANotFound, nil)
+		return
+	}
+
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionMFADisable,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+
+	response.Success(c, http.StatusOK, struct{}{})
+}
+
+func (s *MFAService) currentUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+	if err := s.db.Take(&user, "id = ?", c.GetUint64("uid")).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding current user")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrUsersNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return user, false
+	}
+
+	return user, true
+}
+
+// verifyCurrentUser checks the code of the current user second factor, the failed attempts are counted
+// by the login throttle, so the session can't be used to guess the codes
+func (s *MFAService) verifyCurrentUser(c *gin.Context, confirmed bool) (models.User, models.UserMFA, bool) {
+	var (
+		data models.MFACode
+		mfa  models.UserMFA
+	)
+
+	if err := c.ShouldBindJSON(&data); err != nil || data.Valid() != nil {
+		if err == nil {
+			err = data.Valid()
+		}
+		logger.FromContext(c).WithError(err).Errorf("error binding JSON")
+		response.Error(c, response.ErrMFAInvalidRequest, err)
+		return models.User{}, mfa, false
+	}
+
+	user, ok := s.currentUser(c)
+	if !ok {
+		return user, mfa, false
+	}
+
+	left, err := s.throttle.Locked(user.Mail, "")
+	if err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error checking login throttle of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return user, mfa, false
+	} else if left > 0 {
+		logger.FromContext(c).Errorf("second factor of user '%s' is locked for %s", user.Hash, left)
+		response.Error(c, response.ErrAuthTooManyAttempts, nil)
+		return user, mfa, false
+	}
+
+	if err = s.db.Take(&mfa, "user_id = ?", user.ID).Error; err != nil || mfa.Confirmed() != confirmed {
+		if err != nil && !gorm.IsRecordNotFoundError(err) {
+			logger.FromContext(c).WithError(err).Errorf("error getting second factor of user '%s'", user.Hash)
+			response.Error(c, response.ErrInternal, err)
+		} else if confirmed || err != nil {
+			logger.FromContext(c).Errorf("error finding enrolled second factor of user '%s'", user.Hash)
+			response.Error(c, response.ErrMFANotEnrolled, err)
+		} else {
+			logger.FromContext(c).Errorf("second factor of user '%s' is already enabled", user.Hash)
+			response.Error(c, response.ErrMFAAlreadyEnabled, nil)
+		}
+		return user, mfa, false
+	}
+
+	if ok, err = verifyUserMFACode(s.db, &mfa, data.Code); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error verifying second factor of user '%s'", user.Hash)
+		response.Error(c, response.ErrInternal, err)
+		return user, mfa, false
+	} else if !ok {
+		logger.FromContext(c).Errorf("error matching second factor code of user '%s'", user.Hash)
+		if _, err := s.throttle.Fail(user.Mail, ""); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error counting failed attempt of user '%s'", user.Hash)
+		}
+		response.Error(c, response.ErrMFAInvalidCode, nil)
+		return user, mfa, false
+	}
+
+	return user, mfa, true
+}
+
+// enrollUserMFA creates the new unconfirmed second factor of the user, the previous unconfirmed
+// enrollment is replaced while the confirmed one is kept
+func enrollUserMFA(db *gorm.DB, user models.User) (models.MFAEnrollment, error) {
+	var resp models.MFAEnrollment
+
+	if user.Type != models.UserTypeLocal {
+		return resp, errors.New("second factor is available for local users only")
+	}
+
+	secret, url, qrCode, err := auth.GenerateMFAKey(user.Mail)
+	if err != nil {
+		return resp, err
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return resp, err
+	}
+
+	result := db.Exec(`
+		INSERT INTO user_mfa (user_id, secret, recovery_codes) VALUES (?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+		  secret = EXCLUDED.secret,
+		  recovery_codes = EXCLUDED.recovery_codes,
+		  last_used_step = 0
+		WHERE user_mfa.confirmed_at IS NULL`, user.ID, secret, pq.StringArray(hashes))
+	if result.Error != nil {
+		return resp, result.Error
+	} else if result.RowsAffected == 0 {
+		return resp, errMFAAlreadyEnabled
+	}
+
+	resp.Secret = secret
+	resp.URL = url
+	resp.QRCode = qrCode
+	resp.RecoveryCodes = codes
+
+	return resp, nil
+}
+
+// confirmUserMFA enables the enrolled second factor, it's enforced on the next logins
+func confirmUserMFA(db *gorm.DB, mfa *models.UserMFA) error {
+	now := time.Now()
+	if err := db.Model(mfa).UpdateColumn("confirmed_at", now).Error; err != nil {
+		return err
+	}
+	mfa.ConfirmedAt = &now
+
+	return nil
+}
+
+// verifyUserMFACode checks the TOTP code or spends the recovery code of the confirmed second factor,
+// the conditional updates keep each code single-use when the same code is sent concurrently
+func verifyUserMFACode(db *gorm.DB, mfa *models.UserMFA, code string) (bool, error) {
+	if auth.IsMFACode(code) {
+		step, ok := auth.ValidateMFACode(mfa.Secret, code, time.Now(), mfa.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+
+		result := db.Model(&models.UserMFA{}).
+			Where("user_id = ? AND last_used_step < ?", mfa.UserID, step).
+			UpdateColumn("last_used_step", step)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		mfa.LastUsedStep = step
+
+		return result.RowsAffected == 1, nil
+	}
+
+	// recovery codes replace the authenticator app only after it was confirmed
+	if !mfa.Confirmed() {
+		return false, nil
+	}
+
+	hash := auth.HashRecoveryCode(code)
+	result := db.Model(&models.UserMFA{}).
+		Where("user_id = ? AND ? = ANY(recovery_codes)", mfa.UserID, hash).
+		UpdateColumn("recovery_codes", gorm.Expr("array_remove(recovery_codes, ?)", hash))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if idx := slices.Index(mfa.RecoveryCodes, hash); idx >= 0 {
+		mfa.RecoveryCodes = slices.Delete(mfa.RecoveryCodes, idx, idx+1)
+	}
+
+	return result.RowsAffected == 1, nil
+}
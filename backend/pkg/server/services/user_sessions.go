@@ -0,0 +1,190 @@
+package services
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"pentagi/pkg/audit"
+	"pentagi/pkg/server/logger"
+	"pentagi/pkg/server/models"
+	"pentagi/pkg/server/rdb"
+	"pentagi/pkg/server/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type userSessions struct {
+	Sessions []models.UserSession `json:"sessions"`
+	Total    uint64               `json:"total"`
+}
+
+var userSessionsSQLMappers = map[string]interface{}{
+	"id":           "{{table}}.id",
+	"user_id":      "{{table}}.user_id",
+	"method":       "{{table}}.method",
+	"remote_addr":  "{{table}}.remote_addr",
+	"user_agent":   "{{table}}.user_agent",
+	"expires_at":   "{{table}}.expires_at",
+	"last_seen_at": "{{table}}.last_seen_at",
+	"revoked_at":   "{{table}}.revoked_at",
+	"created_at":   "{{table}}.created_at",
+	"data":         "({{table}}.method || ' ' || {{table}}.remote_addr || ' ' || {{table}}.user_agent)",
+}
+
+type SessionService struct {
+	db    *gorm.DB
+	audit audit.Recorder
+}
+
+func NewSessionService(db *gorm.DB, audit audit.Recorder) *SessionService {
+	return &SessionService{
+		db:    db,
+		audit: audit,
+	}
+}
+
+// GetSessions is a function to return login sessions list
+// @Summary Retrieve login sessions list, the session of the current request is marked as current
+// @Tags Sessions
+// @Produce json
+// @Param request query rdb.TableQuery true "query table params"
+// @Success 200 {object} response.successResp{data=userSessions} "sessions list received successful"
+// @Failure 400 {object} response.errorResp "invalid query request data"
+// @Failure 403 {object} response.errorResp "getting sessions not permitted"
+// @Failure 500 {object} response.errorResp "internal error on getting sessions"
+// @Router /sessions/ [get]
+func (s *SessionService) GetSessions(c *gin.Context) {
+	var (
+		err   error
+		query rdb.TableQuery
+		resp  userSessions
+	)
+
+	if err = c.ShouldBindQuery(&query); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error binding query")
+		response.Error(c, response.ErrSessionsInvalidRequest, err)
+		return
+	}
+
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	var scope func(db *gorm.DB) *gorm.DB
+	if slices.Contains(privs, "sessions.admin") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db
+		}
+	} else if slices.Contains(privs, "sessions.view") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("user_id = ?", uid)
+		}
+	} else {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	query.Init("user_sessions", userSessionsSQLMappers)
+
+	if query.Group != "" {
+		logger.FromContext(c).Errorf("error grouping sessions: not allowed")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if resp.Total, err = query.Query(s.db, &resp.Sessions, scope); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding sessions")
+		response.Error(c, response.ErrInternal, err)
+		return
+	}
+
+	sid := c.GetUint64("sid")
+	for i := 0; i < len(resp.Sessions); i++ {
+		if err = resp.Sessions[i].Valid(); err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error validating session data '%d'", resp.Sessions[i].ID)
+			response.Error(c, response.ErrSessionsInvalidData, err)
+			return
+		}
+		resp.Sessions[i].Current = sid != 0 && resp.Sessions[i].ID == sid
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// RevokeSession is a function to revoke login session by id
+// @Summary Revoke login session by id, the revoked session is kept in the list
+// @Tags Sessions
+// @Produce json
+// @Param sessionID path int true "session id" minimum(0)
+// @Success 200 {object} response.successResp{data=models.UserSession} "session revoked successful"
+// @Failure 400 {object} response.errorResp "invalid session request data"
+// @Failure 403 {object} response.errorResp "revoking session not permitted"
+// @Failure 404 {object} response.errorResp "session not found"
+// @Failure 500 {object} response.errorResp "internal error on revoking session"
+// @Router /sessions/{sessionID} [delete]
+func (s *SessionService) RevokeSession(c *gin.Context) {
+	var (
+		err       error
+		sessionID uint64
+		resp      models.UserSession
+	)
+
+	if sessionID, err = strconv.ParseUint(c.Param("sessionID"), 10, 64); err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error parsing session id")
+		response.Error(c, response.ErrSessionsInvalidRequest, err)
+		return
+	}
+
+	uid := c.GetUint64("uid")
+	privs := c.GetStringSlice("prm")
+	var scope func(db *gorm.DB) *gorm.DB
+	if slices.Contains(privs, "sessions.admin") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ?", sessionID)
+		}
+	} else if slices.Contains(privs, "sessions.delete") {
+		scope = func(db *gorm.DB) *gorm.DB {
+			return db.Where("id = ? AND user_id = ?", sessionID, uid)
+		}
+	} else {
+		logger.FromContext(c).Errorf("error filtering user role permissions: permission not found")
+		response.Error(c, response.ErrNotPermitted, nil)
+		return
+	}
+
+	if err = s.db.Model(&resp).Scopes(scope).Take(&resp).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error on getting session by id")
+		if gorm.IsRecordNotFoundError(err) {
+			response.Error(c, response.ErrSessionsNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
+	if resp.RevokedAt == nil {
+		err = s.db.Model(&resp).UpdateColumn("revoked_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+		if err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error revoking session by id '%d'", sessionID)
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+
+		if err = s.db.Scopes(scope).Take(&resp).Error; err != nil {
+			logger.FromContext(c).WithError(err).Errorf("error finding revoked session by id '%d'", sessionID)
+			response.Error(c, response.ErrInternal, err)
+			return
+		}
+
+		s.audit.Record(auditContext(c), audit.Event{
+			Action:     audit.ActionSessionRevoke,
+			TargetType: audit.TargetSession,
+			TargetID:   int64(resp.ID),
+			TargetName: resp.Method,
+		})
+	}
+
+	resp.Current = resp.ID == c.GetUint64("sid")
+	response.Success(c, http.StatusOK, resp)
+}
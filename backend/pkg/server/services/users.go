@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"slices"
 
+	"pentagi/pkg/audit"
 	"pentagi/pkg/server/logger"
 	"pentagi/pkg/server/models"
 	"pentagi/pkg/server/rdb"
@@ -37,12 +38,14 @@ var usersSQLMappers = map[string]interface{}{
 }
 
 type UserService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	audit audit.Recorder
 }
 
-func NewUserService(db *gorm.DB) *UserService {
+func NewUserService(db *gorm.DB, audit audit.Recorder) *UserService {
 	return &UserService{
-		db: db,
+		db:    db,
+		audit: audit,
 	}
 }
 
@@ -150,6 +153,13 @@ func (s *UserService) ChangePasswordCurrentUser(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionPasswordChange,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+	})
+
 	response.Success(c, http.StatusOK, struct{}{})
 }
 
@@ -377,6 +387,14 @@ func (s *UserService) CreateUser(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionUserCreate,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(resp.ID),
+		TargetName: resp.Mail,
+		After:      resp.User,
+	})
+
 	response.Success(c, http.StatusCreated, resp)
 }
 
@@ -395,10 +413,11 @@ func (s *UserService) CreateUser(c *gin.Context) {
 // @Router /users/{hash} [put]
 func (s *UserService) PatchUser(c *gin.Context) {
 	var (
-		err  error
-		hash = c.Param("hash")
-		resp models.UserRole
-		user models.UserPassword
+		err    error
+		hash   = c.Param("hash")
+		before models.User
+		resp   models.UserRole
+		user   models.UserPassword
 	)
 
 	if err = c.ShouldBindJSON(&user); err != nil {
@@ -435,6 +454,16 @@ func (s *UserService) PatchUser(c *gin.Context) {
 		return
 	}
 
+	if err = s.db.Scopes(scope).Take(&before).Error; err != nil {
+		logger.FromContext(c).WithError(err).Errorf("error finding user by hash")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, response.ErrUsersNotFound, err)
+		} else {
+			response.Error(c, response.ErrInternal, err)
+		}
+		return
+	}
+
 	public_info := []interface{}{"name", "status"}
 	if user.Password != "" {
 		var encPassword []byte
@@ -486,6 +515,24 @@ func (s *UserService) PatchUser(c *gin.Context) {
 		return
 	}
 
+	ctx := auditContext(c)
+	s.audit.Record(ctx, audit.Event{
+		Action:     audit.ActionUserUpdate,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(resp.ID),
+		TargetName: resp.Mail,
+		Before:     before,
+		After:      resp.User,
+	})
+	if user.Password != "" {
+		s.audit.Record(ctx, audit.Event{
+			Action:     audit.ActionPasswordChange,
+			TargetType: audit.TargetUser,
+			TargetID:   int64(resp.ID),
+			TargetName: resp.Mail,
+		})
+	}
+
 	response.Success(c, http.StatusOK, resp)
 }
 
@@ -552,6 +599,14 @@ func (s *UserService) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	s.audit.Record(auditContext(c), audit.Event{
+		Action:     audit.ActionUserDelete,
+		TargetType: audit.TargetUser,
+		TargetID:   int64(user.ID),
+		TargetName: user.Mail,
+		Before:     user.User,
+	})
+
 	response.Success(c, http.StatusOK, struct{}{})
 }
 
@@ -35,8 +35,9 @@ type Browser struct {
 }
 
 type SubtaskInfo struct {
-	Title       string `json:"title" jsonschema:"required,title=Subtask title" jsonschema_description:"Subtask title to show to the user which contains main goal of work result by this subtask"`
-	Description string `json:"description" jsonschema:"required,title=Subtask to complete" jsonschema_description:"Detailed description and instructions and rules and requirements what have to do in the subtask"`
+	Title       string  `json:"title" jsonschema:"required,title=Subtask title" jsonschema_description:"Subtask title to show to the user which contains main goal of work result by this subtask"`
+	Description string  `json:"description" jsonschema:"required,title=Subtask to complete" jsonschema_description:"Detailed description and instructions and rules and requirements what have to do in the subtask"`
+	DependsOn   []int64 `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema:"title=Subtask dependencies" jsonschema_description:"Subtasks which results are required to start this one: 1-based positions of previous subtasks in the generated list or IDs of existing subtasks in the refined plan, empty list means that the subtask is independent and can be executed in parallel with others"`
 }
 
 type SubtaskList struct {
@@ -61,6 +62,7 @@ type SubtaskOperation struct {
 	AfterID     *int64               `json:"after_id,omitempty" jsonschema:"title=Insert after ID" jsonschema_description:"For add/reorder: insert after this subtask ID (null/0 = insert at beginning)"`
 	Title       string               `json:"title,omitempty" jsonschema:"title=New title" jsonschema_description:"New title (required for add, optional for modify)"`
 	Description string               `json:"description,omitempty" jsonschema:"title=New description" jsonschema_description:"New description (required for add, optional for modify)"`
+	DependsOn   []int64              `json:"depends_on,omitempty" jsonschema:"title=New dependencies" jsonschema_description:"For add/modify: IDs of existing subtasks which must be completed before this one (omit to keep current dependencies, empty list to make the subtask independent)"`
 }
 
 type SubtaskInfoPatch struct {
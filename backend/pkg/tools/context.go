@@ -2,13 +2,19 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"path"
 
 	"pentagi/pkg/database"
+	"pentagi/pkg/docker"
 )
 
 type AgentContextKey int
 
-var agentContextKey AgentContextKey
+const (
+	agentContextKey AgentContextKey = iota
+	workDirContextKey
+)
 
 type agentContext struct {
 	ParentAgentType  database.MsgchainType `json:"parent_agent_type"`
@@ -32,3 +38,32 @@ func PutAgentContext(ctx context.Context, agent database.MsgchainType) context.C
 
 	return context.WithValue(ctx, agentContextKey, agentCtx)
 }
+
+// SubtaskWorkDir returns the separate working directory in the primary container for the subtask
+// which is executed in parallel with others, so the subtasks don't clobber files of each other
+func SubtaskWorkDir(subtaskID int64) string {
+	return path.Join(docker.WorkFolderPathInContainer, fmt.Sprintf("subtask-%d", subtaskID))
+}
+
+// GetWorkDir returns the default working directory for the terminal commands and agents prompts
+func GetWorkDir(ctx context.Context) string {
+	if workDir, ok := ctx.Value(workDirContextKey).(string); ok && workDir != "" {
+		return workDir
+	}
+
+	return docker.WorkFolderPathInContainer
+}
+
+// ResolveWorkPath returns the absolute path in the container, relative paths are resolved against
+// the working directory from the context to keep files of parallel subtasks apart
+func ResolveWorkPath(ctx context.Context, p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+
+	return path.Join(GetWorkDir(ctx), p)
+}
+
+func PutWorkDir(ctx context.Context, workDir string) context.Context {
+	return context.WithValue(ctx, workDirContextKey, workDir)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/html"
@@ -118,7 +119,9 @@ func (d *duckduckgo) Handle(ctx context.Context, name string, args json.RawMessa
 	})
 
 	// Perform search
+	start := time.Now()
 	result, err := d.search(ctx, action.Query, numResults)
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypeDuckduckgo), time.Since(start), err)
 	if err != nil {
 		logger.WithError(err).Error("failed to search in DuckDuckGo")
 		return fmt.Sprintf("failed to search in DuckDuckGo: %v", err), nil
@@ -8,8 +8,10 @@ import (
 	"slices"
 	"strings"
 	"text/template"
+	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 	"pentagi/pkg/schema"
 
 	"github.com/vxcontrol/langchaingo/documentloaders"
@@ -96,7 +98,9 @@ func (ce *customExecutor) Execute(
 
 	wrapHandler := func(ctx context.Context, name string, args json.RawMessage) (string, database.MsglogResultFormat, error) {
 		resultFormat := getMessageResultFormat(name)
+		start := time.Now()
 		result, err := handler(ctx, name, args)
+		obs.Observer.RecordToolCall(ctx, name, time.Since(start), err)
 		if err != nil {
 			_, _ = ce.db.UpdateToolcallFailedResult(ctx, database.UpdateToolcallFailedResultParams{
 				Result: fmt.Sprintf("failed to execute handler: %s", err.Error()),
@@ -112,7 +116,9 @@ func (ce *customExecutor) Execute(
 			if err != nil {
 				return "", resultFormat, fmt.Errorf("failed to get summarize prompt: %w", err)
 			}
+			start = time.Now()
 			result, err = ce.summarizer(ctx, summarizePrompt)
+			obs.Observer.RecordSummarization(ctx, obs.SummarizationKindToolResult, time.Since(start), err)
 			if err != nil {
 				_, _ = ce.db.UpdateToolcallFailedResult(ctx, database.UpdateToolcallFailedResultParams{
 					Result: fmt.Sprintf("failed to summarize result: %s", err.Error()),
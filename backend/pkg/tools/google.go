@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 	customsearch "google.golang.org/api/customsearch/v1"
@@ -82,7 +84,9 @@ func (g *google) Handle(ctx context.Context, name string, args json.RawMessage)
 		return "", err
 	}
 
+	start := time.Now()
 	resp, err := svc.Cse.List().Context(ctx).Cx(g.cxKey).Q(action.Query).Lr(g.lrKey).Num(numResults).Do()
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypeGoogle), time.Since(start), err)
 	if err != nil {
 		logger.WithError(err).Error("failed to call tool to search in google results")
 		return fmt.Sprintf("failed to call tool %s to search in google results: %v", name, err), nil
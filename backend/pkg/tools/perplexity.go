@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 )
@@ -153,7 +154,9 @@ func (t *perplexity) Handle(ctx context.Context, name string, args json.RawMessa
 		"max_results": action.MaxResults,
 	})
 
+	start := time.Now()
 	result, err := t.search(ctx, action.Query)
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypePerplexity), time.Since(start), err)
 	if err != nil {
 		logger.WithError(err).Error("failed to search in perplexity")
 		return fmt.Sprintf("failed to search in perplexity: %v", err), nil
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 )
@@ -108,7 +109,9 @@ func (s *SearxngTool) Handle(ctx context.Context, name string, args json.RawMess
 	}
 
 	// Perform the search
+	start := time.Now()
 	results, err := s.performSearxngSearch(ctx, searchArgs.Query, searchArgs.MaxResults.Int())
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypeSearxng), time.Since(start), err)
 	if err != nil {
 		// Update search log with error
 		if searchLogID > 0 {
@@ -9,8 +9,10 @@ import (
 	"net/url"
 	"strings"
 	"text/template"
+	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 )
@@ -88,7 +90,9 @@ func (t *tavily) Handle(ctx context.Context, name string, args json.RawMessage)
 		"max_results": action.MaxResults,
 	})
 
+	start := time.Now()
 	result, err := t.search(ctx, action.Query, action.MaxResults.Int())
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypeTavily), time.Since(start), err)
 	if err != nil {
 		logger.WithError(err).Error("failed to search in tavily")
 		return fmt.Sprintf("failed to search in tavily: %v", err), nil
@@ -128,8 +128,14 @@ func (t *terminal) ExecCommand(
 		return "", fmt.Errorf("container is not running")
 	}
 
-	if cwd == "" {
-		cwd = docker.WorkFolderPathInContainer
+	workDir := GetWorkDir(ctx)
+	cwd = ResolveWorkPath(ctx, cwd)
+	execDir := cwd
+
+	// separate subtask work dir is created on demand, so the command starts from the shared one
+	if workDir != docker.WorkFolderPathInContainer && isSubPath(workDir, cwd) {
+		cmd[2] = fmt.Sprintf("mkdir -p %s && cd %s && %s", shellQuote(workDir), shellQuote(cwd), command)
+		execDir = docker.WorkFolderPathInContainer
 	}
 
 	formattedCommand := FormatTerminalInput(cwd, command)
@@ -146,7 +152,7 @@ func (t *terminal) ExecCommand(
 		Cmd:          cmd,
 		AttachStdout: true,
 		AttachStderr: true,
-		WorkingDir:   cwd,
+		WorkingDir:   execDir,
 		Tty:          true,
 	})
 	if err != nil {
@@ -239,7 +245,7 @@ func (t *terminal) ReadFile(ctx context.Context, flowID int64, path string) (str
 		return "", fmt.Errorf("container is not running")
 	}
 
-	cwd := docker.WorkFolderPathInContainer
+	cwd, path := GetWorkDir(ctx), ResolveWorkPath(ctx, path)
 	formattedCommand := FormatTerminalInput(cwd, fmt.Sprintf("cat %s", path))
 	_, err = t.tlp.PutMsg(ctx, database.TermlogTypeStdin, formattedCommand, t.containerID)
 	if err != nil {
@@ -309,10 +315,19 @@ func (t *terminal) WriteFile(ctx context.Context, flowID int64, content string,
 		return "", fmt.Errorf("container is not running")
 	}
 
+	path = ResolveWorkPath(ctx, path)
+	dir, filename := filepath.Dir(path), filepath.Base(path)
+
+	// files inside the shared work dir are unpacked relative to it, so missing
+	// subtask work dirs are created by the container on the fly
+	if isSubPath(docker.WorkFolderPathInContainer, dir) {
+		dir = docker.WorkFolderPathInContainer
+		filename = strings.TrimPrefix(path, docker.WorkFolderPathInContainer+"/")
+	}
+
 	// put content into a tar archive
 	archive := &bytes.Buffer{}
 	tarWriter := tar.NewWriter(archive)
-	filename := filepath.Base(path)
 	tarHeader := &tar.Header{
 		Name: filename,
 		Mode: 0600,
@@ -328,7 +343,11 @@ func (t *terminal) WriteFile(ctx context.Context, flowID int64, content string,
 		return "", fmt.Errorf("failed to write tar content: %w", err)
 	}
 
-	dir := filepath.Dir(path)
+	err = tarWriter.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to close tar archive: %w", err)
+	}
+
 	err = t.dockerClient.CopyToContainer(ctx, containerName, dir, archive, container.CopyToContainerOptions{
 		AllowOverwriteDirWithFile: true,
 	})
@@ -345,6 +364,16 @@ func (t *terminal) WriteFile(ctx context.Context, flowID int64, content string,
 	return fmt.Sprintf("file %s written successfully", path), nil
 }
 
+// shellQuote wraps the value in single quotes for sh, embedded single quotes are closed and escaped
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// isSubPath reports whether the path is the dir itself or is located inside it
+func isSubPath(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
 func PrimaryTerminalName(flowID int64) string {
 	return fmt.Sprintf("pentagi-terminal-%d", flowID)
 }
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"testing"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/docker"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// containerFS emulates the filesystem of the primary container for the file tool
+type containerFS struct {
+	docker.DockerClient
+	mx    sync.Mutex
+	dirs  map[string]bool
+	files map[string]string
+	execs []container.ExecOptions
+}
+
+func newContainerFS() *containerFS {
+	return &containerFS{
+		dirs:  map[string]bool{"/": true, docker.WorkFolderPathInContainer: true},
+		files: make(map[string]string),
+	}
+}
+
+func (c *containerFS) IsContainerRunning(ctx context.Context, containerID string) (bool, error) {
+	return true, nil
+}
+
+func (c *containerFS) CopyToContainer(ctx context.Context, containerID string, dstPath string,
+	content io.Reader, options container.CopyToContainerOptions,
+) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !c.dirs[dstPath] {
+		return fmt.Errorf("could not find the file %s in container %s", dstPath, containerID)
+	}
+
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		name := path.Join(dstPath, hdr.Name)
+		for dir := path.Dir(name); !c.dirs[dir]; dir = path.Dir(dir) {
+			c.dirs[dir] = true
+		}
+		c.files[name] = string(data)
+	}
+}
+
+func (c *containerFS) CopyFromContainer(ctx context.Context, containerID string,
+	srcPath string,
+) (io.ReadCloser, container.PathStat, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	data, ok := c.files[srcPath]
+	if !ok {
+		return nil, container.PathStat{}, fmt.Errorf("could not find the file %s in container %s", srcPath, containerID)
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(&tar.Header{Name: path.Base(srcPath), Mode: 0600, Size: int64(len(data))}); err != nil {
+		return nil, container.PathStat{}, err
+	}
+	if _, err := tw.Write([]byte(data)); err != nil {
+		return nil, container.PathStat{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, container.PathStat{}, err
+	}
+
+	stat := container.PathStat{Name: path.Base(srcPath), Size: int64(len(data)), Mode: 0600}
+	return io.NopCloser(buf), stat, nil
+}
+
+func (c *containerFS) ContainerExecCreate(ctx context.Context, containerName string,
+	options container.ExecOptions,
+) (container.ExecCreateResponse, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.execs = append(c.execs, options)
+	return container.ExecCreateResponse{}, errors.New("exec is not supported")
+}
+
+type discardTermLog struct{}
+
+func (discardTermLog) PutMsg(ctx context.Context, msgType database.TermlogType, msg string, containerID int64) (int64, error) {
+	return 0, nil
+}
+
+func TestResolveWorkPath(t *testing.T) {
+	subtaskCtx := PutWorkDir(context.Background(), SubtaskWorkDir(7))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		path string
+		want string
+	}{
+		{"default relative", context.Background(), "notes.txt", "/work/notes.txt"},
+		{"default empty", context.Background(), "", "/work"},
+		{"subtask relative", subtaskCtx, "out/notes.txt", "/work/subtask-7/out/notes.txt"},
+		{"subtask parent", subtaskCtx, "../notes.txt", "/work/notes.txt"},
+		{"subtask absolute", subtaskCtx, "/etc/hosts", "/etc/hosts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResolveWorkPath(tt.ctx, tt.path))
+		})
+	}
+}
+
+func TestParallelSubtasksFilesIsolation(t *testing.T) {
+	fs := newContainerFS()
+	term := &terminal{flowID: 1, containerID: 1, containerLID: "primary", dockerClient: fs, tlp: discardTermLog{}}
+
+	const subtasks = 2
+	contents := make([]string, subtasks)
+	errs := make([]error, subtasks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < subtasks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx := PutWorkDir(context.Background(), SubtaskWorkDir(int64(i+1)))
+			if _, err := term.WriteFile(ctx, term.flowID, fmt.Sprintf("subtask %d", i+1), "notes.txt"); err != nil {
+				errs[i] = err
+				return
+			}
+			contents[i], errs[i] = term.ReadFile(ctx, term.flowID, "notes.txt")
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < subtasks; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, fmt.Sprintf("subtask %d", i+1), contents[i])
+	}
+
+	assert.Equal(t, map[string]string{
+		"/work/subtask-1/notes.txt": "subtask 1",
+		"/work/subtask-2/notes.txt": "subtask 2",
+	}, fs.files)
+
+	// the relative file of one subtask is not visible in the shared work dir
+	_, err := term.ReadFile(context.Background(), term.flowID, "notes.txt")
+	assert.Error(t, err)
+}
+
+func TestExecCommandSubtaskWorkDir(t *testing.T) {
+	fs := newContainerFS()
+	term := &terminal{flowID: 1, containerID: 1, containerLID: "primary", dockerClient: fs, tlp: discardTermLog{}}
+	ctx := PutWorkDir(context.Background(), SubtaskWorkDir(3))
+
+	_, err := term.ExecCommand(ctx, "", "ls", false, 0)
+	require.Error(t, err)
+	_, err = term.ExecCommand(ctx, "src", "ls", false, 0)
+	require.Error(t, err)
+	_, err = term.ExecCommand(ctx, "/tmp", "ls", false, 0)
+	require.Error(t, err)
+	_, err = term.ExecCommand(ctx, "it's a dir; rm -rf x", "ls", false, 0)
+	require.Error(t, err)
+
+	require.Len(t, fs.execs, 4)
+	assert.Equal(t, "mkdir -p '/work/subtask-3' && cd '/work/subtask-3' && ls", fs.execs[0].Cmd[2])
+	assert.Equal(t, docker.WorkFolderPathInContainer, fs.execs[0].WorkingDir)
+	assert.Equal(t, "mkdir -p '/work/subtask-3' && cd '/work/subtask-3/src' && ls", fs.execs[1].Cmd[2])
+	assert.Equal(t, docker.WorkFolderPathInContainer, fs.execs[1].WorkingDir)
+	assert.Equal(t, "ls", fs.execs[2].Cmd[2])
+	assert.Equal(t, "/tmp", fs.execs[2].WorkingDir)
+	// the path is a single shell word whatever it contains
+	assert.Equal(t, `mkdir -p '/work/subtask-3' && cd '/work/subtask-3/it'\''s a dir; rm -rf x' && ls`, fs.execs[3].Cmd[2])
+}
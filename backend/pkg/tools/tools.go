@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"pentagi/pkg/config"
 	"pentagi/pkg/database"
@@ -333,6 +334,60 @@ func (fte *flowToolsExecutor) SetFunctions(functions *Functions) {
 	fte.functions = functions
 }
 
+// isFunctionDisabled returns true if the function is disabled for the agent context by the flow settings
+func (fte *flowToolsExecutor) isFunctionDisabled(name, agent string) bool {
+	if fte.functions == nil {
+		return false
+	}
+
+	for _, disabled := range fte.functions.Disabled {
+		if disabled.Name == name && (len(disabled.Context) == 0 || slices.Contains(disabled.Context, agent)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyFunctions removes disabled functions from the executor, barrier functions are always kept
+// because the agent can't finish its work without them
+func (fte *flowToolsExecutor) applyFunctions(ce *customExecutor, agent string) *customExecutor {
+	definitions := make([]llms.FunctionDefinition, 0, len(ce.definitions))
+	for _, def := range ce.definitions {
+		if _, ok := ce.barriers[def.Name]; !ok && fte.isFunctionDisabled(def.Name, agent) {
+			delete(ce.handlers, def.Name)
+			continue
+		}
+		definitions = append(definitions, def)
+	}
+	ce.definitions = definitions
+
+	return ce
+}
+
+// DisableFunctionsExcept returns the list of disabled functions which keeps only the allowed functions
+// and the functions to store agent results which are required to complete the agent work
+func DisableFunctionsExcept(allowed []string) []DisableFunction {
+	names := make([]string, 0, len(toolsTypeMapping))
+	for name, toolType := range toolsTypeMapping {
+		switch toolType {
+		case BarrierToolType, StoreAgentResultToolType, NoneToolType:
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	disabled := make([]DisableFunction, 0, len(names))
+	for _, name := range names {
+		disabled = append(disabled, DisableFunction{Name: name})
+	}
+
+	return disabled
+}
+
 func (fte *flowToolsExecutor) SetScreenshotProvider(scp ScreenshotProvider) {
 	fte.scp = scp
 }
@@ -373,19 +428,39 @@ func (fte *flowToolsExecutor) Prepare(ctx context.Context) error {
 		}
 	}
 
+	cnt, err := SpawnPrimaryContainer(ctx, fte.docker, fte.cfg, fte.flowID, fte.image)
+	if err != nil {
+		return err
+	}
+
+	fte.primaryID = cnt.ID
+	fte.primaryLID = cnt.LocalID.String
+
+	return nil
+}
+
+// SpawnPrimaryContainer starts the primary container of the flow from the image,
+// it keeps running in the background and serves the terminal and file tools
+func SpawnPrimaryContainer(
+	ctx context.Context,
+	dockerClient docker.DockerClient,
+	cfg *config.Config,
+	flowID int64,
+	image string,
+) (database.Container, error) {
 	capAdd := []string{"NET_RAW"}
-	if fte.cfg.DockerNetAdmin {
+	if cfg.DockerNetAdmin {
 		capAdd = append(capAdd, "NET_ADMIN")
 	}
 
-	containerName := PrimaryTerminalName(fte.flowID)
-	cnt, err := fte.docker.SpawnContainer(
+	containerName := PrimaryTerminalName(flowID)
+	cnt, err := dockerClient.SpawnContainer(
 		ctx,
 		containerName,
 		database.ContainerTypePrimary,
-		fte.flowID,
+		flowID,
 		&container.Config{
-			Image:      fte.image,
+			Image:      image,
 			Entrypoint: []string{"tail", "-f", "/dev/null"},
 		},
 		&container.HostConfig{
@@ -393,13 +468,10 @@ func (fte *flowToolsExecutor) Prepare(ctx context.Context) error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to spawn container '%s': %w", containerName, err)
+		return database.Container{}, fmt.Errorf("failed to spawn container '%s': %w", containerName, err)
 	}
 
-	fte.primaryID = cnt.ID
-	fte.primaryLID = cnt.LocalID.String
-
-	return nil
+	return cnt, nil
 }
 
 func (fte *flowToolsExecutor) Release(ctx context.Context) error {
@@ -671,7 +743,7 @@ func (fte *flowToolsExecutor) GetAssistantExecutor(cfg AssistantExecutorConfig)
 		summarizer:  cfg.Summarizer,
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "assistant"), nil
 }
 
 func (fte *flowToolsExecutor) GetPrimaryExecutor(cfg PrimaryExecutorConfig) (ContextToolsExecutor, error) {
@@ -741,7 +813,7 @@ func (fte *flowToolsExecutor) GetPrimaryExecutor(cfg PrimaryExecutorConfig) (Con
 		ce.barriers[AskUserToolName] = struct{}{}
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "agent"), nil
 }
 
 func (fte *flowToolsExecutor) GetInstallerExecutor(cfg InstallerExecutorConfig) (ContextToolsExecutor, error) {
@@ -830,7 +902,7 @@ func (fte *flowToolsExecutor) GetInstallerExecutor(cfg InstallerExecutorConfig)
 		ce.handlers[SearchGuideToolName] = guide.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "agent"), nil
 }
 
 func (fte *flowToolsExecutor) GetCoderExecutor(cfg CoderExecutorConfig) (ContextToolsExecutor, error) {
@@ -919,7 +991,7 @@ func (fte *flowToolsExecutor) GetCoderExecutor(cfg CoderExecutorConfig) (Context
 		ce.handlers[GraphitiSearchToolName] = graphitiSearch.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "coder"), nil
 }
 
 func (fte *flowToolsExecutor) GetPentesterExecutor(cfg PentesterExecutorConfig) (ContextToolsExecutor, error) {
@@ -1031,7 +1103,7 @@ func (fte *flowToolsExecutor) GetPentesterExecutor(cfg PentesterExecutorConfig)
 		ce.handlers[GraphitiSearchToolName] = graphitiSearch.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "agent"), nil
 }
 
 func (fte *flowToolsExecutor) GetSearcherExecutor(cfg SearcherExecutorConfig) (ContextToolsExecutor, error) {
@@ -1185,7 +1257,7 @@ func (fte *flowToolsExecutor) GetSearcherExecutor(cfg SearcherExecutorConfig) (C
 		ce.handlers[StoreAnswerToolName] = search.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "searcher"), nil
 }
 
 func (fte *flowToolsExecutor) GetGeneratorExecutor(cfg GeneratorExecutorConfig) (ContextToolsExecutor, error) {
@@ -1246,7 +1318,7 @@ func (fte *flowToolsExecutor) GetGeneratorExecutor(cfg GeneratorExecutorConfig)
 		ce.handlers[BrowserToolName] = browser.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "generator"), nil
 }
 
 func (fte *flowToolsExecutor) GetRefinerExecutor(cfg RefinerExecutorConfig) (ContextToolsExecutor, error) {
@@ -1307,7 +1379,7 @@ func (fte *flowToolsExecutor) GetRefinerExecutor(cfg RefinerExecutorConfig) (Con
 		ce.handlers[BrowserToolName] = browser.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "generator"), nil
 }
 
 func (fte *flowToolsExecutor) GetMemoristExecutor(cfg MemoristExecutorConfig) (ContextToolsExecutor, error) {
@@ -1373,7 +1445,7 @@ func (fte *flowToolsExecutor) GetMemoristExecutor(cfg MemoristExecutorConfig) (C
 		ce.handlers[GraphitiSearchToolName] = graphitiSearch.Handle
 	}
 
-	return ce, nil
+	return fte.applyFunctions(ce, "memorist"), nil
 }
 
 func (fte *flowToolsExecutor) GetEnricherExecutor(cfg EnricherExecutorConfig) (ContextToolsExecutor, error) {
@@ -1389,7 +1461,7 @@ func (fte *flowToolsExecutor) GetEnricherExecutor(cfg EnricherExecutorConfig) (C
 		return nil, fmt.Errorf("searcher handler is required")
 	}
 
-	return &customExecutor{
+	return fte.applyFunctions(&customExecutor{
 		flowID:    fte.flowID,
 		taskID:    cfg.TaskID,
 		subtaskID: cfg.SubtaskID,
@@ -1408,7 +1480,7 @@ func (fte *flowToolsExecutor) GetEnricherExecutor(cfg EnricherExecutorConfig) (C
 			EnricherResultToolName: cfg.EnricherResult,
 		},
 		barriers: map[string]struct{}{EnricherResultToolName: {}},
-	}, nil
+	}, "enricher"), nil
 }
 
 func (fte *flowToolsExecutor) GetReporterExecutor(cfg ReporterExecutorConfig) (ContextToolsExecutor, error) {
@@ -1416,7 +1488,7 @@ func (fte *flowToolsExecutor) GetReporterExecutor(cfg ReporterExecutorConfig) (C
 		return nil, fmt.Errorf("report result handler is required")
 	}
 
-	return &customExecutor{
+	return fte.applyFunctions(&customExecutor{
 		flowID:      fte.flowID,
 		taskID:      cfg.TaskID,
 		subtaskID:   cfg.SubtaskID,
@@ -1427,5 +1499,5 @@ func (fte *flowToolsExecutor) GetReporterExecutor(cfg ReporterExecutorConfig) (C
 		definitions: []llms.FunctionDefinition{registryDefinitions[ReportResultToolName]},
 		handlers:    map[string]ExecutorHandler{ReportResultToolName: cfg.ReportResult},
 		barriers:    map[string]struct{}{ReportResultToolName: {}},
-	}, nil
+	}, "reporter"), nil
 }
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"pentagi/pkg/config"
+)
+
+func TestApplyFunctions(t *testing.T) {
+	handler := func(ctx context.Context, name string, args json.RawMessage) (string, error) {
+		return "", nil
+	}
+
+	fte := &flowToolsExecutor{
+		cfg: &config.Config{},
+		functions: &Functions{
+			Disabled: []DisableFunction{
+				{Name: SearchToolName, Context: []string{"enricher"}},
+				{Name: EnricherResultToolName},
+				{Name: MemoristToolName, Context: []string{"coder"}},
+			},
+		},
+	}
+
+	executor, err := fte.GetEnricherExecutor(EnricherExecutorConfig{
+		Memorist:       handler,
+		Searcher:       handler,
+		EnricherResult: handler,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get enricher executor: %v", err)
+	}
+
+	names := make(map[string]struct{})
+	for _, tool := range executor.Tools() {
+		names[tool.Function.Name] = struct{}{}
+	}
+
+	if _, ok := names[SearchToolName]; ok {
+		t.Errorf("Expected %s to be disabled for the enricher", SearchToolName)
+	}
+	if _, ok := names[MemoristToolName]; !ok {
+		t.Errorf("Expected %s to be available for the enricher", MemoristToolName)
+	}
+	if _, ok := names[EnricherResultToolName]; !ok {
+		t.Errorf("Expected barrier %s to be always available", EnricherResultToolName)
+	}
+
+	result, err := executor.Execute(context.Background(), 0, "call_1", SearchToolName, "", json.RawMessage(`{}`))
+	if err != nil || result == "" {
+		t.Errorf("Expected disabled function to be reported as not found, got %q: %v", result, err)
+	}
+}
+
+func TestDisableFunctionsExcept(t *testing.T) {
+	disabled := DisableFunctionsExcept([]string{TerminalToolName, FileToolName})
+
+	names := make(map[string]struct{}, len(disabled))
+	for _, function := range disabled {
+		names[function.Name] = struct{}{}
+		if len(function.Context) != 0 {
+			t.Errorf("Expected function %s to be disabled for all agents", function.Name)
+		}
+	}
+
+	for _, name := range []string{TerminalToolName, FileToolName, FinalyToolName, HackResultToolName} {
+		if _, ok := names[name]; ok {
+			t.Errorf("Expected function %s to be kept", name)
+		}
+	}
+	for _, name := range []string{BrowserToolName, PentesterToolName, GoogleToolName, StoreGuideToolName} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("Expected function %s to be disabled", name)
+		}
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"pentagi/pkg/database"
+	obs "pentagi/pkg/observability"
 
 	"github.com/sirupsen/logrus"
 )
@@ -58,7 +60,9 @@ func (t *traversaal) Handle(ctx context.Context, name string, args json.RawMessa
 		"max_results": action.MaxResults,
 	})
 
+	start := time.Now()
 	result, err := t.search(ctx, action.Query)
+	obs.Observer.RecordSearchCall(ctx, string(database.SearchengineTypeTraversaal), time.Since(start), err)
 	if err != nil {
 		logger.WithError(err).Error("failed to search in traversaal")
 		return fmt.Sprintf("failed to search in traversaal: %v", err), nil
@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"context"
+	"sync"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/database/converter"
+	"pentagi/pkg/graph/model"
+	"pentagi/pkg/graph/subscriptions"
+)
+
+// FlowStatusChanged is the data of the flow events
+type FlowStatusChanged struct {
+	Flow           *model.Flow `json:"flow"`
+	PreviousStatus string      `json:"previousStatus,omitempty"`
+}
+
+// TaskStatusChanged is the data of the task status event, the previous status is empty for the new task
+type TaskStatusChanged struct {
+	Task           *model.Task `json:"task"`
+	PreviousStatus string      `json:"previousStatus,omitempty"`
+}
+
+type SubtaskStatusChanged struct {
+	Subtask        *model.Subtask `json:"subtask"`
+	PreviousStatus string         `json:"previousStatus,omitempty"`
+}
+
+// BudgetExceeded is the data of the event of the subtask which is finished by the watchdog
+type BudgetExceeded struct {
+	TaskID    int64  `json:"taskId"`
+	SubtaskID int64  `json:"subtaskId"`
+	Reason    string `json:"reason"`
+}
+
+type subscriptionsController struct {
+	subscriptions.SubscriptionsController
+	notifier Notifier
+	mx       *sync.Mutex
+	flows    map[int64]*flowState
+}
+
+// flowState keeps the last known statuses of the flow, so the events are sent on changes only,
+// the publishers are created many times for the same flow and share the state
+type flowState struct {
+	mx       *sync.Mutex
+	status   database.FlowStatus
+	tasks    map[int64]database.TaskStatus
+	subtasks map[int64]database.SubtaskStatus
+	findings map[int64]struct{}
+}
+
+// WrapSubscriptions returns the subscriptions controller which publishers also notify the webhooks
+// about the flow lifecycle events
+func WrapSubscriptions(
+	subs subscriptions.SubscriptionsController,
+	notifier Notifier,
+) subscriptions.SubscriptionsController {
+	return &subscriptionsController{
+		SubscriptionsController: subs,
+		notifier:                notifier,
+		mx:                      &sync.Mutex{},
+		flows:                   make(map[int64]*flowState),
+	}
+}
+
+func (s *subscriptionsController) NewFlowPublisher(userID, flowID int64) subscriptions.FlowPublisher {
+	return &flowPublisher{
+		FlowPublisher: s.SubscriptionsController.NewFlowPublisher(userID, flowID),
+		ctrl:          s,
+	}
+}
+
+func (s *subscriptionsController) state(flowID int64) *flowState {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	state, ok := s.flows[flowID]
+	if !ok {
+		state = &flowState{
+			mx:       &sync.Mutex{},
+			tasks:    make(map[int64]database.TaskStatus),
+			subtasks: make(map[int64]database.SubtaskStatus),
+			findings: make(map[int64]struct{}),
+		}
+		s.flows[flowID] = state
+	}
+
+	return state
+}
+
+func (s *subscriptionsController) release(flowID int64) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	delete(s.flows, flowID)
+}
+
+type flowPublisher struct {
+	subscriptions.FlowPublisher
+	ctrl *subscriptionsController
+}
+
+func (p *flowPublisher) notify(ctx context.Context, event Event, data any) {
+	p.ctrl.notifier.Notify(ctx, p.GetUserID(), p.GetFlowID(), event, data)
+}
+
+func (p *flowPublisher) FlowCreated(ctx context.Context, flow database.Flow, terms []database.Container) {
+	p.FlowPublisher.FlowCreated(ctx, flow, terms)
+
+	state := p.ctrl.state(flow.ID)
+	state.mx.Lock()
+	state.status = flow.Status
+	state.mx.Unlock()
+
+	p.notify(ctx, EventFlowCreated, FlowStatusChanged{Flow: converter.ConvertFlow(flow, terms)})
+}
+
+func (p *flowPublisher) FlowDeleted(ctx context.Context, flow database.Flow, terms []database.Container) {
+	p.FlowPublisher.FlowDeleted(ctx, flow, terms)
+	p.ctrl.release(flow.ID)
+}
+
+func (p *flowPublisher) FlowUpdated(ctx context.Context, flow database.Flow, terms []database.Container) {
+	p.FlowPublisher.FlowUpdated(ctx, flow, terms)
+
+	event := EventFlowUpdated
+	state := p.ctrl.state(flow.ID)
+	state.mx.Lock()
+	previous := state.status
+	state.status = flow.Status
+	switch flow.Status {
+	case database.FlowStatusFinished, database.FlowStatusFailed:
+		if previous != flow.Status {
+			event = EventFlowFinished
+			// statuses of the finished flow aren't changed anymore, only the flow status is kept
+			clear(state.tasks)
+			clear(state.subtasks)
+			clear(state.findings)
+		}
+	}
+	state.mx.Unlock()
+
+	p.notify(ctx, event, FlowStatusChanged{
+		Flow:           converter.ConvertFlow(flow, terms),
+		PreviousStatus: string(previous),
+	})
+}
+
+func (p *flowPublisher) TaskCreated(ctx context.Context, task database.Task, subtasks []database.Subtask) {
+	p.FlowPublisher.TaskCreated(ctx, task, subtasks)
+
+	state := p.ctrl.state(task.FlowID)
+	state.mx.Lock()
+	state.tasks[task.ID] = task.Status
+	for _, subtask := range subtasks {
+		state.subtasks[subtask.ID] = subtask.Status
+	}
+	state.mx.Unlock()
+
+	p.notify(ctx, EventTaskStatusChanged, TaskStatusChanged{Task: converter.ConvertTask(task, nil)})
+}
+
+// TaskUpdated notifies about the changed statuses of the task and its subtasks, the task which is
+// unknown yet (e.g. after the backend restart) is only remembered to track the next changes
+func (p *flowPublisher) TaskUpdated(ctx context.Context, task database.Task, subtasks []database.Subtask) {
+	p.FlowPublisher.TaskUpdated(ctx, task, subtasks)
+
+	var (
+		taskChange     *TaskStatusChanged
+		subtaskChanges []SubtaskStatusChanged
+	)
+
+	state := p.ctrl.state(task.FlowID)
+	state.mx.Lock()
+	previous, known := state.tasks[task.ID]
+	state.tasks[task.ID] = task.Status
+	if known && previous != task.Status {
+		taskChange = &TaskStatusChanged{
+			Task:           converter.ConvertTask(task, nil),
+			PreviousStatus: string(previous),
+		}
+	}
+	for _, subtask := range subtasks {
+		previous, ok := state.subtasks[subtask.ID]
+		state.subtasks[subtask.ID] = subtask.Status
+		switch {
+		case !known, ok && previous == subtask.Status:
+		case !ok && subtask.Status == database.SubtaskStatusCreated:
+			// new subtasks of the plan are not notified until they are started
+		default:
+			subtaskChanges = append(subtaskChanges, SubtaskStatusChanged{
+				Subtask:        converter.ConvertSubtask(subtask),
+				PreviousStatus: string(previous),
+			})
+		}
+	}
+	state.mx.Unlock()
+
+	for _, change := range subtaskChanges {
+		p.notify(ctx, EventSubtaskStatusChanged, change)
+	}
+	if taskChange != nil {
+		p.notify(ctx, EventTaskStatusChanged, *taskChange)
+	}
+}
+
+func (p *flowPublisher) MessageLogAdded(ctx context.Context, messageLog database.Msglog) {
+	p.FlowPublisher.MessageLogAdded(ctx, messageLog)
+
+	switch messageLog.Type {
+	case database.MsglogTypeAsk:
+		p.notify(ctx, EventAskWaiting, converter.ConvertMessageLog(messageLog))
+	case database.MsglogTypeReport:
+		p.findingAdded(ctx, messageLog)
+	}
+}
+
+func (p *flowPublisher) MessageLogUpdated(ctx context.Context, messageLog database.Msglog) {
+	p.FlowPublisher.MessageLogUpdated(ctx, messageLog)
+
+	if messageLog.Type == database.MsglogTypeReport {
+		p.findingAdded(ctx, messageLog)
+	}
+}
+
+// findingAdded notifies about the report of the subtask or the task once it has the result,
+// the subtask report is created empty and its result is set by the following update
+func (p *flowPublisher) findingAdded(ctx context.Context, messageLog database.Msglog) {
+	if messageLog.Result == "" {
+		return
+	}
+
+	state := p.ctrl.state(messageLog.FlowID)
+	state.mx.Lock()
+	_, found := state.findings[messageLog.ID]
+	state.findings[messageLog.ID] = struct{}{}
+	state.mx.Unlock()
+
+	if !found {
+		p.notify(ctx, EventFindingAdded, converter.ConvertMessageLog(messageLog))
+	}
+}
@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	"pentagi/pkg/database"
+	"pentagi/pkg/graph/subscriptions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notification struct {
+	event Event
+	data  any
+}
+
+type recordNotifier struct {
+	notifications []notification
+}
+
+func (n *recordNotifier) Notify(_ context.Context, _, _ int64, event Event, data any) {
+	n.notifications = append(n.notifications, notification{event: event, data: data})
+}
+
+func (n *recordNotifier) events() []Event {
+	events := make([]Event, 0, len(n.notifications))
+	for _, notification := range n.notifications {
+		events = append(events, notification.event)
+	}
+	n.notifications = nil
+
+	return events
+}
+
+func TestPublisherFlowEvents(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordNotifier{}
+	subs := WrapSubscriptions(subscriptions.NewSubscriptionsController(nil), notifier)
+
+	flow := database.Flow{ID: 1, Status: database.FlowStatusCreated}
+	subs.NewFlowPublisher(1, 1).FlowCreated(ctx, flow, nil)
+
+	flow.Status = database.FlowStatusFinished
+	subs.NewFlowPublisher(1, 1).FlowUpdated(ctx, flow, nil)
+
+	// the title change of the finished flow is a regular update
+	flow.Title = "Renamed"
+	subs.NewFlowPublisher(1, 1).FlowUpdated(ctx, flow, nil)
+
+	assert.Equal(t, []Event{EventFlowCreated, EventFlowFinished, EventFlowUpdated}, notifier.events())
+}
+
+func TestPublisherTaskEvents(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordNotifier{}
+	pub := WrapSubscriptions(subscriptions.NewSubscriptionsController(nil), notifier).NewFlowPublisher(1, 1)
+
+	task := database.Task{ID: 10, FlowID: 1, Status: database.TaskStatusCreated}
+	subtasks := []database.Subtask{
+		{ID: 100, TaskID: 10, Status: database.SubtaskStatusCreated},
+		{ID: 101, TaskID: 10, Status: database.SubtaskStatusCreated},
+	}
+
+	pub.TaskCreated(ctx, task, nil)
+	pub.TaskUpdated(ctx, task, subtasks)
+	assert.Equal(t, []Event{EventTaskStatusChanged}, notifier.events(), "generated plan isn't notified")
+
+	task.Status = database.TaskStatusRunning
+	subtasks[0].Status = database.SubtaskStatusRunning
+	pub.TaskUpdated(ctx, task, subtasks)
+	assert.Equal(t, []Event{EventSubtaskStatusChanged, EventTaskStatusChanged}, notifier.events())
+
+	subtasks[0].Status = database.SubtaskStatusFinished
+	subtasks[1].Status = database.SubtaskStatusRunning
+	pub.TaskUpdated(ctx, task, subtasks)
+	assert.Equal(t, []Event{EventSubtaskStatusChanged, EventSubtaskStatusChanged}, notifier.events())
+
+	// the task which was loaded after the restart is tracked from the first update
+	unknown := database.Task{ID: 20, FlowID: 1, Status: database.TaskStatusRunning}
+	pub.TaskUpdated(ctx, unknown, []database.Subtask{{ID: 200, TaskID: 20, Status: database.SubtaskStatusFinished}})
+	assert.Empty(t, notifier.events())
+
+	unknown.Status = database.TaskStatusFinished
+	pub.TaskUpdated(ctx, unknown, nil)
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Equal(t, EventTaskStatusChanged, notifier.notifications[0].event)
+		assert.Equal(t, "running", notifier.notifications[0].data.(TaskStatusChanged).PreviousStatus)
+	}
+}
+
+func TestPublisherMessageEvents(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordNotifier{}
+	pub := WrapSubscriptions(subscriptions.NewSubscriptionsController(nil), notifier).NewFlowPublisher(1, 1)
+
+	pub.MessageLogAdded(ctx, database.Msglog{ID: 1, FlowID: 1, Type: database.MsglogTypeAsk})
+	pub.MessageLogAdded(ctx, database.Msglog{ID: 2, FlowID: 1, Type: database.MsglogTypeTerminal})
+
+	// the subtask report gets its result by the update
+	report := database.Msglog{ID: 3, FlowID: 1, Type: database.MsglogTypeReport}
+	pub.MessageLogAdded(ctx, report)
+	report.Result = "Port 22 is open"
+	pub.MessageLogUpdated(ctx, report)
+	pub.MessageLogUpdated(ctx, report)
+
+	assert.Equal(t, []Event{EventAskWaiting, EventFindingAdded}, notifier.events())
+}
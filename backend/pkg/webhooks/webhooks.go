@@ -0,0 +1,363 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"pentagi/pkg/config"
+	"pentagi/pkg/database"
+	"pentagi/pkg/system"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	tickInterval    = 10 * time.Second
+	deliveryTimeout = 10 * time.Second
+	deliveryBatch   = 20
+	// delivery is failed after the maximum attempts, the delay between attempts is doubled each time
+	maxAttempts     = 6
+	retryBackoff    = 30 * time.Second
+	maxRetryBackoff = time.Hour
+	maxErrorLength  = 1000
+)
+
+const (
+	SignatureHeader = "X-PentAGI-Signature"
+	EventHeader     = "X-PentAGI-Event"
+	DeliveryHeader  = "X-PentAGI-Delivery"
+)
+
+type Event string
+
+const (
+	EventFlowCreated          Event = "flow_created"
+	EventFlowUpdated          Event = "flow_updated"
+	EventFlowFinished         Event = "flow_finished"
+	EventTaskStatusChanged    Event = "task_status_changed"
+	EventSubtaskStatusChanged Event = "subtask_status_changed"
+	EventAskWaiting           Event = "ask_waiting"
+	EventFindingAdded         Event = "finding_added"
+	EventBudgetExceeded       Event = "budget_exceeded"
+	EventTest                 Event = "test"
+)
+
+// Events is the list of the events which can be subscribed, the test event is sent to the webhook on demand only
+var Events = []Event{
+	EventFlowCreated,
+	EventFlowUpdated,
+	EventFlowFinished,
+	EventTaskStatusChanged,
+	EventSubtaskStatusChanged,
+	EventAskWaiting,
+	EventFindingAdded,
+	EventBudgetExceeded,
+}
+
+func (e Event) Valid() bool {
+	return slices.Contains(Events, e)
+}
+
+// Payload is the JSON body of the webhook request
+type Payload struct {
+	Event     Event     `json:"event"`
+	UserID    int64     `json:"userId"`
+	FlowID    int64     `json:"flowId,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+// Notifier records the event deliveries to the webhooks which are subscribed to the event
+type Notifier interface {
+	Notify(ctx context.Context, userID, flowID int64, event Event, data any)
+}
+
+type Dispatcher interface {
+	Notifier
+	Test(ctx context.Context, webhook database.Webhook) (database.WebhookDelivery, error)
+	Start()
+	Stop()
+}
+
+type dispatcher struct {
+	db     database.Querier
+	client *http.Client
+	wakeup chan struct{}
+	wg     *sync.WaitGroup
+	cancel context.CancelFunc
+	logger *logrus.Entry
+}
+
+// NewDispatcher returns the dispatcher which stores the event deliveries to the delivery log
+// and sends pending deliveries from the background loop, so the deliveries survive the restart
+func NewDispatcher(cfg *config.Config, db database.Querier) (Dispatcher, error) {
+	client, err := system.GetHTTPClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+	client.Timeout = deliveryTimeout
+
+	return &dispatcher{
+		db:     db,
+		client: client,
+		wakeup: make(chan struct{}, 1),
+		wg:     &sync.WaitGroup{},
+		cancel: func() {},
+		logger: logrus.WithField("component", "webhooks"),
+	}, nil
+}
+
+func (d *dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		for {
+			d.tick(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-d.wakeup:
+			}
+		}
+	}()
+}
+
+func (d *dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *dispatcher) Notify(ctx context.Context, userID, flowID int64, event Event, data any) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"user_id": userID,
+		"flow_id": flowID,
+		"event":   event,
+	})
+
+	webhooks, err := d.db.GetEventWebhooks(ctx, database.GetEventWebhooksParams{
+		UserID: database.Int64ToNullInt64(&userID),
+		Event:  string(event),
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to get webhooks of the event")
+		return
+	} else if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:     event,
+		UserID:    userID,
+		FlowID:    flowID,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal webhook payload")
+		return
+	}
+
+	var flow *int64
+	if flowID != 0 {
+		flow = &flowID
+	}
+
+	for _, webhook := range webhooks {
+		_, err := d.db.CreateWebhookDelivery(ctx, database.CreateWebhookDeliveryParams{
+			WebhookID:     webhook.ID,
+			FlowID:        database.Int64ToNullInt64(flow),
+			Event:         string(event),
+			Payload:       payload,
+			NextAttemptAt: database.TimeToNullTime(time.Now()),
+		})
+		if err != nil {
+			logger.WithError(err).WithField("webhook_id", webhook.ID).Error("failed to create webhook delivery")
+		}
+	}
+
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Test sends the test event to the webhook once without retries, even if the webhook is disabled
+func (d *dispatcher) Test(ctx context.Context, webhook database.Webhook) (database.WebhookDelivery, error) {
+	payload, err := json.Marshal(Payload{
+		Event:     EventTest,
+		UserID:    webhook.UserID.Int64,
+		Timestamp: time.Now().UTC(),
+		Data: map[string]any{
+			"webhook_id": webhook.ID,
+			"name":       webhook.Name,
+		},
+	})
+	if err != nil {
+		return database.WebhookDelivery{}, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	// the test delivery has no next attempt, so it's never picked up by the delivery loop
+	delivery, err := d.db.CreateWebhookDelivery(ctx, database.CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		Event:     string(EventTest),
+		Payload:   payload,
+	})
+	if err != nil {
+		return database.WebhookDelivery{}, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return d.attempt(ctx, webhook, delivery, false)
+}
+
+func (d *dispatcher) tick(ctx context.Context, now time.Time) {
+	deliveries, err := d.db.GetDueWebhookDeliveries(ctx, database.GetDueWebhookDeliveriesParams{
+		NextAttemptAt: database.TimeToNullTime(now),
+		Limit:         deliveryBatch,
+	})
+	if err != nil {
+		d.logger.WithError(err).Error("failed to get due webhook deliveries")
+		return
+	}
+
+	webhooks := make(map[int64]database.Webhook)
+	wg := &sync.WaitGroup{}
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = d.db.GetWebhook(ctx, delivery.WebhookID)
+			if err != nil {
+				d.logger.WithError(err).WithField("webhook_id", delivery.WebhookID).Error("failed to get webhook")
+				continue
+			}
+			webhooks[webhook.ID] = webhook
+		}
+
+		wg.Add(1)
+		go func(webhook database.Webhook, delivery database.WebhookDelivery) {
+			defer wg.Done()
+
+			var err error
+			if !webhook.Enabled {
+				_, err = d.update(ctx, delivery, 0, fmt.Errorf("webhook is disabled"), false)
+			} else {
+				_, err = d.attempt(ctx, webhook, delivery, true)
+			}
+			if err != nil {
+				d.logger.WithError(err).WithField("delivery_id", delivery.ID).Error("failed to update webhook delivery")
+			}
+		}(webhook, delivery)
+	}
+	wg.Wait()
+}
+
+// attempt sends the delivery and stores the result, failed delivery is scheduled to the next
+// attempt with the backoff if retries are allowed
+func (d *dispatcher) attempt(
+	ctx context.Context,
+	webhook database.Webhook,
+	delivery database.WebhookDelivery,
+	retry bool,
+) (database.WebhookDelivery, error) {
+	code, err := d.send(ctx, webhook, delivery)
+	if err != nil {
+		d.logger.WithError(err).WithFields(logrus.Fields{
+			"webhook_id":  webhook.ID,
+			"delivery_id": delivery.ID,
+			"event":       delivery.Event,
+		}).Warn("failed to deliver webhook")
+	}
+
+	delivery.Attempts++
+
+	return d.update(ctx, delivery, code, err, retry)
+}
+
+func (d *dispatcher) update(
+	ctx context.Context,
+	delivery database.WebhookDelivery,
+	code int,
+	sendErr error,
+	retry bool,
+) (database.WebhookDelivery, error) {
+	params := database.UpdateWebhookDeliveryParams{
+		ID:           delivery.ID,
+		Status:       database.WebhookDeliveryStatusDelivered,
+		Attempts:     delivery.Attempts,
+		ResponseCode: int32(code),
+	}
+
+	if sendErr != nil {
+		params.Status = database.WebhookDeliveryStatusFailed
+		params.Error = sendErr.Error()[:min(len(sendErr.Error()), maxErrorLength)]
+		if retry && delivery.Attempts < maxAttempts {
+			params.Status = database.WebhookDeliveryStatusPending
+			params.NextAttemptAt = database.TimeToNullTime(time.Now().Add(backoff(int(delivery.Attempts))))
+		}
+	}
+
+	return d.db.UpdateWebhookDelivery(ctx, params)
+}
+
+// send posts the payload to the webhook url and returns the response status code
+func (d *dispatcher) send(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PentAGI-Webhooks")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value, it's HMAC-SHA256 of the request body with the webhook secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay before the next attempt after the given number of attempts
+func backoff(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryBackoff)
+}
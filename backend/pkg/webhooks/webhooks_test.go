@@ -0,0 +1,92 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pentagi/pkg/database"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deliveryQuerier stores the delivery updates, other queries are not used by the delivery attempt
+type deliveryQuerier struct {
+	database.Querier
+	updates []database.UpdateWebhookDeliveryParams
+}
+
+func (q *deliveryQuerier) UpdateWebhookDelivery(
+	_ context.Context,
+	arg database.UpdateWebhookDeliveryParams,
+) (database.WebhookDelivery, error) {
+	q.updates = append(q.updates, arg)
+	return database.WebhookDelivery{ID: arg.ID, Status: arg.Status, Attempts: arg.Attempts}, nil
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t,
+		"sha256=8419ab361b37d61b696d008ef7549a18325132dae5da84c7424e8e1c590d0498",
+		Sign("secret", []byte(`{"event":"test"}`)),
+	)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backoff(1))
+	assert.Equal(t, time.Minute, backoff(2))
+	assert.Equal(t, 4*time.Minute, backoff(4))
+	assert.Equal(t, time.Hour, backoff(20))
+}
+
+func TestEventValid(t *testing.T) {
+	assert.True(t, EventFindingAdded.Valid())
+	assert.False(t, EventTest.Valid(), "test event can't be subscribed")
+	assert.False(t, Event("flow_deleted").Valid())
+}
+
+func TestAttempt(t *testing.T) {
+	payload := json.RawMessage(`{"event":"flow_created"}`)
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign("secret", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "flow_created", r.Header.Get(EventHeader))
+		assert.Equal(t, "7", r.Header.Get(DeliveryHeader))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	db := &deliveryQuerier{}
+	d := &dispatcher{db: db, client: server.Client(), logger: logrus.NewEntry(logrus.New())}
+	webhook := database.Webhook{ID: 1, Url: server.URL, Secret: "secret", Enabled: true}
+	delivery := database.WebhookDelivery{ID: 7, WebhookID: 1, Event: "flow_created", Payload: payload}
+
+	// failed delivery is retried with the backoff
+	_, err := d.attempt(context.Background(), webhook, delivery, true)
+	require.NoError(t, err)
+	require.Len(t, db.updates, 1)
+	assert.Equal(t, database.WebhookDeliveryStatusPending, db.updates[0].Status)
+	assert.Equal(t, int32(http.StatusInternalServerError), db.updates[0].ResponseCode)
+	assert.Contains(t, db.updates[0].Error, "500")
+	assert.True(t, db.updates[0].NextAttemptAt.Time.After(time.Now().Add(retryBackoff-time.Second)))
+
+	// the last attempt fails the delivery
+	delivery.Attempts = maxAttempts - 1
+	_, err = d.attempt(context.Background(), webhook, delivery, true)
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookDeliveryStatusFailed, db.updates[1].Status)
+	assert.False(t, db.updates[1].NextAttemptAt.Valid)
+
+	status = http.StatusNoContent
+	_, err = d.attempt(context.Background(), webhook, delivery, false)
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookDeliveryStatusDelivered, db.updates[2].Status)
+	assert.Equal(t, int32(http.StatusNoContent), db.updates[2].ResponseCode)
+	assert.Empty(t, db.updates[2].Error)
+}
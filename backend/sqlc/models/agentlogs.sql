@@ -6,6 +6,38 @@ INNER JOIN flows f ON al.flow_id = f.id
 WHERE al.flow_id = $1 AND f.deleted_at IS NULL
 ORDER BY al.created_at ASC;
 
+-- name: GetFlowAgentLogsAsc :many
+SELECT
+  al.*
+FROM agentlogs al
+INNER JOIN flows f ON al.flow_id = f.id
+WHERE al.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR al.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR al.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@executors::TEXT[]) = 0 OR al.executor::TEXT = ANY(@executors::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR al.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR al.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR al.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR al.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY al.id ASC
+LIMIT @row_limit;
+
+-- name: GetFlowAgentLogsDesc :many
+SELECT
+  al.*
+FROM agentlogs al
+INNER JOIN flows f ON al.flow_id = f.id
+WHERE al.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR al.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR al.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@executors::TEXT[]) = 0 OR al.executor::TEXT = ANY(@executors::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR al.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR al.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR al.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR al.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY al.id DESC
+LIMIT @row_limit;
+
 -- name: GetUserFlowAgentLogs :many
 SELECT
   al.*
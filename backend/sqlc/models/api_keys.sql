@@ -0,0 +1,34 @@
+-- name: GetApiKeys :many
+SELECT
+  k.*
+FROM api_keys k
+ORDER BY k.created_at DESC;
+
+-- name: GetUserApiKeys :many
+SELECT
+  k.*
+FROM api_keys k
+INNER JOIN users u ON k.user_id = u.id
+WHERE k.user_id = $1
+ORDER BY k.created_at DESC;
+
+-- name: GetApiKey :one
+SELECT
+  k.*
+FROM api_keys k
+WHERE k.id = $1;
+
+-- name: CreateApiKey :one
+INSERT INTO api_keys (
+  user_id, name, key_prefix, key_hash, privileges, expires_at
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING *;
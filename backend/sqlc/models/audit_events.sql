@@ -0,0 +1,8 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  user_id, user_mail, action, target_type, target_id, target_name, old_values, new_values, remote_addr, user_agent
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
@@ -0,0 +1,111 @@
+-- name: GetCampaigns :many
+SELECT
+  c.*
+FROM campaigns c
+WHERE c.deleted_at IS NULL
+ORDER BY c.created_at DESC;
+
+-- name: GetUserCampaigns :many
+SELECT
+  c.*
+FROM campaigns c
+INNER JOIN users u ON c.user_id = u.id
+WHERE c.user_id = $1 AND c.deleted_at IS NULL
+ORDER BY c.created_at DESC;
+
+-- name: GetCampaign :one
+SELECT
+  c.*
+FROM campaigns c
+WHERE c.id = $1 AND c.deleted_at IS NULL;
+
+-- name: GetRunningCampaigns :many
+SELECT
+  c.*
+FROM campaigns c
+WHERE c.status = 'running' AND c.deleted_at IS NULL
+ORDER BY c.created_at ASC;
+
+-- name: CreateCampaign :one
+INSERT INTO campaigns (
+  user_id, name, input, playbook_id, variables, scope,
+  model_provider_name, model_provider_type, plan_review, concurrency
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: UpdateCampaignStatus :one
+UPDATE campaigns
+SET status = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: DeleteCampaign :one
+UPDATE campaigns
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateCampaignTargets :exec
+INSERT INTO campaign_targets (
+  campaign_id, target
+)
+SELECT @campaign_id::BIGINT, UNNEST(@targets::TEXT[]);
+
+-- name: GetCampaignTargets :many
+SELECT
+  ct.*
+FROM campaign_targets ct
+WHERE ct.campaign_id = $1
+ORDER BY ct.id ASC;
+
+-- name: GetCampaignPendingTargets :many
+SELECT
+  ct.*
+FROM campaign_targets ct
+WHERE ct.campaign_id = $1 AND ct.status = 'pending'
+ORDER BY ct.id ASC
+LIMIT $2;
+
+-- name: UpdateCampaignTarget :one
+UPDATE campaign_targets
+SET status = $1, flow_id = $2, reason = $3
+WHERE id = $4
+RETURNING *;
+
+-- name: CancelCampaignPendingTargets :exec
+UPDATE campaign_targets
+SET status = 'cancelled'
+WHERE campaign_id = $1 AND status = 'pending';
+
+-- name: GetCampaignFlows :many
+SELECT
+  f.*
+FROM flows f
+INNER JOIN campaign_targets ct ON ct.flow_id = f.id
+WHERE ct.campaign_id = $1 AND f.deleted_at IS NULL
+ORDER BY f.id ASC;
+
+-- name: GetCampaignUsage :many
+SELECT
+  mc.model,
+  COALESCE(SUM(mc.usage_in), 0)::BIGINT AS usage_in,
+  COALESCE(SUM(mc.usage_out), 0)::BIGINT AS usage_out,
+  COALESCE(SUM(mc.usage_cache_read), 0)::BIGINT AS usage_cache_read,
+  COALESCE(SUM(mc.usage_cache_write), 0)::BIGINT AS usage_cache_write
+FROM msgchains mc
+INNER JOIN campaign_targets ct ON ct.flow_id = mc.flow_id
+WHERE ct.campaign_id = $1
+GROUP BY mc.model
+ORDER BY mc.model ASC;
+
+-- name: GetCampaignFindings :many
+SELECT
+  ct.target,
+  t.*
+FROM tasks t
+INNER JOIN campaign_targets ct ON ct.flow_id = t.flow_id
+WHERE ct.campaign_id = $1 AND t.status = 'finished'
+ORDER BY ct.id ASC, t.id ASC;
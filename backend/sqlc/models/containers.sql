@@ -12,7 +12,7 @@ SELECT
 FROM containers c
 INNER JOIN flows f ON c.flow_id = f.id
 INNER JOIN users u ON f.user_id = u.id
-WHERE f.user_id = $1 AND f.deleted_at IS NULL
+WHERE (f.user_id = $1 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND f.deleted_at IS NULL
 ORDER BY c.created_at DESC;
 
 -- name: GetRunningContainers :many
@@ -46,7 +46,7 @@ SELECT
 FROM containers c
 INNER JOIN flows f ON c.flow_id = f.id
 INNER JOIN users u ON f.user_id = u.id
-WHERE c.flow_id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL
+WHERE c.flow_id = $1 AND (f.user_id = $2 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2)) AND f.deleted_at IS NULL
 ORDER BY c.created_at DESC;
 
 -- name: CreateContainer :one
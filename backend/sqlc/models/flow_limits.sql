@@ -0,0 +1,26 @@
+-- name: GetFlowLimits :one
+SELECT
+  fl.*
+FROM flow_limits fl
+WHERE fl.flow_id = $1;
+
+-- name: UpsertFlowLimits :one
+INSERT INTO flow_limits (
+  flow_id,
+  flow_time_limit,
+  task_time_limit,
+  subtask_time_limit,
+  max_iterations,
+  max_tool_calls,
+  max_delegation_depth
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (flow_id) DO UPDATE SET
+  flow_time_limit = EXCLUDED.flow_time_limit,
+  task_time_limit = EXCLUDED.task_time_limit,
+  subtask_time_limit = EXCLUDED.subtask_time_limit,
+  max_iterations = EXCLUDED.max_iterations,
+  max_tool_calls = EXCLUDED.max_tool_calls,
+  max_delegation_depth = EXCLUDED.max_delegation_depth
+RETURNING *;
@@ -0,0 +1,48 @@
+-- name: CreateFlowQueueItem :one
+INSERT INTO flow_queue (
+  flow_id, priority, input, functions, playbook_id, playbook
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetFlowQueue :many
+SELECT
+  fq.*,
+  f.user_id,
+  f.model_provider_name,
+  f.model_provider_type
+FROM flow_queue fq
+INNER JOIN flows f ON fq.flow_id = f.id
+WHERE f.status = 'queued' AND f.deleted_at IS NULL
+ORDER BY fq.priority DESC, fq.created_at ASC, fq.flow_id ASC;
+
+-- name: DeleteFlowQueueItem :exec
+DELETE FROM flow_queue
+WHERE flow_id = $1;
+
+-- name: GetActiveFlows :many
+SELECT
+  f.*
+FROM flows f
+WHERE f.status IN ('created', 'running') AND f.deleted_at IS NULL
+ORDER BY f.id ASC;
+
+-- name: RequeueFlows :many
+UPDATE flows
+SET status = 'queued'
+WHERE status = 'created' AND deleted_at IS NULL AND id IN (SELECT flow_id FROM flow_queue)
+RETURNING *;
+
+-- name: AdmitQueuedFlow :one
+UPDATE flows
+SET status = 'created'
+WHERE id = $1 AND status = 'queued'
+RETURNING *;
+
+-- name: CancelQueuedFlow :one
+UPDATE flows
+SET status = 'finished'
+WHERE id = $1 AND status = 'queued'
+RETURNING *;
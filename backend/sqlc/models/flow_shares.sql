@@ -0,0 +1,49 @@
+-- name: GetFlowShares :many
+SELECT
+  s.*
+FROM flow_shares s
+WHERE s.flow_id = $1
+ORDER BY s.created_at DESC;
+
+-- name: GetFlowShare :one
+SELECT
+  s.*
+FROM flow_shares s
+WHERE s.id = $1;
+
+-- name: GetFlowShareByTokenHash :one
+SELECT
+  s.*
+FROM flow_shares s
+WHERE s.token_hash = $1;
+
+-- name: CreateFlowShare :one
+INSERT INTO flow_shares (
+  flow_id, user_id, token_prefix, token_hash, with_terminal_logs, expires_at
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: RevokeFlowShare :one
+UPDATE flow_shares
+SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE id = $1
+RETURNING *;
+
+-- name: GetFlowShareAccesses :many
+SELECT
+  a.*
+FROM flow_share_accesses a
+WHERE a.share_id = $1
+ORDER BY a.created_at DESC;
+
+-- name: CreateFlowShareAccess :one
+INSERT INTO flow_share_accesses (
+  share_id, resource, remote_addr, user_agent
+)
+VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
@@ -10,7 +10,7 @@ SELECT
   f.*
 FROM flows f
 INNER JOIN users u ON f.user_id = u.id
-WHERE f.user_id = $1 AND f.deleted_at IS NULL
+WHERE (f.user_id = $1 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND f.deleted_at IS NULL
 ORDER BY f.created_at DESC;
 
 -- name: GetFlow :one
@@ -24,14 +24,14 @@ SELECT
   f.*
 FROM flows f
 INNER JOIN users u ON f.user_id = u.id
-WHERE f.id = $1 AND f.user_id = $2 AND f.deleted_at IS NULL;
+WHERE f.id = $1 AND (f.user_id = $2 OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2)) AND f.deleted_at IS NULL;
 
 -- name: CreateFlow :one
 INSERT INTO flows (
-  title, status, model, model_provider_name, model_provider_type, language, functions, user_id
+  title, status, model, model_provider_name, model_provider_type, language, functions, user_id, plan_review
 )
 VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -64,3 +64,9 @@ UPDATE flows
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
+
+-- name: UpdateFlowTeam :one
+UPDATE flows
+SET team_id = $1
+WHERE id = $2
+RETURNING *;
@@ -0,0 +1,107 @@
+-- name: CopyTask :one
+INSERT INTO tasks (
+  status, title, input, result, flow_id, created_at
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: CopySubtask :one
+INSERT INTO subtasks (
+  status, title, description, result, context, depends_on, task_id, created_at
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: CopyFlowMsgChains :exec
+INSERT INTO msgchains (
+  type, model, model_provider, usage_in, usage_out, usage_cache_read, usage_cache_write,
+  chain, flow_id, task_id, subtask_id, created_at, updated_at
+)
+SELECT
+  m.type, m.model, m.model_provider, m.usage_in, m.usage_out, m.usage_cache_read, m.usage_cache_write,
+  m.chain, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, m.created_at, m.updated_at
+FROM msgchains m
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON m.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON m.subtask_id = sm.src_id
+WHERE m.flow_id = @src_flow_id::BIGINT AND m.type <> 'assistant' AND m.created_at <= @until::TIMESTAMPTZ AND
+  (m.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (m.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY m.id ASC;
+
+-- name: CopyFlowMsgLogs :exec
+INSERT INTO msglogs (
+  type, message, thinking, result, result_format, flow_id, task_id, subtask_id, created_at
+)
+SELECT
+  ml.type, ml.message, ml.thinking, ml.result, ml.result_format, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, ml.created_at
+FROM msglogs ml
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON ml.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON ml.subtask_id = sm.src_id
+WHERE ml.flow_id = @src_flow_id::BIGINT AND ml.created_at <= @until::TIMESTAMPTZ AND
+  (ml.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (ml.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY ml.id ASC;
+
+-- name: CopyFlowAgentLogs :exec
+INSERT INTO agentlogs (
+  initiator, executor, task, result, flow_id, task_id, subtask_id, created_at
+)
+SELECT
+  al.initiator, al.executor, al.task, al.result, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, al.created_at
+FROM agentlogs al
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON al.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON al.subtask_id = sm.src_id
+WHERE al.flow_id = @src_flow_id::BIGINT AND al.created_at <= @until::TIMESTAMPTZ AND
+  (al.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (al.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY al.id ASC;
+
+-- name: CopyFlowSearchLogs :exec
+INSERT INTO searchlogs (
+  initiator, executor, engine, query, result, flow_id, task_id, subtask_id, created_at
+)
+SELECT
+  sl.initiator, sl.executor, sl.engine, sl.query, sl.result, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, sl.created_at
+FROM searchlogs sl
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON sl.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON sl.subtask_id = sm.src_id
+WHERE sl.flow_id = @src_flow_id::BIGINT AND sl.created_at <= @until::TIMESTAMPTZ AND
+  (sl.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (sl.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY sl.id ASC;
+
+-- name: CopyFlowVectorStoreLogs :exec
+INSERT INTO vecstorelogs (
+  initiator, executor, filter, query, action, result, flow_id, task_id, subtask_id, created_at
+)
+SELECT
+  vl.initiator, vl.executor, vl.filter, vl.query, vl.action, vl.result, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, vl.created_at
+FROM vecstorelogs vl
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON vl.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON vl.subtask_id = sm.src_id
+WHERE vl.flow_id = @src_flow_id::BIGINT AND vl.created_at <= @until::TIMESTAMPTZ AND
+  (vl.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (vl.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY vl.id ASC;
+
+-- name: CopyFlowToolcalls :exec
+INSERT INTO toolcalls (
+  call_id, status, name, args, result, flow_id, task_id, subtask_id, created_at, updated_at
+)
+SELECT
+  tc.call_id, tc.status, tc.name, tc.args, tc.result, @dst_flow_id::BIGINT, tm.dst_id, sm.dst_id, tc.created_at, tc.updated_at
+FROM toolcalls tc
+LEFT JOIN UNNEST(@src_task_ids::BIGINT[], @dst_task_ids::BIGINT[]) AS tm(src_id, dst_id) ON tc.task_id = tm.src_id
+LEFT JOIN UNNEST(@src_subtask_ids::BIGINT[], @dst_subtask_ids::BIGINT[]) AS sm(src_id, dst_id) ON tc.subtask_id = sm.src_id
+WHERE tc.flow_id = @src_flow_id::BIGINT AND tc.created_at <= @until::TIMESTAMPTZ AND
+  (tc.task_id IS NULL OR tm.dst_id IS NOT NULL) AND (tc.subtask_id IS NULL OR sm.dst_id IS NOT NULL)
+ORDER BY tc.id ASC;
+
+-- name: CopyContainerTermLogs :exec
+INSERT INTO termlogs (
+  type, text, container_id, created_at
+)
+SELECT
+  tl.type, tl.text, @dst_container_id::BIGINT, tl.created_at
+FROM termlogs tl
+WHERE tl.container_id = @src_container_id::BIGINT AND tl.created_at <= @until::TIMESTAMPTZ
+ORDER BY tl.id ASC;
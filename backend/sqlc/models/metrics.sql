@@ -0,0 +1,26 @@
+-- name: GetFlowsCountByStatus :many
+SELECT
+  f.status,
+  COUNT(*)::BIGINT AS count
+FROM flows f
+WHERE f.deleted_at IS NULL
+GROUP BY f.status
+ORDER BY f.status;
+
+-- name: GetContainersCountByStatus :many
+SELECT
+  c.status,
+  COUNT(*)::BIGINT AS count
+FROM containers c
+GROUP BY c.status
+ORDER BY c.status;
+
+-- name: GetQueueDepths :one
+SELECT
+  (
+    SELECT COUNT(*) FROM flow_queue fq
+    INNER JOIN flows f ON fq.flow_id = f.id
+    WHERE f.status = 'queued' AND f.deleted_at IS NULL
+  )::BIGINT AS flows,
+  (SELECT COUNT(*) FROM webhook_deliveries wd WHERE wd.status = 'pending')::BIGINT AS webhook_deliveries,
+  (SELECT COUNT(*) FROM campaign_targets ct WHERE ct.status = 'pending')::BIGINT AS campaign_targets;
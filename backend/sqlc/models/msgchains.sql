@@ -90,12 +90,14 @@ INSERT INTO msgchains (
   model_provider,
   usage_in,
   usage_out,
+  usage_cache_read,
+  usage_cache_write,
   chain,
   flow_id,
   task_id,
   subtask_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -107,6 +109,10 @@ RETURNING *;
 
 -- name: UpdateMsgChainUsage :one
 UPDATE msgchains
-SET usage_in = usage_in + $1, usage_out = usage_out + $2
-WHERE id = $3
+SET
+  usage_in = usage_in + $1,
+  usage_out = usage_out + $2,
+  usage_cache_read = usage_cache_read + $3,
+  usage_cache_write = usage_cache_write + $4
+WHERE id = $5
 RETURNING *;
@@ -6,6 +6,38 @@ INNER JOIN flows f ON ml.flow_id = f.id
 WHERE ml.flow_id = $1 AND f.deleted_at IS NULL
 ORDER BY ml.created_at ASC;
 
+-- name: GetFlowMsgLogsAsc :many
+SELECT
+  ml.*
+FROM msglogs ml
+INNER JOIN flows f ON ml.flow_id = f.id
+WHERE ml.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR ml.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR ml.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@types::TEXT[]) = 0 OR ml.type::TEXT = ANY(@types::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR ml.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR ml.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR ml.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR ml.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY ml.id ASC
+LIMIT @row_limit;
+
+-- name: GetFlowMsgLogsDesc :many
+SELECT
+  ml.*
+FROM msglogs ml
+INNER JOIN flows f ON ml.flow_id = f.id
+WHERE ml.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR ml.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR ml.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@types::TEXT[]) = 0 OR ml.type::TEXT = ANY(@types::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR ml.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR ml.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR ml.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR ml.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY ml.id DESC
+LIMIT @row_limit;
+
 -- name: GetUserFlowMsgLogs :many
 SELECT
   ml.*
@@ -0,0 +1,65 @@
+-- name: GetUserPlaybooks :many
+SELECT
+  p.*
+FROM playbooks p
+INNER JOIN users u ON p.user_id = u.id
+WHERE (p.user_id = $1 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND p.deleted_at IS NULL
+ORDER BY p.created_at ASC;
+
+-- name: GetUserPlaybook :one
+SELECT
+  p.*
+FROM playbooks p
+INNER JOIN users u ON p.user_id = u.id
+WHERE p.id = $1 AND (p.user_id = $2 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2)) AND p.deleted_at IS NULL;
+
+-- name: CreateUserPlaybook :one
+INSERT INTO playbooks (
+  user_id,
+  name,
+  description,
+  content
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UpdateUserPlaybook :one
+UPDATE playbooks
+SET name = $3, description = $4, content = $5
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserPlaybook :one
+UPDATE playbooks
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateFlowPlaybook :one
+INSERT INTO flow_playbooks (
+  flow_id,
+  playbook_id,
+  plan
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetFlowPlaybook :one
+SELECT
+  fp.*
+FROM flow_playbooks fp
+WHERE fp.flow_id = $1;
+
+-- name: UpdateFlowPlaybookNextTask :one
+UPDATE flow_playbooks
+SET next_task = $1
+WHERE flow_id = $2
+RETURNING *;
+
+-- name: UpdatePlaybookTeam :one
+UPDATE playbooks
+SET team_id = $1
+WHERE id = $2
+RETURNING *;
@@ -9,8 +9,8 @@ SELECT
   p.*
 FROM prompts p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.user_id = $1
-ORDER BY p.type ASC;
+WHERE (p.user_id = $1 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1))
+ORDER BY p.user_id <> $1, p.type ASC;
 
 -- name: GetUserPrompt :one
 SELECT
@@ -62,3 +62,9 @@ WHERE id = $1;
 -- name: DeleteUserPrompt :exec
 DELETE FROM prompts
 WHERE id = $1 AND user_id = $2;
+
+-- name: UpdatePromptTeam :one
+UPDATE prompts
+SET team_id = $1
+WHERE id = $2
+RETURNING *;
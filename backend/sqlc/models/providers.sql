@@ -30,8 +30,8 @@ SELECT
   p.*
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.user_id = $1 AND p.deleted_at IS NULL
-ORDER BY p.created_at ASC;
+WHERE (p.user_id = $1 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $1)) AND p.deleted_at IS NULL
+ORDER BY p.user_id <> $1, p.created_at ASC;
 
 -- name: GetUserProvidersByType :many
 SELECT
@@ -46,7 +46,9 @@ SELECT
   p.*
 FROM providers p
 INNER JOIN users u ON p.user_id = u.id
-WHERE p.name = $1 AND p.user_id = $2 AND p.deleted_at IS NULL;
+WHERE p.name = $1 AND (p.user_id = $2 OR p.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = $2 AND tm.role IN ('operator', 'admin'))) AND p.deleted_at IS NULL
+ORDER BY p.user_id <> $2, p.created_at ASC
+LIMIT 1;
 
 -- name: CreateProvider :one
 INSERT INTO providers (
@@ -82,3 +84,9 @@ UPDATE providers
 SET deleted_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND user_id = $2
 RETURNING *;
+
+-- name: UpdateProviderTeam :one
+UPDATE providers
+SET team_id = $1
+WHERE id = $2
+RETURNING *;
@@ -33,3 +33,26 @@ SELECT
   ) AS privileges
 FROM roles r
 WHERE r.name = $1;
+
+-- name: CreateRole :one
+INSERT INTO roles (
+  name
+) VALUES (
+  $1
+)
+RETURNING *;
+
+-- name: UpdateRole :one
+UPDATE roles
+SET name = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: SetRolePrivileges :exec
+WITH deleted AS (
+  DELETE FROM privileges
+  WHERE role_id = @role_id AND name <> ALL(@privileges::TEXT[])
+)
+INSERT INTO privileges (role_id, name)
+SELECT @role_id, UNNEST(@privileges::TEXT[])
+ON CONFLICT DO NOTHING;
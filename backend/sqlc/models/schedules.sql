@@ -0,0 +1,85 @@
+-- name: GetSchedules :many
+SELECT
+  s.*
+FROM schedules s
+WHERE s.deleted_at IS NULL
+ORDER BY s.created_at ASC;
+
+-- name: GetUserSchedules :many
+SELECT
+  s.*
+FROM schedules s
+INNER JOIN users u ON s.user_id = u.id
+WHERE s.user_id = $1 AND s.deleted_at IS NULL
+ORDER BY s.created_at ASC;
+
+-- name: GetSchedule :one
+SELECT
+  s.*
+FROM schedules s
+WHERE s.id = $1 AND s.deleted_at IS NULL;
+
+-- name: GetDueSchedules :many
+SELECT
+  s.*
+FROM schedules s
+WHERE s.enabled AND s.deleted_at IS NULL AND s.next_run_at <= $1
+ORDER BY s.next_run_at ASC;
+
+-- name: CreateSchedule :one
+INSERT INTO schedules (
+  user_id, name, cron, timezone, input, playbook_id, variables, scope,
+  model_provider_name, model_provider_type, plan_review, missed_policy, enabled, next_run_at
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING *;
+
+-- name: UpdateSchedule :one
+UPDATE schedules
+SET name = $2, cron = $3, timezone = $4, input = $5, playbook_id = $6, variables = $7, scope = $8,
+  model_provider_name = $9, model_provider_type = $10, plan_review = $11, missed_policy = $12, next_run_at = $13
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateScheduleEnabled :one
+UPDATE schedules
+SET enabled = $2, next_run_at = $3
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateScheduleNextRun :one
+UPDATE schedules
+SET next_run_at = $2, last_run_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSchedule :one
+UPDATE schedules
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetScheduleRuns :many
+SELECT
+  sr.*
+FROM schedule_runs sr
+WHERE sr.schedule_id = $1
+ORDER BY sr.scheduled_at DESC;
+
+-- name: CreateScheduleRun :one
+INSERT INTO schedule_runs (
+  schedule_id, flow_id, status, reason, scheduled_at
+)
+VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetScheduleActiveFlows :many
+SELECT
+  f.*
+FROM flows f
+INNER JOIN schedule_runs sr ON sr.flow_id = f.id
+WHERE sr.schedule_id = $1 AND f.status IN ('queued', 'created', 'running') AND f.deleted_at IS NULL;
@@ -0,0 +1,112 @@
+-- name: SearchFlows :many
+SELECT
+  f.id,
+  f.title,
+  search_headline(f.title, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(f.title), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  f.created_at
+FROM flows f
+WHERE search_document(f.title) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR f.id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR f.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR f.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, f.id DESC
+LIMIT @row_limit;
+
+-- name: SearchTasks :many
+SELECT
+  t.id,
+  t.flow_id,
+  f.title AS flow_title,
+  search_headline(t.title || E'\n' || t.input || E'\n' || t.result, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(t.title || E'\n' || t.input || E'\n' || t.result), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  t.created_at
+FROM tasks t
+INNER JOIN flows f ON t.flow_id = f.id
+WHERE search_document(t.title || E'\n' || t.input || E'\n' || t.result) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR t.flow_id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR t.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR t.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, t.id DESC
+LIMIT @row_limit;
+
+-- name: SearchSubtasks :many
+SELECT
+  s.id,
+  s.task_id,
+  t.flow_id,
+  f.title AS flow_title,
+  search_headline(s.title || E'\n' || s.description || E'\n' || s.result, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(s.title || E'\n' || s.description || E'\n' || s.result), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  s.created_at
+FROM subtasks s
+INNER JOIN tasks t ON s.task_id = t.id
+INNER JOIN flows f ON t.flow_id = f.id
+WHERE search_document(s.title || E'\n' || s.description || E'\n' || s.result) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR t.flow_id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR s.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR s.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, s.id DESC
+LIMIT @row_limit;
+
+-- name: SearchMsgLogs :many
+SELECT
+  ml.id,
+  ml.flow_id,
+  ml.task_id,
+  ml.subtask_id,
+  f.title AS flow_title,
+  search_headline(ml.message || E'\n' || ml.result, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(ml.message || E'\n' || ml.result), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  ml.created_at
+FROM msglogs ml
+INNER JOIN flows f ON ml.flow_id = f.id
+WHERE search_document(ml.message || E'\n' || ml.result) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR ml.flow_id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR ml.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR ml.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, ml.id DESC
+LIMIT @row_limit;
+
+-- name: SearchTermLogs :many
+SELECT
+  tl.id,
+  c.flow_id,
+  f.title AS flow_title,
+  search_headline(tl.text, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(tl.text), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  tl.created_at
+FROM termlogs tl
+INNER JOIN containers c ON tl.container_id = c.id
+INNER JOIN flows f ON c.flow_id = f.id
+WHERE search_document(tl.text) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR c.flow_id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR tl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR tl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, tl.id DESC
+LIMIT @row_limit;
+
+-- name: SearchSearchLogs :many
+SELECT
+  sl.id,
+  sl.flow_id,
+  sl.task_id,
+  sl.subtask_id,
+  f.title AS flow_title,
+  search_headline(sl.query || E'\n' || sl.result, websearch_to_tsquery('simple', @query::TEXT), @headline_options::TEXT)::TEXT AS snippet,
+  ts_rank(search_document(sl.query || E'\n' || sl.result), websearch_to_tsquery('simple', @query::TEXT))::REAL AS rank,
+  sl.created_at
+FROM searchlogs sl
+INNER JOIN flows f ON sl.flow_id = f.id
+WHERE search_document(sl.query || E'\n' || sl.result) @@ websearch_to_tsquery('simple', @query::TEXT) AND f.deleted_at IS NULL
+  AND (@admin::BOOLEAN OR f.user_id = @user_id::BIGINT OR f.team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @user_id::BIGINT))
+  AND (sqlc.narg(flow_id)::BIGINT IS NULL OR sl.flow_id = sqlc.narg(flow_id)::BIGINT)
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR sl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR sl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+ORDER BY rank DESC, sl.id DESC
+LIMIT @row_limit;
@@ -6,6 +6,38 @@ INNER JOIN flows f ON sl.flow_id = f.id
 WHERE sl.flow_id = $1 AND f.deleted_at IS NULL
 ORDER BY sl.created_at ASC;
 
+-- name: GetFlowSearchLogsAsc :many
+SELECT
+  sl.*
+FROM searchlogs sl
+INNER JOIN flows f ON sl.flow_id = f.id
+WHERE sl.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR sl.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR sl.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@engines::TEXT[]) = 0 OR sl.engine::TEXT = ANY(@engines::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR sl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR sl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR sl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR sl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY sl.id ASC
+LIMIT @row_limit;
+
+-- name: GetFlowSearchLogsDesc :many
+SELECT
+  sl.*
+FROM searchlogs sl
+INNER JOIN flows f ON sl.flow_id = f.id
+WHERE sl.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR sl.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR sl.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@engines::TEXT[]) = 0 OR sl.engine::TEXT = ANY(@engines::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR sl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR sl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR sl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR sl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY sl.id DESC
+LIMIT @row_limit;
+
 -- name: GetUserFlowSearchLogs :many
 SELECT
   sl.*
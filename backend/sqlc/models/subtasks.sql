@@ -82,9 +82,10 @@ INSERT INTO subtasks (
   status,
   title,
   description,
-  task_id
+  task_id,
+  depends_on
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -0,0 +1,83 @@
+-- name: GetTeams :many
+SELECT
+  t.*
+FROM teams t
+ORDER BY t.name ASC;
+
+-- name: GetUserTeams :many
+SELECT
+  t.*
+FROM teams t
+INNER JOIN team_members tm ON tm.team_id = t.id
+WHERE tm.user_id = $1
+ORDER BY t.name ASC;
+
+-- name: GetTeam :one
+SELECT
+  t.*
+FROM teams t
+WHERE t.id = $1;
+
+-- name: GetTeamByName :one
+SELECT
+  t.*
+FROM teams t
+WHERE LOWER(t.name) = LOWER($1);
+
+-- name: CreateTeam :one
+INSERT INTO teams (
+  name,
+  description
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UpdateTeam :one
+UPDATE teams
+SET name = $1, description = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: DeleteTeam :exec
+DELETE FROM teams
+WHERE id = $1;
+
+-- name: GetTeamMembers :many
+SELECT
+  tm.*,
+  u.name AS user_name,
+  u.mail AS user_mail
+FROM team_members tm
+INNER JOIN users u ON tm.user_id = u.id
+WHERE tm.team_id = $1
+ORDER BY tm.created_at ASC;
+
+-- name: GetTeamMember :one
+SELECT
+  tm.*
+FROM team_members tm
+WHERE tm.team_id = $1 AND tm.user_id = $2;
+
+-- name: GetTeamUserIDs :many
+SELECT
+  tm.user_id
+FROM team_members tm
+WHERE tm.team_id = $1
+ORDER BY tm.user_id ASC;
+
+-- name: SetTeamMember :one
+INSERT INTO team_members (
+  team_id,
+  user_id,
+  role
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (team_id, user_id) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: DeleteTeamMember :exec
+DELETE FROM team_members
+WHERE team_id = $1 AND user_id = $2;
@@ -7,6 +7,38 @@ INNER JOIN flows f ON c.flow_id = f.id
 WHERE c.flow_id = $1
 ORDER BY tl.created_at ASC;
 
+-- name: GetFlowTermLogsAsc :many
+SELECT
+  tl.*
+FROM termlogs tl
+INNER JOIN containers c ON tl.container_id = c.id
+INNER JOIN flows f ON c.flow_id = f.id
+WHERE c.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(container_id)::BIGINT IS NULL OR tl.container_id = sqlc.narg(container_id)::BIGINT)
+  AND (cardinality(@types::TEXT[]) = 0 OR tl.type::TEXT = ANY(@types::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR tl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR tl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR tl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR tl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY tl.id ASC
+LIMIT @row_limit;
+
+-- name: GetFlowTermLogsDesc :many
+SELECT
+  tl.*
+FROM termlogs tl
+INNER JOIN containers c ON tl.container_id = c.id
+INNER JOIN flows f ON c.flow_id = f.id
+WHERE c.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(container_id)::BIGINT IS NULL OR tl.container_id = sqlc.narg(container_id)::BIGINT)
+  AND (cardinality(@types::TEXT[]) = 0 OR tl.type::TEXT = ANY(@types::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR tl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR tl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR tl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR tl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY tl.id DESC
+LIMIT @row_limit;
+
 -- name: GetUserFlowTermLogs :many
 SELECT
   tl.*
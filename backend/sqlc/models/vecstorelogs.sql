@@ -6,6 +6,38 @@ INNER JOIN flows f ON vl.flow_id = f.id
 WHERE vl.flow_id = $1 AND f.deleted_at IS NULL
 ORDER BY vl.created_at ASC;
 
+-- name: GetFlowVectorStoreLogsAsc :many
+SELECT
+  vl.*
+FROM vecstorelogs vl
+INNER JOIN flows f ON vl.flow_id = f.id
+WHERE vl.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR vl.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR vl.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@actions::TEXT[]) = 0 OR vl.action::TEXT = ANY(@actions::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR vl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR vl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR vl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR vl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY vl.id ASC
+LIMIT @row_limit;
+
+-- name: GetFlowVectorStoreLogsDesc :many
+SELECT
+  vl.*
+FROM vecstorelogs vl
+INNER JOIN flows f ON vl.flow_id = f.id
+WHERE vl.flow_id = @flow_id AND f.deleted_at IS NULL
+  AND (sqlc.narg(task_id)::BIGINT IS NULL OR vl.task_id = sqlc.narg(task_id)::BIGINT)
+  AND (sqlc.narg(subtask_id)::BIGINT IS NULL OR vl.subtask_id = sqlc.narg(subtask_id)::BIGINT)
+  AND (cardinality(@actions::TEXT[]) = 0 OR vl.action::TEXT = ANY(@actions::TEXT[]))
+  AND (sqlc.narg(created_after)::TIMESTAMPTZ IS NULL OR vl.created_at >= sqlc.narg(created_after)::TIMESTAMPTZ)
+  AND (sqlc.narg(created_before)::TIMESTAMPTZ IS NULL OR vl.created_at < sqlc.narg(created_before)::TIMESTAMPTZ)
+  AND (sqlc.narg(after_id)::BIGINT IS NULL OR vl.id > sqlc.narg(after_id)::BIGINT)
+  AND (sqlc.narg(before_id)::BIGINT IS NULL OR vl.id < sqlc.narg(before_id)::BIGINT)
+ORDER BY vl.id DESC
+LIMIT @row_limit;
+
 -- name: GetUserFlowVectorStoreLogs :many
 SELECT
   vl.*
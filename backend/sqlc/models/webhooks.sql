@@ -0,0 +1,77 @@
+-- name: GetWebhooks :many
+SELECT
+  w.*
+FROM webhooks w
+ORDER BY w.created_at ASC;
+
+-- name: GetUserWebhooks :many
+SELECT
+  w.*
+FROM webhooks w
+INNER JOIN users u ON w.user_id = u.id
+WHERE w.user_id = $1
+ORDER BY w.created_at ASC;
+
+-- name: GetWebhook :one
+SELECT
+  w.*
+FROM webhooks w
+WHERE w.id = $1;
+
+-- name: GetEventWebhooks :many
+SELECT
+  w.*
+FROM webhooks w
+WHERE w.enabled AND (w.user_id = @user_id OR w.user_id IS NULL) AND (cardinality(w.events) = 0 OR @event::TEXT = ANY(w.events))
+ORDER BY w.id ASC;
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  user_id, name, url, secret, events, enabled
+)
+VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET name = $2, url = $3, secret = $4, events = $5, enabled = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1
+RETURNING *;
+
+-- name: GetWebhookDeliveries :many
+SELECT
+  d.*
+FROM webhook_deliveries d
+WHERE d.webhook_id = $1
+ORDER BY d.id DESC
+LIMIT $2;
+
+-- name: GetDueWebhookDeliveries :many
+SELECT
+  d.*
+FROM webhook_deliveries d
+WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+ORDER BY d.next_attempt_at ASC
+LIMIT $2;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  webhook_id, flow_id, event, payload, next_attempt_at
+)
+VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = $2, attempts = $3, response_code = $4, error = $5, next_attempt_at = $6
+WHERE id = $1
+RETURNING *;
@@ -163,7 +163,7 @@ const MainSidebar = () => {
                                             <span className="truncate font-semibold">{user?.name}</span>
                                             <span className="truncate text-xs">{user?.mail}</span>
                                             <span className="text-muted-foreground truncate text-xs">
-                                                {user?.type === 'local' ? 'local' : 'oauth'}
+                                                {user?.type}
                                             </span>
                                         </div>
                                     </div>
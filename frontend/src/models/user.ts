@@ -8,5 +8,5 @@ export interface User {
     provide: string;
     role_id: number;
     status: 'active' | 'blocked' | 'created';
-    type: 'local' | 'oauth';
+    type: 'ldap' | 'local' | 'oauth';
 }